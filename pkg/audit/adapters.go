@@ -0,0 +1,321 @@
+package audit
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/niiniyare/ruun/schema"
+	"github.com/niiniyare/ruun/theme"
+)
+
+// Resource types recorded by the adapters
+const (
+	ResourceSchema          = "schema"
+	ResourceTheme           = "theme"
+	ResourceThemePreference = "theme_preference"
+	ResourceWorkflow        = "workflow"
+	ResourceForm            = "form"
+)
+
+// Sources are the components whose changes Capture records; nil sources
+// are skipped
+type Sources struct {
+	// Registry must have events enabled, which is the default
+	Registry *schema.Registry
+	Themes   *theme.ObservableManager
+	Switcher *theme.ThemeSwitcher
+}
+
+// Capture records the changes of every source into log, sending recording
+// failures to onError, or logging them when it is nil. It returns the
+// observer that records form submissions and workflow transitions; pass it
+// to schema.RuntimeBuilder.WithObserver for every runtime.
+//
+// On a multi-node deployment call Capture on every node with Logs sharing
+// one store, such as a RedisStore.
+func Capture(log *Log, sources Sources, onError func(error)) *RuntimeRecorder {
+	if sources.Registry != nil {
+		recorder := NewRegistryRecorder(log)
+		recorder.ErrorHandler = onError
+		sources.Registry.Subscribe(recorder.Handle)
+	}
+	if sources.Themes != nil || sources.Switcher != nil {
+		recorder := NewThemeRecorder(log)
+		recorder.ErrorHandler = onError
+		if sources.Themes != nil {
+			sources.Themes.AddObserver(recorder)
+		}
+		if sources.Switcher != nil {
+			sources.Switcher.AddObserver(recorder)
+		}
+	}
+	recorder := NewRuntimeRecorder(log)
+	recorder.ErrorHandler = onError
+	return recorder
+}
+
+// RegistryRecorder records schema registry changes. Subscribe Handle to
+// the registry's event bus on every node, with the nodes' Logs sharing a
+// store: events received from other nodes are skipped, since their origin
+// records them, and reads are not audited.
+type RegistryRecorder struct {
+	log *Log
+	// ErrorHandler receives recording failures; they are logged when nil.
+	ErrorHandler func(error)
+}
+
+func NewRegistryRecorder(log *Log) *RegistryRecorder {
+	return &RegistryRecorder{log: log}
+}
+
+// Handle records a registry event, attributed to the user the registry
+// found in the changing call's context
+func (r *RegistryRecorder) Handle(event *schema.RegistryEvent) {
+	if event.Remote || event.Type == schema.EventSchemaAccessed {
+		return
+	}
+
+	data := maps.Clone(event.Data)
+	if data == nil {
+		data = make(map[string]any, 1)
+	}
+	data["origin"] = event.Origin
+	r.record(context.Background(), Event{
+		ID:           event.ID,
+		Timestamp:    event.Timestamp,
+		Action:       string(event.Type),
+		ResourceType: ResourceSchema,
+		ResourceID:   event.SchemaID,
+		TenantID:     event.TenantID,
+		ActorID:      event.ActorID,
+		Data:         data,
+	})
+}
+
+func (r *RegistryRecorder) record(ctx context.Context, event Event) {
+	if _, err := r.log.Record(ctx, event); err != nil {
+		reportError(r.ErrorHandler, event, err)
+	}
+}
+
+// ThemeRecorder records theme changes. It observes an ObservableManager for
+// theme and tenant configuration changes and a ThemeSwitcher for users'
+// theme and dark-mode choices; compilation, validation and cache events are
+// ignored. Theme and tenant changes are attributed to the user attached to
+// the changing call's context with schema.ContextWithUser.
+type ThemeRecorder struct {
+	log *Log
+	// ErrorHandler receives recording failures; they are logged when nil.
+	ErrorHandler func(error)
+}
+
+var (
+	_ theme.Observer            = (*ThemeRecorder)(nil)
+	_ theme.ThemeSwitchObserver = (*ThemeRecorder)(nil)
+)
+
+func NewThemeRecorder(log *Log) *ThemeRecorder {
+	return &ThemeRecorder{log: log}
+}
+
+func (r *ThemeRecorder) OnThemeRegistered(ctx context.Context, event *theme.ThemeEvent) {
+	r.recordTheme(ctx, event)
+}
+
+func (r *ThemeRecorder) OnThemeUpdated(ctx context.Context, event *theme.ThemeEvent) {
+	r.recordTheme(ctx, event)
+}
+
+func (r *ThemeRecorder) OnThemeDeleted(ctx context.Context, event *theme.ThemeEvent) {
+	r.recordTheme(ctx, event)
+}
+
+func (r *ThemeRecorder) OnTenantConfigured(ctx context.Context, event *theme.TenantEvent) {
+	data := maps.Clone(event.Metadata)
+	if data == nil {
+		data = make(map[string]any, 1)
+	}
+	data["themeId"] = event.ThemeID
+	actorID, _ := actorFromContext(ctx)
+	r.record(ctx, Event{
+		Timestamp:    event.Timestamp,
+		TenantID:     event.TenantID,
+		ActorID:      actorID,
+		Action:       "theme.tenant_" + event.Action,
+		ResourceType: ResourceTheme,
+		ResourceID:   event.ThemeID,
+		Data:         data,
+	})
+}
+
+func (r *ThemeRecorder) OnThemeSwitched(ctx context.Context, event *theme.ThemeSwitchEvent) {
+	r.record(ctx, Event{
+		Timestamp:    event.Timestamp,
+		TenantID:     event.TenantID,
+		ActorID:      event.UserID,
+		Action:       "theme.switched",
+		ResourceType: ResourceThemePreference,
+		ResourceID:   event.UserID,
+		Data: map[string]any{
+			"previousTheme": event.PreviousTheme,
+			"newTheme":      event.NewTheme,
+			"deviceId":      event.DeviceID,
+		},
+	})
+}
+
+func (r *ThemeRecorder) OnDarkModeToggled(ctx context.Context, event *theme.DarkModeEvent) {
+	r.record(ctx, Event{
+		Timestamp:    event.Timestamp,
+		TenantID:     event.TenantID,
+		ActorID:      event.UserID,
+		Action:       "theme.dark_mode_toggled",
+		ResourceType: ResourceThemePreference,
+		ResourceID:   event.UserID,
+		Data: map[string]any{
+			"darkMode": event.DarkMode,
+			"deviceId": event.DeviceID,
+		},
+	})
+}
+
+func (r *ThemeRecorder) OnThemeCompiled(ctx context.Context, event *theme.CompilationEvent)   {}
+func (r *ThemeRecorder) OnValidationFailed(ctx context.Context, event *theme.ValidationEvent) {}
+func (r *ThemeRecorder) OnCacheHit(ctx context.Context, event *theme.CacheEvent)              {}
+func (r *ThemeRecorder) OnCacheMiss(ctx context.Context, event *theme.CacheEvent)             {}
+func (r *ThemeRecorder) OnShutdown(ctx context.Context) error                                 { return nil }
+
+func (r *ThemeRecorder) recordTheme(ctx context.Context, event *theme.ThemeEvent) {
+	data := maps.Clone(event.Metadata)
+	if data == nil {
+		data = make(map[string]any, 1)
+	}
+	data["name"] = event.ThemeName
+	actorID, tenantID := actorFromContext(ctx)
+	r.record(ctx, Event{
+		Timestamp:    event.Timestamp,
+		TenantID:     tenantID,
+		ActorID:      actorID,
+		Action:       "theme." + event.Action,
+		ResourceType: ResourceTheme,
+		ResourceID:   event.ThemeID,
+		Data:         data,
+	})
+}
+
+func (r *ThemeRecorder) record(ctx context.Context, event Event) {
+	if _, err := r.log.Record(context.WithoutCancel(ctx), event); err != nil {
+		reportError(r.ErrorHandler, event, err)
+	}
+}
+
+// RuntimeRecorder records the form submissions and workflow transitions of
+// schema runtimes it observes
+type RuntimeRecorder struct {
+	log *Log
+	// ErrorHandler receives recording failures; they are logged when nil.
+	ErrorHandler func(error)
+}
+
+var _ schema.RuntimeObserver = (*RuntimeRecorder)(nil)
+
+func NewRuntimeRecorder(log *Log) *RuntimeRecorder {
+	return &RuntimeRecorder{log: log}
+}
+
+func (r *RuntimeRecorder) OnSubmitted(ctx context.Context, s *schema.Schema, data map[string]any) {
+	r.record(ctx, FormSubmissionEvent(ctx, s, data))
+}
+
+func (r *RuntimeRecorder) OnWorkflowTransition(ctx context.Context, s *schema.Schema, entry schema.WorkflowHistoryEntry) {
+	_, tenantID := actorFromContext(ctx)
+	r.record(ctx, WorkflowTransitionEvent(s.ID, tenantID, entry))
+}
+
+func (r *RuntimeRecorder) record(ctx context.Context, event Event) {
+	if _, err := r.log.Record(context.WithoutCancel(ctx), event); err != nil {
+		reportError(r.ErrorHandler, event, err)
+	}
+}
+
+// actorFromContext returns the ID and tenant of the user attached to ctx
+func actorFromContext(ctx context.Context) (actorID, tenantID string) {
+	if user, ok := schema.UserFromContext(ctx); ok {
+		return user.GetID(), user.GetTenantID()
+	}
+	return "", ""
+}
+
+// reportError hands a recording failure to handler. Without one it is
+// logged, so a change that went unaudited never goes unnoticed.
+func reportError(handler func(error), event Event, err error) {
+	if handler != nil {
+		handler(err)
+		return
+	}
+	slog.Error("audit: event not recorded",
+		"action", event.Action,
+		"resourceType", event.ResourceType,
+		"resourceId", event.ResourceID,
+		"error", err)
+}
+
+// WorkflowTransitionEvent builds the audit event for a workflow history
+// entry of a schema's workflow
+func WorkflowTransitionEvent(schemaID, tenantID string, entry schema.WorkflowHistoryEntry) Event {
+	data := maps.Clone(entry.Metadata)
+	if data == nil {
+		data = make(map[string]any, 4)
+	}
+	data["action"] = entry.Action
+	data["fromStage"] = entry.FromStage
+	data["toStage"] = entry.ToStage
+	if entry.Note != "" {
+		data["note"] = entry.Note
+	}
+	return Event{
+		ID:           entry.ID,
+		Timestamp:    entry.Timestamp,
+		TenantID:     tenantID,
+		ActorID:      entry.ActorID,
+		ActorName:    entry.ActorName,
+		Action:       "workflow.transition",
+		ResourceType: ResourceWorkflow,
+		ResourceID:   schemaID,
+		Data:         data,
+	}
+}
+
+// FormSubmissionEvent builds the audit event for a form submission by the
+// user attached with schema.ContextWithUser. Only the submitted field names
+// are recorded, never their values, and fields the schema encrypts are
+// listed separately.
+func FormSubmissionEvent(ctx context.Context, s *schema.Schema, data map[string]any) Event {
+	var fields, encrypted []string
+	for name := range data {
+		if strings.HasSuffix(name, schema.BlindIndexSuffix) {
+			continue
+		}
+		fields = append(fields, name)
+		if s.Security != nil && s.Security.ShouldEncryptField(name) {
+			encrypted = append(encrypted, name)
+		}
+	}
+	slices.Sort(fields)
+	slices.Sort(encrypted)
+
+	event := Event{
+		Action:       "form.submitted",
+		ResourceType: ResourceForm,
+		ResourceID:   s.ID,
+		Data:         map[string]any{"fields": fields},
+	}
+	if len(encrypted) > 0 {
+		event.Data["encryptedFields"] = encrypted
+	}
+	event.ActorID, event.TenantID = actorFromContext(ctx)
+	return event
+}
@@ -0,0 +1,392 @@
+// Package audit records who changed what in an append-only, tamper-evident
+// log. Every event carries the hash of its predecessor, so editing, removing
+// or reordering a stored event breaks the chain, and signed checkpoints pin
+// the head of the chain so truncation and wholesale rewrites are detected
+// too.
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrSequenceConflict is returned by Store.Append when the event does
+	// not extend the stored chain
+	ErrSequenceConflict = errors.New("audit: event does not extend the chain")
+	// ErrChainBroken reports an event whose hash or link does not verify
+	ErrChainBroken = errors.New("audit: hash chain broken")
+	// ErrInvalidCheckpoint reports a checkpoint that does not verify
+	ErrInvalidCheckpoint = errors.New("audit: invalid checkpoint")
+)
+
+// Event is one audited change
+type Event struct {
+	ID           string         `json:"id"`
+	Sequence     uint64         `json:"sequence"`
+	Timestamp    time.Time      `json:"timestamp"`
+	TenantID     string         `json:"tenantId,omitempty"`
+	ActorID      string         `json:"actorId,omitempty"`
+	ActorName    string         `json:"actorName,omitempty"`
+	Action       string         `json:"action"`
+	ResourceType string         `json:"resourceType"`
+	ResourceID   string         `json:"resourceId,omitempty"`
+	Data         map[string]any `json:"data,omitempty"`
+	PrevHash     string         `json:"prevHash"`
+	Hash         string         `json:"hash"`
+}
+
+// ComputeHash returns the SHA-256 of the event's JSON encoding with Hash
+// cleared. PrevHash is included, which links the event to its predecessor.
+func (e *Event) ComputeHash() (string, error) {
+	unhashed := *e
+	unhashed.Hash = ""
+	data, err := json.Marshal(&unhashed)
+	if err != nil {
+		return "", fmt.Errorf("audit: encode event %d: %w", e.Sequence, err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Query selects events. Empty fields match everything; results are ordered
+// by sequence and paged with AfterSequence.
+type Query struct {
+	TenantID     string
+	ActorID      string
+	ResourceType string
+	ResourceID   string
+	Action       string
+	Since        time.Time // inclusive
+	Until        time.Time // exclusive
+	// AfterSequence returns only events after this sequence, for paging
+	AfterSequence uint64
+	// Limit caps the number of events; 0 means DefaultQueryLimit
+	Limit int
+}
+
+const (
+	DefaultQueryLimit = 100
+	MaxQueryLimit     = 1000
+)
+
+// Matches reports whether an event satisfies the query's filters
+func (q *Query) Matches(e *Event) bool {
+	switch {
+	case e.Sequence <= q.AfterSequence:
+		return false
+	case q.TenantID != "" && e.TenantID != q.TenantID:
+		return false
+	case q.ActorID != "" && e.ActorID != q.ActorID:
+		return false
+	case q.ResourceType != "" && e.ResourceType != q.ResourceType:
+		return false
+	case q.ResourceID != "" && e.ResourceID != q.ResourceID:
+		return false
+	case q.Action != "" && e.Action != q.Action:
+		return false
+	case !q.Since.IsZero() && e.Timestamp.Before(q.Since):
+		return false
+	case !q.Until.IsZero() && !e.Timestamp.Before(q.Until):
+		return false
+	}
+	return true
+}
+
+func (q *Query) limit() int {
+	if q.Limit <= 0 {
+		return DefaultQueryLimit
+	}
+	return min(q.Limit, MaxQueryLimit)
+}
+
+// Config configures a Log
+type Config struct {
+	// CheckpointEvery signs a checkpoint after this many events; 0 disables
+	// count-based checkpoints
+	CheckpointEvery uint64
+	// CheckpointInterval is how often Run signs a checkpoint when new events
+	// were recorded
+	CheckpointInterval time.Duration
+}
+
+// DefaultConfig returns the default log configuration
+func DefaultConfig() Config {
+	return Config{
+		CheckpointEvery:    100,
+		CheckpointInterval: 5 * time.Minute,
+	}
+}
+
+// Log appends events to a Store, chaining and checkpointing them. Several
+// Logs, in one process or on several nodes, may share a store that all of
+// them reach, such as a RedisStore: the store rejects an event that does
+// not extend its chain, and Record then links the event to the new head
+// and retries. A FileStore admits a single writer.
+type Log struct {
+	store  Store
+	signer Signer
+	config Config
+	now    func() time.Time
+	mu     sync.Mutex
+}
+
+// maxAppendAttempts bounds how often Record relinks an event after other
+// writers extended the chain first
+const maxAppendAttempts = 16
+
+// NewLog creates a log; signer may be nil when checkpoints are not wanted
+func NewLog(store Store, signer Signer, config Config) (*Log, error) {
+	if store == nil {
+		return nil, errors.New("audit: store is required")
+	}
+	return &Log{store: store, signer: signer, config: config, now: time.Now}, nil
+}
+
+// Record appends an event, filling in its ID, timestamp, sequence and
+// hashes. The stored event is returned; when it completes a checkpoint
+// period and signing fails, the event is still recorded and the error is
+// returned alongside it.
+func (l *Log) Record(ctx context.Context, event Event) (*Event, error) {
+	if event.Action == "" || event.ResourceType == "" {
+		return nil, errors.New("audit: action and resource type are required")
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var err error
+	if event.ID == "" {
+		event.ID = uuid.NewString()
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = l.now()
+	}
+	event.Timestamp = event.Timestamp.UTC()
+	if event.Data, err = normalizeData(event.Data); err != nil {
+		return nil, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		err = l.append(ctx, &event)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, ErrSequenceConflict) || attempt == maxAppendAttempts {
+			return nil, err
+		}
+	}
+	if l.signer != nil && l.config.CheckpointEvery > 0 && event.Sequence%l.config.CheckpointEvery == 0 {
+		if _, err := l.checkpoint(ctx, &event); err != nil {
+			return &event, fmt.Errorf("audit: event %d recorded but checkpoint failed: %w", event.Sequence, err)
+		}
+	}
+	return &event, nil
+}
+
+// append links event to the stored head and appends it; callers hold the
+// lock
+func (l *Log) append(ctx context.Context, event *Event) error {
+	last, err := l.store.Last(ctx)
+	if err != nil {
+		return err
+	}
+	event.Sequence, event.PrevHash = 1, ""
+	if last != nil {
+		event.Sequence, event.PrevHash = last.Sequence+1, last.Hash
+	}
+	if event.Hash, err = event.ComputeHash(); err != nil {
+		return err
+	}
+	return l.store.Append(ctx, event)
+}
+
+// Query returns the events matching q
+func (l *Log) Query(ctx context.Context, q Query) ([]*Event, error) {
+	return l.store.Query(ctx, q)
+}
+
+// Checkpoint signs the current head of the chain. It returns the latest
+// checkpoint unchanged when no events were recorded since.
+func (l *Log) Checkpoint(ctx context.Context) (*Checkpoint, error) {
+	if l.signer == nil {
+		return nil, errors.New("audit: no signer configured")
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	last, err := l.store.Last(ctx)
+	if err != nil || last == nil {
+		return nil, err
+	}
+	latest, err := l.store.LatestCheckpoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if latest != nil && latest.Sequence == last.Sequence {
+		return latest, nil
+	}
+	return l.checkpoint(ctx, last)
+}
+
+// Run signs a checkpoint every CheckpointInterval until ctx is cancelled
+func (l *Log) Run(ctx context.Context) error {
+	if l.config.CheckpointInterval <= 0 {
+		return errors.New("audit: checkpoint interval must be positive")
+	}
+
+	ticker := time.NewTicker(l.config.CheckpointInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if _, err := l.Checkpoint(ctx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// checkpoint signs head; callers hold the lock
+func (l *Log) checkpoint(ctx context.Context, head *Event) (*Checkpoint, error) {
+	cp := &Checkpoint{
+		Sequence:  head.Sequence,
+		Hash:      head.Hash,
+		Timestamp: l.now().UTC(),
+		KeyID:     l.signer.KeyID(),
+	}
+	signature, err := l.signer.Sign(cp.signedBytes())
+	if err != nil {
+		return nil, err
+	}
+	cp.Signature = signature
+	if err := l.store.SaveCheckpoint(ctx, cp); err != nil {
+		return nil, err
+	}
+	return cp, nil
+}
+
+// Verification summarises a successful Verify
+type Verification struct {
+	Events      uint64 `json:"events"`
+	Checkpoints int    `json:"checkpoints"`
+	// Unanchored counts the events after the latest checkpoint, whose
+	// removal a verifier cannot detect
+	Unanchored uint64 `json:"unanchored"`
+}
+
+// verifyPageSize is how many events Verify reads at a time
+const verifyPageSize = 500
+
+// Verify recomputes the hash chain of every stored event up to the store's
+// head and checks every checkpoint's signature against verifier and against
+// the chain. Events missing before the head break the chain.
+func (l *Log) Verify(ctx context.Context, verifier Verifier) (*Verification, error) {
+	checkpoints, err := l.store.Checkpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	anchors := make(map[uint64]*Checkpoint, len(checkpoints))
+	for _, cp := range checkpoints {
+		if err := verifier.Verify(cp.KeyID, cp.signedBytes(), cp.Signature); err != nil {
+			return nil, fmt.Errorf("%w: checkpoint %d: %v", ErrInvalidCheckpoint, cp.Sequence, err)
+		}
+		anchors[cp.Sequence] = cp
+	}
+
+	// Read the head after the checkpoints, so every checkpoint lies at or
+	// before it unless events were removed
+	head, err := l.store.Last(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var headSequence uint64
+	if head != nil {
+		headSequence = head.Sequence
+	}
+
+	var prevHash string
+	var last, anchored uint64
+	for last < headSequence {
+		events, err := l.store.Range(ctx, last, verifyPageSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(events) == 0 {
+			return nil, fmt.Errorf("%w: events %d to %d are missing", ErrChainBroken, last+1, headSequence)
+		}
+		for _, event := range events {
+			if event.Sequence != last+1 {
+				return nil, fmt.Errorf("%w: expected event %d, found %d", ErrChainBroken, last+1, event.Sequence)
+			}
+			// Events appended since the head was read are left for the next Verify
+			if event.Sequence > headSequence {
+				break
+			}
+			if event.PrevHash != prevHash {
+				return nil, fmt.Errorf("%w: event %d is not linked to its predecessor", ErrChainBroken, event.Sequence)
+			}
+			hash, err := event.ComputeHash()
+			if err != nil {
+				return nil, err
+			}
+			if hash != event.Hash {
+				return nil, fmt.Errorf("%w: event %d was modified", ErrChainBroken, event.Sequence)
+			}
+			if cp, ok := anchors[event.Sequence]; ok {
+				if cp.Hash != event.Hash {
+					return nil, fmt.Errorf("%w: checkpoint %d does not match the chain", ErrInvalidCheckpoint, cp.Sequence)
+				}
+				anchored = event.Sequence
+				delete(anchors, event.Sequence)
+			}
+			prevHash, last = event.Hash, event.Sequence
+		}
+	}
+
+	// A checkpoint beyond the last event means events were removed
+	for sequence := range anchors {
+		return nil, fmt.Errorf("%w: checkpoint %d is beyond the last event %d", ErrChainBroken, sequence, last)
+	}
+	return &Verification{
+		Events:      last,
+		Checkpoints: len(checkpoints),
+		Unanchored:  last - anchored,
+	}, nil
+}
+
+// normalizeData gives Data the shape it has after being stored and decoded,
+// so the hash computed now still matches when the event is verified
+func normalizeData(data map[string]any) (map[string]any, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("audit: encode event data: %w", err)
+	}
+	var normalized map[string]any
+	if err := decodeJSON(encoded, &normalized); err != nil {
+		return nil, fmt.Errorf("audit: decode event data: %w", err)
+	}
+	return normalized, nil
+}
+
+// decodeJSON decodes numbers as json.Number, which re-encodes exactly
+func decodeJSON(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
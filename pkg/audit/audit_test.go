@@ -0,0 +1,533 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/niiniyare/ruun/schema"
+	"github.com/niiniyare/ruun/theme"
+)
+
+func newTestSigner(t *testing.T) *Ed25519Signer {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := NewEd25519Signer(key)
+	require.NoError(t, err)
+	return signer
+}
+
+// recordEvents records count events alternating between two tenants
+func recordEvents(t *testing.T, log *Log, count int) {
+	t.Helper()
+	for i := range count {
+		tenant := []string{"acme", "globex"}[i%2]
+		_, err := log.Record(context.Background(), Event{
+			TenantID:     tenant,
+			ActorID:      fmt.Sprintf("user-%d", i%3),
+			Action:       "schema.updated",
+			ResourceType: ResourceSchema,
+			ResourceID:   fmt.Sprintf("form-%d", i),
+			Data:         map[string]any{"index": i, "detail": struct{ Note string }{"changed"}},
+		})
+		require.NoError(t, err)
+	}
+}
+
+func TestLogRecordAndVerify(t *testing.T) {
+	ctx := context.Background()
+	signer := newTestSigner(t)
+	log, err := NewLog(NewMemoryStore(), signer, Config{CheckpointEvery: 3})
+	require.NoError(t, err)
+
+	recordEvents(t, log, 7)
+
+	events, err := log.Query(ctx, Query{})
+	require.NoError(t, err)
+	require.Len(t, events, 7)
+	assert.Empty(t, events[0].PrevHash)
+	assert.Equal(t, events[0].Hash, events[1].PrevHash)
+	assert.Equal(t, uint64(7), events[6].Sequence)
+
+	result, err := log.Verify(ctx, signer.Verifier())
+	require.NoError(t, err)
+	assert.Equal(t, &Verification{Events: 7, Checkpoints: 2, Unanchored: 1}, result)
+
+	cp, err := log.Checkpoint(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(7), cp.Sequence)
+	again, err := log.Checkpoint(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, cp, again, "no new checkpoint without new events")
+
+	_, err = log.Verify(ctx, newTestSigner(t).Verifier())
+	assert.ErrorIs(t, err, ErrInvalidCheckpoint, "checkpoints from an unknown key fail")
+}
+
+func TestLogQuery(t *testing.T) {
+	ctx := context.Background()
+	log, err := NewLog(NewMemoryStore(), nil, Config{})
+	require.NoError(t, err)
+	recordEvents(t, log, 10)
+
+	acme, err := log.Query(ctx, Query{TenantID: "acme"})
+	require.NoError(t, err)
+	assert.Len(t, acme, 5)
+
+	byActor, err := log.Query(ctx, Query{TenantID: "acme", ActorID: "user-0"})
+	require.NoError(t, err)
+	require.Len(t, byActor, 2)
+	assert.Equal(t, "form-0", byActor[0].ResourceID)
+	assert.Equal(t, "form-6", byActor[1].ResourceID)
+
+	byResource, err := log.Query(ctx, Query{ResourceType: ResourceSchema, ResourceID: "form-3"})
+	require.NoError(t, err)
+	require.Len(t, byResource, 1)
+
+	page, err := log.Query(ctx, Query{Limit: 4})
+	require.NoError(t, err)
+	require.Len(t, page, 4)
+	next, err := log.Query(ctx, Query{AfterSequence: page[3].Sequence, Limit: 4})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), next[0].Sequence)
+
+	none, err := log.Query(ctx, Query{Until: page[0].Timestamp})
+	require.NoError(t, err)
+	assert.Empty(t, none)
+	all, err := log.Query(ctx, Query{Since: page[0].Timestamp})
+	require.NoError(t, err)
+	assert.Len(t, all, 10)
+}
+
+func TestMemoryStoreRejectsForks(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	log, err := NewLog(store, nil, Config{})
+	require.NoError(t, err)
+	first, err := log.Record(ctx, Event{Action: "form.submitted", ResourceType: ResourceForm})
+	require.NoError(t, err)
+
+	fork := *first
+	fork.Sequence = 2
+	assert.ErrorIs(t, store.Append(ctx, &fork), ErrSequenceConflict)
+}
+
+func newFileLog(t *testing.T, dir string, signer Signer) (*Log, *FileStore) {
+	t.Helper()
+	store, err := NewFileStore(dir)
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+	log, err := NewLog(store, signer, Config{CheckpointEvery: 5})
+	require.NoError(t, err)
+	return log, store
+}
+
+// editLines rewrites a store file line by line
+func editLines(t *testing.T, path string, fn func(lines [][]byte) [][]byte) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := fn(bytes.Split(bytes.TrimSuffix(data, []byte("\n")), []byte("\n")))
+	require.NoError(t, os.WriteFile(path, append(bytes.Join(lines, []byte("\n")), '\n'), 0o600))
+}
+
+func TestFileStoreDetectsTampering(t *testing.T) {
+	ctx := context.Background()
+	signer := newTestSigner(t)
+
+	setup := func(t *testing.T) string {
+		dir := t.TempDir()
+		log, store := newFileLog(t, dir, signer)
+		recordEvents(t, log, 12)
+		require.NoError(t, store.Close())
+		return dir
+	}
+
+	t.Run("survives reopening", func(t *testing.T) {
+		dir := setup(t)
+		log, _ := newFileLog(t, dir, signer)
+		result, err := log.Verify(ctx, signer.Verifier())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(12), result.Events)
+		assert.Equal(t, 2, result.Checkpoints)
+
+		recordEvents(t, log, 1)
+		_, err = log.Verify(ctx, signer.Verifier())
+		require.NoError(t, err)
+	})
+
+	t.Run("edited event", func(t *testing.T) {
+		dir := setup(t)
+		editLines(t, filepath.Join(dir, eventsFile), func(lines [][]byte) [][]byte {
+			lines[3] = bytes.Replace(lines[3], []byte(`"user-0"`), []byte(`"user-2"`), 1)
+			return lines
+		})
+		log, _ := newFileLog(t, dir, signer)
+		_, err := log.Verify(ctx, signer.Verifier())
+		assert.ErrorIs(t, err, ErrChainBroken)
+		assert.ErrorContains(t, err, "event 4 was modified")
+	})
+
+	t.Run("removed event", func(t *testing.T) {
+		dir := setup(t)
+		editLines(t, filepath.Join(dir, eventsFile), func(lines [][]byte) [][]byte {
+			return append(lines[:6], lines[7:]...)
+		})
+		log, _ := newFileLog(t, dir, signer)
+		_, err := log.Verify(ctx, signer.Verifier())
+		assert.ErrorIs(t, err, ErrChainBroken)
+	})
+
+	t.Run("truncated log", func(t *testing.T) {
+		dir := setup(t)
+		editLines(t, filepath.Join(dir, eventsFile), func(lines [][]byte) [][]byte {
+			return lines[:8]
+		})
+		log, _ := newFileLog(t, dir, signer)
+		_, err := log.Verify(ctx, signer.Verifier())
+		assert.ErrorIs(t, err, ErrChainBroken)
+		assert.ErrorContains(t, err, "checkpoint 10 is beyond the last event 8")
+	})
+
+	t.Run("rewritten chain", func(t *testing.T) {
+		dir := setup(t)
+		memory := NewMemoryStore()
+		forged, err := NewLog(memory, nil, Config{})
+		require.NoError(t, err)
+		recordEvents(t, forged, 12)
+		var lines [][]byte
+		events, err := memory.Range(ctx, 0, 100)
+		require.NoError(t, err)
+		for _, event := range events {
+			line, err := json.Marshal(event)
+			require.NoError(t, err)
+			lines = append(lines, line)
+		}
+		editLines(t, filepath.Join(dir, eventsFile), func([][]byte) [][]byte { return lines })
+
+		log, _ := newFileLog(t, dir, signer)
+		_, err = log.Verify(ctx, signer.Verifier())
+		assert.ErrorIs(t, err, ErrInvalidCheckpoint, "a consistent but rewritten chain no longer matches the checkpoints")
+	})
+}
+
+// gappyStore hides some events from Range, as a RedisStore does when they
+// were deleted from its events hash
+type gappyStore struct {
+	Store
+	missing map[uint64]bool
+}
+
+func (s *gappyStore) Range(ctx context.Context, after uint64, limit int) ([]*Event, error) {
+	events, err := s.Store.Range(ctx, after, limit)
+	if err != nil {
+		return nil, err
+	}
+	kept := events[:0]
+	for _, event := range events {
+		if !s.missing[event.Sequence] {
+			kept = append(kept, event)
+		}
+	}
+	return kept, nil
+}
+
+func TestVerifyDetectsMissingEvents(t *testing.T) {
+	ctx := context.Background()
+	signer := newTestSigner(t)
+	memory := NewMemoryStore()
+	log, err := NewLog(memory, signer, Config{CheckpointEvery: 200})
+	require.NoError(t, err)
+	recordEvents(t, log, 2*verifyPageSize+10)
+
+	for name, missing := range map[string][]uint64{
+		"page boundary": {verifyPageSize},
+		"whole page":    sequences(verifyPageSize+1, 2*verifyPageSize),
+		"tail":          {2*verifyPageSize + 9, 2*verifyPageSize + 10},
+	} {
+		t.Run(name, func(t *testing.T) {
+			store := &gappyStore{Store: memory, missing: make(map[uint64]bool)}
+			for _, sequence := range missing {
+				store.missing[sequence] = true
+			}
+			gappy, err := NewLog(store, signer, Config{})
+			require.NoError(t, err)
+			_, err = gappy.Verify(ctx, signer.Verifier())
+			assert.ErrorIs(t, err, ErrChainBroken)
+		})
+	}
+
+	result, err := log.Verify(ctx, signer.Verifier())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2*verifyPageSize+10), result.Events)
+}
+
+func sequences(from, to uint64) []uint64 {
+	var result []uint64
+	for sequence := from; sequence <= to; sequence++ {
+		result = append(result, sequence)
+	}
+	return result
+}
+
+func TestRegistryRecorder(t *testing.T) {
+	ctx := context.Background()
+	log, err := NewLog(NewMemoryStore(), nil, Config{})
+	require.NoError(t, err)
+	recorder := NewRegistryRecorder(log)
+	recorder.ErrorHandler = func(err error) { t.Error(err) }
+
+	now := time.Now()
+	recorder.Handle(&schema.RegistryEvent{ID: "e1", Type: schema.EventSchemaRegistered, SchemaID: "invoice", ActorID: "u1", TenantID: "acme", Origin: "node-a", Timestamp: now})
+	recorder.Handle(&schema.RegistryEvent{ID: "e2", Type: schema.EventSchemaAccessed, SchemaID: "invoice", Timestamp: now})
+	recorder.Handle(&schema.RegistryEvent{ID: "e3", Type: schema.EventSchemaUpdated, SchemaID: "invoice", Timestamp: now, Remote: true})
+
+	events, err := log.Query(ctx, Query{ResourceType: ResourceSchema})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "e1", events[0].ID)
+	assert.Equal(t, "schema.registered", events[0].Action)
+	assert.Equal(t, "u1", events[0].ActorID)
+	assert.Equal(t, "acme", events[0].TenantID)
+	assert.Equal(t, "node-a", events[0].Data["origin"])
+}
+
+func TestThemeRecorder(t *testing.T) {
+	ctx := context.Background()
+	log, err := NewLog(NewMemoryStore(), nil, Config{})
+	require.NoError(t, err)
+	recorder := NewThemeRecorder(log)
+
+	adminCtx := schema.ContextWithUser(ctx, &schema.BasicUser{ID: "admin", TenantID: "acme"})
+	recorder.OnThemeUpdated(adminCtx, &theme.ThemeEvent{ThemeID: "ocean", ThemeName: "Ocean", Action: "updated"})
+	recorder.OnCacheHit(ctx, &theme.CacheEvent{Key: "ocean"})
+	recorder.OnThemeSwitched(ctx, &theme.ThemeSwitchEvent{UserID: "u1", TenantID: "acme", PreviousTheme: "light", NewTheme: "ocean"})
+
+	events, err := log.Query(ctx, Query{})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "theme.updated", events[0].Action)
+	assert.Equal(t, "ocean", events[0].ResourceID)
+	assert.Equal(t, "admin", events[0].ActorID, "theme changes are attributed to the user in the context")
+	assert.Equal(t, "acme", events[0].TenantID)
+
+	switched, err := log.Query(ctx, Query{TenantID: "acme", ActorID: "u1"})
+	require.NoError(t, err)
+	require.Len(t, switched, 1)
+	assert.Equal(t, "theme.switched", switched[0].Action)
+	assert.Equal(t, "ocean", switched[0].Data["newTheme"])
+}
+
+func TestWorkflowAndSubmissionEvents(t *testing.T) {
+	ctx := context.Background()
+	log, err := NewLog(NewMemoryStore(), nil, Config{})
+	require.NoError(t, err)
+
+	transition, err := log.Record(ctx, WorkflowTransitionEvent("invoice", "acme", schema.WorkflowHistoryEntry{
+		ID: "h1", Action: "approve", FromStage: "review", ToStage: "approved", ActorID: "u2", ActorName: "Bea",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "workflow.transition", transition.Action)
+	assert.Equal(t, "approved", transition.Data["toStage"])
+
+	form := &schema.Schema{ID: "customer", Security: &schema.Security{Encryption: &schema.Encryption{
+		Enabled: true, Fields: []string{"ssn"},
+	}}}
+	userCtx := schema.ContextWithUser(ctx, &schema.BasicUser{ID: "u1", TenantID: "acme"})
+	submitted, err := log.Record(ctx, FormSubmissionEvent(userCtx, form, map[string]any{
+		"name": "Alice", "ssn": "123-45-6789", "ssn_bidx": "abc",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "u1", submitted.ActorID)
+	assert.Equal(t, "acme", submitted.TenantID)
+	assert.Equal(t, []any{"name", "ssn"}, submitted.Data["fields"])
+	assert.Equal(t, []any{"ssn"}, submitted.Data["encryptedFields"])
+	assert.NotContains(t, fmt.Sprint(submitted.Data), "123-45-6789", "values are never recorded")
+}
+
+func TestLogsShareStore(t *testing.T) {
+	ctx := context.Background()
+	signer := newTestSigner(t)
+	store := NewMemoryStore()
+
+	var wg sync.WaitGroup
+	for range 3 {
+		log, err := NewLog(store, signer, Config{CheckpointEvery: 10})
+		require.NoError(t, err)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			recordEvents(t, log, 20)
+		}()
+	}
+	wg.Wait()
+
+	log, err := NewLog(store, nil, Config{})
+	require.NoError(t, err)
+	result, err := log.Verify(ctx, signer.Verifier())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(60), result.Events, "writers losing a race relink and retry")
+	assert.Equal(t, 6, result.Checkpoints)
+}
+
+func TestFileStoreSingleWriter(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileStore(dir)
+	require.NoError(t, err)
+
+	_, err = NewFileStore(dir)
+	assert.ErrorIs(t, err, ErrStoreLocked)
+
+	require.NoError(t, store.Close())
+	reopened, err := NewFileStore(dir)
+	require.NoError(t, err)
+	require.NoError(t, reopened.Close())
+}
+
+func TestRedisStore(t *testing.T) {
+	ctx := context.Background()
+	client, mock := redismock.NewClientMock()
+	store, err := NewRedisStore(client, "test:")
+	require.NoError(t, err)
+	const (
+		eventsKey      = "test:{audit}:events"
+		headKey        = "test:{audit}:head"
+		checkpointsKey = "test:{audit}:checkpoints"
+	)
+
+	mock.ExpectHGet(headKey, "sequence").RedisNil()
+	last, err := store.Last(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, last)
+
+	event := &Event{ID: "e1", Sequence: 1, Action: "schema.updated", ResourceType: ResourceSchema, Data: map[string]any{"index": json.Number("1")}}
+	event.Hash, err = event.ComputeHash()
+	require.NoError(t, err)
+	data, err := json.Marshal(event)
+	require.NoError(t, err)
+	keys := []string{eventsKey, headKey}
+
+	mock.ExpectEvalSha(redisAppendScript.Hash(), keys, event.Sequence, event.PrevHash, event.Hash, data).SetVal(int64(1))
+	require.NoError(t, store.Append(ctx, event))
+	mock.ExpectEvalSha(redisAppendScript.Hash(), keys, event.Sequence, event.PrevHash, event.Hash, data).SetVal(int64(0))
+	assert.ErrorIs(t, store.Append(ctx, event), ErrSequenceConflict)
+
+	mock.ExpectHGet(headKey, "sequence").SetVal("1")
+	mock.ExpectHGet(eventsKey, "1").SetVal(string(data))
+	last, err = store.Last(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, event, last)
+
+	mock.ExpectHGet(headKey, "sequence").SetVal("2")
+	mock.ExpectHMGet(eventsKey, "1", "2").SetVal([]any{string(data), nil})
+	events, err := store.Range(ctx, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []*Event{event}, events, "missing events are left for Verify to report")
+
+	mock.ExpectHGet(headKey, "sequence").SetVal("2")
+	mock.ExpectHGet(eventsKey, "2").RedisNil()
+	_, err = store.Last(ctx)
+	assert.ErrorIs(t, err, ErrChainBroken, "a deleted head event breaks the chain")
+
+	mock.ExpectHGet(headKey, "sequence").SetVal("1")
+	mock.ExpectHMGet(eventsKey, "1").SetVal([]any{string(data)})
+	mock.ExpectHGet(headKey, "sequence").SetVal("1")
+	matched, err := store.Query(ctx, Query{Action: "schema.updated"})
+	require.NoError(t, err)
+	assert.Len(t, matched, 1)
+
+	cp := &Checkpoint{Sequence: 1, Hash: event.Hash, KeyID: "k1", Signature: []byte{1}}
+	cpData, err := json.Marshal(cp)
+	require.NoError(t, err)
+	mock.ExpectRPush(checkpointsKey, cpData).SetVal(1)
+	require.NoError(t, store.SaveCheckpoint(ctx, cp))
+	mock.ExpectLRange(checkpointsKey, 0, -1).SetVal([]string{string(cpData)})
+	latest, err := store.LatestCheckpoint(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, cp.Hash, latest.Hash)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecorderLogsFailures(t *testing.T) {
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	log, err := NewLog(NewMemoryStore(), nil, Config{})
+	require.NoError(t, err)
+	NewRegistryRecorder(log).Handle(&schema.RegistryEvent{SchemaID: "invoice"})
+	assert.Contains(t, buf.String(), "audit: event not recorded")
+	assert.Contains(t, buf.String(), "resourceId=invoice")
+}
+
+func TestCapture(t *testing.T) {
+	ctx := context.Background()
+	log, err := NewLog(NewMemoryStore(), nil, Config{})
+	require.NoError(t, err)
+	failOnError := func(err error) { t.Error(err) }
+
+	config := schema.DefaultRegistryConfig()
+	config.ValidateOnStore = false
+	registry, err := schema.NewRegistry(config)
+	require.NoError(t, err)
+	themeConfig := theme.DefaultManagerConfig()
+	themeConfig.EnableValidation = false
+	themes, err := theme.NewObservableManager(themeConfig, nil, nil)
+	require.NoError(t, err)
+
+	observer := Capture(log, Sources{Registry: registry, Themes: themes}, failOnError)
+
+	adminCtx := schema.ContextWithUser(ctx, &schema.BasicUser{ID: "admin", TenantID: "acme"})
+	require.NoError(t, registry.Register(adminCtx, &schema.Schema{ID: "invoice", Type: schema.TypeForm, Title: "Invoice"}))
+	require.Eventually(t, func() bool {
+		events, err := log.Query(ctx, Query{ResourceType: ResourceSchema, ActorID: "admin", TenantID: "acme"})
+		return err == nil && len(events) == 1
+	}, time.Second, 10*time.Millisecond, "registry events are delivered asynchronously")
+
+	require.NoError(t, themes.RegisterTheme(adminCtx, &theme.Theme{ID: "ocean", Name: "Ocean"}))
+	recorded, err := log.Query(ctx, Query{ResourceType: ResourceTheme})
+	require.NoError(t, err)
+	require.Len(t, recorded, 1)
+	assert.Equal(t, "theme.registered", recorded[0].Action)
+	assert.Equal(t, "admin", recorded[0].ActorID)
+
+	form := &schema.Schema{ID: "invoice", Workflow: &schema.Workflow{
+		Enabled: true,
+		Stage:   "review",
+		Actions: []schema.WorkflowAction{{ID: "approve", Type: "approve", ToStage: "approved"}},
+	}}
+	runtime := schema.NewRuntime(form).
+		WithObserver(observer).
+		WithInitialData(map[string]any{"amount": 10}).
+		MustBuild()
+	userCtx := schema.ContextWithUser(ctx, &schema.BasicUser{ID: "u1", TenantID: "acme"})
+	require.NoError(t, runtime.HandleSubmit(userCtx))
+	_, err = runtime.ApplyWorkflowAction(userCtx, "approve", "")
+	require.NoError(t, err)
+
+	submitted, err := log.Query(ctx, Query{ResourceType: ResourceForm, ActorID: "u1"})
+	require.NoError(t, err)
+	require.Len(t, submitted, 1)
+	assert.Equal(t, []any{"amount"}, submitted[0].Data["fields"])
+
+	transitions, err := log.Query(ctx, Query{ResourceType: ResourceWorkflow, TenantID: "acme"})
+	require.NoError(t, err)
+	require.Len(t, transitions, 1)
+	assert.Equal(t, "approved", transitions[0].Data["toStage"])
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package audit
+
+import "os"
+
+// lockWriter is a no-op where advisory file locks are unavailable; a single
+// writer per FileStore directory is then up to the deployment
+func lockWriter(file *os.File) error {
+	return nil
+}
@@ -0,0 +1,214 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Redis key layout. Every key carries the {audit} hash tag so the append
+// script, which touches the events and the head, runs on one Redis Cluster
+// slot:
+//
+//	<prefix>{audit}:events       hash of sequence -> event JSON
+//	<prefix>{audit}:head         hash of the last event's sequence and hash
+//	<prefix>{audit}:checkpoints  list of checkpoint JSON, in the order saved
+const redisHashTag = "{audit}:"
+
+// redisAppendScript appends an event only if it extends the stored head,
+// returning 1 when it was appended and 0 otherwise.
+//
+//	KEYS: events, head
+//	ARGV: sequence, prevHash, hash, event JSON
+var redisAppendScript = redis.NewScript(`
+local head = redis.call('HMGET', KEYS[2], 'sequence', 'hash')
+local sequence = tonumber(head[1]) or 0
+local hash = head[2] or ''
+if tonumber(ARGV[1]) ~= sequence + 1 or ARGV[2] ~= hash then
+	return 0
+end
+redis.call('HSET', KEYS[1], ARGV[1], ARGV[4])
+redis.call('HSET', KEYS[2], 'sequence', ARGV[1], 'hash', ARGV[3])
+return 1
+`)
+
+// queryPageSize is how many events RedisStore.Query reads at a time
+const queryPageSize = 500
+
+// RedisStore keeps events and checkpoints in Redis, so every node of a
+// deployment records into one chain. Appends are checked against the head
+// atomically, which lets the Logs of several nodes share the store.
+type RedisStore struct {
+	client redis.Cmdable
+	prefix string
+}
+
+func NewRedisStore(client redis.Cmdable, prefix string) (*RedisStore, error) {
+	if client == nil {
+		return nil, errors.New("audit: redis client is required")
+	}
+	if prefix == "" {
+		prefix = "audit:"
+	}
+	return &RedisStore{client: client, prefix: prefix}, nil
+}
+
+func (s *RedisStore) Append(ctx context.Context, event *Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("audit: encode event %d: %w", event.Sequence, err)
+	}
+	keys := []string{s.eventsKey(), s.headKey()}
+	applied, err := redisAppendScript.Run(ctx, s.client, keys, event.Sequence, event.PrevHash, event.Hash, data).Int()
+	if err != nil {
+		return fmt.Errorf("audit: append event %d: %w", event.Sequence, err)
+	}
+	if applied == 0 {
+		return fmt.Errorf("%w: event %d", ErrSequenceConflict, event.Sequence)
+	}
+	return nil
+}
+
+func (s *RedisStore) Last(ctx context.Context) (*Event, error) {
+	head, err := s.head(ctx)
+	if err != nil || head == 0 {
+		return nil, err
+	}
+	data, err := s.client.HGet(ctx, s.eventsKey(), strconv.FormatUint(head, 10)).Result()
+	if err == redis.Nil {
+		return nil, fmt.Errorf("%w: head event %d is missing", ErrChainBroken, head)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("audit: read event %d: %w", head, err)
+	}
+	return decodeEvent(data)
+}
+
+func (s *RedisStore) Range(ctx context.Context, after uint64, limit int) ([]*Event, error) {
+	head, err := s.head(ctx)
+	if err != nil || head <= after {
+		return nil, err
+	}
+	count := min(uint64((&Query{Limit: limit}).limit()), head-after)
+	fields := make([]string, 0, count)
+	for sequence := after + 1; sequence <= after+count; sequence++ {
+		fields = append(fields, strconv.FormatUint(sequence, 10))
+	}
+	values, err := s.client.HMGet(ctx, s.eventsKey(), fields...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("audit: read events: %w", err)
+	}
+
+	// A missing event is left out, for Log.Verify to report the gap
+	events := make([]*Event, 0, len(values))
+	for _, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			continue
+		}
+		event, err := decodeEvent(data)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// Query scans the events after q.AfterSequence in pages until it has found
+// q's limit of matches
+func (s *RedisStore) Query(ctx context.Context, q Query) ([]*Event, error) {
+	limit := q.limit()
+	var result []*Event
+	for after := q.AfterSequence; ; {
+		events, err := s.Range(ctx, after, queryPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range events {
+			if q.Matches(event) {
+				result = append(result, event)
+				if len(result) == limit {
+					return result, nil
+				}
+			}
+		}
+		if len(events) == 0 {
+			return result, nil
+		}
+		after = events[len(events)-1].Sequence
+	}
+}
+
+func (s *RedisStore) SaveCheckpoint(ctx context.Context, cp *Checkpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	if err := s.client.RPush(ctx, s.checkpointsKey(), data).Err(); err != nil {
+		return fmt.Errorf("audit: save checkpoint %d: %w", cp.Sequence, err)
+	}
+	return nil
+}
+
+func (s *RedisStore) Checkpoints(ctx context.Context) ([]*Checkpoint, error) {
+	values, err := s.client.LRange(ctx, s.checkpointsKey(), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("audit: read checkpoints: %w", err)
+	}
+	checkpoints := make([]*Checkpoint, len(values))
+	for i, value := range values {
+		var cp Checkpoint
+		if err := json.Unmarshal([]byte(value), &cp); err != nil {
+			return nil, fmt.Errorf("audit: decode checkpoint: %w", err)
+		}
+		checkpoints[i] = &cp
+	}
+	return checkpoints, nil
+}
+
+func (s *RedisStore) LatestCheckpoint(ctx context.Context) (*Checkpoint, error) {
+	checkpoints, err := s.Checkpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var latest *Checkpoint
+	for _, cp := range checkpoints {
+		if latest == nil || cp.Sequence > latest.Sequence {
+			latest = cp
+		}
+	}
+	return latest, nil
+}
+
+// head returns the sequence of the last event, or 0 when there is none
+func (s *RedisStore) head(ctx context.Context) (uint64, error) {
+	value, err := s.client.HGet(ctx, s.headKey(), "sequence").Result()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("audit: read head: %w", err)
+	}
+	sequence, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("audit: invalid head %q: %w", value, err)
+	}
+	return sequence, nil
+}
+
+func (s *RedisStore) eventsKey() string      { return s.prefix + redisHashTag + "events" }
+func (s *RedisStore) headKey() string        { return s.prefix + redisHashTag + "head" }
+func (s *RedisStore) checkpointsKey() string { return s.prefix + redisHashTag + "checkpoints" }
+
+func decodeEvent(data string) (*Event, error) {
+	var event Event
+	if err := decodeJSON([]byte(data), &event); err != nil {
+		return nil, fmt.Errorf("audit: decode event: %w", err)
+	}
+	return &event, nil
+}
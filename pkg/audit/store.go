@@ -0,0 +1,306 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// Store persists events and checkpoints. Events are append-only: Append
+// fails with ErrSequenceConflict unless the event extends the last one.
+type Store interface {
+	Append(ctx context.Context, event *Event) error
+	// Last returns the most recent event, or nil when the store is empty
+	Last(ctx context.Context) (*Event, error)
+	// Range returns up to limit events after the given sequence, in order
+	Range(ctx context.Context, after uint64, limit int) ([]*Event, error)
+	Query(ctx context.Context, q Query) ([]*Event, error)
+
+	SaveCheckpoint(ctx context.Context, cp *Checkpoint) error
+	// Checkpoints returns every checkpoint in the order they were saved
+	Checkpoints(ctx context.Context) ([]*Checkpoint, error)
+	// LatestCheckpoint returns the most recent checkpoint, or nil
+	LatestCheckpoint(ctx context.Context) (*Checkpoint, error)
+}
+
+// MemoryStore keeps events in memory, for tests and single-process tools.
+// Logs sharing a MemoryStore may record concurrently.
+type MemoryStore struct {
+	events      []*Event
+	checkpoints []*Checkpoint
+	mu          sync.RWMutex
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+func (s *MemoryStore) Append(ctx context.Context, event *Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.extends(event); err != nil {
+		return err
+	}
+	stored := *event
+	s.events = append(s.events, &stored)
+	return nil
+}
+
+// extends checks that event follows the last event; callers hold the lock
+func (s *MemoryStore) extends(event *Event) error {
+	var sequence uint64
+	var hash string
+	if n := len(s.events); n > 0 {
+		sequence, hash = s.events[n-1].Sequence, s.events[n-1].Hash
+	}
+	if event.Sequence != sequence+1 || event.PrevHash != hash {
+		return fmt.Errorf("%w: got sequence %d after %d", ErrSequenceConflict, event.Sequence, sequence)
+	}
+	return nil
+}
+
+func (s *MemoryStore) Last(ctx context.Context) (*Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.events) == 0 {
+		return nil, nil
+	}
+	last := *s.events[len(s.events)-1]
+	return &last, nil
+}
+
+func (s *MemoryStore) Range(ctx context.Context, after uint64, limit int) ([]*Event, error) {
+	return s.Query(ctx, Query{AfterSequence: after, Limit: limit})
+}
+
+func (s *MemoryStore) Query(ctx context.Context, q Query) ([]*Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	limit := q.limit()
+	start := sort.Search(len(s.events), func(i int) bool {
+		return s.events[i].Sequence > q.AfterSequence
+	})
+
+	var result []*Event
+	for _, event := range s.events[start:] {
+		if len(result) == limit {
+			break
+		}
+		if q.Matches(event) {
+			found := *event
+			result = append(result, &found)
+		}
+	}
+	return result, nil
+}
+
+func (s *MemoryStore) SaveCheckpoint(ctx context.Context, cp *Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *cp
+	s.checkpoints = append(s.checkpoints, &stored)
+	return nil
+}
+
+func (s *MemoryStore) Checkpoints(ctx context.Context) ([]*Checkpoint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]*Checkpoint, len(s.checkpoints))
+	for i, cp := range s.checkpoints {
+		found := *cp
+		result[i] = &found
+	}
+	return result, nil
+}
+
+func (s *MemoryStore) LatestCheckpoint(ctx context.Context) (*Checkpoint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var latest *Checkpoint
+	for _, cp := range s.checkpoints {
+		if latest == nil || cp.Sequence > latest.Sequence {
+			latest = cp
+		}
+	}
+	if latest == nil {
+		return nil, nil
+	}
+	found := *latest
+	return &found, nil
+}
+
+const (
+	eventsFile      = "events.jsonl"
+	checkpointsFile = "checkpoints.jsonl"
+)
+
+// ErrStoreLocked is returned by NewFileStore when another FileStore, in
+// this or another process, has the directory open
+var ErrStoreLocked = errors.New("audit: store is locked by another writer")
+
+// FileStore appends events and checkpoints as JSON lines to files in a
+// directory, syncing every write. Events are indexed in memory when the
+// store is opened; the files are loaded as they are, so tampering is
+// reported by Log.Verify rather than hidden by a failed open.
+//
+// A FileStore is the only writer of its directory: it holds an exclusive
+// lock on the events file until Close, and a second store opened on the
+// same directory fails with ErrStoreLocked. Nodes that all record events
+// must share a RedisStore instead of keeping one FileStore each.
+type FileStore struct {
+	*MemoryStore
+	events      *os.File
+	checkpoints *os.File
+	mu          sync.Mutex
+}
+
+func NewFileStore(dir string) (*FileStore, error) {
+	if dir == "" {
+		return nil, errors.New("audit: store directory cannot be empty")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("audit: create store directory: %w", err)
+	}
+
+	events, err := openAppendOnly(filepath.Join(dir, eventsFile))
+	if err != nil {
+		return nil, err
+	}
+	if err := lockWriter(events); err != nil {
+		events.Close()
+		if errors.Is(err, ErrStoreLocked) {
+			return nil, fmt.Errorf("%w: %s", ErrStoreLocked, dir)
+		}
+		return nil, fmt.Errorf("audit: lock %s: %w", eventsFile, err)
+	}
+
+	memory, err := loadFileStore(dir)
+	if err != nil {
+		events.Close()
+		return nil, err
+	}
+	checkpoints, err := openAppendOnly(filepath.Join(dir, checkpointsFile))
+	if err != nil {
+		events.Close()
+		return nil, err
+	}
+	return &FileStore{MemoryStore: memory, events: events, checkpoints: checkpoints}, nil
+}
+
+// loadFileStore indexes the events and checkpoints stored in dir
+func loadFileStore(dir string) (*MemoryStore, error) {
+	memory := NewMemoryStore()
+	if err := readJSONLines(filepath.Join(dir, eventsFile), func(line []byte) error {
+		var event Event
+		if err := decodeJSON(line, &event); err != nil {
+			return err
+		}
+		memory.events = append(memory.events, &event)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if err := readJSONLines(filepath.Join(dir, checkpointsFile), func(line []byte) error {
+		var cp Checkpoint
+		if err := json.Unmarshal(line, &cp); err != nil {
+			return err
+		}
+		memory.checkpoints = append(memory.checkpoints, &cp)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return memory, nil
+}
+
+func (s *FileStore) Append(ctx context.Context, event *Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.MemoryStore.mu.RLock()
+	err := s.MemoryStore.extends(event)
+	s.MemoryStore.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	if err := appendJSONLine(s.events, event); err != nil {
+		return err
+	}
+	return s.MemoryStore.Append(ctx, event)
+}
+
+func (s *FileStore) SaveCheckpoint(ctx context.Context, cp *Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := appendJSONLine(s.checkpoints, cp); err != nil {
+		return err
+	}
+	return s.MemoryStore.SaveCheckpoint(ctx, cp)
+}
+
+// Close closes the underlying files, releasing the directory to another
+// writer
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return errors.Join(s.events.Close(), s.checkpoints.Close())
+}
+
+func openAppendOnly(path string) (*os.File, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("audit: open %s: %w", filepath.Base(path), err)
+	}
+	return file, nil
+}
+
+func appendJSONLine(file *os.File, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("audit: write %s: %w", filepath.Base(file.Name()), err)
+	}
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("audit: sync %s: %w", filepath.Base(file.Name()), err)
+	}
+	return nil
+}
+
+func readJSONLines(path string, fn func(line []byte) error) error {
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("audit: open %s: %w", filepath.Base(path), err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		if err := fn(scanner.Bytes()); err != nil {
+			return fmt.Errorf("audit: %s line %d: %w", filepath.Base(path), line, err)
+		}
+	}
+	return scanner.Err()
+}
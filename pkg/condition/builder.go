@@ -14,8 +14,6 @@ import (
 	"time"
 
 	"github.com/dlclark/regexp2"
-	"github.com/expr-lang/expr"
-	"github.com/expr-lang/expr/vm"
 	"github.com/google/uuid"
 )
 
@@ -690,8 +688,20 @@ func (e *Evaluator) Evaluate(ctx context.Context, root any, evalCtx *EvalContext
 	return result, err
 }
 
-// evaluateWithDepth handles depth tracking and delegates to specific evaluators
+// evaluateWithDepth evaluates a node, recording it when the context carries
+// an explain tracer
 func (e *Evaluator) evaluateWithDepth(ctx context.Context, node any, evalCtx *EvalContext, depth int) (bool, error) {
+	if t := tracerFromContext(ctx); t != nil {
+		t.enter(node)
+		result, err := e.evaluateNode(ctx, node, evalCtx, depth)
+		t.exit(result, err)
+		return result, err
+	}
+	return e.evaluateNode(ctx, node, evalCtx, depth)
+}
+
+// evaluateNode handles depth tracking and delegates to specific evaluators
+func (e *Evaluator) evaluateNode(ctx context.Context, node any, evalCtx *EvalContext, depth int) (bool, error) {
 	// Check context cancellation early
 	select {
 	case <-ctx.Done():
@@ -814,6 +824,8 @@ func mapToStruct(m map[string]any, target any) error {
 // evaluateGroup evaluates a condition group with AND/OR/NOT logic
 func (e *Evaluator) evaluateGroup(ctx context.Context, group *ConditionGroup, evalCtx *EvalContext, depth int) (bool, error) {
 	atomic.AddInt32(&evalCtx.metrics.GroupsEvaluated, 1)
+	t := tracerFromContext(ctx)
+	t.group(group)
 
 	// Evaluate formula if present using expr-lang
 	if group.If != "" {
@@ -844,6 +856,7 @@ func (e *Evaluator) evaluateGroup(ctx context.Context, group *ConditionGroup, ev
 			finalResult = finalResult && result
 			// Short-circuit on first false for AND
 			if !finalResult {
+				t.skip(group.Children[i+1:])
 				break
 			}
 		}
@@ -857,6 +870,7 @@ func (e *Evaluator) evaluateGroup(ctx context.Context, group *ConditionGroup, ev
 			finalResult = finalResult || result
 			// Short-circuit on first true for OR
 			if finalResult {
+				t.skip(group.Children[i+1:])
 				break
 			}
 		}
@@ -870,72 +884,18 @@ func (e *Evaluator) evaluateGroup(ctx context.Context, group *ConditionGroup, ev
 	return finalResult, nil
 }
 
-// evaluateFormula evaluates an expr-lang formula with complexity limits
+// evaluateFormula evaluates a boolean expr-lang formula. Programs are
+// compiled against the field types declared in evalCtx and cached per
+// formula and environment types.
 func (e *Evaluator) evaluateFormula(ctx context.Context, formula string, evalCtx *EvalContext) (any, error) {
-	if formula == "" {
-		return nil, errors.New("empty formula")
-	}
-
-	// Check formula length to prevent compilation of overly complex expressions
-	if len(formula) > MaxFormulaLength {
-		return nil, fmt.Errorf("%w: formula length %d exceeds limit %d",
-			ErrFormulaComplexity, len(formula), MaxFormulaLength)
-	}
-
-	// Check cache for compiled program
-	var program *vm.Program
-	if cached, ok := e.programCache.Load(formula); ok {
-		program = cached.(*vm.Program)
-		atomic.AddInt32(&evalCtx.metrics.CacheHits, 1)
-	} else {
-		atomic.AddInt32(&evalCtx.metrics.CacheMisses, 1)
-
-		// Compile the expression
-		// Create environment with available functions and data
-		env := make(map[string]any)
-
-		// Add context data
-		evalCtx.mu.RLock()
-		for k, v := range evalCtx.Data {
-			env[k] = v
-		}
-		evalCtx.mu.RUnlock()
-
-		// Compile with type checking
-		compiled, err := expr.Compile(formula, expr.Env(env), expr.AsBool())
-		if err != nil {
-			return nil, fmt.Errorf("formula compilation failed: %w", err)
-		}
-
-		program = compiled
-		e.programCache.Store(formula, program)
-	}
-
-	// Prepare runtime environment
-	env := make(map[string]any)
-	evalCtx.mu.RLock()
-	for k, v := range evalCtx.Data {
-		env[k] = v
-	}
-	evalCtx.mu.RUnlock()
-
-	// Add special variables
-	env["now"] = evalCtx.Now
-
-	// Execute the program with context awareness
-	// Note: expr-lang doesn't natively support context cancellation
-	// We rely on the parent evaluation timeout
-	result, err := expr.Run(program, env)
-	if err != nil {
-		return nil, fmt.Errorf("formula execution failed: %w", err)
-	}
-
-	return result, nil
+	return e.EvaluateFormula(ctx, formula, FieldTypeBoolean, evalCtx)
 }
 
 // evaluateRule evaluates a single condition rule
 func (e *Evaluator) evaluateRule(ctx context.Context, rule *ConditionRule, evalCtx *EvalContext) (bool, error) {
 	atomic.AddInt32(&evalCtx.metrics.RulesEvaluated, 1)
+	t := tracerFromContext(ctx)
+	t.rule(rule)
 
 	// Evaluate formula if present (takes precedence)
 	if rule.If != "" {
@@ -955,6 +915,7 @@ func (e *Evaluator) evaluateRule(ctx context.Context, rule *ConditionRule, evalC
 	if err != nil {
 		return false, fmt.Errorf("left expression: %w", err)
 	}
+	t.left(rule.Left, leftVal)
 
 	// Handle unary operators (don't need right value)
 	if isUnaryOperator(rule.Op) {
@@ -966,6 +927,7 @@ func (e *Evaluator) evaluateRule(ctx context.Context, rule *ConditionRule, evalC
 	if err != nil {
 		return false, fmt.Errorf("right expression: %w", err)
 	}
+	t.right(rule.Right, rightVals)
 
 	return e.applyOperator(ctx, rule.Op, leftVal, rightVals)
 }
@@ -1606,10 +1568,60 @@ func (b *Builder) Build() *ConditionGroup {
 	return b.group
 }
 
-// Validate checks if the built condition is valid
+// NewBuilderFrom wraps an existing condition group so it can be extended
+// and validated
+func NewBuilderFrom(group *ConditionGroup) *Builder {
+	return &Builder{group: group}
+}
+
+// Validate checks the built condition and every nested group and rule
 func (b *Builder) Validate() error {
 	if b.group == nil {
 		return fmt.Errorf("%w: builder has no group", ErrValidation)
 	}
-	return b.group.Validate()
+	return validateTree(b.group, 0)
+}
+
+// validateTree validates a node and its descendants
+func validateTree(node any, depth int) error {
+	if depth > DefaultMaxDepth {
+		return fmt.Errorf("%w: depth %d exceeds limit %d", ErrMaxDepthExceeded, depth, DefaultMaxDepth)
+	}
+
+	switch v := node.(type) {
+	case *ConditionGroup:
+		if err := v.Validate(); err != nil {
+			return fmt.Errorf("group %s: %w", v.ID, err)
+		}
+		for i, child := range v.Children {
+			if err := validateTree(child, depth+1); err != nil {
+				return fmt.Errorf("group %s: child %d: %w", v.ID, i, err)
+			}
+		}
+		return nil
+	case ConditionGroup:
+		return validateTree(&v, depth)
+	case *ConditionRule:
+		if err := v.Validate(); err != nil {
+			return fmt.Errorf("rule %s: %w", v.ID, err)
+		}
+		return nil
+	case ConditionRule:
+		return validateTree(&v, depth)
+	case map[string]any:
+		if _, hasConjunction := v["conjunction"]; hasConjunction {
+			var group ConditionGroup
+			if err := mapToStruct(v, &group); err != nil {
+				return fmt.Errorf("group: invalid structure: %w", err)
+			}
+			return validateTree(&group, depth)
+		}
+		var rule ConditionRule
+		if err := mapToStruct(v, &rule); err != nil {
+			return fmt.Errorf("rule: invalid structure: %w", err)
+		}
+		return validateTree(&rule, depth)
+	default:
+		return fmt.Errorf("%w: unknown node type %T", ErrInvalidExpression, node)
+	}
 }
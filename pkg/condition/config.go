@@ -0,0 +1,131 @@
+package condition
+
+import (
+	"fmt"
+	"slices"
+)
+
+// LookupField returns the field with the given name. Config.Fields may hold
+// Field values, pointers or JSON-decoded maps.
+func (c *Config) LookupField(name string) (Field, bool) {
+	for _, field := range c.FieldList() {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return Field{}, false
+}
+
+// FieldList returns the configured fields in declaration order. Entries
+// that are not fields are skipped.
+func (c *Config) FieldList() []Field {
+	if c == nil {
+		return nil
+	}
+	fields := make([]Field, 0, len(c.Fields))
+	for _, entry := range c.Fields {
+		switch v := entry.(type) {
+		case Field:
+			fields = append(fields, v)
+		case *Field:
+			if v != nil {
+				fields = append(fields, *v)
+			}
+		case map[string]any:
+			var field Field
+			if err := mapToStruct(v, &field); err == nil {
+				fields = append(fields, field)
+			}
+		}
+	}
+	return fields
+}
+
+// FieldOperators returns the operators allowed for a field. Field level
+// operators take precedence over the field type's configuration.
+func (c *Config) FieldOperators(field Field) []OperatorType {
+	if len(field.Operators) > 0 {
+		return field.Operators
+	}
+	if c != nil {
+		if typeConfig, ok := c.Types[string(field.Type)]; ok {
+			return typeConfig.Operators
+		}
+	}
+	return nil
+}
+
+// FieldDefaultOperator returns the operator new rules on a field start with
+func (c *Config) FieldDefaultOperator(field Field) OperatorType {
+	if c != nil {
+		if typeConfig, ok := c.Types[string(field.Type)]; ok && typeConfig.DefaultOp != "" {
+			return typeConfig.DefaultOp
+		}
+	}
+	if ops := c.FieldOperators(field); len(ops) > 0 {
+		return ops[0]
+	}
+	return OpEqual
+}
+
+// FieldValueTypes returns how the right operand of a rule on field may be
+// given, falling back from the field to its type and then the config
+func (c *Config) FieldValueTypes(field Field) []ValueType {
+	if len(field.ValueTypes) > 0 {
+		return field.ValueTypes
+	}
+	if c != nil {
+		if typeConfig, ok := c.Types[string(field.Type)]; ok && len(typeConfig.ValueTypes) > 0 {
+			return typeConfig.ValueTypes
+		}
+		if len(c.ValueTypes) > 0 {
+			return c.ValueTypes
+		}
+	}
+	return []ValueType{ValueTypeValue}
+}
+
+// ValidateRule checks a rule against the configuration: the field must be
+// declared, and the operator and right value type allowed for it. Formula
+// rules are not checked.
+func (c *Config) ValidateRule(rule *ConditionRule) error {
+	if rule.If != "" {
+		return nil
+	}
+	if rule.Left.Type != ValueTypeField {
+		return fmt.Errorf("%w: left side must be a field", ErrValidation)
+	}
+	field, ok := c.LookupField(rule.Left.Field)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrFieldNotFound, rule.Left.Field)
+	}
+	if ops := c.FieldOperators(field); len(ops) > 0 && !slices.Contains(ops, rule.Op) {
+		return fmt.Errorf("%w: %s is not allowed for field %s", ErrInvalidOperator, rule.Op, field.Name)
+	}
+	if isUnaryOperator(rule.Op) {
+		return nil
+	}
+
+	operands, err := ruleOperands(rule.Right)
+	if err != nil {
+		return err
+	}
+	allowed := c.FieldValueTypes(field)
+	for _, operand := range operands {
+		if !slices.Contains(allowed, operand.Type) {
+			return fmt.Errorf("%w: value type %s is not allowed for field %s", ErrValidation, operand.Type, field.Name)
+		}
+		if operand.Type == ValueTypeField {
+			if _, ok := c.LookupField(operand.Field); !ok {
+				return fmt.Errorf("%w: %s", ErrFieldNotFound, operand.Field)
+			}
+		}
+	}
+	return nil
+}
+
+// Operands returns the right side of a rule as expressions, in the forms
+// the Evaluator accepts: a single expression or value, or a list of them
+func (r *ConditionRule) Operands() ([]Expression, error) {
+	return ruleOperands(r.Right)
+}
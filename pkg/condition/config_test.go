@@ -0,0 +1,110 @@
+package condition_test
+
+import (
+	"testing"
+
+	cb "github.com/niiniyare/ruun/pkg/condition"
+	"github.com/stretchr/testify/suite"
+)
+
+// ConfigTestSuite tests field lookup and rule validation against a config
+type ConfigTestSuite struct {
+	suite.Suite
+	config *cb.Config
+}
+
+func (s *ConfigTestSuite) SetupTest() {
+	s.config = &cb.Config{
+		ValueTypes: []cb.ValueType{cb.ValueTypeValue},
+		Fields: []any{
+			cb.Field{Name: "status", Label: "Status", Type: cb.FieldTypeSelect,
+				Values: []cb.SelectOption{{Label: "Open", Value: "open"}, {Label: "Closed", Value: "closed"}}},
+			&cb.Field{Name: "total", Label: "Total", Type: cb.FieldTypeNumber,
+				ValueTypes: []cb.ValueType{cb.ValueTypeValue, cb.ValueTypeField}},
+			map[string]any{"name": "limit", "label": "Limit", "type": "number"},
+			"not a field",
+		},
+		Types: map[string]cb.TypeConfig{
+			"number": {DefaultOp: cb.OpGreater, Operators: []cb.OperatorType{cb.OpEqual, cb.OpGreater, cb.OpBetween}},
+			"select": {Operators: []cb.OperatorType{cb.OpEqual, cb.OpIn}},
+		},
+	}
+}
+
+func (s *ConfigTestSuite) TestFieldList() {
+	fields := s.config.FieldList()
+	s.Require().Len(fields, 3)
+	s.Equal("status", fields[0].Name)
+	s.Equal("total", fields[1].Name)
+	s.Equal(cb.FieldTypeNumber, fields[2].Type)
+
+	field, ok := s.config.LookupField("limit")
+	s.True(ok)
+	s.Equal("Limit", field.Label)
+	_, ok = s.config.LookupField("missing")
+	s.False(ok)
+
+	var nilConfig *cb.Config
+	s.Empty(nilConfig.FieldList())
+}
+
+func (s *ConfigTestSuite) TestFieldOperatorsAndValueTypes() {
+	status, _ := s.config.LookupField("status")
+	total, _ := s.config.LookupField("total")
+	limit, _ := s.config.LookupField("limit")
+
+	s.Equal([]cb.OperatorType{cb.OpEqual, cb.OpIn}, s.config.FieldOperators(status))
+	s.Equal(cb.OpEqual, s.config.FieldDefaultOperator(status))
+	s.Equal(cb.OpGreater, s.config.FieldDefaultOperator(total))
+
+	s.Equal([]cb.ValueType{cb.ValueTypeValue, cb.ValueTypeField}, s.config.FieldValueTypes(total))
+	s.Equal([]cb.ValueType{cb.ValueTypeValue}, s.config.FieldValueTypes(limit))
+}
+
+func (s *ConfigTestSuite) TestValidateRule() {
+	valid := []*cb.ConditionRule{
+		{ID: "r1", Left: cb.Expression{Type: cb.ValueTypeField, Field: "status"}, Op: cb.OpIn, Right: []any{"open", "closed"}},
+		{ID: "r2", Left: cb.Expression{Type: cb.ValueTypeField, Field: "total"}, Op: cb.OpGreater,
+			Right: cb.Expression{Type: cb.ValueTypeField, Field: "limit"}},
+		{ID: "r3", Left: cb.Expression{Type: cb.ValueTypeField, Field: "total"}, Op: cb.OpBetween, Right: []any{10, 100}},
+		{ID: "r4", If: "total > limit"},
+	}
+	for _, rule := range valid {
+		s.NoError(s.config.ValidateRule(rule), rule.ID)
+	}
+
+	s.ErrorIs(s.config.ValidateRule(&cb.ConditionRule{
+		ID: "unknown", Left: cb.Expression{Type: cb.ValueTypeField, Field: "missing"}, Op: cb.OpEqual, Right: 1,
+	}), cb.ErrFieldNotFound)
+	s.ErrorIs(s.config.ValidateRule(&cb.ConditionRule{
+		ID: "operator", Left: cb.Expression{Type: cb.ValueTypeField, Field: "status"}, Op: cb.OpGreater, Right: "open",
+	}), cb.ErrInvalidOperator)
+	s.ErrorIs(s.config.ValidateRule(&cb.ConditionRule{
+		ID: "value_type", Left: cb.Expression{Type: cb.ValueTypeField, Field: "limit"}, Op: cb.OpEqual,
+		Right: cb.Expression{Type: cb.ValueTypeField, Field: "total"},
+	}), cb.ErrValidation)
+	s.ErrorIs(s.config.ValidateRule(&cb.ConditionRule{
+		ID: "right_field", Left: cb.Expression{Type: cb.ValueTypeField, Field: "total"}, Op: cb.OpEqual,
+		Right: cb.Expression{Type: cb.ValueTypeField, Field: "missing"},
+	}), cb.ErrFieldNotFound)
+}
+
+func (s *ConfigTestSuite) TestBuilderValidatesNestedNodes() {
+	root := cb.NewBuilder(cb.ConjunctionAnd).
+		AddRule("status", cb.OpEqual, "open").
+		AddGroup(&cb.ConditionGroup{ID: "inner", Conjunction: cb.ConjunctionOr, Children: []any{
+			&cb.ConditionRule{ID: "broken", Op: cb.OpEqual, Right: 1},
+		}}).
+		Build()
+
+	err := cb.NewBuilderFrom(root).Validate()
+	s.Require().Error(err)
+	s.Contains(err.Error(), "group inner: child 0: rule broken")
+
+	root.Children = root.Children[:1]
+	s.NoError(cb.NewBuilderFrom(root).Validate())
+}
+
+func TestConfigSuite(t *testing.T) {
+	suite.Run(t, new(ConfigTestSuite))
+}
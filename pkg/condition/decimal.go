@@ -0,0 +1,197 @@
+package condition
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ErrDivisionByZero is returned when a decimal is divided by zero
+var ErrDivisionByZero = errors.New("division by zero")
+
+// Decimal is an exact decimal number for currency-safe arithmetic in
+// formulas. Values are immutable; the zero value is 0.
+type Decimal struct {
+	r *big.Rat
+}
+
+// decimalScale is the number of fractional digits kept when a decimal that
+// is not exactly representable (e.g. 1/3) is printed or converted
+const decimalScale = 18
+
+// NewDecimal converts a number, numeric string or Decimal to a Decimal
+func NewDecimal(value any) (Decimal, error) {
+	switch v := value.(type) {
+	case Decimal:
+		return v, nil
+	case *Decimal:
+		if v == nil {
+			return Decimal{}, nil
+		}
+		return *v, nil
+	case string:
+		r, ok := new(big.Rat).SetString(strings.TrimSpace(v))
+		if !ok {
+			return Decimal{}, fmt.Errorf("%w: %q is not a decimal", ErrInvalidExpression, v)
+		}
+		return Decimal{r: r}, nil
+	case float64:
+		// Use the shortest representation so 0.1 stays 0.1
+		return NewDecimal(strconv.FormatFloat(v, 'f', -1, 64))
+	case float32:
+		return NewDecimal(strconv.FormatFloat(float64(v), 'f', -1, 32))
+	case int:
+		return Decimal{r: new(big.Rat).SetInt64(int64(v))}, nil
+	case int64:
+		return Decimal{r: new(big.Rat).SetInt64(v)}, nil
+	case int32:
+		return Decimal{r: new(big.Rat).SetInt64(int64(v))}, nil
+	case uint:
+		return Decimal{r: new(big.Rat).SetUint64(uint64(v))}, nil
+	case uint64:
+		return Decimal{r: new(big.Rat).SetUint64(v)}, nil
+	case uint32:
+		return Decimal{r: new(big.Rat).SetUint64(uint64(v))}, nil
+	case nil:
+		return Decimal{}, nil
+	default:
+		return Decimal{}, fmt.Errorf("%w: cannot convert %T to decimal", ErrInvalidExpression, value)
+	}
+}
+
+// MustDecimal is like NewDecimal but panics on error. Intended for
+// constants and tests.
+func MustDecimal(value any) Decimal {
+	d, err := NewDecimal(value)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func (d Decimal) rat() *big.Rat {
+	if d.r == nil {
+		return new(big.Rat)
+	}
+	return d.r
+}
+
+// Add returns d + o
+func (d Decimal) Add(o Decimal) Decimal {
+	return Decimal{r: new(big.Rat).Add(d.rat(), o.rat())}
+}
+
+// Sub returns d - o
+func (d Decimal) Sub(o Decimal) Decimal {
+	return Decimal{r: new(big.Rat).Sub(d.rat(), o.rat())}
+}
+
+// Mul returns d * o
+func (d Decimal) Mul(o Decimal) Decimal {
+	return Decimal{r: new(big.Rat).Mul(d.rat(), o.rat())}
+}
+
+// Div returns d / o
+func (d Decimal) Div(o Decimal) (Decimal, error) {
+	if o.IsZero() {
+		return Decimal{}, ErrDivisionByZero
+	}
+	return Decimal{r: new(big.Rat).Quo(d.rat(), o.rat())}, nil
+}
+
+// Neg returns -d
+func (d Decimal) Neg() Decimal {
+	return Decimal{r: new(big.Rat).Neg(d.rat())}
+}
+
+// Cmp compares d and o and returns -1, 0 or +1
+func (d Decimal) Cmp(o Decimal) int {
+	return d.rat().Cmp(o.rat())
+}
+
+// Sign returns -1, 0 or +1
+func (d Decimal) Sign() int {
+	return d.rat().Sign()
+}
+
+// IsZero reports whether d is 0
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Round rounds to places fractional digits, halves away from zero
+func (d Decimal) Round(places int) Decimal {
+	return d.round(places, false)
+}
+
+// RoundBank rounds to places fractional digits, halves to even
+func (d Decimal) RoundBank(places int) Decimal {
+	return d.round(places, true)
+}
+
+func (d Decimal) round(places int, halfEven bool) Decimal {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(max(places, 0))), nil)
+	scaled := new(big.Rat).Mul(d.rat(), new(big.Rat).SetInt(scale))
+
+	quotient, remainder := new(big.Int).QuoRem(scaled.Num(), scaled.Denom(), new(big.Int))
+	// Compare 2*|remainder| with the denominator to find the rounding direction
+	twice := new(big.Int).Abs(remainder)
+	twice.Lsh(twice, 1)
+	switch c := twice.Cmp(scaled.Denom()); {
+	case c > 0, c == 0 && (!halfEven || quotient.Bit(0) == 1):
+		if scaled.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+	return Decimal{r: new(big.Rat).SetFrac(quotient, scale)}
+}
+
+// Truncate drops fractional digits beyond places
+func (d Decimal) Truncate(places int) Decimal {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(max(places, 0))), nil)
+	scaled := new(big.Rat).Mul(d.rat(), new(big.Rat).SetInt(scale))
+	quotient := new(big.Int).Quo(scaled.Num(), scaled.Denom())
+	return Decimal{r: new(big.Rat).SetFrac(quotient, scale)}
+}
+
+// Float64 returns the nearest float64
+func (d Decimal) Float64() float64 {
+	f, _ := d.rat().Float64()
+	return f
+}
+
+// String formats the decimal without trailing zeros
+func (d Decimal) String() string {
+	s := d.rat().FloatString(decimalScale)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+// StringFixed formats the decimal with exactly places fractional digits
+func (d Decimal) StringFixed(places int) string {
+	return d.Round(places).rat().FloatString(max(places, 0))
+}
+
+// MarshalJSON encodes the decimal as a JSON number
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON decodes a JSON number or numeric string
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	parsed, err := NewDecimal(strings.Trim(string(data), `"`))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
@@ -0,0 +1,348 @@
+package condition
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// TraceKind identifies the kind of node in an explain tree
+type TraceKind string
+
+const (
+	TraceGroup   TraceKind = "group"
+	TraceRule    TraceKind = "rule"
+	TraceUnknown TraceKind = "unknown"
+)
+
+// TraceValue is an operand as it was resolved during evaluation
+type TraceValue struct {
+	Field string `json:"field,omitempty"`
+	Func  string `json:"func,omitempty"`
+	Value any    `json:"value"`
+}
+
+// TraceNode records the evaluation of one group or rule
+type TraceNode struct {
+	Kind        TraceKind    `json:"kind"`
+	ID          string       `json:"id,omitempty"`
+	Conjunction Conjunction  `json:"conjunction,omitempty"`
+	Not         bool         `json:"not,omitempty"`
+	Formula     string       `json:"formula,omitempty"`
+	Operator    OperatorType `json:"op,omitempty"`
+	Left        *TraceValue  `json:"left,omitempty"`
+	Right       []TraceValue `json:"right,omitempty"`
+	Result      bool         `json:"result"`
+	// Failed is set on every node on the path of an evaluation error,
+	// Error only on the node where it occurred
+	Failed bool   `json:"failed,omitempty"`
+	Error  string `json:"error,omitempty"`
+	// Skipped children were not evaluated because the group short-circuited
+	Skipped      bool `json:"skipped,omitempty"`
+	ShortCircuit bool `json:"shortCircuit,omitempty"`
+	// Decisive marks the last child evaluated, whose result decided the group
+	Decisive bool         `json:"decisive,omitempty"`
+	Children []*TraceNode `json:"children,omitempty"`
+}
+
+// Explanation is the explain tree of one evaluation
+type Explanation struct {
+	Result bool   `json:"result"`
+	Error  string `json:"error,omitempty"`
+	// DecidedBy is the ID of the rule, or formula group, that decided the result
+	DecidedBy string     `json:"decidedBy,omitempty"`
+	Trace     *TraceNode `json:"trace,omitempty"`
+}
+
+// Explain evaluates root like Evaluate and records how the result was
+// reached. The explanation is returned together with any evaluation error.
+func (e *Evaluator) Explain(ctx context.Context, root any, evalCtx *EvalContext) (*Explanation, error) {
+	t := &tracer{}
+	result, err := e.Evaluate(context.WithValue(ctx, tracerKey{}, t), root, evalCtx)
+
+	x := &Explanation{Result: result, Trace: t.root}
+	if err != nil {
+		x.Error = err.Error()
+	}
+	if decisive := x.Decisive(); decisive != nil {
+		x.DecidedBy = decisive.ID
+	}
+	return x, err
+}
+
+// Decisive returns the innermost node that decided the result
+func (x *Explanation) Decisive() *TraceNode {
+	node := x.Trace
+	for node != nil {
+		var next *TraceNode
+		for _, child := range node.Children {
+			if child.Decisive {
+				next = child
+				break
+			}
+		}
+		if next == nil {
+			break
+		}
+		node = next
+	}
+	return node
+}
+
+// JSON renders the explanation as indented JSON
+func (x *Explanation) JSON() ([]byte, error) {
+	return json.MarshalIndent(x, "", "  ")
+}
+
+// String renders the explanation as an indented tree. Nodes on the
+// deciding path are marked with ">".
+func (x *Explanation) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "result: %t", x.Result)
+	if x.DecidedBy != "" {
+		fmt.Fprintf(&b, " (decided by %s)", x.DecidedBy)
+	}
+	if x.Error != "" {
+		fmt.Fprintf(&b, "\nerror: %s", x.Error)
+	}
+	b.WriteByte('\n')
+	if x.Trace != nil {
+		x.Trace.write(&b, 0)
+	}
+	return b.String()
+}
+
+func (n *TraceNode) write(b *strings.Builder, depth int) {
+	b.WriteString(strings.Repeat("  ", depth))
+	if n.Decisive {
+		b.WriteString("> ")
+	} else {
+		b.WriteString("  ")
+	}
+
+	switch {
+	case n.Skipped:
+		b.WriteString("[skipped] ")
+	case n.Failed:
+		b.WriteString("[error] ")
+	default:
+		fmt.Fprintf(b, "[%t] ", n.Result)
+	}
+
+	b.WriteString(string(n.Kind))
+	if n.ID != "" {
+		b.WriteString(" " + n.ID)
+	}
+	if !n.Skipped {
+		switch n.Kind {
+		case TraceGroup:
+			if n.Conjunction != "" {
+				b.WriteString(" (" + strings.ToUpper(string(n.Conjunction)) + ")")
+			}
+			if n.Not {
+				b.WriteString(" NOT")
+			}
+			if n.Formula != "" {
+				b.WriteString(" if " + n.Formula)
+			}
+			if n.ShortCircuit {
+				b.WriteString(" short-circuit")
+			}
+		case TraceRule:
+			if n.Formula != "" {
+				b.WriteString(": if " + n.Formula)
+			} else if n.Left != nil {
+				b.WriteString(": " + n.Left.String() + " " + string(n.Operator))
+				if len(n.Right) > 0 {
+					operands := make([]string, len(n.Right))
+					for i, operand := range n.Right {
+						operands[i] = operand.String()
+					}
+					b.WriteString(" " + strings.Join(operands, ", "))
+				}
+			}
+		}
+	}
+	if n.Error != "" {
+		b.WriteString(": " + n.Error)
+	}
+	b.WriteByte('\n')
+
+	for _, child := range n.Children {
+		child.write(b, depth+1)
+	}
+}
+
+// String renders the operand as field=value, func()=value or value
+func (v TraceValue) String() string {
+	value := formatTraceValue(v.Value)
+	switch {
+	case v.Field != "":
+		return v.Field + "=" + value
+	case v.Func != "":
+		return v.Func + "()=" + value
+	}
+	return value
+}
+
+func formatTraceValue(v any) string {
+	if data, err := json.Marshal(v); err == nil {
+		return string(data)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+type tracerKey struct{}
+
+// tracer builds the explain tree while the Evaluator walks a condition.
+// Evaluation of one tree is sequential, so it needs no locking; a nil
+// tracer ignores every call.
+type tracer struct {
+	root  *TraceNode
+	stack []*TraceNode
+}
+
+func tracerFromContext(ctx context.Context) *tracer {
+	t, _ := ctx.Value(tracerKey{}).(*tracer)
+	return t
+}
+
+func (t *tracer) current() *TraceNode {
+	if t == nil || len(t.stack) == 0 {
+		return nil
+	}
+	return t.stack[len(t.stack)-1]
+}
+
+func (t *tracer) enter(node any) {
+	n := newTraceNode(node)
+	if parent := t.current(); parent != nil {
+		parent.Children = append(parent.Children, n)
+	} else if t.root == nil {
+		t.root = n
+	}
+	t.stack = append(t.stack, n)
+}
+
+func (t *tracer) exit(result bool, err error) {
+	n := t.current()
+	if n == nil {
+		return
+	}
+	t.stack = t.stack[:len(t.stack)-1]
+
+	n.Result = result
+	if err != nil {
+		n.Failed = true
+		childFailed := false
+		for _, child := range n.Children {
+			childFailed = childFailed || child.Failed
+		}
+		if !childFailed {
+			n.Error = err.Error()
+		}
+	}
+	for i := len(n.Children) - 1; i >= 0; i-- {
+		if !n.Children[i].Skipped {
+			n.Children[i].Decisive = true
+			break
+		}
+	}
+}
+
+func (t *tracer) group(group *ConditionGroup) {
+	n := t.current()
+	if n == nil {
+		return
+	}
+	n.Kind = TraceGroup
+	n.ID = group.ID
+	n.Conjunction = group.Conjunction
+	n.Not = group.Not
+	n.Formula = group.If
+}
+
+func (t *tracer) rule(rule *ConditionRule) {
+	n := t.current()
+	if n == nil {
+		return
+	}
+	n.Kind = TraceRule
+	n.ID = rule.ID
+	n.Formula = rule.If
+	n.Operator = rule.Op
+}
+
+func (t *tracer) left(expr Expression, value any) {
+	n := t.current()
+	if n == nil {
+		return
+	}
+	n.Left = traceValue(expr, value)
+}
+
+func (t *tracer) right(right any, values []any) {
+	n := t.current()
+	if n == nil {
+		return
+	}
+	operands, err := ruleOperands(right)
+	aligned := err == nil && len(operands) == len(values)
+	n.Right = make([]TraceValue, len(values))
+	for i, value := range values {
+		if aligned {
+			n.Right[i] = *traceValue(operands[i], value)
+		} else {
+			n.Right[i] = TraceValue{Value: value}
+		}
+	}
+}
+
+// skip records the children a group did not evaluate
+func (t *tracer) skip(children []any) {
+	n := t.current()
+	if n == nil || len(children) == 0 {
+		return
+	}
+	n.ShortCircuit = true
+	for _, child := range children {
+		skipped := newTraceNode(child)
+		skipped.Skipped = true
+		n.Children = append(n.Children, skipped)
+	}
+}
+
+func traceValue(expr Expression, value any) *TraceValue {
+	v := &TraceValue{Value: value}
+	switch expr.Type {
+	case ValueTypeField:
+		v.Field = expr.Field
+	case ValueTypeFunc:
+		if expr.Func != nil {
+			v.Func = expr.Func.Type
+		}
+	}
+	return v
+}
+
+// newTraceNode identifies a node before it is validated
+func newTraceNode(node any) *TraceNode {
+	switch v := node.(type) {
+	case *ConditionGroup:
+		return &TraceNode{Kind: TraceGroup, ID: v.ID}
+	case ConditionGroup:
+		return &TraceNode{Kind: TraceGroup, ID: v.ID}
+	case *ConditionRule:
+		return &TraceNode{Kind: TraceRule, ID: v.ID}
+	case ConditionRule:
+		return &TraceNode{Kind: TraceRule, ID: v.ID}
+	case map[string]any:
+		id, _ := v["id"].(string)
+		if _, hasConjunction := v["conjunction"]; hasConjunction {
+			return &TraceNode{Kind: TraceGroup, ID: id}
+		}
+		return &TraceNode{Kind: TraceRule, ID: id}
+	}
+	return &TraceNode{Kind: TraceUnknown}
+}
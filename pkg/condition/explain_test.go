@@ -0,0 +1,150 @@
+package condition_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	cb "github.com/niiniyare/ruun/pkg/condition"
+	"github.com/stretchr/testify/suite"
+)
+
+// ExplainTestSuite tests evaluation traces
+type ExplainTestSuite struct {
+	suite.Suite
+	ctx       context.Context
+	evaluator *cb.Evaluator
+}
+
+func (s *ExplainTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.evaluator = cb.NewEvaluator(&cb.Config{}, cb.DefaultEvalOptions())
+}
+
+func (s *ExplainTestSuite) explain(root any, data map[string]any) (*cb.Explanation, error) {
+	return s.evaluator.Explain(s.ctx, root, cb.NewEvalContext(data, cb.DefaultEvalOptions()))
+}
+
+func (s *ExplainTestSuite) TestShortCircuit() {
+	root := &cb.ConditionGroup{
+		ID:          "visible",
+		Conjunction: cb.ConjunctionAnd,
+		Children: []any{
+			&cb.ConditionRule{ID: "is_open", Left: cb.Expression{Type: cb.ValueTypeField, Field: "status"}, Op: cb.OpEqual, Right: "open"},
+			&cb.ConditionRule{ID: "over_limit", Left: cb.Expression{Type: cb.ValueTypeField, Field: "total"}, Op: cb.OpGreater,
+				Right: cb.Expression{Type: cb.ValueTypeField, Field: "limit"}},
+			&cb.ConditionRule{ID: "is_vip", Left: cb.Expression{Type: cb.ValueTypeField, Field: "tier"}, Op: cb.OpIn, Right: []any{"gold", "platinum"}},
+		},
+	}
+
+	x, err := s.explain(root, map[string]any{"status": "open", "total": 50, "limit": 100, "tier": "gold"})
+	s.Require().NoError(err)
+	s.False(x.Result)
+	s.Equal("over_limit", x.DecidedBy)
+
+	trace := x.Trace
+	s.Require().NotNil(trace)
+	s.Equal(cb.TraceGroup, trace.Kind)
+	s.True(trace.ShortCircuit)
+	s.Require().Len(trace.Children, 3)
+
+	first, second, third := trace.Children[0], trace.Children[1], trace.Children[2]
+	s.True(first.Result)
+	s.Equal(&cb.TraceValue{Field: "status", Value: "open"}, first.Left)
+	s.False(first.Decisive)
+
+	s.False(second.Result)
+	s.True(second.Decisive)
+	s.Equal(cb.OpGreater, second.Operator)
+	s.Equal([]cb.TraceValue{{Field: "limit", Value: 100}}, second.Right)
+
+	s.True(third.Skipped)
+	s.Equal("is_vip", third.ID)
+	s.Nil(third.Left)
+
+	text := x.String()
+	s.Contains(text, "result: false (decided by over_limit)")
+	s.Contains(text, "  [false] group visible (AND) short-circuit\n")
+	s.Contains(text, `    [true] rule is_open: status="open" equal "open"`)
+	s.Contains(text, `  > [false] rule over_limit: total=50 greater limit=100`)
+	s.Contains(text, "    [skipped] rule is_vip\n")
+}
+
+func (s *ExplainTestSuite) TestNestedFormulaAndNot() {
+	root := cb.NewBuilder(cb.ConjunctionOr).
+		AddFormula("qty * price > 100").
+		AddGroup(&cb.ConditionGroup{ID: "archived", Conjunction: cb.ConjunctionAnd, Not: true, If: "archived"}).
+		Build()
+
+	x, err := s.explain(root, map[string]any{"qty": 2, "price": 10, "archived": false})
+	s.Require().NoError(err)
+	s.True(x.Result)
+	s.Equal("archived", x.DecidedBy)
+	s.Equal("qty * price > 100", x.Trace.Children[0].Formula)
+	s.False(x.Trace.Children[0].Result)
+	s.True(x.Trace.Children[1].Not)
+	s.Contains(x.String(), "> [true] group archived (AND) NOT if archived")
+}
+
+func (s *ExplainTestSuite) TestError() {
+	root := cb.NewBuilder(cb.ConjunctionAnd).
+		AddRule("status", cb.OpEqual, "open").
+		AddRule("missing", cb.OpEqual, 1).
+		Build()
+
+	x, err := s.explain(root, map[string]any{"status": "open"})
+	s.Require().Error(err)
+	s.Require().NotNil(x)
+	s.NotEmpty(x.Error)
+
+	s.True(x.Trace.Failed)
+	s.Empty(x.Trace.Error, "the error is reported where it occurred")
+	failed := x.Decisive()
+	s.True(failed.Failed)
+	s.Contains(failed.Error, "missing")
+	s.Contains(x.String(), "[error] rule ")
+}
+
+func (s *ExplainTestSuite) TestJSON() {
+	var root map[string]any
+	s.Require().NoError(json.Unmarshal([]byte(`{
+		"id": "root",
+		"conjunction": "or",
+		"children": [
+			{"id": "r1", "left": {"type": "field", "field": "country"}, "op": "equal", "right": "US"},
+			{"id": "r2", "left": {"type": "field", "field": "country"}, "op": "equal", "right": "CA"}
+		]
+	}`), &root))
+
+	x, err := s.explain(root, map[string]any{"country": "US"})
+	s.Require().NoError(err)
+
+	data, err := x.JSON()
+	s.Require().NoError(err)
+	var decoded map[string]any
+	s.Require().NoError(json.Unmarshal(data, &decoded))
+	s.Equal(true, decoded["result"])
+	s.Equal("r1", decoded["decidedBy"])
+
+	trace := decoded["trace"].(map[string]any)
+	s.Equal("group", trace["kind"])
+	children := trace["children"].([]any)
+	s.Require().Len(children, 2)
+	s.Equal(map[string]any{"field": "country", "value": "US"}, children[0].(map[string]any)["left"])
+	s.Equal(true, children[1].(map[string]any)["skipped"])
+}
+
+func (s *ExplainTestSuite) TestEvaluateUnaffected() {
+	root := cb.NewBuilder(cb.ConjunctionAnd).AddRule("status", cb.OpEqual, "open").Build()
+	evalCtx := cb.NewEvalContext(map[string]any{"status": "open"}, cb.DefaultEvalOptions())
+
+	result, err := s.evaluator.Evaluate(s.ctx, root, evalCtx)
+	s.Require().NoError(err)
+	x, err := s.explain(root, map[string]any{"status": "open"})
+	s.Require().NoError(err)
+	s.Equal(result, x.Result)
+}
+
+func TestExplainSuite(t *testing.T) {
+	suite.Run(t, new(ExplainTestSuite))
+}
@@ -0,0 +1,492 @@
+package condition
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/file"
+	"github.com/expr-lang/expr/vm"
+)
+
+// FieldTypeDecimal is an exact decimal field, used for currency amounts
+const FieldTypeDecimal FieldType = "decimal"
+
+// ErrFormulaType is returned when a formula produces a value of the wrong type
+var ErrFormulaType = errors.New("formula type mismatch")
+
+// FormulaError describes a formula that failed to compile or evaluate.
+// Field names the field the formula belongs to, when known.
+type FormulaError struct {
+	Field   string `json:"field,omitempty"`
+	Formula string `json:"formula"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+	Err     error  `json:"-"`
+}
+
+// Error implements error
+func (e *FormulaError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("formula")
+	if e.Field != "" {
+		fmt.Fprintf(&sb, " of field %s", e.Field)
+	}
+	if e.Line > 0 {
+		fmt.Fprintf(&sb, " at %d:%d", e.Line, e.Column)
+	}
+	sb.WriteString(": ")
+	sb.WriteString(e.Message)
+	return sb.String()
+}
+
+// Unwrap returns the underlying error
+func (e *FormulaError) Unwrap() error {
+	return e.Err
+}
+
+// newFormulaError wraps a compile error, keeping the position reported by
+// expr-lang
+func newFormulaError(formula string, err error, kind error) *FormulaError {
+	fe := &FormulaError{Formula: formula, Message: err.Error(), Err: kind}
+	var exprErr *file.Error
+	if errors.As(err, &exprErr) {
+		fe.Line = exprErr.Line
+		fe.Column = exprErr.Column + 1
+		fe.Message = exprErr.Message
+	}
+	return fe
+}
+
+// Formula is a compiled formula that produces a value of ResultType.
+// Formulas are compiled against declared field types, so type errors such
+// as adding a date to a string are reported at compile time.
+type Formula struct {
+	Source     string
+	ResultType FieldType // empty accepts any result
+
+	program *vm.Program
+	fields  map[string]Field
+}
+
+// CompileFormula compiles a formula against the declared fields. Values
+// in data that are not declared fields are typed from their current value.
+func (e *Evaluator) CompileFormula(formula string, resultType FieldType, fields map[string]Field, data map[string]any) (*Formula, error) {
+	compiled, _, err := e.compileFormula(formula, resultType, fields, data)
+	return compiled, err
+}
+
+func (e *Evaluator) compileFormula(formula string, resultType FieldType, fields map[string]Field, data map[string]any) (*Formula, bool, error) {
+	if formula == "" {
+		return nil, false, &FormulaError{Formula: formula, Message: "empty formula", Err: ErrInvalidExpression}
+	}
+	if len(formula) > MaxFormulaLength {
+		return nil, false, &FormulaError{
+			Formula: formula,
+			Message: fmt.Sprintf("formula length %d exceeds limit %d", len(formula), MaxFormulaLength),
+			Err:     ErrFormulaComplexity,
+		}
+	}
+
+	env := formulaTypeEnv(fields, data)
+	key := string(resultType) + "\x00" + formulaEnvSignature(env) + "\x00" + formula
+	if cached, ok := e.programCache.Load(key); ok {
+		return &Formula{Source: formula, ResultType: resultType, program: cached.(*vm.Program), fields: fields}, true, nil
+	}
+
+	options := append(formulaOptions(), expr.Env(env))
+	if resultType == FieldTypeBoolean {
+		options = append(options, expr.AsBool())
+	}
+	program, err := expr.Compile(formula, options...)
+	if err != nil {
+		return nil, false, newFormulaError(formula, err, ErrInvalidExpression)
+	}
+	if out := program.Node().Type(); !formulaResultAssignable(out, resultType) {
+		return nil, false, &FormulaError{
+			Formula: formula,
+			Message: fmt.Sprintf("formula returns %s, expected %s", out, resultType),
+			Err:     ErrFormulaType,
+		}
+	}
+
+	e.programCache.Store(key, program)
+	return &Formula{Source: formula, ResultType: resultType, program: program, fields: fields}, false, nil
+}
+
+// ValidateFormula compiles a formula against the declared fields and
+// reports the error, if any, for field
+func (e *Evaluator) ValidateFormula(field, formula string, resultType FieldType, fields map[string]Field) error {
+	if _, err := e.CompileFormula(formula, resultType, fields, nil); err != nil {
+		var fe *FormulaError
+		if errors.As(err, &fe) {
+			fe.Field = field
+		}
+		return err
+	}
+	return nil
+}
+
+// EvaluateFormula compiles (or reuses) and runs a formula against the
+// fields declared in evalCtx and returns a value of resultType
+func (e *Evaluator) EvaluateFormula(ctx context.Context, formula string, resultType FieldType, evalCtx *EvalContext) (any, error) {
+	if evalCtx == nil {
+		return nil, fmt.Errorf("%w: nil evaluation context", ErrInvalidExpression)
+	}
+
+	evalCtx.mu.RLock()
+	fields := make(map[string]Field, len(evalCtx.Fields))
+	for name, field := range evalCtx.Fields {
+		fields[name] = field
+	}
+	data := make(map[string]any, len(evalCtx.Data)+len(evalCtx.Functions))
+	for k, v := range evalCtx.Data {
+		data[k] = v
+	}
+	for name := range evalCtx.Functions {
+		data[name] = formulaFuncPlaceholder
+	}
+	evalCtx.mu.RUnlock()
+
+	compiled, cached, err := e.compileFormula(formula, resultType, fields, data)
+	if cached {
+		atomic.AddInt32(&evalCtx.metrics.CacheHits, 1)
+	} else {
+		atomic.AddInt32(&evalCtx.metrics.CacheMisses, 1)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return compiled.Eval(ctx, evalCtx)
+}
+
+// Eval runs the formula with values from evalCtx, converted to their
+// declared field types
+func (f *Formula) Eval(ctx context.Context, evalCtx *EvalContext) (any, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	env, err := formulaRuntimeEnv(ctx, f.fields, evalCtx)
+	if err != nil {
+		return nil, &FormulaError{Formula: f.Source, Message: err.Error(), Err: ErrFormulaType}
+	}
+
+	result, err := expr.Run(f.program, env)
+	if err != nil {
+		return nil, &FormulaError{Formula: f.Source, Message: err.Error(), Err: ErrInvalidExpression}
+	}
+	converted, err := convertFormulaResult(result, f.ResultType)
+	if err != nil {
+		return nil, &FormulaError{Formula: f.Source, Message: err.Error(), Err: ErrFormulaType}
+	}
+	return converted, nil
+}
+
+// formulaVariables are available to every formula
+var formulaVariables = map[string]any{
+	"now":   time.Time{},
+	"today": time.Time{},
+}
+
+// formulaFuncPlaceholder gives functions registered on an EvalContext
+// their type at compile time; the real handlers are bound at run time
+var formulaFuncPlaceholder = func(args ...any) (any, error) { return nil, nil }
+
+// formulaTypeEnv builds the compile-time environment: zero values of the
+// declared field types, the current value of undeclared data, and custom
+// functions registered on the evaluation context
+func formulaTypeEnv(fields map[string]Field, data map[string]any) map[string]any {
+	env := make(map[string]any, len(fields)+len(data)+len(formulaVariables))
+	for k, v := range data {
+		env[k] = v
+	}
+	for k, v := range formulaVariables {
+		env[k] = v
+	}
+	for name, field := range fields {
+		setFormulaPath(env, fieldName(name, field), formulaZeroValue(field.Type))
+	}
+	return env
+}
+
+// formulaRuntimeEnv builds the run-time environment from evalCtx
+func formulaRuntimeEnv(ctx context.Context, fields map[string]Field, evalCtx *EvalContext) (map[string]any, error) {
+	evalCtx.mu.RLock()
+	env := make(map[string]any, len(evalCtx.Data)+len(evalCtx.Functions)+len(formulaVariables))
+	for k, v := range evalCtx.Data {
+		env[k] = v
+	}
+	functions := make(map[string]FuncHandler, len(evalCtx.Functions))
+	for name, handler := range evalCtx.Functions {
+		functions[name] = handler
+	}
+	evalCtx.mu.RUnlock()
+
+	env["now"] = evalCtx.Now
+	env["today"] = time.Date(evalCtx.Now.Year(), evalCtx.Now.Month(), evalCtx.Now.Day(), 0, 0, 0, 0, evalCtx.Now.Location())
+
+	for name, field := range fields {
+		path := fieldName(name, field)
+		value, err := evalCtx.GetValue(path)
+		if err != nil && !errors.Is(err, ErrFieldNotFound) {
+			return nil, fmt.Errorf("field %s: %w", path, err)
+		}
+		converted, err := coerceFormulaValue(value, field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", path, err)
+		}
+		setFormulaPath(env, path, converted)
+	}
+
+	for name, handler := range functions {
+		env[name] = func(args ...any) (any, error) {
+			return handler(ctx, args, evalCtx)
+		}
+	}
+	return env, nil
+}
+
+func fieldName(key string, field Field) string {
+	if field.Name != "" {
+		return field.Name
+	}
+	return key
+}
+
+// setFormulaPath sets a dotted path, creating nested maps as needed
+func setFormulaPath(env map[string]any, path string, value any) {
+	parts := strings.Split(path, ".")
+	current := env
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := current[part].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+		} else {
+			// Copy so data maps owned by the caller are not modified
+			copied := make(map[string]any, len(next))
+			for k, v := range next {
+				copied[k] = v
+			}
+			next = copied
+		}
+		current[part] = next
+		current = next
+	}
+	current[parts[len(parts)-1]] = value
+}
+
+// formulaZeroValue returns a value of the Go type a field type maps to
+func formulaZeroValue(fieldType FieldType) any {
+	switch fieldType {
+	case FieldTypeText:
+		return ""
+	case FieldTypeNumber:
+		return float64(0)
+	case FieldTypeDecimal:
+		return Decimal{}
+	case FieldTypeBoolean:
+		return false
+	case FieldTypeDate, FieldTypeDateTime, FieldTypeTime:
+		return time.Time{}
+	default:
+		// Select values and unknown types are checked at run time
+		return nil
+	}
+}
+
+// formulaEnvSignature identifies the environment types for the program
+// cache, so a formula compiled for one set of types is not reused with
+// another
+func formulaEnvSignature(env map[string]any) string {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	var sb strings.Builder
+	for _, k := range keys {
+		sb.WriteString(k)
+		sb.WriteByte(':')
+		switch v := env[k].(type) {
+		case nil:
+			sb.WriteString("any")
+		case map[string]any:
+			sb.WriteByte('{')
+			sb.WriteString(formulaEnvSignature(v))
+			sb.WriteByte('}')
+		default:
+			sb.WriteString(reflect.TypeOf(v).String())
+		}
+		sb.WriteByte(';')
+	}
+	return sb.String()
+}
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	decimalType = reflect.TypeOf(Decimal{})
+)
+
+// formulaResultAssignable reports whether a compiled result type can
+// produce a value of resultType. Interface results are checked at run time.
+func formulaResultAssignable(t reflect.Type, resultType FieldType) bool {
+	if t == nil || t.Kind() == reflect.Interface || resultType == "" {
+		return true
+	}
+	switch resultType {
+	case FieldTypeBoolean:
+		return t.Kind() == reflect.Bool
+	case FieldTypeNumber, FieldTypeDecimal:
+		return t == decimalType || isNumericKind(t.Kind())
+	case FieldTypeText:
+		return t.Kind() == reflect.String || t == decimalType
+	case FieldTypeDate, FieldTypeDateTime, FieldTypeTime:
+		return t == timeType
+	default:
+		return true
+	}
+}
+
+func isNumericKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// convertFormulaResult converts a formula result to the Go type of
+// resultType
+func convertFormulaResult(value any, resultType FieldType) (any, error) {
+	if resultType == "" || value == nil {
+		return value, nil
+	}
+	switch resultType {
+	case FieldTypeBoolean:
+		if b, ok := value.(bool); ok {
+			return b, nil
+		}
+	case FieldTypeNumber:
+		if d, ok := value.(Decimal); ok {
+			return d.Float64(), nil
+		}
+		if f, ok := toNumber(value); ok {
+			return f, nil
+		}
+	case FieldTypeDecimal:
+		if d, err := NewDecimal(value); err == nil {
+			return d, nil
+		}
+	case FieldTypeText:
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case Decimal:
+			return v.String(), nil
+		}
+	case FieldTypeDate, FieldTypeDateTime, FieldTypeTime:
+		if t, ok := value.(time.Time); ok {
+			return t, nil
+		}
+	default:
+		return value, nil
+	}
+	return nil, fmt.Errorf("%w: formula returned %T, expected %s", ErrFormulaType, value, resultType)
+}
+
+// coerceFormulaValue converts a data value to the Go type of a field type.
+// Missing values become the zero value of the type.
+func coerceFormulaValue(value any, fieldType FieldType) (any, error) {
+	if value == nil {
+		return formulaZeroValue(fieldType), nil
+	}
+	switch fieldType {
+	case FieldTypeText:
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case fmt.Stringer:
+			return v.String(), nil
+		default:
+			return fmt.Sprint(v), nil
+		}
+	case FieldTypeNumber:
+		switch v := value.(type) {
+		case Decimal:
+			return v.Float64(), nil
+		case string:
+			if strings.TrimSpace(v) == "" {
+				return float64(0), nil
+			}
+			f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q is not a number", ErrFormulaType, v)
+			}
+			return f, nil
+		}
+		if f, ok := toNumber(value); ok {
+			return f, nil
+		}
+	case FieldTypeDecimal:
+		if s, ok := value.(string); ok && strings.TrimSpace(s) == "" {
+			return Decimal{}, nil
+		}
+		d, err := NewDecimal(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrFormulaType, err)
+		}
+		return d, nil
+	case FieldTypeBoolean:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q is not a boolean", ErrFormulaType, v)
+			}
+			return b, nil
+		}
+	case FieldTypeDate, FieldTypeDateTime, FieldTypeTime:
+		if s, ok := value.(string); ok && s == "" {
+			return time.Time{}, nil
+		}
+		if t, ok := parseFormulaTime(value); ok {
+			return t, nil
+		}
+	default:
+		return value, nil
+	}
+	return nil, fmt.Errorf("%w: cannot convert %T to %s", ErrFormulaType, value, fieldType)
+}
+
+// toNumber converts Go numeric types to float64
+func toNumber(value any) (float64, bool) {
+	rv := reflect.ValueOf(value)
+	switch {
+	case rv.CanInt():
+		return float64(rv.Int()), true
+	case rv.CanUint():
+		return float64(rv.Uint()), true
+	case rv.CanFloat():
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+// parseFormulaTime accepts time.Time and the formats Evaluator.toTime
+// understands
+func parseFormulaTime(value any) (time.Time, bool) {
+	return (&Evaluator{}).toTime(value)
+}
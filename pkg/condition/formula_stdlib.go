@@ -0,0 +1,338 @@
+package condition
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/expr-lang/expr"
+
+	"github.com/niiniyare/ruun/pkg/shared"
+)
+
+// Formula standard library
+//
+// Date math:      addDays, addMonths, addYears, daysBetween, startOfMonth,
+//                 endOfMonth, startOfYear, endOfYear, startOfWeek, quarter,
+//                 age, makeDate, parseDate, formatDate
+// Business days:  addBusinessDays, subBusinessDays, businessDaysBetween,
+//                 isBusinessDay, isWeekend, nextBusinessDay, previousBusinessDay
+// Rounding:       roundTo, roundHalfEven, ceilTo, floorTo, truncTo
+// Decimals:       decimal, money, toNumber; + - * / and comparisons are
+//                 overloaded so decimals mix with numbers exactly
+// Strings:        left, right, mid, padLeft, padRight, formatNumber, isBlank
+//
+// The expr-lang builtins (upper, lower, trim, split, join, replace, len,
+// abs, min, max, ...) are also available.
+
+// formulaOptions returns the expr options that install the standard library
+func formulaOptions() []expr.Option {
+	return []expr.Option{
+		// Date math
+		expr.Function("addDays", func(params ...any) (any, error) {
+			return params[0].(time.Time).AddDate(0, 0, formulaInt(params[1])), nil
+		}, new(func(time.Time, int) time.Time), new(func(time.Time, float64) time.Time)),
+		expr.Function("addMonths", func(params ...any) (any, error) {
+			return addMonthsClamped(params[0].(time.Time), formulaInt(params[1])), nil
+		}, new(func(time.Time, int) time.Time), new(func(time.Time, float64) time.Time)),
+		expr.Function("addYears", func(params ...any) (any, error) {
+			return addMonthsClamped(params[0].(time.Time), 12*formulaInt(params[1])), nil
+		}, new(func(time.Time, int) time.Time), new(func(time.Time, float64) time.Time)),
+		expr.Function("daysBetween", func(params ...any) (any, error) {
+			return shared.DaysBetween(params[0].(time.Time), params[1].(time.Time)), nil
+		}, new(func(time.Time, time.Time) int)),
+		expr.Function("startOfMonth", timeFunc(shared.StartOfMonth), new(func(time.Time) time.Time)),
+		expr.Function("endOfMonth", timeFunc(shared.EndOfMonth), new(func(time.Time) time.Time)),
+		expr.Function("startOfYear", timeFunc(shared.StartOfYear), new(func(time.Time) time.Time)),
+		expr.Function("endOfYear", timeFunc(shared.EndOfYear), new(func(time.Time) time.Time)),
+		expr.Function("startOfWeek", timeFunc(shared.StartOfWeek), new(func(time.Time) time.Time)),
+		expr.Function("quarter", func(params ...any) (any, error) {
+			return shared.GetQuarter(params[0].(time.Time)), nil
+		}, new(func(time.Time) int)),
+		expr.Function("age", func(params ...any) (any, error) {
+			return shared.Age(params[0].(time.Time)), nil
+		}, new(func(time.Time) int)),
+		expr.Function("makeDate", func(params ...any) (any, error) {
+			return time.Date(formulaInt(params[0]), time.Month(formulaInt(params[1])), formulaInt(params[2]), 0, 0, 0, 0, time.UTC), nil
+		}, new(func(int, int, int) time.Time), new(func(float64, float64, float64) time.Time)),
+		expr.Function("parseDate", func(params ...any) (any, error) {
+			if t, ok := parseFormulaTime(params[0]); ok {
+				return t, nil
+			}
+			return nil, fmt.Errorf("%w: cannot parse date %q", ErrInvalidExpression, params[0])
+		}, new(func(string) time.Time)),
+		expr.Function("formatDate", func(params ...any) (any, error) {
+			layout := params[1].(string)
+			if preset, ok := shared.GetFormatPreset(layout); ok {
+				layout = preset
+			}
+			return params[0].(time.Time).Format(layout), nil
+		}, new(func(time.Time, string) string)),
+
+		// Business days
+		expr.Function("addBusinessDays", func(params ...any) (any, error) {
+			return shared.AddBusinessDays(params[0].(time.Time), formulaInt(params[1])), nil
+		}, new(func(time.Time, int) time.Time), new(func(time.Time, float64) time.Time)),
+		expr.Function("subBusinessDays", func(params ...any) (any, error) {
+			return shared.SubBusinessDays(params[0].(time.Time), formulaInt(params[1])), nil
+		}, new(func(time.Time, int) time.Time), new(func(time.Time, float64) time.Time)),
+		expr.Function("businessDaysBetween", func(params ...any) (any, error) {
+			return shared.BusinessDaysBetween(params[0].(time.Time), params[1].(time.Time)), nil
+		}, new(func(time.Time, time.Time) int)),
+		expr.Function("isBusinessDay", func(params ...any) (any, error) {
+			return shared.IsBusinessDay(params[0].(time.Time)), nil
+		}, new(func(time.Time) bool)),
+		expr.Function("isWeekend", func(params ...any) (any, error) {
+			return shared.IsWeekend(params[0].(time.Time)), nil
+		}, new(func(time.Time) bool)),
+		expr.Function("nextBusinessDay", timeFunc(shared.NextBusinessDay), new(func(time.Time) time.Time)),
+		expr.Function("previousBusinessDay", timeFunc(shared.PreviousBusinessDay), new(func(time.Time) time.Time)),
+
+		// Rounding
+		expr.Function("roundTo", roundFunc(Decimal.Round, func(f float64) float64 { return math.Round(f) }),
+			new(func(float64, int) float64), new(func(Decimal, int) Decimal)),
+		expr.Function("roundHalfEven", roundFunc(Decimal.RoundBank, math.RoundToEven),
+			new(func(float64, int) float64), new(func(Decimal, int) Decimal)),
+		expr.Function("ceilTo", roundFunc(decimalCeil, math.Ceil),
+			new(func(float64, int) float64), new(func(Decimal, int) Decimal)),
+		expr.Function("floorTo", roundFunc(decimalFloor, math.Floor),
+			new(func(float64, int) float64), new(func(Decimal, int) Decimal)),
+		expr.Function("truncTo", roundFunc(Decimal.Truncate, math.Trunc),
+			new(func(float64, int) float64), new(func(Decimal, int) Decimal)),
+
+		// Decimals
+		expr.Function("decimal", func(params ...any) (any, error) {
+			return NewDecimal(params[0])
+		}, new(func(float64) Decimal), new(func(int) Decimal), new(func(string) Decimal), new(func(Decimal) Decimal)),
+		expr.Function("money", func(params ...any) (any, error) {
+			d, err := NewDecimal(params[0])
+			if err != nil {
+				return nil, err
+			}
+			return d.Round(2), nil
+		}, new(func(float64) Decimal), new(func(int) Decimal), new(func(string) Decimal), new(func(Decimal) Decimal)),
+		expr.Function("toNumber", func(params ...any) (any, error) {
+			switch v := params[0].(type) {
+			case Decimal:
+				return v.Float64(), nil
+			case string:
+				return strconv.ParseFloat(strings.TrimSpace(v), 64)
+			}
+			f, _ := toNumber(params[0])
+			return f, nil
+		}, new(func(Decimal) float64), new(func(string) float64), new(func(int) float64), new(func(float64) float64)),
+		decimalOperator("decimalAdd", Decimal.Add),
+		decimalOperator("decimalSub", Decimal.Sub),
+		decimalOperator("decimalMul", Decimal.Mul),
+		decimalOperator("decimalDiv", nil),
+		decimalComparison("decimalEq", func(c int) bool { return c == 0 }),
+		decimalComparison("decimalNe", func(c int) bool { return c != 0 }),
+		decimalComparison("decimalLt", func(c int) bool { return c < 0 }),
+		decimalComparison("decimalLe", func(c int) bool { return c <= 0 }),
+		decimalComparison("decimalGt", func(c int) bool { return c > 0 }),
+		decimalComparison("decimalGe", func(c int) bool { return c >= 0 }),
+		expr.Operator("+", "decimalAdd"),
+		expr.Operator("-", "decimalSub"),
+		expr.Operator("*", "decimalMul"),
+		expr.Operator("/", "decimalDiv"),
+		expr.Operator("==", "decimalEq"),
+		expr.Operator("!=", "decimalNe"),
+		expr.Operator("<", "decimalLt"),
+		expr.Operator("<=", "decimalLe"),
+		expr.Operator(">", "decimalGt"),
+		expr.Operator(">=", "decimalGe"),
+
+		// Strings
+		expr.Function("left", func(params ...any) (any, error) {
+			runes := []rune(params[0].(string))
+			return string(runes[:clampIndex(formulaInt(params[1]), len(runes))]), nil
+		}, new(func(string, int) string)),
+		expr.Function("right", func(params ...any) (any, error) {
+			runes := []rune(params[0].(string))
+			return string(runes[len(runes)-clampIndex(formulaInt(params[1]), len(runes)):]), nil
+		}, new(func(string, int) string)),
+		expr.Function("mid", func(params ...any) (any, error) {
+			runes := []rune(params[0].(string))
+			start := clampIndex(formulaInt(params[1]), len(runes))
+			end := clampIndex(start+formulaInt(params[2]), len(runes))
+			return string(runes[start:end]), nil
+		}, new(func(string, int, int) string)),
+		expr.Function("padLeft", func(params ...any) (any, error) {
+			s, pad := params[0].(string), padding(params)
+			return strings.Repeat(pad, max(formulaInt(params[1])-utf8.RuneCountInString(s), 0)) + s, nil
+		}, new(func(string, int) string), new(func(string, int, string) string)),
+		expr.Function("padRight", func(params ...any) (any, error) {
+			s, pad := params[0].(string), padding(params)
+			return s + strings.Repeat(pad, max(formulaInt(params[1])-utf8.RuneCountInString(s), 0)), nil
+		}, new(func(string, int) string), new(func(string, int, string) string)),
+		expr.Function("formatNumber", func(params ...any) (any, error) {
+			d, err := NewDecimal(params[0])
+			if err != nil {
+				return nil, err
+			}
+			return groupThousands(d.StringFixed(formulaInt(params[1]))), nil
+		}, new(func(float64, int) string), new(func(int, int) string), new(func(Decimal, int) string)),
+		expr.Function("isBlank", func(params ...any) (any, error) {
+			return strings.TrimSpace(params[0].(string)) == "", nil
+		}, new(func(string) bool)),
+	}
+}
+
+func timeFunc(fn func(time.Time) time.Time) func(params ...any) (any, error) {
+	return func(params ...any) (any, error) {
+		return fn(params[0].(time.Time)), nil
+	}
+}
+
+// addMonthsClamped adds months, clamping the day to the end of the target
+// month so Jan 31 + 1 month is Feb 28/29 rather than Mar 3
+func addMonthsClamped(t time.Time, months int) time.Time {
+	first := time.Date(t.Year(), t.Month(), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	target := first.AddDate(0, months, 0)
+	day := min(t.Day(), shared.DaysInMonth(target.Year(), target.Month()))
+	return target.AddDate(0, 0, day-1)
+}
+
+// roundFunc builds a rounding function over decimals and floats. Floats
+// are rounded through a decimal so 1.005 rounds to 1.01.
+func roundFunc(decimalFn func(Decimal, int) Decimal, floatFn func(float64) float64) func(params ...any) (any, error) {
+	return func(params ...any) (any, error) {
+		places := formulaInt(params[1])
+		switch v := params[0].(type) {
+		case Decimal:
+			return decimalFn(v, places), nil
+		case float64:
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				return v, nil
+			}
+			d, err := NewDecimal(v)
+			if err != nil {
+				scale := math.Pow(10, float64(places))
+				return floatFn(v*scale) / scale, nil
+			}
+			return decimalFn(d, places).Float64(), nil
+		}
+		return nil, fmt.Errorf("%w: cannot round %T", ErrInvalidExpression, params[0])
+	}
+}
+
+func decimalCeil(d Decimal, places int) Decimal {
+	truncated := d.Truncate(places)
+	if d.Sign() > 0 && truncated.Cmp(d) != 0 {
+		return truncated.Add(decimalUnit(places))
+	}
+	return truncated
+}
+
+func decimalFloor(d Decimal, places int) Decimal {
+	truncated := d.Truncate(places)
+	if d.Sign() < 0 && truncated.Cmp(d) != 0 {
+		return truncated.Sub(decimalUnit(places))
+	}
+	return truncated
+}
+
+// decimalUnit returns 10^-places
+func decimalUnit(places int) Decimal {
+	unit, _ := MustDecimal(1).Div(MustDecimal(math.Pow10(max(places, 0))))
+	return unit
+}
+
+// decimalOperandTypes are the operand combinations an overloaded operator
+// accepts; at least one side is a Decimal
+func decimalOperandTypes[R any]() []any {
+	return []any{
+		new(func(Decimal, Decimal) R),
+		new(func(Decimal, float64) R),
+		new(func(float64, Decimal) R),
+		new(func(Decimal, int) R),
+		new(func(int, Decimal) R),
+	}
+}
+
+// decimalOperator overloads an arithmetic operator for decimals. A nil fn
+// means division, which can fail.
+func decimalOperator(name string, fn func(Decimal, Decimal) Decimal) expr.Option {
+	return expr.Function(name, func(params ...any) (any, error) {
+		a, b, err := decimalOperands(params)
+		if err != nil {
+			return nil, err
+		}
+		if fn == nil {
+			return a.Div(b)
+		}
+		return fn(a, b), nil
+	}, decimalOperandTypes[Decimal]()...)
+}
+
+// decimalComparison overloads a comparison operator for decimals
+func decimalComparison(name string, test func(int) bool) expr.Option {
+	return expr.Function(name, func(params ...any) (any, error) {
+		a, b, err := decimalOperands(params)
+		if err != nil {
+			return nil, err
+		}
+		return test(a.Cmp(b)), nil
+	}, decimalOperandTypes[bool]()...)
+}
+
+func decimalOperands(params []any) (Decimal, Decimal, error) {
+	a, err := NewDecimal(params[0])
+	if err != nil {
+		return Decimal{}, Decimal{}, err
+	}
+	b, err := NewDecimal(params[1])
+	if err != nil {
+		return Decimal{}, Decimal{}, err
+	}
+	return a, b, nil
+}
+
+// formulaInt converts an int or float argument to int
+func formulaInt(value any) int {
+	switch v := value.(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	}
+	f, _ := toNumber(value)
+	return int(f)
+}
+
+func clampIndex(i, n int) int {
+	return min(max(i, 0), n)
+}
+
+func padding(params []any) string {
+	if len(params) > 2 {
+		if pad, _ := params[2].(string); pad != "" {
+			return pad
+		}
+	}
+	return " "
+}
+
+// groupThousands inserts thousands separators into a formatted number
+func groupThousands(s string) string {
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	whole, fraction, hasFraction := strings.Cut(s, ".")
+	var sb strings.Builder
+	for i, r := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteRune(r)
+	}
+	if hasFraction {
+		sb.WriteByte('.')
+		sb.WriteString(fraction)
+	}
+	return sign + sb.String()
+}
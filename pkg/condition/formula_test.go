@@ -0,0 +1,267 @@
+package condition_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	cb "github.com/niiniyare/ruun/pkg/condition"
+	"github.com/stretchr/testify/suite"
+)
+
+// TypedFormulaTestSuite tests value-producing formulas
+type TypedFormulaTestSuite struct {
+	EvaluatorTestSuite
+}
+
+func (s *TypedFormulaTestSuite) evalCtx(data map[string]any, fields ...cb.Field) *cb.EvalContext {
+	evalCtx := cb.NewEvalContext(data, cb.DefaultEvalOptions())
+	for _, field := range fields {
+		s.Require().NoError(evalCtx.RegisterField(field))
+	}
+	return evalCtx
+}
+
+func (s *TypedFormulaTestSuite) TestTypedResults() {
+	friday := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		formula    string
+		resultType cb.FieldType
+		data       map[string]any
+		fields     []cb.Field
+		expected   any
+	}{
+		{
+			name:       "number_from_mixed_ints_and_floats",
+			formula:    "quantity * unit_price",
+			resultType: cb.FieldTypeNumber,
+			data:       map[string]any{"quantity": 10, "unit_price": 5.5},
+			fields: []cb.Field{
+				{Name: "quantity", Label: "Quantity", Type: cb.FieldTypeNumber},
+				{Name: "unit_price", Label: "Unit price", Type: cb.FieldTypeNumber},
+			},
+			expected: 55.0,
+		},
+		{
+			name:       "numeric_strings_are_converted",
+			formula:    "quantity + 1",
+			resultType: cb.FieldTypeNumber,
+			data:       map[string]any{"quantity": "41"},
+			fields:     []cb.Field{{Name: "quantity", Label: "Quantity", Type: cb.FieldTypeNumber}},
+			expected:   42.0,
+		},
+		{
+			name:       "decimal_arithmetic_is_exact",
+			formula:    "price * quantity + shipping",
+			resultType: cb.FieldTypeDecimal,
+			data:       map[string]any{"price": "19.99", "quantity": 3, "shipping": 0.1},
+			fields: []cb.Field{
+				{Name: "price", Label: "Price", Type: cb.FieldTypeDecimal},
+				{Name: "quantity", Label: "Quantity", Type: cb.FieldTypeNumber},
+				{Name: "shipping", Label: "Shipping", Type: cb.FieldTypeDecimal},
+			},
+			expected: cb.MustDecimal("60.07"),
+		},
+		{
+			name:       "decimal_comparison",
+			formula:    "decimal(0.1) + decimal(0.2) == decimal(0.3)",
+			resultType: cb.FieldTypeBoolean,
+			expected:   true,
+		},
+		{
+			name:       "rounding",
+			formula:    "roundTo(1.005, 2) + floorTo(2.999, 1) + ceilTo(0.001, 2)",
+			resultType: cb.FieldTypeNumber,
+			expected:   1.01 + 2.9 + 0.01,
+		},
+		{
+			name:       "money_rounds_to_cents",
+			formula:    "money(total / 3)",
+			resultType: cb.FieldTypeText,
+			data:       map[string]any{"total": "100"},
+			fields:     []cb.Field{{Name: "total", Label: "Total", Type: cb.FieldTypeDecimal}},
+			expected:   "33.33",
+		},
+		{
+			name:       "business_days",
+			formula:    "addBusinessDays(start, 3)",
+			resultType: cb.FieldTypeDate,
+			data:       map[string]any{"start": "2026-10-16"},
+			fields:     []cb.Field{{Name: "start", Label: "Start", Type: cb.FieldTypeDate}},
+			expected:   friday.AddDate(0, 0, 5),
+		},
+		{
+			name:       "add_months_clamps_to_month_end",
+			formula:    "formatDate(addMonths(makeDate(2026, 1, 31), 1), 'date')",
+			resultType: cb.FieldTypeText,
+			expected:   "2026-02-28",
+		},
+		{
+			name:       "business_days_between",
+			formula:    "businessDaysBetween(start, end)",
+			resultType: cb.FieldTypeNumber,
+			data:       map[string]any{"start": friday, "end": friday.AddDate(0, 0, 7)},
+			fields: []cb.Field{
+				{Name: "start", Label: "Start", Type: cb.FieldTypeDate},
+				{Name: "end", Label: "End", Type: cb.FieldTypeDate},
+			},
+			expected: 6.0, // both ends are counted
+		},
+		{
+			name:       "strings",
+			formula:    "upper(left(code, 3)) + '-' + padLeft(string(seq), 4, '0')",
+			resultType: cb.FieldTypeText,
+			data:       map[string]any{"code": "invoice", "seq": 42},
+			fields:     []cb.Field{{Name: "code", Label: "Code", Type: cb.FieldTypeText}},
+			expected:   "INV-0042",
+		},
+		{
+			name:       "format_number",
+			formula:    "formatNumber(amount, 2)",
+			resultType: cb.FieldTypeText,
+			data:       map[string]any{"amount": 1234567.891},
+			fields:     []cb.Field{{Name: "amount", Label: "Amount", Type: cb.FieldTypeNumber}},
+			expected:   "1,234,567.89",
+		},
+		{
+			name:       "nested_declared_field",
+			formula:    "customer.discount * 100",
+			resultType: cb.FieldTypeNumber,
+			data:       map[string]any{"customer": map[string]any{"discount": "0.15"}},
+			fields:     []cb.Field{{Name: "customer.discount", Label: "Discount", Type: cb.FieldTypeNumber}},
+			expected:   15.0,
+		},
+		{
+			name:       "missing_value_is_zero",
+			formula:    "quantity * 2",
+			resultType: cb.FieldTypeNumber,
+			fields:     []cb.Field{{Name: "quantity", Label: "Quantity", Type: cb.FieldTypeNumber}},
+			expected:   0.0,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			result, err := s.evaluator.EvaluateFormula(s.ctx, tt.formula, tt.resultType, s.evalCtx(tt.data, tt.fields...))
+			s.Require().NoError(err)
+			if expected, ok := tt.expected.(cb.Decimal); ok {
+				s.Require().IsType(cb.Decimal{}, result)
+				s.Equal(expected.String(), result.(cb.Decimal).String())
+				return
+			}
+			if expected, ok := tt.expected.(float64); ok {
+				s.InDelta(expected, result, 1e-9)
+				return
+			}
+			s.Equal(tt.expected, result)
+		})
+	}
+}
+
+func (s *TypedFormulaTestSuite) TestCompileErrors() {
+	fields := map[string]cb.Field{
+		"name":     {Name: "name", Label: "Name", Type: cb.FieldTypeText},
+		"quantity": {Name: "quantity", Label: "Quantity", Type: cb.FieldTypeNumber},
+		"due":      {Name: "due", Label: "Due", Type: cb.FieldTypeDate},
+	}
+
+	tests := []struct {
+		name       string
+		formula    string
+		resultType cb.FieldType
+		kind       error
+	}{
+		{name: "operand_types", formula: "name - quantity", resultType: cb.FieldTypeNumber, kind: cb.ErrInvalidExpression},
+		{name: "unknown_field", formula: "quantity * price", resultType: cb.FieldTypeNumber, kind: cb.ErrInvalidExpression},
+		{name: "function_argument", formula: "addDays(name, 1)", resultType: cb.FieldTypeDate, kind: cb.ErrInvalidExpression},
+		{name: "result_type", formula: "quantity > 1", resultType: cb.FieldTypeNumber, kind: cb.ErrFormulaType},
+		{name: "date_result", formula: "quantity + 1", resultType: cb.FieldTypeDate, kind: cb.ErrFormulaType},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			err := s.evaluator.ValidateFormula("total", tt.formula, tt.resultType, fields)
+			s.Require().Error(err)
+			s.True(errors.Is(err, tt.kind), "got %v", err)
+
+			var formulaErr *cb.FormulaError
+			s.Require().True(errors.As(err, &formulaErr))
+			s.Equal("total", formulaErr.Field)
+			s.Contains(err.Error(), "formula of field total")
+		})
+	}
+
+	err := s.evaluator.ValidateFormula("total", "quantity *", cb.FieldTypeNumber, fields)
+	var formulaErr *cb.FormulaError
+	s.Require().True(errors.As(err, &formulaErr))
+	s.Equal(1, formulaErr.Line)
+	s.Positive(formulaErr.Column)
+}
+
+func (s *TypedFormulaTestSuite) TestCacheIsKeyedByTypes() {
+	first, err := s.evaluator.EvaluateFormula(s.ctx, "a + b", "", s.evalCtx(map[string]any{"a": 1, "b": 2}))
+	s.Require().NoError(err)
+	s.Equal(3, first)
+
+	second, err := s.evaluator.EvaluateFormula(s.ctx, "a + b", "", s.evalCtx(map[string]any{"a": "x", "b": "y"}))
+	s.Require().NoError(err)
+	s.Equal("xy", second)
+
+	evalCtx := s.evalCtx(map[string]any{"a": 1, "b": 2})
+	_, err = s.evaluator.EvaluateFormula(s.ctx, "a + b", "", evalCtx)
+	s.Require().NoError(err)
+	s.Equal(int32(1), evalCtx.GetMetrics().CacheHits)
+}
+
+func (s *TypedFormulaTestSuite) TestContextFunctionsAndVariables() {
+	evalCtx := s.evalCtx(map[string]any{"amount": 200}, cb.Field{Name: "amount", Label: "Amount", Type: cb.FieldTypeNumber})
+	s.Require().NoError(evalCtx.RegisterFunction("tax", func(ctx context.Context, args []any, evalCtx *cb.EvalContext) (any, error) {
+		return args[0].(float64) * 0.16, nil
+	}))
+
+	result, err := s.evaluator.EvaluateFormula(s.ctx, "amount + tax(amount)", "", evalCtx)
+	s.Require().NoError(err)
+	s.InDelta(232.0, result, 1e-9)
+
+	result, err = s.evaluator.EvaluateFormula(s.ctx, "today <= now && daysBetween(today, addDays(today, 7)) == 7", cb.FieldTypeBoolean, evalCtx)
+	s.Require().NoError(err)
+	s.Equal(true, result)
+}
+
+func (s *TypedFormulaTestSuite) TestRuntimeErrors() {
+	evalCtx := s.evalCtx(map[string]any{"total": "10", "count": 0},
+		cb.Field{Name: "total", Label: "Total", Type: cb.FieldTypeDecimal},
+		cb.Field{Name: "count", Label: "Count", Type: cb.FieldTypeDecimal})
+	_, err := s.evaluator.EvaluateFormula(s.ctx, "total / count", cb.FieldTypeDecimal, evalCtx)
+	s.ErrorContains(err, "division by zero")
+
+	evalCtx = s.evalCtx(map[string]any{"quantity": "many"}, cb.Field{Name: "quantity", Label: "Quantity", Type: cb.FieldTypeNumber})
+	_, err = s.evaluator.EvaluateFormula(s.ctx, "quantity * 2", cb.FieldTypeNumber, evalCtx)
+	s.ErrorIs(err, cb.ErrFormulaType)
+}
+
+func TestDecimal(t *testing.T) {
+	s := new(suite.Suite)
+	s.SetT(t)
+
+	s.Equal("0.3", cb.MustDecimal(0.1).Add(cb.MustDecimal(0.2)).String())
+	s.Equal("2.68", cb.MustDecimal("2.675").Round(2).String())
+	s.Equal("2.68", cb.MustDecimal("2.675").RoundBank(2).String())
+	s.Equal("2.66", cb.MustDecimal("2.665").RoundBank(2).String())
+	s.Equal("-1.5", cb.MustDecimal("-1.45").Round(1).String())
+	s.Equal("-1.4", cb.MustDecimal("-1.45").RoundBank(1).String())
+	s.Equal("1.20", cb.MustDecimal("1.2").StringFixed(2))
+
+	third, err := cb.MustDecimal(1).Div(cb.MustDecimal(3))
+	s.Require().NoError(err)
+	s.Equal("1", third.Mul(cb.MustDecimal(3)).String())
+
+	_, err = cb.MustDecimal(1).Div(cb.Decimal{})
+	s.ErrorIs(err, cb.ErrDivisionByZero)
+}
+
+func TestTypedFormulaSuite(t *testing.T) {
+	suite.Run(t, new(TypedFormulaTestSuite))
+}
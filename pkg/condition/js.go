@@ -0,0 +1,381 @@
+package condition
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/expr-lang/expr/ast"
+	"github.com/expr-lang/expr/parser"
+)
+
+// ErrJSUnsupported is returned for conditions that cannot run in the
+// browser, such as function expressions and formulas outside the
+// supported subset
+var ErrJSUnsupported = errors.New("condition cannot be translated to JavaScript")
+
+// JSOptions configures JavaScript generation
+type JSOptions struct {
+	// DataVar is the expression holding the form values, e.g. "formData"
+	// in the form organism's Alpine store. Defaults to "formData".
+	DataVar  string
+	MaxDepth int
+}
+
+// JSCompiler translates condition trees into JavaScript predicates that
+// call into JSRuntime.
+//
+// Formulas are supported for the expr-lang subset that has a direct
+// JavaScript counterpart: literals, field and member access, arithmetic,
+// comparison, logical operators, in, contains, startsWith, endsWith,
+// matches, the ternary operator and the len, lower, upper, trim and abs
+// builtins. expr-lang checks operand types when a formula is compiled; the
+// runtime checks them when the operation runs, so a mistyped branch that
+// is never reached fails in Go but not in the browser. Use JSEquivalence
+// to compare both over generated data.
+type JSCompiler struct {
+	opts JSOptions
+}
+
+// jsDataVarPattern matches identifiers and dotted paths
+var jsDataVarPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*(\.[A-Za-z_$][A-Za-z0-9_$]*)*$`)
+
+// NewJSCompiler creates a JavaScript compiler
+func NewJSCompiler(opts JSOptions) (*JSCompiler, error) {
+	if opts.DataVar == "" {
+		opts.DataVar = "formData"
+	}
+	if !jsDataVarPattern.MatchString(opts.DataVar) {
+		return nil, fmt.Errorf("%w: invalid data variable %q", ErrValidation, opts.DataVar)
+	}
+	if opts.MaxDepth == 0 {
+		opts.MaxDepth = DefaultMaxDepth
+	}
+	return &JSCompiler{opts: opts}, nil
+}
+
+// CompileJS is a convenience wrapper around NewJSCompiler and Compile
+func CompileJS(root any, opts JSOptions) (string, error) {
+	compiler, err := NewJSCompiler(opts)
+	if err != nil {
+		return "", err
+	}
+	return compiler.Compile(root)
+}
+
+// Compile translates a condition tree into a JavaScript boolean
+// expression. The expression throws where the Evaluator returns an error,
+// e.g. for missing fields.
+func (c *JSCompiler) Compile(root any) (string, error) {
+	if root == nil {
+		return "", fmt.Errorf("%w: root condition is nil", ErrInvalidExpression)
+	}
+	return c.node(root, 0)
+}
+
+// Predicate compiles a condition into an expression suitable for an Alpine
+// binding such as x-show. Errors evaluate to false.
+func (c *JSCompiler) Predicate(root any) (string, error) {
+	js, err := c.Compile(root)
+	if err != nil {
+		return "", err
+	}
+	return JSRuntimeName + ".test(() => " + js + ")", nil
+}
+
+func (c *JSCompiler) node(node any, depth int) (string, error) {
+	if depth > c.opts.MaxDepth {
+		return "", fmt.Errorf("%w: depth %d exceeds limit %d", ErrMaxDepthExceeded, depth, c.opts.MaxDepth)
+	}
+
+	switch v := node.(type) {
+	case *ConditionGroup:
+		return c.group(v, depth)
+	case ConditionGroup:
+		return c.group(&v, depth)
+	case *ConditionRule:
+		return c.rule(v)
+	case ConditionRule:
+		return c.rule(&v)
+	case map[string]any:
+		id, _ := v["id"].(string)
+		if id == "" {
+			id = "unknown"
+		}
+		if _, hasConjunction := v["conjunction"]; hasConjunction {
+			var group ConditionGroup
+			if err := mapToStruct(v, &group); err != nil {
+				return jsFail(fmt.Sprintf("group %s: invalid structure: %v", id, err)), nil
+			}
+			return c.group(&group, depth)
+		}
+		var rule ConditionRule
+		if err := mapToStruct(v, &rule); err != nil {
+			return jsFail(fmt.Sprintf("rule %s: invalid structure: %v", id, err)), nil
+		}
+		return c.rule(&rule)
+	default:
+		return jsFail(fmt.Sprintf("%v: unknown node type %T", ErrInvalidExpression, node)), nil
+	}
+}
+
+// group compiles a group. Invalid groups compile to a throwing expression
+// because the Evaluator only reports them when it reaches them.
+func (c *JSCompiler) group(group *ConditionGroup, depth int) (string, error) {
+	if err := group.Validate(); err != nil {
+		return jsFail(fmt.Sprintf("group %s: %v", group.ID, err)), nil
+	}
+
+	if group.If != "" {
+		formula, err := c.formula(group.If)
+		if err != nil {
+			return "", fmt.Errorf("group %s: %w", group.ID, err)
+		}
+		if group.Not {
+			return "!" + formula, nil
+		}
+		return formula, nil
+	}
+
+	joiner := " && "
+	if group.Conjunction == ConjunctionOr {
+		joiner = " || "
+	}
+	clauses := make([]string, 0, len(group.Children))
+	for i, child := range group.Children {
+		clause, err := c.node(child, depth+1)
+		if err != nil {
+			return "", fmt.Errorf("group %s: child %d: %w", group.ID, i, err)
+		}
+		clauses = append(clauses, clause)
+	}
+
+	clause := "(" + strings.Join(clauses, joiner) + ")"
+	if group.Not {
+		clause = "!" + clause
+	}
+	return clause, nil
+}
+
+func (c *JSCompiler) rule(rule *ConditionRule) (string, error) {
+	if err := rule.Validate(); err != nil {
+		return jsFail(fmt.Sprintf("rule %s: %v", rule.ID, err)), nil
+	}
+	if rule.If != "" {
+		formula, err := c.formula(rule.If)
+		if err != nil {
+			return "", fmt.Errorf("rule %s: %w", rule.ID, err)
+		}
+		return formula, nil
+	}
+
+	left, err := c.operand(rule.Left)
+	if err != nil {
+		return "", fmt.Errorf("rule %s: left expression: %w", rule.ID, err)
+	}
+	op := jsString(string(rule.Op))
+	if isUnaryOperator(rule.Op) {
+		return fmt.Sprintf("%s.rule(%s, %s, [])", JSRuntimeName, op, left), nil
+	}
+
+	right, err := ruleOperands(rule.Right)
+	if err != nil {
+		return jsFail(fmt.Sprintf("rule %s: right expression: %v", rule.ID, err)), nil
+	}
+	items := make([]string, len(right))
+	for i, expr := range right {
+		if items[i], err = c.operand(expr); err != nil {
+			return "", fmt.Errorf("rule %s: right expression: %w", rule.ID, err)
+		}
+	}
+	return fmt.Sprintf("%s.rule(%s, %s, [%s])", JSRuntimeName, op, left, strings.Join(items, ", ")), nil
+}
+
+// operand compiles a value or field expression
+func (c *JSCompiler) operand(expr Expression) (string, error) {
+	if err := expr.Validate(); err != nil {
+		return jsFail(err.Error()), nil
+	}
+	switch expr.Type {
+	case ValueTypeValue:
+		return jsLiteral(expr.Value)
+	case ValueTypeField:
+		return c.field(expr.Field), nil
+	case ValueTypeFunc:
+		return "", fmt.Errorf("%w: function %s runs on the server only", ErrJSUnsupported, expr.Func.Type)
+	default:
+		return jsFail(fmt.Sprintf("%v: unknown type %s", ErrInvalidExpression, expr.Type)), nil
+	}
+}
+
+func (c *JSCompiler) field(path string) string {
+	return fmt.Sprintf("%s.get(%s, %s)", JSRuntimeName, c.opts.DataVar, jsString(path))
+}
+
+// formula compiles an expr-lang formula that must produce a boolean
+func (c *JSCompiler) formula(formula string) (string, error) {
+	tree, err := parser.Parse(formula)
+	if err != nil {
+		// The Evaluator reports syntax errors when it reaches the formula
+		return jsFail(fmt.Sprintf("formula evaluation failed: %v", err)), nil
+	}
+	js, err := c.formulaNode(tree.Node)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s.fbool(%s)", JSRuntimeName, js), nil
+}
+
+func (c *JSCompiler) formulaNode(node ast.Node) (string, error) {
+	r := JSRuntimeName
+	switch n := node.(type) {
+	case *ast.NilNode:
+		return "null", nil
+	case *ast.BoolNode:
+		return strconv.FormatBool(n.Value), nil
+	case *ast.IntegerNode:
+		return strconv.Itoa(n.Value), nil
+	case *ast.FloatNode:
+		return jsLiteral(n.Value)
+	case *ast.StringNode:
+		return jsString(n.Value), nil
+
+	case *ast.IdentifierNode:
+		switch n.Value {
+		case "now", "today", "$env":
+			return "", fmt.Errorf("%w: %s is only available on the server", ErrJSUnsupported, n.Value)
+		}
+		return c.field(n.Value), nil
+
+	case *ast.MemberNode:
+		if n.Optional {
+			return "", fmt.Errorf("%w: optional chaining", ErrJSUnsupported)
+		}
+		object, err := c.formulaNode(n.Node)
+		if err != nil {
+			return "", err
+		}
+		property, err := c.formulaNode(n.Property)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s.fmember(%s, %s)", r, object, property), nil
+
+	case *ast.ArrayNode:
+		items := make([]string, len(n.Nodes))
+		for i, item := range n.Nodes {
+			js, err := c.formulaNode(item)
+			if err != nil {
+				return "", err
+			}
+			items[i] = js
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+
+	case *ast.UnaryNode:
+		operand, err := c.formulaNode(n.Node)
+		if err != nil {
+			return "", err
+		}
+		switch n.Operator {
+		case "!", "not":
+			return fmt.Sprintf("!%s.fbool(%s)", r, operand), nil
+		case "-", "+":
+			return fmt.Sprintf("%s.fneg(%s, %s)", r, jsString(n.Operator), operand), nil
+		}
+		return "", fmt.Errorf("%w: unary operator %s", ErrJSUnsupported, n.Operator)
+
+	case *ast.BinaryNode:
+		left, err := c.formulaNode(n.Left)
+		if err != nil {
+			return "", err
+		}
+		right, err := c.formulaNode(n.Right)
+		if err != nil {
+			return "", err
+		}
+		switch n.Operator {
+		case "&&", "and":
+			return fmt.Sprintf("(%s.fbool(%s) && %s.fbool(%s))", r, left, r, right), nil
+		case "||", "or":
+			return fmt.Sprintf("(%s.fbool(%s) || %s.fbool(%s))", r, left, r, right), nil
+		case "==":
+			return fmt.Sprintf("%s.feq(%s, %s)", r, left, right), nil
+		case "!=":
+			return fmt.Sprintf("!%s.feq(%s, %s)", r, left, right), nil
+		case "<", "<=", ">", ">=":
+			return fmt.Sprintf("%s.fcmp(%s, %s, %s)", r, jsString(n.Operator), left, right), nil
+		case "+", "-", "*", "/":
+			return fmt.Sprintf("%s.farith(%s, %s, %s)", r, jsString(n.Operator), left, right), nil
+		case "in":
+			return fmt.Sprintf("%s.fin(%s, %s)", r, left, right), nil
+		case "contains", "startsWith", "endsWith", "matches":
+			return fmt.Sprintf("%s.fstr(%s, %s, %s)", r, jsString(n.Operator), left, right), nil
+		}
+		return "", fmt.Errorf("%w: operator %s", ErrJSUnsupported, n.Operator)
+
+	case *ast.ConditionalNode:
+		cond, err := c.formulaNode(n.Cond)
+		if err != nil {
+			return "", err
+		}
+		exp1, err := c.formulaNode(n.Exp1)
+		if err != nil {
+			return "", err
+		}
+		exp2, err := c.formulaNode(n.Exp2)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("(%s.fbool(%s) ? %s : %s)", r, cond, exp1, exp2), nil
+
+	case *ast.BuiltinNode:
+		switch n.Name {
+		case "len", "lower", "upper", "trim", "abs":
+		default:
+			return "", fmt.Errorf("%w: builtin %s", ErrJSUnsupported, n.Name)
+		}
+		args := make([]string, len(n.Arguments))
+		for i, arg := range n.Arguments {
+			js, err := c.formulaNode(arg)
+			if err != nil {
+				return "", err
+			}
+			args[i] = js
+		}
+		return fmt.Sprintf("%s.fcall(%s, [%s])", r, jsString(n.Name), strings.Join(args, ", ")), nil
+
+	case *ast.CallNode:
+		name := "call"
+		if ident, ok := n.Callee.(*ast.IdentifierNode); ok {
+			name = ident.Value
+		}
+		return "", fmt.Errorf("%w: function %s runs on the server only", ErrJSUnsupported, name)
+
+	default:
+		return "", fmt.Errorf("%w: %T in formula", ErrJSUnsupported, node)
+	}
+}
+
+// jsLiteral encodes a value as a JavaScript literal
+func jsLiteral(value any) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("%w: cannot encode %T: %v", ErrJSUnsupported, value, err)
+	}
+	return string(data), nil
+}
+
+// jsString encodes a string literal
+func jsString(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+// jsFail returns an expression that throws msg when evaluated
+func jsFail(msg string) string {
+	return fmt.Sprintf("%s.fail(%s)", JSRuntimeName, jsString(msg))
+}
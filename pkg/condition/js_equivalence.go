@@ -0,0 +1,489 @@
+package condition
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/expr-lang/expr/ast"
+	"github.com/expr-lang/expr/parser"
+)
+
+// ErrJSRunnerUnavailable is returned when no JavaScript engine is found
+var ErrJSRunnerUnavailable = errors.New("javascript runner unavailable")
+
+// JSRunner executes a script and returns what it writes to stdout
+type JSRunner interface {
+	RunJS(ctx context.Context, script string) ([]byte, error)
+}
+
+// NodeRunner runs scripts with Node.js
+type NodeRunner struct {
+	// Path to the node binary; looked up in PATH when empty
+	Path string
+}
+
+// RunJS implements JSRunner
+func (r NodeRunner) RunJS(ctx context.Context, script string) ([]byte, error) {
+	path := r.Path
+	if path == "" {
+		var err error
+		if path, err = exec.LookPath("node"); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrJSRunnerUnavailable, err)
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, path, "-")
+	cmd.Stdin = strings.NewReader(script)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("node: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// EquivalenceOutcome is the result of one predicate evaluation
+type EquivalenceOutcome struct {
+	Value bool   `json:"value"`
+	Error string `json:"error,omitempty"`
+}
+
+// Failed reports whether the evaluation returned an error
+func (o EquivalenceOutcome) Failed() bool {
+	return o.Error != ""
+}
+
+// agrees reports whether two outcomes are the same. Error messages are not
+// compared, only whether both sides failed.
+func (o EquivalenceOutcome) agrees(other EquivalenceOutcome) bool {
+	if o.Failed() || other.Failed() {
+		return o.Failed() && other.Failed()
+	}
+	return o.Value == other.Value
+}
+
+func (o EquivalenceOutcome) String() string {
+	if o.Failed() {
+		return "error: " + o.Error
+	}
+	return fmt.Sprintf("%t", o.Value)
+}
+
+// EquivalenceMismatch is a data set on which Go and JavaScript disagree
+type EquivalenceMismatch struct {
+	Data map[string]any     `json:"data"`
+	Go   EquivalenceOutcome `json:"go"`
+	JS   EquivalenceOutcome `json:"js"`
+}
+
+// EquivalenceReport summarises an equivalence check
+type EquivalenceReport struct {
+	Script     string                `json:"-"`
+	Cases      int                   `json:"cases"`
+	Mismatches []EquivalenceMismatch `json:"mismatches,omitempty"`
+}
+
+// OK reports whether every case agreed
+func (r *EquivalenceReport) OK() bool {
+	return len(r.Mismatches) == 0
+}
+
+// Err returns an error describing the first mismatches, or nil
+func (r *EquivalenceReport) Err() error {
+	if r.OK() {
+		return nil
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d of %d cases differ between Go and JavaScript", len(r.Mismatches), r.Cases)
+	for i, m := range r.Mismatches {
+		if i == 5 {
+			fmt.Fprintf(&b, "\n  ... and %d more", len(r.Mismatches)-i)
+			break
+		}
+		data, _ := json.Marshal(m.Data)
+		fmt.Fprintf(&b, "\n  %s: go=%s js=%s", data, m.Go, m.JS)
+	}
+	return errors.New(b.String())
+}
+
+// JSEquivalence checks that compiled JavaScript predicates give the same
+// results as the Evaluator. Data is passed through JSON before either side
+// sees it, as it is when form values reach the server.
+type JSEquivalence struct {
+	Evaluator *Evaluator
+	Runner    JSRunner
+	// Samples is the number of generated data sets, default 200
+	Samples int
+	// Seed makes generated data reproducible
+	Seed uint64
+}
+
+// NewJSEquivalence creates an equivalence checker that runs Node.js
+func NewJSEquivalence(evaluator *Evaluator) *JSEquivalence {
+	return &JSEquivalence{
+		Evaluator: evaluator,
+		Runner:    NodeRunner{},
+		Samples:   200,
+		Seed:      1,
+	}
+}
+
+// Check evaluates root in Go and JavaScript over generated data and the
+// given extra data sets, and reports every disagreement
+func (q *JSEquivalence) Check(ctx context.Context, root any, data ...map[string]any) (*EquivalenceReport, error) {
+	if q.Evaluator == nil {
+		return nil, fmt.Errorf("%w: evaluator is required", ErrValidation)
+	}
+	runner := q.Runner
+	if runner == nil {
+		runner = NodeRunner{}
+	}
+
+	js, err := CompileJS(root, JSOptions{DataVar: "data"})
+	if err != nil {
+		return nil, err
+	}
+
+	cases := append(generateConditionData(root, q.Samples, q.Seed), data...)
+	encoded, err := json.Marshal(cases)
+	if err != nil {
+		return nil, fmt.Errorf("encode cases: %w", err)
+	}
+	// Decode once so Go sees the same JSON types as JavaScript
+	var decoded []map[string]any
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		return nil, fmt.Errorf("decode cases: %w", err)
+	}
+
+	script := JSRuntime + "\n" +
+		"var predicate = function (data) { return " + js + "; };\n" +
+		"var cases = " + string(encoded) + ";\n" +
+		"process.stdout.write(JSON.stringify(cases.map(function (data) {\n" +
+		"  return " + JSRuntimeName + ".evaluate(function () { return predicate(data); });\n" +
+		"})));\n"
+	out, err := runner.RunJS(ctx, script)
+	if err != nil {
+		return nil, err
+	}
+	var jsOutcomes []EquivalenceOutcome
+	if err := json.Unmarshal(out, &jsOutcomes); err != nil {
+		return nil, fmt.Errorf("decode javascript results: %w", err)
+	}
+	if len(jsOutcomes) != len(decoded) {
+		return nil, fmt.Errorf("javascript returned %d results for %d cases", len(jsOutcomes), len(decoded))
+	}
+
+	report := &EquivalenceReport{Script: script, Cases: len(decoded)}
+	for i, values := range decoded {
+		evalCtx := NewEvalContext(values, DefaultEvalOptions())
+		var goOutcome EquivalenceOutcome
+		goOutcome.Value, err = q.Evaluator.Evaluate(ctx, root, evalCtx)
+		if err != nil {
+			goOutcome = EquivalenceOutcome{Error: err.Error()}
+		}
+		if !goOutcome.agrees(jsOutcomes[i]) {
+			report.Mismatches = append(report.Mismatches, EquivalenceMismatch{
+				Data: cases[i],
+				Go:   goOutcome,
+				JS:   jsOutcomes[i],
+			})
+		}
+	}
+	return report, nil
+}
+
+// missingValue marks a field that is left out of a generated data set
+type missingValue struct{}
+
+// conditionDomain collects the fields a condition reads and values worth
+// testing them with
+type conditionDomain struct {
+	fields map[string][]any
+	// typed fields are read by formulas, which expr-lang type checks, so
+	// they only get values of the kinds their literals have
+	typed map[string]bool
+}
+
+// generateConditionData builds n data sets for the fields root reads,
+// mixing values from the condition's literals with boundary values
+func generateConditionData(root any, n int, seed uint64) []map[string]any {
+	domain := &conditionDomain{fields: make(map[string][]any), typed: make(map[string]bool)}
+	domain.node(root, 0)
+
+	names := make([]string, 0, len(domain.fields))
+	for name := range domain.fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	candidates := make(map[string][]any, len(names))
+	for _, name := range names {
+		candidates[name] = domain.candidates(name)
+	}
+
+	rng := rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))
+	cases := make([]map[string]any, 0, n)
+	for i := 0; i < n; i++ {
+		data := make(map[string]any)
+		for _, name := range names {
+			values := candidates[name]
+			value := values[rng.IntN(len(values))]
+			if _, missing := value.(missingValue); missing {
+				continue
+			}
+			setDataPath(data, name, value)
+		}
+		cases = append(cases, data)
+	}
+	return cases
+}
+
+func (d *conditionDomain) node(node any, depth int) {
+	if depth > DefaultMaxDepth {
+		return
+	}
+	switch v := node.(type) {
+	case *ConditionGroup:
+		d.group(v, depth)
+	case ConditionGroup:
+		d.group(&v, depth)
+	case *ConditionRule:
+		d.rule(v)
+	case ConditionRule:
+		d.rule(&v)
+	case map[string]any:
+		if _, hasConjunction := v["conjunction"]; hasConjunction {
+			var group ConditionGroup
+			if mapToStruct(v, &group) == nil {
+				d.group(&group, depth)
+			}
+			return
+		}
+		var rule ConditionRule
+		if mapToStruct(v, &rule) == nil {
+			d.rule(&rule)
+		}
+	}
+}
+
+func (d *conditionDomain) group(group *ConditionGroup, depth int) {
+	if group == nil {
+		return
+	}
+	if group.If != "" {
+		d.formula(group.If)
+	}
+	for _, child := range group.Children {
+		d.node(child, depth+1)
+	}
+}
+
+func (d *conditionDomain) rule(rule *ConditionRule) {
+	if rule == nil {
+		return
+	}
+	if rule.If != "" {
+		d.formula(rule.If)
+		return
+	}
+	left := ""
+	if rule.Left.Type == ValueTypeField && rule.Left.Field != "" {
+		left = rule.Left.Field
+		d.add(left)
+	}
+	operands, err := ruleOperands(rule.Right)
+	if err != nil {
+		return
+	}
+	for _, operand := range operands {
+		switch operand.Type {
+		case ValueTypeField:
+			d.add(operand.Field)
+		case ValueTypeValue:
+			if left != "" {
+				d.add(left, operand.Value)
+			}
+		}
+	}
+}
+
+func (d *conditionDomain) add(field string, values ...any) {
+	d.fields[field] = append(d.fields[field], values...)
+}
+
+// formula records the identifiers a formula reads and the literals they
+// are compared with
+func (d *conditionDomain) formula(formula string) {
+	tree, err := parser.Parse(formula)
+	if err != nil {
+		return
+	}
+	ast.Walk(&tree.Node, &formulaDomainVisitor{domain: d})
+}
+
+type formulaDomainVisitor struct {
+	domain *conditionDomain
+}
+
+func (v *formulaDomainVisitor) Visit(node *ast.Node) {
+	switch n := (*node).(type) {
+	case *ast.IdentifierNode:
+		if n.Value != "now" && n.Value != "today" {
+			v.domain.typed[n.Value] = true
+			v.domain.add(n.Value)
+		}
+	case *ast.BinaryNode:
+		side, literal := n.Left, formulaLiteral(n.Right)
+		if literal == nil {
+			side, literal = n.Right, formulaLiteral(n.Left)
+		}
+		if literal == nil {
+			return
+		}
+		// Every field on the other side is tried with the literal, so
+		// arithmetic such as qty * price > 100 can reach both outcomes
+		for _, path := range formulaFieldPaths(side) {
+			v.domain.typed[path] = true
+			v.domain.add(path, literal)
+		}
+	}
+}
+
+// formulaFieldPaths returns the data paths read by a formula subtree
+func formulaFieldPaths(node ast.Node) []string {
+	if path := formulaFieldPath(node); path != "" {
+		return []string{path}
+	}
+	switch n := node.(type) {
+	case *ast.BinaryNode:
+		return append(formulaFieldPaths(n.Left), formulaFieldPaths(n.Right)...)
+	case *ast.UnaryNode:
+		return formulaFieldPaths(n.Node)
+	case *ast.BuiltinNode:
+		var paths []string
+		for _, arg := range n.Arguments {
+			paths = append(paths, formulaFieldPaths(arg)...)
+		}
+		return paths
+	}
+	return nil
+}
+
+// formulaFieldPath returns the data path of an identifier or member chain
+func formulaFieldPath(node ast.Node) string {
+	switch n := node.(type) {
+	case *ast.IdentifierNode:
+		return n.Value
+	case *ast.MemberNode:
+		prop, ok := n.Property.(*ast.StringNode)
+		if !ok {
+			return ""
+		}
+		if parent := formulaFieldPath(n.Node); parent != "" {
+			return parent + "." + prop.Value
+		}
+	}
+	return ""
+}
+
+func formulaLiteral(node ast.Node) any {
+	switch n := node.(type) {
+	case *ast.IntegerNode:
+		return float64(n.Value)
+	case *ast.FloatNode:
+		return n.Value
+	case *ast.StringNode:
+		return n.Value
+	case *ast.BoolNode:
+		return n.Value
+	case *ast.ArrayNode:
+		items := make([]any, 0, len(n.Nodes))
+		for _, item := range n.Nodes {
+			if value := formulaLiteral(item); value != nil {
+				items = append(items, value)
+			}
+		}
+		return items
+	}
+	return nil
+}
+
+// candidates returns the values to try for a field
+func (d *conditionDomain) candidates(field string) []any {
+	var values []any
+	kinds := make(map[string]bool)
+	var add func(value any)
+	add = func(value any) {
+		switch v := value.(type) {
+		case []any:
+			for _, item := range v {
+				add(item)
+			}
+			if !d.typed[field] {
+				values = append(values, v)
+			}
+		case float64:
+			kinds["number"] = true
+			values = append(values, v, v-1, v+1, v+0.5)
+		case int:
+			add(float64(v))
+		case int64:
+			add(float64(v))
+		case string:
+			kinds["string"] = true
+			values = append(values, v, v+"x", "x"+v, strings.ToUpper(v))
+			if len(v) > 1 {
+				values = append(values, v[:len(v)-1], v[1:])
+			}
+		case bool:
+			kinds["bool"] = true
+			values = append(values, v, !v)
+		default:
+			if value != nil {
+				values = append(values, value)
+			}
+		}
+	}
+	for _, value := range d.fields[field] {
+		add(value)
+	}
+
+	if d.typed[field] {
+		// Formulas are type checked: stay within the literal kinds
+		switch {
+		case len(kinds) == 0:
+			values = append(values, 0.0, 1.0, -1.0, 2.5, 12.0, "", "abc")
+		case kinds["number"]:
+			values = append(values, 0.0)
+		case kinds["string"]:
+			values = append(values, "")
+		}
+		return values
+	}
+
+	return append(values, nil, missingValue{}, "", 0.0, 1.0, true, false,
+		"2026-01-01", "2026-01-01T12:00:00Z", []any{}, []any{"a", 1.0})
+}
+
+// setDataPath sets a dotted path, creating nested maps
+func setDataPath(data map[string]any, path string, value any) {
+	parts := strings.Split(path, ".")
+	current := data
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := current[part].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			current[part] = next
+		}
+		current = next
+	}
+	current[parts[len(parts)-1]] = value
+}
@@ -0,0 +1,364 @@
+package condition
+
+// JSRuntimeName is the global object that compiled JavaScript predicates
+// call into
+const JSRuntimeName = "ruunCondition"
+
+// JSRuntime is the browser runtime for compiled predicates. Include it once
+// per page before any element that uses a compiled binding:
+//
+//	<script>{ condition.JSRuntime }</script>
+//
+// It mirrors the Evaluator: values are compared numerically, then as
+// times (the same layouts as Evaluator.toTime), then as booleans, and
+// finally as strings formatted like Go's %v. Missing fields throw, like
+// EvalContext.GetValue.
+const JSRuntime = `(function (root) {
+  "use strict";
+  if (root.ruunCondition) { return; }
+
+  var MAX_REGEX_LENGTH = 1000;
+  var regexCache = new Map();
+  var hasOwn = Object.prototype.hasOwnProperty;
+
+  function fail(message) { throw new Error(message); }
+  function isNil(v) { return v === null || v === undefined; }
+
+  function get(data, path) {
+    var parts = String(path).split(".");
+    var current = data;
+    for (var i = 0; i < parts.length; i++) {
+      if (isNil(current)) { fail("field not found: nil value at " + parts.slice(0, i).join(".")); }
+      if (typeof current !== "object" || Array.isArray(current)) { fail("cannot access field " + parts[i] + " on non-map/struct type"); }
+      if (!hasOwn.call(current, parts[i])) { fail("field not found: " + path); }
+      current = current[parts[i]];
+    }
+    return current === undefined ? null : current;
+  }
+
+  // formatFloat formats a number like Go's %v for float64
+  function formatFloat(f) {
+    if (isNaN(f)) { return "NaN"; }
+    if (!isFinite(f)) { return f > 0 ? "+Inf" : "-Inf"; }
+    if (f === 0) { return 1 / f < 0 ? "-0" : "0"; }
+    var exp = f.toExponential();
+    var at = exp.indexOf("e");
+    var e = parseInt(exp.slice(at + 1), 10);
+    if (e < -4 || e >= 6) {
+      var abs = Math.abs(e);
+      return exp.slice(0, at) + "e" + (e < 0 ? "-" : "+") + (abs < 10 ? "0" : "") + abs;
+    }
+    return String(f);
+  }
+
+  // str formats a value like Go's %v
+  function str(v) {
+    if (isNil(v)) { return "<nil>"; }
+    switch (typeof v) {
+      case "string": return v;
+      case "boolean": return v ? "true" : "false";
+      case "number": return formatFloat(v);
+    }
+    if (Array.isArray(v)) { return "[" + v.map(str).join(" ") + "]"; }
+    if (typeof v === "object") {
+      return "map[" + Object.keys(v).sort(compareStrings).map(function (k) { return k + ":" + str(v[k]); }).join(" ") + "]";
+    }
+    return String(v);
+  }
+
+  // compareStrings orders by code point, which matches Go's byte order
+  function compareStrings(a, b) {
+    if (a === b) { return 0; }
+    var ca = Array.from(a), cb = Array.from(b);
+    var n = Math.min(ca.length, cb.length);
+    for (var i = 0; i < n; i++) {
+      var x = ca[i].codePointAt(0), y = cb[i].codePointAt(0);
+      if (x !== y) { return x < y ? -1 : 1; }
+    }
+    return ca.length < cb.length ? -1 : (ca.length > cb.length ? 1 : 0);
+  }
+
+  var DATE = "(\\d{4})-(\\d{2})-(\\d{2})";
+  var CLOCK = "(\\d{1,2}):(\\d{2}):(\\d{2})([.,]\\d+)?";
+  var TIME_LAYOUTS = [
+    { re: new RegExp("^" + DATE + "T" + CLOCK + "(Z|[+-]\\d{2}:\\d{2})$"), date: true, clock: true, zone: true },
+    { re: new RegExp("^" + DATE + "T" + CLOCK + "$"), date: true, clock: true },
+    { re: new RegExp("^" + DATE + "$"), date: true },
+    { re: new RegExp("^" + CLOCK + "$"), clock: true },
+    { re: new RegExp("^" + DATE + " " + CLOCK + "$"), date: true, clock: true }
+  ];
+
+  function daysIn(month, year) {
+    if (month === 2) { return (year % 4 === 0 && (year % 100 !== 0 || year % 400 === 0)) ? 29 : 28; }
+    return [31, 28, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31][month - 1];
+  }
+
+  function daysFromCivil(y, m, d) {
+    y -= m <= 2 ? 1 : 0;
+    var era = Math.floor(y / 400);
+    var yoe = y - era * 400;
+    var doy = Math.floor((153 * (m + (m > 2 ? -3 : 9)) + 2) / 5) + d - 1;
+    var doe = yoe * 365 + Math.floor(yoe / 4) - Math.floor(yoe / 100) + doy;
+    return era * 146097 + doe - 719468;
+  }
+
+  // toTime parses a string into [seconds, nanoseconds] or returns null
+  function toTime(v) {
+    if (typeof v !== "string") { return null; }
+    for (var i = 0; i < TIME_LAYOUTS.length; i++) {
+      var layout = TIME_LAYOUTS[i];
+      var m = layout.re.exec(v);
+      if (!m) { continue; }
+      var g = 1, year = 0, month = 1, day = 1, hour = 0, min = 0, sec = 0, nsec = 0, offset = 0;
+      if (layout.date) {
+        year = +m[g++]; month = +m[g++]; day = +m[g++];
+        if (month < 1 || month > 12 || day < 1 || day > daysIn(month, year)) { continue; }
+      }
+      if (layout.clock) {
+        hour = +m[g++]; min = +m[g++]; sec = +m[g++];
+        var frac = m[g++];
+        if (hour > 23 || min > 59 || sec > 59) { continue; }
+        if (frac) { nsec = +(frac.slice(1, 10) + "000000000").slice(0, 9); }
+      }
+      if (layout.zone) {
+        var zone = m[g++];
+        if (zone !== "Z") {
+          var zh = +zone.slice(1, 3), zm = +zone.slice(4, 6);
+          if (zh > 24 || zm > 60) { continue; }
+          offset = (zh * 60 + zm) * 60 * (zone[0] === "-" ? -1 : 1);
+        }
+      }
+      return [daysFromCivil(year, month, day) * 86400 + hour * 3600 + min * 60 + sec - offset, nsec];
+    }
+    return null;
+  }
+
+  function cmp(a, b) {
+    if (isNil(a) && isNil(b)) { return 0; }
+    if (isNil(a)) { return -1; }
+    if (isNil(b)) { return 1; }
+    if (typeof a === "number" && typeof b === "number") { return a < b ? -1 : (a > b ? 1 : 0); }
+    var ta = toTime(a), tb = toTime(b);
+    if (ta && tb) {
+      if (ta[0] !== tb[0]) { return ta[0] < tb[0] ? -1 : 1; }
+      return ta[1] < tb[1] ? -1 : (ta[1] > tb[1] ? 1 : 0);
+    }
+    if (typeof a === "boolean" && typeof b === "boolean") { return a === b ? 0 : (a ? 1 : -1); }
+    return compareStrings(str(a), str(b));
+  }
+
+  function empty(v) {
+    if (isNil(v)) { return true; }
+    if (typeof v === "string" || Array.isArray(v)) { return v.length === 0; }
+    if (typeof v === "object") { return Object.keys(v).length === 0; }
+    return false;
+  }
+
+  function contains(haystack, needle) {
+    if (isNil(haystack) || isNil(needle)) { return false; }
+    var n = str(needle);
+    if (Array.isArray(haystack)) {
+      return haystack.some(function (item) { return str(item).indexOf(n) >= 0; });
+    }
+    return str(haystack).indexOf(n) >= 0;
+  }
+
+  function compileRegex(pattern) {
+    var re = regexCache.get(pattern);
+    if (!re) {
+      try { re = new RegExp(pattern); } catch (e) { fail("invalid regexp: " + e.message); }
+      regexCache.set(pattern, re);
+    }
+    return re;
+  }
+
+  function matchRegexp(s, pattern) {
+    if (isNil(s) || isNil(pattern)) { return false; }
+    var p = str(pattern);
+    var size = new TextEncoder().encode(p).length;
+    if (size > MAX_REGEX_LENGTH) { fail("regex pattern too complex: pattern length " + size + " exceeds limit " + MAX_REGEX_LENGTH); }
+    return compileRegex(p).test(str(s));
+  }
+
+  // rule applies a condition operator, see Evaluator.applyOperator
+  function rule(op, left, right) {
+    switch (op) {
+      case "is_empty": return empty(left);
+      case "is_not_empty": return !empty(left);
+      case "between": case "not_between":
+        if (right.length !== 2) { fail(op + " requires exactly 2 values, got " + right.length); }
+        break;
+      case "select_any_in": case "select_not_any_in":
+        if (right.length === 0) { fail(op + " requires at least one value"); }
+        break;
+      default:
+        if (right.length === 0) { fail(op + " requires a right value"); }
+    }
+    var r = right[0];
+    switch (op) {
+      case "equal": return cmp(left, r) === 0;
+      case "not_equal": return cmp(left, r) !== 0;
+      case "less": return cmp(left, r) < 0;
+      case "less_or_equal": return cmp(left, r) <= 0;
+      case "greater": return cmp(left, r) > 0;
+      case "greater_or_equal": return cmp(left, r) >= 0;
+      case "between": return cmp(left, right[0]) >= 0 && cmp(left, right[1]) <= 0;
+      case "not_between": return cmp(left, right[0]) < 0 || cmp(left, right[1]) > 0;
+      case "contains": return contains(left, r);
+      case "not_contains": return !contains(left, r);
+      case "starts_with": return !isNil(left) && !isNil(r) && str(left).startsWith(str(r));
+      case "ends_with": return !isNil(left) && !isNil(r) && str(left).endsWith(str(r));
+      case "select_any_in": return right.some(function (item) { return cmp(left, item) === 0; });
+      case "select_not_any_in": return !right.some(function (item) { return cmp(left, item) === 0; });
+      case "match_regexp": return matchRegexp(left, r);
+    }
+    return fail("invalid operator: " + op);
+  }
+
+  // Formula support: the subset of expr-lang the compiler accepts
+
+  function typeName(v) {
+    if (isNil(v)) { return "nil"; }
+    if (Array.isArray(v)) { return "array"; }
+    return typeof v;
+  }
+
+  function fbool(v) {
+    if (typeof v !== "boolean") { fail("expected bool, got " + typeName(v)); }
+    return v;
+  }
+
+  function feq(a, b) {
+    if (isNil(a) || isNil(b)) { return isNil(a) && isNil(b); }
+    if (typeof a === "object" || typeof b === "object") { return JSON.stringify(a) === JSON.stringify(b); }
+    return a === b;
+  }
+
+  function fcmp(op, a, b) {
+    var c;
+    if (typeof a === "number" && typeof b === "number") {
+      c = a < b ? -1 : (a > b ? 1 : 0);
+      if (isNaN(a) || isNaN(b)) { return false; }
+    } else if (typeof a === "string" && typeof b === "string") {
+      c = compareStrings(a, b);
+    } else {
+      fail("invalid operation: " + typeName(a) + " " + op + " " + typeName(b));
+    }
+    switch (op) {
+      case "<": return c < 0;
+      case "<=": return c <= 0;
+      case ">": return c > 0;
+      default: return c >= 0;
+    }
+  }
+
+  function farith(op, a, b) {
+    if (op === "+" && typeof a === "string" && typeof b === "string") { return a + b; }
+    if (typeof a !== "number" || typeof b !== "number") {
+      fail("invalid operation: " + typeName(a) + " " + op + " " + typeName(b));
+    }
+    switch (op) {
+      case "+": return a + b;
+      case "-": return a - b;
+      case "*": return a * b;
+      default: return a / b;
+    }
+  }
+
+  function fneg(op, v) {
+    if (typeof v !== "number") { fail("invalid operation: " + op + typeName(v)); }
+    return op === "-" ? -v : v;
+  }
+
+  function fmember(obj, key) {
+    if (isNil(obj)) { fail("cannot fetch " + key + " from nil"); }
+    if (Array.isArray(obj) && typeof key === "number") {
+      var i = key < 0 ? obj.length + key : key;
+      if (i < 0 || i >= obj.length) { fail("index out of range: " + key + " (array length is " + obj.length + ")"); }
+      return obj[i];
+    }
+    if (typeof obj === "object" && !Array.isArray(obj) && typeof key === "string") {
+      return hasOwn.call(obj, key) ? obj[key] : null;
+    }
+    return fail("cannot fetch " + key + " from " + typeName(obj));
+  }
+
+  function fin(needle, haystack) {
+    if (Array.isArray(haystack)) { return haystack.some(function (item) { return feq(needle, item); }); }
+    if (!isNil(haystack) && typeof haystack === "object") {
+      if (typeof needle !== "string") { fail("cannot use " + typeName(needle) + " as map key"); }
+      return hasOwn.call(haystack, needle);
+    }
+    return fail("operator in not defined on " + typeName(haystack));
+  }
+
+  function fstr(op, a, b) {
+    if (typeof a !== "string" || typeof b !== "string") {
+      fail("invalid operation: " + typeName(a) + " " + op + " " + typeName(b));
+    }
+    switch (op) {
+      case "contains": return a.indexOf(b) >= 0;
+      case "startsWith": return a.startsWith(b);
+      case "endsWith": return a.endsWith(b);
+      default: return compileRegex(b).test(a);
+    }
+  }
+
+  function trimSet(s, cutset) {
+    var cut = Array.from(cutset), chars = Array.from(s);
+    var start = 0, end = chars.length;
+    while (start < end && cut.indexOf(chars[start]) >= 0) { start++; }
+    while (end > start && cut.indexOf(chars[end - 1]) >= 0) { end--; }
+    return chars.slice(start, end).join("");
+  }
+
+  function fcall(name, args) {
+    var v = args[0];
+    switch (name) {
+      case "len":
+        if (typeof v === "string") { return Array.from(v).length; }
+        if (Array.isArray(v)) { return v.length; }
+        if (!isNil(v) && typeof v === "object") { return Object.keys(v).length; }
+        break;
+      case "lower":
+        if (typeof v === "string") { return v.toLowerCase(); }
+        break;
+      case "upper":
+        if (typeof v === "string") { return v.toUpperCase(); }
+        break;
+      case "trim":
+        if (typeof v === "string" && args.length === 1) { return v.trim(); }
+        if (typeof v === "string" && typeof args[1] === "string") { return trimSet(v, args[1]); }
+        break;
+      case "abs":
+        if (typeof v === "number") { return Math.abs(v); }
+        break;
+    }
+    return fail("invalid argument for " + name + " (type " + typeName(v) + ")");
+  }
+
+  // test runs a compiled predicate for a binding; errors count as false,
+  // as they do in Field.IsVisible and friends
+  function test(fn) {
+    try { return fn() === true; } catch (e) { return false; }
+  }
+
+  // evaluate runs a predicate and reports its value or error
+  function evaluate(fn) {
+    try {
+      var value = fn();
+      if (typeof value !== "boolean") { return { error: "non-boolean result " + typeName(value) }; }
+      return { value: value };
+    } catch (e) {
+      return { error: String(e && e.message ? e.message : e) };
+    }
+  }
+
+  root.ruunCondition = {
+    get: get, str: str, cmp: cmp, rule: rule, fail: fail,
+    fbool: fbool, feq: feq, fcmp: fcmp, farith: farith, fneg: fneg,
+    fmember: fmember, fin: fin, fstr: fstr, fcall: fcall,
+    test: test, evaluate: evaluate
+  };
+})(typeof globalThis !== "undefined" ? globalThis : this);
+`
@@ -0,0 +1,199 @@
+package condition_test
+
+import (
+	"context"
+	"encoding/json"
+	"os/exec"
+	"strings"
+	"testing"
+
+	cb "github.com/niiniyare/ruun/pkg/condition"
+	"github.com/stretchr/testify/suite"
+)
+
+// JSCompilerTestSuite tests translation of condition trees into JavaScript
+type JSCompilerTestSuite struct {
+	suite.Suite
+	ctx       context.Context
+	evaluator *cb.Evaluator
+}
+
+func (s *JSCompilerTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.evaluator = cb.NewEvaluator(&cb.Config{}, cb.DefaultEvalOptions())
+}
+
+// equivalence returns a checker, skipping when Node.js is not installed
+func (s *JSCompilerTestSuite) equivalence() *cb.JSEquivalence {
+	if _, err := exec.LookPath("node"); err != nil {
+		s.T().Skip("node not installed")
+	}
+	return cb.NewJSEquivalence(s.evaluator)
+}
+
+func (s *JSCompilerTestSuite) TestCompile() {
+	root := cb.NewBuilder(cb.ConjunctionAnd).
+		AddRule("status", cb.OpEqual, "open").
+		AddBetweenRule("total", 10, 100).
+		Build()
+
+	js, err := cb.CompileJS(root, cb.JSOptions{})
+	s.Require().NoError(err)
+	s.Contains(js, `ruunCondition.rule("equal", ruunCondition.get(formData, "status"), ["open"])`)
+	s.Contains(js, `ruunCondition.rule("between", ruunCondition.get(formData, "total"), [10, 100])`)
+	s.Contains(js, " && ")
+
+	compiler, err := cb.NewJSCompiler(cb.JSOptions{DataVar: "$store.form.formData"})
+	s.Require().NoError(err)
+	predicate, err := compiler.Predicate(root)
+	s.Require().NoError(err)
+	s.Contains(predicate, "ruunCondition.test(() => ")
+	s.Contains(predicate, "ruunCondition.get($store.form.formData, ")
+}
+
+func (s *JSCompilerTestSuite) TestRefusals() {
+	tests := []struct {
+		name string
+		root *cb.ConditionGroup
+	}{
+		{
+			name: "function_expression",
+			root: &cb.ConditionGroup{ID: "g", Conjunction: cb.ConjunctionAnd, Children: []any{
+				cb.ConditionRule{
+					ID:    "r",
+					Left:  cb.Expression{Type: cb.ValueTypeField, Field: "created"},
+					Op:    cb.OpLess,
+					Right: cb.Expression{Type: cb.ValueTypeFunc, Func: &cb.FuncCall{Type: "now"}},
+				},
+			}},
+		},
+		{name: "now", root: cb.NewBuilder(cb.ConjunctionAnd).AddFormula("created < now()").Build()},
+		{name: "env", root: cb.NewBuilder(cb.ConjunctionAnd).SetFormula(`$env.user == "x"`).Build()},
+		{name: "unknown_builtin", root: cb.NewBuilder(cb.ConjunctionAnd).AddFormula("sum(items) > 1").Build()},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			_, err := cb.CompileJS(tt.root, cb.JSOptions{})
+			s.ErrorIs(err, cb.ErrJSUnsupported)
+		})
+	}
+
+	_, err := cb.NewJSCompiler(cb.JSOptions{DataVar: "formData; alert(1)"})
+	s.ErrorIs(err, cb.ErrValidation)
+}
+
+func (s *JSCompilerTestSuite) TestEquivalence() {
+	equivalence := s.equivalence()
+
+	tests := []struct {
+		name string
+		root any
+	}{
+		{
+			name: "comparisons",
+			root: cb.NewBuilder(cb.ConjunctionAnd).
+				AddRule("status", cb.OpNotEqual, "void").
+				AddRule("total", cb.OpGreaterOrEqual, 10).
+				AddFieldComparison("total", "limit", cb.OpLess).
+				Build(),
+		},
+		{
+			name: "text",
+			root: cb.NewBuilder(cb.ConjunctionOr).
+				AddRule("name", cb.OpContains, "cme").
+				AddRule("name", cb.OpStartsWith, "AC").
+				AddRule("code", cb.OpEndsWith, "-01").
+				AddRule("code", cb.OpMatchRegexp, `^[A-Z]{2}-\d+$`).
+				Build(),
+		},
+		{
+			name: "sets_and_ranges",
+			root: cb.NewBuilder(cb.ConjunctionAnd).
+				AddInRule("status", "open", "paid").
+				AddRule("tags", cb.OpNotIn, []any{"vip", "new"}).
+				AddBetweenRule("total", 1.5, 99).
+				Not().
+				Build(),
+		},
+		{
+			name: "dates_and_emptiness",
+			root: cb.NewBuilder(cb.ConjunctionOr).
+				AddRule("due", cb.OpLess, "2026-03-01").
+				AddRule("note", cb.OpIsEmpty, nil).
+				AddRule("approved", cb.OpEqual, true).
+				Build(),
+		},
+		{
+			name: "formulas",
+			root: cb.NewBuilder(cb.ConjunctionAnd).
+				SetFormula(`qty * price > 100 && status in ["open", "paid"]`).
+				AddFormula(`len(name) >= 3 || -discount < 2`).
+				Build(),
+		},
+		{
+			name: "nested_paths",
+			root: cb.NewBuilder(cb.ConjunctionAnd).
+				AddRule("customer.tier", cb.OpEqual, "gold").
+				AddFormula(`customer.age >= 18`).
+				Build(),
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			report, err := equivalence.Check(s.ctx, tt.root)
+			s.Require().NoError(err)
+			s.Equal(200, report.Cases)
+			s.NoError(report.Err())
+		})
+	}
+}
+
+func (s *JSCompilerTestSuite) TestEquivalenceJSONConditions() {
+	equivalence := s.equivalence()
+
+	var root map[string]any
+	s.Require().NoError(json.Unmarshal([]byte(`{
+		"id": "root",
+		"conjunction": "and",
+		"children": [
+			{"id": "r1", "left": {"type": "field", "field": "amount"}, "op": "greater", "right": {"type": "value", "value": 1000000}},
+			{"id": "r2", "left": {"type": "field", "field": "label"}, "op": "equal", "right": "1e+06"}
+		]
+	}`), &root))
+
+	report, err := equivalence.Check(s.ctx, root,
+		map[string]any{"amount": 2e6, "label": 1e6},
+		map[string]any{"amount": 0.00001, "label": "1e+06"},
+	)
+	s.Require().NoError(err)
+	s.Equal(202, report.Cases)
+	s.NoError(report.Err())
+}
+
+// negatingRunner flips every JavaScript result
+type negatingRunner struct {
+	cb.NodeRunner
+}
+
+func (r negatingRunner) RunJS(ctx context.Context, script string) ([]byte, error) {
+	return r.NodeRunner.RunJS(ctx, strings.Replace(script, "return predicate(data)", "return !predicate(data)", 1))
+}
+
+func (s *JSCompilerTestSuite) TestEquivalenceReportsMismatches() {
+	equivalence := s.equivalence()
+	equivalence.Runner = negatingRunner{}
+	equivalence.Samples = 20
+
+	root := cb.NewBuilder(cb.ConjunctionAnd).AddRule("status", cb.OpEqual, "open").Build()
+	report, err := equivalence.Check(s.ctx, root, map[string]any{"status": "open"})
+	s.Require().NoError(err)
+	s.False(report.OK())
+	s.Equal(map[string]any{"status": "open"}, report.Mismatches[len(report.Mismatches)-1].Data)
+	s.ErrorContains(report.Err(), "differ between Go and JavaScript")
+}
+
+func TestJSCompilerSuite(t *testing.T) {
+	suite.Run(t, new(JSCompilerTestSuite))
+}
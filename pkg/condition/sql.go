@@ -0,0 +1,632 @@
+package condition
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SQL errors
+var (
+	ErrSQLUnsupported  = errors.New("condition cannot be translated to SQL")
+	ErrFieldNotAllowed = errors.New("field not allowed")
+)
+
+// SQLDialect identifies the database a WHERE clause is generated for
+type SQLDialect string
+
+const (
+	DialectPostgres SQLDialect = "postgres"
+	DialectMySQL    SQLDialect = "mysql"
+	DialectSQLite   SQLDialect = "sqlite"
+)
+
+// Supports reports whether the dialect can express an operator. SQLite has
+// no built-in REGEXP implementation, so match_regexp is refused there.
+func (d SQLDialect) Supports(op OperatorType) bool {
+	switch d {
+	case DialectPostgres, DialectMySQL:
+		return isValidOperator(op)
+	case DialectSQLite:
+		return isValidOperator(op) && op != OpMatchRegexp
+	default:
+		return false
+	}
+}
+
+// placeholder returns the bind parameter for the n-th argument (1-based)
+func (d SQLDialect) placeholder(n int) string {
+	if d == DialectPostgres {
+		return "$" + strconv.Itoa(n)
+	}
+	return "?"
+}
+
+// quoteIdent quotes a single identifier
+func (d SQLDialect) quoteIdent(name string) string {
+	if d == DialectMySQL {
+		return "`" + name + "`"
+	}
+	return `"` + name + `"`
+}
+
+// SQLColumn maps a condition field to a database column
+type SQLColumn struct {
+	// Column is the column name, optionally qualified ("orders.total").
+	// Defaults to the field name.
+	Column string `json:"column,omitempty"`
+	// Type selects type-specific SQL, e.g. is_empty also matches '' for
+	// text columns. Defaults to text.
+	Type FieldType `json:"type,omitempty"`
+	// Operators restricts the operators allowed on the column. Empty
+	// allows every operator the dialect supports.
+	Operators []OperatorType `json:"operators,omitempty"`
+}
+
+// SQLOptions configures SQL generation
+type SQLOptions struct {
+	Dialect SQLDialect
+	// Columns is the whitelist of filterable fields. Rules on any other
+	// field are refused.
+	Columns map[string]SQLColumn
+	// ArgOffset is the number of bind arguments already in the query, so
+	// PostgreSQL placeholders continue at $ArgOffset+1
+	ArgOffset int
+	MaxDepth  int
+}
+
+// SQLFragment is a parameterised WHERE clause fragment
+type SQLFragment struct {
+	SQL  string
+	Args []any
+}
+
+// SQLCompiler translates condition trees into parameterised SQL.
+// Values are always bound as arguments; only whitelisted, quoted column
+// names are written into the SQL text.
+//
+// Formulas (If) cannot be pushed down to the database and are refused, as
+// are function expressions. Regular expressions are passed to the
+// database engine, whose syntax may differ from regexp2 for advanced
+// constructs.
+type SQLCompiler struct {
+	opts    SQLOptions
+	columns map[string]sqlColumn
+}
+
+type sqlColumn struct {
+	SQLColumn
+	quoted string
+}
+
+// identPattern matches plain or qualified SQL identifiers
+var identPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// NewSQLCompiler creates a compiler for the dialect and column mapping
+func NewSQLCompiler(opts SQLOptions) (*SQLCompiler, error) {
+	switch opts.Dialect {
+	case DialectPostgres, DialectMySQL, DialectSQLite:
+	default:
+		return nil, fmt.Errorf("%w: unknown SQL dialect %q", ErrValidation, opts.Dialect)
+	}
+	if opts.MaxDepth == 0 {
+		opts.MaxDepth = DefaultMaxDepth
+	}
+	if opts.ArgOffset < 0 {
+		return nil, fmt.Errorf("%w: negative argument offset", ErrValidation)
+	}
+
+	columns := make(map[string]sqlColumn, len(opts.Columns))
+	for field, column := range opts.Columns {
+		name := column.Column
+		if name == "" {
+			name = field
+		}
+		if !identPattern.MatchString(name) {
+			return nil, fmt.Errorf("%w: invalid column %q for field %s", ErrValidation, name, field)
+		}
+		if column.Type == "" {
+			column.Type = FieldTypeText
+		}
+		for _, op := range column.Operators {
+			if !isValidOperator(op) {
+				return nil, fmt.Errorf("%w: unknown operator %s for field %s", ErrInvalidOperator, op, field)
+			}
+		}
+		parts := strings.Split(name, ".")
+		for i, part := range parts {
+			parts[i] = opts.Dialect.quoteIdent(part)
+		}
+		columns[field] = sqlColumn{SQLColumn: column, quoted: strings.Join(parts, ".")}
+	}
+
+	return &SQLCompiler{opts: opts, columns: columns}, nil
+}
+
+// CompileSQL is a convenience wrapper around NewSQLCompiler and Compile
+func CompileSQL(root any, opts SQLOptions) (SQLFragment, error) {
+	compiler, err := NewSQLCompiler(opts)
+	if err != nil {
+		return SQLFragment{}, err
+	}
+	return compiler.Compile(root)
+}
+
+// Compile translates a condition tree (*ConditionGroup, ConditionRule or
+// their JSON map form) into a WHERE clause fragment
+func (c *SQLCompiler) Compile(root any) (SQLFragment, error) {
+	if root == nil {
+		return SQLFragment{}, fmt.Errorf("%w: root condition is nil", ErrInvalidExpression)
+	}
+	w := &sqlWriter{compiler: c, args: make([]any, 0)}
+	clause, err := w.node(root, 0)
+	if err != nil {
+		return SQLFragment{}, err
+	}
+	return SQLFragment{SQL: clause, Args: w.args}, nil
+}
+
+// sqlWriter accumulates bind arguments while a tree is compiled
+type sqlWriter struct {
+	compiler *SQLCompiler
+	args     []any
+}
+
+// bind adds an argument and returns its placeholder
+func (w *sqlWriter) bind(value any) string {
+	w.args = append(w.args, sqlValue(value))
+	return w.compiler.opts.Dialect.placeholder(w.compiler.opts.ArgOffset + len(w.args))
+}
+
+func (w *sqlWriter) node(node any, depth int) (string, error) {
+	if depth > w.compiler.opts.MaxDepth {
+		return "", fmt.Errorf("%w: depth %d exceeds limit %d", ErrMaxDepthExceeded, depth, w.compiler.opts.MaxDepth)
+	}
+
+	switch v := node.(type) {
+	case *ConditionGroup:
+		return w.group(v, depth)
+	case ConditionGroup:
+		return w.group(&v, depth)
+	case *ConditionRule:
+		return w.rule(v)
+	case ConditionRule:
+		return w.rule(&v)
+	case map[string]any:
+		id, _ := v["id"].(string)
+		if id == "" {
+			id = "unknown"
+		}
+		if _, hasConjunction := v["conjunction"]; hasConjunction {
+			var group ConditionGroup
+			if err := mapToStruct(v, &group); err != nil {
+				return "", fmt.Errorf("group %s: invalid structure: %w", id, err)
+			}
+			return w.group(&group, depth)
+		}
+		var rule ConditionRule
+		if err := mapToStruct(v, &rule); err != nil {
+			return "", fmt.Errorf("rule %s: invalid structure: %w", id, err)
+		}
+		return w.rule(&rule)
+	default:
+		return "", fmt.Errorf("%w: unknown node type %T", ErrInvalidExpression, node)
+	}
+}
+
+func (w *sqlWriter) group(group *ConditionGroup, depth int) (string, error) {
+	if err := group.Validate(); err != nil {
+		return "", fmt.Errorf("group %s: %w", group.ID, err)
+	}
+	if group.If != "" {
+		return "", fmt.Errorf("group %s: %w: formulas cannot be pushed down", group.ID, ErrSQLUnsupported)
+	}
+
+	joiner := " AND "
+	if group.Conjunction == ConjunctionOr {
+		joiner = " OR "
+	}
+	clauses := make([]string, 0, len(group.Children))
+	for i, child := range group.Children {
+		clause, err := w.node(child, depth+1)
+		if err != nil {
+			return "", fmt.Errorf("group %s: child %d: %w", group.ID, i, err)
+		}
+		clauses = append(clauses, clause)
+	}
+
+	clause := "(" + strings.Join(clauses, joiner) + ")"
+	if group.Not {
+		clause = "NOT " + clause
+	}
+	return clause, nil
+}
+
+func (w *sqlWriter) rule(rule *ConditionRule) (string, error) {
+	if err := rule.Validate(); err != nil {
+		return "", fmt.Errorf("rule %s: %w", rule.ID, err)
+	}
+	if rule.If != "" {
+		return "", fmt.Errorf("rule %s: %w: formulas cannot be pushed down", rule.ID, ErrSQLUnsupported)
+	}
+	clause, err := w.comparison(rule)
+	if err != nil {
+		return "", fmt.Errorf("rule %s: %w", rule.ID, err)
+	}
+	return clause, nil
+}
+
+// comparison renders the operator of a validated rule
+func (w *sqlWriter) comparison(rule *ConditionRule) (string, error) {
+	dialect := w.compiler.opts.Dialect
+	if !dialect.Supports(rule.Op) {
+		return "", fmt.Errorf("%w: operator %s is not supported by %s", ErrSQLUnsupported, rule.Op, dialect)
+	}
+
+	// Operator restrictions apply to the column on the left
+	var column *sqlColumn
+	if rule.Left.Type == ValueTypeField {
+		col, err := w.column(rule.Left.Field)
+		if err != nil {
+			return "", err
+		}
+		if len(col.Operators) > 0 && !containsOperator(col.Operators, rule.Op) {
+			return "", fmt.Errorf("%w: operator %s is not allowed on field %s", ErrInvalidOperator, rule.Op, rule.Left.Field)
+		}
+		column = &col
+	}
+	left, err := w.operand(rule.Left)
+	if err != nil {
+		return "", fmt.Errorf("left expression: %w", err)
+	}
+	textual := column == nil || isTextColumn(column.Type)
+
+	switch rule.Op {
+	case OpIsEmpty:
+		if textual {
+			return fmt.Sprintf("(%s IS NULL OR %s = '')", left, left), nil
+		}
+		return left + " IS NULL", nil
+	case OpIsNotEmpty:
+		if textual {
+			return fmt.Sprintf("(%s IS NOT NULL AND %s <> '')", left, left), nil
+		}
+		return left + " IS NOT NULL", nil
+	}
+
+	right, err := ruleOperands(rule.Right)
+	if err != nil {
+		return "", fmt.Errorf("right expression: %w", err)
+	}
+
+	switch rule.Op {
+	case OpBetween, OpNotBetween:
+		if len(right) != 2 {
+			return "", fmt.Errorf("%s requires exactly 2 values, got %d", rule.Op, len(right))
+		}
+		low, err := w.operand(right[0])
+		if err != nil {
+			return "", fmt.Errorf("right expression: %w", err)
+		}
+		high, err := w.operand(right[1])
+		if err != nil {
+			return "", fmt.Errorf("right expression: %w", err)
+		}
+		keyword := "BETWEEN"
+		if rule.Op == OpNotBetween {
+			keyword = "NOT BETWEEN"
+		}
+		return fmt.Sprintf("%s %s %s AND %s", left, keyword, low, high), nil
+
+	case OpIn, OpNotIn:
+		right = expandSliceOperands(right)
+		if len(right) == 0 {
+			return "", fmt.Errorf("%s requires at least one value", rule.Op)
+		}
+		items := make([]string, len(right))
+		for i, expr := range right {
+			if items[i], err = w.operand(expr); err != nil {
+				return "", fmt.Errorf("right expression: item %d: %w", i, err)
+			}
+		}
+		keyword := "IN"
+		if rule.Op == OpNotIn {
+			keyword = "NOT IN"
+		}
+		return fmt.Sprintf("%s %s (%s)", left, keyword, strings.Join(items, ", ")), nil
+	}
+
+	if len(right) == 0 {
+		return "", fmt.Errorf("%s requires a right value", rule.Op)
+	}
+	operand := right[0]
+
+	switch rule.Op {
+	case OpEqual, OpNotEqual:
+		if operand.Type == ValueTypeValue && operand.Value == nil {
+			if rule.Op == OpEqual {
+				return left + " IS NULL", nil
+			}
+			return left + " IS NOT NULL", nil
+		}
+		fallthrough
+	case OpLess, OpLessOrEqual, OpGreater, OpGreaterOrEqual:
+		value, err := w.operand(operand)
+		if err != nil {
+			return "", fmt.Errorf("right expression: %w", err)
+		}
+		return fmt.Sprintf("%s %s %s", left, sqlComparisons[rule.Op], value), nil
+
+	case OpContains, OpNotContains, OpStartsWith, OpEndsWith:
+		if !textual {
+			return "", fmt.Errorf("%w: %s requires a text column", ErrSQLUnsupported, rule.Op)
+		}
+		clause, err := w.like(left, rule.Op, operand)
+		if err != nil {
+			return "", fmt.Errorf("right expression: %w", err)
+		}
+		return clause, nil
+
+	case OpMatchRegexp:
+		if operand.Type == ValueTypeValue {
+			if pattern := fmt.Sprintf("%v", operand.Value); len(pattern) > MaxRegexPatternLength {
+				return "", fmt.Errorf("%w: pattern length %d exceeds limit %d",
+					ErrRegexComplexity, len(pattern), MaxRegexPatternLength)
+			}
+		}
+		pattern, err := w.operand(operand)
+		if err != nil {
+			return "", fmt.Errorf("right expression: %w", err)
+		}
+		if dialect == DialectMySQL {
+			// 'c' makes the match case-sensitive like regexp2
+			return fmt.Sprintf("REGEXP_LIKE(%s, %s, 'c')", left, pattern), nil
+		}
+		return fmt.Sprintf("%s ~ %s", left, pattern), nil
+
+	default:
+		return "", fmt.Errorf("%w: %s", ErrInvalidOperator, rule.Op)
+	}
+}
+
+// sqlComparisons maps comparison operators to SQL
+var sqlComparisons = map[OperatorType]string{
+	OpEqual:          "=",
+	OpNotEqual:       "<>",
+	OpLess:           "<",
+	OpLessOrEqual:    "<=",
+	OpGreater:        ">",
+	OpGreaterOrEqual: ">=",
+}
+
+// like renders the substring operators case-sensitively, as the evaluator
+// matches them. Literal values are escaped into a LIKE pattern; field
+// operands use position functions instead.
+func (w *sqlWriter) like(left string, op OperatorType, operand Expression) (string, error) {
+	dialect := w.compiler.opts.Dialect
+
+	if operand.Type == ValueTypeValue {
+		needle := fmt.Sprintf("%v", operand.Value)
+		if operand.Value == nil {
+			// The evaluator never matches a nil needle
+			if op == OpNotContains {
+				return "1 = 1", nil
+			}
+			return "1 = 0", nil
+		}
+
+		if dialect == DialectSQLite {
+			// SQLite LIKE ignores ASCII case; instr and substr do not.
+			// Positional ? parameters need one argument per occurrence.
+			return w.sqlitePosition(left, op, func() string { return w.bind(needle) }), nil
+		}
+
+		pattern := escapeLike(needle)
+		switch op {
+		case OpContains, OpNotContains:
+			pattern = "%" + pattern + "%"
+		case OpStartsWith:
+			pattern += "%"
+		case OpEndsWith:
+			pattern = "%" + pattern
+		}
+		placeholder := w.bind(pattern)
+		keyword := "LIKE"
+		if op == OpNotContains {
+			keyword = "NOT LIKE"
+		}
+		if dialect == DialectMySQL {
+			// Binary comparison keeps MySQL's case-insensitive collations out
+			// of the match; backslash is MySQL's default LIKE escape
+			return fmt.Sprintf("CAST(%s AS BINARY) %s CAST(%s AS BINARY)", left, keyword, placeholder), nil
+		}
+		return fmt.Sprintf("%s %s %s ESCAPE '\\'", left, keyword, placeholder), nil
+	}
+
+	needle, err := w.operand(operand)
+	if err != nil {
+		return "", err
+	}
+	switch dialect {
+	case DialectSQLite:
+		return w.sqlitePosition(left, op, func() string { return needle }), nil
+	case DialectMySQL:
+		left, needle = fmt.Sprintf("CAST(%s AS BINARY)", left), fmt.Sprintf("CAST(%s AS BINARY)", needle)
+		switch op {
+		case OpContains:
+			return fmt.Sprintf("LOCATE(%s, %s) > 0", needle, left), nil
+		case OpNotContains:
+			return fmt.Sprintf("LOCATE(%s, %s) = 0", needle, left), nil
+		case OpStartsWith:
+			return fmt.Sprintf("LEFT(%s, LENGTH(%s)) = %s", left, needle, needle), nil
+		default:
+			return fmt.Sprintf("RIGHT(%s, LENGTH(%s)) = %s", left, needle, needle), nil
+		}
+	default:
+		switch op {
+		case OpContains:
+			return fmt.Sprintf("strpos(%s, %s) > 0", left, needle), nil
+		case OpNotContains:
+			return fmt.Sprintf("strpos(%s, %s) = 0", left, needle), nil
+		case OpStartsWith:
+			return fmt.Sprintf("left(%s, length(%s)) = %s", left, needle, needle), nil
+		default:
+			return fmt.Sprintf("right(%s, length(%s)) = %s", left, needle, needle), nil
+		}
+	}
+}
+
+// sqlitePosition renders substring operators with SQLite's case-sensitive
+// string functions. needle is called for every occurrence of the operand.
+func (w *sqlWriter) sqlitePosition(left string, op OperatorType, needle func() string) string {
+	switch op {
+	case OpContains:
+		return fmt.Sprintf("instr(%s, %s) > 0", left, needle())
+	case OpNotContains:
+		return fmt.Sprintf("instr(%s, %s) = 0", left, needle())
+	case OpStartsWith:
+		return fmt.Sprintf("substr(%s, 1, length(%s)) = %s", left, needle(), needle())
+	default:
+		// An empty suffix always matches; substr(x, -0) would not
+		return fmt.Sprintf("(length(%s) = 0 OR substr(%s, -length(%s)) = %s)", needle(), left, needle(), needle())
+	}
+}
+
+// operand renders an expression as a quoted column or a bind parameter
+func (w *sqlWriter) operand(expr Expression) (string, error) {
+	if err := expr.Validate(); err != nil {
+		return "", err
+	}
+	switch expr.Type {
+	case ValueTypeValue:
+		return w.bind(expr.Value), nil
+	case ValueTypeField:
+		column, err := w.column(expr.Field)
+		if err != nil {
+			return "", err
+		}
+		return column.quoted, nil
+	case ValueTypeFunc:
+		return "", fmt.Errorf("%w: function %s cannot be pushed down", ErrSQLUnsupported, expr.Func.Type)
+	default:
+		return "", fmt.Errorf("%w: unknown type %s", ErrInvalidExpression, expr.Type)
+	}
+}
+
+// column looks up a whitelisted field
+func (w *sqlWriter) column(field string) (sqlColumn, error) {
+	column, ok := w.compiler.columns[field]
+	if !ok {
+		return sqlColumn{}, fmt.Errorf("%w: %s", ErrFieldNotAllowed, field)
+	}
+	return column, nil
+}
+
+// ruleOperands normalises the right side of a rule the same way
+// Evaluator.evaluateRightExpression does, without evaluating it
+func ruleOperands(right any) ([]Expression, error) {
+	switch v := right.(type) {
+	case nil:
+		return nil, errors.New("right expression is nil")
+	case []any:
+		if len(v) == 0 {
+			return nil, nil
+		}
+		switch v[0].(type) {
+		case Expression, map[string]any:
+			operands := make([]Expression, len(v))
+			for i, item := range v {
+				expr, err := toOperand(item)
+				if err != nil {
+					return nil, fmt.Errorf("item %d: %w", i, err)
+				}
+				operands[i] = expr
+			}
+			return operands, nil
+		default:
+			operands := make([]Expression, len(v))
+			for i, item := range v {
+				operands[i] = Expression{Type: ValueTypeValue, Value: item}
+			}
+			return operands, nil
+		}
+	default:
+		expr, err := toOperand(v)
+		if err != nil {
+			return nil, err
+		}
+		return []Expression{expr}, nil
+	}
+}
+
+func toOperand(v any) (Expression, error) {
+	switch value := v.(type) {
+	case Expression:
+		return value, nil
+	case map[string]any:
+		var expr Expression
+		if err := mapToStruct(value, &expr); err != nil {
+			return Expression{}, fmt.Errorf("invalid expression: %w", err)
+		}
+		return expr, nil
+	default:
+		return Expression{Type: ValueTypeValue, Value: v}, nil
+	}
+}
+
+// expandSliceOperands flattens a single slice value ({"type": "value",
+// "value": [...]}) into one operand per item, since a slice cannot be bound
+// as a scalar
+func expandSliceOperands(operands []Expression) []Expression {
+	if len(operands) != 1 || operands[0].Type != ValueTypeValue || operands[0].Value == nil {
+		return operands
+	}
+	rv := reflect.ValueOf(operands[0].Value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return operands
+	}
+	if rv.Type().Elem().Kind() == reflect.Uint8 {
+		return operands // []byte is a scalar
+	}
+	expanded := make([]Expression, rv.Len())
+	for i := range expanded {
+		expanded[i] = Expression{Type: ValueTypeValue, Value: rv.Index(i).Interface()}
+	}
+	return expanded
+}
+
+// sqlValue converts values drivers do not understand
+func sqlValue(value any) any {
+	switch v := value.(type) {
+	case Decimal:
+		return v.String()
+	case *Decimal:
+		if v == nil {
+			return nil
+		}
+		return v.String()
+	default:
+		return value
+	}
+}
+
+// escapeLike escapes LIKE wildcards with backslashes
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+func isTextColumn(fieldType FieldType) bool {
+	return fieldType == FieldTypeText || fieldType == FieldTypeSelect
+}
+
+func containsOperator(ops []OperatorType, op OperatorType) bool {
+	for _, candidate := range ops {
+		if candidate == op {
+			return true
+		}
+	}
+	return false
+}
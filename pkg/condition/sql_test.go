@@ -0,0 +1,215 @@
+package condition_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	cb "github.com/niiniyare/ruun/pkg/condition"
+	"github.com/stretchr/testify/suite"
+)
+
+// SQLCompilerTestSuite tests translation of condition trees into SQL
+type SQLCompilerTestSuite struct {
+	suite.Suite
+	columns map[string]cb.SQLColumn
+}
+
+func (s *SQLCompilerTestSuite) SetupTest() {
+	s.columns = map[string]cb.SQLColumn{
+		"status":  {Column: "orders.status", Type: cb.FieldTypeSelect},
+		"total":   {Column: "grand_total", Type: cb.FieldTypeNumber},
+		"limit":   {Column: "credit_limit", Type: cb.FieldTypeNumber},
+		"name":    {Type: cb.FieldTypeText},
+		"created": {Column: "created_at", Type: cb.FieldTypeDateTime},
+		"note":    {Type: cb.FieldTypeText, Operators: []cb.OperatorType{cb.OpIsEmpty, cb.OpIsNotEmpty}},
+	}
+}
+
+func (s *SQLCompilerTestSuite) compile(dialect cb.SQLDialect, root any) (cb.SQLFragment, error) {
+	return cb.CompileSQL(root, cb.SQLOptions{Dialect: dialect, Columns: s.columns})
+}
+
+func (s *SQLCompilerTestSuite) TestDialects() {
+	inner := cb.NewBuilder(cb.ConjunctionOr).
+		AddRule("name", cb.OpStartsWith, "ACME_").
+		AddRule("note", cb.OpIsEmpty, nil).
+		Build()
+	root := cb.NewBuilder(cb.ConjunctionAnd).
+		AddInRule("status", "open", "paid").
+		AddBetweenRule("total", 10, 100).
+		AddFieldComparison("total", "limit", cb.OpLessOrEqual).
+		AddGroup(inner).
+		Not().
+		Build()
+
+	tests := []struct {
+		dialect  cb.SQLDialect
+		expected string
+	}{
+		{
+			dialect: cb.DialectPostgres,
+			expected: `NOT ("orders"."status" IN ($1, $2) AND "grand_total" BETWEEN $3 AND $4 AND "grand_total" <= "credit_limit"` +
+				` AND ("name" LIKE $5 ESCAPE '\' OR ("note" IS NULL OR "note" = '')))`,
+		},
+		{
+			dialect: cb.DialectMySQL,
+			expected: "NOT (`orders`.`status` IN (?, ?) AND `grand_total` BETWEEN ? AND ? AND `grand_total` <= `credit_limit`" +
+				" AND (CAST(`name` AS BINARY) LIKE CAST(? AS BINARY) OR (`note` IS NULL OR `note` = '')))",
+		},
+		{
+			dialect: cb.DialectSQLite,
+			expected: `NOT ("orders"."status" IN (?, ?) AND "grand_total" BETWEEN ? AND ? AND "grand_total" <= "credit_limit"` +
+				` AND (substr("name", 1, length(?)) = ? OR ("note" IS NULL OR "note" = '')))`,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(string(tt.dialect), func() {
+			fragment, err := s.compile(tt.dialect, root)
+			s.Require().NoError(err)
+			s.Equal(tt.expected, fragment.SQL)
+			if tt.dialect == cb.DialectSQLite {
+				s.Equal([]any{"open", "paid", 10, 100, "ACME_", "ACME_"}, fragment.Args)
+			} else {
+				s.Equal([]any{"open", "paid", 10, 100, `ACME\_%`}, fragment.Args)
+			}
+		})
+	}
+}
+
+func (s *SQLCompilerTestSuite) TestOperators() {
+	tests := []struct {
+		name     string
+		field    string
+		op       cb.OperatorType
+		value    any
+		expected string
+		args     []any
+	}{
+		{name: "equal_nil", field: "name", op: cb.OpEqual, value: nil, expected: `"name" IS NULL`, args: []any{}},
+		{name: "not_equal", field: "total", op: cb.OpNotEqual, value: 5, expected: `"grand_total" <> $1`, args: []any{5}},
+		{name: "contains", field: "name", op: cb.OpContains, value: "50%", expected: `"name" LIKE $1 ESCAPE '\'`, args: []any{`%50\%%`}},
+		{name: "not_contains", field: "name", op: cb.OpNotContains, value: "x", expected: `"name" NOT LIKE $1 ESCAPE '\'`, args: []any{"%x%"}},
+		{name: "ends_with", field: "name", op: cb.OpEndsWith, value: "Ltd", expected: `"name" LIKE $1 ESCAPE '\'`, args: []any{"%Ltd"}},
+		{name: "is_not_empty_number", field: "total", op: cb.OpIsNotEmpty, expected: `"grand_total" IS NOT NULL`, args: []any{}},
+		{name: "regexp", field: "name", op: cb.OpMatchRegexp, value: "^A.*", expected: `"name" ~ $1`, args: []any{"^A.*"}},
+		{name: "decimal_value", field: "total", op: cb.OpGreater, value: cb.MustDecimal("10.50"), expected: `"grand_total" > $1`, args: []any{"10.5"}},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			root := cb.NewBuilder(cb.ConjunctionAnd).AddRule(tt.field, tt.op, tt.value).Build()
+			fragment, err := s.compile(cb.DialectPostgres, root)
+			s.Require().NoError(err)
+			s.Equal("("+tt.expected+")", fragment.SQL)
+			s.Equal(tt.args, fragment.Args)
+		})
+	}
+}
+
+func (s *SQLCompilerTestSuite) TestJSONConditions() {
+	var root map[string]any
+	s.Require().NoError(json.Unmarshal([]byte(`{
+		"id": "root",
+		"conjunction": "or",
+		"children": [
+			{"id": "r1", "left": {"type": "field", "field": "status"}, "op": "select_not_any_in", "right": {"type": "value", "value": ["void", "draft"]}},
+			{"id": "r2", "left": {"type": "field", "field": "created"}, "op": "greater_or_equal", "right": "2026-01-01"}
+		]
+	}`), &root))
+
+	compiler, err := cb.NewSQLCompiler(cb.SQLOptions{Dialect: cb.DialectPostgres, Columns: s.columns, ArgOffset: 2})
+	s.Require().NoError(err)
+	fragment, err := compiler.Compile(root)
+	s.Require().NoError(err)
+	s.Equal(`("orders"."status" NOT IN ($3, $4) OR "created_at" >= $5)`, fragment.SQL)
+	s.Equal([]any{"void", "draft", "2026-01-01"}, fragment.Args)
+}
+
+func (s *SQLCompilerTestSuite) TestRefusals() {
+	tests := []struct {
+		name    string
+		dialect cb.SQLDialect
+		root    *cb.ConditionGroup
+		kind    error
+	}{
+		{
+			name:    "field_not_whitelisted",
+			dialect: cb.DialectPostgres,
+			root:    cb.NewBuilder(cb.ConjunctionAnd).AddRule("password", cb.OpEqual, "x").Build(),
+			kind:    cb.ErrFieldNotAllowed,
+		},
+		{
+			name:    "right_field_not_whitelisted",
+			dialect: cb.DialectPostgres,
+			root:    cb.NewBuilder(cb.ConjunctionAnd).AddFieldComparison("total", "cost", cb.OpGreater).Build(),
+			kind:    cb.ErrFieldNotAllowed,
+		},
+		{
+			name:    "rule_formula",
+			dialect: cb.DialectMySQL,
+			root:    cb.NewBuilder(cb.ConjunctionAnd).AddFormula("total > 10").Build(),
+			kind:    cb.ErrSQLUnsupported,
+		},
+		{
+			name:    "group_formula",
+			dialect: cb.DialectMySQL,
+			root:    cb.NewBuilder(cb.ConjunctionAnd).SetFormula("total > 10").Build(),
+			kind:    cb.ErrSQLUnsupported,
+		},
+		{
+			name:    "sqlite_regexp",
+			dialect: cb.DialectSQLite,
+			root:    cb.NewBuilder(cb.ConjunctionAnd).AddRule("name", cb.OpMatchRegexp, "^A").Build(),
+			kind:    cb.ErrSQLUnsupported,
+		},
+		{
+			name:    "substring_on_number",
+			dialect: cb.DialectPostgres,
+			root:    cb.NewBuilder(cb.ConjunctionAnd).AddRule("total", cb.OpContains, "1").Build(),
+			kind:    cb.ErrSQLUnsupported,
+		},
+		{
+			name:    "column_operators",
+			dialect: cb.DialectPostgres,
+			root:    cb.NewBuilder(cb.ConjunctionAnd).AddRule("note", cb.OpEqual, "x").Build(),
+			kind:    cb.ErrInvalidOperator,
+		},
+		{
+			name:    "function_expression",
+			dialect: cb.DialectPostgres,
+			root: &cb.ConditionGroup{ID: "g", Conjunction: cb.ConjunctionAnd, Children: []any{
+				cb.ConditionRule{
+					ID:    "r",
+					Left:  cb.Expression{Type: cb.ValueTypeField, Field: "created"},
+					Op:    cb.OpLess,
+					Right: cb.Expression{Type: cb.ValueTypeFunc, Func: &cb.FuncCall{Type: "now"}},
+				},
+			}},
+			kind: cb.ErrSQLUnsupported,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			_, err := s.compile(tt.dialect, tt.root)
+			s.Require().Error(err)
+			s.ErrorIs(err, tt.kind)
+		})
+	}
+}
+
+func (s *SQLCompilerTestSuite) TestInvalidOptions() {
+	_, err := cb.NewSQLCompiler(cb.SQLOptions{Dialect: "oracle"})
+	s.ErrorIs(err, cb.ErrValidation)
+
+	_, err = cb.NewSQLCompiler(cb.SQLOptions{
+		Dialect: cb.DialectPostgres,
+		Columns: map[string]cb.SQLColumn{"name": {Column: "name; DROP TABLE users"}},
+	})
+	s.ErrorIs(err, cb.ErrValidation)
+}
+
+func TestSQLCompilerSuite(t *testing.T) {
+	suite.Run(t, new(SQLCompilerTestSuite))
+}
@@ -1,6 +1,33 @@
 package config
 
+import (
+	"reflect"
+	"strings"
+)
+
 // FeatureConfig represents feature flags
 type FeatureConfig struct {
 	EnableNewDashboard bool `yaml:"enable_new_dashboard" mapstructure:"enable_new_dashboard"`
 }
+
+// Flags returns the boolean feature flags keyed by their mapstructure name,
+// e.g. "enable_new_dashboard", for consumers such as theme conditions.
+func (f FeatureConfig) Flags() map[string]bool {
+	flags := make(map[string]bool)
+
+	value := reflect.ValueOf(f)
+	typ := value.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.Type.Kind() != reflect.Bool {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("mapstructure"), ",")[0]
+		if name == "" {
+			name = field.Name
+		}
+		flags[name] = value.Field(i).Bool()
+	}
+
+	return flags
+}
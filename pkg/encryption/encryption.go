@@ -10,9 +10,11 @@ import (
 	"fmt"
 	"io"
 	"runtime"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/go-redis/redis/v8"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/argon2"
 )
@@ -26,10 +28,29 @@ type Config struct {
 }
 
 type KeyManagementConfig struct {
-	Provider     string        `yaml:"provider"`
+	Provider     string        `yaml:"provider"` // memory, file or softhsm
 	Region       string        `yaml:"region"`
 	KeyTTL       time.Duration `yaml:"key_ttl"`
 	RotationFreq time.Duration `yaml:"rotation_frequency"`
+
+	// Envelope encryption, used by the file and softhsm providers
+	KeyStore    string `yaml:"key_store"`     // Wrapped data keys: file (default) or redis
+	KeyStoreDir string `yaml:"key_store_dir"` // file: directory of wrapped data keys
+	KEKFile     string `yaml:"kek_file"`      // file: key-encryption key
+	KEKLabel    string `yaml:"kek_label"`     // softhsm: key-encryption key label
+	TokenFile   string `yaml:"token_file"`    // softhsm: token path, empty for memory only
+	TokenPIN    string `yaml:"token_pin"`     // softhsm: token PIN
+
+	// Earlier KEKs; keys they wrapped are rewrapped under the current KEK
+	// when the repository is created
+	PreviousKEKFiles  []string `yaml:"previous_kek_files"`  // file
+	PreviousKEKLabels []string `yaml:"previous_kek_labels"` // softhsm
+
+	// Shared key store for instances on several hosts
+	RedisAddr     string `yaml:"redis_addr"`
+	RedisPassword string `yaml:"redis_password"`
+	RedisDB       int    `yaml:"redis_db"`
+	RedisPrefix   string `yaml:"redis_prefix"` // Default "encryption:"
 }
 
 type EncryptionConfig struct {
@@ -88,6 +109,8 @@ const (
 	ErrCodeDataTooLarge       = "DATA_TOO_LARGE"
 	ErrCodeInvalidEncryption  = "INVALID_ENCRYPTION"
 	ErrCodeServiceUnavailable = "SERVICE_UNAVAILABLE"
+	ErrCodeKeyExists          = "KEY_EXISTS"
+	ErrCodePayloadChanged     = "PAYLOAD_CHANGED"
 )
 
 // Domain Types with enhanced validation
@@ -556,11 +579,14 @@ func (ckr *CachedKeyRepository) ListKeys(ctx context.Context) (map[KeyID][]*Encr
 	return ckr.underlying.ListKeys(ctx)
 }
 
+func (ckr *CachedKeyRepository) LatestKeyMetadata(ctx context.Context) (map[KeyID]KeyMetadata, error) {
+	return latestKeyMetadata(ctx, ckr.underlying)
+}
+
 func (ckr *CachedKeyRepository) RotateKey(ctx context.Context, keyID KeyID) (*EncryptionKey, error) {
 	// Clear all cached versions of this key
 	ckr.cache.Range(func(key, value any) bool {
-		keyStr := key.(string)
-		if keyStr[:len(keyID)] == string(keyID) {
+		if strings.HasPrefix(key.(string), string(keyID)+":") {
 			ckr.cache.Delete(key)
 		}
 		return true
@@ -569,6 +595,15 @@ func (ckr *CachedKeyRepository) RotateKey(ctx context.Context, keyID KeyID) (*En
 	return ckr.underlying.RotateKey(ctx, keyID)
 }
 
+func (ckr *CachedKeyRepository) RetireKey(ctx context.Context, keyID KeyID, version KeyVersion) error {
+	retirer, ok := ckr.underlying.(KeyRetirer)
+	if !ok {
+		return NewEncryptionError(ErrCodeServiceUnavailable, "key repository cannot retire keys", nil, nil)
+	}
+	ckr.cache.Delete(fmt.Sprintf("%s:%d", keyID, version))
+	return retirer.RetireKey(ctx, keyID, version)
+}
+
 func (ckr *CachedKeyRepository) HealthCheck(ctx context.Context) error {
 	return ckr.underlying.HealthCheck(ctx)
 }
@@ -918,6 +953,7 @@ type FieldEncryptionService struct {
 	logger        *zap.Logger
 	metrics       Metrics
 	config        *Config
+	stopRotation  context.CancelFunc
 }
 
 func NewFieldEncryptionService(
@@ -1094,6 +1130,14 @@ func (fes *FieldEncryptionService) BulkEncryptAndStore(ctx context.Context, fiel
 	return nil
 }
 
+// Close stops scheduled key rotation
+func (fes *FieldEncryptionService) Close() error {
+	if fes.stopRotation != nil {
+		fes.stopRotation()
+	}
+	return nil
+}
+
 func (fes *FieldEncryptionService) HealthCheck(ctx context.Context) error {
 	if err := fes.encryptionSvc.HealthCheck(ctx); err != nil {
 		return err
@@ -1277,6 +1321,34 @@ func (kr *SecureInMemoryKeyRepository) RotateKey(ctx context.Context, keyID KeyI
 	return newKey, nil
 }
 
+func (kr *SecureInMemoryKeyRepository) RetireKey(ctx context.Context, keyID KeyID, version KeyVersion) error {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+
+	versions, exists := kr.keys[keyID]
+	if !exists {
+		return NewEncryptionError(ErrCodeKeyNotFound, "key ID not found", nil,
+			map[string]any{"keyID": keyID})
+	}
+	key, exists := versions[version]
+	if !exists {
+		return NewEncryptionError(ErrCodeKeyNotFound, "key version not found", nil,
+			map[string]any{"keyID": keyID, "version": version})
+	}
+	for other := range versions {
+		if other > version {
+			key.Zeroize()
+			delete(versions, version)
+			kr.logger.Info("key retired",
+				zap.String("keyID", string(keyID)),
+				zap.Uint32("version", uint32(version)))
+			return nil
+		}
+	}
+	return NewEncryptionError(ErrCodeInvalidInput, "cannot retire the latest key version", nil,
+		map[string]any{"keyID": keyID, "version": version})
+}
+
 func (kr *SecureInMemoryKeyRepository) HealthCheck(ctx context.Context) error {
 	kr.mu.RLock()
 	defer kr.mu.RUnlock()
@@ -1438,17 +1510,8 @@ func NewFieldEncryptionServiceFactory(config *Config) (*FieldEncryptionService,
 	// Initialize metrics
 	metrics := NewSimpleMetrics()
 
-	// Initialize key derivation service
-	kdf := NewArgon2KeyDerivationService()
-
-	// Generate master key (in production, this would come from secure key management)
-	masterKey := make([]byte, 32)
-	if _, err := rand.Read(masterKey); err != nil {
-		return nil, fmt.Errorf("failed to generate master key: %w", err)
-	}
-
 	// Initialize key repository
-	keyRepo, err := NewSecureInMemoryKeyRepository(masterKey, kdf, logger, metrics)
+	keyRepo, err := newKeyRepository(config.KeyManagement, logger, metrics)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create key repository: %w", err)
 	}
@@ -1466,7 +1529,79 @@ func NewFieldEncryptionServiceFactory(config *Config) (*FieldEncryptionService,
 	encryptionSvc := NewEncryptionService(finalKeyRepo, config.Encryption.MaxDataSize, logger, metrics)
 
 	// Initialize field encryption service
-	return NewFieldEncryptionService(encryptionSvc, fieldRepo, finalKeyRepo, logger, metrics, config), nil
+	service := NewFieldEncryptionService(encryptionSvc, fieldRepo, finalKeyRepo, logger, metrics, config)
+
+	// Rotate keys on schedule until the service is closed
+	if config.KeyManagement.RotationFreq > 0 {
+		ctx, cancel := context.WithCancel(context.Background())
+		scheduler := NewKeyRotationScheduler(finalKeyRepo, config.KeyManagement, logger, metrics)
+		go scheduler.Run(ctx)
+		service.stopRotation = cancel
+	}
+	return service, nil
+}
+
+// newKeyRepository creates the key repository for the configured provider.
+// The memory provider keeps keys derived from a random master key for the
+// life of the process; the others persist wrapped keys in the configured
+// key store, rewrapping keys left under a previous KEK.
+func newKeyRepository(config KeyManagementConfig, logger *zap.Logger, metrics Metrics) (KeyRepository, error) {
+	if config.Provider == "" || config.Provider == "memory" {
+		// Generate master key (in production, this would come from secure key management)
+		masterKey := make([]byte, 32)
+		if _, err := rand.Read(masterKey); err != nil {
+			return nil, fmt.Errorf("failed to generate master key: %w", err)
+		}
+		return NewSecureInMemoryKeyRepository(masterKey, NewArgon2KeyDerivationService(), logger, metrics)
+	}
+
+	kek, previous, err := newKEKProviders(config, true)
+	if err != nil {
+		return nil, err
+	}
+	store, err := newKeyStore(config)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := NewEnvelopeKeyRepository(store, kek, config, logger, metrics)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	if len(previous) > 0 {
+		repo.AcceptPreviousKEKs(previous...)
+		rewrapped, err := repo.RewrapKeys(ctx)
+		if err != nil {
+			return nil, err
+		}
+		logger.Info("keys rewrapped under the current KEK", zap.Int("versions", rewrapped), zap.String("kekID", kek.ID()))
+	}
+	if _, err := repo.EnsureKey(ctx, DefaultKeyID); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// newKeyStore creates the wrapped key store named by config.KeyStore
+func newKeyStore(config KeyManagementConfig) (KeyStore, error) {
+	switch config.KeyStore {
+	case "", "file":
+		return NewFileKeyStore(config.KeyStoreDir)
+	case "redis":
+		if config.RedisAddr == "" {
+			return nil, NewEncryptionError(ErrCodeInvalidInput, "redis key store address cannot be empty", nil, nil)
+		}
+		client := redis.NewClient(&redis.Options{
+			Addr:     config.RedisAddr,
+			Password: config.RedisPassword,
+			DB:       config.RedisDB,
+		})
+		return NewRedisKeyStore(client, config.RedisPrefix)
+	default:
+		return nil, NewEncryptionError(ErrCodeInvalidInput, "unsupported key store", nil,
+			map[string]any{"keyStore": config.KeyStore})
+	}
 }
 
 // Usage Example with error handling and logging
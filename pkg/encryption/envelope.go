@@ -0,0 +1,446 @@
+package encryption
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// KEKProvider wraps data keys with a key-encryption key (KEK) that never
+// leaves the provider
+type KEKProvider interface {
+	// ID identifies the KEK; it is recorded with every wrapped key
+	ID() string
+	WrapKey(ctx context.Context, key, aad []byte) ([]byte, error)
+	UnwrapKey(ctx context.Context, wrapped, aad []byte) ([]byte, error)
+}
+
+// WrappedKey is the stored form of a data key
+type WrappedKey struct {
+	ID         KeyID      `json:"id"`
+	Version    KeyVersion `json:"version"`
+	Algorithm  Algorithm  `json:"algorithm"`
+	KEKID      string     `json:"kekId"`
+	WrappedKey []byte     `json:"wrappedKey"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  time.Time  `json:"expiresAt"`
+}
+
+// KeyMetadata describes a stored key version without its key material
+type KeyMetadata struct {
+	Version   KeyVersion
+	KEKID     string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// KeyMetadataLister is implemented by key repositories that can describe
+// their keys without unwrapping them
+type KeyMetadataLister interface {
+	// LatestKeyMetadata returns the latest version of every key
+	LatestKeyMetadata(ctx context.Context) (map[KeyID]KeyMetadata, error)
+}
+
+// KeyStore persists wrapped data keys. Versions are immutable: Put fails
+// with ErrCodeKeyExists when the version is already stored, which lets
+// instances sharing a store rotate concurrently.
+type KeyStore interface {
+	Put(ctx context.Context, key *WrappedKey) error
+	// Replace overwrites a stored version, failing with ErrCodeKeyNotFound
+	// when it is missing. It is only used to rewrap the same key material
+	// under another KEK.
+	Replace(ctx context.Context, key *WrappedKey) error
+	Get(ctx context.Context, keyID KeyID, version KeyVersion) (*WrappedKey, error)
+	// Versions returns every version of a key in ascending order
+	Versions(ctx context.Context, keyID KeyID) ([]*WrappedKey, error)
+	KeyIDs(ctx context.Context) ([]KeyID, error)
+	Delete(ctx context.Context, keyID KeyID, version KeyVersion) error
+	HealthCheck(ctx context.Context) error
+}
+
+// keyNeverExpires is the expiry of keys created without a KeyTTL
+var keyNeverExpires = time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+
+// EnvelopeKeyRepository is a KeyRepository whose data keys are generated
+// randomly, wrapped by a KEKProvider and persisted in a KeyStore, so they
+// survive restarts without the store ever holding key material in clear.
+//
+// Expired versions are still returned by GetKey so existing ciphertexts stay
+// readable; GetLatestKey only returns an unexpired version.
+//
+// Keys wrapped by a retired KEK stay readable when that KEK is accepted
+// through AcceptPreviousKEKs, until RewrapKeys moves them to the current one.
+type EnvelopeKeyRepository struct {
+	store    KeyStore
+	kek      KEKProvider
+	previous map[string]KEKProvider
+	ttl      time.Duration
+	logger   *zap.Logger
+	metrics  Metrics
+}
+
+func NewEnvelopeKeyRepository(store KeyStore, kek KEKProvider, config KeyManagementConfig, logger *zap.Logger, metrics Metrics) (*EnvelopeKeyRepository, error) {
+	if store == nil {
+		return nil, NewEncryptionError(ErrCodeInvalidInput, "key store cannot be nil", nil, nil)
+	}
+	if kek == nil {
+		return nil, NewEncryptionError(ErrCodeInvalidInput, "KEK provider cannot be nil", nil, nil)
+	}
+
+	return &EnvelopeKeyRepository{
+		store:   store,
+		kek:     kek,
+		ttl:     config.KeyTTL,
+		logger:  logger,
+		metrics: metrics,
+	}, nil
+}
+
+func (kr *EnvelopeKeyRepository) GetKey(ctx context.Context, keyID KeyID, version KeyVersion) (*EncryptionKey, error) {
+	record, err := kr.store.Get(ctx, keyID, version)
+	if err != nil {
+		return nil, err
+	}
+	return kr.unwrap(ctx, record)
+}
+
+func (kr *EnvelopeKeyRepository) GetLatestKey(ctx context.Context, keyID KeyID) (*EncryptionKey, error) {
+	versions, err := kr.store.Versions(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, NewEncryptionError(ErrCodeKeyNotFound, "key ID not found", nil,
+			map[string]any{"keyID": keyID})
+	}
+
+	latest := versions[len(versions)-1]
+	if time.Now().After(latest.ExpiresAt) {
+		return nil, NewEncryptionError(ErrCodeKeyNotFound, "no valid key found", nil,
+			map[string]any{"keyID": keyID, "version": latest.Version})
+	}
+	return kr.unwrap(ctx, latest)
+}
+
+func (kr *EnvelopeKeyRepository) StoreKey(ctx context.Context, key *EncryptionKey) error {
+	if key == nil {
+		return NewEncryptionError(ErrCodeInvalidInput, "key cannot be nil", nil, nil)
+	}
+
+	record := &WrappedKey{
+		ID:        key.ID(),
+		Version:   key.Version(),
+		Algorithm: key.Algorithm(),
+		KEKID:     kr.kek.ID(),
+		CreatedAt: key.CreatedAt(),
+		ExpiresAt: key.ExpiresAt(),
+	}
+	plaintext := key.Key()
+	defer clear(plaintext)
+
+	wrapped, err := kr.kek.WrapKey(ctx, plaintext, wrappedKeyAAD(record))
+	if err != nil {
+		return NewEncryptionError(ErrCodeEncryptionFailed, "failed to wrap key", err,
+			map[string]any{"keyID": key.ID(), "version": key.Version()})
+	}
+	record.WrappedKey = wrapped
+
+	if err := kr.store.Put(ctx, record); err != nil {
+		return err
+	}
+
+	kr.logger.Info("key stored",
+		zap.String("keyID", string(key.ID())),
+		zap.Uint32("version", uint32(key.Version())),
+		zap.String("kekID", record.KEKID))
+	return nil
+}
+
+// AcceptPreviousKEKs lets the repository unwrap keys wrapped by earlier
+// KEKs. Call it before the repository is shared.
+func (kr *EnvelopeKeyRepository) AcceptPreviousKEKs(keks ...KEKProvider) {
+	for _, kek := range keks {
+		if kek == nil || kek.ID() == kr.kek.ID() {
+			continue
+		}
+		if kr.previous == nil {
+			kr.previous = make(map[string]KEKProvider)
+		}
+		kr.previous[kek.ID()] = kek
+	}
+}
+
+// RewrapKeys wraps every stored version that is not under the current KEK
+// again with it, and returns how many versions it rewrapped. The key
+// material is unchanged, so existing ciphertexts stay readable. A failed
+// version does not stop the others; the first error is returned.
+func (kr *EnvelopeKeyRepository) RewrapKeys(ctx context.Context) (int, error) {
+	keyIDs, err := kr.store.KeyIDs(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var rewrapped int
+	var firstErr error
+	for _, keyID := range keyIDs {
+		versions, err := kr.store.Versions(ctx, keyID)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		for _, record := range versions {
+			if record.KEKID == kr.kek.ID() {
+				continue
+			}
+			if err := kr.rewrap(ctx, record); err != nil {
+				kr.metrics.IncrementErrorCount("rewrap_key", "rewrap_failed")
+				kr.logger.Error("key rewrap failed",
+					zap.String("keyID", string(record.ID)),
+					zap.Uint32("version", uint32(record.Version)),
+					zap.Error(err))
+				if firstErr == nil {
+					firstErr = err
+				}
+				continue
+			}
+			rewrapped++
+		}
+	}
+	return rewrapped, firstErr
+}
+
+// LatestKeyMetadata returns the latest version of every key from the
+// store's records, without unwrapping them
+func (kr *EnvelopeKeyRepository) LatestKeyMetadata(ctx context.Context) (map[KeyID]KeyMetadata, error) {
+	keyIDs, err := kr.store.KeyIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[KeyID]KeyMetadata, len(keyIDs))
+	for _, keyID := range keyIDs {
+		versions, err := kr.store.Versions(ctx, keyID)
+		if err != nil {
+			return nil, err
+		}
+		if len(versions) == 0 {
+			continue
+		}
+		latest := versions[len(versions)-1]
+		result[keyID] = KeyMetadata{
+			Version:   latest.Version,
+			KEKID:     latest.KEKID,
+			CreatedAt: latest.CreatedAt,
+			ExpiresAt: latest.ExpiresAt,
+		}
+	}
+	return result, nil
+}
+
+// ListKeys returns every stored version, expired ones included
+func (kr *EnvelopeKeyRepository) ListKeys(ctx context.Context) (map[KeyID][]*EncryptionKey, error) {
+	keyIDs, err := kr.store.KeyIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[KeyID][]*EncryptionKey, len(keyIDs))
+	for _, keyID := range keyIDs {
+		versions, err := kr.store.Versions(ctx, keyID)
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range versions {
+			key, err := kr.unwrap(ctx, record)
+			if err != nil {
+				return nil, err
+			}
+			result[keyID] = append(result[keyID], key)
+		}
+	}
+	return result, nil
+}
+
+// RotateKey stores a new random version of a key. When another instance
+// stored the same version first, its key is returned instead.
+func (kr *EnvelopeKeyRepository) RotateKey(ctx context.Context, keyID KeyID) (*EncryptionKey, error) {
+	versions, err := kr.store.Versions(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, NewEncryptionError(ErrCodeKeyNotFound, "key ID not found", nil,
+			map[string]any{"keyID": keyID})
+	}
+
+	key, err := kr.createKey(ctx, keyID, versions[len(versions)-1].Version+1)
+	if err != nil {
+		return nil, NewEncryptionError(ErrCodeKeyRotationFailed, "failed to rotate key", err,
+			map[string]any{"keyID": keyID})
+	}
+
+	kr.logger.Info("key rotated",
+		zap.String("keyID", string(keyID)),
+		zap.Uint32("newVersion", uint32(key.Version())))
+	return key, nil
+}
+
+// EnsureKey creates the first version of a key unless it already exists
+func (kr *EnvelopeKeyRepository) EnsureKey(ctx context.Context, keyID KeyID) (*EncryptionKey, error) {
+	versions, err := kr.store.Versions(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) > 0 {
+		return kr.unwrap(ctx, versions[len(versions)-1])
+	}
+	return kr.createKey(ctx, keyID, CurrentKeyVersion)
+}
+
+// RetireKey deletes a key version that no longer protects any data. The
+// latest version cannot be retired.
+func (kr *EnvelopeKeyRepository) RetireKey(ctx context.Context, keyID KeyID, version KeyVersion) error {
+	versions, err := kr.store.Versions(ctx, keyID)
+	if err != nil {
+		return err
+	}
+	if len(versions) > 0 && versions[len(versions)-1].Version == version {
+		return NewEncryptionError(ErrCodeInvalidInput, "cannot retire the latest key version", nil,
+			map[string]any{"keyID": keyID, "version": version})
+	}
+	if err := kr.store.Delete(ctx, keyID, version); err != nil {
+		return err
+	}
+
+	kr.logger.Info("key retired",
+		zap.String("keyID", string(keyID)),
+		zap.Uint32("version", uint32(version)))
+	return nil
+}
+
+// HealthCheck checks the store and that the KEK can wrap and unwrap
+func (kr *EnvelopeKeyRepository) HealthCheck(ctx context.Context) error {
+	if err := kr.store.HealthCheck(ctx); err != nil {
+		return NewEncryptionError(ErrCodeServiceUnavailable, "key store unavailable", err, nil)
+	}
+
+	probe := []byte("health-check")
+	wrapped, err := kr.kek.WrapKey(ctx, probe, probe)
+	if err == nil {
+		_, err = kr.kek.UnwrapKey(ctx, wrapped, probe)
+	}
+	if err != nil {
+		return NewEncryptionError(ErrCodeServiceUnavailable, "KEK provider unavailable", err,
+			map[string]any{"kekID": kr.kek.ID()})
+	}
+	return nil
+}
+
+// createKey generates and stores a key version. A version stored
+// concurrently by another instance wins.
+func (kr *EnvelopeKeyRepository) createKey(ctx context.Context, keyID KeyID, version KeyVersion) (*EncryptionKey, error) {
+	keyBytes := make([]byte, 32)
+	if _, err := rand.Read(keyBytes); err != nil {
+		return nil, err
+	}
+	defer clear(keyBytes)
+
+	ttl := kr.ttl
+	if ttl <= 0 {
+		ttl = time.Until(keyNeverExpires)
+	}
+	key, err := NewEncryptionKey(keyID, version, keyBytes, AlgorithmAES256GCM, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := kr.StoreKey(ctx, key); err != nil {
+		if hasErrorCode(err, ErrCodeKeyExists) {
+			key.Zeroize()
+			return kr.GetKey(ctx, keyID, version)
+		}
+		return nil, err
+	}
+	return key, nil
+}
+
+// rewrap stores record wrapped by the current KEK
+func (kr *EnvelopeKeyRepository) rewrap(ctx context.Context, record *WrappedKey) error {
+	plaintext, err := kr.unwrapBytes(ctx, record)
+	if err != nil {
+		return err
+	}
+	defer clear(plaintext)
+
+	replacement := *record
+	replacement.KEKID = kr.kek.ID()
+	replacement.WrappedKey, err = kr.kek.WrapKey(ctx, plaintext, wrappedKeyAAD(&replacement))
+	if err != nil {
+		return NewEncryptionError(ErrCodeEncryptionFailed, "failed to wrap key", err,
+			map[string]any{"keyID": record.ID, "version": record.Version})
+	}
+	if err := kr.store.Replace(ctx, &replacement); err != nil {
+		return err
+	}
+
+	kr.logger.Info("key rewrapped",
+		zap.String("keyID", string(record.ID)),
+		zap.Uint32("version", uint32(record.Version)),
+		zap.String("previousKEKID", record.KEKID),
+		zap.String("kekID", replacement.KEKID))
+	return nil
+}
+
+func (kr *EnvelopeKeyRepository) unwrap(ctx context.Context, record *WrappedKey) (*EncryptionKey, error) {
+	plaintext, err := kr.unwrapBytes(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(plaintext)
+
+	key, err := NewEncryptionKey(record.ID, record.Version, plaintext, record.Algorithm, 0)
+	if err != nil {
+		return nil, err
+	}
+	key.createdAt = record.CreatedAt
+	key.expiresAt = record.ExpiresAt
+	return key, nil
+}
+
+// unwrapBytes returns the key material of record using the KEK that
+// wrapped it; callers clear the result
+func (kr *EnvelopeKeyRepository) unwrapBytes(ctx context.Context, record *WrappedKey) ([]byte, error) {
+	kek := kr.kek
+	if record.KEKID != kek.ID() {
+		var ok bool
+		if kek, ok = kr.previous[record.KEKID]; !ok {
+			return nil, NewEncryptionError(ErrCodeDecryptionFailed, "key was wrapped by a different KEK", nil,
+				map[string]any{"keyID": record.ID, "version": record.Version, "kekID": record.KEKID})
+		}
+	}
+
+	plaintext, err := kek.UnwrapKey(ctx, record.WrappedKey, wrappedKeyAAD(record))
+	if err != nil {
+		kr.metrics.IncrementErrorCount("unwrap_key", "unwrap_failed")
+		return nil, NewEncryptionError(ErrCodeDecryptionFailed, "failed to unwrap key", err,
+			map[string]any{"keyID": record.ID, "version": record.Version})
+	}
+	return plaintext, nil
+}
+
+// wrappedKeyAAD binds a wrapped key to its identity, so a wrapped key
+// copied to another ID or version fails to unwrap
+func wrappedKeyAAD(record *WrappedKey) []byte {
+	return []byte(fmt.Sprintf("key-wrap:%s:%d:%s", record.ID, record.Version, record.Algorithm))
+}
+
+func hasErrorCode(err error, code string) bool {
+	var encErr *EncryptionError
+	return errors.As(err, &encErr) && encErr.Code == code
+}
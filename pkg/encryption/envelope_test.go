@@ -0,0 +1,361 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var (
+	_ KeyRepository = (*EnvelopeKeyRepository)(nil)
+	_ KeyStore      = (*FileKeyStore)(nil)
+	_ KeyStore      = (*RedisKeyStore)(nil)
+	_ KEKProvider   = (*FileKEKProvider)(nil)
+)
+
+func newFileEnvelopeRepository(t *testing.T, dir string, config KeyManagementConfig) *EnvelopeKeyRepository {
+	t.Helper()
+	kek, err := NewFileKEKProvider(filepath.Join(dir, "kek"), true)
+	require.NoError(t, err)
+	store, err := NewFileKeyStore(filepath.Join(dir, "keys"))
+	require.NoError(t, err)
+	repo, err := NewEnvelopeKeyRepository(store, kek, config, zap.NewNop(), NewSimpleMetrics())
+	require.NoError(t, err)
+	return repo
+}
+
+func TestEnvelopeKeyRepositorySurvivesRestart(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	repo := newFileEnvelopeRepository(t, dir, KeyManagementConfig{})
+	key, err := repo.EnsureKey(ctx, DefaultKeyID)
+	require.NoError(t, err)
+	assert.Equal(t, CurrentKeyVersion, key.Version())
+
+	service := NewEncryptionService(repo, 1024, zap.NewNop(), NewSimpleMetrics())
+	payload, err := service.Encrypt(ctx, "user@example.com", DefaultKeyID)
+	require.NoError(t, err)
+
+	// The key file holds the key wrapped, never in clear
+	data, err := os.ReadFile(filepath.Join(dir, "keys", "default.json"))
+	require.NoError(t, err)
+	assert.False(t, bytes.Contains(data, key.Key()))
+	info, err := os.Stat(filepath.Join(dir, "kek"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	restarted := newFileEnvelopeRepository(t, dir, KeyManagementConfig{})
+	again, err := restarted.EnsureKey(ctx, DefaultKeyID)
+	require.NoError(t, err)
+	assert.Equal(t, key.Key(), again.Key())
+	assert.True(t, key.CreatedAt().Equal(again.CreatedAt()))
+
+	plaintext, err := NewEncryptionService(restarted, 1024, zap.NewNop(), NewSimpleMetrics()).Decrypt(ctx, payload)
+	require.NoError(t, err)
+	assert.Equal(t, "user@example.com", plaintext)
+}
+
+func TestEnvelopeKeyRepositoryRotation(t *testing.T) {
+	ctx := context.Background()
+	repo := newFileEnvelopeRepository(t, t.TempDir(), KeyManagementConfig{KeyTTL: time.Hour})
+
+	_, err := repo.RotateKey(ctx, "orders")
+	assert.True(t, hasErrorCode(err, ErrCodeKeyNotFound))
+
+	first, err := repo.EnsureKey(ctx, "orders")
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), first.ExpiresAt(), time.Minute)
+
+	second, err := repo.RotateKey(ctx, "orders")
+	require.NoError(t, err)
+	assert.Equal(t, first.Version()+1, second.Version())
+	assert.NotEqual(t, first.Key(), second.Key())
+
+	latest, err := repo.GetLatestKey(ctx, "orders")
+	require.NoError(t, err)
+	assert.Equal(t, second.Version(), latest.Version())
+
+	old, err := repo.GetKey(ctx, "orders", first.Version())
+	require.NoError(t, err)
+	assert.Equal(t, first.Key(), old.Key())
+
+	keys, err := repo.ListKeys(ctx)
+	require.NoError(t, err)
+	assert.Len(t, keys["orders"], 2)
+
+	// A version stored concurrently by another instance wins
+	winner, err := repo.createKey(ctx, "orders", second.Version())
+	require.NoError(t, err)
+	assert.Equal(t, second.Key(), winner.Key())
+}
+
+func TestEnvelopeKeyRepositoryRejectsOtherKEK(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	repo := newFileEnvelopeRepository(t, dir, KeyManagementConfig{})
+	_, err := repo.EnsureKey(ctx, DefaultKeyID)
+	require.NoError(t, err)
+	require.NoError(t, repo.HealthCheck(ctx))
+
+	otherKEK, err := NewFileKEKProvider(filepath.Join(t.TempDir(), "kek"), true)
+	require.NoError(t, err)
+	other, err := NewEnvelopeKeyRepository(repo.store, otherKEK, KeyManagementConfig{}, zap.NewNop(), NewSimpleMetrics())
+	require.NoError(t, err)
+
+	_, err = other.GetLatestKey(ctx, DefaultKeyID)
+	assert.True(t, hasErrorCode(err, ErrCodeDecryptionFailed))
+}
+
+func TestEnvelopeKeyRepositoryRewrapsUnderNewKEK(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	repo := newFileEnvelopeRepository(t, dir, KeyManagementConfig{})
+	first, err := repo.EnsureKey(ctx, DefaultKeyID)
+	require.NoError(t, err)
+	second, err := repo.RotateKey(ctx, DefaultKeyID)
+	require.NoError(t, err)
+
+	newKEK, err := NewFileKEKProvider(filepath.Join(t.TempDir(), "kek"), true)
+	require.NoError(t, err)
+	moved, err := NewEnvelopeKeyRepository(repo.store, newKEK, KeyManagementConfig{}, zap.NewNop(), NewSimpleMetrics())
+	require.NoError(t, err)
+	moved.AcceptPreviousKEKs(repo.kek)
+
+	old, err := moved.GetKey(ctx, DefaultKeyID, first.Version())
+	require.NoError(t, err, "keys under an accepted KEK stay readable")
+	assert.Equal(t, first.Key(), old.Key())
+
+	rewrapped, err := moved.RewrapKeys(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, rewrapped)
+	rewrapped, err = moved.RewrapKeys(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, rewrapped, "rewrapped keys are left alone")
+
+	// Only the new KEK is needed from now on
+	current, err := NewEnvelopeKeyRepository(repo.store, newKEK, KeyManagementConfig{}, zap.NewNop(), NewSimpleMetrics())
+	require.NoError(t, err)
+	latest, err := current.GetLatestKey(ctx, DefaultKeyID)
+	require.NoError(t, err)
+	assert.Equal(t, second.Key(), latest.Key())
+	assert.True(t, second.CreatedAt().Equal(latest.CreatedAt()))
+
+	_, err = repo.GetLatestKey(ctx, DefaultKeyID)
+	assert.True(t, hasErrorCode(err, ErrCodeDecryptionFailed), "the old KEK no longer applies")
+}
+
+func TestFileKeyStoreRejectsUnsafeKeyIDs(t *testing.T) {
+	store, err := NewFileKeyStore(t.TempDir())
+	require.NoError(t, err)
+
+	for _, keyID := range []KeyID{"", "../escape", ".hidden", "a/b"} {
+		err := store.Put(context.Background(), &WrappedKey{ID: keyID, Version: 1})
+		assert.True(t, hasErrorCode(err, ErrCodeInvalidInput), keyID)
+	}
+}
+
+func TestRedisKeyStore(t *testing.T) {
+	ctx := context.Background()
+	client, mock := redismock.NewClientMock()
+	store, err := NewRedisKeyStore(client, "test:")
+	require.NoError(t, err)
+
+	key := &WrappedKey{
+		ID:         "orders",
+		Version:    2,
+		Algorithm:  AlgorithmAES256GCM,
+		KEKID:      "file:0011",
+		WrappedKey: []byte{1, 2, 3},
+		CreatedAt:  time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		ExpiresAt:  keyNeverExpires,
+	}
+	data, err := json.Marshal(key)
+	require.NoError(t, err)
+
+	mock.ExpectTxPipeline()
+	mock.ExpectHSetNX("test:key:orders", "2", data).SetVal(true)
+	mock.ExpectSAdd("test:ids", "orders").SetVal(1)
+	mock.ExpectTxPipelineExec()
+	require.NoError(t, store.Put(ctx, key))
+
+	mock.ExpectTxPipeline()
+	mock.ExpectHSetNX("test:key:orders", "2", data).SetVal(false)
+	mock.ExpectSAdd("test:ids", "orders").SetVal(0)
+	mock.ExpectTxPipelineExec()
+	assert.True(t, hasErrorCode(store.Put(ctx, key), ErrCodeKeyExists))
+
+	mock.ExpectEval(replaceScript, []string{"test:key:orders"}, "2", data).SetVal(int64(1))
+	require.NoError(t, store.Replace(ctx, key))
+
+	mock.ExpectEval(replaceScript, []string{"test:key:orders"}, "2", data).SetVal(int64(0))
+	assert.True(t, hasErrorCode(store.Replace(ctx, key), ErrCodeKeyNotFound))
+
+	mock.ExpectHGet("test:key:orders", "2").SetVal(string(data))
+	loaded, err := store.Get(ctx, "orders", 2)
+	require.NoError(t, err)
+	assert.Equal(t, key.WrappedKey, loaded.WrappedKey)
+
+	mock.ExpectHGet("test:key:orders", "9").RedisNil()
+	_, err = store.Get(ctx, "orders", 9)
+	assert.True(t, hasErrorCode(err, ErrCodeKeyNotFound))
+
+	first := *key
+	first.Version = 1
+	firstData, _ := json.Marshal(&first)
+	mock.ExpectHGetAll("test:key:orders").SetVal(map[string]string{"2": string(data), "1": string(firstData)})
+	versions, err := store.Versions(ctx, "orders")
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, KeyVersion(1), versions[0].Version)
+
+	mock.ExpectSMembers("test:ids").SetVal([]string{"orders", "default"})
+	keyIDs, err := store.KeyIDs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []KeyID{"default", "orders"}, keyIDs)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSoftwareHSM(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "token.json")
+
+	hsm, err := OpenSoftwareHSM(path, "1234")
+	require.NoError(t, err)
+	require.NoError(t, hsm.GenerateKey("kek"))
+	assert.True(t, hasErrorCode(hsm.GenerateKey("kek"), ErrCodeKeyExists))
+
+	kek, err := hsm.Provider("kek")
+	require.NoError(t, err)
+	wrapped, err := kek.WrapKey(ctx, []byte("data key"), []byte("aad"))
+	require.NoError(t, err)
+
+	reopened, err := OpenSoftwareHSM(path, "1234")
+	require.NoError(t, err)
+	kek, err = reopened.Provider("kek")
+	require.NoError(t, err)
+	assert.Equal(t, "softhsm:kek", kek.ID())
+	unwrapped, err := kek.UnwrapKey(ctx, wrapped, []byte("aad"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("data key"), unwrapped)
+
+	_, err = kek.UnwrapKey(ctx, wrapped, []byte("other aad"))
+	assert.Error(t, err)
+
+	_, err = OpenSoftwareHSM(path, "0000")
+	assert.True(t, hasErrorCode(err, ErrCodeDecryptionFailed))
+}
+
+// countingKEK counts unwrapped keys
+type countingKEK struct {
+	KEKProvider
+	unwrapped int
+}
+
+func (k *countingKEK) UnwrapKey(ctx context.Context, wrapped, aad []byte) ([]byte, error) {
+	k.unwrapped++
+	return k.KEKProvider.UnwrapKey(ctx, wrapped, aad)
+}
+
+func TestKeyRotationScheduler(t *testing.T) {
+	ctx := context.Background()
+	config := KeyManagementConfig{RotationFreq: 7 * 24 * time.Hour}
+	repo := newFileEnvelopeRepository(t, t.TempDir(), config)
+	kek := &countingKEK{KEKProvider: repo.kek}
+	repo.kek = kek
+	key, err := repo.EnsureKey(ctx, DefaultKeyID)
+	require.NoError(t, err)
+
+	metrics := NewSimpleMetrics()
+	scheduler := NewKeyRotationScheduler(repo, config, zap.NewNop(), metrics)
+	assert.Equal(t, time.Hour, scheduler.interval)
+
+	rotated, err := scheduler.RotateDue(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, rotated)
+	assert.Zero(t, kek.unwrapped, "due keys are found from metadata")
+
+	scheduler.now = func() time.Time { return key.CreatedAt().Add(config.RotationFreq) }
+	rotated, err = scheduler.RotateDue(ctx)
+	require.NoError(t, err)
+	require.Len(t, rotated, 1)
+	assert.Equal(t, key.Version()+1, rotated[0].Version())
+	assert.Equal(t, int64(1), metrics.counters["key_rotation_default"])
+
+	// The new version restarts the period
+	rotated, err = scheduler.RotateDue(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, rotated)
+
+	disabled := NewKeyRotationScheduler(repo, KeyManagementConfig{}, zap.NewNop(), metrics)
+	assert.NoError(t, disabled.Run(ctx))
+
+	// Behind the cache, metadata still comes from the store
+	cached := NewKeyRotationScheduler(NewCachedKeyRepository(repo, time.Minute, zap.NewNop()), config, zap.NewNop(), metrics)
+	rotated, err = cached.RotateDue(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, rotated)
+	assert.Zero(t, kek.unwrapped)
+}
+
+func TestNewFieldEncryptionServiceFactoryWithFileProvider(t *testing.T) {
+	dir := t.TempDir()
+	config := &Config{
+		KeyManagement: KeyManagementConfig{
+			Provider:    "file",
+			KEKFile:     filepath.Join(dir, "kek"),
+			KeyStoreDir: filepath.Join(dir, "keys"),
+		},
+		Encryption: EncryptionConfig{Algorithm: AlgorithmAES256GCM, MaxDataSize: 1024},
+	}
+
+	service, err := NewFieldEncryptionServiceFactory(config)
+	require.NoError(t, err)
+	require.NoError(t, service.HealthCheck(context.Background()))
+	assert.FileExists(t, filepath.Join(dir, "keys", "default.json"))
+	assert.Nil(t, service.stopRotation, "rotation is off without a frequency")
+
+	// Moving to a new KEK rewraps the stored keys at start-up
+	config.KeyManagement.PreviousKEKFiles = []string{config.KeyManagement.KEKFile}
+	config.KeyManagement.KEKFile = filepath.Join(dir, "kek-2")
+	config.KeyManagement.RotationFreq = 24 * time.Hour
+	service, err = NewFieldEncryptionServiceFactory(config)
+	require.NoError(t, err)
+	require.NotNil(t, service.stopRotation, "scheduled rotation is started")
+	require.NoError(t, service.Close())
+
+	data, err := os.ReadFile(filepath.Join(dir, "keys", "default.json"))
+	require.NoError(t, err)
+	var versions []*WrappedKey
+	require.NoError(t, json.Unmarshal(data, &versions))
+	kek, err := NewFileKEKProvider(config.KeyManagement.KEKFile, false)
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+	assert.Equal(t, kek.ID(), versions[0].KEKID)
+}
+
+func TestNewKeyStore(t *testing.T) {
+	store, err := newKeyStore(KeyManagementConfig{KeyStoreDir: t.TempDir()})
+	require.NoError(t, err)
+	assert.IsType(t, &FileKeyStore{}, store)
+
+	store, err = newKeyStore(KeyManagementConfig{KeyStore: "redis", RedisAddr: "localhost:6379", RedisPrefix: "keys:"})
+	require.NoError(t, err)
+	require.IsType(t, &RedisKeyStore{}, store)
+	assert.Equal(t, "keys:", store.(*RedisKeyStore).prefix)
+
+	_, err = newKeyStore(KeyManagementConfig{KeyStore: "redis"})
+	assert.True(t, hasErrorCode(err, ErrCodeInvalidInput))
+	_, err = newKeyStore(KeyManagementConfig{KeyStore: "s3"})
+	assert.True(t, hasErrorCode(err, ErrCodeInvalidInput))
+}
@@ -0,0 +1,359 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// NewKEKProvider creates the KEK provider named by config.Provider
+func NewKEKProvider(config KeyManagementConfig) (KEKProvider, error) {
+	kek, _, err := newKEKProviders(config, false)
+	return kek, err
+}
+
+// NewPreviousKEKProviders opens the earlier KEKs listed in
+// config.PreviousKEKFiles or config.PreviousKEKLabels, so keys they wrapped
+// can be rewrapped under the current KEK
+func NewPreviousKEKProviders(config KeyManagementConfig) ([]KEKProvider, error) {
+	_, previous, err := newKEKProviders(config, true)
+	return previous, err
+}
+
+// newKEKProviders opens the current KEK and, with withPrevious set, the
+// earlier ones. Previous KEKs must already exist.
+func newKEKProviders(config KeyManagementConfig, withPrevious bool) (KEKProvider, []KEKProvider, error) {
+	switch config.Provider {
+	case "file":
+		kek, err := NewFileKEKProvider(config.KEKFile, true)
+		if err != nil || !withPrevious {
+			return kek, nil, err
+		}
+		var previous []KEKProvider
+		for _, path := range config.PreviousKEKFiles {
+			old, err := NewFileKEKProvider(path, false)
+			if err != nil {
+				return nil, nil, err
+			}
+			previous = append(previous, old)
+		}
+		return kek, previous, nil
+	case "softhsm":
+		hsm, err := OpenSoftwareHSM(config.TokenFile, config.TokenPIN)
+		if err != nil {
+			return nil, nil, err
+		}
+		label := config.KEKLabel
+		if label == "" {
+			label = "kek"
+		}
+		if !hsm.HasKey(label) {
+			if err := hsm.GenerateKey(label); err != nil {
+				return nil, nil, err
+			}
+		}
+		kek, err := hsm.Provider(label)
+		if err != nil || !withPrevious {
+			return kek, nil, err
+		}
+		var previous []KEKProvider
+		for _, label := range config.PreviousKEKLabels {
+			old, err := hsm.Provider(label)
+			if err != nil {
+				return nil, nil, err
+			}
+			previous = append(previous, old)
+		}
+		return kek, previous, nil
+	default:
+		return nil, nil, NewEncryptionError(ErrCodeInvalidInput, "unsupported key provider", nil,
+			map[string]any{"provider": config.Provider})
+	}
+}
+
+// FileKEKProvider wraps keys with an AES-256 KEK read from a local file
+// holding 32 raw bytes or 64 hex characters
+type FileKEKProvider struct {
+	id   string
+	aead cipher.AEAD
+}
+
+// NewFileKEKProvider loads the KEK at path. With create set, a missing file
+// is created with a new random KEK readable only by the owner.
+func NewFileKEKProvider(path string, create bool) (*FileKEKProvider, error) {
+	if path == "" {
+		return nil, NewEncryptionError(ErrCodeInvalidInput, "KEK file path cannot be empty", nil, nil)
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && create {
+		data, err = createKEKFile(path)
+	}
+	if err != nil {
+		return nil, NewEncryptionError(ErrCodeServiceUnavailable, "failed to read KEK file", err,
+			map[string]any{"path": path})
+	}
+
+	kek, err := parseKEK(data)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(kek)
+
+	aead, err := newKeyWrapAEAD(kek)
+	if err != nil {
+		return nil, err
+	}
+	return &FileKEKProvider{id: "file:" + kekFingerprint(kek), aead: aead}, nil
+}
+
+// ID is derived from the KEK, so keys wrapped by another file are detected
+func (p *FileKEKProvider) ID() string { return p.id }
+
+func (p *FileKEKProvider) WrapKey(ctx context.Context, key, aad []byte) ([]byte, error) {
+	return sealKey(p.aead, key, aad)
+}
+
+func (p *FileKEKProvider) UnwrapKey(ctx context.Context, wrapped, aad []byte) ([]byte, error) {
+	return openKey(p.aead, wrapped, aad)
+}
+
+func createKEKFile(path string) ([]byte, error) {
+	kek := make([]byte, 32)
+	if _, err := rand.Read(kek); err != nil {
+		return nil, err
+	}
+	data := []byte(hex.EncodeToString(kek) + "\n")
+	clear(kek)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	if err := writeFileAtomic(path, data, 0o600); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func parseKEK(data []byte) ([]byte, error) {
+	if len(data) == 32 {
+		return bytes.Clone(data), nil
+	}
+	kek, err := hex.DecodeString(string(bytes.TrimSpace(data)))
+	if err != nil || len(kek) != 32 {
+		return nil, NewEncryptionError(ErrCodeInvalidInput, "KEK must be 32 bytes or 64 hex characters", err, nil)
+	}
+	return kek, nil
+}
+
+func kekFingerprint(kek []byte) string {
+	sum := sha256.Sum256(kek)
+	return hex.EncodeToString(sum[:8])
+}
+
+// SoftwareHSM is a software stand-in for a PKCS#11 token: keys are generated
+// inside the token, addressed by label and never exported. The token is
+// persisted encrypted under a key derived from its PIN; an empty path keeps
+// it in memory.
+type SoftwareHSM struct {
+	mu   sync.RWMutex
+	path string
+	salt []byte
+	aead cipher.AEAD
+	keys map[string][]byte
+}
+
+// softHSMToken is the on-disk form of a SoftwareHSM
+type softHSMToken struct {
+	Salt []byte `json:"salt"`
+	Keys []byte `json:"keys"` // Sealed JSON of label -> key
+}
+
+// OpenSoftwareHSM opens the token at path, creating it when missing
+func OpenSoftwareHSM(path, pin string) (*SoftwareHSM, error) {
+	if pin == "" {
+		return nil, NewEncryptionError(ErrCodeInvalidInput, "token PIN cannot be empty", nil, nil)
+	}
+
+	hsm := &SoftwareHSM{path: path, keys: make(map[string][]byte)}
+	var token softHSMToken
+	if path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			if err := json.Unmarshal(data, &token); err != nil {
+				return nil, NewEncryptionError(ErrCodeInvalidInput, "corrupted token", err, map[string]any{"path": path})
+			}
+		case !errors.Is(err, fs.ErrNotExist):
+			return nil, NewEncryptionError(ErrCodeServiceUnavailable, "failed to read token", err, map[string]any{"path": path})
+		}
+	}
+
+	hsm.salt = token.Salt
+	if len(hsm.salt) == 0 {
+		hsm.salt = make([]byte, 16)
+		if _, err := rand.Read(hsm.salt); err != nil {
+			return nil, err
+		}
+	}
+
+	pinKey := argon2.IDKey([]byte(pin), hsm.salt, 1, 64*1024, 4, 32)
+	defer clear(pinKey)
+	aead, err := newKeyWrapAEAD(pinKey)
+	if err != nil {
+		return nil, err
+	}
+	hsm.aead = aead
+
+	if len(token.Keys) > 0 {
+		data, err := openKey(aead, token.Keys, hsm.salt)
+		if err != nil {
+			return nil, NewEncryptionError(ErrCodeDecryptionFailed, "incorrect PIN or corrupted token", err, nil)
+		}
+		defer clear(data)
+		if err := json.Unmarshal(data, &hsm.keys); err != nil {
+			return nil, NewEncryptionError(ErrCodeInvalidInput, "corrupted token", err, nil)
+		}
+	}
+	return hsm, nil
+}
+
+// GenerateKey creates an AES-256 key under label
+func (h *SoftwareHSM) GenerateKey(label string) error {
+	if label == "" {
+		return NewEncryptionError(ErrCodeInvalidInput, "key label cannot be empty", nil, nil)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, exists := h.keys[label]; exists {
+		return NewEncryptionError(ErrCodeKeyExists, "key label already exists", nil, map[string]any{"label": label})
+	}
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return err
+	}
+	h.keys[label] = key
+	if err := h.save(); err != nil {
+		delete(h.keys, label)
+		return err
+	}
+	return nil
+}
+
+func (h *SoftwareHSM) HasKey(label string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	_, exists := h.keys[label]
+	return exists
+}
+
+// Provider returns a KEKProvider backed by the key under label
+func (h *SoftwareHSM) Provider(label string) (KEKProvider, error) {
+	if !h.HasKey(label) {
+		return nil, NewEncryptionError(ErrCodeKeyNotFound, "key label not found", nil, map[string]any{"label": label})
+	}
+	return &softHSMKEK{hsm: h, label: label}, nil
+}
+
+func (h *SoftwareHSM) aeadFor(label string) (cipher.AEAD, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	key, exists := h.keys[label]
+	if !exists {
+		return nil, NewEncryptionError(ErrCodeKeyNotFound, "key label not found", nil, map[string]any{"label": label})
+	}
+	return newKeyWrapAEAD(key)
+}
+
+// save persists the token; callers hold the write lock
+func (h *SoftwareHSM) save() error {
+	if h.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(h.keys)
+	if err != nil {
+		return err
+	}
+	defer clear(data)
+	sealed, err := sealKey(h.aead, data, h.salt)
+	if err != nil {
+		return err
+	}
+	token, err := json.Marshal(softHSMToken{Salt: h.salt, Keys: sealed})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o700); err != nil {
+		return NewEncryptionError(ErrCodeServiceUnavailable, "failed to save token", err, nil)
+	}
+	if err := writeFileAtomic(h.path, token, 0o600); err != nil {
+		return NewEncryptionError(ErrCodeServiceUnavailable, "failed to save token", err, nil)
+	}
+	return nil
+}
+
+type softHSMKEK struct {
+	hsm   *SoftwareHSM
+	label string
+}
+
+func (k *softHSMKEK) ID() string { return "softhsm:" + k.label }
+
+func (k *softHSMKEK) WrapKey(ctx context.Context, key, aad []byte) ([]byte, error) {
+	aead, err := k.hsm.aeadFor(k.label)
+	if err != nil {
+		return nil, err
+	}
+	return sealKey(aead, key, aad)
+}
+
+func (k *softHSMKEK) UnwrapKey(ctx context.Context, wrapped, aad []byte) ([]byte, error) {
+	aead, err := k.hsm.aeadFor(k.label)
+	if err != nil {
+		return nil, err
+	}
+	return openKey(aead, wrapped, aad)
+}
+
+// AES-GCM key wrapping; the nonce is prepended to the sealed key
+func newKeyWrapAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func sealKey(aead cipher.AEAD, key, aad []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(key)+aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, key, aad), nil
+}
+
+func openKey(aead cipher.AEAD, wrapped, aad []byte) ([]byte, error) {
+	if len(wrapped) < aead.NonceSize() {
+		return nil, NewEncryptionError(ErrCodeInvalidEncryption, "wrapped key too short", nil, nil)
+	}
+	nonce, sealed := wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():]
+	return aead.Open(nil, nonce, sealed, aad)
+}
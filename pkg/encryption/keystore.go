@@ -0,0 +1,415 @@
+package encryption
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// FileKeyStore keeps wrapped keys in one JSON file per key ID. Writes are
+// atomic but only serialised within the process: share a directory between
+// instances through RedisKeyStore instead.
+type FileKeyStore struct {
+	dir string
+	mu  sync.RWMutex
+}
+
+func NewFileKeyStore(dir string) (*FileKeyStore, error) {
+	if dir == "" {
+		return nil, NewEncryptionError(ErrCodeInvalidInput, "key store directory cannot be empty", nil, nil)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, NewEncryptionError(ErrCodeServiceUnavailable, "failed to create key store directory", err,
+			map[string]any{"dir": dir})
+	}
+	return &FileKeyStore{dir: dir}, nil
+}
+
+func (s *FileKeyStore) Put(ctx context.Context, key *WrappedKey) error {
+	if err := validateKeyID(key.ID); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	versions, err := s.read(key.ID)
+	if err != nil {
+		return err
+	}
+	for _, existing := range versions {
+		if existing.Version == key.Version {
+			return NewEncryptionError(ErrCodeKeyExists, "key version already exists", nil,
+				map[string]any{"keyID": key.ID, "version": key.Version})
+		}
+	}
+	versions = append(versions, key)
+	sortWrappedKeys(versions)
+
+	data, err := json.MarshalIndent(versions, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(s.path(key.ID), data, 0o600); err != nil {
+		return NewEncryptionError(ErrCodeServiceUnavailable, "failed to write key file", err,
+			map[string]any{"keyID": key.ID})
+	}
+	return nil
+}
+
+func (s *FileKeyStore) Replace(ctx context.Context, key *WrappedKey) error {
+	if err := validateKeyID(key.ID); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	versions, err := s.read(key.ID)
+	if err != nil {
+		return err
+	}
+	index := slices.IndexFunc(versions, func(existing *WrappedKey) bool {
+		return existing.Version == key.Version
+	})
+	if index < 0 {
+		return NewEncryptionError(ErrCodeKeyNotFound, "key version not found", nil,
+			map[string]any{"keyID": key.ID, "version": key.Version})
+	}
+	versions[index] = key
+
+	data, err := json.MarshalIndent(versions, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(s.path(key.ID), data, 0o600); err != nil {
+		return NewEncryptionError(ErrCodeServiceUnavailable, "failed to write key file", err,
+			map[string]any{"keyID": key.ID})
+	}
+	return nil
+}
+
+func (s *FileKeyStore) Get(ctx context.Context, keyID KeyID, version KeyVersion) (*WrappedKey, error) {
+	versions, err := s.Versions(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range versions {
+		if key.Version == version {
+			return key, nil
+		}
+	}
+	return nil, NewEncryptionError(ErrCodeKeyNotFound, "key version not found", nil,
+		map[string]any{"keyID": keyID, "version": version})
+}
+
+func (s *FileKeyStore) Versions(ctx context.Context, keyID KeyID) ([]*WrappedKey, error) {
+	if err := validateKeyID(keyID); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.read(keyID)
+}
+
+func (s *FileKeyStore) KeyIDs(ctx context.Context) ([]KeyID, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, NewEncryptionError(ErrCodeServiceUnavailable, "failed to list key store", err, nil)
+	}
+	var keyIDs []KeyID
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if ok && !entry.IsDir() && validateKeyID(KeyID(name)) == nil {
+			keyIDs = append(keyIDs, KeyID(name))
+		}
+	}
+	return keyIDs, nil
+}
+
+func (s *FileKeyStore) Delete(ctx context.Context, keyID KeyID, version KeyVersion) error {
+	if err := validateKeyID(keyID); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	versions, err := s.read(keyID)
+	if err != nil {
+		return err
+	}
+	kept := slices.DeleteFunc(slices.Clone(versions), func(key *WrappedKey) bool {
+		return key.Version == version
+	})
+	if len(kept) == len(versions) {
+		return NewEncryptionError(ErrCodeKeyNotFound, "key version not found", nil,
+			map[string]any{"keyID": keyID, "version": version})
+	}
+
+	if len(kept) == 0 {
+		err = os.Remove(s.path(keyID))
+	} else {
+		var data []byte
+		if data, err = json.MarshalIndent(kept, "", "  "); err == nil {
+			err = writeFileAtomic(s.path(keyID), data, 0o600)
+		}
+	}
+	if err != nil {
+		return NewEncryptionError(ErrCodeServiceUnavailable, "failed to write key file", err,
+			map[string]any{"keyID": keyID})
+	}
+	return nil
+}
+
+func (s *FileKeyStore) HealthCheck(ctx context.Context) error {
+	info, err := os.Stat(s.dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return NewEncryptionError(ErrCodeServiceUnavailable, "key store path is not a directory", nil,
+			map[string]any{"dir": s.dir})
+	}
+	return nil
+}
+
+func (s *FileKeyStore) path(keyID KeyID) string {
+	return filepath.Join(s.dir, string(keyID)+".json")
+}
+
+// read loads the versions of a key; callers hold the lock
+func (s *FileKeyStore) read(keyID KeyID) ([]*WrappedKey, error) {
+	data, err := os.ReadFile(s.path(keyID))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, NewEncryptionError(ErrCodeServiceUnavailable, "failed to read key file", err,
+			map[string]any{"keyID": keyID})
+	}
+
+	var versions []*WrappedKey
+	if err := json.Unmarshal(data, &versions); err != nil {
+		return nil, NewEncryptionError(ErrCodeInvalidEncryption, "corrupted key file", err,
+			map[string]any{"keyID": keyID})
+	}
+	return versions, nil
+}
+
+// RedisKeyStore keeps wrapped keys in Redis:
+//
+//	<prefix>key:<id>  hash of version -> wrapped key JSON
+//	<prefix>ids       set of key IDs
+type RedisKeyStore struct {
+	client redis.Cmdable
+	prefix string
+}
+
+func NewRedisKeyStore(client redis.Cmdable, prefix string) (*RedisKeyStore, error) {
+	if client == nil {
+		return nil, NewEncryptionError(ErrCodeInvalidInput, "redis client is required", nil, nil)
+	}
+	if prefix == "" {
+		prefix = "encryption:"
+	}
+	return &RedisKeyStore{client: client, prefix: prefix}, nil
+}
+
+func (s *RedisKeyStore) Put(ctx context.Context, key *WrappedKey) error {
+	if err := validateKeyID(key.ID); err != nil {
+		return err
+	}
+	data, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+
+	var created *redis.BoolCmd
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		created = pipe.HSetNX(ctx, s.keyKey(key.ID), versionField(key.Version), data)
+		pipe.SAdd(ctx, s.idsKey(), string(key.ID))
+		return nil
+	})
+	if err != nil {
+		return NewEncryptionError(ErrCodeServiceUnavailable, "failed to store key", err,
+			map[string]any{"keyID": key.ID})
+	}
+	if !created.Val() {
+		return NewEncryptionError(ErrCodeKeyExists, "key version already exists", nil,
+			map[string]any{"keyID": key.ID, "version": key.Version})
+	}
+	return nil
+}
+
+// replaceScript overwrites a hash field only when it exists
+const replaceScript = `if redis.call("HEXISTS", KEYS[1], ARGV[1]) == 1 then
+	redis.call("HSET", KEYS[1], ARGV[1], ARGV[2])
+	return 1
+end
+return 0`
+
+func (s *RedisKeyStore) Replace(ctx context.Context, key *WrappedKey) error {
+	if err := validateKeyID(key.ID); err != nil {
+		return err
+	}
+	data, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+
+	replaced, err := s.client.Eval(ctx, replaceScript, []string{s.keyKey(key.ID)}, versionField(key.Version), data).Int()
+	if err != nil {
+		return NewEncryptionError(ErrCodeServiceUnavailable, "failed to store key", err,
+			map[string]any{"keyID": key.ID})
+	}
+	if replaced == 0 {
+		return NewEncryptionError(ErrCodeKeyNotFound, "key version not found", nil,
+			map[string]any{"keyID": key.ID, "version": key.Version})
+	}
+	return nil
+}
+
+func (s *RedisKeyStore) Get(ctx context.Context, keyID KeyID, version KeyVersion) (*WrappedKey, error) {
+	data, err := s.client.HGet(ctx, s.keyKey(keyID), versionField(version)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, NewEncryptionError(ErrCodeKeyNotFound, "key version not found", nil,
+			map[string]any{"keyID": keyID, "version": version})
+	}
+	if err != nil {
+		return nil, NewEncryptionError(ErrCodeServiceUnavailable, "failed to load key", err,
+			map[string]any{"keyID": keyID})
+	}
+	return decodeWrappedKey(keyID, data)
+}
+
+func (s *RedisKeyStore) Versions(ctx context.Context, keyID KeyID) ([]*WrappedKey, error) {
+	fields, err := s.client.HGetAll(ctx, s.keyKey(keyID)).Result()
+	if err != nil {
+		return nil, NewEncryptionError(ErrCodeServiceUnavailable, "failed to load key versions", err,
+			map[string]any{"keyID": keyID})
+	}
+
+	versions := make([]*WrappedKey, 0, len(fields))
+	for _, data := range fields {
+		key, err := decodeWrappedKey(keyID, []byte(data))
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, key)
+	}
+	sortWrappedKeys(versions)
+	return versions, nil
+}
+
+func (s *RedisKeyStore) KeyIDs(ctx context.Context) ([]KeyID, error) {
+	members, err := s.client.SMembers(ctx, s.idsKey()).Result()
+	if err != nil {
+		return nil, NewEncryptionError(ErrCodeServiceUnavailable, "failed to list keys", err, nil)
+	}
+	sort.Strings(members)
+	keyIDs := make([]KeyID, len(members))
+	for i, member := range members {
+		keyIDs[i] = KeyID(member)
+	}
+	return keyIDs, nil
+}
+
+func (s *RedisKeyStore) Delete(ctx context.Context, keyID KeyID, version KeyVersion) error {
+	deleted, err := s.client.HDel(ctx, s.keyKey(keyID), versionField(version)).Result()
+	if err != nil {
+		return NewEncryptionError(ErrCodeServiceUnavailable, "failed to delete key", err,
+			map[string]any{"keyID": keyID})
+	}
+	if deleted == 0 {
+		return NewEncryptionError(ErrCodeKeyNotFound, "key version not found", nil,
+			map[string]any{"keyID": keyID, "version": version})
+	}
+	return nil
+}
+
+func (s *RedisKeyStore) HealthCheck(ctx context.Context) error {
+	return s.client.Ping(ctx).Err()
+}
+
+func (s *RedisKeyStore) keyKey(keyID KeyID) string { return s.prefix + "key:" + string(keyID) }
+func (s *RedisKeyStore) idsKey() string            { return s.prefix + "ids" }
+
+func versionField(version KeyVersion) string {
+	return strconv.FormatUint(uint64(version), 10)
+}
+
+func decodeWrappedKey(keyID KeyID, data []byte) (*WrappedKey, error) {
+	var key WrappedKey
+	if err := json.Unmarshal(data, &key); err != nil {
+		return nil, NewEncryptionError(ErrCodeInvalidEncryption, "corrupted key record", err,
+			map[string]any{"keyID": keyID})
+	}
+	return &key, nil
+}
+
+func sortWrappedKeys(keys []*WrappedKey) {
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Version < keys[j].Version })
+}
+
+// validateKeyID restricts key IDs to names safe as file names
+func validateKeyID(keyID KeyID) error {
+	if keyID == "" {
+		return NewEncryptionError(ErrCodeInvalidInput, "key ID cannot be empty", nil, nil)
+	}
+	if len(keyID) > 255 {
+		return NewEncryptionError(ErrCodeInvalidInput, "key ID too long", nil, nil)
+	}
+	if keyID[0] == '.' {
+		return NewEncryptionError(ErrCodeInvalidInput, "key ID cannot start with a dot", nil,
+			map[string]any{"keyID": keyID})
+	}
+	for _, r := range keyID {
+		valid := r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.'
+		if !valid {
+			return NewEncryptionError(ErrCodeInvalidInput, "key ID may only contain letters, digits, '-', '_' and '.'", nil,
+				map[string]any{"keyID": keyID})
+		}
+	}
+	return nil
+}
+
+// writeFileAtomic replaces path with data through a synced temporary file
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,433 @@
+package encryption
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// PayloadRecord is a stored encrypted value
+type PayloadRecord struct {
+	ID      string
+	Payload *EncryptedPayload
+}
+
+// PayloadSource gives the re-encryption worker access to stored payloads
+type PayloadSource interface {
+	// Scan returns up to limit records after cursor whose payloads use keyID
+	// below version, in a stable order, with the cursor to continue from.
+	// An empty cursor ends the scan.
+	Scan(ctx context.Context, keyID KeyID, below KeyVersion, cursor string, limit int) ([]PayloadRecord, string, error)
+	// Replace swaps a record's payload if it still holds old, failing with
+	// ErrCodePayloadChanged otherwise
+	Replace(ctx context.Context, id string, old, replacement *EncryptedPayload) error
+	// CountByVersion counts the payloads under each version of keyID
+	CountByVersion(ctx context.Context, keyID KeyID) (map[KeyVersion]int, error)
+}
+
+// KeyRetirer is implemented by key repositories that can delete old versions
+type KeyRetirer interface {
+	RetireKey(ctx context.Context, keyID KeyID, version KeyVersion) error
+}
+
+// ReencryptionCheckpoint is the resumable progress of a re-encryption job
+type ReencryptionCheckpoint struct {
+	JobID         string     `json:"jobId"`
+	KeyID         KeyID      `json:"keyId"`
+	TargetVersion KeyVersion `json:"targetVersion"`
+	Pass          int        `json:"pass"`
+	Cursor        string     `json:"cursor"`
+	Scanned       int64      `json:"scanned"`
+	Reencrypted   int64      `json:"reencrypted"`
+	Skipped       int64      `json:"skipped"`
+	Failed        int64      `json:"failed"`
+	Done          bool       `json:"done"`
+	StartedAt     time.Time  `json:"startedAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
+
+// CheckpointStore persists re-encryption progress
+type CheckpointStore interface {
+	// Load returns nil without error when the job has no checkpoint
+	Load(ctx context.Context, jobID string) (*ReencryptionCheckpoint, error)
+	Save(ctx context.Context, checkpoint *ReencryptionCheckpoint) error
+}
+
+type ReencryptionConfig struct {
+	BatchSize     int           `yaml:"batch_size"`
+	BatchInterval time.Duration `yaml:"batch_interval"` // Pause between batches
+}
+
+func DefaultReencryptionConfig() ReencryptionConfig {
+	return ReencryptionConfig{
+		BatchSize:     100,
+		BatchInterval: 100 * time.Millisecond,
+	}
+}
+
+// ReencryptionWorker moves payloads encrypted under old versions of a key to
+// its latest version, in throttled batches. Progress is checkpointed after
+// every batch so an interrupted job resumes where it stopped.
+type ReencryptionWorker struct {
+	service     EncryptionService
+	keyRepo     KeyRepository
+	source      PayloadSource
+	checkpoints CheckpointStore
+	config      ReencryptionConfig
+	logger      *zap.Logger
+	metrics     Metrics
+}
+
+func NewReencryptionWorker(
+	service EncryptionService,
+	keyRepo KeyRepository,
+	source PayloadSource,
+	checkpoints CheckpointStore,
+	config ReencryptionConfig,
+	logger *zap.Logger,
+	metrics Metrics,
+) *ReencryptionWorker {
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultReencryptionConfig().BatchSize
+	}
+	if checkpoints == nil {
+		checkpoints = NewMemoryCheckpointStore()
+	}
+
+	return &ReencryptionWorker{
+		service:     service,
+		keyRepo:     keyRepo,
+		source:      source,
+		checkpoints: checkpoints,
+		config:      config,
+		logger:      logger,
+		metrics:     metrics,
+	}
+}
+
+// Run re-encrypts every payload under an older version of keyID. A job is
+// identified by the key and its latest version: running it again resumes an
+// unfinished job, returns a finished one, or retries the records that failed.
+func (w *ReencryptionWorker) Run(ctx context.Context, keyID KeyID) (*ReencryptionCheckpoint, error) {
+	latest, err := w.keyRepo.GetLatestKey(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	jobID := fmt.Sprintf("%s.v%d", keyID, latest.Version())
+	cp, err := w.checkpoints.Load(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case cp == nil:
+		cp = &ReencryptionCheckpoint{
+			JobID:         jobID,
+			KeyID:         keyID,
+			TargetVersion: latest.Version(),
+			Pass:          1,
+			StartedAt:     time.Now(),
+		}
+	case cp.Done && cp.Failed == 0:
+		return cp, nil
+	case cp.Done:
+		// Failed records were passed over; scan again for them
+		cp.Pass++
+		cp.Cursor = ""
+		cp.Failed = 0
+		cp.Done = false
+	}
+
+	w.logger.Info("re-encryption started",
+		zap.String("jobID", jobID),
+		zap.Int("pass", cp.Pass),
+		zap.String("cursor", cp.Cursor))
+
+	for {
+		records, next, err := w.source.Scan(ctx, keyID, cp.TargetVersion, cp.Cursor, w.config.BatchSize)
+		if err != nil {
+			w.metrics.IncrementErrorCount("reencrypt", "scan_failed")
+			return cp, NewEncryptionError(ErrCodeServiceUnavailable, "failed to scan payloads", err,
+				map[string]any{"jobID": jobID, "cursor": cp.Cursor})
+		}
+
+		for _, record := range records {
+			// Stop before the checkpoint moves past unprocessed records
+			if err := ctx.Err(); err != nil {
+				return cp, err
+			}
+			w.reencrypt(ctx, cp, record)
+		}
+
+		cp.Cursor = next
+		cp.Done = next == ""
+		cp.UpdatedAt = time.Now()
+		if err := w.checkpoints.Save(ctx, cp); err != nil {
+			return cp, err
+		}
+
+		w.logger.Info("re-encryption batch completed",
+			zap.String("jobID", jobID),
+			zap.Int("records", len(records)),
+			zap.Int64("reencrypted", cp.Reencrypted),
+			zap.Int64("failed", cp.Failed))
+
+		if cp.Done {
+			return cp, nil
+		}
+		if w.config.BatchInterval > 0 {
+			select {
+			case <-ctx.Done():
+				return cp, ctx.Err()
+			case <-time.After(w.config.BatchInterval):
+			}
+		}
+	}
+}
+
+func (w *ReencryptionWorker) reencrypt(ctx context.Context, cp *ReencryptionCheckpoint, record PayloadRecord) {
+	cp.Scanned++
+	payload := record.Payload
+	if payload == nil || payload.KeyID() != cp.KeyID || payload.KeyVersion() >= cp.TargetVersion {
+		cp.Skipped++
+		return
+	}
+
+	replacement, err := w.service.RotateKey(ctx, payload, cp.KeyID)
+	if err != nil {
+		cp.Failed++
+		w.metrics.IncrementErrorCount("reencrypt", "rotation_failed")
+		w.logger.Error("re-encryption failed", zap.String("recordID", record.ID), zap.Error(err))
+		return
+	}
+
+	if err := w.source.Replace(ctx, record.ID, payload, replacement); err != nil {
+		if hasErrorCode(err, ErrCodePayloadChanged) {
+			// Rewritten concurrently, and so under the latest key
+			cp.Skipped++
+			return
+		}
+		cp.Failed++
+		w.metrics.IncrementErrorCount("reencrypt", "replace_failed")
+		w.logger.Error("re-encrypted payload not saved", zap.String("recordID", record.ID), zap.Error(err))
+		return
+	}
+
+	cp.Reencrypted++
+	w.metrics.IncrementSuccessCount("reencrypt")
+}
+
+// RetireUnused deletes the versions of keyID older than the latest that no
+// payload references any more, and returns them
+func (w *ReencryptionWorker) RetireUnused(ctx context.Context, keyID KeyID) ([]KeyVersion, error) {
+	retirer, ok := w.keyRepo.(KeyRetirer)
+	if !ok {
+		return nil, NewEncryptionError(ErrCodeServiceUnavailable, "key repository cannot retire keys", nil, nil)
+	}
+
+	latest, err := w.keyRepo.GetLatestKey(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := w.source.CountByVersion(ctx, keyID)
+	if err != nil {
+		return nil, NewEncryptionError(ErrCodeServiceUnavailable, "failed to count payloads", err,
+			map[string]any{"keyID": keyID})
+	}
+	keys, err := w.keyRepo.ListKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var retired []KeyVersion
+	for _, key := range keys[keyID] {
+		version := key.Version()
+		if version >= latest.Version() || counts[version] > 0 {
+			continue
+		}
+		if err := retirer.RetireKey(ctx, keyID, version); err != nil {
+			return retired, err
+		}
+		retired = append(retired, version)
+	}
+	slices.Sort(retired)
+
+	if len(retired) > 0 {
+		w.logger.Info("unused key versions retired",
+			zap.String("keyID", string(keyID)),
+			zap.Int("count", len(retired)))
+	}
+	return retired, nil
+}
+
+// FieldRepositorySource exposes a ThreadSafeFieldRepository to the
+// re-encryption worker, scanning records in ID order
+type FieldRepositorySource struct {
+	repo *ThreadSafeFieldRepository
+}
+
+func NewFieldRepositorySource(repo *ThreadSafeFieldRepository) *FieldRepositorySource {
+	return &FieldRepositorySource{repo: repo}
+}
+
+func (s *FieldRepositorySource) Scan(ctx context.Context, keyID KeyID, below KeyVersion, cursor string, limit int) ([]PayloadRecord, string, error) {
+	s.repo.mu.RLock()
+	defer s.repo.mu.RUnlock()
+
+	ids := make([]string, 0, len(s.repo.fields))
+	for id := range s.repo.fields {
+		if id > cursor {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	var records []PayloadRecord
+	for i, id := range ids {
+		payload := s.repo.fields[id].Payload()
+		if payload.KeyID() != keyID || payload.KeyVersion() >= below {
+			continue
+		}
+		records = append(records, PayloadRecord{ID: id, Payload: payload})
+		if len(records) == limit {
+			if i == len(ids)-1 {
+				return records, "", nil
+			}
+			return records, id, nil
+		}
+	}
+	return records, "", nil
+}
+
+func (s *FieldRepositorySource) Replace(ctx context.Context, id string, old, replacement *EncryptedPayload) error {
+	field, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	field.mu.Lock()
+	defer field.mu.Unlock()
+
+	if field.payload != old {
+		return NewEncryptionError(ErrCodePayloadChanged, "payload changed since it was read", nil,
+			map[string]any{"fieldID": id})
+	}
+	field.payload = replacement
+	field.version++
+	field.updatedAt = time.Now()
+	return nil
+}
+
+func (s *FieldRepositorySource) CountByVersion(ctx context.Context, keyID KeyID) (map[KeyVersion]int, error) {
+	s.repo.mu.RLock()
+	defer s.repo.mu.RUnlock()
+
+	counts := make(map[KeyVersion]int)
+	for _, field := range s.repo.fields {
+		if payload := field.Payload(); payload.KeyID() == keyID {
+			counts[payload.KeyVersion()]++
+		}
+	}
+	return counts, nil
+}
+
+// MemoryCheckpointStore keeps checkpoints for the life of the process
+type MemoryCheckpointStore struct {
+	mu          sync.RWMutex
+	checkpoints map[string]ReencryptionCheckpoint
+}
+
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{checkpoints: make(map[string]ReencryptionCheckpoint)}
+}
+
+func (s *MemoryCheckpointStore) Load(ctx context.Context, jobID string) (*ReencryptionCheckpoint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	cp, exists := s.checkpoints[jobID]
+	if !exists {
+		return nil, nil
+	}
+	return &cp, nil
+}
+
+func (s *MemoryCheckpointStore) Save(ctx context.Context, checkpoint *ReencryptionCheckpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkpoints[checkpoint.JobID] = *checkpoint
+	return nil
+}
+
+// FileCheckpointStore keeps one JSON checkpoint file per job
+type FileCheckpointStore struct {
+	dir string
+}
+
+func NewFileCheckpointStore(dir string) (*FileCheckpointStore, error) {
+	if dir == "" {
+		return nil, NewEncryptionError(ErrCodeInvalidInput, "checkpoint directory cannot be empty", nil, nil)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, NewEncryptionError(ErrCodeServiceUnavailable, "failed to create checkpoint directory", err,
+			map[string]any{"dir": dir})
+	}
+	return &FileCheckpointStore{dir: dir}, nil
+}
+
+func (s *FileCheckpointStore) Load(ctx context.Context, jobID string) (*ReencryptionCheckpoint, error) {
+	path, err := s.path(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, NewEncryptionError(ErrCodeServiceUnavailable, "failed to read checkpoint", err,
+			map[string]any{"jobID": jobID})
+	}
+
+	var cp ReencryptionCheckpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, NewEncryptionError(ErrCodeInvalidInput, "corrupted checkpoint", err,
+			map[string]any{"jobID": jobID})
+	}
+	return &cp, nil
+}
+
+func (s *FileCheckpointStore) Save(ctx context.Context, checkpoint *ReencryptionCheckpoint) error {
+	path, err := s.path(checkpoint.JobID)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(checkpoint, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, data, 0o600); err != nil {
+		return NewEncryptionError(ErrCodeServiceUnavailable, "failed to write checkpoint", err,
+			map[string]any{"jobID": checkpoint.JobID})
+	}
+	return nil
+}
+
+func (s *FileCheckpointStore) path(jobID string) (string, error) {
+	// Job IDs are derived from key IDs and share their restrictions
+	if err := validateKeyID(KeyID(jobID)); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.dir, jobID+".json"), nil
+}
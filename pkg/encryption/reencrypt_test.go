@@ -0,0 +1,151 @@
+package encryption
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type reencryptionFixture struct {
+	repo    *EnvelopeKeyRepository
+	service EncryptionService
+	fields  *ThreadSafeFieldRepository
+	metrics *SimpleMetrics
+}
+
+// newReencryptionFixture stores count fields under version 1 of the
+// default key, then rotates it
+func newReencryptionFixture(t *testing.T, count int) *reencryptionFixture {
+	t.Helper()
+	ctx := context.Background()
+
+	f := &reencryptionFixture{metrics: NewSimpleMetrics()}
+	f.repo = newFileEnvelopeRepository(t, t.TempDir(), KeyManagementConfig{})
+	_, err := f.repo.EnsureKey(ctx, DefaultKeyID)
+	require.NoError(t, err)
+	f.service = NewEncryptionService(f.repo, 1024, zap.NewNop(), f.metrics)
+	f.fields = NewThreadSafeFieldRepository(zap.NewNop(), f.metrics)
+
+	for i := range count {
+		payload, err := f.service.Encrypt(ctx, fmt.Sprintf("secret-%02d", i), DefaultKeyID)
+		require.NoError(t, err)
+		field, err := NewFieldEncryption(fmt.Sprintf("user-%02d", i), "email", payload)
+		require.NoError(t, err)
+		require.NoError(t, f.fields.Save(ctx, field))
+	}
+
+	_, err = f.repo.RotateKey(ctx, DefaultKeyID)
+	require.NoError(t, err)
+	return f
+}
+
+func (f *reencryptionFixture) worker(source PayloadSource, checkpoints CheckpointStore) *ReencryptionWorker {
+	return NewReencryptionWorker(f.service, f.repo, source, checkpoints,
+		ReencryptionConfig{BatchSize: 10}, zap.NewNop(), f.metrics)
+}
+
+func TestReencryptionWorker(t *testing.T) {
+	ctx := context.Background()
+	f := newReencryptionFixture(t, 25)
+	source := NewFieldRepositorySource(f.fields)
+
+	worker := f.worker(source, NewMemoryCheckpointStore())
+	retired, err := worker.RetireUnused(ctx, DefaultKeyID)
+	require.NoError(t, err)
+	assert.Empty(t, retired, "version 1 is still referenced")
+
+	cp, err := worker.Run(ctx, DefaultKeyID)
+	require.NoError(t, err)
+	assert.True(t, cp.Done)
+	assert.Equal(t, "default.v2", cp.JobID)
+	assert.Equal(t, int64(25), cp.Reencrypted)
+	assert.Zero(t, cp.Failed)
+	assert.Equal(t, int64(25), f.metrics.counters["reencrypt_success"])
+
+	counts, err := source.CountByVersion(ctx, DefaultKeyID)
+	require.NoError(t, err)
+	assert.Equal(t, map[KeyVersion]int{2: 25}, counts)
+
+	field, err := f.fields.FindByID(ctx, "user-07")
+	require.NoError(t, err)
+	plaintext, err := f.service.Decrypt(ctx, field.Payload())
+	require.NoError(t, err)
+	assert.Equal(t, "secret-07", plaintext)
+
+	// A finished job is not run again
+	again, err := worker.Run(ctx, DefaultKeyID)
+	require.NoError(t, err)
+	assert.Equal(t, cp.UpdatedAt, again.UpdatedAt)
+
+	retired, err = worker.RetireUnused(ctx, DefaultKeyID)
+	require.NoError(t, err)
+	assert.Equal(t, []KeyVersion{1}, retired)
+	_, err = f.repo.GetKey(ctx, DefaultKeyID, 1)
+	assert.True(t, hasErrorCode(err, ErrCodeKeyNotFound))
+}
+
+// cancellingSource cancels the run after a number of replacements
+type cancellingSource struct {
+	*FieldRepositorySource
+	cancel context.CancelFunc
+	after  int
+}
+
+func (s *cancellingSource) Replace(ctx context.Context, id string, old, replacement *EncryptedPayload) error {
+	if s.after--; s.after == 0 {
+		s.cancel()
+	}
+	return s.FieldRepositorySource.Replace(ctx, id, old, replacement)
+}
+
+func TestReencryptionWorkerResumes(t *testing.T) {
+	f := newReencryptionFixture(t, 25)
+	checkpoints, err := NewFileCheckpointStore(t.TempDir())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	source := &cancellingSource{FieldRepositorySource: NewFieldRepositorySource(f.fields), cancel: cancel, after: 10}
+	_, err = f.worker(source, checkpoints).Run(ctx, DefaultKeyID)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	saved, err := checkpoints.Load(context.Background(), "default.v2")
+	require.NoError(t, err)
+	require.NotNil(t, saved)
+	assert.False(t, saved.Done)
+	assert.Equal(t, "user-09", saved.Cursor)
+	assert.Equal(t, int64(10), saved.Reencrypted)
+
+	cp, err := f.worker(NewFieldRepositorySource(f.fields), checkpoints).Run(context.Background(), DefaultKeyID)
+	require.NoError(t, err)
+	assert.True(t, cp.Done)
+	assert.Equal(t, int64(25), cp.Reencrypted)
+	assert.Equal(t, 1, cp.Pass)
+}
+
+func TestFieldRepositorySourceReplaceDetectsChanges(t *testing.T) {
+	ctx := context.Background()
+	f := newReencryptionFixture(t, 1)
+	source := NewFieldRepositorySource(f.fields)
+
+	records, next, err := source.Scan(ctx, DefaultKeyID, 2, "", 10)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Empty(t, next)
+
+	field, err := f.fields.FindByID(ctx, "user-00")
+	require.NoError(t, err)
+	current, err := f.service.Encrypt(ctx, "changed", DefaultKeyID)
+	require.NoError(t, err)
+	require.NoError(t, field.UpdatePayload(current))
+
+	err = source.Replace(ctx, "user-00", records[0].Payload, current)
+	assert.True(t, hasErrorCode(err, ErrCodePayloadChanged))
+
+	cp, err := f.worker(source, nil).Run(ctx, DefaultKeyID)
+	require.NoError(t, err)
+	assert.Zero(t, cp.Reencrypted, "the updated payload already uses the latest key")
+}
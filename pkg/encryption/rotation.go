@@ -0,0 +1,125 @@
+package encryption
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// KeyRotationScheduler rotates every key whose latest version is older than
+// KeyManagementConfig.RotationFreq. Ages come from the stored metadata of
+// repositories implementing KeyMetadataLister, so checking does not unwrap
+// any key. Instances sharing a key store may all
+// run a scheduler: a version stored by one instance makes the key current
+// for the others.
+type KeyRotationScheduler struct {
+	repo      KeyRepository
+	frequency time.Duration
+	interval  time.Duration
+	logger    *zap.Logger
+	metrics   Metrics
+	now       func() time.Time
+}
+
+func NewKeyRotationScheduler(repo KeyRepository, config KeyManagementConfig, logger *zap.Logger, metrics Metrics) *KeyRotationScheduler {
+	// Check often enough that a key is never more than ~4% past due
+	interval := min(max(config.RotationFreq/24, time.Minute), time.Hour)
+
+	return &KeyRotationScheduler{
+		repo:      repo,
+		frequency: config.RotationFreq,
+		interval:  interval,
+		logger:    logger,
+		metrics:   metrics,
+		now:       time.Now,
+	}
+}
+
+// Run rotates due keys until ctx is done. It returns immediately when
+// rotation is disabled by a zero RotationFreq.
+func (s *KeyRotationScheduler) Run(ctx context.Context) error {
+	if s.frequency <= 0 {
+		return nil
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.RotateDue(ctx); err != nil {
+			s.logger.Error("scheduled key rotation failed", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// RotateDue rotates the keys that are due and returns their new versions.
+// A failed key does not stop the others; the first error is returned.
+func (s *KeyRotationScheduler) RotateDue(ctx context.Context) ([]*EncryptionKey, error) {
+	if s.frequency <= 0 {
+		return nil, nil
+	}
+
+	keys, err := latestKeyMetadata(ctx, s.repo)
+	if err != nil {
+		return nil, NewEncryptionError(ErrCodeKeyRotationFailed, "failed to list keys", err, nil)
+	}
+
+	var rotated []*EncryptionKey
+	var firstErr error
+	for keyID, latest := range keys {
+		if s.now().Before(latest.CreatedAt.Add(s.frequency)) {
+			continue
+		}
+
+		key, err := s.repo.RotateKey(ctx, keyID)
+		if err != nil {
+			s.metrics.IncrementErrorCount("rotate_key", "rotation_failed")
+			s.logger.Error("key rotation failed", zap.String("keyID", string(keyID)), zap.Error(err))
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		s.metrics.RecordKeyRotation(string(keyID))
+		s.logger.Info("key rotated on schedule",
+			zap.String("keyID", string(keyID)),
+			zap.Uint32("previousVersion", uint32(latest.Version)),
+			zap.Uint32("newVersion", uint32(key.Version())))
+		rotated = append(rotated, key)
+	}
+	return rotated, firstErr
+}
+
+// latestKeyMetadata describes the latest version of every key, from stored
+// metadata when the repository provides it. Other repositories hold their
+// keys in memory and are listed instead.
+func latestKeyMetadata(ctx context.Context, repo KeyRepository) (map[KeyID]KeyMetadata, error) {
+	if lister, ok := repo.(KeyMetadataLister); ok {
+		return lister.LatestKeyMetadata(ctx)
+	}
+
+	keys, err := repo.ListKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[KeyID]KeyMetadata, len(keys))
+	for keyID, versions := range keys {
+		var latest *EncryptionKey
+		for _, key := range versions {
+			if latest == nil || key.Version() > latest.Version() {
+				latest = key
+			}
+		}
+		if latest != nil {
+			result[keyID] = KeyMetadata{Version: latest.Version(), CreatedAt: latest.CreatedAt(), ExpiresAt: latest.ExpiresAt()}
+		}
+	}
+	return result, nil
+}
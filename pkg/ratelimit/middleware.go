@@ -0,0 +1,125 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/niiniyare/ruun/schema"
+)
+
+// Locals read by the default key function
+const (
+	LocalsTenantID = "tenant_id"
+	LocalsUserID   = "user_id"
+	LocalsSchemaID = "schema_id"
+)
+
+// Standard rate limit response headers
+const (
+	HeaderLimit      = "RateLimit-Limit"
+	HeaderRemaining  = "RateLimit-Remaining"
+	HeaderReset      = "RateLimit-Reset"
+	HeaderPolicy     = "RateLimit-Policy"
+	HeaderRetryAfter = "Retry-After"
+)
+
+// MiddlewareConfig configures NewMiddleware
+type MiddlewareConfig struct {
+	Limiter *Limiter
+	// KeyFunc identifies the request; DefaultKey is used when nil
+	KeyFunc func(c *fiber.Ctx) Key
+	// Schema returns the schema a request submits to, whose
+	// Security.RateLimit then also applies; may be nil or return nil
+	Schema func(c *fiber.Ctx) *schema.Schema
+	// SkipPaths are request paths that are never limited
+	SkipPaths []string
+	// ErrorHandler handles limiter failures. By default requests are let
+	// through, so an unavailable store does not take the service down.
+	ErrorHandler func(c *fiber.Ctx, err error) error
+}
+
+// DefaultKey builds a key from the tenant, user and schema IDs stored in
+// Locals, the client IP, and the method and matched route as the action.
+// Mounted with app.Use the route is the mount prefix, so every route below
+// it shares one quota; attach the middleware to routes for per-route quotas.
+func DefaultKey(c *fiber.Ctx) Key {
+	return Key{
+		TenantID: localString(c, LocalsTenantID),
+		UserID:   localString(c, LocalsUserID),
+		IP:       c.IP(),
+		SchemaID: localString(c, LocalsSchemaID),
+		Action:   c.Method() + " " + c.Route().Path,
+	}
+}
+
+// NewMiddleware limits requests and sets the RateLimit-Limit,
+// RateLimit-Remaining, RateLimit-Reset and RateLimit-Policy headers. Denied
+// requests fail with 429 Too Many Requests and a Retry-After header.
+func NewMiddleware(config MiddlewareConfig) fiber.Handler {
+	if config.Limiter == nil {
+		panic("ratelimit: middleware requires a limiter")
+	}
+	if config.KeyFunc == nil {
+		config.KeyFunc = DefaultKey
+	}
+	if config.ErrorHandler == nil {
+		config.ErrorHandler = func(c *fiber.Ctx, err error) error { return c.Next() }
+	}
+
+	return func(c *fiber.Ctx) error {
+		if slices.Contains(config.SkipPaths, c.Path()) {
+			return c.Next()
+		}
+
+		key := config.KeyFunc(c)
+		var s *schema.Schema
+		if config.Schema != nil {
+			s = config.Schema(c)
+		}
+
+		var result *Result
+		var err error
+		if s != nil {
+			result, err = config.Limiter.AllowSchema(c.UserContext(), key, s)
+		} else {
+			result, err = config.Limiter.Allow(c.UserContext(), key)
+		}
+		if err != nil {
+			return config.ErrorHandler(c, err)
+		}
+
+		if result.Limit > 0 {
+			c.Set(HeaderLimit, strconv.Itoa(result.Limit))
+			c.Set(HeaderRemaining, strconv.Itoa(result.Remaining))
+			c.Set(HeaderReset, strconv.Itoa(ceilSeconds(result.Reset)))
+			c.Set(HeaderPolicy, result.Policy)
+		}
+		if !result.Allowed {
+			c.Set(HeaderRetryAfter, strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			return fiber.NewError(fiber.StatusTooManyRequests, "rate limit exceeded")
+		}
+		return c.Next()
+	}
+}
+
+func localString(c *fiber.Ctx, key string) string {
+	switch v := c.Locals(key).(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
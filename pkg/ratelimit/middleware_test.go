@@ -0,0 +1,84 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	limiter, _, _ := newTestLimiter(t, &Plan{Name: "free", Limits: map[string]Limit{
+		"POST /forms/:id": {Requests: 1, Window: time.Minute, PerUser: true},
+	}})
+
+	app := fiber.New()
+	identify := func(c *fiber.Ctx) error {
+		c.Locals(LocalsTenantID, "acme")
+		c.Locals(LocalsUserID, c.Get("X-User"))
+		return c.Next()
+	}
+	limit := NewMiddleware(MiddlewareConfig{Limiter: limiter, SkipPaths: []string{"/forms/health"}})
+	app.Post("/forms/:id", identify, limit, func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+	submit := func(path, user string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(fiber.MethodPost, path, nil)
+		req.Header.Set("X-User", user)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		rec := httptest.NewRecorder()
+		rec.Code = resp.StatusCode
+		for name, values := range resp.Header {
+			rec.Header()[name] = values
+		}
+		return rec
+	}
+
+	first := submit("/forms/invoice", "u1")
+	assert.Equal(t, fiber.StatusOK, first.Code)
+	assert.Equal(t, "1", first.Header().Get(HeaderLimit))
+	assert.Equal(t, "0", first.Header().Get(HeaderRemaining))
+	assert.Equal(t, "60", first.Header().Get(HeaderReset))
+	assert.Equal(t, "1;w=60", first.Header().Get(HeaderPolicy))
+
+	denied := submit("/forms/customer", "u1")
+	assert.Equal(t, fiber.StatusTooManyRequests, denied.Code, "the quota is per route, not per path")
+	assert.Equal(t, "60", denied.Header().Get(HeaderRetryAfter))
+
+	assert.Equal(t, fiber.StatusOK, submit("/forms/invoice", "u2").Code)
+	assert.Equal(t, fiber.StatusOK, submit("/forms/health", "u1").Code)
+}
+
+// planProviderFunc adapts a function to PlanProvider
+type planProviderFunc func(ctx context.Context, tenantID string) (*Plan, error)
+
+func (f planProviderFunc) TenantPlan(ctx context.Context, tenantID string) (*Plan, error) {
+	return f(ctx, tenantID)
+}
+
+func TestMiddlewareErrorHandler(t *testing.T) {
+	limiter, err := NewLimiter(NewMemoryStore(), planProviderFunc(func(context.Context, string) (*Plan, error) {
+		return nil, errors.New("plans unavailable")
+	}))
+	require.NoError(t, err)
+
+	open := fiber.New()
+	open.Get("/", NewMiddleware(MiddlewareConfig{Limiter: limiter}), func(c *fiber.Ctx) error { return c.SendString("ok") })
+	resp, err := open.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode, "requests are let through by default")
+
+	closed := fiber.New()
+	closed.Get("/", NewMiddleware(MiddlewareConfig{
+		Limiter:      limiter,
+		ErrorHandler: func(c *fiber.Ctx, err error) error { return fiber.ErrServiceUnavailable },
+	}), func(c *fiber.Ctx) error { return c.SendString("ok") })
+	resp, err = closed.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+}
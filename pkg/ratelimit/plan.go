@@ -0,0 +1,98 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// AnyAction keys a plan's fallback limit for actions without their own
+const AnyAction = "*"
+
+// Plan is a set of limits a tenant subscribes to, keyed by action
+type Plan struct {
+	Name   string           `json:"name"`
+	Limits map[string]Limit `json:"limits"`
+}
+
+// LimitFor returns the limit for an action, falling back to AnyAction
+func (p *Plan) LimitFor(action string) (Limit, bool) {
+	if p == nil {
+		return Limit{}, false
+	}
+	if limit, ok := p.Limits[action]; ok {
+		return limit, true
+	}
+	limit, ok := p.Limits[AnyAction]
+	return limit, ok
+}
+
+// Validate checks every limit of the plan
+func (p *Plan) Validate() error {
+	if p.Name == "" {
+		return errors.New("ratelimit: plan name is required")
+	}
+	for action, limit := range p.Limits {
+		if err := limit.Validate(); err != nil {
+			return fmt.Errorf("plan %s action %s: %w", p.Name, action, err)
+		}
+	}
+	return nil
+}
+
+// PlanProvider returns the plan a tenant is on. A nil plan leaves the
+// tenant unlimited.
+type PlanProvider interface {
+	TenantPlan(ctx context.Context, tenantID string) (*Plan, error)
+}
+
+// StaticPlans is a PlanProvider over plans defined in configuration.
+// Tenants without an assignment get the default plan.
+type StaticPlans struct {
+	plans       map[string]*Plan
+	tenants     map[string]string
+	defaultPlan string
+	mu          sync.RWMutex
+}
+
+func NewStaticPlans(defaultPlan string, plans ...*Plan) (*StaticPlans, error) {
+	s := &StaticPlans{
+		plans:       make(map[string]*Plan, len(plans)),
+		tenants:     make(map[string]string),
+		defaultPlan: defaultPlan,
+	}
+	for _, plan := range plans {
+		if err := plan.Validate(); err != nil {
+			return nil, err
+		}
+		s.plans[plan.Name] = plan
+	}
+	if _, ok := s.plans[defaultPlan]; defaultPlan != "" && !ok {
+		return nil, fmt.Errorf("ratelimit: unknown default plan %q", defaultPlan)
+	}
+	return s, nil
+}
+
+// Assign puts a tenant on a plan
+func (s *StaticPlans) Assign(tenantID, plan string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.plans[plan]; !ok {
+		return fmt.Errorf("ratelimit: unknown plan %q", plan)
+	}
+	s.tenants[tenantID] = plan
+	return nil
+}
+
+func (s *StaticPlans) TenantPlan(ctx context.Context, tenantID string) (*Plan, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	name, ok := s.tenants[tenantID]
+	if !ok {
+		name = s.defaultPlan
+	}
+	return s.plans[name], nil
+}
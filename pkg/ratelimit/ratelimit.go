@@ -0,0 +1,355 @@
+// Package ratelimit enforces per-tenant request quotas. Limits come from the
+// tenant's plan and from a schema's Security.RateLimit; each is counted
+// under a key combining the tenant and action with, as the limit requires,
+// the user, client IP and schema.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/niiniyare/ruun/pkg/config"
+	"github.com/niiniyare/ruun/schema"
+)
+
+// Algorithm selects how a limit is counted
+type Algorithm string
+
+const (
+	// TokenBucket refills Requests tokens per Window up to Burst, allowing
+	// short bursts above the average rate
+	TokenBucket Algorithm = "token_bucket"
+	// SlidingWindow allows Requests per rolling Window, estimated from the
+	// current and previous fixed windows
+	SlidingWindow Algorithm = "sliding_window"
+)
+
+// Limit is a quota for one action
+type Limit struct {
+	Algorithm Algorithm     `json:"algorithm,omitempty"`
+	Requests  int           `json:"requests"`
+	Window    time.Duration `json:"window"`
+	// Burst is the token bucket capacity; it defaults to Requests
+	Burst int `json:"burst,omitempty"`
+	// PerUser, PerIP and PerSchema split the tenant's quota further
+	PerUser   bool `json:"perUser,omitempty"`
+	PerIP     bool `json:"perIp,omitempty"`
+	PerSchema bool `json:"perSchema,omitempty"`
+}
+
+// Validate checks the limit is usable
+func (l Limit) Validate() error {
+	if l.Requests <= 0 {
+		return errors.New("ratelimit: requests must be positive")
+	}
+	if l.Window <= 0 {
+		return errors.New("ratelimit: window must be positive")
+	}
+	switch l.Algorithm {
+	case "", TokenBucket, SlidingWindow:
+		return nil
+	}
+	return fmt.Errorf("ratelimit: unknown algorithm %q", l.Algorithm)
+}
+
+// policy describes the limit for the RateLimit-Policy header
+func (l Limit) policy() string {
+	if l.algorithm() == TokenBucket && l.capacity() != l.Requests {
+		return fmt.Sprintf("%d;w=%d;burst=%d", l.Requests, int(l.Window.Seconds()), l.capacity())
+	}
+	return fmt.Sprintf("%d;w=%d", l.Requests, int(l.Window.Seconds()))
+}
+
+func (l Limit) algorithm() Algorithm {
+	if l.Algorithm == "" {
+		return TokenBucket
+	}
+	return l.Algorithm
+}
+
+func (l Limit) capacity() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.Requests
+}
+
+// LimitFromSchema converts a schema's rate limit; ok is false when it is
+// disabled. ByTenant needs no equivalent since every key includes the tenant.
+func LimitFromSchema(rl *schema.RateLimit) (limit Limit, ok bool) {
+	if rl == nil || !rl.Enabled {
+		return Limit{}, false
+	}
+	return Limit{
+		Algorithm: TokenBucket,
+		Requests:  rl.MaxRequests,
+		Window:    rl.Window,
+		Burst:     rl.Burst,
+		PerUser:   rl.ByUser,
+		PerIP:     rl.ByIP,
+		PerSchema: true,
+	}, true
+}
+
+// LimitFromConfig converts the UI service's rate limit configuration, for
+// use as the default plan's limit; ok is false when it is disabled
+func LimitFromConfig(cfg config.UIRateLimitConfig) (limit Limit, ok bool) {
+	if !cfg.Enabled {
+		return Limit{}, false
+	}
+	return Limit{
+		Algorithm: TokenBucket,
+		Requests:  cfg.Requests,
+		Window:    cfg.Window,
+		Burst:     cfg.BurstLimit,
+		PerUser:   true,
+		PerIP:     true,
+	}, true
+}
+
+// Key identifies who is making a request and what they are doing. Requests
+// with an empty TenantID, such as unauthenticated ones, are counted under the
+// default plan in one bucket shared by all of them, unless the limit counts
+// PerIP or PerUser; give such callers a limit with PerIP set.
+type Key struct {
+	TenantID string
+	UserID   string
+	IP       string
+	SchemaID string
+	Action   string
+}
+
+// scoped returns the store key for a limit, keeping only the components
+// the limit counts by
+func (k Key) scoped(name string, limit Limit) string {
+	parts := []string{name, "t=" + url.PathEscape(k.TenantID), "a=" + url.PathEscape(k.Action)}
+	if limit.PerUser {
+		parts = append(parts, "u="+url.PathEscape(k.UserID))
+	}
+	if limit.PerIP {
+		parts = append(parts, "ip="+url.PathEscape(k.IP))
+	}
+	if limit.PerSchema {
+		parts = append(parts, "s="+url.PathEscape(k.SchemaID))
+	}
+	return strings.Join(parts, "|")
+}
+
+// Result is the outcome of a rate limit check
+type Result struct {
+	Allowed bool
+	// Limit is the quota applied; 0 means the request was not limited
+	Limit     int
+	Remaining int
+	// Reset is when the quota is fully available again
+	Reset time.Duration
+	// RetryAfter is how long a denied request should wait
+	RetryAfter time.Duration
+	// Policy describes the limit for the RateLimit-Policy header
+	Policy string
+}
+
+// moreRestrictive reports whether r should be reported instead of other
+func (r *Result) moreRestrictive(other *Result) bool {
+	if other == nil || other.Limit == 0 {
+		return true
+	}
+	if r.Allowed != other.Allowed {
+		return !r.Allowed
+	}
+	if !r.Allowed {
+		return r.RetryAfter > other.RetryAfter
+	}
+	return r.Remaining < other.Remaining
+}
+
+// Limiter checks requests against tenant plans and schema limits
+type Limiter struct {
+	store Store
+	plans PlanProvider
+	now   func() time.Time
+}
+
+func NewLimiter(store Store, plans PlanProvider) (*Limiter, error) {
+	if store == nil {
+		return nil, errors.New("ratelimit: store is required")
+	}
+	if plans == nil {
+		return nil, errors.New("ratelimit: plan provider is required")
+	}
+	return &Limiter{store: store, plans: plans, now: time.Now}, nil
+}
+
+// Allow counts a request against the tenant plan's limit for its action.
+// Requests whose plan has no limit for the action are always allowed.
+func (l *Limiter) Allow(ctx context.Context, key Key) (*Result, error) {
+	return l.check(ctx, key, nil)
+}
+
+// AllowSchema counts a request against both the tenant plan and the
+// schema's Security.RateLimit, reporting whichever is more restrictive. A
+// request denied by one limit spends nothing from the other. A nil schema
+// counts against the plan only, as Allow does.
+func (l *Limiter) AllowSchema(ctx context.Context, key Key, s *schema.Schema) (*Result, error) {
+	if s == nil {
+		return l.check(ctx, key, nil)
+	}
+	key.SchemaID = s.ID
+	return l.check(ctx, key, s)
+}
+
+// check takes from the plan limit, then the schema limit. The plan request
+// is given back when the schema limit denies, since the two keys cannot be
+// updated in one atomic step.
+func (l *Limiter) check(ctx context.Context, key Key, s *schema.Schema) (*Result, error) {
+	plan, err := l.plans.TenantPlan(ctx, key.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := l.now()
+	result := &Result{Allowed: true}
+	planLimit, hasPlanLimit := plan.LimitFor(key.Action)
+	var planKey string
+	if hasPlanLimit {
+		planKey = key.scoped("plan:"+plan.Name, planLimit)
+		if result, err = l.take(ctx, planKey, planLimit, now); err != nil {
+			return nil, err
+		}
+		if !result.Allowed {
+			return result, nil
+		}
+	}
+	if s != nil && s.Security != nil && s.Security.IsRateLimitEnabled() {
+		limit, _ := LimitFromSchema(s.Security.RateLimit)
+		schemaResult, err := l.take(ctx, key.scoped("schema", limit), limit, now)
+		if err != nil {
+			return nil, err
+		}
+		if !schemaResult.Allowed && hasPlanLimit {
+			if err := l.giveBack(ctx, planKey, planLimit, now); err != nil {
+				return nil, err
+			}
+		}
+		if schemaResult.moreRestrictive(result) {
+			result = schemaResult
+		}
+	}
+	return result, nil
+}
+
+// take spends one request of a limit
+func (l *Limiter) take(ctx context.Context, key string, limit Limit, now time.Time) (*Result, error) {
+	if err := limit.Validate(); err != nil {
+		return nil, err
+	}
+	if limit.algorithm() == SlidingWindow {
+		return l.takeWindow(ctx, key, limit, now)
+	}
+	return l.takeTokens(ctx, key, limit, now)
+}
+
+// giveBack returns the request take spent at now
+func (l *Limiter) giveBack(ctx context.Context, key string, limit Limit, now time.Time) error {
+	if limit.algorithm() == SlidingWindow {
+		return l.store.UncountWindow(ctx, windowRequest(key, limit, now))
+	}
+	return l.store.ReturnTokens(ctx, tokenRequest(key, limit, now))
+}
+
+func tokenRequest(key string, limit Limit, now time.Time) TokenRequest {
+	capacity := float64(limit.capacity())
+	perSecond := float64(limit.Requests) / limit.Window.Seconds()
+	return TokenRequest{
+		Key:       key,
+		Capacity:  capacity,
+		PerSecond: perSecond,
+		Cost:      1,
+		Now:       now,
+		// An idle bucket is full again after this long; forget it
+		TTL: seconds(capacity/perSecond) + time.Second,
+	}
+}
+
+func windowRequest(key string, limit Limit, now time.Time) WindowRequest {
+	start := now.Truncate(limit.Window)
+	return WindowRequest{
+		Key:         key,
+		WindowStart: start,
+		Window:      limit.Window,
+		PrevWeight:  1 - float64(now.Sub(start))/float64(limit.Window),
+		Limit:       limit.Requests,
+		Cost:        1,
+	}
+}
+
+func (l *Limiter) takeTokens(ctx context.Context, key string, limit Limit, now time.Time) (*Result, error) {
+	req := tokenRequest(key, limit, now)
+	allowed, tokens, err := l.store.TakeTokens(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Allowed:   allowed,
+		Limit:     limit.capacity(),
+		Remaining: int(math.Floor(tokens)),
+		Reset:     seconds((req.Capacity - tokens) / req.PerSecond),
+		Policy:    limit.policy(),
+	}
+	if !allowed {
+		result.RetryAfter = seconds((1 - tokens) / req.PerSecond)
+	}
+	return result, nil
+}
+
+func (l *Limiter) takeWindow(ctx context.Context, key string, limit Limit, now time.Time) (*Result, error) {
+	req := windowRequest(key, limit, now)
+	allowed, prev, curr, err := l.store.CountWindow(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	elapsed := now.Sub(req.WindowStart)
+	used := float64(prev)*req.PrevWeight + float64(curr)
+	result := &Result{
+		Allowed:   allowed,
+		Limit:     limit.Requests,
+		Remaining: max(0, limit.Requests-int(math.Ceil(used))),
+		Reset:     limit.Window - elapsed,
+		Policy:    limit.policy(),
+	}
+	if !allowed {
+		result.RetryAfter = windowRetryAfter(limit, elapsed, prev, curr)
+	}
+	return result, nil
+}
+
+// windowRetryAfter returns how long until one more request fits, as the
+// previous window's weight decays and, if needed, the window rolls over
+func windowRetryAfter(limit Limit, elapsed time.Duration, prev, curr int64) time.Duration {
+	window := float64(limit.Window)
+	free := float64(limit.Requests) - float64(curr) - 1
+	if free >= 0 && prev > 0 {
+		// prev * (1 - t/window) <= free
+		wait := time.Duration((1-free/float64(prev))*window) - elapsed
+		return max(wait, 0)
+	}
+
+	// Wait for the next window, where the current count becomes previous
+	untilNext := limit.Window - elapsed
+	free = float64(limit.Requests) - 1
+	if curr == 0 || float64(curr) <= free {
+		return untilNext
+	}
+	return untilNext + time.Duration((1-free/float64(curr))*window)
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
@@ -0,0 +1,230 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/niiniyare/ruun/schema"
+)
+
+// testClock is a manually advanced clock
+type testClock struct{ now time.Time }
+
+func (c *testClock) Now() time.Time          { return c.now }
+func (c *testClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newTestLimiter(t *testing.T, plans ...*Plan) (*Limiter, *StaticPlans, *testClock) {
+	t.Helper()
+	provider, err := NewStaticPlans(plans[0].Name, plans...)
+	require.NoError(t, err)
+	limiter, err := NewLimiter(NewMemoryStore(), provider)
+	require.NoError(t, err)
+	clock := &testClock{now: time.Unix(1_700_000_000, 0)}
+	limiter.now = clock.Now
+	return limiter, provider, clock
+}
+
+func allow(t *testing.T, limiter *Limiter, key Key) *Result {
+	t.Helper()
+	result, err := limiter.Allow(context.Background(), key)
+	require.NoError(t, err)
+	return result
+}
+
+func TestTokenBucket(t *testing.T) {
+	limiter, _, clock := newTestLimiter(t, &Plan{Name: "free", Limits: map[string]Limit{
+		AnyAction: {Requests: 2, Window: time.Second, Burst: 3},
+	}})
+	key := Key{TenantID: "acme", Action: "submit"}
+
+	for i := range 3 {
+		result := allow(t, limiter, key)
+		require.True(t, result.Allowed)
+		assert.Equal(t, 2-i, result.Remaining)
+	}
+	denied := allow(t, limiter, key)
+	assert.False(t, denied.Allowed)
+	assert.Equal(t, 3, denied.Limit)
+	assert.Equal(t, 500*time.Millisecond, denied.RetryAfter)
+	assert.Equal(t, 1500*time.Millisecond, denied.Reset)
+	assert.Equal(t, "2;w=1;burst=3", denied.Policy)
+
+	clock.Advance(denied.RetryAfter)
+	assert.True(t, allow(t, limiter, key).Allowed)
+	assert.False(t, allow(t, limiter, key).Allowed)
+}
+
+func TestSlidingWindow(t *testing.T) {
+	limiter, _, clock := newTestLimiter(t, &Plan{Name: "free", Limits: map[string]Limit{
+		"export": {Algorithm: SlidingWindow, Requests: 4, Window: 10 * time.Second},
+	}})
+	key := Key{TenantID: "acme", Action: "export"}
+	clock.now = clock.now.Truncate(10 * time.Second)
+
+	for range 4 {
+		require.True(t, allow(t, limiter, key).Allowed)
+	}
+	denied := allow(t, limiter, key)
+	require.False(t, denied.Allowed)
+	assert.Equal(t, 0, denied.Remaining)
+
+	// RetryAfter lands a quarter into the next window, where three of the
+	// previous four requests still count
+	assert.Equal(t, 12500*time.Millisecond, denied.RetryAfter)
+	clock.Advance(denied.RetryAfter)
+	result := allow(t, limiter, key)
+	require.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+	denied = allow(t, limiter, key)
+	require.False(t, denied.Allowed)
+
+	clock.Advance(denied.RetryAfter)
+	assert.True(t, allow(t, limiter, key).Allowed, "allowed once RetryAfter has passed")
+}
+
+func TestLimiterScopesKeys(t *testing.T) {
+	limiter, plans, _ := newTestLimiter(t,
+		&Plan{Name: "free", Limits: map[string]Limit{
+			"submit":  {Requests: 1, Window: time.Minute, PerUser: true},
+			AnyAction: {Requests: 2, Window: time.Minute},
+		}},
+		&Plan{Name: "enterprise"},
+	)
+	require.NoError(t, plans.Assign("globex", "enterprise"))
+
+	assert.True(t, allow(t, limiter, Key{TenantID: "acme", UserID: "u1", Action: "submit"}).Allowed)
+	assert.False(t, allow(t, limiter, Key{TenantID: "acme", UserID: "u1", Action: "submit"}).Allowed)
+	assert.True(t, allow(t, limiter, Key{TenantID: "acme", UserID: "u2", Action: "submit"}).Allowed, "users have separate quotas")
+	assert.True(t, allow(t, limiter, Key{TenantID: "initech", UserID: "u1", Action: "submit"}).Allowed, "tenants have separate quotas")
+
+	// The fallback limit is shared by the tenant's users
+	assert.True(t, allow(t, limiter, Key{TenantID: "acme", UserID: "u1", Action: "search"}).Allowed)
+	assert.True(t, allow(t, limiter, Key{TenantID: "acme", UserID: "u2", Action: "search"}).Allowed)
+	assert.False(t, allow(t, limiter, Key{TenantID: "acme", UserID: "u3", Action: "search"}).Allowed)
+
+	unlimited := allow(t, limiter, Key{TenantID: "globex", Action: "submit"})
+	assert.True(t, unlimited.Allowed)
+	assert.Zero(t, unlimited.Limit)
+
+	assert.Error(t, plans.Assign("globex", "platinum"))
+}
+
+func TestLimiterAllowSchema(t *testing.T) {
+	limiter, _, _ := newTestLimiter(t, &Plan{Name: "free", Limits: map[string]Limit{
+		AnyAction: {Requests: 100, Window: time.Minute},
+	}})
+	form := &schema.Schema{ID: "invoice", Security: &schema.Security{RateLimit: &schema.RateLimit{
+		Enabled: true, MaxRequests: 2, Window: time.Minute, ByUser: true,
+	}}}
+	key := Key{TenantID: "acme", UserID: "u1", Action: "submit"}
+
+	result, err := limiter.AllowSchema(context.Background(), key, form)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Limit, "the stricter schema limit is reported")
+	assert.Equal(t, 1, result.Remaining)
+
+	_, err = limiter.AllowSchema(context.Background(), key, form)
+	require.NoError(t, err)
+	result, err = limiter.AllowSchema(context.Background(), key, form)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+
+	other := &schema.Schema{ID: "customer", Security: form.Security}
+	result, err = limiter.AllowSchema(context.Background(), key, other)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed, "schema limits are counted per schema")
+
+	result, err = limiter.AllowSchema(context.Background(), key, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 100, result.Limit, "without a schema only the plan applies")
+}
+
+func TestLimiterAllowSchemaSpendsOnlyWhenAllowed(t *testing.T) {
+	ctx := context.Background()
+	for _, algorithm := range []Algorithm{TokenBucket, SlidingWindow} {
+		t.Run(string(algorithm), func(t *testing.T) {
+			limiter, plans, _ := newTestLimiter(t,
+				&Plan{Name: "free", Limits: map[string]Limit{AnyAction: {Algorithm: algorithm, Requests: 3, Window: time.Minute}}},
+				&Plan{Name: "enterprise"},
+			)
+			form := &schema.Schema{ID: "invoice", Security: &schema.Security{RateLimit: &schema.RateLimit{
+				Enabled: true, MaxRequests: 2, Window: time.Minute,
+			}}}
+			key := Key{TenantID: "acme", Action: "submit"}
+
+			for range 2 {
+				result, err := limiter.AllowSchema(ctx, key, form)
+				require.NoError(t, err)
+				require.True(t, result.Allowed)
+			}
+			for range 3 {
+				result, err := limiter.AllowSchema(ctx, key, form)
+				require.NoError(t, err)
+				assert.False(t, result.Allowed)
+			}
+			plan := allow(t, limiter, key)
+			assert.True(t, plan.Allowed, "requests the schema denied are given back to the plan")
+			assert.Equal(t, 0, plan.Remaining)
+
+			other := &schema.Schema{ID: "customer", Security: form.Security}
+			result, err := limiter.AllowSchema(ctx, key, other)
+			require.NoError(t, err)
+			assert.False(t, result.Allowed, "the plan is used up")
+			require.NoError(t, plans.Assign("acme", "enterprise"))
+			result, err = limiter.AllowSchema(ctx, key, other)
+			require.NoError(t, err)
+			assert.True(t, result.Allowed)
+			assert.Equal(t, 1, result.Remaining, "requests the plan denied spend nothing from the schema")
+		})
+	}
+}
+
+func TestNewStaticPlansValidates(t *testing.T) {
+	_, err := NewStaticPlans("free", &Plan{Name: "free", Limits: map[string]Limit{"submit": {Requests: 0, Window: time.Second}}})
+	assert.Error(t, err)
+	_, err = NewStaticPlans("pro", &Plan{Name: "free"})
+	assert.Error(t, err)
+}
+
+func TestRedisStore(t *testing.T) {
+	ctx := context.Background()
+	client, mock := redismock.NewClientMock()
+	store, err := NewRedisStore(client, "")
+	require.NoError(t, err)
+	now := time.UnixMilli(1_700_000_000_500)
+
+	mock.ExpectEvalSha(tokenBucketScript.Hash(), []string{"ratelimit:{k}:tb"},
+		float64(3), "0.002", now.UnixMilli(), 1, int64(2500)).
+		SetVal([]any{int64(1), "2"})
+	allowed, tokens, err := store.TakeTokens(ctx, TokenRequest{
+		Key: "k", Capacity: 3, PerSecond: 2, Cost: 1, Now: now, TTL: 2500 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Equal(t, 2.0, tokens)
+
+	start := time.UnixMilli(1_700_000_000_000)
+	mock.ExpectEvalSha(slidingWindowScript.Hash(),
+		[]string{"ratelimit:{k}:1700000000000", "ratelimit:{k}:1699999990000"},
+		4, 1, "0.950000", int64(20000)).
+		SetVal([]any{int64(0), int64(3), int64(2)})
+	allowed, prev, curr, err := store.CountWindow(ctx, WindowRequest{
+		Key: "k", WindowStart: start, Window: 10 * time.Second, PrevWeight: 0.95, Limit: 4, Cost: 1,
+	})
+	require.NoError(t, err)
+	assert.False(t, allowed)
+	assert.Equal(t, int64(3), prev)
+	assert.Equal(t, int64(2), curr)
+
+	mock.ExpectEvalSha(returnTokensScript.Hash(), []string{"ratelimit:{k}:tb"}, float64(3), 1).SetVal(int64(1))
+	require.NoError(t, store.ReturnTokens(ctx, TokenRequest{Key: "k", Capacity: 3, PerSecond: 2, Cost: 1, Now: now}))
+	mock.ExpectEvalSha(uncountWindowScript.Hash(), []string{"ratelimit:{k}:1700000000000"}, 1).SetVal(int64(1))
+	require.NoError(t, store.UncountWindow(ctx, WindowRequest{Key: "k", WindowStart: start, Window: 10 * time.Second, Cost: 1}))
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,169 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// tokenBucketScript refills and takes tokens atomically. It returns whether
+// the tokens were taken and the tokens left, as a string to keep fractions.
+var tokenBucketScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local per_ms = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local cost = tonumber(ARGV[4])
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or capacity
+local ts = tonumber(state[2]) or now
+if now > ts then
+  tokens = math.min(capacity, tokens + (now - ts) * per_ms)
+  ts = now
+end
+local allowed = 0
+if tokens >= cost then
+  tokens = tokens - cost
+  allowed = 1
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', ts)
+redis.call('PEXPIRE', KEYS[1], ARGV[5])
+return {allowed, tostring(tokens)}
+`)
+
+// returnTokensScript gives tokens back to a bucket that still exists; a
+// bucket that expired meanwhile is full again anyway
+var returnTokensScript = redis.NewScript(`
+local tokens = tonumber(redis.call('HGET', KEYS[1], 'tokens'))
+if tokens then
+  tokens = math.min(tonumber(ARGV[1]), tokens + tonumber(ARGV[2]))
+  redis.call('HSET', KEYS[1], 'tokens', tostring(tokens))
+end
+return 1
+`)
+
+// slidingWindowScript counts a request in the current window unless the
+// weighted previous window plus the current one would exceed the limit
+var slidingWindowScript = redis.NewScript(`
+local prev = tonumber(redis.call('GET', KEYS[2]) or '0')
+local curr = tonumber(redis.call('GET', KEYS[1]) or '0')
+local limit = tonumber(ARGV[1])
+local cost = tonumber(ARGV[2])
+local weight = tonumber(ARGV[3])
+if prev * weight + curr + cost > limit then
+  return {0, prev, curr}
+end
+curr = redis.call('INCRBY', KEYS[1], cost)
+redis.call('PEXPIRE', KEYS[1], ARGV[4])
+return {1, prev, curr}
+`)
+
+// uncountWindowScript removes requests from a window counter, never below
+// zero
+var uncountWindowScript = redis.NewScript(`
+local curr = tonumber(redis.call('GET', KEYS[1]) or '0')
+if curr > 0 then
+  redis.call('DECRBY', KEYS[1], math.min(curr, tonumber(ARGV[1])))
+end
+return 1
+`)
+
+// RedisStore shares limiter state between instances. Keys are hash-tagged
+// so a limit's keys live in one cluster slot:
+//
+//	<prefix>{<key>}:tb            token bucket hash
+//	<prefix>{<key>}:<window ms>   sliding window counter
+//
+// Time comes from the calling instance, so instances need synchronised
+// clocks.
+type RedisStore struct {
+	client redis.Cmdable
+	prefix string
+}
+
+// NewRedisStore creates a Redis-backed store. An empty prefix defaults to
+// "ratelimit:".
+func NewRedisStore(client redis.Cmdable, prefix string) (*RedisStore, error) {
+	if client == nil {
+		return nil, errors.New("ratelimit: redis client is required")
+	}
+	if prefix == "" {
+		prefix = "ratelimit:"
+	}
+	return &RedisStore{client: client, prefix: prefix}, nil
+}
+
+func (s *RedisStore) TakeTokens(ctx context.Context, req TokenRequest) (bool, float64, error) {
+	reply, err := tokenBucketScript.Run(ctx, s.client, []string{s.bucketKey(req.Key)},
+		req.Capacity,
+		strconv.FormatFloat(req.PerSecond/1000, 'g', -1, 64),
+		req.Now.UnixMilli(),
+		req.Cost,
+		req.TTL.Milliseconds(),
+	).Slice()
+	if err != nil {
+		return false, 0, fmt.Errorf("ratelimit: token bucket: %w", err)
+	}
+	if len(reply) != 2 {
+		return false, 0, fmt.Errorf("ratelimit: unexpected token bucket reply %v", reply)
+	}
+
+	allowed, _ := reply[0].(int64)
+	text, _ := reply[1].(string)
+	tokens, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return false, 0, fmt.Errorf("ratelimit: unexpected token count %q", text)
+	}
+	return allowed == 1, tokens, nil
+}
+
+func (s *RedisStore) ReturnTokens(ctx context.Context, req TokenRequest) error {
+	err := returnTokensScript.Run(ctx, s.client, []string{s.bucketKey(req.Key)}, req.Capacity, req.Cost).Err()
+	if err != nil {
+		return fmt.Errorf("ratelimit: return tokens: %w", err)
+	}
+	return nil
+}
+
+func (s *RedisStore) CountWindow(ctx context.Context, req WindowRequest) (bool, int64, int64, error) {
+	keys := []string{
+		s.windowKey(req.Key, req.WindowStart),
+		s.windowKey(req.Key, req.WindowStart.Add(-req.Window)),
+	}
+	reply, err := slidingWindowScript.Run(ctx, s.client, keys,
+		req.Limit,
+		req.Cost,
+		strconv.FormatFloat(req.PrevWeight, 'f', 6, 64),
+		(2 * req.Window).Milliseconds(),
+	).Slice()
+	if err != nil {
+		return false, 0, 0, fmt.Errorf("ratelimit: sliding window: %w", err)
+	}
+	if len(reply) != 3 {
+		return false, 0, 0, fmt.Errorf("ratelimit: unexpected sliding window reply %v", reply)
+	}
+
+	allowed, _ := reply[0].(int64)
+	prev, _ := reply[1].(int64)
+	curr, _ := reply[2].(int64)
+	return allowed == 1, prev, curr, nil
+}
+
+func (s *RedisStore) UncountWindow(ctx context.Context, req WindowRequest) error {
+	err := uncountWindowScript.Run(ctx, s.client, []string{s.windowKey(req.Key, req.WindowStart)}, req.Cost).Err()
+	if err != nil {
+		return fmt.Errorf("ratelimit: uncount window: %w", err)
+	}
+	return nil
+}
+
+func (s *RedisStore) bucketKey(key string) string {
+	return s.prefix + "{" + key + "}:tb"
+}
+
+func (s *RedisStore) windowKey(key string, start time.Time) string {
+	return s.prefix + "{" + key + "}:" + strconv.FormatInt(start.UnixMilli(), 10)
+}
@@ -0,0 +1,170 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TokenRequest takes Cost tokens from a bucket refilling PerSecond tokens
+// up to Capacity. A bucket not yet stored starts full.
+type TokenRequest struct {
+	Key       string
+	Capacity  float64
+	PerSecond float64
+	Cost      int
+	Now       time.Time
+	// TTL is how long an idle bucket is kept
+	TTL time.Duration
+}
+
+// WindowRequest counts Cost requests in the fixed window starting at
+// WindowStart, unless the previous window's count weighted by PrevWeight
+// plus the current count would exceed Limit
+type WindowRequest struct {
+	Key         string
+	WindowStart time.Time
+	Window      time.Duration
+	PrevWeight  float64
+	Limit       int
+	Cost        int
+}
+
+// Store keeps limiter state. Every operation is atomic: concurrent
+// requests for a key never spend the same token or window slot.
+type Store interface {
+	// TakeTokens returns whether the tokens were taken and the tokens left
+	TakeTokens(ctx context.Context, req TokenRequest) (allowed bool, tokens float64, err error)
+	// ReturnTokens gives back the Cost tokens of a TakeTokens that was
+	// allowed, up to Capacity
+	ReturnTokens(ctx context.Context, req TokenRequest) error
+	// CountWindow returns whether the request was counted and the previous
+	// and current window counts afterwards
+	CountWindow(ctx context.Context, req WindowRequest) (allowed bool, prev, curr int64, err error)
+	// UncountWindow removes the Cost requests of a CountWindow that was
+	// allowed from the window they were counted in
+	UncountWindow(ctx context.Context, req WindowRequest) error
+}
+
+// MemoryStore keeps limiter state in process, for single-instance
+// deployments and tests
+type MemoryStore struct {
+	buckets map[string]*memoryBucket
+	windows map[string]*memoryWindow
+	calls   int
+	mu      sync.Mutex
+}
+
+type memoryBucket struct {
+	tokens  float64
+	updated time.Time
+	expires time.Time
+}
+
+type memoryWindow struct {
+	start   time.Time
+	prev    int64
+	curr    int64
+	expires time.Time
+}
+
+// memorySweepEvery is how many calls pass between sweeps of idle state
+const memorySweepEvery = 1000
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*memoryBucket),
+		windows: make(map[string]*memoryWindow),
+	}
+}
+
+func (s *MemoryStore) TakeTokens(ctx context.Context, req TokenRequest) (bool, float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(req.Now)
+
+	bucket, ok := s.buckets[req.Key]
+	if !ok {
+		bucket = &memoryBucket{tokens: req.Capacity, updated: req.Now}
+		s.buckets[req.Key] = bucket
+	}
+	if req.Now.After(bucket.updated) {
+		bucket.tokens = min(req.Capacity, bucket.tokens+req.Now.Sub(bucket.updated).Seconds()*req.PerSecond)
+		bucket.updated = req.Now
+	}
+	bucket.expires = req.Now.Add(req.TTL)
+
+	cost := float64(req.Cost)
+	if bucket.tokens < cost {
+		return false, bucket.tokens, nil
+	}
+	bucket.tokens -= cost
+	return true, bucket.tokens, nil
+}
+
+func (s *MemoryStore) ReturnTokens(ctx context.Context, req TokenRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if bucket, ok := s.buckets[req.Key]; ok {
+		bucket.tokens = min(req.Capacity, bucket.tokens+float64(req.Cost))
+	}
+	return nil
+}
+
+func (s *MemoryStore) CountWindow(ctx context.Context, req WindowRequest) (bool, int64, int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(req.WindowStart)
+
+	window, ok := s.windows[req.Key]
+	switch {
+	case !ok:
+		window = &memoryWindow{start: req.WindowStart}
+		s.windows[req.Key] = window
+	case window.start.Equal(req.WindowStart.Add(-req.Window)):
+		window.start, window.prev, window.curr = req.WindowStart, window.curr, 0
+	case !window.start.Equal(req.WindowStart):
+		window.start, window.prev, window.curr = req.WindowStart, 0, 0
+	}
+	window.expires = req.WindowStart.Add(2 * req.Window)
+
+	if float64(window.prev)*req.PrevWeight+float64(window.curr+int64(req.Cost)) > float64(req.Limit) {
+		return false, window.prev, window.curr, nil
+	}
+	window.curr += int64(req.Cost)
+	return true, window.prev, window.curr, nil
+}
+
+func (s *MemoryStore) UncountWindow(ctx context.Context, req WindowRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	window, ok := s.windows[req.Key]
+	switch {
+	case !ok:
+	case window.start.Equal(req.WindowStart):
+		window.curr = max(0, window.curr-int64(req.Cost))
+	case window.start.Equal(req.WindowStart.Add(req.Window)):
+		window.prev = max(0, window.prev-int64(req.Cost))
+	}
+	return nil
+}
+
+// sweep drops expired state every memorySweepEvery calls; callers hold
+// the lock
+func (s *MemoryStore) sweep(now time.Time) {
+	if s.calls++; s.calls%memorySweepEvery != 0 {
+		return
+	}
+	for key, bucket := range s.buckets {
+		if now.After(bucket.expires) {
+			delete(s.buckets, key)
+		}
+	}
+	for key, window := range s.windows {
+		if now.After(window.expires) {
+			delete(s.windows, key)
+		}
+	}
+}
@@ -226,7 +226,17 @@ type A11yStandardResult struct {
 
 // NewAccessibilityValidator creates a new accessibility validator
 func NewAccessibilityValidator() *AccessibilityValidator {
-	config := A11yConfig{
+	validator := &AccessibilityValidator{
+		config: DefaultA11yConfig(),
+	}
+
+	validator.registerBuiltinRules()
+	return validator
+}
+
+// DefaultA11yConfig returns the default WCAG 2.1 AA configuration
+func DefaultA11yConfig() A11yConfig {
+	return A11yConfig{
 		Level:     A11yLevelAA,
 		Standards: []A11yStandard{A11yStandardWCAG21},
 		ColorContrast: ContrastConfig{
@@ -250,13 +260,6 @@ func NewAccessibilityValidator() *AccessibilityValidator {
 			CheckAriaLabels: true,
 		},
 	}
-
-	validator := &AccessibilityValidator{
-		config: config,
-	}
-
-	validator.registerBuiltinRules()
-	return validator
 }
 
 // ValidateAccessibility validates accessibility for a component or element
@@ -278,6 +281,9 @@ func (av *AccessibilityValidator) ValidateAccessibility(ctx *ValidationContext,
 		Path:     []string{},
 		Metadata: make(map[string]any),
 	}
+	if doc, ok := value.(*HTMLDocument); ok && doc != nil {
+		result.Metrics.TotalElements = len(doc.elements)
+	}
 
 	// Run accessibility rules
 	for _, rule := range av.rules {
@@ -542,7 +548,6 @@ func (av *AccessibilityValidator) calculateScore(metrics A11yMetrics) float64 {
 func (av *AccessibilityValidator) checkStandardCompliance(result *AccessibilityResult, standard A11yStandard) A11yStandardResult {
 	// Count violations for this standard
 	violations := 0
-	warnings := 0
 
 	for _, violation := range result.Violations {
 		if violation.Standard == standard {
@@ -550,10 +555,8 @@ func (av *AccessibilityValidator) checkStandardCompliance(result *AccessibilityR
 		}
 	}
 
-	for _, warning := range result.Warnings {
-		// Note: A11yWarning doesn't have Standard field, would need to add it
-		warnings++
-	}
+	// A11yWarning has no Standard field, so every warning counts
+	warnings := len(result.Warnings)
 
 	score := result.Score
 	if violations > 0 {
@@ -585,15 +588,15 @@ func (v *AltTextValidator) ValidateA11y(element any, context *A11yValidationCont
 	}
 
 	// Check if element is an image or has image-like properties
-	if av.isImageElement(element) {
-		altText := av.getAltText(element)
+	if v.isImageElement(element) {
+		altText := v.getAltText(element)
 
 		if altText == "" {
 			result.Passed = false
 			result.Violations = append(result.Violations, A11yViolation{
 				Code:     "missing_alt_text",
 				Message:  "Image is missing alternative text",
-				Element:  av.getElementDescription(element),
+				Element:  v.getElementDescription(element),
 				Impact:   A11yImpactSerious,
 				Category: A11yCategoryPerceivable,
 				Standard: A11yStandardWCAG21,
@@ -608,7 +611,7 @@ func (v *AltTextValidator) ValidateA11y(element any, context *A11yValidationCont
 			result.Warnings = append(result.Warnings, A11yWarning{
 				Code:     "alt_text_too_long",
 				Message:  "Alternative text is very long and may be verbose",
-				Element:  av.getElementDescription(element),
+				Element:  v.getElementDescription(element),
 				Category: A11yCategoryPerceivable,
 				Level:    A11yLevelAA,
 				Fix:      "Consider shortening alt text to be more concise",
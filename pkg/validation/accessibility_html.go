@@ -0,0 +1,698 @@
+package validation
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/a-h/templ"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/niiniyare/ruun/schema"
+)
+
+// HTMLAuditor audits rendered markup for WCAG 2.2 conformance. Components
+// are rendered to HTML and parsed with golang.org/x/net/html; the rule
+// validators then inspect the real DOM tree. Results use the same
+// AccessibilityResult format as AccessibilityValidator.
+type HTMLAuditor struct {
+	validator  *AccessibilityValidator
+	tokens     map[string]string
+	darkTokens map[string]string
+}
+
+// NewHTMLAuditor creates an auditor with the built-in WCAG 2.2 HTML rules.
+// tokens maps CSS custom properties (e.g. "--color-primary") to their
+// resolved theme values and is used to resolve var() in contrast checks;
+// ParseCSSVariables extracts them from compiled theme CSS.
+func NewHTMLAuditor(tokens map[string]string) *HTMLAuditor {
+	config := DefaultA11yConfig()
+	config.Standards = []A11yStandard{A11yStandardWCAG22}
+
+	validator := &AccessibilityValidator{config: config}
+	validator.registerHTMLRules()
+
+	return &HTMLAuditor{
+		validator: validator,
+		tokens:    tokens,
+	}
+}
+
+// Validator returns the underlying validator, e.g. to add rules
+func (a *HTMLAuditor) Validator() *AccessibilityValidator {
+	return a.validator
+}
+
+// SetDarkTokens sets the CSS custom properties of the dark color scheme.
+// They override the light tokens when text is checked with dark: variants
+// applied; ParseCSSVariables extracts them from the theme's dark CSS.
+func (a *HTMLAuditor) SetDarkTokens(tokens map[string]string) {
+	a.darkTokens = tokens
+}
+
+// SetConfig replaces the audit configuration
+func (a *HTMLAuditor) SetConfig(config A11yConfig) {
+	a.validator.config = config
+}
+
+// AuditComponent renders a templ component and audits the markup
+func (a *HTMLAuditor) AuditComponent(ctx context.Context, name string, component templ.Component) (*AccessibilityResult, error) {
+	var buf bytes.Buffer
+	if err := component.Render(ctx, &buf); err != nil {
+		return nil, fmt.Errorf("render component %s: %w", name, err)
+	}
+	return a.AuditHTML(ctx, name, &buf)
+}
+
+// AuditSchema renders a schema with renderer and audits the markup
+func (a *HTMLAuditor) AuditSchema(ctx context.Context, renderer schema.Renderer, s *schema.Schema, data map[string]any) (*AccessibilityResult, error) {
+	markup, err := renderer.Render(ctx, s, data)
+	if err != nil {
+		return nil, fmt.Errorf("render schema %s: %w", s.ID, err)
+	}
+	return a.AuditHTML(ctx, s.ID, strings.NewReader(markup))
+}
+
+// AuditHTML parses and audits markup. source names the markup in results.
+func (a *HTMLAuditor) AuditHTML(ctx context.Context, source string, r io.Reader) (*AccessibilityResult, error) {
+	doc, err := ParseHTMLDocument(r, a.tokens)
+	if err != nil {
+		return nil, err
+	}
+	doc.Source = source
+	doc.DarkTokens = a.darkTokens
+	return a.validator.ValidateAccessibility(NewValidationContext(ctx, ValidationLevelError, source), doc), nil
+}
+
+// HTMLDocument is parsed markup passed as the element to HTML rule validators
+type HTMLDocument struct {
+	Root       *html.Node
+	Source     string
+	IsDocument bool              // markup was a full document with an <html> element
+	Tokens     map[string]string // CSS custom properties for resolving var()
+	DarkTokens map[string]string // dark color scheme overrides of Tokens
+
+	elements        []*html.Node
+	ids             map[string][]*html.Node
+	hasDarkVariants bool
+}
+
+var htmlElementPattern = regexp.MustCompile(`(?i)<html[\s>]`)
+
+// ParseHTMLDocument parses markup for auditing. Fragments are parsed as the
+// body of an implied document.
+func ParseHTMLDocument(r io.Reader, tokens map[string]string) (*HTMLDocument, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read markup: %w", err)
+	}
+	root, err := html.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("parse markup: %w", err)
+	}
+
+	doc := &HTMLDocument{
+		Root:       root,
+		IsDocument: htmlElementPattern.Match(data),
+		Tokens:     tokens,
+		ids:        make(map[string][]*html.Node),
+	}
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			// The parser adds html, head and body to fragments
+			if doc.IsDocument || (n.DataAtom != atom.Html && n.DataAtom != atom.Head && n.DataAtom != atom.Body) {
+				doc.elements = append(doc.elements, n)
+			}
+			if id := attr(n, "id"); id != "" {
+				doc.ids[id] = append(doc.ids[id], n)
+			}
+			if strings.Contains(attr(n, "class"), "dark:") {
+				doc.hasDarkVariants = true
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(root)
+	return doc, nil
+}
+
+// Elements returns every element in document order
+func (d *HTMLDocument) Elements() []*html.Node {
+	return d.elements
+}
+
+// ElementByID returns the first element with the id
+func (d *HTMLDocument) ElementByID(id string) *html.Node {
+	if nodes := d.ids[id]; len(nodes) > 0 {
+		return nodes[0]
+	}
+	return nil
+}
+
+// darkScheme returns the document with dark tokens applied over the light
+// ones, or nil when the markup has no dark: variants and no dark tokens
+func (d *HTMLDocument) darkScheme() *HTMLDocument {
+	if !d.hasDarkVariants && len(d.DarkTokens) == 0 {
+		return nil
+	}
+	tokens := make(map[string]string, len(d.Tokens)+len(d.DarkTokens))
+	for name, value := range d.Tokens {
+		tokens[name] = value
+	}
+	for name, value := range d.DarkTokens {
+		tokens[name] = value
+	}
+	dark := *d
+	dark.Tokens = tokens
+	return &dark
+}
+
+// accessibleName computes a simplified accessible name following the
+// precedence of the accessible name computation: aria-labelledby,
+// aria-label, native labelling, then content and title
+func (d *HTMLDocument) accessibleName(n *html.Node) string {
+	if ids := attr(n, "aria-labelledby"); ids != "" {
+		var parts []string
+		for _, id := range strings.Fields(ids) {
+			if ref := d.ElementByID(id); ref != nil {
+				parts = append(parts, textContent(ref))
+			}
+		}
+		if name := strings.TrimSpace(strings.Join(parts, " ")); name != "" {
+			return name
+		}
+	}
+	if label := strings.TrimSpace(attr(n, "aria-label")); label != "" {
+		return label
+	}
+
+	switch n.DataAtom {
+	case atom.Input:
+		switch strings.ToLower(attr(n, "type")) {
+		case "submit", "reset", "button":
+			if value := strings.TrimSpace(attr(n, "value")); value != "" {
+				return value
+			}
+			if t := strings.ToLower(attr(n, "type")); t != "button" {
+				return t
+			}
+		case "image":
+			return strings.TrimSpace(attr(n, "alt"))
+		}
+		if label := d.labelText(n); label != "" {
+			return label
+		}
+	case atom.Select, atom.Textarea, atom.Meter, atom.Progress, atom.Output:
+		if label := d.labelText(n); label != "" {
+			return label
+		}
+	case atom.Img, atom.Area:
+		if alt, ok := attrOK(n, "alt"); ok {
+			return strings.TrimSpace(alt)
+		}
+	case atom.Svg:
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && c.Data == "title" {
+				return textContent(c)
+			}
+		}
+	case atom.Button, atom.A, atom.Summary, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Th, atom.Td, atom.Label, atom.Legend, atom.Option:
+		if text := textContent(n); text != "" {
+			return text
+		}
+	default:
+		if nameFromContentRoles[attr(n, "role")] {
+			if text := textContent(n); text != "" {
+				return text
+			}
+		}
+	}
+	return strings.TrimSpace(attr(n, "title"))
+}
+
+// labelText returns the text of <label for> elements and a wrapping <label>
+func (d *HTMLDocument) labelText(n *html.Node) string {
+	var parts []string
+	if id := attr(n, "id"); id != "" {
+		for _, el := range d.elements {
+			if el.DataAtom == atom.Label && attr(el, "for") == id {
+				parts = append(parts, textContent(el))
+			}
+		}
+	}
+	for p := n.Parent; p != nil; p = p.Parent {
+		if p.DataAtom == atom.Label {
+			parts = append(parts, textContent(p))
+			break
+		}
+	}
+	return strings.TrimSpace(strings.Join(parts, " "))
+}
+
+// nameFromContentRoles take their accessible name from their content
+var nameFromContentRoles = map[string]bool{
+	"button": true, "cell": true, "checkbox": true, "columnheader": true, "gridcell": true,
+	"heading": true, "link": true, "menuitem": true, "menuitemcheckbox": true, "menuitemradio": true,
+	"option": true, "radio": true, "row": true, "rowheader": true, "switch": true, "tab": true,
+	"tooltip": true, "treeitem": true,
+}
+
+// textContent returns the rendered text of a subtree, skipping hidden
+// content and using alt text for images
+func textContent(n *html.Node) string {
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			b.WriteString(n.Data)
+			b.WriteByte(' ')
+			return
+		case html.ElementNode:
+			if isHidden(n) || n.DataAtom == atom.Script || n.DataAtom == atom.Style || n.DataAtom == atom.Template {
+				return
+			}
+			if n.DataAtom == atom.Img {
+				b.WriteString(attr(n, "alt"))
+				b.WriteByte(' ')
+			}
+			if label := attr(n, "aria-label"); label != "" && n.DataAtom != atom.Label {
+				b.WriteString(label)
+				b.WriteByte(' ')
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// hasOwnText reports whether an element has non-whitespace text children
+func hasOwnText(n *html.Node) bool {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.TextNode && strings.TrimSpace(c.Data) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// isHidden reports whether an element is removed from the accessibility tree
+func isHidden(n *html.Node) bool {
+	if _, ok := attrOK(n, "hidden"); ok {
+		return true
+	}
+	if attr(n, "aria-hidden") == "true" {
+		return true
+	}
+	if n.DataAtom == atom.Input && strings.EqualFold(attr(n, "type"), "hidden") {
+		return true
+	}
+	style := parseInlineStyle(attr(n, "style"))
+	return style["display"] == "none" || style["visibility"] == "hidden"
+}
+
+// isHiddenInTree reports whether an element or any ancestor is hidden
+func isHiddenInTree(n *html.Node) bool {
+	for ; n != nil; n = n.Parent {
+		if n.Type == html.ElementNode && isHidden(n) {
+			return true
+		}
+	}
+	return false
+}
+
+// isFocusable reports whether an element is in the sequential focus order
+func isFocusable(n *html.Node) bool {
+	if _, disabled := attrOK(n, "disabled"); disabled {
+		return false
+	}
+	if tabindex, ok := attrOK(n, "tabindex"); ok {
+		if i, err := strconv.Atoi(strings.TrimSpace(tabindex)); err == nil {
+			return i >= 0
+		}
+	}
+	return isNativelyFocusable(n)
+}
+
+// isNativelyFocusable reports whether an element is focusable without tabindex
+func isNativelyFocusable(n *html.Node) bool {
+	switch n.DataAtom {
+	case atom.A, atom.Area:
+		_, ok := attrOK(n, "href")
+		return ok
+	case atom.Button, atom.Select, atom.Textarea, atom.Iframe, atom.Summary:
+		return true
+	case atom.Input:
+		return !strings.EqualFold(attr(n, "type"), "hidden")
+	}
+	_, editable := attrOK(n, "contenteditable")
+	return editable
+}
+
+// isInteractive reports whether an element is a native or ARIA widget
+func isInteractive(n *html.Node) bool {
+	return isNativelyFocusable(n) || widgetRoles[attr(n, "role")]
+}
+
+// describeElement formats an element's opening tag with identifying attributes
+func describeElement(n *html.Node) string {
+	var b strings.Builder
+	b.WriteString("<" + n.Data)
+	for _, key := range []string{"id", "name", "type", "role", "href", "class"} {
+		if value, ok := attrOK(n, key); ok {
+			if len(value) > 40 {
+				value = value[:40] + "…"
+			}
+			fmt.Fprintf(&b, " %s=%q", key, value)
+		}
+	}
+	b.WriteString(">")
+	return b.String()
+}
+
+// elementPath returns a CSS selector path to the element
+func elementPath(n *html.Node) string {
+	var parts []string
+	for ; n != nil && n.Type == html.ElementNode; n = n.Parent {
+		if n.DataAtom == atom.Html || n.DataAtom == atom.Body {
+			break
+		}
+		if id := attr(n, "id"); id != "" {
+			parts = append(parts, n.Data+"#"+id)
+			break
+		}
+		index := 1
+		for s := n.PrevSibling; s != nil; s = s.PrevSibling {
+			if s.Type == html.ElementNode && s.Data == n.Data {
+				index++
+			}
+		}
+		parts = append(parts, fmt.Sprintf("%s:nth-of-type(%d)", n.Data, index))
+	}
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+	return strings.Join(parts, " > ")
+}
+
+func attr(n *html.Node, key string) string {
+	value, _ := attrOK(n, key)
+	return value
+}
+
+func attrOK(n *html.Node, key string) (string, bool) {
+	for _, a := range n.Attr {
+		if a.Namespace == "" && a.Key == key {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+// hasAttrOrBinding reports whether an attribute is set statically or bound
+// with Alpine.js (":attr" or "x-bind:attr")
+func hasAttrOrBinding(n *html.Node, key string) bool {
+	for _, candidate := range []string{key, ":" + key, "x-bind:" + key} {
+		if _, ok := attrOK(n, candidate); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// parseInlineStyle parses a style attribute into lower-cased declarations
+func parseInlineStyle(style string) map[string]string {
+	declarations := make(map[string]string)
+	for _, declaration := range strings.Split(style, ";") {
+		property, value, ok := strings.Cut(declaration, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), "!important"))
+		declarations[strings.ToLower(strings.TrimSpace(property))] = value
+	}
+	return declarations
+}
+
+var cssVariablePattern = regexp.MustCompile(`(--[A-Za-z0-9_-]+)\s*:\s*([^;{}]+);`)
+
+// ParseCSSVariables extracts custom property declarations from compiled
+// theme CSS. Later declarations win, so pass light-mode CSS for light-mode audits.
+func ParseCSSVariables(css string) map[string]string {
+	variables := make(map[string]string)
+	for _, match := range cssVariablePattern.FindAllStringSubmatch(css, -1) {
+		variables[match[1]] = strings.TrimSpace(match[2])
+	}
+	return variables
+}
+
+var cssVarPattern = regexp.MustCompile(`var\(\s*(--[A-Za-z0-9_-]+)\s*(?:,\s*([^()]*(?:\([^()]*\))?[^()]*))?\)`)
+
+// resolveVars substitutes var() references with token values
+func (d *HTMLDocument) resolveVars(value string) string {
+	for depth := 0; depth < 10 && strings.Contains(value, "var("); depth++ {
+		value = cssVarPattern.ReplaceAllStringFunc(value, func(ref string) string {
+			match := cssVarPattern.FindStringSubmatch(ref)
+			if token, ok := d.Tokens[match[1]]; ok {
+				return token
+			}
+			return strings.TrimSpace(match[2])
+		})
+	}
+	return value
+}
+
+// tokenColor resolves a utility class suffix such as "primary" in
+// "text-primary" to a theme color
+func (d *HTMLDocument) tokenColor(name string) (rgba, bool) {
+	for _, key := range []string{"--color-" + name, "--" + name} {
+		if value, ok := d.Tokens[key]; ok {
+			if color, ok := parseCSSColor(d.resolveVars(value)); ok {
+				return color, true
+			}
+		}
+	}
+	return rgba{}, false
+}
+
+// rgba is a color with components in [0, 1]
+type rgba struct {
+	r, g, b, a float64
+}
+
+// over composites c over the background
+func (c rgba) over(background rgba) rgba {
+	if c.a >= 1 {
+		return c
+	}
+	a := c.a + background.a*(1-c.a)
+	if a == 0 {
+		return rgba{}
+	}
+	blend := func(fg, bg float64) float64 {
+		return (fg*c.a + bg*background.a*(1-c.a)) / a
+	}
+	return rgba{blend(c.r, background.r), blend(c.g, background.g), blend(c.b, background.b), a}
+}
+
+// luminance returns the WCAG relative luminance
+func (c rgba) luminance() float64 {
+	channel := func(v float64) float64 {
+		if v <= 0.04045 {
+			return v / 12.92
+		}
+		return math.Pow((v+0.055)/1.055, 2.4)
+	}
+	return 0.2126*channel(c.r) + 0.7152*channel(c.g) + 0.0722*channel(c.b)
+}
+
+func (c rgba) hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", int(math.Round(c.r*255)), int(math.Round(c.g*255)), int(math.Round(c.b*255)))
+}
+
+// contrastRatio returns the WCAG contrast ratio of two opaque colors
+func contrastRatio(fg, bg rgba) float64 {
+	lighter := math.Max(fg.luminance(), bg.luminance())
+	darker := math.Min(fg.luminance(), bg.luminance())
+	return (lighter + 0.05) / (darker + 0.05)
+}
+
+var namedColors = map[string]rgba{
+	"black":       {0, 0, 0, 1},
+	"white":       {1, 1, 1, 1},
+	"red":         {1, 0, 0, 1},
+	"green":       {0, 128.0 / 255, 0, 1},
+	"blue":        {0, 0, 1, 1},
+	"gray":        {128.0 / 255, 128.0 / 255, 128.0 / 255, 1},
+	"grey":        {128.0 / 255, 128.0 / 255, 128.0 / 255, 1},
+	"silver":      {192.0 / 255, 192.0 / 255, 192.0 / 255, 1},
+	"yellow":      {1, 1, 0, 1},
+	"orange":      {1, 165.0 / 255, 0, 1},
+	"transparent": {0, 0, 0, 0},
+}
+
+var colorFunctionPattern = regexp.MustCompile(`^(rgba?|hsla?|oklch)\(\s*([^)]*)\)$`)
+
+// parseCSSColor parses hex, rgb(), hsl(), oklch() and basic named colors.
+// Bare "H S% L%" triplets, as used in hsl(var(--token)) themes, are read as HSL.
+func parseCSSColor(value string) (rgba, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if color, ok := namedColors[value]; ok {
+		return color, true
+	}
+	if strings.HasPrefix(value, "#") {
+		return parseHexColor(value[1:])
+	}
+	match := colorFunctionPattern.FindStringSubmatch(value)
+	if match == nil {
+		if parts := strings.Fields(value); len(parts) == 3 && strings.HasSuffix(parts[1], "%") && strings.HasSuffix(parts[2], "%") {
+			return parseColorFunction("hsl", parts)
+		}
+		return rgba{}, false
+	}
+	args := strings.FieldsFunc(match[2], func(r rune) bool { return r == ',' || r == ' ' || r == '/' })
+	return parseColorFunction(match[1], args)
+}
+
+func parseHexColor(hex string) (rgba, bool) {
+	if len(hex) == 3 || len(hex) == 4 {
+		var expanded strings.Builder
+		for _, r := range hex {
+			expanded.WriteRune(r)
+			expanded.WriteRune(r)
+		}
+		hex = expanded.String()
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	if len(hex) != 8 {
+		return rgba{}, false
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return rgba{}, false
+	}
+	return rgba{
+		r: float64(v>>24&0xff) / 255,
+		g: float64(v>>16&0xff) / 255,
+		b: float64(v>>8&0xff) / 255,
+		a: float64(v&0xff) / 255,
+	}, true
+}
+
+func parseColorFunction(name string, args []string) (rgba, bool) {
+	if len(args) < 3 {
+		return rgba{}, false
+	}
+	values := make([]float64, len(args))
+	for i, arg := range args {
+		percent := strings.HasSuffix(arg, "%")
+		v, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSuffix(arg, "%"), "deg"), 64)
+		if err != nil {
+			return rgba{}, false
+		}
+		if percent {
+			v /= 100
+		}
+		values[i] = v
+	}
+	alpha := 1.0
+	if len(values) > 3 {
+		alpha = values[3]
+	}
+
+	switch name {
+	case "rgb", "rgba":
+		scale := func(i int) float64 {
+			if strings.HasSuffix(args[i], "%") {
+				return values[i]
+			}
+			return values[i] / 255
+		}
+		return rgba{scale(0), scale(1), scale(2), alpha}, true
+	case "hsl", "hsla":
+		r, g, b := hslToRGB(values[0], values[1], values[2])
+		return rgba{r, g, b, alpha}, true
+	case "oklch":
+		r, g, b := oklchToRGB(values[0], values[1], values[2])
+		return rgba{r, g, b, alpha}, true
+	}
+	return rgba{}, false
+}
+
+func hslToRGB(h, s, l float64) (float64, float64, float64) {
+	h = math.Mod(math.Mod(h, 360)+360, 360) / 360
+	if s == 0 {
+		return l, l, l
+	}
+	q := l * (1 + s)
+	if l >= 0.5 {
+		q = l + s - l*s
+	}
+	p := 2*l - q
+	hue := func(t float64) float64 {
+		t = math.Mod(t+1, 1)
+		switch {
+		case t < 1.0/6:
+			return p + (q-p)*6*t
+		case t < 0.5:
+			return q
+		case t < 2.0/3:
+			return p + (q-p)*(2.0/3-t)*6
+		}
+		return p
+	}
+	return hue(h + 1.0/3), hue(h), hue(h - 1.0/3)
+}
+
+func oklchToRGB(l, c, h float64) (float64, float64, float64) {
+	rad := h * math.Pi / 180
+	a, b := c*math.Cos(rad), c*math.Sin(rad)
+
+	lp := math.Pow(l+0.3963377774*a+0.2158037573*b, 3)
+	mp := math.Pow(l-0.1055613458*a-0.0638541728*b, 3)
+	sp := math.Pow(l-0.0894841775*a-1.2914855480*b, 3)
+
+	gamma := func(v float64) float64 {
+		v = math.Max(0, math.Min(1, v))
+		if v <= 0.0031308 {
+			return 12.92 * v
+		}
+		return 1.055*math.Pow(v, 1/2.4) - 0.055
+	}
+	return gamma(4.0767416621*lp - 3.3077115913*mp + 0.2309699292*sp),
+		gamma(-1.2684380046*lp + 2.6097574011*mp - 0.3413193965*sp),
+		gamma(-0.0041960863*lp - 0.7034186147*mp + 1.7076147010*sp)
+}
+
+// parseCSSLength converts a length to pixels, assuming a 16px root font size
+func parseCSSLength(value string, inherited float64) (float64, bool) {
+	value = strings.TrimSpace(strings.ToLower(value))
+	for _, unit := range []struct {
+		suffix string
+		scale  float64
+	}{{"px", 1}, {"pt", 4.0 / 3}, {"rem", 16}, {"em", inherited}, {"%", inherited / 100}} {
+		if number, ok := strings.CutSuffix(value, unit.suffix); ok {
+			v, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+			if err != nil {
+				return 0, false
+			}
+			return v * unit.scale, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,854 @@
+package validation
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// registerHTMLRules registers the WCAG 2.2 rules that inspect an *HTMLDocument
+func (av *AccessibilityValidator) registerHTMLRules() {
+	rules := []struct {
+		id, name, description string
+		level                 A11yLevel
+		validator             A11yRuleValidator
+	}{
+		{"wcag111", "Non-text Content", "Images and image roles must have text alternatives", A11yLevelA, &HTMLAltTextValidator{}},
+		{"wcag131", "Info and Relationships", "Headings must be non-empty and nested in order", A11yLevelA, &HTMLHeadingValidator{}},
+		{"wcag143", "Contrast Minimum", "Text must have sufficient contrast against its background", A11yLevelAA, &HTMLContrastValidator{config: av.config.ColorContrast}},
+		{"wcag211", "Keyboard", "Elements with click handlers must be reachable by keyboard", A11yLevelA, &HTMLKeyboardValidator{}},
+		{"wcag243", "Focus Order", "Focus order must follow the document order", A11yLevelA, &HTMLFocusOrderValidator{}},
+		{"wcag258", "Target Size Minimum", "Pointer targets must be at least 24 by 24 CSS pixels", A11yLevelAA, &HTMLTargetSizeValidator{}},
+		{"wcag311", "Language of Page", "The document must declare its language", A11yLevelA, &HTMLLanguageValidator{}},
+		{"wcag332", "Labels or Instructions", "Form controls must have labels", A11yLevelA, &HTMLFormLabelValidator{}},
+		{"wcag412", "Name, Role, Value", "ARIA roles, states and references must be valid and controls named", A11yLevelA, &HTMLAriaValidator{}},
+	}
+	for _, rule := range rules {
+		av.AddRule(A11yRule{
+			ID:          rule.id,
+			Name:        rule.name,
+			Description: rule.description,
+			Category:    rule.validator.GetCategory(),
+			Level:       rule.level,
+			Standard:    A11yStandardWCAG22,
+			Enabled:     true,
+			Validator:   rule.validator,
+		})
+	}
+}
+
+// htmlRuleResult collects findings of one rule over a document
+type htmlRuleResult struct {
+	*A11yRuleResult
+	doc      *HTMLDocument
+	category A11yCategory
+	level    A11yLevel
+	resource A11yResource
+}
+
+func newHTMLRuleResult(rule string, doc *HTMLDocument, category A11yCategory, level A11yLevel, understanding string) *htmlRuleResult {
+	return &htmlRuleResult{
+		A11yRuleResult: &A11yRuleResult{Passed: true, Rule: rule, Metadata: make(map[string]any)},
+		doc:            doc,
+		category:       category,
+		level:          level,
+		resource: A11yResource{
+			Title: "Understanding WCAG 2.2",
+			URL:   "https://www.w3.org/WAI/WCAG22/Understanding/" + understanding + ".html",
+			Type:  "documentation",
+		},
+	}
+}
+
+func (r *htmlRuleResult) location(n *html.Node) *SourceLocation {
+	return &SourceLocation{File: r.doc.Source, Path: elementPath(n)}
+}
+
+func (r *htmlRuleResult) violation(n *html.Node, code string, impact A11yImpact, message, fix string, context map[string]any) {
+	r.Passed = false
+	r.Violations = append(r.Violations, A11yViolation{
+		Code:      code,
+		Message:   message,
+		Element:   describeElement(n),
+		Impact:    impact,
+		Category:  r.category,
+		Standard:  A11yStandardWCAG22,
+		Level:     r.level,
+		Location:  r.location(n),
+		Fix:       fix,
+		Resources: []A11yResource{r.resource},
+		Context:   context,
+	})
+}
+
+func (r *htmlRuleResult) warning(n *html.Node, code, message, fix string) {
+	r.Warnings = append(r.Warnings, A11yWarning{
+		Code:     code,
+		Message:  message,
+		Element:  describeElement(n),
+		Category: r.category,
+		Level:    r.level,
+		Location: r.location(n),
+		Fix:      fix,
+	})
+}
+
+// htmlDocument extracts the document from a validator element
+func htmlDocument(element any) (*HTMLDocument, bool) {
+	doc, ok := element.(*HTMLDocument)
+	return doc, ok && doc != nil
+}
+
+// HTMLAltTextValidator checks text alternatives for images
+type HTMLAltTextValidator struct{}
+
+func (v *HTMLAltTextValidator) ValidateA11y(element any, context *A11yValidationContext) *A11yRuleResult {
+	doc, ok := htmlDocument(element)
+	if !ok {
+		return nil
+	}
+	result := newHTMLRuleResult("wcag111", doc, v.GetCategory(), A11yLevelA, "non-text-content")
+
+	for _, n := range doc.Elements() {
+		if isHiddenInTree(n) {
+			continue
+		}
+		role := attr(n, "role")
+		switch {
+		case n.DataAtom == atom.Img && role != "presentation" && role != "none":
+			alt, hasAlt := attrOK(n, "alt")
+			if !hasAlt && doc.accessibleName(n) == "" {
+				result.violation(n, "missing_alt_text", A11yImpactSerious,
+					"Image is missing alternative text",
+					`Add alt text describing the image, or alt="" if it is decorative`, nil)
+			} else if len(alt) > 125 {
+				result.warning(n, "alt_text_too_long", "Alternative text is very long and may be verbose",
+					"Shorten the alt text and move long descriptions into the page")
+			}
+		case (n.DataAtom == atom.Input && strings.EqualFold(attr(n, "type"), "image")) || n.DataAtom == atom.Area:
+			if doc.accessibleName(n) == "" {
+				result.violation(n, "missing_alt_text", A11yImpactCritical,
+					"Image button or image map area is missing alternative text",
+					"Add alt text describing the action or destination", nil)
+			}
+		case role == "img":
+			if doc.accessibleName(n) == "" {
+				result.violation(n, "missing_img_name", A11yImpactSerious,
+					`Element with role="img" has no accessible name`,
+					"Add aria-label or aria-labelledby", nil)
+			}
+		}
+	}
+	return result.A11yRuleResult
+}
+
+func (v *HTMLAltTextValidator) GetCategory() A11yCategory {
+	return A11yCategoryPerceivable
+}
+
+// HTMLHeadingValidator checks heading content and nesting order
+type HTMLHeadingValidator struct{}
+
+func (v *HTMLHeadingValidator) ValidateA11y(element any, context *A11yValidationContext) *A11yRuleResult {
+	doc, ok := htmlDocument(element)
+	if !ok {
+		return nil
+	}
+	result := newHTMLRuleResult("wcag131", doc, v.GetCategory(), A11yLevelA, "info-and-relationships")
+
+	previous, h1s := 0, 0
+	for _, n := range doc.Elements() {
+		level := headingLevel(n)
+		if level == 0 || isHiddenInTree(n) {
+			continue
+		}
+		if doc.accessibleName(n) == "" {
+			result.violation(n, "empty_heading", A11yImpactModerate,
+				"Heading has no text content",
+				"Add text to the heading or remove it", nil)
+		}
+		if previous > 0 && level > previous+1 {
+			result.violation(n, "heading_order", A11yImpactModerate,
+				fmt.Sprintf("Heading level %d follows level %d and skips a level", level, previous),
+				fmt.Sprintf("Use a level %d heading or add the missing intermediate heading", previous+1),
+				map[string]any{"level": level, "previousLevel": previous})
+		}
+		if level == 1 {
+			h1s++
+			if h1s == 2 {
+				result.warning(n, "multiple_h1", "Document has more than one level 1 heading",
+					"Use a single level 1 heading for the page title")
+			}
+		}
+		previous = level
+	}
+	result.Metadata["headings"] = previous > 0
+	return result.A11yRuleResult
+}
+
+func (v *HTMLHeadingValidator) GetCategory() A11yCategory {
+	return A11yCategoryPerceivable
+}
+
+// headingLevel returns 1-6 for headings and 0 otherwise
+func headingLevel(n *html.Node) int {
+	switch n.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		return int(n.Data[1] - '0')
+	}
+	if attr(n, "role") == "heading" {
+		if level, err := strconv.Atoi(attr(n, "aria-level")); err == nil && level > 0 {
+			return level
+		}
+		return 2
+	}
+	return 0
+}
+
+// HTMLContrastValidator checks text contrast. Colors come from inline styles
+// and text-*/bg-* utility classes, with var() and class names resolved
+// against the document's theme tokens. Text whose colors cannot be
+// determined is skipped; a missing background is assumed white. Documents
+// with dark:text-*/dark:bg-* variants or dark theme tokens are checked a
+// second time in the dark color scheme.
+type HTMLContrastValidator struct {
+	config ContrastConfig
+}
+
+// textStyle is the computed style inherited down the tree
+type textStyle struct {
+	color      *rgba
+	background rgba
+	fontSize   float64
+	bold       bool
+}
+
+func (v *HTMLContrastValidator) ValidateA11y(element any, context *A11yValidationContext) *A11yRuleResult {
+	doc, ok := htmlDocument(element)
+	if !ok {
+		return nil
+	}
+	result := newHTMLRuleResult("wcag143", doc, v.GetCategory(), A11yLevelAA, "contrast-minimum")
+	lowest := 0.0
+
+	v.check(doc, result, false, &lowest)
+	if dark := doc.darkScheme(); dark != nil {
+		v.check(dark, result, true, &lowest)
+	}
+
+	if lowest > 0 {
+		result.Metadata["lowestContrastRatio"] = lowest
+	}
+	return result.A11yRuleResult
+}
+
+// check walks the document in one color scheme and records its findings
+func (v *HTMLContrastValidator) check(doc *HTMLDocument, result *htmlRuleResult, dark bool, lowest *float64) {
+	scheme, prefix := "light", ""
+	background := namedColors["white"]
+	if dark {
+		scheme, prefix = "dark", "Dark mode: "
+		background = namedColors["black"]
+		if color, ok := doc.tokenColor("background"); ok {
+			background = color.over(namedColors["black"])
+		}
+	}
+
+	var walk func(n *html.Node, style textStyle, explicit bool)
+	walk = func(n *html.Node, style textStyle, explicit bool) {
+		if n.Type == html.ElementNode {
+			if isHidden(n) || n.DataAtom == atom.Script || n.DataAtom == atom.Style {
+				return
+			}
+			var set bool
+			style, set = v.computeStyle(doc, n, style, dark)
+			explicit = explicit || set
+
+			if explicit && style.color != nil && hasOwnText(n) {
+				fg := style.color.over(style.background)
+				ratio := contrastRatio(fg, style.background)
+				large := style.fontSize >= 24 || (style.bold && style.fontSize >= 18.66)
+				required := v.config.MinContrastNormal
+				if large {
+					required = v.config.MinContrastLarge
+				}
+				if *lowest == 0 || ratio < *lowest {
+					*lowest = ratio
+				}
+				details := map[string]any{
+					"foregroundColor": fg.hex(),
+					"backgroundColor": style.background.hex(),
+					"contrastRatio":   ratio,
+					"requiredRatio":   required,
+					"largeText":       large,
+					"colorScheme":     scheme,
+				}
+				if ratio < required {
+					result.violation(n, "insufficient_contrast", A11yImpactSerious,
+						fmt.Sprintf("%sText contrast ratio %.2f:1 is below minimum %.2f:1", prefix, ratio, required),
+						"Use theme tokens with more contrast between text and background", details)
+				} else if ratio < v.config.MinContrastAAA && !large {
+					result.warning(n, "contrast_aaa_recommended",
+						fmt.Sprintf("%sContrast ratio %.2f:1 meets AA but not AAA (%.2f:1)", prefix, ratio, v.config.MinContrastAAA),
+						"Increase contrast for AAA compliance")
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c, style, explicit)
+		}
+	}
+	walk(doc.Root, textStyle{background: background, fontSize: 16}, false)
+}
+
+// computeStyle applies an element's own colors and font to the inherited
+// style and reports whether it set a color. In the dark scheme dark:
+// variants override the element's base utility classes.
+func (v *HTMLContrastValidator) computeStyle(doc *HTMLDocument, n *html.Node, style textStyle, dark bool) (textStyle, bool) {
+	set := false
+	switch n.DataAtom {
+	case atom.H1:
+		style.fontSize, style.bold = 32, true
+	case atom.H2:
+		style.fontSize, style.bold = 24, true
+	case atom.H3:
+		style.fontSize, style.bold = 18.72, true
+	case atom.B, atom.Strong, atom.Th:
+		style.bold = true
+	}
+
+	var base, variants []string
+	for _, class := range strings.Fields(attr(n, "class")) {
+		if variant, ok := strings.CutPrefix(class, "dark:"); ok {
+			variants = append(variants, variant)
+		} else {
+			base = append(base, class)
+		}
+	}
+	classes := base
+	if dark {
+		classes = append(classes, variants...)
+	}
+	var background *rgba
+	for _, class := range classes {
+		if name, ok := strings.CutPrefix(class, "text-"); ok {
+			if color, ok := doc.tokenColor(name); ok {
+				style.color, set = &color, true
+			}
+		} else if name, ok := strings.CutPrefix(class, "bg-"); ok {
+			if color, ok := doc.tokenColor(name); ok {
+				background, set = &color, true
+			}
+		}
+	}
+	if background != nil {
+		style.background = background.over(style.background)
+	}
+
+	declarations := parseInlineStyle(attr(n, "style"))
+	if value, ok := declarations["color"]; ok {
+		if color, ok := parseCSSColor(doc.resolveVars(value)); ok {
+			style.color, set = &color, true
+		}
+	}
+	for _, property := range []string{"background-color", "background"} {
+		if value, ok := declarations[property]; ok {
+			if color, ok := parseCSSColor(doc.resolveVars(value)); ok {
+				style.background, set = color.over(style.background), true
+				break
+			}
+		}
+	}
+	if value, ok := declarations["font-size"]; ok {
+		if size, ok := parseCSSLength(doc.resolveVars(value), style.fontSize); ok {
+			style.fontSize = size
+		}
+	}
+	if value, ok := declarations["font-weight"]; ok {
+		weight, err := strconv.Atoi(value)
+		style.bold = value == "bold" || value == "bolder" || (err == nil && weight >= 700)
+	}
+	return style, set
+}
+
+func (v *HTMLContrastValidator) GetCategory() A11yCategory {
+	return A11yCategoryPerceivable
+}
+
+// clickAttributes mark elements with pointer interaction
+var clickAttributes = []string{"onclick", "@click", "x-on:click", "hx-get", "hx-post", "hx-put", "hx-patch", "hx-delete"}
+
+// HTMLKeyboardValidator checks that clickable elements are keyboard operable
+type HTMLKeyboardValidator struct{}
+
+func (v *HTMLKeyboardValidator) ValidateA11y(element any, context *A11yValidationContext) *A11yRuleResult {
+	doc, ok := htmlDocument(element)
+	if !ok {
+		return nil
+	}
+	result := newHTMLRuleResult("wcag211", doc, v.GetCategory(), A11yLevelA, "keyboard")
+
+	for _, n := range doc.Elements() {
+		if isHiddenInTree(n) || isNativelyFocusable(n) || n.DataAtom == atom.Form || n.DataAtom == atom.Label {
+			continue
+		}
+		clickable := slices.ContainsFunc(clickAttributes, func(key string) bool {
+			_, ok := attrOK(n, key)
+			return ok
+		})
+		if !clickable {
+			continue
+		}
+		if !isFocusable(n) {
+			result.violation(n, "not_focusable", A11yImpactSerious,
+				"Element has a click handler but cannot be reached with the keyboard",
+				`Use a <button> or add tabindex="0", a widget role and a key handler`, nil)
+			continue
+		}
+		if !hasKeyHandler(n) {
+			result.warning(n, "missing_keyboard_handlers",
+				"Focusable element with a click handler has no keyboard handler",
+				"Handle Enter and Space, e.g. @keydown.enter and @keydown.space")
+		}
+	}
+	return result.A11yRuleResult
+}
+
+func (v *HTMLKeyboardValidator) GetCategory() A11yCategory {
+	return A11yCategoryOperable
+}
+
+// hasKeyHandler reports whether an element handles key events
+func hasKeyHandler(n *html.Node) bool {
+	for _, a := range n.Attr {
+		key := strings.ToLower(a.Key)
+		if strings.HasPrefix(key, "onkey") || strings.HasPrefix(key, "@key") || strings.HasPrefix(key, "x-on:key") {
+			return true
+		}
+		if strings.HasPrefix(key, "hx-trigger") && strings.Contains(a.Val, "key") {
+			return true
+		}
+	}
+	return false
+}
+
+// HTMLFocusOrderValidator checks for positive tabindex values that override
+// the document focus order
+type HTMLFocusOrderValidator struct{}
+
+func (v *HTMLFocusOrderValidator) ValidateA11y(element any, context *A11yValidationContext) *A11yRuleResult {
+	doc, ok := htmlDocument(element)
+	if !ok {
+		return nil
+	}
+	result := newHTMLRuleResult("wcag243", doc, v.GetCategory(), A11yLevelA, "focus-order")
+
+	for _, n := range doc.Elements() {
+		tabindex, err := strconv.Atoi(strings.TrimSpace(attr(n, "tabindex")))
+		if err == nil && tabindex > 0 {
+			result.warning(n, "positive_tabindex",
+				fmt.Sprintf("tabindex=%d moves the element ahead of the document focus order", tabindex),
+				`Use tabindex="0" and order elements in the markup instead`)
+		}
+	}
+	return result.A11yRuleResult
+}
+
+func (v *HTMLFocusOrderValidator) GetCategory() A11yCategory {
+	return A11yCategoryOperable
+}
+
+// HTMLTargetSizeValidator checks interactive elements sized below 24px with
+// inline styles (WCAG 2.2 success criterion 2.5.8)
+type HTMLTargetSizeValidator struct{}
+
+func (v *HTMLTargetSizeValidator) ValidateA11y(element any, context *A11yValidationContext) *A11yRuleResult {
+	doc, ok := htmlDocument(element)
+	if !ok {
+		return nil
+	}
+	result := newHTMLRuleResult("wcag258", doc, v.GetCategory(), A11yLevelAA, "target-size-minimum")
+
+	for _, n := range doc.Elements() {
+		// Inline links in text are exempt
+		if !isInteractive(n) || isHiddenInTree(n) || n.DataAtom == atom.A {
+			continue
+		}
+		declarations := parseInlineStyle(attr(n, "style"))
+		for _, property := range []string{"width", "height"} {
+			value, ok := declarations[property]
+			if !ok {
+				continue
+			}
+			if size, ok := parseCSSLength(doc.resolveVars(value), 16); ok && size < 24 {
+				result.violation(n, "target_too_small", A11yImpactModerate,
+					fmt.Sprintf("Target %s is %.0fpx, below the 24px minimum", property, size),
+					"Make the target at least 24 by 24 CSS pixels or space it from other targets",
+					map[string]any{property: size})
+				break
+			}
+		}
+	}
+	return result.A11yRuleResult
+}
+
+func (v *HTMLTargetSizeValidator) GetCategory() A11yCategory {
+	return A11yCategoryOperable
+}
+
+// HTMLLanguageValidator checks the lang attribute of full documents
+type HTMLLanguageValidator struct{}
+
+func (v *HTMLLanguageValidator) ValidateA11y(element any, context *A11yValidationContext) *A11yRuleResult {
+	doc, ok := htmlDocument(element)
+	if !ok {
+		return nil
+	}
+	result := newHTMLRuleResult("wcag311", doc, v.GetCategory(), A11yLevelA, "language-of-page")
+	if !doc.IsDocument {
+		return result.A11yRuleResult
+	}
+	for _, n := range doc.Elements() {
+		if n.DataAtom == atom.Html && strings.TrimSpace(attr(n, "lang")) == "" {
+			result.violation(n, "missing_lang", A11yImpactSerious,
+				"Document does not declare its language",
+				`Add a lang attribute, e.g. <html lang="en">`, nil)
+		}
+	}
+	return result.A11yRuleResult
+}
+
+func (v *HTMLLanguageValidator) GetCategory() A11yCategory {
+	return A11yCategoryUnderstandable
+}
+
+// HTMLFormLabelValidator checks that form controls have labels
+type HTMLFormLabelValidator struct{}
+
+func (v *HTMLFormLabelValidator) ValidateA11y(element any, context *A11yValidationContext) *A11yRuleResult {
+	doc, ok := htmlDocument(element)
+	if !ok {
+		return nil
+	}
+	result := newHTMLRuleResult("wcag332", doc, v.GetCategory(), A11yLevelA, "labels-or-instructions")
+
+	for _, n := range doc.Elements() {
+		if n.DataAtom == atom.Label {
+			if id := attr(n, "for"); id != "" && doc.ElementByID(id) == nil {
+				result.violation(n, "label_target_missing", A11yImpactModerate,
+					fmt.Sprintf("Label refers to missing control %q", id),
+					"Point the for attribute at the id of the labelled control", nil)
+			}
+			continue
+		}
+		if !isLabelable(n) || isHiddenInTree(n) {
+			continue
+		}
+		if doc.accessibleName(n) != "" {
+			continue
+		}
+		if strings.TrimSpace(attr(n, "placeholder")) != "" {
+			result.violation(n, "placeholder_only_label", A11yImpactSerious,
+				"Form control is labelled only by its placeholder",
+				"Add a visible <label> associated with the control; placeholders disappear while typing", nil)
+			continue
+		}
+		result.violation(n, "missing_form_label", A11yImpactCritical,
+			"Form control has no label",
+			`Add <label for="id">, wrap the control in a <label>, or use aria-labelledby`, nil)
+	}
+	return result.A11yRuleResult
+}
+
+func (v *HTMLFormLabelValidator) GetCategory() A11yCategory {
+	return A11yCategoryUnderstandable
+}
+
+// isLabelable reports whether an element is a form control that needs a label
+func isLabelable(n *html.Node) bool {
+	switch n.DataAtom {
+	case atom.Select, atom.Textarea:
+		return true
+	case atom.Input:
+		switch strings.ToLower(attr(n, "type")) {
+		case "hidden", "submit", "reset", "button", "image":
+			return false
+		}
+		return true
+	}
+	switch attr(n, "role") {
+	case "textbox", "searchbox", "combobox", "listbox", "slider", "spinbutton", "checkbox", "radio", "switch":
+		return true
+	}
+	return false
+}
+
+// HTMLAriaValidator checks ARIA roles, states and properties, ID references
+// and accessible names of controls
+type HTMLAriaValidator struct{}
+
+func (v *HTMLAriaValidator) ValidateA11y(element any, context *A11yValidationContext) *A11yRuleResult {
+	doc, ok := htmlDocument(element)
+	if !ok {
+		return nil
+	}
+	result := newHTMLRuleResult("wcag412", doc, v.GetCategory(), A11yLevelA, "name-role-value")
+
+	for id, nodes := range doc.ids {
+		if len(nodes) > 1 {
+			result.warning(nodes[1], "duplicate_id",
+				fmt.Sprintf("id %q is used by %d elements", id, len(nodes)),
+				"Give each element a unique id so labels and ARIA references resolve")
+		}
+	}
+
+	for _, n := range doc.Elements() {
+		role := attr(n, "role")
+		if role != "" {
+			// The first recognised token of a role list is used
+			if !slices.ContainsFunc(strings.Fields(role), func(r string) bool { return ariaRoles[r] }) {
+				result.violation(n, "invalid_role", A11yImpactSerious,
+					fmt.Sprintf("Role %q is not a valid WAI-ARIA role", role),
+					"Use a role from WAI-ARIA 1.2 or a native element", nil)
+			}
+			role = strings.Fields(role)[0]
+			for _, state := range ariaRequiredStates[role] {
+				if !hasAttrOrBinding(n, state) && !nativelySupplies(n, state) {
+					result.violation(n, "missing_required_state", A11yImpactCritical,
+						fmt.Sprintf("Role %q requires %s", role, state),
+						fmt.Sprintf("Add %s and keep it in sync with the widget state", state), nil)
+				}
+			}
+		}
+
+		for _, a := range n.Attr {
+			if !strings.HasPrefix(a.Key, "aria-") {
+				continue
+			}
+			kind, known := ariaAttributes[a.Key]
+			if !known {
+				result.violation(n, "invalid_aria_attribute", A11yImpactSerious,
+					fmt.Sprintf("%s is not a valid ARIA attribute", a.Key),
+					"Remove the attribute or fix its spelling", nil)
+				continue
+			}
+			if msg := validateAriaValue(doc, kind, a.Val); msg != "" {
+				result.violation(n, "invalid_aria_value", A11yImpactSerious,
+					fmt.Sprintf("%s=%q %s", a.Key, a.Val, msg),
+					"Use a value allowed by WAI-ARIA 1.2", nil)
+			}
+		}
+
+		if attr(n, "aria-hidden") == "true" {
+			if focusable := firstFocusable(n); focusable != nil {
+				result.violation(focusable, "focusable_aria_hidden", A11yImpactSerious,
+					"Focusable element is inside aria-hidden content",
+					`Remove aria-hidden or make the element unfocusable with tabindex="-1"`, nil)
+			}
+		}
+
+		if isHiddenInTree(n) {
+			continue
+		}
+		needsName := n.DataAtom == atom.Button ||
+			(n.DataAtom == atom.A && isNativelyFocusable(n)) ||
+			(strings.EqualFold(attr(n, "type"), "button") && n.DataAtom == atom.Input) ||
+			nameRequiredRoles[role]
+		if needsName && doc.accessibleName(n) == "" {
+			result.violation(n, "missing_accessible_name", A11yImpactCritical,
+				"Control has no accessible name",
+				"Add text content, aria-label or aria-labelledby", nil)
+		}
+	}
+	return result.A11yRuleResult
+}
+
+func (v *HTMLAriaValidator) GetCategory() A11yCategory {
+	return A11yCategoryRobust
+}
+
+// nativelySupplies reports whether a native element provides an ARIA state,
+// e.g. a checkbox input with role="switch" supplies aria-checked
+func nativelySupplies(n *html.Node, state string) bool {
+	if n.DataAtom != atom.Input {
+		return false
+	}
+	inputType := strings.ToLower(attr(n, "type"))
+	switch state {
+	case "aria-checked":
+		return inputType == "checkbox" || inputType == "radio"
+	case "aria-valuenow":
+		return inputType == "range" || inputType == "number"
+	}
+	return false
+}
+
+// firstFocusable returns the element or first descendant that is focusable
+func firstFocusable(n *html.Node) *html.Node {
+	if n.Type == html.ElementNode && isFocusable(n) {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := firstFocusable(c); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// ariaValueKind describes the allowed values of an ARIA attribute
+type ariaValueKind struct {
+	tokens []string // allowed tokens; empty means free text
+	list   bool     // whitespace separated list of tokens
+	number bool
+	idRefs bool
+}
+
+var (
+	ariaBoolean  = ariaValueKind{tokens: []string{"true", "false"}}
+	ariaTristate = ariaValueKind{tokens: []string{"true", "false", "mixed", "undefined"}}
+	ariaOptional = ariaValueKind{tokens: []string{"true", "false", "undefined"}}
+	ariaNumber   = ariaValueKind{number: true}
+	ariaIDRefs   = ariaValueKind{idRefs: true}
+	ariaText     = ariaValueKind{}
+)
+
+// ariaAttributes lists WAI-ARIA 1.2 states and properties
+var ariaAttributes = map[string]ariaValueKind{
+	"aria-activedescendant":       ariaIDRefs,
+	"aria-atomic":                 ariaBoolean,
+	"aria-autocomplete":           {tokens: []string{"inline", "list", "both", "none"}},
+	"aria-braillelabel":           ariaText,
+	"aria-brailleroledescription": ariaText,
+	"aria-busy":                   ariaBoolean,
+	"aria-checked":                ariaTristate,
+	"aria-colcount":               ariaNumber,
+	"aria-colindex":               ariaNumber,
+	"aria-colindextext":           ariaText,
+	"aria-colspan":                ariaNumber,
+	"aria-controls":               ariaIDRefs,
+	"aria-current":                {tokens: []string{"page", "step", "location", "date", "time", "true", "false"}},
+	"aria-describedby":            ariaIDRefs,
+	"aria-description":            ariaText,
+	"aria-details":                ariaIDRefs,
+	"aria-disabled":               ariaBoolean,
+	"aria-dropeffect":             {tokens: []string{"copy", "execute", "link", "move", "none", "popup"}, list: true},
+	"aria-errormessage":           ariaIDRefs,
+	"aria-expanded":               ariaOptional,
+	"aria-flowto":                 ariaIDRefs,
+	"aria-grabbed":                ariaOptional,
+	"aria-haspopup":               {tokens: []string{"false", "true", "menu", "listbox", "tree", "grid", "dialog"}},
+	"aria-hidden":                 ariaOptional,
+	"aria-invalid":                {tokens: []string{"grammar", "false", "spelling", "true"}},
+	"aria-keyshortcuts":           ariaText,
+	"aria-label":                  ariaText,
+	"aria-labelledby":             ariaIDRefs,
+	"aria-level":                  ariaNumber,
+	"aria-live":                   {tokens: []string{"assertive", "off", "polite"}},
+	"aria-modal":                  ariaBoolean,
+	"aria-multiline":              ariaBoolean,
+	"aria-multiselectable":        ariaBoolean,
+	"aria-orientation":            {tokens: []string{"horizontal", "vertical", "undefined"}},
+	"aria-owns":                   ariaIDRefs,
+	"aria-placeholder":            ariaText,
+	"aria-posinset":               ariaNumber,
+	"aria-pressed":                ariaTristate,
+	"aria-readonly":               ariaBoolean,
+	"aria-relevant":               {tokens: []string{"additions", "all", "removals", "text"}, list: true},
+	"aria-required":               ariaBoolean,
+	"aria-roledescription":        ariaText,
+	"aria-rowcount":               ariaNumber,
+	"aria-rowindex":               ariaNumber,
+	"aria-rowindextext":           ariaText,
+	"aria-rowspan":                ariaNumber,
+	"aria-selected":               ariaOptional,
+	"aria-setsize":                ariaNumber,
+	"aria-sort":                   {tokens: []string{"ascending", "descending", "none", "other"}},
+	"aria-valuemax":               ariaNumber,
+	"aria-valuemin":               ariaNumber,
+	"aria-valuenow":               ariaNumber,
+	"aria-valuetext":              ariaText,
+}
+
+// validateAriaValue returns a description of what is wrong with value, or ""
+func validateAriaValue(doc *HTMLDocument, kind ariaValueKind, value string) string {
+	value = strings.TrimSpace(value)
+	switch {
+	case kind.number:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return "is not a number"
+		}
+	case kind.idRefs:
+		for _, id := range strings.Fields(value) {
+			if doc.ElementByID(id) == nil {
+				return fmt.Sprintf("refers to missing id %q", id)
+			}
+		}
+	case len(kind.tokens) > 0:
+		values := []string{strings.ToLower(value)}
+		if kind.list {
+			values = strings.Fields(strings.ToLower(value))
+		}
+		for _, v := range values {
+			if !slices.Contains(kind.tokens, v) {
+				return fmt.Sprintf("is not one of %s", strings.Join(kind.tokens, ", "))
+			}
+		}
+	}
+	return ""
+}
+
+// ariaRoles lists the concrete WAI-ARIA 1.2 roles
+var ariaRoles = toSet(
+	"alert", "alertdialog", "application", "article", "banner", "blockquote", "button", "caption",
+	"cell", "checkbox", "code", "columnheader", "combobox", "complementary", "contentinfo",
+	"definition", "deletion", "dialog", "directory", "document", "emphasis", "feed", "figure",
+	"form", "generic", "grid", "gridcell", "group", "heading", "img", "insertion", "link", "list",
+	"listbox", "listitem", "log", "main", "marquee", "math", "menu", "menubar", "menuitem",
+	"menuitemcheckbox", "menuitemradio", "meter", "navigation", "none", "note", "option",
+	"paragraph", "presentation", "progressbar", "radio", "radiogroup", "region", "row",
+	"rowgroup", "rowheader", "scrollbar", "search", "searchbox", "separator", "slider",
+	"spinbutton", "status", "strong", "subscript", "superscript", "switch", "tab", "table",
+	"tablist", "tabpanel", "term", "textbox", "time", "timer", "toolbar", "tooltip", "tree",
+	"treegrid", "treeitem",
+)
+
+// widgetRoles are interactive roles
+var widgetRoles = toSet(
+	"button", "checkbox", "combobox", "gridcell", "link", "listbox", "menuitem", "menuitemcheckbox",
+	"menuitemradio", "option", "radio", "scrollbar", "searchbox", "slider", "spinbutton", "switch",
+	"tab", "textbox", "treeitem",
+)
+
+// nameRequiredRoles must have an accessible name
+var nameRequiredRoles = toSet(
+	"button", "checkbox", "combobox", "dialog", "alertdialog", "link", "listbox", "menuitem",
+	"menuitemcheckbox", "menuitemradio", "meter", "option", "progressbar", "radio", "radiogroup",
+	"searchbox", "slider", "spinbutton", "switch", "tab", "tabpanel", "textbox", "tree", "treeitem",
+)
+
+// ariaRequiredStates lists states a role must declare
+var ariaRequiredStates = map[string][]string{
+	"checkbox":         {"aria-checked"},
+	"combobox":         {"aria-expanded"},
+	"heading":          {"aria-level"},
+	"menuitemcheckbox": {"aria-checked"},
+	"menuitemradio":    {"aria-checked"},
+	"meter":            {"aria-valuenow"},
+	"radio":            {"aria-checked"},
+	"scrollbar":        {"aria-controls", "aria-valuenow"},
+	"slider":           {"aria-valuenow"},
+	"switch":           {"aria-checked"},
+}
+
+func toSet(values ...string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
@@ -0,0 +1,209 @@
+package validation
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func auditMarkup(t *testing.T, auditor *HTMLAuditor, markup string) *AccessibilityResult {
+	t.Helper()
+	result, err := auditor.AuditHTML(context.Background(), "test.html", strings.NewReader(markup))
+	require.NoError(t, err)
+	return result
+}
+
+func violationCodes(result *AccessibilityResult) []string {
+	codes := make([]string, 0, len(result.Violations))
+	for _, violation := range result.Violations {
+		codes = append(codes, violation.Code)
+	}
+	return codes
+}
+
+func contrastViolations(result *AccessibilityResult) []A11yViolation {
+	var violations []A11yViolation
+	for _, violation := range result.Violations {
+		if violation.Code == "insufficient_contrast" {
+			violations = append(violations, violation)
+		}
+	}
+	return violations
+}
+
+func TestHTMLAuditor_Rules(t *testing.T) {
+	tests := []struct {
+		name   string
+		markup string
+		code   string
+	}{
+		{"image without alt", `<img src="logo.png">`, "missing_alt_text"},
+		{"unlabelled input", `<input type="text" name="email">`, "missing_form_label"},
+		{"skipped heading level", `<h1>Title</h1><h3>Section</h3>`, "heading_order"},
+		{"empty heading", `<h2></h2>`, "empty_heading"},
+		{"unknown role", `<div role="banana">x</div>`, "invalid_role"},
+		{"document without lang", `<!DOCTYPE html><html><head><title>t</title></head><body><p>x</p></body></html>`, "missing_lang"},
+		{"low inline contrast", `<p style="color: #777; background-color: #888">Faint</p>`, "insufficient_contrast"},
+	}
+
+	auditor := NewHTMLAuditor(nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := auditMarkup(t, auditor, tt.markup)
+			assert.False(t, result.Compliant)
+			assert.Contains(t, violationCodes(result), tt.code)
+		})
+	}
+}
+
+func TestHTMLAuditor_Warnings(t *testing.T) {
+	auditor := NewHTMLAuditor(nil)
+	for markup, code := range map[string]string{
+		`<p id="a">one</p><p id="a">two</p>`: "duplicate_id",
+		`<button tabindex="2">Save</button>`: "positive_tabindex",
+	} {
+		result := auditMarkup(t, auditor, markup)
+		var codes []string
+		for _, warning := range result.Warnings {
+			codes = append(codes, warning.Code)
+		}
+		assert.Contains(t, codes, code, markup)
+	}
+}
+
+func TestHTMLAuditor_AccessibleMarkupPasses(t *testing.T) {
+	markup := `<!DOCTYPE html><html lang="en"><head><title>Form</title></head><body>
+		<h1>Sign in</h1>
+		<img src="logo.png" alt="Company logo">
+		<label for="email">Email</label><input id="email" type="email">
+		<button type="submit">Sign in</button>
+	</body></html>`
+
+	result := auditMarkup(t, NewHTMLAuditor(nil), markup)
+	assert.True(t, result.Compliant, "unexpected violations: %v", violationCodes(result))
+	assert.Empty(t, result.Violations)
+}
+
+func TestHTMLAuditor_AuditComponent(t *testing.T) {
+	component := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, `<button type="button"></button>`)
+		return err
+	})
+
+	result, err := NewHTMLAuditor(nil).AuditComponent(context.Background(), "IconButton", component)
+	require.NoError(t, err)
+	require.NotEmpty(t, result.Violations)
+	assert.Equal(t, "missing_accessible_name", result.Violations[0].Code)
+	assert.Equal(t, "IconButton", result.Violations[0].Location.File)
+}
+
+func TestHTMLAuditor_ContrastTokens(t *testing.T) {
+	tokens := map[string]string{
+		"--color-foreground": "#111111",
+		"--color-muted":      "#aaaaaa",
+		"--color-primary":    "var(--brand)",
+		"--brand":            "220 90% 40%",
+	}
+	auditor := NewHTMLAuditor(tokens)
+
+	assert.Empty(t, contrastViolations(auditMarkup(t, auditor, `<p class="text-foreground">Body</p>`)))
+	assert.Empty(t, contrastViolations(auditMarkup(t, auditor, `<p class="text-primary">Link</p>`)))
+
+	violations := contrastViolations(auditMarkup(t, auditor, `<p class="text-muted">Hint</p>`))
+	require.Len(t, violations, 1)
+	assert.Equal(t, "#aaaaaa", violations[0].Context["foregroundColor"])
+	assert.Equal(t, "light", violations[0].Context["colorScheme"])
+}
+
+func TestHTMLAuditor_DarkVariants(t *testing.T) {
+	tokens := map[string]string{
+		"--color-foreground": "#111111",
+		"--color-background": "#ffffff",
+		"--color-surface":    "#0a0a0a",
+		"--color-dim":        "#333333",
+		"--color-bright":     "#f5f5f5",
+	}
+	auditor := NewHTMLAuditor(tokens)
+
+	t.Run("low contrast dark variant", func(t *testing.T) {
+		result := auditMarkup(t, auditor, `<div class="bg-background dark:bg-surface"><p class="text-foreground dark:text-dim">Hi</p></div>`)
+		violations := contrastViolations(result)
+		require.Len(t, violations, 1)
+		assert.Equal(t, "dark", violations[0].Context["colorScheme"])
+		assert.Contains(t, violations[0].Message, "Dark mode")
+	})
+
+	t.Run("readable dark variant", func(t *testing.T) {
+		result := auditMarkup(t, auditor, `<div class="bg-background dark:bg-surface"><p class="text-foreground dark:text-bright">Hi</p></div>`)
+		assert.Empty(t, contrastViolations(result))
+	})
+
+	t.Run("variant order does not matter", func(t *testing.T) {
+		result := auditMarkup(t, auditor, `<div class="dark:bg-surface bg-background"><p class="dark:text-dim text-foreground">Hi</p></div>`)
+		assert.Len(t, contrastViolations(result), 1)
+	})
+}
+
+func TestHTMLAuditor_DarkTokens(t *testing.T) {
+	auditor := NewHTMLAuditor(map[string]string{
+		"--color-foreground": "#111111",
+		"--color-background": "#ffffff",
+	})
+	markup := `<div class="bg-background"><p class="text-foreground">Body</p></div>`
+
+	assert.Empty(t, contrastViolations(auditMarkup(t, auditor, markup)))
+
+	auditor.SetDarkTokens(map[string]string{"--color-background": "#000000"})
+	violations := contrastViolations(auditMarkup(t, auditor, markup))
+	require.Len(t, violations, 1)
+	assert.Equal(t, "dark", violations[0].Context["colorScheme"])
+	assert.Equal(t, "#000000", violations[0].Context["backgroundColor"])
+}
+
+func TestParseCSSVariables(t *testing.T) {
+	css := `:root { --color-primary: #2563eb; --radius: 0.5rem; } .dark { --color-primary: #60a5fa; }`
+
+	variables := ParseCSSVariables(css)
+	assert.Equal(t, "#60a5fa", variables["--color-primary"])
+	assert.Equal(t, "0.5rem", variables["--radius"])
+}
+
+func TestBuildIntegration_AccessibilityValidation(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.html"),
+		[]byte(`<!DOCTYPE html><html lang="en"><head><title>Home</title></head><body><h1>Home</h1></body></html>`), 0o644))
+
+	bi := NewBuildIntegration()
+	command := &BuildCommand{Name: "a11y", WorkingDir: dir}
+
+	result := bi.runValidationCommand(context.Background(), command, BuildResult{})
+	assert.Equal(t, BuildStatusSuccess, result.Status, result.Error)
+
+	bi.AuditComponent("Avatar", templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, `<img src="avatar.png">`)
+		return err
+	}))
+	result = bi.runValidationCommand(context.Background(), command, BuildResult{})
+	assert.Equal(t, BuildStatusFailed, result.Status)
+	require.NotNil(t, result.Validation)
+	require.NotEmpty(t, result.Validation.Errors)
+	assert.Equal(t, "missing_alt_text", result.Validation.Errors[0].Code)
+	assert.Equal(t, "Avatar", result.Validation.Errors[0].Component)
+}
+
+func TestBuildIntegration_ValidateAccessibilityFile(t *testing.T) {
+	dir := t.TempDir()
+	page := filepath.Join(dir, "page.html")
+	require.NoError(t, os.WriteFile(page, []byte(`<input type="text">`), 0o644))
+
+	bi := NewBuildIntegration()
+	assert.Equal(t, []string{"accessibility"}, bi.determineValidationsForFile(page))
+	assert.Error(t, bi.runValidation(context.Background(), "accessibility", page))
+}
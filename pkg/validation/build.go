@@ -4,13 +4,19 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/fs"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/a-h/templ"
+	"github.com/fsnotify/fsnotify"
 )
 
 // BuildIntegration provides validation integration with build processes
@@ -21,6 +27,11 @@ type BuildIntegration struct {
 	hooks     []BuildHook
 	cache     *BuildCache
 	reporter  *BuildReporter
+	deps      *DependencyIndex
+	stream    *WatchStream
+	session   *watchSession
+	auditor   *HTMLAuditor
+	audited   map[string]templ.Component
 	mutex     sync.RWMutex
 }
 
@@ -32,11 +43,14 @@ type BuildIntegrationConfig struct {
 	EnableCaching         bool          `json:"enableCaching"`
 	WatchDirectories      []string      `json:"watchDirectories"`
 	WatchFileExtensions   []string      `json:"watchFileExtensions"`
+	WatchDebounce         time.Duration `json:"watchDebounce"`
 	IgnorePatterns        []string      `json:"ignorePatterns"`
 	CacheTTL              time.Duration `json:"cacheTTL"`
 	ValidationTimeout     time.Duration `json:"validationTimeout"`
 	ConcurrentValidations int           `json:"concurrentValidations"`
 	ReportPath            string        `json:"reportPath"`
+	EnableSARIFReport     bool          `json:"enableSarifReport"` // also write build-report.sarif
+	EnableJUnitReport     bool          `json:"enableJunitReport"` // also write build-report.junit.xml
 	ExitOnFailure         bool          `json:"exitOnFailure"`
 	Verbose               bool          `json:"verbose"`
 }
@@ -139,16 +153,27 @@ const (
 	BuildHookTypePostBuild      BuildHookType = "post_build"
 )
 
-// FileWatcher monitors files for changes
+// FileWatcher monitors files for changes using filesystem notifications.
+// Events are debounced and coalesced per file before delivery, so editors
+// that write a file several times produce a single event.
 type FileWatcher struct {
-	paths      []string
-	extensions []string
-	ignore     []string
-	callback   func(string, FileEvent)
-	running    bool
-	stopChan   chan struct{}
-	mutex      sync.RWMutex
-}
+	paths         []string
+	extensions    []string
+	ignore        []string
+	debounce      time.Duration
+	callback      func(string, FileEvent)
+	batchCallback func([]FileEvent)
+	errorCallback func(error)
+	pending       map[string]*FileEvent // accessed only by the watch goroutine
+	order         []string
+	running       bool
+	stopChan      chan struct{}
+	done          chan struct{}
+	mutex         sync.RWMutex
+}
+
+// defaultWatchDebounce is the quiet period before a batch is delivered
+const defaultWatchDebounce = 100 * time.Millisecond
 
 // FileEvent represents a file system event
 type FileEvent struct {
@@ -270,6 +295,7 @@ func NewBuildIntegration() *BuildIntegration {
 		EnableCaching:         true,
 		WatchDirectories:      []string{"./src", "./components", "./schemas", "./themes"},
 		WatchFileExtensions:   []string{".go", ".templ", ".json", ".yaml", ".js", ".ts", ".tsx"},
+		WatchDebounce:         defaultWatchDebounce,
 		IgnorePatterns:        []string{"node_modules", ".git", "dist", "build"},
 		CacheTTL:              time.Hour * 24,
 		ValidationTimeout:     time.Minute * 10,
@@ -286,6 +312,10 @@ func NewBuildIntegration() *BuildIntegration {
 		hooks:     make([]BuildHook, 0),
 		cache:     NewBuildCache(1000, config.CacheTTL),
 		reporter:  NewBuildReporter(config),
+		deps:      NewDependencyIndex(),
+		stream:    NewWatchStream(),
+		auditor:   NewHTMLAuditor(nil),
+		audited:   make(map[string]templ.Component),
 	}
 }
 
@@ -367,53 +397,145 @@ exit 0
 	return nil
 }
 
-// StartFileWatching starts watching files for changes
+// StartFileWatching indexes the watched directories and starts watching
+// them for changes. Each debounced batch of changes revalidates the changed
+// files and the files that depend on them; results are published to the
+// watch stream and printed to the watch output.
 func (bi *BuildIntegration) StartFileWatching() error {
 	if !bi.config.EnableFileWatching {
 		return nil
 	}
 
+	bi.mutex.Lock()
+	defer bi.mutex.Unlock()
+
+	if bi.session != nil {
+		return fmt.Errorf("file watching already started")
+	}
+
+	if err := bi.deps.Build(bi.config.WatchDirectories, bi.isIndexedFile); err != nil {
+		return fmt.Errorf("failed to build dependency index: %w", err)
+	}
+
+	session := newWatchSession(bi)
 	for _, dir := range bi.config.WatchDirectories {
 		watcher := NewFileWatcher([]string{dir}, bi.config.WatchFileExtensions, bi.config.IgnorePatterns)
-		watcher.SetCallback(bi.onFileChanged)
+		watcher.SetDebounce(bi.config.WatchDebounce)
+		watcher.SetBatchCallback(bi.onFilesChanged)
+		watcher.SetErrorCallback(func(err error) {
+			bi.stream.Publish(WatchEvent{Type: WatchEventError, Error: err.Error()})
+		})
 
 		if err := watcher.Start(); err != nil {
+			for _, started := range bi.watchers {
+				started.Stop()
+			}
+			bi.watchers = make(map[string]*FileWatcher)
+			session.stop()
 			return fmt.Errorf("failed to start file watcher for %s: %w", dir, err)
 		}
 
 		bi.watchers[dir] = watcher
 	}
 
+	bi.session = session
 	return nil
 }
 
 // StopFileWatching stops file watching
 func (bi *BuildIntegration) StopFileWatching() {
-	for _, watcher := range bi.watchers {
+	bi.mutex.Lock()
+	watchers := bi.watchers
+	session := bi.session
+	bi.watchers = make(map[string]*FileWatcher)
+	bi.session = nil
+	bi.mutex.Unlock()
+
+	for _, watcher := range watchers {
 		watcher.Stop()
 	}
+	if session != nil {
+		session.stop()
+	}
 }
 
-// onFileChanged handles file change events
-func (bi *BuildIntegration) onFileChanged(path string, event FileEvent) {
-	if bi.config.Verbose {
-		fmt.Printf("File changed: %s (%s)\n", path, event.Type)
-	}
+// SetWatchOutput sets where watch results are printed (default stdout);
+// nil disables terminal output
+func (bi *BuildIntegration) SetWatchOutput(w io.Writer) {
+	bi.stream.SetOutput(w, bi.config.Verbose)
+}
 
-	// Determine which validations to run based on file type
-	validations := bi.determineValidationsForFile(path)
+// WatchStream returns the stream of watch events
+func (bi *BuildIntegration) WatchStream() *WatchStream {
+	return bi.stream
+}
 
-	// Run validations asynchronously
-	go func() {
-		ctx, cancel := context.WithTimeout(context.Background(), bi.config.ValidationTimeout)
-		defer cancel()
+// WatchHandler returns an SSE handler streaming watch events for dev-mode
+// browser overlays
+func (bi *BuildIntegration) WatchHandler() http.Handler {
+	return NewWatchSSEHandler(bi.stream, 0)
+}
+
+// Dependencies returns the dependency index used for incremental
+// revalidation
+func (bi *BuildIntegration) Dependencies() *DependencyIndex {
+	return bi.deps
+}
 
-		for _, validation := range validations {
-			if err := bi.runValidation(ctx, validation, path); err != nil {
-				fmt.Printf("Validation failed for %s: %v\n", path, err)
+// onFilesChanged handles a debounced batch of file events
+func (bi *BuildIntegration) onFilesChanged(events []FileEvent) {
+	bi.mutex.RLock()
+	session := bi.session
+	bi.mutex.RUnlock()
+	if session == nil {
+		return
+	}
+
+	changed := make([]string, 0, len(events))
+	deleted := make(map[string]bool)
+	for _, event := range events {
+		changed = append(changed, event.Path)
+		if event.Type == FileEventTypeDeleted {
+			deleted[event.Path] = true
+		}
+	}
+
+	// Dependents are resolved before the index forgets deleted files
+	affected := bi.deps.Affected(changed)
+	for _, event := range events {
+		if deleted[event.Path] {
+			bi.deps.Remove(event.Path)
+		} else if bi.isIndexedFile(event.Path) {
+			if err := bi.deps.Update(event.Path); err != nil {
+				bi.stream.Publish(WatchEvent{Type: WatchEventError, Error: fmt.Sprintf("failed to index %s: %v", event.Path, err)})
 			}
 		}
-	}()
+	}
+	affected = mergeSorted(affected, bi.deps.Affected(changed))
+
+	targets := make([]string, 0, len(affected))
+	for _, path := range affected {
+		if !deleted[path] {
+			targets = append(targets, path)
+		}
+	}
+	session.enqueue(changed, targets)
+}
+
+// SetHTMLAuditor replaces the auditor used by accessibility checks, e.g. to
+// resolve the theme's tokens in contrast checks
+func (bi *BuildIntegration) SetHTMLAuditor(auditor *HTMLAuditor) {
+	bi.mutex.Lock()
+	defer bi.mutex.Unlock()
+	bi.auditor = auditor
+}
+
+// AuditComponent registers a component that accessibility checks render and
+// audit alongside the .html files in the command's working directory
+func (bi *BuildIntegration) AuditComponent(name string, component templ.Component) {
+	bi.mutex.Lock()
+	defer bi.mutex.Unlock()
+	bi.audited[name] = component
 }
 
 // AddPipeline adds a build pipeline
@@ -836,6 +958,11 @@ func (bi *BuildIntegration) isComponentFile(path string) bool {
 		(ext == ".json" && strings.Contains(path, "component"))
 }
 
+func (bi *BuildIntegration) isMarkupFile(path string) bool {
+	ext := filepath.Ext(path)
+	return ext == ".html" || ext == ".htm"
+}
+
 func (bi *BuildIntegration) isSchemaFile(path string) bool {
 	ext := filepath.Ext(path)
 	return (ext == ".json" || ext == ".yaml" || ext == ".yml") &&
@@ -847,6 +974,8 @@ func (bi *BuildIntegration) determineValidationsForFile(path string) []string {
 
 	if bi.isComponentFile(path) {
 		validations = append(validations, "component", "accessibility")
+	} else if bi.isMarkupFile(path) {
+		validations = append(validations, "accessibility")
 	}
 
 	if bi.isSchemaFile(path) {
@@ -913,10 +1042,33 @@ func (bi *BuildIntegration) validateSchemaFile(ctx context.Context, path string)
 }
 
 func (bi *BuildIntegration) validateAccessibilityFile(ctx context.Context, path string) error {
-	// Accessibility validation for file
+	if !bi.isMarkupFile(path) {
+		return nil
+	}
+	result, err := bi.auditFile(ctx, path)
+	if err != nil {
+		return err
+	}
+	if !result.Compliant {
+		return fmt.Errorf("accessibility validation failed: %d violations", len(result.Violations))
+	}
 	return nil
 }
 
+// auditFile audits a rendered markup file
+func (bi *BuildIntegration) auditFile(ctx context.Context, path string) (*AccessibilityResult, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	bi.mutex.RLock()
+	auditor := bi.auditor
+	bi.mutex.RUnlock()
+	return auditor.AuditHTML(ctx, path, file)
+}
+
 func (bi *BuildIntegration) validateThemeFile(ctx context.Context, path string) error {
 	// Theme validation for file
 	return nil
@@ -967,11 +1119,105 @@ func (bi *BuildIntegration) runGenericValidation(ctx context.Context, command *B
 	return bi.runGenericCommand(ctx, command, result)
 }
 
+// runAccessibilityValidation audits the registered components and the
+// rendered .html files under the command's working directory
 func (bi *BuildIntegration) runAccessibilityValidation(ctx context.Context, command *BuildCommand, result BuildResult) BuildResult {
-	// Accessibility validation logic
+	bi.mutex.RLock()
+	auditor := bi.auditor
+	names := make([]string, 0, len(bi.audited))
+	components := make(map[string]templ.Component, len(bi.audited))
+	for name, component := range bi.audited {
+		names = append(names, name)
+		components[name] = component
+	}
+	bi.mutex.RUnlock()
+	sort.Strings(names)
+
+	validationResults := make([]*ValidationResult, 0, len(names))
+	for _, name := range names {
+		a11yResult, err := auditor.AuditComponent(ctx, name, components[name])
+		if err != nil {
+			result.Status = BuildStatusFailed
+			result.Error = fmt.Sprintf("Failed to audit component: %v", err)
+			return result
+		}
+		validationResults = append(validationResults, a11yValidationResult(name, a11yResult))
+	}
+
+	if command.WorkingDir != "" {
+		err := filepath.WalkDir(command.WorkingDir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || bi.shouldIgnoreFile(path) || !bi.isMarkupFile(path) {
+				return nil
+			}
+			a11yResult, err := bi.auditFile(ctx, path)
+			if err != nil {
+				return fmt.Errorf("failed to audit %s: %w", path, err)
+			}
+			validationResults = append(validationResults, a11yValidationResult(path, a11yResult))
+			return nil
+		})
+		if err != nil {
+			result.Status = BuildStatusFailed
+			result.Error = fmt.Sprintf("Failed to audit markup: %v", err)
+			return result
+		}
+	}
+
+	combinedResult := bi.combineValidationResults(validationResults)
+	result.Validation = combinedResult
+
+	if combinedResult.Valid {
+		result.Status = BuildStatusSuccess
+		result.Output = fmt.Sprintf("Audited %d components and pages successfully", len(validationResults))
+	} else {
+		result.Status = BuildStatusFailed
+		result.Error = fmt.Sprintf("Accessibility validation failed with %d violations", len(combinedResult.Errors))
+	}
+
 	return result
 }
 
+// a11yValidationResult converts an audit result for a build result
+func a11yValidationResult(source string, result *AccessibilityResult) *ValidationResult {
+	validationResult := &ValidationResult{
+		Valid:         result.Compliant,
+		Level:         ValidationLevel(result.Level),
+		Accessibility: result,
+		Timestamp:     result.Timestamp,
+		Metadata:      map[string]any{"score": result.Score},
+	}
+
+	for _, violation := range result.Violations {
+		validationResult.Errors = append(validationResult.Errors, ValidationError{
+			Code:       violation.Code,
+			Message:    violation.Message,
+			Component:  source,
+			Field:      violation.Element,
+			Level:      ValidationLevelError,
+			Source:     "accessibility",
+			Location:   violation.Location,
+			Suggestion: violation.Fix,
+			Details:    violation.Context,
+			Timestamp:  result.Timestamp,
+		})
+	}
+	for _, warning := range result.Warnings {
+		validationResult.Warnings = append(validationResult.Warnings, ValidationWarning{
+			Code:      warning.Code,
+			Message:   warning.Message,
+			Component: source,
+			Field:     warning.Element,
+			Source:    "accessibility",
+			Location:  warning.Location,
+		})
+	}
+
+	return validationResult
+}
+
 func (bi *BuildIntegration) runPerformanceValidation(ctx context.Context, command *BuildCommand, result BuildResult) BuildResult {
 	// Performance validation logic
 	return result
@@ -1114,7 +1360,8 @@ func NewFileWatcher(paths, extensions, ignore []string) *FileWatcher {
 		paths:      paths,
 		extensions: extensions,
 		ignore:     ignore,
-		stopChan:   make(chan struct{}),
+		debounce:   defaultWatchDebounce,
+		pending:    make(map[string]*FileEvent),
 	}
 }
 
@@ -1122,6 +1369,25 @@ func (fw *FileWatcher) SetCallback(callback func(string, FileEvent)) {
 	fw.callback = callback
 }
 
+// SetBatchCallback sets a callback that receives each debounced batch of
+// coalesced events
+func (fw *FileWatcher) SetBatchCallback(callback func([]FileEvent)) {
+	fw.batchCallback = callback
+}
+
+// SetErrorCallback sets a callback for watcher errors
+func (fw *FileWatcher) SetErrorCallback(callback func(error)) {
+	fw.errorCallback = callback
+}
+
+// SetDebounce sets how long the watcher waits for further events before
+// delivering a batch
+func (fw *FileWatcher) SetDebounce(debounce time.Duration) {
+	fw.mutex.Lock()
+	defer fw.mutex.Unlock()
+	fw.debounce = debounce
+}
+
 func (fw *FileWatcher) Start() error {
 	fw.mutex.Lock()
 	defer fw.mutex.Unlock()
@@ -1130,75 +1396,180 @@ func (fw *FileWatcher) Start() error {
 		return fmt.Errorf("file watcher already running")
 	}
 
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	for _, path := range fw.paths {
+		if err := fw.addTree(watcher, path, false); err != nil {
+			watcher.Close()
+			return err
+		}
+	}
+
+	fw.stopChan = make(chan struct{})
+	fw.done = make(chan struct{})
 	fw.running = true
 
-	go fw.watch()
+	go fw.watch(watcher, fw.stopChan, fw.done)
 	return nil
 }
 
 func (fw *FileWatcher) Stop() {
 	fw.mutex.Lock()
-	defer fw.mutex.Unlock()
-
 	if !fw.running {
+		fw.mutex.Unlock()
 		return
 	}
-
 	fw.running = false
 	close(fw.stopChan)
+	done := fw.done
+	fw.mutex.Unlock()
+
+	<-done
 }
 
-func (fw *FileWatcher) watch() {
-	// Simplified file watching implementation
-	// In production, use a proper file watching library like fsnotify
-	ticker := time.NewTicker(time.Second)
-	defer ticker.Stop()
+func (fw *FileWatcher) watch(watcher *fsnotify.Watcher, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	defer watcher.Close()
+
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+	defer timer.Stop()
 
 	for {
 		select {
-		case <-fw.stopChan:
+		case <-stop:
 			return
-		case <-ticker.C:
-			fw.checkFiles()
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if fw.handleEvent(watcher, event) {
+				fw.mutex.RLock()
+				timer.Reset(fw.debounce)
+				fw.mutex.RUnlock()
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			if fw.errorCallback != nil {
+				fw.errorCallback(err)
+			}
+		case <-timer.C:
+			fw.flush()
 		}
 	}
 }
 
-func (fw *FileWatcher) checkFiles() {
-	for _, path := range fw.paths {
-		filepath.WalkDir(path, func(filePath string, d fs.DirEntry, err error) error {
-			if err != nil {
-				return nil
-			}
+// handleEvent records an fsnotify event and reports whether it is pending
+func (fw *FileWatcher) handleEvent(watcher *fsnotify.Watcher, event fsnotify.Event) bool {
+	path := event.Name
+	if fw.shouldIgnoreFile(path) {
+		return false
+	}
 
-			if fw.shouldIgnoreFile(filePath) {
-				return nil
+	switch {
+	case event.Has(fsnotify.Create):
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			// New directories are not watched yet; files written into them
+			// before the watch is added are reported as created
+			if err := fw.addTree(watcher, path, true); err != nil && fw.errorCallback != nil {
+				fw.errorCallback(err)
 			}
+			return len(fw.pending) > 0
+		}
+		return fw.record(path, FileEventTypeCreated)
+	case event.Has(fsnotify.Write):
+		return fw.record(path, FileEventTypeModified)
+	case event.Has(fsnotify.Remove), event.Has(fsnotify.Rename):
+		return fw.record(path, FileEventTypeDeleted)
+	}
+	return false
+}
 
-			if fw.isWatchedFile(filePath) {
-				// Check file modification time
-				info, err := d.Info()
-				if err != nil {
-					return nil
-				}
-
-				// Simplified change detection
-				if time.Since(info.ModTime()) < time.Second*2 {
-					event := FileEvent{
-						Type:      FileEventTypeModified,
-						Path:      filePath,
-						Timestamp: time.Now(),
-					}
-
-					if fw.callback != nil {
-						fw.callback(filePath, event)
-					}
-				}
-			}
+// record coalesces an event with any pending event for the same file.
+// Created then deleted cancels out, deleted then created is a modification
+// and any later change keeps a pending creation.
+func (fw *FileWatcher) record(path string, eventType FileEventType) bool {
+	if !fw.isWatchedFile(path) {
+		return false
+	}
 
-			return nil
-		})
+	now := time.Now()
+	previous, exists := fw.pending[path]
+	if !exists {
+		fw.pending[path] = &FileEvent{Type: eventType, Path: path, Timestamp: now}
+		fw.order = append(fw.order, path)
+		return true
 	}
+
+	switch {
+	case previous.Type == FileEventTypeCreated && eventType == FileEventTypeDeleted:
+		delete(fw.pending, path)
+		return true
+	case previous.Type == FileEventTypeCreated:
+	case previous.Type == FileEventTypeDeleted && eventType != FileEventTypeDeleted:
+		previous.Type = FileEventTypeModified
+	default:
+		previous.Type = eventType
+	}
+	previous.Timestamp = now
+	return true
+}
+
+// flush delivers the pending events in the order they first occurred
+func (fw *FileWatcher) flush() {
+	events := make([]FileEvent, 0, len(fw.pending))
+	for _, path := range fw.order {
+		if event, ok := fw.pending[path]; ok {
+			events = append(events, *event)
+			delete(fw.pending, path)
+		}
+	}
+	fw.order = fw.order[:0]
+	if len(events) == 0 {
+		return
+	}
+
+	if fw.callback != nil {
+		for _, event := range events {
+			fw.callback(event.Path, event)
+		}
+	}
+	if fw.batchCallback != nil {
+		fw.batchCallback(events)
+	}
+}
+
+// addTree watches a directory and its subdirectories. fsnotify does not
+// watch recursively.
+func (fw *FileWatcher) addTree(watcher *fsnotify.Watcher, root string, recordFiles bool) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root && !recordFiles {
+				return fmt.Errorf("failed to watch %s: %w", root, err)
+			}
+			return nil
+		}
+		if fw.shouldIgnoreFile(path) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if err := watcher.Add(path); err != nil {
+				return fmt.Errorf("failed to watch %s: %w", path, err)
+			}
+			return nil
+		}
+		if recordFiles {
+			fw.record(path, FileEventTypeCreated)
+		}
+		return nil
+	})
 }
 
 func (fw *FileWatcher) shouldIgnoreFile(path string) bool {
@@ -1318,9 +1689,45 @@ func (br *BuildReporter) GenerateReport() error {
 		return fmt.Errorf("failed to write HTML report: %w", err)
 	}
 
+	// Generate SARIF and JUnit reports for code review and test dashboards
+	// when enabled
+	if !br.config.EnableSARIFReport && !br.config.EnableJUnitReport {
+		return nil
+	}
+	artifacts := make([]ReportArtifact, 0, len(br.results))
+	for _, result := range br.results {
+		artifacts = append(artifacts, ArtifactFromBuildResult(result))
+	}
+
+	if br.config.EnableSARIFReport {
+		sarifPath := filepath.Join(br.config.ReportPath, "build-report.sarif")
+		if err := writeReportFile(sarifPath, artifacts, NewSARIFReporter("ruun-validation", "").Write); err != nil {
+			return fmt.Errorf("failed to write SARIF report: %w", err)
+		}
+	}
+
+	if br.config.EnableJUnitReport {
+		junitPath := filepath.Join(br.config.ReportPath, "build-report.junit.xml")
+		if err := writeReportFile(junitPath, artifacts, NewJUnitReporter("build").Write); err != nil {
+			return fmt.Errorf("failed to write JUnit report: %w", err)
+		}
+	}
+
 	return nil
 }
 
+func writeReportFile(path string, artifacts []ReportArtifact, write func(io.Writer, []ReportArtifact) error) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(file, artifacts); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
 func (br *BuildReporter) generateHTMLReport(path string) error {
 	// Generate HTML report
 	html := `<!DOCTYPE html>
@@ -0,0 +1,407 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DependencyKind classifies an indexed file
+type DependencyKind string
+
+const (
+	DependencyKindSchema    DependencyKind = "schema"
+	DependencyKindMixin     DependencyKind = "mixin"
+	DependencyKindTheme     DependencyKind = "theme"
+	DependencyKindComponent DependencyKind = "component"
+)
+
+// DependencyNode describes what an indexed file defines and uses.
+// Entities are keyed as "kind:id", e.g. "mixin:audit_fields". Templ and Go
+// files define one "component:Name" entity per component or declaration
+// in Defines.
+type DependencyNode struct {
+	Path    string         `json:"path"`
+	Kind    DependencyKind `json:"kind"`
+	ID      string         `json:"id"`
+	Defines []string       `json:"defines,omitempty"`
+	Uses    []string       `json:"uses,omitempty"`
+	Errors  []string       `json:"errors,omitempty"`
+}
+
+// Key returns the entity key the file defines
+func (n *DependencyNode) Key() string {
+	return string(n.Kind) + ":" + n.ID
+}
+
+// Keys returns every entity key the file defines
+func (n *DependencyNode) Keys() []string {
+	return append([]string{n.Key()}, n.Defines...)
+}
+
+// DependencyIndex records which schemas and components use which schemas,
+// mixins and themes, so a change to one file revalidates only the files
+// that depend on it
+type DependencyIndex struct {
+	files   map[string]*DependencyNode
+	defines map[string]string          // entity key -> path
+	users   map[string]map[string]bool // entity key -> paths using it
+	mutex   sync.RWMutex
+}
+
+// NewDependencyIndex creates an empty dependency index
+func NewDependencyIndex() *DependencyIndex {
+	return &DependencyIndex{
+		files:   make(map[string]*DependencyNode),
+		defines: make(map[string]string),
+		users:   make(map[string]map[string]bool),
+	}
+}
+
+// Build indexes every file below dirs accepted by include. Missing
+// directories are skipped.
+func (di *DependencyIndex) Build(dirs []string, include func(path string) bool) error {
+	for _, dir := range dirs {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if path == dir && os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if d.IsDir() || !include(path) {
+				return nil
+			}
+			return di.Update(path)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Update (re)indexes a file. A file that cannot be parsed is indexed
+// without uses so it is still revalidated when it changes.
+func (di *DependencyIndex) Update(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	node, parseErr := parseDependencyNode(path, data)
+
+	di.mutex.Lock()
+	defer di.mutex.Unlock()
+	di.remove(path)
+	di.files[path] = node
+	for _, key := range node.Keys() {
+		di.defines[key] = path
+	}
+	for _, key := range node.Uses {
+		if di.users[key] == nil {
+			di.users[key] = make(map[string]bool)
+		}
+		di.users[key][path] = true
+	}
+	return parseErr
+}
+
+// Remove drops a file from the index
+func (di *DependencyIndex) Remove(path string) {
+	di.mutex.Lock()
+	defer di.mutex.Unlock()
+	di.remove(path)
+}
+
+func (di *DependencyIndex) remove(path string) {
+	node, exists := di.files[path]
+	if !exists {
+		return
+	}
+	for _, key := range node.Keys() {
+		if di.defines[key] == path {
+			delete(di.defines, key)
+		}
+	}
+	for _, key := range node.Uses {
+		delete(di.users[key], path)
+		if len(di.users[key]) == 0 {
+			delete(di.users, key)
+		}
+	}
+	delete(di.files, path)
+}
+
+// Node returns the index entry of a file
+func (di *DependencyIndex) Node(path string) (*DependencyNode, bool) {
+	di.mutex.RLock()
+	defer di.mutex.RUnlock()
+	node, exists := di.files[path]
+	return node, exists
+}
+
+// Dependents returns the files that use entities defined by path, directly
+// or transitively, sorted
+func (di *DependencyIndex) Dependents(path string) []string {
+	affected := di.Affected([]string{path})
+	return slices.DeleteFunc(affected, func(p string) bool { return p == path })
+}
+
+// Affected returns the given paths plus every file that transitively
+// depends on them, sorted
+func (di *DependencyIndex) Affected(paths []string) []string {
+	di.mutex.RLock()
+	defer di.mutex.RUnlock()
+
+	seen := make(map[string]bool, len(paths))
+	queue := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if !seen[path] {
+			seen[path] = true
+			queue = append(queue, path)
+		}
+	}
+	for len(queue) > 0 {
+		path := queue[0]
+		queue = queue[1:]
+		node, exists := di.files[path]
+		if !exists {
+			continue
+		}
+		for _, key := range node.Keys() {
+			for user := range di.users[key] {
+				if !seen[user] {
+					seen[user] = true
+					queue = append(queue, user)
+				}
+			}
+		}
+	}
+
+	affected := make([]string, 0, len(seen))
+	for path := range seen {
+		affected = append(affected, path)
+	}
+	slices.Sort(affected)
+	return affected
+}
+
+// parseDependencyNode classifies a file and extracts its references:
+// "extends" and "$ref" name schemas, "mixins" names mixins and
+// "theme"/"themeId" name themes
+func parseDependencyNode(path string, data []byte) (*DependencyNode, error) {
+	node := &DependencyNode{
+		Path: path,
+		Kind: dependencyKindForPath(path),
+		ID:   strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+	}
+
+	var doc any
+	var err error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &doc)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &doc)
+	case ".templ":
+		parseTemplDependencies(node, data)
+		return node, nil
+	case ".go":
+		if err := parseGoDependencies(node, data); err != nil {
+			node.Errors = append(node.Errors, err.Error())
+			return node, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		return node, nil
+	default:
+		return node, nil
+	}
+	if err != nil {
+		node.Errors = append(node.Errors, err.Error())
+		return node, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	object, ok := doc.(map[string]any)
+	if !ok {
+		return node, nil
+	}
+	if id, ok := object["id"].(string); ok && id != "" {
+		node.ID = id
+	}
+
+	uses := make(map[string]bool)
+	if extends, ok := object["extends"].(string); ok && extends != "" {
+		uses[schemaEntityKey(extends)] = true
+	}
+	if mixins, ok := object["mixins"].([]any); ok {
+		for _, mixin := range mixins {
+			switch m := mixin.(type) {
+			case string:
+				uses["mixin:"+m] = true
+			case map[string]any:
+				if id, ok := m["id"].(string); ok {
+					uses["mixin:"+id] = true
+				}
+			}
+		}
+	}
+	collectReferences(object, uses)
+	delete(uses, node.Key())
+
+	for key := range uses {
+		node.Uses = append(node.Uses, key)
+	}
+	slices.Sort(node.Uses)
+	return node, nil
+}
+
+// collectReferences walks a document for $ref and theme references
+func collectReferences(value any, uses map[string]bool) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			if ref, ok := child.(string); ok && ref != "" {
+				switch key {
+				case "$ref":
+					uses[schemaEntityKey(ref)] = true
+				case "theme", "themeId", "theme_id":
+					uses["theme:"+ref] = true
+				}
+			}
+			collectReferences(child, uses)
+		}
+	case []any:
+		for _, child := range v {
+			collectReferences(child, uses)
+		}
+	}
+}
+
+var (
+	templDefinition = regexp.MustCompile(`(?m)^templ\s+(?:\([^)]*\)\s*)?(\w+)\s*\(([^)]*)\)`)
+	templCall       = regexp.MustCompile(`@(?:\w+\.)?(\w+)\s*[({]`)
+	templSelector   = regexp.MustCompile(`\b[a-z]\w*\.([A-Z]\w*)`)
+	exportedName    = regexp.MustCompile(`\b[A-Z]\w*`)
+)
+
+// parseTemplDependencies records the components a templ file declares
+// ("templ Name(") and what it uses: the components it renders ("@Name(" or
+// "@pkg.Name("), the exported types in its signatures and the exported
+// identifiers it selects from other packages
+func parseTemplDependencies(node *DependencyNode, data []byte) {
+	defines := make(map[string]bool)
+	uses := make(map[string]bool)
+	for _, match := range templDefinition.FindAllSubmatch(data, -1) {
+		defines[componentEntityKey(string(match[1]))] = true
+		for _, name := range exportedName.FindAll(match[2], -1) {
+			uses[componentEntityKey(string(name))] = true
+		}
+	}
+	for _, pattern := range []*regexp.Regexp{templCall, templSelector} {
+		for _, match := range pattern.FindAllSubmatch(data, -1) {
+			uses[componentEntityKey(string(match[1]))] = true
+		}
+	}
+	setComponentDependencies(node, defines, uses)
+}
+
+// parseGoDependencies records the top-level functions and types a Go file
+// declares and the exported identifiers it calls or selects, so Go helpers
+// and the components that use them are revalidated together
+func parseGoDependencies(node *DependencyNode, data []byte) error {
+	file, err := parser.ParseFile(token.NewFileSet(), node.Path, data, parser.SkipObjectResolution)
+	if err != nil {
+		return err
+	}
+
+	defines := make(map[string]bool)
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil {
+				defines[componentEntityKey(d.Name.Name)] = true
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				if t, ok := spec.(*ast.TypeSpec); ok {
+					defines[componentEntityKey(t.Name.Name)] = true
+				}
+			}
+		}
+	}
+
+	uses := make(map[string]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch e := n.(type) {
+		case *ast.SelectorExpr:
+			if e.Sel.IsExported() {
+				uses[componentEntityKey(e.Sel.Name)] = true
+			}
+		case *ast.CallExpr:
+			if ident, ok := e.Fun.(*ast.Ident); ok && types.Universe.Lookup(ident.Name) == nil {
+				uses[componentEntityKey(ident.Name)] = true
+			}
+		}
+		return true
+	})
+	setComponentDependencies(node, defines, uses)
+	return nil
+}
+
+func setComponentDependencies(node *DependencyNode, defines, uses map[string]bool) {
+	delete(defines, node.Key())
+	for key := range defines {
+		node.Defines = append(node.Defines, key)
+		delete(uses, key)
+	}
+	delete(uses, node.Key())
+	for key := range uses {
+		node.Uses = append(node.Uses, key)
+	}
+	slices.Sort(node.Defines)
+	slices.Sort(node.Uses)
+}
+
+func componentEntityKey(name string) string {
+	return string(DependencyKindComponent) + ":" + name
+}
+
+// schemaEntityKey strips the version and fragment of a schema reference
+// such as "user.base@1.0.0#email"
+func schemaEntityKey(ref string) string {
+	ref, _, _ = strings.Cut(ref, "#")
+	ref, _, _ = strings.Cut(ref, "@")
+	return "schema:" + ref
+}
+
+func dependencyKindForPath(path string) DependencyKind {
+	lower := strings.ToLower(filepath.ToSlash(path))
+	switch {
+	case strings.Contains(lower, "mixin"):
+		return DependencyKindMixin
+	case strings.Contains(lower, "theme"):
+		return DependencyKindTheme
+	case strings.Contains(lower, "schema"):
+		return DependencyKindSchema
+	default:
+		return DependencyKindComponent
+	}
+}
+
+// mergeSorted merges two sorted, deduplicated slices
+func mergeSorted(a, b []string) []string {
+	merged := append(slices.Clone(a), b...)
+	slices.Sort(merged)
+	return slices.Compact(merged)
+}
@@ -0,0 +1,116 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestDependencyIndex_SchemaReferences(t *testing.T) {
+	dir := t.TempDir()
+	mixin := writeFile(t, dir, "mixins/audit.json", `{"id": "audit_fields"}`)
+	theme := writeFile(t, dir, "themes/dark.yaml", "id: dark\n")
+	base := writeFile(t, dir, "schemas/user.base.json", `{"id": "user.base", "mixins": ["audit_fields"]}`)
+	user := writeFile(t, dir, "schemas/user.json", `{"id": "user", "extends": "user.base@1.0.0", "theme": "dark"}`)
+	address := writeFile(t, dir, "schemas/address.json", `{"id": "address", "fields": [{"$ref": "user#email"}]}`)
+
+	index := NewDependencyIndex()
+	require.NoError(t, index.Build([]string{dir, filepath.Join(dir, "missing")}, func(string) bool { return true }))
+
+	node, ok := index.Node(user)
+	require.True(t, ok)
+	assert.Equal(t, DependencyKindSchema, node.Kind)
+	assert.Equal(t, []string{"schema:user.base", "theme:dark"}, node.Uses)
+
+	assert.Equal(t, []string{address, base, user}, index.Dependents(mixin))
+	assert.Equal(t, []string{address, user}, index.Dependents(theme))
+	assert.Empty(t, index.Dependents(address))
+
+	index.Remove(base)
+	assert.Equal(t, []string{mixin}, index.Affected([]string{mixin}))
+}
+
+func TestDependencyIndex_Components(t *testing.T) {
+	dir := t.TempDir()
+	button := writeFile(t, dir, "atoms/button.templ", `package atoms
+
+templ Button(props ButtonProps) {
+	<button>{ children... }</button>
+}
+
+templ IconButton(props ButtonProps) {
+	@Button(props) {
+		@Icon(props.Icon)
+	}
+}
+`)
+	helpers := writeFile(t, dir, "atoms/button_helpers.go", `package atoms
+
+type ButtonProps struct{ Icon string }
+
+func buttonClasses(classes []string) int { return len(classes) }
+`)
+	form := writeFile(t, dir, "molecules/form.templ", `package molecules
+
+templ Form() {
+	<form>@atoms.IconButton(atoms.ButtonProps{})</form>
+}
+`)
+	page := writeFile(t, dir, "pages/page.go", `package pages
+
+func Page() templ.Component { return molecules.Form() }
+`)
+	writeFile(t, dir, "atoms/button_templ.go", `package atoms`)
+
+	bi := NewBuildIntegration()
+	index := NewDependencyIndex()
+	require.NoError(t, index.Build([]string{dir}, bi.isIndexedFile))
+
+	node, ok := index.Node(button)
+	require.True(t, ok)
+	assert.Equal(t, []string{"component:Button", "component:IconButton"}, node.Defines)
+	assert.Equal(t, []string{"component:ButtonProps", "component:Icon"}, node.Uses)
+
+	node, ok = index.Node(helpers)
+	require.True(t, ok)
+	assert.Equal(t, []string{"component:ButtonProps", "component:buttonClasses"}, node.Defines)
+	assert.Empty(t, node.Uses, "builtins are not dependencies")
+
+	_, ok = index.Node(filepath.Join(dir, "atoms/button_templ.go"))
+	assert.False(t, ok, "generated templ code is not indexed")
+
+	assert.Equal(t, []string{form, page}, index.Dependents(button))
+	assert.Equal(t, []string{button, form, page}, index.Dependents(helpers))
+	assert.Equal(t, []string{page}, index.Dependents(form))
+}
+
+func TestDependencyIndex_Update(t *testing.T) {
+	dir := t.TempDir()
+	card := writeFile(t, dir, "card.templ", "templ Card() {\n\t<div></div>\n}\n")
+	list := writeFile(t, dir, "list.templ", "templ List() {\n\t@Card()\n}\n")
+
+	index := NewDependencyIndex()
+	require.NoError(t, index.Build([]string{dir}, func(string) bool { return true }))
+	assert.Equal(t, []string{list}, index.Dependents(card))
+
+	writeFile(t, dir, "list.templ", "templ List() {\n\t<ul></ul>\n}\n")
+	require.NoError(t, index.Update(list))
+	assert.Empty(t, index.Dependents(card))
+
+	broken := writeFile(t, dir, "broken.go", "package broken\n\nfunc {")
+	assert.Error(t, index.Update(broken))
+	node, ok := index.Node(broken)
+	require.True(t, ok, "unparsable files stay indexed")
+	assert.NotEmpty(t, node.Errors)
+}
@@ -0,0 +1,371 @@
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WatchEventType represents the type of watch event
+type WatchEventType string
+
+const (
+	WatchEventCycleStarted  WatchEventType = "cycle_started"
+	WatchEventResult        WatchEventType = "result"
+	WatchEventCycleFinished WatchEventType = "cycle_finished"
+	WatchEventError         WatchEventType = "error"
+)
+
+// WatchEvent is published for every revalidation cycle and result in
+// watch mode
+type WatchEvent struct {
+	Seq       uint64         `json:"seq"`
+	Type      WatchEventType `json:"type"`
+	Cycle     int            `json:"cycle,omitempty"`
+	Changed   []string       `json:"changed,omitempty"`
+	Affected  []string       `json:"affected,omitempty"`
+	Result    *WatchResult   `json:"result,omitempty"`
+	Passed    int            `json:"passed,omitempty"`
+	Failed    int            `json:"failed,omitempty"`
+	Duration  time.Duration  `json:"duration,omitempty"`
+	Error     string         `json:"error,omitempty"`
+	Timestamp time.Time      `json:"timestamp"`
+}
+
+// WatchResult is the outcome of one validation of one file
+type WatchResult struct {
+	Path       string        `json:"path"`
+	Validation string        `json:"validation"`
+	Passed     bool          `json:"passed"`
+	Error      string        `json:"error,omitempty"`
+	Dependency bool          `json:"dependency"` // revalidated because a dependency changed
+	Duration   time.Duration `json:"duration"`
+}
+
+// WatchStream fans watch events out to subscribers and the terminal
+type WatchStream struct {
+	subscribers map[int]chan WatchEvent
+	nextID      int
+	seq         uint64
+	output      io.Writer
+	verbose     bool
+	mutex       sync.Mutex
+}
+
+// NewWatchStream creates a stream that prints to stdout
+func NewWatchStream() *WatchStream {
+	return &WatchStream{
+		subscribers: make(map[int]chan WatchEvent),
+		output:      os.Stdout,
+	}
+}
+
+// SetOutput sets the terminal output; verbose also prints passing results
+func (ws *WatchStream) SetOutput(w io.Writer, verbose bool) {
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+	ws.output = w
+	ws.verbose = verbose
+}
+
+// Subscribe returns a channel of events and a function that unsubscribes.
+// Events are dropped for subscribers whose buffer is full.
+func (ws *WatchStream) Subscribe(buffer int) (<-chan WatchEvent, func()) {
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	id := ws.nextID
+	ws.nextID++
+	ch := make(chan WatchEvent, buffer)
+	ws.subscribers[id] = ch
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			ws.mutex.Lock()
+			defer ws.mutex.Unlock()
+			delete(ws.subscribers, id)
+			close(ch)
+		})
+	}
+}
+
+// Publish sends an event to all subscribers and prints it
+func (ws *WatchStream) Publish(event WatchEvent) {
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	ws.seq++
+	event.Seq = ws.seq
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	if ws.output != nil {
+		WriteWatchEvent(ws.output, event, ws.verbose)
+	}
+	for _, ch := range ws.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// WriteWatchEvent prints a watch event for the terminal. Passing results
+// are only printed when verbose.
+func WriteWatchEvent(w io.Writer, event WatchEvent, verbose bool) {
+	switch event.Type {
+	case WatchEventCycleStarted:
+		fmt.Fprintf(w, "[watch #%d] %d changed, revalidating %d file(s)\n", event.Cycle, len(event.Changed), len(event.Affected))
+	case WatchEventResult:
+		result := event.Result
+		if result == nil || (result.Passed && !verbose) {
+			return
+		}
+		via := ""
+		if result.Dependency {
+			via = " (dependency changed)"
+		}
+		if result.Passed {
+			fmt.Fprintf(w, "  ✓ %s %s%s %s\n", result.Validation, result.Path, via, result.Duration.Round(time.Millisecond))
+		} else {
+			fmt.Fprintf(w, "  ✗ %s %s%s: %s\n", result.Validation, result.Path, via, result.Error)
+		}
+	case WatchEventCycleFinished:
+		fmt.Fprintf(w, "[watch #%d] %d passed, %d failed in %s\n", event.Cycle, event.Passed, event.Failed, event.Duration.Round(time.Millisecond))
+	case WatchEventError:
+		fmt.Fprintf(w, "[watch] error: %s\n", event.Error)
+	}
+}
+
+// WatchSSEHandler streams watch events as server-sent events for dev-mode
+// browser overlays
+type WatchSSEHandler struct {
+	stream    *WatchStream
+	heartbeat time.Duration
+}
+
+// NewWatchSSEHandler creates an SSE handler. Zero heartbeat uses 25 seconds.
+func NewWatchSSEHandler(stream *WatchStream, heartbeat time.Duration) *WatchSSEHandler {
+	if heartbeat <= 0 {
+		heartbeat = 25 * time.Second
+	}
+	return &WatchSSEHandler{stream: stream, heartbeat: heartbeat}
+}
+
+// ServeHTTP implements http.Handler
+func (h *WatchSSEHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	events, unsubscribe := h.stream.Subscribe(64)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	io.WriteString(w, ": connected\n\n")
+	flusher.Flush()
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := WriteWatchSSEEvent(w, event); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// WriteWatchSSEEvent writes a watch event in server-sent event framing
+func WriteWatchSSEEvent(w io.Writer, event WatchEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\nid: %d\ndata: %s\n\n", event.Type, event.Seq, data)
+	return err
+}
+
+// watchSession runs revalidation cycles one at a time. Batches that arrive
+// while a cycle runs are merged into the next cycle.
+type watchSession struct {
+	bi      *BuildIntegration
+	changed []string
+	targets []string
+	cycle   int
+	wake    chan struct{}
+	ctx     context.Context
+	cancel  context.CancelFunc
+	done    chan struct{}
+	mutex   sync.Mutex
+}
+
+func newWatchSession(bi *BuildIntegration) *watchSession {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &watchSession{
+		bi:     bi,
+		wake:   make(chan struct{}, 1),
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+func (s *watchSession) enqueue(changed, targets []string) {
+	s.mutex.Lock()
+	s.changed = mergeSorted(s.changed, sortedCopy(changed))
+	s.targets = mergeSorted(s.targets, targets)
+	s.mutex.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *watchSession) stop() {
+	s.cancel()
+	<-s.done
+}
+
+func (s *watchSession) run() {
+	defer close(s.done)
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-s.wake:
+			s.mutex.Lock()
+			changed, targets := s.changed, s.targets
+			s.changed, s.targets = nil, nil
+			s.mutex.Unlock()
+			if len(changed) > 0 {
+				s.revalidate(changed, targets)
+			}
+		}
+	}
+}
+
+// revalidate runs the validations for every target file
+func (s *watchSession) revalidate(changed, targets []string) {
+	bi := s.bi
+	s.cycle++
+	start := time.Now()
+	bi.stream.Publish(WatchEvent{Type: WatchEventCycleStarted, Cycle: s.cycle, Changed: changed, Affected: targets})
+
+	ctx, cancel := context.WithTimeout(s.ctx, bi.config.ValidationTimeout)
+	defer cancel()
+
+	concurrency := max(bi.config.ConcurrentValidations, 1)
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	passed, failed := 0, 0
+
+	for _, path := range targets {
+		dependency := !slices.Contains(changed, path)
+		for _, validation := range bi.determineValidationsForFile(path) {
+			wg.Add(1)
+			semaphore <- struct{}{}
+			go func() {
+				defer wg.Done()
+				defer func() { <-semaphore }()
+
+				began := time.Now()
+				err := bi.runValidation(ctx, validation, path)
+				result := &WatchResult{
+					Path:       displayPath(path),
+					Validation: validation,
+					Passed:     err == nil,
+					Dependency: dependency,
+					Duration:   time.Since(began),
+				}
+				if err != nil {
+					result.Error = err.Error()
+				}
+
+				mutex.Lock()
+				if result.Passed {
+					passed++
+				} else {
+					failed++
+				}
+				mutex.Unlock()
+				bi.stream.Publish(WatchEvent{Type: WatchEventResult, Cycle: s.cycle, Result: result})
+			}()
+		}
+	}
+	wg.Wait()
+
+	bi.stream.Publish(WatchEvent{
+		Type:     WatchEventCycleFinished,
+		Cycle:    s.cycle,
+		Changed:  changed,
+		Passed:   passed,
+		Failed:   failed,
+		Duration: time.Since(start),
+	})
+}
+
+// isIndexedFile reports whether a file takes part in the dependency index.
+// Generated _templ.go files are skipped; their .templ sources declare the
+// same components.
+func (bi *BuildIntegration) isIndexedFile(path string) bool {
+	if bi.shouldIgnoreFile(path) {
+		return false
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", ".yaml", ".yml", ".templ":
+		return true
+	case ".go":
+		return !strings.HasSuffix(path, "_templ.go") && !strings.HasSuffix(path, "_test.go")
+	}
+	return false
+}
+
+// displayPath returns path relative to the working directory when possible
+func displayPath(path string) string {
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return rel
+		}
+	}
+	return path
+}
+
+func sortedCopy(values []string) []string {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	return slices.Compact(sorted)
+}
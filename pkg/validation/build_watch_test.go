@@ -0,0 +1,116 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileWatcher_RecordCoalesces(t *testing.T) {
+	tests := []struct {
+		name   string
+		events []FileEventType
+		want   []FileEventType
+	}{
+		{"writes collapse", []FileEventType{FileEventTypeModified, FileEventTypeModified}, []FileEventType{FileEventTypeModified}},
+		{"created stays created", []FileEventType{FileEventTypeCreated, FileEventTypeModified}, []FileEventType{FileEventTypeCreated}},
+		{"created then deleted cancels", []FileEventType{FileEventTypeCreated, FileEventTypeDeleted}, nil},
+		{"deleted then created modifies", []FileEventType{FileEventTypeDeleted, FileEventTypeCreated}, []FileEventType{FileEventTypeModified}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fw := NewFileWatcher(nil, []string{".templ"}, nil)
+			var got []FileEventType
+			fw.SetBatchCallback(func(events []FileEvent) {
+				for _, event := range events {
+					got = append(got, event.Type)
+				}
+			})
+			for _, eventType := range tt.events {
+				fw.record("button.templ", eventType)
+			}
+			assert.False(t, fw.record("button.css", FileEventTypeModified), "unwatched extension")
+			fw.flush()
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFileWatcher_Debounce(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "card.templ", "templ Card() {}\n")
+
+	fw := NewFileWatcher([]string{dir}, []string{".templ"}, nil)
+	fw.SetDebounce(150 * time.Millisecond)
+
+	var mutex sync.Mutex
+	var batches [][]FileEvent
+	fw.SetBatchCallback(func(events []FileEvent) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		batches = append(batches, events)
+	})
+	require.NoError(t, fw.Start())
+	defer fw.Stop()
+
+	for i := range 5 {
+		require.NoError(t, os.WriteFile(path, []byte("templ Card() { "+string(rune('a'+i))+" }\n"), 0o644))
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	require.Eventually(t, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return len(batches) > 0
+	}, 2*time.Second, 10*time.Millisecond)
+	time.Sleep(300 * time.Millisecond)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	require.Len(t, batches, 1, "rapid writes are delivered as one batch")
+	require.Len(t, batches[0], 1)
+	assert.Equal(t, path, batches[0][0].Path)
+	assert.Equal(t, FileEventTypeModified, batches[0][0].Type)
+}
+
+func TestBuildIntegration_WatchRevalidatesDependents(t *testing.T) {
+	dir := t.TempDir()
+	card := writeFile(t, dir, "card.templ", "templ Card() {\n\t<div></div>\n}\n")
+	list := writeFile(t, dir, "list.templ", "templ List() {\n\t@Card()\n}\n")
+	writeFile(t, dir, "other.templ", "templ Other() {\n\t<p></p>\n}\n")
+
+	bi := NewBuildIntegration()
+	bi.config.EnableFileWatching = true
+	bi.config.WatchDirectories = []string{dir}
+	bi.config.WatchDebounce = 20 * time.Millisecond
+	bi.SetWatchOutput(nil)
+
+	events, unsubscribe := bi.WatchStream().Subscribe(64)
+	defer unsubscribe()
+	require.NoError(t, bi.StartFileWatching())
+	defer bi.StopFileWatching()
+
+	require.NoError(t, os.WriteFile(card, []byte("templ Card() {\n\t<section></section>\n}\n"), 0o644))
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event := <-events:
+			if event.Type != WatchEventCycleStarted {
+				continue
+			}
+			assert.Equal(t, []string{card}, event.Changed)
+			assert.Equal(t, []string{card, list}, event.Affected)
+			assert.NotContains(t, event.Affected, filepath.Join(dir, "other.templ"))
+			return
+		case <-timeout:
+			t.Fatal("no revalidation cycle started")
+		}
+	}
+}
@@ -653,7 +653,7 @@ func (cv *ComponentValidator) validateVariants(instance *ComponentInstance, vari
 	}
 
 	// Determine which variant is being used (simplified logic)
-	for variantName, variantSchema := range variants {
+	for _, variantSchema := range variants {
 		isVariant := true
 
 		// Check if all required props for this variant are present
@@ -123,7 +123,7 @@ func NewValidationEngine(opts ValidationOptions) *ValidationEngine {
 	}
 
 	if opts.EnableMetrics {
-		engine.metrics = NewMetricsCollector()
+		engine.metrics = NewMetricsCollector(NewPerformanceValidator().config)
 	}
 	if opts.EnableA11y {
 		engine.a11y = NewAccessibilityValidator()
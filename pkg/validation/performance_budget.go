@@ -0,0 +1,607 @@
+package validation
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/a-h/templ"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/niiniyare/ruun/schema"
+)
+
+// RenderMeasurement is the measured cost of rendering one component or
+// schema: render time and allocations per render, and the size, node count
+// and Alpine/HTMX attribute weight of the produced HTML
+type RenderMeasurement struct {
+	Name        string        `json:"name"`
+	Kind        string        `json:"kind"` // component, schema
+	Iterations  int           `json:"iterations"`
+	TimePerOp   time.Duration `json:"timePerOp"`
+	P95         time.Duration `json:"p95"`
+	AllocsPerOp int64         `json:"allocsPerOp"`
+	BytesPerOp  int64         `json:"bytesPerOp"` // heap bytes allocated per render
+	HTMLBytes   int64         `json:"htmlBytes"`
+	GzipBytes   int64         `json:"gzipBytes"`
+	DOMNodes    int           `json:"domNodes"` // elements and non-blank text nodes
+	DOMDepth    int           `json:"domDepth"`
+
+	// Inline behaviour attributes. Weight is the serialized size of the
+	// attributes, i.e. what they add to every response.
+	AlpineAttributes int   `json:"alpineAttributes"`
+	AlpineBytes      int64 `json:"alpineBytes"`
+	HTMXAttributes   int   `json:"htmxAttributes"`
+	HTMXBytes        int64 `json:"htmxBytes"`
+}
+
+// Key identifies the measurement in a baseline, e.g. "component/Button"
+func (m RenderMeasurement) Key() string {
+	return firstNonEmpty(m.Kind, "component") + "/" + m.Name
+}
+
+// InteractiveBytes returns the combined Alpine and HTMX attribute weight
+func (m RenderMeasurement) InteractiveBytes() int64 {
+	return m.AlpineBytes + m.HTMXBytes
+}
+
+// RenderBenchmark renders components repeatedly and measures them
+type RenderBenchmark struct {
+	// Iterations is the number of measured renders
+	Iterations int
+	// Warmup renders run before measuring and are discarded
+	Warmup int
+	// MaxDuration stops measuring early for slow components
+	MaxDuration time.Duration
+}
+
+// NewRenderBenchmark creates a benchmark with default settings
+func NewRenderBenchmark() *RenderBenchmark {
+	return &RenderBenchmark{
+		Iterations:  200,
+		Warmup:      10,
+		MaxDuration: 2 * time.Second,
+	}
+}
+
+// MeasureComponent measures a templ component
+func (rb *RenderBenchmark) MeasureComponent(ctx context.Context, name string, component templ.Component) (*RenderMeasurement, error) {
+	return rb.Measure(ctx, name, "component", component.Render)
+}
+
+// MeasureSchema measures a schema rendered with renderer, normally the
+// renderer backed by the form organism
+func (rb *RenderBenchmark) MeasureSchema(ctx context.Context, renderer schema.Renderer, s *schema.Schema, data map[string]any) (*RenderMeasurement, error) {
+	return rb.Measure(ctx, s.ID, "schema", func(ctx context.Context, w io.Writer) error {
+		markup, err := renderer.Render(ctx, s, data)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, markup)
+		return err
+	})
+}
+
+// Measure runs render repeatedly. The output of the last render is
+// analysed for size, DOM nodes and attribute weight.
+func (rb *RenderBenchmark) Measure(ctx context.Context, name, kind string, render func(ctx context.Context, w io.Writer) error) (*RenderMeasurement, error) {
+	var buf bytes.Buffer
+	for range rb.Warmup {
+		buf.Reset()
+		if err := render(ctx, &buf); err != nil {
+			return nil, fmt.Errorf("render %s %s: %w", kind, name, err)
+		}
+	}
+
+	iterations := max(rb.Iterations, 1)
+	times := make([]time.Duration, 0, iterations)
+
+	runtime.GC()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	for range iterations {
+		buf.Reset()
+		began := time.Now()
+		if err := render(ctx, &buf); err != nil {
+			return nil, fmt.Errorf("render %s %s: %w", kind, name, err)
+		}
+		times = append(times, time.Since(began))
+		if rb.MaxDuration > 0 && time.Since(start) > rb.MaxDuration {
+			break
+		}
+	}
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	n := len(times)
+	measurement := &RenderMeasurement{
+		Name:        name,
+		Kind:        kind,
+		Iterations:  n,
+		TimePerOp:   elapsed / time.Duration(n),
+		AllocsPerOp: int64(after.Mallocs-before.Mallocs) / int64(n),
+		BytesPerOp:  int64(after.TotalAlloc-before.TotalAlloc) / int64(n),
+	}
+	slices.Sort(times)
+	measurement.P95 = times[min(n*95/100, n-1)]
+
+	if err := measurement.analyse(buf.Bytes()); err != nil {
+		return nil, fmt.Errorf("analyse %s %s: %w", kind, name, err)
+	}
+	return measurement, nil
+}
+
+// analyse records the size and structure of rendered markup
+func (m *RenderMeasurement) analyse(markup []byte) error {
+	m.HTMLBytes = int64(len(markup))
+
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	if _, err := gz.Write(markup); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	m.GzipBytes = int64(compressed.Len())
+
+	root, err := html.Parse(bytes.NewReader(markup))
+	if err != nil {
+		return err
+	}
+	isDocument := htmlElementPattern.Match(markup)
+
+	var walk func(n *html.Node, depth int)
+	walk = func(n *html.Node, depth int) {
+		switch n.Type {
+		case html.ElementNode:
+			// The parser adds html, head and body to fragments
+			if isDocument || (n.DataAtom != atom.Html && n.DataAtom != atom.Head && n.DataAtom != atom.Body) {
+				depth++
+				m.DOMNodes++
+				m.DOMDepth = max(m.DOMDepth, depth)
+			}
+			for _, a := range n.Attr {
+				switch name := attributeName(a); {
+				case isHTMXAttribute(name):
+					m.HTMXAttributes++
+					m.HTMXBytes += attributeWeight(a)
+				case isAlpineAttribute(name):
+					m.AlpineAttributes++
+					m.AlpineBytes += attributeWeight(a)
+				}
+			}
+		case html.TextNode:
+			if strings.TrimSpace(n.Data) != "" {
+				m.DOMNodes++
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c, depth)
+		}
+	}
+	walk(root, 0)
+	return nil
+}
+
+func attributeName(a html.Attribute) string {
+	if a.Namespace != "" {
+		return a.Namespace + ":" + a.Key
+	}
+	return a.Key
+}
+
+// attributeWeight is the serialized size of ` name="value"`
+func attributeWeight(a html.Attribute) int64 {
+	weight := int64(len(attributeName(a)) + 1)
+	if a.Val != "" {
+		weight += int64(len(html.EscapeString(a.Val)) + 3)
+	}
+	return weight
+}
+
+func isAlpineAttribute(name string) bool {
+	return strings.HasPrefix(name, "x-") || strings.HasPrefix(name, "@") || strings.HasPrefix(name, ":")
+}
+
+func isHTMXAttribute(name string) bool {
+	return strings.HasPrefix(name, "hx-") || strings.HasPrefix(name, "data-hx-")
+}
+
+// RenderBudget is the accepted cost of one component or schema. Zero
+// values are not checked.
+type RenderBudget struct {
+	TimePerOp        time.Duration `json:"timePerOp,omitempty"`
+	AllocsPerOp      int64         `json:"allocsPerOp,omitempty"`
+	HTMLBytes        int64         `json:"htmlBytes,omitempty"`
+	DOMNodes         int           `json:"domNodes,omitempty"`
+	InteractiveBytes int64         `json:"interactiveBytes,omitempty"`
+	// Tolerance overrides the baseline tolerance for this entry
+	Tolerance *float64 `json:"tolerance,omitempty"`
+}
+
+// BudgetFromMeasurement creates a budget equal to a measurement
+func BudgetFromMeasurement(m RenderMeasurement) RenderBudget {
+	return RenderBudget{
+		TimePerOp:        m.TimePerOp,
+		AllocsPerOp:      m.AllocsPerOp,
+		HTMLBytes:        m.HTMLBytes,
+		DOMNodes:         m.DOMNodes,
+		InteractiveBytes: m.InteractiveBytes(),
+	}
+}
+
+// PerformanceBaseline is the budget file checked into the repository.
+// Budgets are keyed by RenderMeasurement.Key.
+type PerformanceBaseline struct {
+	Version int `json:"version"`
+	// Tolerance is the allowed relative growth over a budget, e.g. 0.05
+	// fails a component that renders 6% more HTML than its budget
+	Tolerance float64 `json:"tolerance"`
+	// TimeTolerance applies to render time, which is noisier than the
+	// structural metrics. A negative value disables time checks, e.g. on
+	// shared CI runners.
+	TimeTolerance float64                 `json:"timeTolerance"`
+	Budgets       map[string]RenderBudget `json:"budgets"`
+	UpdatedAt     time.Time               `json:"updatedAt,omitempty"`
+}
+
+// NewPerformanceBaseline creates an empty baseline with default tolerances
+func NewPerformanceBaseline() *PerformanceBaseline {
+	return &PerformanceBaseline{
+		Version:       1,
+		Tolerance:     0.05,
+		TimeTolerance: 0.25,
+		Budgets:       make(map[string]RenderBudget),
+	}
+}
+
+// LoadPerformanceBaseline reads a baseline file. A missing file returns an
+// empty baseline.
+func LoadPerformanceBaseline(path string) (*PerformanceBaseline, error) {
+	baseline := NewPerformanceBaseline()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return baseline, nil
+		}
+		return nil, fmt.Errorf("failed to read performance baseline: %w", err)
+	}
+	if err := json.Unmarshal(data, baseline); err != nil {
+		return nil, fmt.Errorf("failed to parse performance baseline %s: %w", path, err)
+	}
+	if baseline.Budgets == nil {
+		baseline.Budgets = make(map[string]RenderBudget)
+	}
+	return baseline, nil
+}
+
+// Save writes the baseline as indented JSON
+func (pb *PerformanceBaseline) Save(path string) error {
+	data, err := json.MarshalIndent(pb, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode performance baseline: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create baseline directory: %w", err)
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Update sets the budgets of the measured targets to their measurements,
+// keeping per-entry tolerance overrides
+func (pb *PerformanceBaseline) Update(measurements []RenderMeasurement) {
+	for _, m := range measurements {
+		budget := BudgetFromMeasurement(m)
+		budget.Tolerance = pb.Budgets[m.Key()].Tolerance
+		pb.Budgets[m.Key()] = budget
+	}
+	pb.UpdatedAt = time.Now().UTC()
+}
+
+// BudgetRegression is one metric that exceeded its budget
+type BudgetRegression struct {
+	Metric  string  `json:"metric"`
+	Budget  float64 `json:"budget"`
+	Allowed float64 `json:"allowed"`
+	Actual  float64 `json:"actual"`
+	Change  float64 `json:"change"` // relative change against the budget
+}
+
+// String formats the regression, e.g. "htmlBytes 1200 > 1050 (+14.3%)"
+func (r BudgetRegression) String() string {
+	return fmt.Sprintf("%s %s > %s (%+.1f%%)", r.Metric, formatBudgetValue(r.Metric, r.Actual),
+		formatBudgetValue(r.Metric, r.Allowed), r.Change*100)
+}
+
+func formatBudgetValue(metric string, value float64) string {
+	if metric == "timePerOp" {
+		return time.Duration(value).String()
+	}
+	return fmt.Sprintf("%.0f", value)
+}
+
+// BudgetResult is the comparison of one measurement with its budget
+type BudgetResult struct {
+	Measurement RenderMeasurement  `json:"measurement"`
+	Budget      *RenderBudget      `json:"budget,omitempty"` // nil when the target has no budget yet
+	Regressions []BudgetRegression `json:"regressions,omitempty"`
+}
+
+// Passed reports whether the measurement is within budget
+func (r BudgetResult) Passed() bool {
+	return len(r.Regressions) == 0
+}
+
+// BudgetReport is the result of checking measurements against a baseline
+type BudgetReport struct {
+	Results []BudgetResult `json:"results"`
+	Missing []string       `json:"missing,omitempty"` // measured targets without a budget
+	Passed  bool           `json:"passed"`
+}
+
+// Check compares measurements with their budgets
+func (pb *PerformanceBaseline) Check(measurements []RenderMeasurement) *BudgetReport {
+	report := &BudgetReport{Passed: true}
+	for _, m := range measurements {
+		result := BudgetResult{Measurement: m}
+		budget, exists := pb.Budgets[m.Key()]
+		if !exists {
+			report.Missing = append(report.Missing, m.Key())
+			report.Results = append(report.Results, result)
+			continue
+		}
+		result.Budget = &budget
+
+		tolerance := pb.Tolerance
+		if budget.Tolerance != nil {
+			tolerance = *budget.Tolerance
+		}
+		check := func(metric string, limit, actual float64, tolerance float64) {
+			if limit <= 0 || tolerance < 0 {
+				return
+			}
+			allowed := limit * (1 + tolerance)
+			if actual > allowed {
+				result.Regressions = append(result.Regressions, BudgetRegression{
+					Metric:  metric,
+					Budget:  limit,
+					Allowed: allowed,
+					Actual:  actual,
+					Change:  actual/limit - 1,
+				})
+			}
+		}
+		check("timePerOp", float64(budget.TimePerOp), float64(m.TimePerOp), pb.TimeTolerance)
+		check("allocsPerOp", float64(budget.AllocsPerOp), float64(m.AllocsPerOp), tolerance)
+		check("htmlBytes", float64(budget.HTMLBytes), float64(m.HTMLBytes), tolerance)
+		check("domNodes", float64(budget.DOMNodes), float64(m.DOMNodes), tolerance)
+		check("interactiveBytes", float64(budget.InteractiveBytes), float64(m.InteractiveBytes()), tolerance)
+
+		if !result.Passed() {
+			report.Passed = false
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report
+}
+
+// Err returns an error listing every regression, or nil
+func (br *BudgetReport) Err() error {
+	if br.Passed {
+		return nil
+	}
+	var sb strings.Builder
+	sb.WriteString("performance budget exceeded:")
+	for _, result := range br.Results {
+		for _, regression := range result.Regressions {
+			fmt.Fprintf(&sb, "\n  %s: %s", result.Measurement.Key(), regression)
+		}
+	}
+	return fmt.Errorf("%s", sb.String())
+}
+
+// Write prints a table of measurements and their status
+func (br *BudgetReport) Write(w io.Writer) {
+	fmt.Fprintf(w, "%-40s %10s %8s %8s %6s %9s  %s\n", "target", "time/op", "allocs", "html", "nodes", "alpine+hx", "status")
+	for _, result := range br.Results {
+		m := result.Measurement
+		status := "ok"
+		switch {
+		case result.Budget == nil:
+			status = "new"
+		case !result.Passed():
+			status = "REGRESSED"
+		}
+		fmt.Fprintf(w, "%-40s %10s %8d %8d %6d %9d  %s\n", m.Key(), m.TimePerOp.Round(time.Microsecond/10),
+			m.AllocsPerOp, m.HTMLBytes, m.DOMNodes, m.InteractiveBytes(), status)
+		for _, regression := range result.Regressions {
+			fmt.Fprintf(w, "    %s\n", regression)
+		}
+	}
+}
+
+// ArtifactsFromBudgetReport converts a budget report for the SARIF and
+// JUnit reporters
+func ArtifactsFromBudgetReport(report *BudgetReport) []ReportArtifact {
+	artifacts := make([]ReportArtifact, 0, len(report.Results))
+	for _, result := range report.Results {
+		m := result.Measurement
+		artifact := ReportArtifact{
+			Name:     m.Key(),
+			Kind:     "performance",
+			Passed:   result.Passed(),
+			Duration: m.TimePerOp * time.Duration(m.Iterations),
+		}
+		for _, regression := range result.Regressions {
+			artifact.Findings = append(artifact.Findings, ReportFinding{
+				RuleID:     "performance_budget/" + regression.Metric,
+				RuleName:   "PerformanceBudget",
+				Category:   "performance",
+				Tags:       []string{"performance", m.Kind},
+				Severity:   ReportSeverityError,
+				Message:    fmt.Sprintf("%s exceeds its budget: %s", m.Key(), regression),
+				Path:       m.Name,
+				Suggestion: "Reduce the rendered output or update the baseline if the growth is intended",
+				Properties: map[string]any{"budget": regression.Budget, "actual": regression.Actual, "change": regression.Change},
+			})
+		}
+		if result.Budget == nil {
+			artifact.Findings = append(artifact.Findings, ReportFinding{
+				RuleID:   "performance_budget/missing",
+				Category: "performance",
+				Severity: ReportSeverityNote,
+				Message:  fmt.Sprintf("%s has no performance budget", m.Key()),
+				Path:     m.Name,
+			})
+		}
+		artifacts = append(artifacts, artifact)
+	}
+	return artifacts
+}
+
+// BudgetTB is the subset of testing.TB used by CheckRenderBudgets
+type BudgetTB interface {
+	Helper()
+	Errorf(format string, args ...any)
+	Fatalf(format string, args ...any)
+	Logf(format string, args ...any)
+}
+
+// CheckRenderBudgets checks measurements against the baseline at path from
+// a test. With UPDATE_PERF_BASELINE set the baseline is rewritten instead.
+// Targets without a budget fail the test; new components and schemas are
+// added to the baseline by running with UPDATE_PERF_BASELINE set.
+func CheckRenderBudgets(t BudgetTB, path string, measurements []RenderMeasurement) {
+	t.Helper()
+	baseline, err := LoadPerformanceBaseline(path)
+	if err != nil {
+		t.Fatalf("%v", err)
+		return
+	}
+
+	if os.Getenv("UPDATE_PERF_BASELINE") != "" {
+		baseline.Update(measurements)
+		if err := baseline.Save(path); err != nil {
+			t.Fatalf("failed to save performance baseline: %v", err)
+		}
+		return
+	}
+
+	report := baseline.Check(measurements)
+	var table strings.Builder
+	report.Write(&table)
+	t.Logf("render budgets:\n%s", table.String())
+
+	if err := report.Err(); err != nil {
+		t.Errorf("%v\nset UPDATE_PERF_BASELINE=1 to accept the new values", err)
+	}
+	if len(report.Missing) > 0 {
+		t.Errorf("no performance budget for %s in %s\nset UPDATE_PERF_BASELINE=1 to record them",
+			strings.Join(report.Missing, ", "), path)
+	}
+}
+
+// ValidateRenderMeasurements fills the render, bundle, memory and
+// complexity metrics from real measurements instead of estimates and
+// checks them against the validator thresholds
+func (pv *PerformanceValidator) ValidateRenderMeasurements(measurements []RenderMeasurement) *PerformanceMetrics {
+	metrics := &PerformanceMetrics{
+		MeetsThresholds: true,
+		Timestamp:       time.Now(),
+		RenderTime: &RenderTimeMetrics{
+			ComponentTimes: make(map[string]time.Duration),
+			ThresholdsMet:  true,
+		},
+		BundleSize: &BundleSizeMetrics{
+			ComponentSizes:  make(map[string]int64),
+			DependencySizes: make(map[string]int64),
+			ThresholdsMet:   true,
+		},
+		MemoryUsage: &MemoryMetrics{
+			ComponentMemory: make(map[string]int64),
+			ThresholdsMet:   true,
+		},
+		Complexity: &ComplexityMetrics{
+			ComponentComplexity: make(map[string]int),
+			ThresholdsMet:       true,
+		},
+	}
+
+	var total time.Duration
+	times := make([]time.Duration, 0, len(measurements))
+	for _, m := range measurements {
+		key := m.Key()
+		times = append(times, m.TimePerOp)
+		total += m.TimePerOp
+		metrics.RenderTime.ComponentTimes[key] = m.TimePerOp
+		metrics.RenderTime.InitialRender = max(metrics.RenderTime.InitialRender, m.P95)
+		if pv.thresholds.MaxRenderTime > 0 && m.TimePerOp > pv.thresholds.MaxRenderTime {
+			metrics.RenderTime.ThresholdsMet = false
+			metrics.RenderTime.Bottlenecks = append(metrics.RenderTime.Bottlenecks,
+				fmt.Sprintf("%s renders in %v, exceeding threshold %v", key, m.TimePerOp, pv.thresholds.MaxRenderTime))
+		}
+
+		metrics.BundleSize.ComponentSizes[key] = m.HTMLBytes
+		metrics.BundleSize.TotalSize += m.HTMLBytes
+		metrics.BundleSize.GzippedSize += m.GzipBytes
+		if pv.thresholds.MaxPayloadSize > 0 && m.HTMLBytes > pv.thresholds.MaxPayloadSize {
+			metrics.BundleSize.ThresholdsMet = false
+			metrics.BundleSize.Recommendations = append(metrics.BundleSize.Recommendations,
+				fmt.Sprintf("%s renders %d bytes, exceeding payload threshold %d bytes", key, m.HTMLBytes, pv.thresholds.MaxPayloadSize))
+		}
+		if m.HTMLBytes > 0 && float64(m.InteractiveBytes())/float64(m.HTMLBytes) > 0.5 {
+			metrics.BundleSize.Recommendations = append(metrics.BundleSize.Recommendations,
+				fmt.Sprintf("%s: %d of %d bytes are inline Alpine/HTMX attributes; move logic into Alpine.data components",
+					key, m.InteractiveBytes(), m.HTMLBytes))
+		}
+
+		metrics.MemoryUsage.ComponentMemory[key] = m.BytesPerOp
+		metrics.MemoryUsage.ObjectCount += m.AllocsPerOp
+
+		metrics.Complexity.ComponentComplexity[key] = m.DOMNodes
+		metrics.Complexity.DependencyDepth = max(metrics.Complexity.DependencyDepth, m.DOMDepth)
+		if pv.thresholds.MaxComplexity > 0 && m.DOMDepth > pv.thresholds.MaxComplexity {
+			metrics.Complexity.ThresholdsMet = false
+			metrics.Complexity.Suggestions = append(metrics.Complexity.Suggestions, ComplexitySuggestion{
+				Component:  key,
+				Issue:      fmt.Sprintf("DOM depth %d exceeds %d", m.DOMDepth, pv.thresholds.MaxComplexity),
+				Suggestion: "Flatten wrapper elements",
+				Impact:     "medium",
+			})
+		}
+	}
+
+	if len(times) > 0 {
+		metrics.RenderTime.AverageRender = total / time.Duration(len(times))
+		metrics.RenderTime.P95Render = pv.calculatePercentile(times, 95)
+		metrics.RenderTime.P99Render = pv.calculatePercentile(times, 99)
+		if metrics.RenderTime.AverageRender > 0 {
+			metrics.RenderTime.FrameRate = math.Min(float64(time.Second)/float64(metrics.RenderTime.AverageRender), 1000)
+		}
+	}
+	if pv.thresholds.MaxBundleSize > 0 && metrics.BundleSize.TotalSize > pv.thresholds.MaxBundleSize {
+		metrics.BundleSize.ThresholdsMet = false
+		metrics.BundleSize.Recommendations = append(metrics.BundleSize.Recommendations,
+			fmt.Sprintf("Total rendered size %d bytes exceeds threshold %d bytes", metrics.BundleSize.TotalSize, pv.thresholds.MaxBundleSize))
+	}
+
+	metrics.MeetsThresholds = metrics.RenderTime.ThresholdsMet && metrics.BundleSize.ThresholdsMet &&
+		metrics.MemoryUsage.ThresholdsMet && metrics.Complexity.ThresholdsMet
+	metrics.Score = pv.calculatePerformanceScore(metrics)
+	metrics.Grade = pv.calculatePerformanceGrade(metrics.Score)
+	pv.metrics.AddSample(*metrics)
+	return metrics
+}
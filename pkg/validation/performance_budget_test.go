@@ -0,0 +1,54 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeBudgetTB struct {
+	errors []string
+	fatals []string
+}
+
+func (f *fakeBudgetTB) Helper()                           {}
+func (f *fakeBudgetTB) Logf(format string, args ...any)   {}
+func (f *fakeBudgetTB) Errorf(format string, args ...any) { f.errors = append(f.errors, format) }
+func (f *fakeBudgetTB) Fatalf(format string, args ...any) { f.fatals = append(f.fatals, format) }
+
+func TestCheckRenderBudgets(t *testing.T) {
+	t.Setenv("UPDATE_PERF_BASELINE", "")
+	path := t.TempDir() + "/budgets.json"
+	measurement := RenderMeasurement{Name: "Card", Kind: "component", HTMLBytes: 1000, DOMNodes: 10}
+
+	t.Run("missing budget fails", func(t *testing.T) {
+		tb := &fakeBudgetTB{}
+		CheckRenderBudgets(tb, path, []RenderMeasurement{measurement})
+		assert.Len(t, tb.errors, 1)
+		assert.NoFileExists(t, path, "missing budgets are not recorded implicitly")
+	})
+
+	t.Run("update records the baseline", func(t *testing.T) {
+		t.Setenv("UPDATE_PERF_BASELINE", "1")
+		tb := &fakeBudgetTB{}
+		CheckRenderBudgets(tb, path, []RenderMeasurement{measurement})
+		assert.Empty(t, tb.errors)
+		assert.FileExists(t, path)
+	})
+
+	t.Run("within tolerance passes", func(t *testing.T) {
+		tb := &fakeBudgetTB{}
+		within := measurement
+		within.HTMLBytes = 1040
+		CheckRenderBudgets(tb, path, []RenderMeasurement{within})
+		assert.Empty(t, tb.errors)
+	})
+
+	t.Run("regression fails", func(t *testing.T) {
+		tb := &fakeBudgetTB{}
+		grown := measurement
+		grown.HTMLBytes = 1200
+		CheckRenderBudgets(tb, path, []RenderMeasurement{grown})
+		assert.Len(t, tb.errors, 1)
+	})
+}
@@ -0,0 +1,134 @@
+//go:build !race
+
+package validation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/niiniyare/ruun/pkg/condition"
+	"github.com/niiniyare/ruun/schema"
+	"github.com/niiniyare/ruun/views/components"
+	"github.com/niiniyare/ruun/views/components/atoms"
+	"github.com/niiniyare/ruun/views/components/molecules"
+	"github.com/niiniyare/ruun/views/components/organisms"
+)
+
+// renderBudgetBaseline is the committed baseline of the render budget
+// tests; run them with UPDATE_PERF_BASELINE=1 to accept new values. The
+// race detector inflates allocation counts, so the file is excluded from
+// race builds.
+const renderBudgetBaseline = "testdata/render_budgets.json"
+
+func budgetComponents() map[string]templ.Component {
+	return map[string]templ.Component{
+		"Button": atoms.Button(atoms.ButtonProps{Text: "Save", Type: "submit", Variant: components.ButtonPrimary}),
+		"Badge":  atoms.Badge(atoms.BadgeProps{Text: "New", Variant: components.BadgeSecondary}),
+		"FormField": molecules.FormField(molecules.FormFieldProps{
+			ID:       "country",
+			Name:     "country",
+			Type:     molecules.FormFieldSelect,
+			Label:    "Country",
+			Required: true,
+			Options: []molecules.FormFieldOption{
+				{Value: "ke", Label: "Kenya"},
+				{Value: "so", Label: "Somalia", Selected: true},
+			},
+		}),
+		"Tabs": organisms.Tabs(organisms.TabsProps{
+			ID: "tabs",
+			Items: []organisms.TabItem{
+				{ID: "overview", Label: "Overview"},
+				{ID: "activity", Label: "Activity", Count: 4},
+			},
+		}),
+		"DataTable": organisms.DataTable(organisms.DataTableProps{
+			ID: "users",
+			Columns: []organisms.DataTableColumn{
+				{Key: "name", Title: "Name", Type: organisms.ColumnTypeText, Visible: true},
+				{Key: "email", Title: "Email", Type: organisms.ColumnTypeText, Visible: true},
+			},
+			Rows: []organisms.DataTableRow{
+				{ID: "1", Data: map[string]any{"name": "Ada", "email": "ada@example.com"}},
+				{ID: "2", Data: map[string]any{"name": "Grace", "email": "grace@example.com"}},
+			},
+		}),
+	}
+}
+
+func budgetSchemas() []*schema.Schema {
+	return []*schema.Schema{
+		{
+			ID:    "login",
+			Type:  schema.TypeForm,
+			Title: "Sign in",
+			Fields: []schema.Field{
+				{Name: "email", Type: schema.FieldEmail, Label: "Email", Required: true},
+				{Name: "password", Type: schema.FieldPassword, Label: "Password", Required: true},
+				{Name: "remember", Type: schema.FieldCheckbox, Label: "Remember me"},
+			},
+			Actions: []schema.Action{{ID: "submit", Type: schema.ActionSubmit, Text: "Sign in"}},
+		},
+		{
+			ID:    "customer",
+			Type:  schema.TypeForm,
+			Title: "Customer",
+			Fields: []schema.Field{
+				{Name: "name", Type: schema.FieldText, Label: "Name", Required: true},
+				{Name: "type", Type: schema.FieldSelect, Label: "Type", Options: []schema.FieldOption{
+					{Value: "person", Label: "Person"},
+					{Value: "company", Label: "Company"},
+				}},
+				{
+					Name: "company", Type: schema.FieldText, Label: "Company",
+					Conditional: &schema.Conditional{Show: &condition.ConditionGroup{
+						ID:          "company_only",
+						Conjunction: condition.ConjunctionAnd,
+						Children: []any{&condition.ConditionRule{
+							ID:    "type_is_company",
+							Left:  condition.Expression{Type: condition.ValueTypeField, Field: "type"},
+							Op:    condition.OpEqual,
+							Right: "company",
+						}},
+					}},
+				},
+				{Name: "notes", Type: schema.FieldTextarea, Label: "Notes"},
+			},
+			Actions: []schema.Action{
+				{ID: "save", Type: schema.ActionSubmit, Text: "Save", Variant: "primary"},
+				{ID: "cancel", Type: schema.ActionButton, Text: "Cancel"},
+			},
+		},
+	}
+}
+
+func TestRenderBudgets(t *testing.T) {
+	if testing.Short() {
+		t.Skip("render budgets are measured in full test runs")
+	}
+
+	ctx := context.Background()
+	benchmark := NewRenderBenchmark()
+	var measurements []RenderMeasurement
+
+	for name, component := range budgetComponents() {
+		measurement, err := benchmark.MeasureComponent(ctx, name, component)
+		require.NoError(t, err)
+		assert.Positive(t, measurement.HTMLBytes, name)
+		measurements = append(measurements, *measurement)
+	}
+
+	renderer := organisms.NewSchemaRenderer()
+	for _, s := range budgetSchemas() {
+		measurement, err := benchmark.MeasureSchema(ctx, renderer, s, map[string]any{"type": "person"})
+		require.NoError(t, err)
+		assert.Positive(t, measurement.DOMNodes, s.ID)
+		measurements = append(measurements, *measurement)
+	}
+
+	CheckRenderBudgets(t, renderBudgetBaseline, measurements)
+}
@@ -0,0 +1,353 @@
+package validation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ReportArtifact is one validated artifact (schema, theme, component or
+// rendered page) and its findings, the common input of the SARIF and JUnit
+// reporters
+type ReportArtifact struct {
+	Name     string          `json:"name"`
+	Kind     string          `json:"kind"` // schema, theme, accessibility, component, ...
+	Location *SourceLocation `json:"location,omitempty"`
+	Passed   bool            `json:"passed"`
+	Duration time.Duration   `json:"duration,omitempty"`
+	Findings []ReportFinding `json:"findings,omitempty"`
+}
+
+// ReportSeverity is the severity of a finding
+type ReportSeverity string
+
+const (
+	ReportSeverityError   ReportSeverity = "error"
+	ReportSeverityWarning ReportSeverity = "warning"
+	ReportSeverityNote    ReportSeverity = "note"
+)
+
+// ReportFinding is a single error, warning or suggestion
+type ReportFinding struct {
+	RuleID      string          `json:"ruleId"`
+	RuleName    string          `json:"ruleName,omitempty"`
+	Description string          `json:"description,omitempty"`
+	HelpURI     string          `json:"helpUri,omitempty"`
+	Category    string          `json:"category,omitempty"`
+	Tags        []string        `json:"tags,omitempty"`
+	Severity    ReportSeverity  `json:"severity"`
+	Message     string          `json:"message"`
+	Path        string          `json:"path,omitempty"` // logical location, e.g. a JSON path or element path
+	Location    *SourceLocation `json:"location,omitempty"`
+	Suggestion  string          `json:"suggestion,omitempty"`
+	Properties  map[string]any  `json:"properties,omitempty"`
+}
+
+// Failures returns the error findings
+func (a ReportArtifact) Failures() []ReportFinding {
+	return slices.DeleteFunc(slices.Clone(a.Findings), func(f ReportFinding) bool {
+		return f.Severity != ReportSeverityError
+	})
+}
+
+// severityForLevel maps a validation level to a finding severity
+func severityForLevel(level ValidationLevel) ReportSeverity {
+	if level == ValidationLevelWarn {
+		return ReportSeverityWarning
+	}
+	return ReportSeverityError
+}
+
+// severityForImpact maps an accessibility impact to a finding severity
+func severityForImpact(impact A11yImpact) ReportSeverity {
+	switch impact {
+	case A11yImpactCritical, A11yImpactSerious:
+		return ReportSeverityError
+	case A11yImpactModerate:
+		return ReportSeverityWarning
+	default:
+		return ReportSeverityNote
+	}
+}
+
+// ArtifactsFromComprehensive converts a validation suite result into one
+// artifact per validator result; nested accessibility and theme results
+// become separate artifacts
+func ArtifactsFromComprehensive(result *ComprehensiveValidationResult) []ReportArtifact {
+	if result == nil {
+		return nil
+	}
+	names := make([]string, 0, len(result.Results))
+	for name := range result.Results {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	artifacts := make([]ReportArtifact, 0, len(names))
+	for _, name := range names {
+		validation := result.Results[name]
+		if validation == nil {
+			continue
+		}
+		artifacts = append(artifacts, ArtifactFromValidation(name, name, validation))
+		if validation.Accessibility != nil {
+			artifacts = append(artifacts, ArtifactFromAccessibility(name+"/accessibility", validation.Accessibility))
+		}
+		if validation.Theme != nil {
+			artifacts = append(artifacts, ArtifactFromTheme(name+"/theme", validation.Theme))
+		}
+	}
+	return artifacts
+}
+
+// ArtifactFromValidation converts a generic validation result
+func ArtifactFromValidation(name, kind string, result *ValidationResult) ReportArtifact {
+	artifact := ReportArtifact{Name: name, Kind: kind, Passed: result.Valid}
+	for _, err := range result.Errors {
+		artifact.Findings = append(artifact.Findings, ReportFinding{
+			RuleID:     ruleIDOrDefault(err.Code, kind),
+			Category:   err.Source,
+			Severity:   severityForLevel(err.Level),
+			Message:    err.Message,
+			Path:       firstNonEmpty(err.Field, err.Component),
+			Location:   err.Location,
+			Suggestion: err.Suggestion,
+			Properties: err.Details,
+		})
+	}
+	for _, warn := range result.Warnings {
+		artifact.Findings = append(artifact.Findings, ReportFinding{
+			RuleID:     ruleIDOrDefault(warn.Code, kind),
+			Category:   warn.Source,
+			Severity:   ReportSeverityWarning,
+			Message:    warn.Message,
+			Path:       firstNonEmpty(warn.Field, warn.Component),
+			Location:   warn.Location,
+			Properties: warn.Details,
+		})
+	}
+	return artifact
+}
+
+// ArtifactFromSchema converts a schema validation result
+func ArtifactFromSchema(name string, result *SchemaValidationResult) ReportArtifact {
+	artifact := ReportArtifact{Name: name, Kind: "schema", Passed: result.Valid}
+	for _, err := range result.Errors {
+		artifact.Findings = append(artifact.Findings, ReportFinding{
+			RuleID:     ruleIDOrDefault(firstNonEmpty(err.Rule, err.Code), "schema"),
+			RuleName:   err.Code,
+			Category:   string(err.Category),
+			Tags:       []string{"schema", string(err.Category)},
+			Severity:   severityForLevel(err.Level),
+			Message:    err.Message,
+			Path:       err.Path,
+			Location:   err.Location,
+			Suggestion: err.Suggestion,
+			Properties: schemaErrorProperties(err),
+		})
+	}
+	for _, warn := range result.Warnings {
+		artifact.Findings = append(artifact.Findings, ReportFinding{
+			RuleID:     ruleIDOrDefault(firstNonEmpty(warn.Rule, warn.Code), "schema"),
+			RuleName:   warn.Code,
+			Category:   string(warn.Category),
+			Tags:       []string{"schema", string(warn.Category)},
+			Severity:   ReportSeverityWarning,
+			Message:    warn.Message,
+			Path:       warn.Path,
+			Location:   warn.Location,
+			Suggestion: warn.Suggestion,
+			Properties: warn.Context,
+		})
+	}
+	for _, suggestion := range result.Suggestions {
+		artifact.Findings = append(artifact.Findings, ReportFinding{
+			RuleID:     ruleIDOrDefault(firstNonEmpty(suggestion.Rule, suggestion.Code), "schema"),
+			RuleName:   suggestion.Code,
+			Category:   string(suggestion.Category),
+			Tags:       []string{"schema", string(suggestion.Category)},
+			Severity:   ReportSeverityNote,
+			Message:    suggestion.Message,
+			Path:       suggestion.Path,
+			Suggestion: suggestion.Suggestion,
+			Properties: suggestion.Details,
+		})
+	}
+	if result.Performance != nil {
+		artifact.Duration = result.Performance.Duration
+	}
+	return artifact
+}
+
+func schemaErrorProperties(err SchemaError) map[string]any {
+	properties := make(map[string]any, len(err.Context)+2)
+	for key, value := range err.Context {
+		properties[key] = value
+	}
+	if err.Expected != nil {
+		properties["expected"] = err.Expected
+	}
+	if err.Actual != nil {
+		properties["actual"] = err.Actual
+	}
+	if len(properties) == 0 {
+		return nil
+	}
+	return properties
+}
+
+// ArtifactFromTheme converts a theme validation result
+func ArtifactFromTheme(name string, result *ThemeValidationResult) ReportArtifact {
+	artifact := ReportArtifact{Name: name, Kind: "theme", Passed: result.Valid}
+	for _, violation := range result.Violations {
+		properties := map[string]any{}
+		for key, value := range violation.Context {
+			properties[key] = value
+		}
+		if violation.Value != "" {
+			properties["value"] = violation.Value
+		}
+		if violation.Expected != "" {
+			properties["expected"] = violation.Expected
+		}
+		artifact.Findings = append(artifact.Findings, ReportFinding{
+			RuleID:     ruleIDOrDefault(violation.Code, "theme"),
+			Category:   string(violation.Category),
+			Tags:       []string{"theme", string(violation.Category)},
+			Severity:   severityForLevel(violation.Severity),
+			Message:    violation.Message,
+			Path:       violation.Token,
+			Location:   violation.Location,
+			Suggestion: violation.Fix,
+			Properties: properties,
+		})
+	}
+	for _, warn := range result.Warnings {
+		artifact.Findings = append(artifact.Findings, ReportFinding{
+			RuleID:     ruleIDOrDefault(warn.Code, "theme"),
+			Category:   string(warn.Category),
+			Tags:       []string{"theme", string(warn.Category)},
+			Severity:   ReportSeverityWarning,
+			Message:    warn.Message,
+			Path:       warn.Token,
+			Location:   warn.Location,
+			Suggestion: warn.Fix,
+			Properties: warn.Context,
+		})
+	}
+	return artifact
+}
+
+// ArtifactFromAccessibility converts an accessibility result. Rule metadata
+// carries the WCAG criterion, level and documentation link.
+func ArtifactFromAccessibility(name string, result *AccessibilityResult) ReportArtifact {
+	artifact := ReportArtifact{Name: name, Kind: "accessibility", Passed: result.Compliant}
+	for _, violation := range result.Violations {
+		finding := ReportFinding{
+			RuleID:     ruleIDOrDefault(violation.Code, "accessibility"),
+			Category:   string(violation.Category),
+			Tags:       a11yTags(violation.Standard, violation.Level, violation.Category),
+			Severity:   severityForImpact(violation.Impact),
+			Message:    violation.Message,
+			Path:       locationPath(violation.Location),
+			Location:   violation.Location,
+			Suggestion: violation.Fix,
+			Properties: map[string]any{"impact": violation.Impact, "element": violation.Element},
+		}
+		if len(violation.Resources) > 0 {
+			finding.HelpURI = violation.Resources[0].URL
+			finding.Description = violation.Resources[0].Title
+		}
+		for key, value := range violation.Context {
+			finding.Properties[key] = value
+		}
+		artifact.Findings = append(artifact.Findings, finding)
+	}
+	for _, warn := range result.Warnings {
+		artifact.Findings = append(artifact.Findings, ReportFinding{
+			RuleID:     ruleIDOrDefault(warn.Code, "accessibility"),
+			Category:   string(warn.Category),
+			Tags:       a11yTags("", warn.Level, warn.Category),
+			Severity:   ReportSeverityWarning,
+			Message:    warn.Message,
+			Path:       locationPath(warn.Location),
+			Location:   warn.Location,
+			Suggestion: warn.Fix,
+			Properties: map[string]any{"element": warn.Element},
+		})
+	}
+	for _, suggestion := range result.Suggestions {
+		artifact.Findings = append(artifact.Findings, ReportFinding{
+			RuleID:     ruleIDOrDefault(suggestion.Code, "accessibility"),
+			Category:   string(suggestion.Category),
+			Tags:       a11yTags("", "", suggestion.Category),
+			Severity:   ReportSeverityNote,
+			Message:    suggestion.Message,
+			Suggestion: suggestion.Fix,
+			Properties: map[string]any{"element": suggestion.Element, "priority": suggestion.Priority},
+		})
+	}
+	return artifact
+}
+
+// ArtifactFromBuildResult converts a build stage or command result
+func ArtifactFromBuildResult(result BuildResult) ReportArtifact {
+	name := strings.Join(nonEmpty(result.Pipeline, result.Stage, result.Command), "/")
+	artifact := ReportArtifact{Kind: "build"}
+	if result.Validation != nil {
+		artifact = ArtifactFromValidation(name, "build", result.Validation)
+	}
+	artifact.Name = name
+	artifact.Duration = result.Duration
+	artifact.Passed = result.Status != BuildStatusFailed
+	if result.Status == BuildStatusFailed && len(artifact.Failures()) == 0 {
+		artifact.Findings = append(artifact.Findings, ReportFinding{
+			RuleID:   "build_failed",
+			Severity: ReportSeverityError,
+			Message:  firstNonEmpty(result.Error, fmt.Sprintf("exit code %d", result.ExitCode)),
+		})
+	}
+	return artifact
+}
+
+func a11yTags(standard A11yStandard, level A11yLevel, category A11yCategory) []string {
+	tags := []string{"accessibility"}
+	if standard != "" {
+		tags = append(tags, strings.ToLower(string(standard)))
+	}
+	if level != "" {
+		tags = append(tags, "wcag-"+strings.ToLower(string(level)))
+	}
+	if category != "" {
+		tags = append(tags, string(category))
+	}
+	return tags
+}
+
+func locationPath(location *SourceLocation) string {
+	if location == nil {
+		return ""
+	}
+	return location.Path
+}
+
+func ruleIDOrDefault(id, kind string) string {
+	if id == "" {
+		return kind
+	}
+	return id
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func nonEmpty(values ...string) []string {
+	return slices.DeleteFunc(values, func(v string) bool { return v == "" })
+}
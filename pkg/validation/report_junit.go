@@ -0,0 +1,189 @@
+package validation
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// JUnitTestSuites is the root of a JUnit XML report
+type JUnitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr,omitempty"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []JUnitTestSuite `xml:"testsuite"`
+}
+
+// JUnitTestSuite groups the test cases of one artifact kind
+type JUnitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	Cases     []JUnitTestCase `xml:"testcase"`
+}
+
+// JUnitTestCase is one validated artifact
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// JUnitFailure lists the errors that failed an artifact
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// JUnitReporter converts report artifacts into JUnit XML with one test case
+// per artifact and one test suite per artifact kind. Errors fail the test
+// case; warnings and notes are written to system-out.
+type JUnitReporter struct {
+	Name      string
+	Timestamp time.Time
+}
+
+// NewJUnitReporter creates a JUnit reporter
+func NewJUnitReporter(name string) *JUnitReporter {
+	return &JUnitReporter{Name: name, Timestamp: time.Now()}
+}
+
+// Build converts artifacts into JUnit test suites
+func (jr *JUnitReporter) Build(artifacts []ReportArtifact) *JUnitTestSuites {
+	root := &JUnitTestSuites{Name: jr.Name}
+	suiteIndex := make(map[string]int)
+	durations := make(map[string]time.Duration)
+	var total time.Duration
+
+	for _, artifact := range artifacts {
+		kind := firstNonEmpty(artifact.Kind, "validation")
+		index, exists := suiteIndex[kind]
+		if !exists {
+			index = len(root.Suites)
+			suiteIndex[kind] = index
+			suite := JUnitTestSuite{Name: jr.suiteName(kind)}
+			if !jr.Timestamp.IsZero() {
+				suite.Timestamp = jr.Timestamp.UTC().Format("2006-01-02T15:04:05")
+			}
+			root.Suites = append(root.Suites, suite)
+		}
+		suite := &root.Suites[index]
+
+		testCase := JUnitTestCase{
+			Name:      artifact.Name,
+			ClassName: jr.suiteName(kind),
+			Time:      junitSeconds(artifact.Duration),
+		}
+
+		failures := artifact.Failures()
+		if len(failures) == 0 && !artifact.Passed {
+			failures = []ReportFinding{{RuleID: kind, Message: fmt.Sprintf("%s validation failed", kind)}}
+		}
+		if len(failures) > 0 {
+			message := failures[0].Message
+			if len(failures) > 1 {
+				message = fmt.Sprintf("%s (and %d more)", message, len(failures)-1)
+			}
+			testCase.Failure = &JUnitFailure{
+				Message: message,
+				Type:    failures[0].RuleID,
+				Text:    formatJUnitFindings(failures),
+			}
+			suite.Failures++
+			root.Failures++
+		}
+
+		var notes []ReportFinding
+		for _, finding := range artifact.Findings {
+			if finding.Severity != ReportSeverityError {
+				notes = append(notes, finding)
+			}
+		}
+		testCase.SystemOut = formatJUnitFindings(notes)
+
+		suite.Cases = append(suite.Cases, testCase)
+		suite.Tests++
+		root.Tests++
+		durations[kind] += artifact.Duration
+		total += artifact.Duration
+	}
+
+	for kind, index := range suiteIndex {
+		root.Suites[index].Time = junitSeconds(durations[kind])
+	}
+	root.Time = junitSeconds(total)
+	return root
+}
+
+// Write writes artifacts as JUnit XML
+func (jr *JUnitReporter) Write(w io.Writer, artifacts []ReportArtifact) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(jr.Build(artifacts)); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func (jr *JUnitReporter) suiteName(kind string) string {
+	if jr.Name == "" {
+		return kind
+	}
+	return jr.Name + "." + kind
+}
+
+// formatJUnitFindings lists findings one per line with their location
+func formatJUnitFindings(findings []ReportFinding) string {
+	var sb strings.Builder
+	for _, finding := range findings {
+		fmt.Fprintf(&sb, "[%s] %s: %s", finding.Severity, finding.RuleID, finding.Message)
+		if location := formatSourceLocation(finding.Location); location != "" {
+			fmt.Fprintf(&sb, " (%s)", location)
+		} else if finding.Path != "" {
+			fmt.Fprintf(&sb, " (%s)", finding.Path)
+		}
+		if finding.Suggestion != "" {
+			fmt.Fprintf(&sb, "\n  fix: %s", finding.Suggestion)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// formatSourceLocation formats a location as file:line:column path
+func formatSourceLocation(location *SourceLocation) string {
+	if location == nil || location.File == "" {
+		return ""
+	}
+	formatted := location.File
+	if location.Line > 0 {
+		formatted += fmt.Sprintf(":%d", location.Line)
+		if location.Column > 0 {
+			formatted += fmt.Sprintf(":%d", location.Column)
+		}
+	}
+	if location.Path != "" {
+		formatted += " " + location.Path
+	}
+	return formatted
+}
+
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,271 @@
+package validation
+
+import (
+	"encoding/json"
+	"io"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// SARIF 2.1.0 constants
+const (
+	SARIFVersion = "2.1.0"
+	SARIFSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// SARIFLog is the root of a SARIF 2.1.0 document
+type SARIFLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+// SARIFRun is a single run of the validation tool
+type SARIFRun struct {
+	Tool        SARIFTool         `json:"tool"`
+	Artifacts   []SARIFArtifact   `json:"artifacts,omitempty"`
+	Results     []SARIFResult     `json:"results"`
+	Invocations []SARIFInvocation `json:"invocations,omitempty"`
+}
+
+// SARIFTool describes the tool that produced the results
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+// SARIFDriver holds tool identity and rule metadata
+type SARIFDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []SARIFRule `json:"rules,omitempty"`
+}
+
+// SARIFRule is a reporting descriptor for one rule
+type SARIFRule struct {
+	ID                   string           `json:"id"`
+	Name                 string           `json:"name,omitempty"`
+	ShortDescription     *SARIFMessage    `json:"shortDescription,omitempty"`
+	FullDescription      *SARIFMessage    `json:"fullDescription,omitempty"`
+	Help                 *SARIFMessage    `json:"help,omitempty"`
+	HelpURI              string           `json:"helpUri,omitempty"`
+	DefaultConfiguration *SARIFRuleConfig `json:"defaultConfiguration,omitempty"`
+	Properties           map[string]any   `json:"properties,omitempty"`
+}
+
+// SARIFRuleConfig is the default configuration of a rule
+type SARIFRuleConfig struct {
+	Level string `json:"level"`
+}
+
+// SARIFMessage is a plain text message
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+// SARIFArtifact describes a validated file or logical artifact
+type SARIFArtifact struct {
+	Location SARIFArtifactLocation `json:"location"`
+	Roles    []string              `json:"roles,omitempty"`
+}
+
+// SARIFResult is one finding
+type SARIFResult struct {
+	RuleID     string          `json:"ruleId"`
+	RuleIndex  int             `json:"ruleIndex"`
+	Level      string          `json:"level"`
+	Kind       string          `json:"kind,omitempty"`
+	Message    SARIFMessage    `json:"message"`
+	Locations  []SARIFLocation `json:"locations,omitempty"`
+	Properties map[string]any  `json:"properties,omitempty"`
+}
+
+// SARIFLocation locates a result physically and/or logically
+type SARIFLocation struct {
+	PhysicalLocation *SARIFPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []SARIFLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+// SARIFPhysicalLocation is a file and optional region
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	Region           *SARIFRegion          `json:"region,omitempty"`
+}
+
+// SARIFArtifactLocation is a file URI
+type SARIFArtifactLocation struct {
+	URI   string `json:"uri"`
+	Index *int   `json:"index,omitempty"`
+}
+
+// SARIFRegion is a line/column range
+type SARIFRegion struct {
+	StartLine   int `json:"startLine,omitempty"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// SARIFLogicalLocation is a path inside an artifact, e.g. a JSON path
+type SARIFLogicalLocation struct {
+	Name               string `json:"name,omitempty"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind,omitempty"`
+}
+
+// SARIFInvocation records whether the run succeeded
+type SARIFInvocation struct {
+	ExecutionSuccessful bool `json:"executionSuccessful"`
+}
+
+// SARIFReporter converts report artifacts into SARIF 2.1.0
+type SARIFReporter struct {
+	ToolName       string
+	ToolVersion    string
+	InformationURI string
+	// BaseDir makes file URIs relative so code-review tools can map them to
+	// the repository
+	BaseDir string
+}
+
+// NewSARIFReporter creates a SARIF reporter
+func NewSARIFReporter(toolName, toolVersion string) *SARIFReporter {
+	return &SARIFReporter{ToolName: toolName, ToolVersion: toolVersion}
+}
+
+// Build converts artifacts into a SARIF log with a single run
+func (sr *SARIFReporter) Build(artifacts []ReportArtifact) *SARIFLog {
+	run := SARIFRun{
+		Tool: SARIFTool{Driver: SARIFDriver{
+			Name:           sr.ToolName,
+			Version:        sr.ToolVersion,
+			InformationURI: sr.InformationURI,
+		}},
+		Results:     make([]SARIFResult, 0),
+		Invocations: []SARIFInvocation{{ExecutionSuccessful: true}},
+	}
+
+	ruleIndex := make(map[string]int)
+	artifactIndex := make(map[string]int)
+	addArtifact := func(uri string) *int {
+		index, exists := artifactIndex[uri]
+		if !exists {
+			index = len(run.Artifacts)
+			artifactIndex[uri] = index
+			run.Artifacts = append(run.Artifacts, SARIFArtifact{
+				Location: SARIFArtifactLocation{URI: uri},
+				Roles:    []string{"analysisTarget"},
+			})
+		}
+		return &index
+	}
+
+	for _, artifact := range artifacts {
+		for _, finding := range artifact.Findings {
+			index, exists := ruleIndex[finding.RuleID]
+			if !exists {
+				index = len(run.Tool.Driver.Rules)
+				ruleIndex[finding.RuleID] = index
+				run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule(finding))
+			}
+
+			result := SARIFResult{
+				RuleID:     finding.RuleID,
+				RuleIndex:  index,
+				Level:      string(finding.Severity),
+				Kind:       "fail",
+				Message:    SARIFMessage{Text: finding.Message},
+				Properties: sarifProperties(artifact, finding),
+			}
+
+			location := SARIFLocation{}
+			source := finding.Location
+			if source == nil || source.File == "" {
+				source = artifact.Location
+			}
+			if source != nil && source.File != "" {
+				uri := sr.uri(source.File)
+				physical := &SARIFPhysicalLocation{
+					ArtifactLocation: SARIFArtifactLocation{URI: uri, Index: addArtifact(uri)},
+				}
+				if source.Line > 0 {
+					physical.Region = &SARIFRegion{StartLine: source.Line, StartColumn: source.Column}
+				}
+				location.PhysicalLocation = physical
+			}
+			if path := firstNonEmpty(finding.Path, locationPath(source)); path != "" {
+				location.LogicalLocations = []SARIFLogicalLocation{{
+					Name:               path[strings.LastIndexAny(path, "./>")+1:],
+					FullyQualifiedName: artifact.Name + ":" + path,
+					Kind:               "element",
+				}}
+			} else if location.PhysicalLocation == nil {
+				location.LogicalLocations = []SARIFLogicalLocation{{FullyQualifiedName: artifact.Name, Kind: "module"}}
+			}
+			result.Locations = []SARIFLocation{location}
+
+			// SARIF fixes require artifact changes, so prose suggestions are
+			// carried as a property
+			if finding.Suggestion != "" {
+				result.Properties["suggestion"] = finding.Suggestion
+			}
+			run.Results = append(run.Results, result)
+		}
+	}
+
+	return &SARIFLog{
+		Schema:  SARIFSchema,
+		Version: SARIFVersion,
+		Runs:    []SARIFRun{run},
+	}
+}
+
+// Write writes artifacts as indented SARIF JSON
+func (sr *SARIFReporter) Write(w io.Writer, artifacts []ReportArtifact) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sr.Build(artifacts))
+}
+
+func (sr *SARIFReporter) uri(file string) string {
+	if sr.BaseDir != "" && filepath.IsAbs(file) {
+		if rel, err := filepath.Rel(sr.BaseDir, file); err == nil && !strings.HasPrefix(rel, "..") {
+			file = rel
+		}
+	}
+	return filepath.ToSlash(file)
+}
+
+func sarifRule(finding ReportFinding) SARIFRule {
+	rule := SARIFRule{
+		ID:                   finding.RuleID,
+		Name:                 finding.RuleName,
+		HelpURI:              finding.HelpURI,
+		DefaultConfiguration: &SARIFRuleConfig{Level: string(finding.Severity)},
+	}
+	if finding.Description != "" {
+		rule.ShortDescription = &SARIFMessage{Text: finding.Description}
+	} else {
+		rule.ShortDescription = &SARIFMessage{Text: finding.Message}
+	}
+	if finding.Suggestion != "" {
+		rule.Help = &SARIFMessage{Text: finding.Suggestion}
+	}
+	properties := map[string]any{}
+	if finding.Category != "" {
+		properties["category"] = finding.Category
+	}
+	if tags := nonEmpty(slices.Clone(finding.Tags)...); len(tags) > 0 {
+		properties["tags"] = tags
+	}
+	if len(properties) > 0 {
+		rule.Properties = properties
+	}
+	return rule
+}
+
+func sarifProperties(artifact ReportArtifact, finding ReportFinding) map[string]any {
+	properties := map[string]any{"artifact": artifact.Name, "kind": artifact.Kind}
+	maps.Copy(properties, finding.Properties)
+	return properties
+}
@@ -0,0 +1,181 @@
+package validation
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func reportArtifacts() []ReportArtifact {
+	schema := ArtifactFromValidation("user.json", "schema", &ValidationResult{
+		Errors: []ValidationError{{
+			Code:       "required_field",
+			Message:    "field email is required",
+			Field:      "fields.email",
+			Level:      ValidationLevelError,
+			Location:   &SourceLocation{File: "/repo/schemas/user.json", Line: 12, Column: 4},
+			Suggestion: "add an email field",
+		}},
+		Warnings: []ValidationWarning{{Code: "missing_description", Message: "schema has no description"}},
+	})
+	schema.Duration = 1500 * time.Millisecond
+
+	page := ArtifactFromAccessibility("index.html", &AccessibilityResult{
+		Violations: []A11yViolation{{
+			Code:      "missing_alt_text",
+			Message:   "image has no alt text",
+			Element:   "img",
+			Impact:    A11yImpactSerious,
+			Category:  A11yCategory("images"),
+			Level:     A11yLevel("A"),
+			Location:  &SourceLocation{File: "index.html", Path: "html > body > img"},
+			Resources: []A11yResource{{Title: "Non-text content", URL: "https://www.w3.org/WAI/WCAG21/Understanding/non-text-content"}},
+		}},
+	})
+
+	theme := ArtifactFromTheme("dark", &ThemeValidationResult{Valid: true})
+	return []ReportArtifact{schema, page, theme}
+}
+
+func TestSARIFReporter_Build(t *testing.T) {
+	reporter := NewSARIFReporter("ruun-validation", "1.2.3")
+	reporter.BaseDir = "/repo"
+
+	log := reporter.Build(reportArtifacts())
+	assert.Equal(t, SARIFVersion, log.Version)
+	assert.Equal(t, SARIFSchema, log.Schema)
+	require.Len(t, log.Runs, 1)
+
+	run := log.Runs[0]
+	assert.Equal(t, "ruun-validation", run.Tool.Driver.Name)
+	assert.Equal(t, "1.2.3", run.Tool.Driver.Version)
+	require.Len(t, run.Tool.Driver.Rules, 3)
+	require.Len(t, run.Results, 3)
+
+	required := run.Results[0]
+	assert.Equal(t, "required_field", required.RuleID)
+	assert.Equal(t, 0, required.RuleIndex)
+	assert.Equal(t, "error", required.Level)
+	assert.Equal(t, "add an email field", required.Properties["suggestion"])
+	physical := required.Locations[0].PhysicalLocation
+	require.NotNil(t, physical)
+	assert.Equal(t, "schemas/user.json", physical.ArtifactLocation.URI, "paths are relative to BaseDir")
+	assert.Equal(t, &SARIFRegion{StartLine: 12, StartColumn: 4}, physical.Region)
+	assert.Equal(t, "user.json:fields.email", required.Locations[0].LogicalLocations[0].FullyQualifiedName)
+
+	warning := run.Results[1]
+	assert.Equal(t, "warning", warning.Level)
+	assert.Nil(t, warning.Locations[0].PhysicalLocation)
+	assert.Equal(t, "module", warning.Locations[0].LogicalLocations[0].Kind)
+
+	a11y := run.Results[2]
+	assert.Equal(t, "error", a11y.Level, "serious impact is an error")
+	rule := run.Tool.Driver.Rules[a11y.RuleIndex]
+	assert.Equal(t, "https://www.w3.org/WAI/WCAG21/Understanding/non-text-content", rule.HelpURI)
+	assert.Equal(t, "Non-text content", rule.ShortDescription.Text)
+	assert.Equal(t, []string{"accessibility", "wcag-a", "images"}, rule.Properties["tags"])
+
+	require.Len(t, run.Artifacts, 2)
+	assert.Equal(t, "index.html", run.Artifacts[1].Location.URI)
+}
+
+func TestSARIFReporter_Write(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, NewSARIFReporter("ruun-validation", "").Write(&buf, reportArtifacts()))
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, SARIFVersion, decoded["version"])
+	assert.Contains(t, decoded, "$schema")
+
+	buf.Reset()
+	require.NoError(t, NewSARIFReporter("ruun-validation", "").Write(&buf, nil))
+	assert.Contains(t, buf.String(), `"results": []`, "an empty run still has a results array")
+}
+
+func TestJUnitReporter_Build(t *testing.T) {
+	reporter := NewJUnitReporter("build")
+	reporter.Timestamp = time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	suites := reporter.Build(reportArtifacts())
+	assert.Equal(t, 3, suites.Tests)
+	assert.Equal(t, 2, suites.Failures)
+	assert.Equal(t, "1.500", suites.Time)
+	require.Len(t, suites.Suites, 3)
+
+	schema := suites.Suites[0]
+	assert.Equal(t, "build.schema", schema.Name)
+	assert.Equal(t, "2025-01-02T03:04:05", schema.Timestamp)
+	require.Len(t, schema.Cases, 1)
+	testCase := schema.Cases[0]
+	require.NotNil(t, testCase.Failure)
+	assert.Equal(t, "required_field", testCase.Failure.Type)
+	assert.Equal(t, "field email is required", testCase.Failure.Message)
+	assert.Contains(t, testCase.Failure.Text, "/repo/schemas/user.json:12:4")
+	assert.Contains(t, testCase.Failure.Text, "fix: add an email field")
+	assert.Contains(t, testCase.SystemOut, "[warning] missing_description")
+
+	theme := suites.Suites[2]
+	assert.Equal(t, 0, theme.Failures)
+	assert.Nil(t, theme.Cases[0].Failure)
+}
+
+func TestJUnitReporter_FailedWithoutFindings(t *testing.T) {
+	suites := NewJUnitReporter("").Build([]ReportArtifact{{Name: "lint", Kind: "build", Passed: false}})
+	require.Len(t, suites.Suites, 1)
+	assert.Equal(t, "build", suites.Suites[0].Name)
+	require.NotNil(t, suites.Suites[0].Cases[0].Failure)
+	assert.Equal(t, "build validation failed", suites.Suites[0].Cases[0].Failure.Message)
+}
+
+func TestJUnitReporter_Write(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, NewJUnitReporter("build").Write(&buf, reportArtifacts()))
+	assert.True(t, bytes.HasPrefix(buf.Bytes(), []byte(xml.Header)))
+
+	var decoded JUnitTestSuites
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, 3, decoded.Tests)
+	assert.Equal(t, 2, decoded.Failures)
+}
+
+func TestBuildReporter_ReportFormats(t *testing.T) {
+	results := []BuildResult{{Pipeline: "ci", Stage: "lint", Status: BuildStatusFailed, Error: "lint failed"}}
+
+	t.Run("default", func(t *testing.T) {
+		config := NewBuildIntegration().config
+		config.ReportPath = t.TempDir()
+		reporter := NewBuildReporter(config)
+		reporter.AddResults(results)
+		require.NoError(t, reporter.GenerateReport())
+
+		assert.FileExists(t, filepath.Join(config.ReportPath, "build-report.json"))
+		assert.NoFileExists(t, filepath.Join(config.ReportPath, "build-report.sarif"))
+		assert.NoFileExists(t, filepath.Join(config.ReportPath, "build-report.junit.xml"))
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		config := NewBuildIntegration().config
+		config.ReportPath = t.TempDir()
+		config.EnableSARIFReport = true
+		config.EnableJUnitReport = true
+		reporter := NewBuildReporter(config)
+		reporter.AddResults(results)
+		require.NoError(t, reporter.GenerateReport())
+
+		sarif, err := os.ReadFile(filepath.Join(config.ReportPath, "build-report.sarif"))
+		require.NoError(t, err)
+		assert.Contains(t, string(sarif), `"ruleId": "build_failed"`)
+
+		junit, err := os.ReadFile(filepath.Join(config.ReportPath, "build-report.junit.xml"))
+		require.NoError(t, err)
+		assert.Contains(t, string(junit), `<testcase name="ci/lint"`)
+	})
+}
@@ -4,10 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/niiniyare/ruun/pkg/ratelimit"
+	"github.com/niiniyare/ruun/schema"
 )
 
 // RuntimeValidator provides runtime validation for user inputs and API calls
@@ -15,9 +20,9 @@ type RuntimeValidator struct {
 	middleware   []ValidationMiddleware
 	interceptors []ValidationInterceptor
 	config       RuntimeValidationConfig
-	metrics      *RuntimeMetrics
+	metrics      *RuntimeValidationMetrics
 	cache        *ValidationCache
-	rateLimiter  *RateLimiter
+	rateLimiter  *ratelimit.Limiter
 }
 
 // RuntimeValidationConfig configures runtime validation
@@ -52,8 +57,8 @@ type ValidationInterceptor interface {
 	OnValidationError(ctx context.Context, data any, err error) error
 }
 
-// RuntimeMetrics contains runtime validation metrics
-type RuntimeMetrics struct {
+// RuntimeValidationMetrics contains runtime validation metrics
+type RuntimeValidationMetrics struct {
 	TotalValidations      int64            `json:"totalValidations"`
 	SuccessfulValidations int64            `json:"successfulValidations"`
 	FailedValidations     int64            `json:"failedValidations"`
@@ -62,6 +67,7 @@ type RuntimeMetrics struct {
 	ErrorsByType          map[string]int64 `json:"errorsByType"`
 	CacheHitRate          float64          `json:"cacheHitRate"`
 	RateLimitHits         int64            `json:"rateLimitHits"`
+	RateLimitErrors       int64            `json:"rateLimitErrors"`
 	LastUpdated           time.Time        `json:"lastUpdated"`
 	mutex                 sync.RWMutex
 }
@@ -83,22 +89,6 @@ type CacheEntry struct {
 	TTL       time.Duration     `json:"ttl"`
 }
 
-// RateLimiter provides rate limiting for validation requests
-type RateLimiter struct {
-	requests map[string]*RequestCounter
-	mutex    sync.RWMutex
-	limit    int
-	window   time.Duration
-}
-
-// RequestCounter tracks requests per client
-type RequestCounter struct {
-	Count        int       `json:"count"`
-	Window       time.Time `json:"window"`
-	Blocked      bool      `json:"blocked"`
-	BlockedUntil time.Time `json:"blockedUntil"`
-}
-
 // RuntimeValidationRequest represents a validation request
 type RuntimeValidationRequest struct {
 	ID        string         `json:"id"`
@@ -204,15 +194,86 @@ func NewRuntimeValidator() *RuntimeValidator {
 		middleware:   make([]ValidationMiddleware, 0),
 		interceptors: make([]ValidationInterceptor, 0),
 		config:       config,
-		metrics:      NewRuntimeMetrics(),
+		metrics:      NewRuntimeValidationMetrics(),
 		cache:        NewValidationCache(config.MaxCacheSize, config.CacheTTL),
-		rateLimiter:  NewRateLimiter(config.RateLimitRequests, config.RateLimitWindow),
+		rateLimiter:  newRateLimiter(config),
+	}
+}
+
+// newRateLimiter limits each client to RateLimitRequests validations of a
+// type per RateLimitWindow, counted in process. It returns nil, leaving
+// requests unlimited, when the configured limit is unusable.
+func newRateLimiter(config RuntimeValidationConfig) *ratelimit.Limiter {
+	plans, err := ratelimit.NewStaticPlans("default", &ratelimit.Plan{
+		Name: "default",
+		Limits: map[string]ratelimit.Limit{ratelimit.AnyAction: {
+			Algorithm: ratelimit.SlidingWindow,
+			Requests:  config.RateLimitRequests,
+			Window:    config.RateLimitWindow,
+			PerIP:     true,
+		}},
+	})
+	if err != nil {
+		return nil
+	}
+	limiter, err := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), plans)
+	if err != nil {
+		return nil
+	}
+	return limiter
+}
+
+// SetRateLimiter replaces the in-process limiter, for instance with one
+// over tenant plans and a ratelimit.RedisStore shared between instances
+func (rv *RuntimeValidator) SetRateLimiter(limiter *ratelimit.Limiter) {
+	rv.rateLimiter = limiter
+}
+
+// RateLimiter provides rate limiting for validation requests
+//
+// Deprecated: RuntimeValidator counts requests with a ratelimit.Limiter;
+// build one with ratelimit.NewLimiter and pass it to SetRateLimiter.
+type RateLimiter struct {
+	limiter *ratelimit.Limiter
+}
+
+// RequestCounter tracks requests per client
+//
+// Deprecated: RateLimiter no longer exposes its counters; see
+// ratelimit.Result for the state of a quota.
+type RequestCounter struct {
+	Count        int       `json:"count"`
+	Window       time.Time `json:"window"`
+	Blocked      bool      `json:"blocked"`
+	BlockedUntil time.Time `json:"blockedUntil"`
+}
+
+// NewRateLimiter creates a new rate limiter allowing limit requests per
+// client in each window
+//
+// Deprecated: use ratelimit.NewLimiter.
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{limiter: newRateLimiter(RuntimeValidationConfig{
+		RateLimitRequests: limit,
+		RateLimitWindow:   window,
+	})}
+}
+
+// Allow counts a request from clientID and reports whether it is within
+// the limit
+//
+// Deprecated: use ratelimit.Limiter.Allow.
+func (rl *RateLimiter) Allow(clientID string) bool {
+	if rl.limiter == nil {
+		return true
 	}
+	result, err := rl.limiter.Allow(context.Background(), ratelimit.Key{IP: clientID})
+	return err != nil || result.Allowed
 }
 
-// NewRuntimeMetrics creates new runtime metrics
-func NewRuntimeMetrics() *RuntimeMetrics {
-	return &RuntimeMetrics{
+// NewRuntimeValidationMetrics creates new runtime metrics
+func NewRuntimeValidationMetrics() *RuntimeValidationMetrics {
+	return &RuntimeValidationMetrics{
 		ValidationsByType: make(map[string]int64),
 		ErrorsByType:      make(map[string]int64),
 		LastUpdated:       time.Now(),
@@ -228,15 +289,6 @@ func NewValidationCache(maxSize int, ttl time.Duration) *ValidationCache {
 	}
 }
 
-// NewRateLimiter creates a new rate limiter
-func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
-	return &RateLimiter{
-		requests: make(map[string]*RequestCounter),
-		limit:    limit,
-		window:   window,
-	}
-}
-
 // ValidateInput validates user input data
 func (rv *RuntimeValidator) ValidateInput(ctx context.Context, data any, schema any) *RuntimeValidationResponse {
 	request := &RuntimeValidationRequest{
@@ -297,9 +349,13 @@ func (rv *RuntimeValidator) processValidationRequest(ctx context.Context, reques
 		Timestamp: start,
 	}
 
-	// Check rate limiting
-	if rv.config.EnableRateLimit {
-		if !rv.rateLimiter.Allow(request.ClientID) {
+	// Check rate limiting; a failing limiter lets the request through and
+	// is counted in RateLimitErrors
+	if rv.config.EnableRateLimit && rv.rateLimiter != nil {
+		quota, err := rv.allowRequest(ctx, request)
+		if err != nil {
+			rv.metrics.RecordRateLimitError()
+		} else if !quota.Allowed {
 			response.Valid = false
 			response.Result = &ValidationResult{
 				Valid: false,
@@ -308,7 +364,7 @@ func (rv *RuntimeValidator) processValidationRequest(ctx context.Context, reques
 					NewValidationError("rate_limit_exceeded", "Too many validation requests", "", ValidationLevelError),
 				},
 				Timestamp: time.Now(),
-				Metadata:  map[string]any{"rateLimited": true},
+				Metadata:  map[string]any{"rateLimited": true, "retryAfter": quota.RetryAfter},
 			}
 			rv.metrics.RecordRateLimitHit()
 			return response
@@ -411,8 +467,7 @@ func (rv *RuntimeValidator) validateInputData(ctx context.Context, data any, sch
 			StrictMode: rv.config.StrictMode,
 		})
 
-		validationCtx := NewValidationContext(ctx, ValidationLevelError, "runtime_input")
-		schemaResult := engine.ValidateSchema(validationCtx, schema)
+		schemaResult := engine.ValidateSchema(schema)
 
 		if !schemaResult.IsValid() {
 			result.Valid = false
@@ -798,50 +853,24 @@ func (vc *ValidationCache) GetHitRate() float64 {
 	return float64(vc.hits) / float64(total)
 }
 
-// Rate limiter methods
-
-func (rl *RateLimiter) Allow(clientID string) bool {
-	rl.mutex.Lock()
-	defer rl.mutex.Unlock()
-
-	now := time.Now()
-	counter, exists := rl.requests[clientID]
-
-	if !exists {
-		rl.requests[clientID] = &RequestCounter{
-			Count:  1,
-			Window: now,
-		}
-		return true
-	}
-
-	// Check if client is currently blocked
-	if counter.Blocked && now.Before(counter.BlockedUntil) {
-		return false
-	}
-
-	// Reset window if expired
-	if now.Sub(counter.Window) > rl.window {
-		counter.Count = 1
-		counter.Window = now
-		counter.Blocked = false
-		return true
+// allowRequest counts a request against the rate limiter, keyed by the
+// tenant and user attached with schema.ContextWithUser, the client and the
+// validation type. Validating a *schema.Schema also applies its
+// Security.RateLimit.
+func (rv *RuntimeValidator) allowRequest(ctx context.Context, request *RuntimeValidationRequest) (*ratelimit.Result, error) {
+	key := ratelimit.Key{IP: request.ClientID, Action: "validate:" + request.Type}
+	if user, ok := schema.UserFromContext(ctx); ok {
+		key.TenantID, key.UserID = user.GetTenantID(), user.GetID()
 	}
-
-	// Check if limit is exceeded
-	if counter.Count >= rl.limit {
-		counter.Blocked = true
-		counter.BlockedUntil = now.Add(rl.window)
-		return false
+	if s, ok := request.Schema.(*schema.Schema); ok && s != nil {
+		return rv.rateLimiter.AllowSchema(ctx, key, s)
 	}
-
-	counter.Count++
-	return true
+	return rv.rateLimiter.Allow(ctx, key)
 }
 
 // Metrics methods
 
-func (rm *RuntimeMetrics) RecordValidation(validationType string, success bool, duration time.Duration) {
+func (rm *RuntimeValidationMetrics) RecordValidation(validationType string, success bool, duration time.Duration) {
 	rm.mutex.Lock()
 	defer rm.mutex.Unlock()
 
@@ -867,14 +896,21 @@ func (rm *RuntimeMetrics) RecordValidation(validationType string, success bool,
 	rm.LastUpdated = time.Now()
 }
 
-func (rm *RuntimeMetrics) RecordRateLimitHit() {
+func (rm *RuntimeValidationMetrics) RecordRateLimitHit() {
 	rm.mutex.Lock()
 	defer rm.mutex.Unlock()
 
 	rm.RateLimitHits++
 }
 
-func (rm *RuntimeMetrics) GetStats() map[string]any {
+func (rm *RuntimeValidationMetrics) RecordRateLimitError() {
+	rm.mutex.Lock()
+	defer rm.mutex.Unlock()
+
+	rm.RateLimitErrors++
+}
+
+func (rm *RuntimeValidationMetrics) GetStats() map[string]any {
 	rm.mutex.RLock()
 	defer rm.mutex.RUnlock()
 
@@ -892,6 +928,7 @@ func (rm *RuntimeMetrics) GetStats() map[string]any {
 		"validationsByType":     rm.ValidationsByType,
 		"errorsByType":          rm.ErrorsByType,
 		"rateLimitHits":         rm.RateLimitHits,
+		"rateLimitErrors":       rm.RateLimitErrors,
 		"lastUpdated":           rm.LastUpdated,
 	}
 }
@@ -945,7 +982,13 @@ func (rv *RuntimeValidator) HTTPMiddleware() func(http.Handler) http.Handler {
 			if !response.Valid {
 				// Return validation error
 				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusBadRequest)
+				status := http.StatusBadRequest
+				if limited, _ := response.Result.Metadata["rateLimited"].(bool); limited {
+					status = http.StatusTooManyRequests
+					retryAfter, _ := response.Result.Metadata["retryAfter"].(time.Duration)
+					w.Header().Set(ratelimit.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+				}
+				w.WriteHeader(status)
 				json.NewEncoder(w).Encode(map[string]any{
 					"error":  "Validation failed",
 					"result": response.Result,
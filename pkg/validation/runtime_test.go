@@ -0,0 +1,109 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/niiniyare/ruun/pkg/ratelimit"
+	"github.com/niiniyare/ruun/schema"
+)
+
+func newTestRateLimiter(t *testing.T, requests int) *ratelimit.Limiter {
+	t.Helper()
+	plans, err := ratelimit.NewStaticPlans("free", &ratelimit.Plan{Name: "free", Limits: map[string]ratelimit.Limit{
+		ratelimit.AnyAction: {Requests: requests, Window: time.Minute, PerUser: true},
+	}})
+	require.NoError(t, err)
+	limiter, err := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), plans)
+	require.NoError(t, err)
+	return limiter
+}
+
+func rateLimited(response *RuntimeValidationResponse) bool {
+	limited, _ := response.Result.Metadata["rateLimited"].(bool)
+	return limited
+}
+
+func TestRuntimeValidator_RateLimit(t *testing.T) {
+	rv := NewRuntimeValidator()
+	rv.SetRateLimiter(newTestRateLimiter(t, 2))
+	ctx := schema.ContextWithUser(context.Background(), &schema.BasicUser{ID: "u1", TenantID: "acme"})
+
+	for i := range 2 {
+		assert.False(t, rateLimited(rv.ValidateForm(ctx, map[string]any{"n": i}, nil)))
+	}
+	response := rv.ValidateForm(ctx, map[string]any{"n": 3}, nil)
+	assert.True(t, rateLimited(response))
+	assert.False(t, response.Valid)
+	assert.Equal(t, int64(1), rv.metrics.RateLimitHits)
+
+	other := schema.ContextWithUser(context.Background(), &schema.BasicUser{ID: "u2", TenantID: "acme"})
+	assert.False(t, rateLimited(rv.ValidateForm(other, map[string]any{"n": 4}, nil)), "quotas are per user")
+	assert.False(t, rateLimited(rv.ValidateInput(ctx, "text", nil)), "quotas are per validation type")
+}
+
+func TestRuntimeValidator_SchemaRateLimit(t *testing.T) {
+	rv := NewRuntimeValidator()
+	rv.SetRateLimiter(newTestRateLimiter(t, 100))
+	form := &schema.Schema{ID: "invoice", Security: &schema.Security{RateLimit: &schema.RateLimit{
+		Enabled: true, MaxRequests: 1, Window: time.Minute,
+	}}}
+	ctx := context.Background()
+
+	assert.False(t, rateLimited(rv.ValidateForm(ctx, map[string]any{"n": 1}, form)))
+	assert.True(t, rateLimited(rv.ValidateForm(ctx, map[string]any{"n": 2}, form)), "the schema's Security.RateLimit is enforced")
+	assert.False(t, rateLimited(rv.ValidateForm(ctx, map[string]any{"n": 3}, &schema.Schema{ID: "customer"})))
+}
+
+func TestRuntimeValidator_HTTPMiddlewareRateLimit(t *testing.T) {
+	rv := NewRuntimeValidator()
+	rv.SetRateLimiter(newTestRateLimiter(t, 1))
+	handler := rv.HTTPMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	request := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/forms", nil)
+		req.Header.Set("X-Client-ID", "client-1")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.NotEqual(t, http.StatusTooManyRequests, request().Code)
+	limited := request()
+	assert.Equal(t, http.StatusTooManyRequests, limited.Code)
+	assert.NotEmpty(t, limited.Header().Get(ratelimit.HeaderRetryAfter))
+}
+
+// failingPlans is a plan provider that is always unavailable
+type failingPlans struct{}
+
+func (failingPlans) TenantPlan(context.Context, string) (*ratelimit.Plan, error) {
+	return nil, errors.New("plans unavailable")
+}
+
+func TestRuntimeValidator_RateLimitErrors(t *testing.T) {
+	limiter, err := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), failingPlans{})
+	require.NoError(t, err)
+	rv := NewRuntimeValidator()
+	rv.SetRateLimiter(limiter)
+
+	response := rv.ValidateForm(context.Background(), map[string]any{"n": 1}, nil)
+	assert.False(t, rateLimited(response), "a failing limiter lets requests through")
+	assert.Equal(t, int64(1), rv.metrics.RateLimitErrors)
+	assert.Equal(t, int64(1), rv.metrics.GetStats()["rateLimitErrors"])
+}
+
+func TestRateLimiter_Deprecated(t *testing.T) {
+	limiter := NewRateLimiter(2, time.Minute)
+	assert.True(t, limiter.Allow("client-1"))
+	assert.True(t, limiter.Allow("client-1"))
+	assert.False(t, limiter.Allow("client-1"))
+	assert.True(t, limiter.Allow("client-2"), "clients are limited separately")
+}
@@ -131,6 +131,7 @@ const (
 	ReportFormatHuman    ReportFormat = "human"
 	ReportFormatMarkdown ReportFormat = "markdown"
 	ReportFormatJUnit    ReportFormat = "junit"
+	ReportFormatSARIF    ReportFormat = "sarif"
 )
 
 // NewSchemaValidator creates a new schema validator
@@ -273,7 +274,7 @@ func (sv *SchemaValidator) registerBuiltinRules() {
 		Name:        "Required Fields",
 		Description: "Validates required fields configuration",
 		Category:    SchemaCategoryForm,
-		Level:       ValidationLevelWarning,
+		Level:       ValidationLevelWarn,
 		Enabled:     true,
 		Validator:   &RequiredFieldsValidator{},
 	})
@@ -315,7 +316,7 @@ func (sv *SchemaValidator) registerBuiltinRules() {
 		Name:        "Theme Consistency",
 		Description: "Validates theme consistency and completeness",
 		Category:    SchemaCategoryTheme,
-		Level:       ValidationLevelWarning,
+		Level:       ValidationLevelWarn,
 		Enabled:     true,
 		Validator:   &ThemeConsistencyValidator{},
 	})
@@ -344,17 +345,17 @@ func (sv *SchemaValidator) mergeResults(base, addition *SchemaValidationResult)
 // FormStructureValidator validates basic form schema structure
 type FormStructureValidator struct{}
 
-func (v *FormStructureValidator) ValidateSchema(schema any, context *SchemaValidationContext) *SchemaValidationResult {
+func (v *FormStructureValidator) ValidateSchema(schemaData any, context *SchemaValidationContext) *SchemaValidationResult {
 	result := &SchemaValidationResult{
 		Valid:     true,
 		Timestamp: time.Now(),
 		Metadata:  make(map[string]any),
 	}
 
-	formSchema, ok := schema.(*schema.Schema)
+	formSchema, ok := schemaData.(*schema.Schema)
 	if !ok {
 		// Try to convert from interface
-		if schemaMap, ok := schema.(map[string]any); ok {
+		if schemaMap, ok := schemaData.(map[string]any); ok {
 			// Basic structure validation for map representation
 			requiredFields := []string{"id", "type", "title"}
 			for _, field := range requiredFields {
@@ -377,7 +378,7 @@ func (v *FormStructureValidator) ValidateSchema(schema any, context *SchemaValid
 				Message:  "Schema must be a valid form schema object",
 				Path:     strings.Join(context.Path, "."),
 				Expected: "*schema.Schema or map[string]any",
-				Actual:   reflect.TypeOf(schema).String(),
+				Actual:   reflect.TypeOf(schemaData).String(),
 				Level:    ValidationLevelError,
 				Category: SchemaCategoryForm,
 				Rule:     "form.schema.structure",
@@ -435,14 +436,14 @@ func (v *FormStructureValidator) GetCategory() SchemaCategory {
 // FormFieldsValidator validates form fields
 type FormFieldsValidator struct{}
 
-func (v *FormFieldsValidator) ValidateSchema(schema any, context *SchemaValidationContext) *SchemaValidationResult {
+func (v *FormFieldsValidator) ValidateSchema(schemaData any, context *SchemaValidationContext) *SchemaValidationResult {
 	result := &SchemaValidationResult{
 		Valid:     true,
 		Timestamp: time.Now(),
 		Metadata:  make(map[string]any),
 	}
 
-	formSchema, ok := schema.(*schema.Schema)
+	formSchema, ok := schemaData.(*schema.Schema)
 	if !ok {
 		return result // Skip if not a form schema
 	}
@@ -470,7 +471,7 @@ func (v *FormFieldsValidator) ValidateSchema(schema any, context *SchemaValidati
 			Level:    context.Level,
 		}
 
-		fieldResult := v.validateField(field, fieldContext)
+		fieldResult := v.validateField(&field, fieldContext)
 		result = v.mergeResults(result, fieldResult)
 	}
 
@@ -531,14 +532,14 @@ func (v *FormFieldsValidator) GetCategory() SchemaCategory {
 // RequiredFieldsValidator validates required field configuration
 type RequiredFieldsValidator struct{}
 
-func (v *RequiredFieldsValidator) ValidateSchema(schema any, context *SchemaValidationContext) *SchemaValidationResult {
+func (v *RequiredFieldsValidator) ValidateSchema(schemaData any, context *SchemaValidationContext) *SchemaValidationResult {
 	result := &SchemaValidationResult{
 		Valid:     true,
 		Timestamp: time.Now(),
 		Metadata:  make(map[string]any),
 	}
 
-	formSchema, ok := schema.(*schema.Schema)
+	formSchema, ok := schemaData.(*schema.Schema)
 	if !ok {
 		return result
 	}
@@ -584,14 +585,14 @@ func (v *RequiredFieldsValidator) GetCategory() SchemaCategory {
 // FieldTypeValidator validates field types
 type FieldTypeValidator struct{}
 
-func (v *FieldTypeValidator) ValidateSchema(schema any, context *SchemaValidationContext) *SchemaValidationResult {
+func (v *FieldTypeValidator) ValidateSchema(schemaData any, context *SchemaValidationContext) *SchemaValidationResult {
 	result := &SchemaValidationResult{
 		Valid:     true,
 		Timestamp: time.Now(),
 		Metadata:  make(map[string]any),
 	}
 
-	field, ok := schema.(*schema.Field)
+	field, ok := schemaData.(*schema.Field)
 	if !ok {
 		return result
 	}
@@ -636,14 +637,14 @@ func (v *FieldTypeValidator) GetCategory() SchemaCategory {
 // FieldValidationValidator validates field validation rules
 type FieldValidationValidator struct{}
 
-func (v *FieldValidationValidator) ValidateSchema(schema any, context *SchemaValidationContext) *SchemaValidationResult {
+func (v *FieldValidationValidator) ValidateSchema(schemaData any, context *SchemaValidationContext) *SchemaValidationResult {
 	result := &SchemaValidationResult{
 		Valid:     true,
 		Timestamp: time.Now(),
 		Metadata:  make(map[string]any),
 	}
 
-	field, ok := schema.(*schema.Field)
+	field, ok := schemaData.(*schema.Field)
 	if !ok {
 		return result
 	}
@@ -700,14 +701,14 @@ func (v *FieldValidationValidator) GetCategory() SchemaCategory {
 // ThemeTokenValidator validates theme design tokens
 type ThemeTokenValidator struct{}
 
-func (v *ThemeTokenValidator) ValidateSchema(schema any, context *SchemaValidationContext) *SchemaValidationResult {
+func (v *ThemeTokenValidator) ValidateSchema(schemaData any, context *SchemaValidationContext) *SchemaValidationResult {
 	result := &SchemaValidationResult{
 		Valid:     true,
 		Timestamp: time.Now(),
 		Metadata:  make(map[string]any),
 	}
 
-	theme, ok := schema.(*schema.Theme)
+	theme, ok := schemaData.(*schema.Theme)
 	if !ok {
 		return result
 	}
@@ -727,7 +728,7 @@ func (v *ThemeTokenValidator) ValidateSchema(schema any, context *SchemaValidati
 	}
 
 	// Validate that essential token categories are present
-	if theme.Tokens.Semantic == nil {
+	if theme.Tokens["semantic"] == nil {
 		result.Warnings = append(result.Warnings, SchemaWarning{
 			Code:       "theme.tokens.semantic_missing",
 			Message:    "Theme should have semantic tokens for better maintainability",
@@ -748,14 +749,14 @@ func (v *ThemeTokenValidator) GetCategory() SchemaCategory {
 // ThemeConsistencyValidator validates theme consistency
 type ThemeConsistencyValidator struct{}
 
-func (v *ThemeConsistencyValidator) ValidateSchema(schema any, context *SchemaValidationContext) *SchemaValidationResult {
+func (v *ThemeConsistencyValidator) ValidateSchema(schemaData any, context *SchemaValidationContext) *SchemaValidationResult {
 	result := &SchemaValidationResult{
 		Valid:     true,
 		Timestamp: time.Now(),
 		Metadata:  make(map[string]any),
 	}
 
-	theme, ok := schema.(*schema.Theme)
+	theme, ok := schemaData.(*schema.Theme)
 	if !ok {
 		return result
 	}
@@ -803,6 +804,8 @@ func (er *ErrorReporter) GenerateReport(result *SchemaValidationResult) (string,
 		return er.generateMarkdownReport(result)
 	case ReportFormatJUnit:
 		return er.generateJUnitReport(result)
+	case ReportFormatSARIF:
+		return er.generateSARIFReport(result)
 	default:
 		return er.generateHumanReport(result)
 	}
@@ -897,32 +900,25 @@ func (er *ErrorReporter) generateMarkdownReport(result *SchemaValidationResult)
 }
 
 func (er *ErrorReporter) generateJUnitReport(result *SchemaValidationResult) (string, error) {
-	// Simplified JUnit XML format
 	var report strings.Builder
+	err := NewJUnitReporter("validation").Write(&report, []ReportArtifact{er.artifact(result)})
+	return report.String(), err
+}
 
-	report.WriteString(`<?xml version="1.0" encoding="UTF-8"?>`)
-	report.WriteString("\n")
-	report.WriteString(fmt.Sprintf(`<testsuite name="SchemaValidation" tests="1" failures="%d" errors="0" time="%.3f">`,
-		len(result.Errors), result.Performance.Duration.Seconds()))
-	report.WriteString("\n")
+func (er *ErrorReporter) generateSARIFReport(result *SchemaValidationResult) (string, error) {
+	var report strings.Builder
+	err := NewSARIFReporter("ruun-validation", "").Write(&report, []ReportArtifact{er.artifact(result)})
+	return report.String(), err
+}
 
-	if result.Valid {
-		report.WriteString(`  <testcase name="SchemaValidation" classname="validation"/>`)
-	} else {
-		report.WriteString(`  <testcase name="SchemaValidation" classname="validation">`)
-		report.WriteString("\n")
-		for _, err := range result.Errors {
-			report.WriteString(fmt.Sprintf(`    <failure message="%s" type="%s">%s</failure>`,
-				err.Message, err.Code, err.Path))
-			report.WriteString("\n")
+func (er *ErrorReporter) artifact(result *SchemaValidationResult) ReportArtifact {
+	artifact := ArtifactFromSchema("schema", result)
+	if !er.suggestions {
+		for i := range artifact.Findings {
+			artifact.Findings[i].Suggestion = ""
 		}
-		report.WriteString(`  </testcase>`)
 	}
-
-	report.WriteString("\n")
-	report.WriteString("</testsuite>")
-
-	return report.String(), nil
+	return artifact
 }
 
 // SchemaStructureValidator validates basic schema structure
@@ -602,7 +602,7 @@ func (tf *TestFramework) validateSchema(ctx context.Context, testCase *TestCase)
 
 func (tf *TestFramework) validateAccessibility(ctx context.Context, testCase *TestCase) (*ValidationResult, error) {
 	validator := NewAccessibilityValidator()
-	validationCtx := NewValidationContext(ctx, ValidationLevelAA, "test")
+	validationCtx := NewValidationContext(ctx, ValidationLevelStrict, "test")
 	a11yResult := validator.ValidateAccessibility(validationCtx, testCase.Input)
 	return tf.convertA11yResult(a11yResult), nil
 }
@@ -982,11 +982,9 @@ func (tr *TestReporter) generateJUnitReport() (string, error) {
 
 	testCases := ""
 	for _, result := range tr.results {
-		status := "passed"
 		failure := ""
 
 		if result.Status == TestStatusFailed {
-			status = "failed"
 			failure = fmt.Sprintf(`<failure message="%s">%v</failure>`, result.Error, result.Assertions)
 		}
 
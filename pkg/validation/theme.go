@@ -526,7 +526,7 @@ func (tv *ThemeValidator) registerBuiltinRules() {
 		Name:        "Color Consistency",
 		Description: "Validates color palette consistency and harmony",
 		Category:    ThemeValidationCategoryConsistency,
-		Level:       ValidationLevelWarning,
+		Level:       ValidationLevelWarn,
 		Enabled:     true,
 		Validator:   &ColorConsistencyValidator{},
 	})
@@ -548,7 +548,7 @@ func (tv *ThemeValidator) registerBuiltinRules() {
 		Name:        "Theme Size",
 		Description: "Validates theme bundle size and complexity",
 		Category:    ThemeValidationCategoryPerformance,
-		Level:       ValidationLevelWarning,
+		Level:       ValidationLevelWarn,
 		Enabled:     true,
 		Validator:   &ThemePerformanceValidator{maxTokens: tv.config.MaxTokenCount},
 	})
@@ -1197,7 +1197,7 @@ func (v *TokenFormatValidator) ValidateTheme(theme any, context *ThemeValidation
 				Code:     "invalid_token_name",
 				Message:  fmt.Sprintf("Token name '%s' doesn't follow naming conventions", token),
 				Category: ThemeValidationCategoryTokens,
-				Severity: ValidationLevelWarning,
+				Severity: ValidationLevelWarn,
 				Token:    token,
 				Fix:      "Use kebab-case or dot notation for token names",
 				AutoFix:  true,
@@ -45,6 +45,8 @@ type Action struct {
 
 	// Internal
 	evaluator *condition.Evaluator `json:"-"`
+	// explicitZeros holds zero values set in the source JSON; see MarshalJSON
+	explicitZeros jsonZeros
 }
 
 // Core Methods
@@ -193,6 +193,11 @@ func (bre *BusinessRuleEngine) ListRules() []*BusinessRule {
 
 // ApplyRules applies business rules to a schema based on provided data with deep copy
 func (bre *BusinessRuleEngine) ApplyRules(ctx context.Context, schema *Schema, data map[string]any) (*Schema, error) {
+	return bre.applyRules(ctx, schema, data, nil)
+}
+
+// applyRules applies the rules, recording each step in explain when set
+func (bre *BusinessRuleEngine) applyRules(ctx context.Context, schema *Schema, data map[string]any, explain *RulesExplanation) (*Schema, error) {
 	bre.mu.RLock()
 	defer bre.mu.RUnlock()
 	// Create a deep copy of the schema to modify
@@ -202,17 +207,26 @@ func (bre *BusinessRuleEngine) ApplyRules(ctx context.Context, schema *Schema, d
 	collector := NewErrorCollector()
 	// Apply each rule
 	for _, rule := range applicableRules {
+		trace := explain.addRule(rule)
 		if !rule.Enabled {
 			continue
 		}
 		// Check if rule condition is met
-		shouldApply, err := bre.evaluateRuleCondition(ctx, rule, data)
+		var shouldApply bool
+		var err error
+		if trace != nil {
+			shouldApply, err = bre.explainRuleCondition(ctx, rule, data, trace)
+		} else {
+			shouldApply, err = bre.evaluateRuleCondition(ctx, rule, data)
+		}
 		if err != nil {
+			trace.fail(err)
 			collector.AddValidationError(rule.ID, "condition_evaluation_failed", err.Error())
 			continue
 		}
 		if shouldApply {
-			if err := bre.applyRuleActions(ctx, rule, modifiedSchema, data); err != nil {
+			trace.markApplied()
+			if err := bre.applyRuleActions(ctx, rule, modifiedSchema, data, trace); err != nil {
 				if schemaErr, ok := err.(SchemaError); ok {
 					collector.AddFieldError(rule.ID, schemaErr)
 				} else {
@@ -286,9 +300,13 @@ func (bre *BusinessRuleEngine) getApplicableRules() []*BusinessRule {
 	for _, rule := range bre.rules {
 		rules = append(rules, rule)
 	}
-	// Sort by priority (higher priority first)
+	// Sort by priority (higher priority first), then by ID so equal
+	// priorities apply in a stable order
 	sort.Slice(rules, func(i, j int) bool {
-		return rules[i].Priority > rules[j].Priority
+		if rules[i].Priority != rules[j].Priority {
+			return rules[i].Priority > rules[j].Priority
+		}
+		return rules[i].ID < rules[j].ID
 	})
 	return rules
 }
@@ -303,10 +321,12 @@ func (bre *BusinessRuleEngine) evaluateRuleCondition(ctx context.Context, rule *
 }
 
 // applyRuleActions applies all actions for a rule
-func (bre *BusinessRuleEngine) applyRuleActions(ctx context.Context, rule *BusinessRule, schema *Schema, data map[string]any) error {
+func (bre *BusinessRuleEngine) applyRuleActions(ctx context.Context, rule *BusinessRule, schema *Schema, data map[string]any, trace *RuleTrace) error {
 	collector := NewErrorCollector()
 	for i, action := range rule.Actions {
-		if err := bre.applyAction(ctx, action, schema, data); err != nil {
+		err := bre.applyAction(ctx, action, schema, data)
+		trace.addAction(action, schema, err)
+		if err != nil {
 			collector.AddValidationError(
 				fmt.Sprintf("rule_%s_action_%d", rule.ID, i),
 				"action_failed",
@@ -406,37 +426,20 @@ func (bre *BusinessRuleEngine) calculateFieldValue(ctx context.Context, schema *
 			}
 			schema.Fields[i].Config["formula"] = formula
 			schema.Fields[i].Config["calculated"] = true
-			// Simple formula evaluation for testing
-			// TODO: Replace with proper expression evaluator
-			var calcResult any
-			switch formula {
-			case "quantity * unit_price":
-				qty, qtyOk := data["quantity"]
-				price, priceOk := data["unit_price"]
-				if !qtyOk || !priceOk {
-					return NewValidationError("formula_evaluation_failed", "missing required variables quantity or unit_price")
-				}
-				qtyFloat, qtyIsNum := qty.(float64)
-				priceFloat, priceIsNum := price.(float64)
-				// Try int conversion if float64 didn't work
-				if !qtyIsNum {
-					if qtyInt, ok := qty.(int); ok {
-						qtyFloat = float64(qtyInt)
-						qtyIsNum = true
-					}
-				}
-				if !priceIsNum {
-					if priceInt, ok := price.(int); ok {
-						priceFloat = float64(priceInt)
-						priceIsNum = true
-					}
-				}
-				if !qtyIsNum || !priceIsNum {
-					return NewValidationError("formula_evaluation_failed", "variables must be numeric")
+			field := &schema.Fields[i]
+			resultType := field.formulaResultType()
+			if rt, ok := params["resultType"].(string); ok && rt != "" {
+				resultType = FormulaResultType(FieldType(rt))
+			}
+			evalCtx := condition.NewEvalContext(data, condition.DefaultEvalOptions())
+			for _, f := range schema.FormulaFields() {
+				if err := evalCtx.RegisterField(f); err != nil {
+					return formulaError(fieldName, err)
 				}
-				calcResult = qtyFloat * priceFloat
-			default:
-				return NewValidationError("formula_evaluation_failed", fmt.Sprintf("unsupported formula: %s", formula))
+			}
+			calcResult, err := bre.evaluator.EvaluateFormula(ctx, formula, resultType, evalCtx)
+			if err != nil {
+				return formulaError(fieldName, err)
 			}
 			// Set the calculated value as the field default
 			schema.Fields[i].Default = calcResult
@@ -485,11 +488,15 @@ func (bre *BusinessRuleEngine) ExplainRule(ctx context.Context, rule *BusinessRu
 	explanation += fmt.Sprintf("Type: %s\n", rule.Type)
 	explanation += fmt.Sprintf("Priority: %d\n", rule.Priority)
 	explanation += fmt.Sprintf("Enabled: %v\n", rule.Enabled)
-	shouldApply, err := bre.evaluateRuleCondition(ctx, rule, data)
+	trace := &RuleTrace{}
+	shouldApply, err := bre.explainRuleCondition(ctx, rule, data, trace)
 	if err != nil {
 		return "", err
 	}
 	explanation += fmt.Sprintf("Condition Met: %v\n", shouldApply)
+	if trace.Condition != nil {
+		explanation += "Condition Trace:\n" + indentLines(trace.Condition.String(), "  ")
+	}
 	explanation += fmt.Sprintf("Actions: %d\n", len(rule.Actions))
 	for i, action := range rule.Actions {
 		explanation += fmt.Sprintf("  Action %d: %s on %s\n", i+1, action.Type, action.Target)
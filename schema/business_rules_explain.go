@@ -0,0 +1,258 @@
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/niiniyare/ruun/pkg/condition"
+)
+
+// RulesExplanation records how business rules produced a schema's state
+type RulesExplanation struct {
+	// Rules in the order the engine considered them
+	Rules []*RuleTrace `json:"rules"`
+	// Decisions holds the final state of every target a rule changed
+	Decisions []StateDecision `json:"decisions,omitempty"`
+}
+
+// RuleTrace records the evaluation of a single business rule
+type RuleTrace struct {
+	RuleID    string                 `json:"ruleId"`
+	Name      string                 `json:"name"`
+	Type      BusinessRuleType       `json:"type"`
+	Priority  int                    `json:"priority"`
+	Enabled   bool                   `json:"enabled"`
+	Applied   bool                   `json:"applied"`
+	Condition *condition.Explanation `json:"condition,omitempty"`
+	Actions   []ActionTrace          `json:"actions,omitempty"`
+	Error     string                 `json:"error,omitempty"`
+}
+
+// ActionTrace records one applied action
+type ActionTrace struct {
+	Type   BusinessRuleActionType `json:"type"`
+	Target string                 `json:"target,omitempty"`
+	Value  any                    `json:"value,omitempty"`
+	Error  string                 `json:"error,omitempty"`
+}
+
+// StateDecision is the rule that last set a property of a field or action
+type StateDecision struct {
+	// Target is a field name or action ID
+	Target   string `json:"target"`
+	Property string `json:"property"`
+	Value    any    `json:"value"`
+	RuleID   string `json:"ruleId"`
+	// Condition is the condition rule that decided the business rule's
+	// condition, empty for unconditional rules
+	Condition string                 `json:"condition,omitempty"`
+	Action    BusinessRuleActionType `json:"action"`
+}
+
+// ExplainRules applies the rules like ApplyRules and reports, for every
+// rule, its condition trace and actions, and for every target the rule that
+// decided its final state. The explanation is returned together with the
+// errors ApplyRules would return.
+func (bre *BusinessRuleEngine) ExplainRules(ctx context.Context, schema *Schema, data map[string]any) (*RulesExplanation, error) {
+	explain := &RulesExplanation{}
+	_, err := bre.applyRules(ctx, schema, data, explain)
+	explain.decide()
+	return explain, err
+}
+
+// Decision returns the decision for a target property such as "visible",
+// "required", "enabled", "default" or "options"
+func (x *RulesExplanation) Decision(target, property string) (StateDecision, bool) {
+	for _, decision := range x.Decisions {
+		if decision.Target == target && decision.Property == property {
+			return decision, true
+		}
+	}
+	return StateDecision{}, false
+}
+
+// JSON renders the explanation as indented JSON
+func (x *RulesExplanation) JSON() ([]byte, error) {
+	return json.MarshalIndent(x, "", "  ")
+}
+
+// String renders the explanation for support staff
+func (x *RulesExplanation) String() string {
+	var b strings.Builder
+	b.WriteString("Decisions:\n")
+	if len(x.Decisions) == 0 {
+		b.WriteString("  none\n")
+	}
+	for _, d := range x.Decisions {
+		fmt.Fprintf(&b, "  %s.%s = %s by rule %s (%s)", d.Target, d.Property, formatExplainValue(d.Value), d.RuleID, d.Action)
+		if d.Condition != "" {
+			fmt.Fprintf(&b, " via %s", d.Condition)
+		}
+		b.WriteByte('\n')
+	}
+
+	b.WriteString("Rules:\n")
+	for _, rule := range x.Rules {
+		status := "not applied"
+		switch {
+		case !rule.Enabled:
+			status = "disabled"
+		case rule.Error != "":
+			status = "error: " + rule.Error
+		case rule.Applied:
+			status = "applied"
+		}
+		fmt.Fprintf(&b, "  %s %q (priority %d): %s\n", rule.RuleID, rule.Name, rule.Priority, status)
+		if rule.Condition != nil && rule.Condition.Trace != nil {
+			b.WriteString(indentLines(rule.Condition.String(), "    "))
+		}
+		for _, action := range rule.Actions {
+			fmt.Fprintf(&b, "    action %s on %s", action.Type, action.Target)
+			if action.Error != "" {
+				fmt.Fprintf(&b, ": error: %s", action.Error)
+			}
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+// addRule starts the trace of a rule; it is a no-op on a nil explanation
+func (x *RulesExplanation) addRule(rule *BusinessRule) *RuleTrace {
+	if x == nil {
+		return nil
+	}
+	trace := &RuleTrace{
+		RuleID:   rule.ID,
+		Name:     rule.Name,
+		Type:     rule.Type,
+		Priority: rule.Priority,
+		Enabled:  rule.Enabled,
+	}
+	x.Rules = append(x.Rules, trace)
+	return trace
+}
+
+// decide derives the final state of each target. Rules are applied in
+// order, so the last successful action on a property wins.
+func (x *RulesExplanation) decide() {
+	decisions := make(map[string]StateDecision)
+	for _, rule := range x.Rules {
+		if !rule.Applied {
+			continue
+		}
+		var decidedBy string
+		if rule.Condition != nil {
+			decidedBy = rule.Condition.DecidedBy
+		}
+		for _, action := range rule.Actions {
+			property, value, ok := actionState(action)
+			if !ok || action.Error != "" {
+				continue
+			}
+			decisions[action.Target+"\x00"+property] = StateDecision{
+				Target:    action.Target,
+				Property:  property,
+				Value:     value,
+				RuleID:    rule.RuleID,
+				Condition: decidedBy,
+				Action:    action.Type,
+			}
+		}
+	}
+
+	x.Decisions = make([]StateDecision, 0, len(decisions))
+	for _, decision := range decisions {
+		x.Decisions = append(x.Decisions, decision)
+	}
+	sort.Slice(x.Decisions, func(i, j int) bool {
+		if x.Decisions[i].Target != x.Decisions[j].Target {
+			return x.Decisions[i].Target < x.Decisions[j].Target
+		}
+		return x.Decisions[i].Property < x.Decisions[j].Property
+	})
+}
+
+// actionState maps an action to the property it sets
+func actionState(action ActionTrace) (string, any, bool) {
+	switch action.Type {
+	case ActionShowField, ActionShowButton:
+		return "visible", true, true
+	case ActionHideField, ActionHideButton:
+		return "visible", false, true
+	case ActionRequireField:
+		return "required", true, true
+	case ActionOptionalField:
+		return "required", false, true
+	case ActionEnableButton:
+		return "enabled", true, true
+	case ActionDisableButton:
+		return "enabled", false, true
+	case ActionSetDefault, ActionCalculate:
+		return "default", action.Value, true
+	case ActionSetOptions:
+		return "options", action.Value, true
+	}
+	return "", nil, false
+}
+
+// explainRuleCondition evaluates a rule's condition and records its trace
+func (bre *BusinessRuleEngine) explainRuleCondition(ctx context.Context, rule *BusinessRule, data map[string]any, trace *RuleTrace) (bool, error) {
+	if rule.Condition == nil {
+		return true, nil
+	}
+	evalCtx := condition.NewEvalContext(data, condition.DefaultEvalOptions())
+	explanation, err := bre.evaluator.Explain(ctx, rule.Condition, evalCtx)
+	trace.Condition = explanation
+	return explanation.Result, err
+}
+
+func (t *RuleTrace) fail(err error) {
+	if t != nil {
+		t.Error = err.Error()
+	}
+}
+
+func (t *RuleTrace) markApplied() {
+	if t != nil {
+		t.Applied = true
+	}
+}
+
+// addAction records an action and, for calculations, the computed value
+func (t *RuleTrace) addAction(action BusinessRuleAction, schema *Schema, err error) {
+	if t == nil {
+		return
+	}
+	trace := ActionTrace{Type: action.Type, Target: action.Target, Value: action.Value}
+	if err != nil {
+		trace.Error = err.Error()
+	} else if action.Type == ActionCalculate {
+		if field, ok := schema.GetField(action.Target); ok {
+			trace.Value = field.Default
+		}
+	}
+	t.Actions = append(t.Actions, trace)
+}
+
+func formatExplainValue(v any) string {
+	if data, err := json.Marshal(v); err == nil {
+		return string(data)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// indentLines prefixes every line of s
+func indentLines(s, prefix string) string {
+	lines := strings.SplitAfter(s, "\n")
+	var b strings.Builder
+	for _, line := range lines {
+		if line != "" {
+			b.WriteString(prefix + line)
+		}
+	}
+	return b.String()
+}
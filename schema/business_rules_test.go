@@ -472,6 +472,7 @@ func (suite *BusinessRulesTestSuite) TestCalculationActions() {
 	})
 	require.NoError(suite.T(), err)
 	require.True(suite.T(), modifiedSchema.Fields[2].Readonly) // Calculated fields should be readonly
+	require.InDelta(suite.T(), 59.9, modifiedSchema.Fields[2].Default, 1e-9)
 }
 
 // Test disabled rules
@@ -879,6 +880,8 @@ func (suite *BusinessRulesTestSuite) TestExplainRule() {
 	require.Contains(suite.T(), explanation, "Priority: 10")
 	require.Contains(suite.T(), explanation, "Enabled: true")
 	require.Contains(suite.T(), explanation, "Condition Met: true")
+	require.Contains(suite.T(), explanation, "Condition Trace:")
+	require.Contains(suite.T(), explanation, `> [true] rule status_check: status="active" equal "active"`)
 	require.Contains(suite.T(), explanation, "Actions: 2")
 	require.Contains(suite.T(), explanation, "Action 1: show_field on field1")
 	require.Contains(suite.T(), explanation, "Action 2: hide_field on field2")
@@ -921,6 +924,88 @@ func (suite *BusinessRulesTestSuite) TestExplainRule() {
 	require.Contains(suite.T(), explanation, "Actions: 0")
 }
 
+func explainRule(id, field string, op condition.OperatorType, value any) *condition.ConditionRule {
+	return &condition.ConditionRule{
+		ID:    id,
+		Left:  condition.Expression{Type: condition.ValueTypeField, Field: field},
+		Op:    op,
+		Right: value,
+	}
+}
+
+func explainGroup(conjunction condition.Conjunction, rules ...*condition.ConditionRule) *condition.ConditionGroup {
+	group := condition.NewBuilder(conjunction).Build()
+	for _, rule := range rules {
+		group.Children = append(group.Children, rule)
+	}
+	return group
+}
+
+// Test ExplainRules method
+func (suite *BusinessRulesTestSuite) TestExplainRules() {
+	schema := &Schema{
+		ID:    "order_form",
+		Title: "Order",
+		Fields: []Field{
+			{Name: "discount", Type: FieldNumber},
+			{Name: "total", Type: FieldNumber},
+		},
+		Actions: []Action{{ID: "submit", Type: ActionSubmit, Text: "Submit"}},
+	}
+	showDiscount, err := CreateFieldVisibilityRule("show_discount", "discount",
+		explainGroup(condition.ConjunctionOr,
+			explainRule("is_vip", "tier", condition.OpEqual, "vip"),
+			explainRule("is_large", "total", condition.OpGreater, 1000)), true)
+	require.NoError(suite.T(), err)
+	showDiscount.Priority = 10
+	hideDiscount, err := CreateFieldVisibilityRule("hide_discount", "discount",
+		explainGroup(condition.ConjunctionAnd,
+			explainRule("is_draft", "status", condition.OpEqual, "draft")), false)
+	require.NoError(suite.T(), err)
+	hideDiscount.Priority = 5
+	lockSubmit := &BusinessRule{
+		ID: "lock_submit", Name: "Lock submit", Type: RuleTypeActionEnabled, Priority: 1, Enabled: true,
+		Condition: explainGroup(condition.ConjunctionAnd,
+			explainRule("no_total", "total", condition.OpLessOrEqual, 0)),
+		Actions: []BusinessRuleAction{{Type: ActionDisableButton, Target: "submit"}},
+	}
+	for _, rule := range []*BusinessRule{showDiscount, hideDiscount, lockSubmit} {
+		require.NoError(suite.T(), suite.engine.AddRule(rule))
+	}
+
+	data := map[string]any{"tier": "basic", "total": 1500, "status": "draft"}
+	explanation, err := suite.engine.ExplainRules(suite.ctx, schema, data)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), explanation.Rules, 3)
+	require.Equal(suite.T(), "show_discount", explanation.Rules[0].RuleID)
+	require.True(suite.T(), explanation.Rules[0].Applied)
+	require.Equal(suite.T(), "is_large", explanation.Rules[0].Condition.DecidedBy)
+	require.False(suite.T(), explanation.Rules[2].Applied)
+
+	// The lower priority rule runs later and wins
+	decision, ok := explanation.Decision("discount", "visible")
+	require.True(suite.T(), ok)
+	require.Equal(suite.T(), false, decision.Value)
+	require.Equal(suite.T(), "hide_discount", decision.RuleID)
+	require.Equal(suite.T(), "is_draft", decision.Condition)
+	_, ok = explanation.Decision("submit", "enabled")
+	require.False(suite.T(), ok)
+
+	// The explanation matches what ApplyRules does
+	applied, err := suite.engine.ApplyRules(suite.ctx, schema, data)
+	require.NoError(suite.T(), err)
+	require.True(suite.T(), applied.Fields[0].Hidden)
+
+	text := explanation.String()
+	require.Contains(suite.T(), text, `discount.visible = false by rule hide_discount (hide_field) via is_draft`)
+	require.Contains(suite.T(), text, `lock_submit "Lock submit" (priority 1): not applied`)
+	require.Contains(suite.T(), text, `> [false] rule no_total: total=1500 less_or_equal 0`)
+
+	encoded, err := explanation.JSON()
+	require.NoError(suite.T(), err)
+	require.Contains(suite.T(), string(encoded), `"decidedBy": "is_large"`)
+}
+
 // Test UpdateRule method
 func (suite *BusinessRulesTestSuite) TestUpdateRule() {
 	// First add a rule to update
@@ -2,6 +2,7 @@ package schema
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/niiniyare/ruun/pkg/condition"
 )
@@ -41,6 +42,63 @@ func (c *Conditional) HasStateConditions() bool {
 	return c != nil && (c.Required != nil || c.Disabled != nil || c.Readonly != nil)
 }
 
+// AlpineBindings compiles the visibility and state conditions into Alpine.js
+// attribute bindings ("x-show", ":required", ":disabled", ":readonly") so
+// they can be re-evaluated in the browser without a server round-trip.
+// The page must include condition.JSRuntime. As on the server, a condition
+// that fails to evaluate hides the component and leaves states off.
+func (c *Conditional) AlpineBindings(opts condition.JSOptions) (map[string]string, error) {
+	bindings := make(map[string]string)
+	if c == nil {
+		return bindings, nil
+	}
+
+	compiler, err := condition.NewJSCompiler(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.HasVisibility() {
+		var parts []string
+		if c.Hide != nil {
+			hide, err := compiler.Compile(c.Hide)
+			if err != nil {
+				return nil, fmt.Errorf("compile hide condition: %w", err)
+			}
+			parts = append(parts, "!("+hide+")")
+		}
+		if c.Show != nil {
+			show, err := compiler.Compile(c.Show)
+			if err != nil {
+				return nil, fmt.Errorf("compile show condition: %w", err)
+			}
+			parts = append(parts, "("+show+")")
+		}
+		bindings["x-show"] = condition.JSRuntimeName + ".test(() => " + strings.Join(parts, " && ") + ")"
+	}
+
+	states := []struct {
+		attr string
+		cond *condition.ConditionGroup
+	}{
+		{":required", c.Required},
+		{":disabled", c.Disabled},
+		{":readonly", c.Readonly},
+	}
+	for _, state := range states {
+		if state.cond == nil {
+			continue
+		}
+		predicate, err := compiler.Predicate(state.cond)
+		if err != nil {
+			return nil, fmt.Errorf("compile %s condition: %w", strings.TrimPrefix(state.attr, ":"), err)
+		}
+		bindings[state.attr] = predicate
+	}
+
+	return bindings, nil
+}
+
 // ConditionalBuilder for fluent construction
 type ConditionalBuilder struct {
 	cond Conditional
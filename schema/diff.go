@@ -0,0 +1,726 @@
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ChangeSeverity classifies a schema change by its effect on stored data
+type ChangeSeverity string
+
+const (
+	// ChangeBreaking means data valid under the old schema may be rejected or lost
+	ChangeBreaking ChangeSeverity = "breaking"
+	// ChangeCompatible alters behavior but keeps existing data valid
+	ChangeCompatible ChangeSeverity = "compatible"
+	// ChangeCosmetic only affects presentation
+	ChangeCosmetic ChangeSeverity = "cosmetic"
+)
+
+// severityRank orders severities from least to most severe
+var severityRank = map[ChangeSeverity]int{
+	ChangeCosmetic:   1,
+	ChangeCompatible: 2,
+	ChangeBreaking:   3,
+}
+
+// ChangeKind identifies what changed between two schemas
+type ChangeKind string
+
+const (
+	ChangeFieldAdded          ChangeKind = "field_added"
+	ChangeFieldRemoved        ChangeKind = "field_removed"
+	ChangeFieldRetyped        ChangeKind = "field_retyped"
+	ChangeFieldChanged        ChangeKind = "field_changed"
+	ChangeValidationTightened ChangeKind = "validation_tightened"
+	ChangeValidationLoosened  ChangeKind = "validation_loosened"
+	ChangeOptionAdded         ChangeKind = "option_added"
+	ChangeOptionRemoved       ChangeKind = "option_removed"
+	ChangeOptionChanged       ChangeKind = "option_changed"
+	ChangeLayoutChanged       ChangeKind = "layout_changed"
+	ChangeActionAdded         ChangeKind = "action_added"
+	ChangeActionRemoved       ChangeKind = "action_removed"
+	ChangeActionChanged       ChangeKind = "action_changed"
+	ChangeSchemaChanged       ChangeKind = "schema_changed"
+)
+
+// SchemaChange is a single difference between two schemas
+type SchemaChange struct {
+	Kind     ChangeKind     `json:"kind"`
+	Severity ChangeSeverity `json:"severity"`
+	Path     string         `json:"path"`
+	Before   any            `json:"before,omitempty"`
+	After    any            `json:"after,omitempty"`
+	Message  string         `json:"message"`
+}
+
+// SchemaDiff lists the changes from one schema to another
+type SchemaDiff struct {
+	SchemaID    string         `json:"schemaId"`
+	FromVersion string         `json:"fromVersion,omitempty"`
+	ToVersion   string         `json:"toVersion,omitempty"`
+	Changes     []SchemaChange `json:"changes"`
+}
+
+// Severity returns the most severe change, or "" when nothing changed
+func (d *SchemaDiff) Severity() ChangeSeverity {
+	var severity ChangeSeverity
+	for _, change := range d.Changes {
+		if severityRank[change.Severity] > severityRank[severity] {
+			severity = change.Severity
+		}
+	}
+	return severity
+}
+
+// HasBreaking reports whether any change is breaking
+func (d *SchemaDiff) HasBreaking() bool {
+	return d.Severity() == ChangeBreaking
+}
+
+// Filter returns the changes with the given severity
+func (d *SchemaDiff) Filter(severity ChangeSeverity) []SchemaChange {
+	var changes []SchemaChange
+	for _, change := range d.Changes {
+		if change.Severity == severity {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// BreakingChangeError is returned by Registry.Update when an update contains
+// breaking changes and no migration was supplied
+type BreakingChangeError struct {
+	Diff *SchemaDiff
+}
+
+func (e *BreakingChangeError) Error() string {
+	breaking := e.Diff.Filter(ChangeBreaking)
+	messages := make([]string, 0, len(breaking))
+	for _, change := range breaking {
+		messages = append(messages, change.Message)
+	}
+	return fmt.Sprintf("schema %s has %d breaking change(s) without a migration: %s",
+		e.Diff.SchemaID, len(breaking), strings.Join(messages, "; "))
+}
+
+// MigrationFunc converts a submission from one schema version to the next
+type MigrationFunc func(ctx context.Context, data map[string]any) (map[string]any, error)
+
+// SchemaMigration converts submissions stored against one schema version so
+// they validate against the next. Migrations are persisted through the
+// registry's storage without their function; Name identifies the function,
+// which every node registers with Registry.RegisterMigrationFunc.
+type SchemaMigration struct {
+	Name        string        `json:"name"`
+	FromVersion string        `json:"fromVersion"`
+	ToVersion   string        `json:"toVersion"`
+	Description string        `json:"description,omitempty"`
+	Migrate     MigrationFunc `json:"-"`
+}
+
+// DiffSchemas compares two schemas and classifies every structural change
+func DiffSchemas(from, to *Schema) *SchemaDiff {
+	diff := &SchemaDiff{
+		SchemaID:    to.ID,
+		FromVersion: from.Version,
+		ToVersion:   to.Version,
+		Changes:     []SchemaChange{},
+	}
+	d := &differ{diff: diff}
+
+	d.compareCosmetic("title", from.Title, to.Title)
+	d.compareCosmetic("description", from.Description, to.Description)
+	d.compareFields(from.Fields, to.Fields)
+	d.compareActions(from.Actions, to.Actions)
+	if !jsonEqual(from.Layout, to.Layout) {
+		d.add(ChangeLayoutChanged, ChangeCosmetic, "layout", nil, nil, "layout changed")
+	}
+	if !jsonEqual(from.Validation, to.Validation) {
+		d.compareValidation("validation", from.Validation, to.Validation)
+	}
+
+	return diff
+}
+
+// differ accumulates changes into a diff
+type differ struct {
+	diff *SchemaDiff
+}
+
+func (d *differ) add(kind ChangeKind, severity ChangeSeverity, path string, before, after any, message string) {
+	d.diff.Changes = append(d.diff.Changes, SchemaChange{
+		Kind:     kind,
+		Severity: severity,
+		Path:     path,
+		Before:   before,
+		After:    after,
+		Message:  message,
+	})
+}
+
+func (d *differ) compareCosmetic(path string, before, after string) {
+	if before != after {
+		d.add(ChangeSchemaChanged, ChangeCosmetic, path, before, after, path+" changed")
+	}
+}
+
+func (d *differ) compareFields(before, after []Field) {
+	old := make(map[string]*Field, len(before))
+	for i := range before {
+		old[before[i].Name] = &before[i]
+	}
+	seen := make(map[string]bool, len(after))
+
+	for i := range after {
+		field := &after[i]
+		seen[field.Name] = true
+		path := "fields." + field.Name
+		previous, ok := old[field.Name]
+		if !ok {
+			severity := ChangeCompatible
+			message := fmt.Sprintf("field %s added", field.Name)
+			if isFieldRequired(field) && field.Default == nil {
+				severity = ChangeBreaking
+				message = fmt.Sprintf("required field %s added without a default", field.Name)
+			}
+			d.add(ChangeFieldAdded, severity, path, nil, field.Type, message)
+			continue
+		}
+		d.compareField(path, previous, field)
+	}
+
+	for i := range before {
+		if !seen[before[i].Name] {
+			d.add(ChangeFieldRemoved, ChangeBreaking, "fields."+before[i].Name, before[i].Type, nil,
+				fmt.Sprintf("field %s removed", before[i].Name))
+		}
+	}
+}
+
+func (d *differ) compareField(path string, before, after *Field) {
+	if before.Type != after.Type {
+		severity := ChangeBreaking
+		if fieldTypeWidens(before.Type, after.Type) {
+			severity = ChangeCompatible
+		}
+		d.add(ChangeFieldRetyped, severity, path+".type", before.Type, after.Type,
+			fmt.Sprintf("field %s changed type from %s to %s", after.Name, before.Type, after.Type))
+	}
+
+	switch wasRequired, isRequired := isFieldRequired(before), isFieldRequired(after); {
+	case !wasRequired && isRequired:
+		d.add(ChangeValidationTightened, ChangeBreaking, path+".required", false, true,
+			fmt.Sprintf("field %s became required", after.Name))
+	case wasRequired && !isRequired:
+		d.add(ChangeValidationLoosened, ChangeCompatible, path+".required", true, false,
+			fmt.Sprintf("field %s became optional", after.Name))
+	}
+
+	d.compareValidation(path+".validation", before.Validation, after.Validation)
+	d.compareOptions(path+".options", after.Name, before.Options, after.Options)
+
+	// Behavioral changes keep stored data valid
+	for name, pair := range map[string][2]any{
+		"default":     {before.Default, after.Default},
+		"hidden":      {before.Hidden, after.Hidden},
+		"disabled":    {before.Disabled, after.Disabled},
+		"readonly":    {before.Readonly, after.Readonly},
+		"conditional": {before.Conditional, after.Conditional},
+		"dataSource":  {before.DataSource, after.DataSource},
+		"transform":   {before.Transform, after.Transform},
+		"permission":  {before.RequirePermission, after.RequirePermission},
+		"roles":       {before.RequireRoles, after.RequireRoles},
+	} {
+		if !jsonEqual(pair[0], pair[1]) {
+			d.add(ChangeFieldChanged, ChangeCompatible, path+"."+name, pair[0], pair[1],
+				fmt.Sprintf("field %s %s changed", after.Name, name))
+		}
+	}
+
+	// Presentation changes
+	for name, pair := range map[string][2]any{
+		"label":       {before.Label, after.Label},
+		"description": {before.Description, after.Description},
+		"placeholder": {before.Placeholder, after.Placeholder},
+		"help":        {before.Help, after.Help},
+		"tooltip":     {before.Tooltip, after.Tooltip},
+		"icon":        {before.Icon, after.Icon},
+		"layout":      {before.Layout, after.Layout},
+		"style":       {before.Style, after.Style},
+		"i18n":        {before.I18n, after.I18n},
+	} {
+		if !jsonEqual(pair[0], pair[1]) {
+			kind := ChangeFieldChanged
+			if name == "layout" {
+				kind = ChangeLayoutChanged
+			}
+			d.add(kind, ChangeCosmetic, path+"."+name, pair[0], pair[1],
+				fmt.Sprintf("field %s %s changed", after.Name, name))
+		}
+	}
+}
+
+// compareValidation reports each tightened or loosened constraint. Adding a
+// constraint tightens; removing one loosens.
+func (d *differ) compareValidation(path string, before, after *FieldValidation) {
+	if before == nil {
+		before = &FieldValidation{}
+	}
+	if after == nil {
+		after = &FieldValidation{}
+	}
+
+	d.compareLowerBound(path+".minLength", intValue(before.MinLength), intValue(after.MinLength))
+	d.compareUpperBound(path+".maxLength", intValue(before.MaxLength), intValue(after.MaxLength))
+	d.compareLowerBound(path+".min", before.Min, after.Min)
+	d.compareUpperBound(path+".max", before.Max, after.Max)
+	d.compareLowerBound(path+".minItems", intValue(before.MinItems), intValue(after.MinItems))
+	d.compareUpperBound(path+".maxItems", intValue(before.MaxItems), intValue(after.MaxItems))
+	d.compareUpperBound(path+".maxFileSize", int64Value(before.MaxFileSize), int64Value(after.MaxFileSize))
+	if !jsonEqual(before.Step, after.Step) {
+		d.compareConstraint(path+".step", before.Step == nil, after.Step == nil, before.Step, after.Step)
+	}
+
+	d.compareConstraint(path+".pattern", before.Pattern == "", after.Pattern == "", before.Pattern, after.Pattern)
+	d.compareConstraint(path+".format", before.Format == "", after.Format == "", before.Format, after.Format)
+	customBefore, customAfter := before.CustomFunction+before.Custom, after.CustomFunction+after.Custom
+	d.compareConstraint(path+".custom", customBefore == "", customAfter == "", customBefore, customAfter)
+
+	d.compareFlag(path+".integer", before.Integer, after.Integer)
+	d.compareFlag(path+".positive", before.Positive, after.Positive)
+	d.compareFlag(path+".unique", before.Unique, after.Unique)
+
+	// An empty accepted-types list accepts anything
+	switch {
+	case len(before.AcceptedTypes) == 0 && len(after.AcceptedTypes) > 0:
+		d.tightened(path+".acceptedTypes", nil, after.AcceptedTypes)
+	case len(before.AcceptedTypes) > 0 && len(after.AcceptedTypes) == 0:
+		d.loosened(path+".acceptedTypes", before.AcceptedTypes, nil)
+	default:
+		for _, accepted := range before.AcceptedTypes {
+			if !slices.Contains(after.AcceptedTypes, accepted) {
+				d.tightened(path+".acceptedTypes", accepted, nil)
+			}
+		}
+		for _, accepted := range after.AcceptedTypes {
+			if !slices.Contains(before.AcceptedTypes, accepted) {
+				d.loosened(path+".acceptedTypes", nil, accepted)
+			}
+		}
+	}
+
+	rules := make(map[string]ValidationRule, len(before.Rules))
+	for _, rule := range before.Rules {
+		rules[rule.Name] = rule
+	}
+	for _, rule := range after.Rules {
+		previous, ok := rules[rule.Name]
+		delete(rules, rule.Name)
+		switch {
+		case !ok:
+			d.tightened(path+".rules."+rule.Name, nil, rule.Name)
+		case !jsonEqual(previous.Params, rule.Params) || previous.Condition != rule.Condition || previous.CustomFunc != rule.CustomFunc:
+			d.tightened(path+".rules."+rule.Name, previous.Params, rule.Params)
+		case previous.Message != rule.Message:
+			d.add(ChangeFieldChanged, ChangeCosmetic, path+".rules."+rule.Name+".message", previous.Message, rule.Message, "validation message changed")
+		}
+	}
+	for name := range rules {
+		d.loosened(path+".rules."+name, name, nil)
+	}
+
+	if !jsonEqual(before.Messages, after.Messages) {
+		d.add(ChangeFieldChanged, ChangeCosmetic, path+".messages", before.Messages, after.Messages, "validation messages changed")
+	}
+}
+
+func (d *differ) tightened(path string, before, after any) {
+	d.add(ChangeValidationTightened, ChangeBreaking, path, before, after, path+" tightened")
+}
+
+func (d *differ) loosened(path string, before, after any) {
+	d.add(ChangeValidationLoosened, ChangeCompatible, path, before, after, path+" loosened")
+}
+
+// compareLowerBound treats a higher or newly set minimum as tightening
+func (d *differ) compareLowerBound(path string, before, after *float64) {
+	switch {
+	case before == nil && after == nil:
+	case before == nil:
+		d.tightened(path, nil, *after)
+	case after == nil:
+		d.loosened(path, *before, nil)
+	case *after > *before:
+		d.tightened(path, *before, *after)
+	case *after < *before:
+		d.loosened(path, *before, *after)
+	}
+}
+
+// compareUpperBound treats a lower or newly set maximum as tightening
+func (d *differ) compareUpperBound(path string, before, after *float64) {
+	switch {
+	case before == nil && after == nil:
+	case before == nil:
+		d.tightened(path, nil, *after)
+	case after == nil:
+		d.loosened(path, *before, nil)
+	case *after < *before:
+		d.tightened(path, *before, *after)
+	case *after > *before:
+		d.loosened(path, *before, *after)
+	}
+}
+
+// compareConstraint handles constraints whose values cannot be ordered: any
+// new or changed value tightens, removing the value loosens
+func (d *differ) compareConstraint(path string, beforeEmpty, afterEmpty bool, before, after any) {
+	switch {
+	case beforeEmpty && afterEmpty:
+	case afterEmpty:
+		d.loosened(path, before, nil)
+	case beforeEmpty || !jsonEqual(before, after):
+		d.tightened(path, before, after)
+	}
+}
+
+func (d *differ) compareFlag(path string, before, after bool) {
+	switch {
+	case !before && after:
+		d.tightened(path, false, true)
+	case before && !after:
+		d.loosened(path, true, false)
+	}
+}
+
+func (d *differ) compareOptions(path, field string, before, after []FieldOption) {
+	old := flattenOptions(before)
+	current := flattenOptions(after)
+	for key, option := range old {
+		next, ok := current[key]
+		switch {
+		case !ok:
+			d.add(ChangeOptionRemoved, ChangeBreaking, path, option.Value, nil,
+				fmt.Sprintf("option %v removed from field %s", option.Value, field))
+		case option.Disabled != next.Disabled:
+			d.add(ChangeOptionChanged, ChangeCompatible, path, option.Value, next.Value,
+				fmt.Sprintf("option %v of field %s enabled state changed", option.Value, field))
+		case option.Label != next.Label || option.Icon != next.Icon || option.Group != next.Group:
+			d.add(ChangeOptionChanged, ChangeCosmetic, path, option.Label, next.Label,
+				fmt.Sprintf("option %v of field %s relabeled", option.Value, field))
+		}
+	}
+	for key, option := range current {
+		if _, ok := old[key]; !ok {
+			d.add(ChangeOptionAdded, ChangeCompatible, path, nil, option.Value,
+				fmt.Sprintf("option %v added to field %s", option.Value, field))
+		}
+	}
+}
+
+func (d *differ) compareActions(before, after []Action) {
+	old := make(map[string]*Action, len(before))
+	for i := range before {
+		old[before[i].ID] = &before[i]
+	}
+	seen := make(map[string]bool, len(after))
+
+	for i := range after {
+		action := &after[i]
+		seen[action.ID] = true
+		path := "actions." + action.ID
+		previous, ok := old[action.ID]
+		if !ok {
+			d.add(ChangeActionAdded, ChangeCompatible, path, nil, action.Type, fmt.Sprintf("action %s added", action.ID))
+			continue
+		}
+		if previous.Type != action.Type {
+			d.add(ChangeActionChanged, ChangeBreaking, path+".type", previous.Type, action.Type,
+				fmt.Sprintf("action %s changed type from %s to %s", action.ID, previous.Type, action.Type))
+		}
+		for name, pair := range map[string][2]any{
+			"behavior":    {previous.Behavior, action.Behavior},
+			"binding":     {previous.Binding, action.Binding},
+			"conditional": {previous.Conditional, action.Conditional},
+			"permissions": {previous.Permissions, action.Permissions},
+			"confirm":     {previous.Confirm, action.Confirm},
+			"config":      {previous.Config, action.Config},
+			"disabled":    {previous.Disabled, action.Disabled},
+			"hidden":      {previous.Hidden, action.Hidden},
+		} {
+			if !jsonEqual(pair[0], pair[1]) {
+				d.add(ChangeActionChanged, ChangeCompatible, path+"."+name, pair[0], pair[1],
+					fmt.Sprintf("action %s %s changed", action.ID, name))
+			}
+		}
+		for name, pair := range map[string][2]any{
+			"text":     {previous.Text, action.Text},
+			"variant":  {previous.Variant, action.Variant},
+			"size":     {previous.Size, action.Size},
+			"icon":     {previous.Icon, action.Icon},
+			"position": {previous.Position, action.Position},
+			"style":    {previous.Style, action.Style},
+		} {
+			if !jsonEqual(pair[0], pair[1]) {
+				d.add(ChangeActionChanged, ChangeCosmetic, path+"."+name, pair[0], pair[1],
+					fmt.Sprintf("action %s %s changed", action.ID, name))
+			}
+		}
+	}
+
+	for i := range before {
+		if !seen[before[i].ID] {
+			d.add(ChangeActionRemoved, ChangeBreaking, "actions."+before[i].ID, before[i].Type, nil,
+				fmt.Sprintf("action %s removed", before[i].ID))
+		}
+	}
+}
+
+// isFieldRequired reports whether a field must have a value
+func isFieldRequired(field *Field) bool {
+	return field.Required || (field.Validation != nil && field.Validation.Required)
+}
+
+// stringFieldTypes store their values as plain strings
+var stringFieldTypes = []FieldType{FieldText, FieldTextarea, FieldRichText, FieldEmail, FieldURL, FieldPhone, FieldPassword, FieldSelect, FieldRadio}
+
+// numericFieldTypes store their values as numbers
+var numericFieldTypes = []FieldType{FieldNumber, FieldCurrency, FieldSlider, FieldRating}
+
+// fieldTypeWidens reports whether every value valid for one field type stays
+// valid for another, e.g. email to text
+func fieldTypeWidens(from, to FieldType) bool {
+	switch {
+	case slices.Contains(stringFieldTypes, from):
+		return to == FieldText || to == FieldTextarea || to == FieldRichText
+	case slices.Contains(numericFieldTypes, from):
+		return to == FieldNumber || to == FieldCurrency
+	}
+	return false
+}
+
+// flattenOptions indexes options and their children by value
+func flattenOptions(options []FieldOption) map[string]FieldOption {
+	result := make(map[string]FieldOption)
+	var walk func([]FieldOption)
+	walk = func(options []FieldOption) {
+		for _, option := range options {
+			result[fmt.Sprint(option.Value)] = option
+			walk(option.Children)
+		}
+	}
+	walk(options)
+	return result
+}
+
+func intValue(v *int) *float64 {
+	if v == nil {
+		return nil
+	}
+	f := float64(*v)
+	return &f
+}
+
+func int64Value(v int64) *float64 {
+	if v == 0 {
+		return nil
+	}
+	f := float64(v)
+	return &f
+}
+
+// jsonEqual compares values by their JSON encoding, treating nil and empty
+// values alike
+func jsonEqual(a, b any) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return normalizeJSON(ja) == normalizeJSON(jb)
+}
+
+// normalizeJSON maps null and empty collections to "". Scalars such as false,
+// 0 and "" are real values, so they stay distinct from unset.
+func normalizeJSON(data []byte) string {
+	switch s := string(data); s {
+	case "null", "{}", "[]":
+		return ""
+	default:
+		return s
+	}
+}
+
+// UpdateOption configures Registry.Update
+type UpdateOption func(*updateOptions)
+
+type updateOptions struct {
+	migration *SchemaMigration
+}
+
+// WithMigration supplies the migration that accompanies a breaking update.
+// Empty versions default to the stored and new schema versions.
+func WithMigration(migration *SchemaMigration) UpdateOption {
+	return func(o *updateOptions) {
+		o.migration = migration
+	}
+}
+
+// Diff compares the stored schema with a candidate replacement
+func (r *Registry) Diff(ctx context.Context, schema *Schema) (*SchemaDiff, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.diffStored(ctx, schema)
+}
+
+func (r *Registry) diffStored(ctx context.Context, schema *Schema) (*SchemaDiff, error) {
+	var current Schema
+	if err := r.decodeStored(ctx, schema.ID, &current); err != nil {
+		return nil, err
+	}
+	return DiffSchemas(&current, schema), nil
+}
+
+// decodeStored decodes the stored schema without parsing it, so its version
+// is not migrated
+func (r *Registry) decodeStored(ctx context.Context, id string, schema *Schema) error {
+	data, err := r.storage.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("storage get: %w", err)
+	}
+	if err := json.Unmarshal(data, schema); err != nil {
+		return fmt.Errorf("unmarshal schema: %w", err)
+	}
+	return nil
+}
+
+// checkBreakingChanges refuses breaking updates without a migration and fills
+// in the migration's versions
+func (r *Registry) checkBreakingChanges(ctx context.Context, schema *Schema, migration *SchemaMigration) error {
+	diff, err := r.diffStored(ctx, schema)
+	if err != nil {
+		return err
+	}
+	if migration == nil {
+		if diff.HasBreaking() {
+			return &BreakingChangeError{Diff: diff}
+		}
+		return nil
+	}
+	if migration.Name == "" {
+		return fmt.Errorf("migration for schema %s has no name", schema.ID)
+	}
+	if migration.Migrate == nil && r.migrationFuncs[migration.Name] == nil {
+		return fmt.Errorf("%w: %s has no Migrate function and none is registered", ErrMigrationNotFound, migration.Name)
+	}
+	if migration.FromVersion == "" {
+		migration.FromVersion = diff.FromVersion
+	}
+	if migration.ToVersion == "" {
+		migration.ToVersion = diff.ToVersion
+	}
+	return nil
+}
+
+// RegisterMigrationFunc registers the function of a named migration. Every
+// node that migrates data must register the functions of the migrations
+// stored for its schemas, typically at startup.
+func (r *Registry) RegisterMigrationFunc(name string, fn MigrationFunc) error {
+	if name == "" || fn == nil {
+		return fmt.Errorf("migration function requires a name and a function")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.migrationFuncs[name] = fn
+	return nil
+}
+
+// GetMigrations returns the migrations recorded for a schema, oldest first.
+// Migrations whose function is not registered on this node have a nil Migrate.
+func (r *Registry) GetMigrations(ctx context.Context, id string) ([]*SchemaMigration, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.loadMigrations(ctx, id)
+}
+
+// MigrateData applies every recorded migration from fromVersion onwards to
+// data submitted against that version. Data already at the stored schema's
+// version is returned unchanged; any other version without a migration is an
+// error.
+func (r *Registry) MigrateData(ctx context.Context, id, fromVersion string, data map[string]any) (map[string]any, error) {
+	r.mu.RLock()
+	migrations, err := r.loadMigrations(ctx, id)
+	var current Schema
+	if err == nil {
+		err = r.decodeStored(ctx, id, &current)
+	}
+	r.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	if fromVersion == current.Version {
+		return data, nil
+	}
+	start := slices.IndexFunc(migrations, func(m *SchemaMigration) bool { return m.FromVersion == fromVersion })
+	if start < 0 {
+		return nil, fmt.Errorf("%w: schema %s has no migration from version %s", ErrMigrationNotFound, id, fromVersion)
+	}
+	for _, migration := range migrations[start:] {
+		if migration.Migrate == nil {
+			return nil, fmt.Errorf("%w: function %s of schema %s is not registered", ErrMigrationNotFound, migration.Name, id)
+		}
+		data, err = migration.Migrate(ctx, data)
+		if err != nil {
+			return nil, fmt.Errorf("migrate schema %s from %s to %s: %w", id, migration.FromVersion, migration.ToVersion, err)
+		}
+	}
+	return data, nil
+}
+
+// loadMigrations reads a schema's migrations from storage and attaches the
+// registered functions. The caller holds r.mu.
+func (r *Registry) loadMigrations(ctx context.Context, id string) ([]*SchemaMigration, error) {
+	data, err := getRecord(ctx, r.storage, migrationRecordPrefix+id)
+	if err != nil {
+		return nil, fmt.Errorf("load migrations: %w", err)
+	}
+	var migrations []*SchemaMigration
+	if data != nil {
+		if err := json.Unmarshal(data, &migrations); err != nil {
+			return nil, fmt.Errorf("decode migrations: %w", err)
+		}
+	}
+	for _, migration := range migrations {
+		migration.Migrate = r.migrationFuncs[migration.Name]
+	}
+	return migrations, nil
+}
+
+// recordMigration appends a migration to a schema's stored migrations and
+// registers its function. The caller holds r.mu.
+func (r *Registry) recordMigration(ctx context.Context, id string, migration *SchemaMigration) error {
+	if migration.Migrate != nil {
+		r.migrationFuncs[migration.Name] = migration.Migrate
+	}
+	migrations, err := r.loadMigrations(ctx, id)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(append(migrations, migration))
+	if err != nil {
+		return fmt.Errorf("encode migrations: %w", err)
+	}
+	if err := setRecord(ctx, r.storage, migrationRecordPrefix+id, data); err != nil {
+		return fmt.Errorf("save migrations: %w", err)
+	}
+	return nil
+}
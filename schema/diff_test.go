@@ -0,0 +1,240 @@
+package schema
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func changeKinds(diff *SchemaDiff, severity ChangeSeverity) map[string]ChangeKind {
+	kinds := make(map[string]ChangeKind)
+	for _, change := range diff.Filter(severity) {
+		kinds[change.Path] = change.Kind
+	}
+	return kinds
+}
+
+func TestDiffSchemas(t *testing.T) {
+	from := &Schema{
+		ID:      "user.profile",
+		Version: "1.0.0",
+		Title:   "Profile",
+		Fields: []Field{
+			{Name: "email", Type: FieldEmail, Label: "Email", Required: true},
+			{Name: "age", Type: FieldNumber, Validation: &FieldValidation{MinLength: intPtr(1)}},
+			{Name: "bio", Type: FieldTextarea, Validation: &FieldValidation{MaxLength: intPtr(100)}},
+			{Name: "legacy", Type: FieldText},
+			{Name: "role", Type: FieldSelect, Options: []FieldOption{
+				{Value: "admin", Label: "Admin"},
+				{Value: "user", Label: "User"},
+			}},
+		},
+		Actions: []Action{
+			{ID: "save", Type: ActionSubmit, Text: "Save"},
+			{ID: "cancel", Type: ActionReset, Text: "Cancel"},
+		},
+	}
+	to := &Schema{
+		ID:      "user.profile",
+		Version: "2.0.0",
+		Title:   "User Profile",
+		Fields: []Field{
+			{Name: "email", Type: FieldText, Label: "Email address", Required: true},
+			{Name: "age", Type: FieldNumber, Validation: &FieldValidation{MinLength: intPtr(3)}},
+			{Name: "bio", Type: FieldTextarea, Validation: &FieldValidation{MaxLength: intPtr(500)}},
+			{Name: "role", Type: FieldSelect, Options: []FieldOption{
+				{Value: "user", Label: "Member"},
+				{Value: "guest", Label: "Guest"},
+			}},
+			{Name: "nickname", Type: FieldText},
+			{Name: "country", Type: FieldSelect, Required: true},
+		},
+		Actions: []Action{
+			{ID: "save", Type: ActionSubmit, Text: "Save changes"},
+		},
+		Layout: &Layout{Type: LayoutGrid, Columns: 2},
+	}
+
+	diff := DiffSchemas(from, to)
+	require.Equal(t, "1.0.0", diff.FromVersion)
+	require.Equal(t, "2.0.0", diff.ToVersion)
+	require.True(t, diff.HasBreaking())
+	require.Equal(t, ChangeBreaking, diff.Severity())
+
+	require.Equal(t, map[string]ChangeKind{
+		"fields.age.validation.minLength": ChangeValidationTightened,
+		"fields.role.options":             ChangeOptionRemoved,
+		"fields.legacy":                   ChangeFieldRemoved,
+		"fields.country":                  ChangeFieldAdded,
+		"actions.cancel":                  ChangeActionRemoved,
+	}, changeKinds(diff, ChangeBreaking))
+
+	require.Equal(t, map[string]ChangeKind{
+		"fields.email.type":               ChangeFieldRetyped,
+		"fields.bio.validation.maxLength": ChangeValidationLoosened,
+		"fields.role.options":             ChangeOptionAdded,
+		"fields.nickname":                 ChangeFieldAdded,
+	}, changeKinds(diff, ChangeCompatible))
+
+	require.Equal(t, map[string]ChangeKind{
+		"title":               ChangeSchemaChanged,
+		"fields.email.label":  ChangeFieldChanged,
+		"fields.role.options": ChangeOptionChanged,
+		"actions.save.text":   ChangeActionChanged,
+		"layout":              ChangeLayoutChanged,
+	}, changeKinds(diff, ChangeCosmetic))
+}
+
+func TestDiffSchemas_Retype(t *testing.T) {
+	for _, tc := range []struct {
+		from, to FieldType
+		severity ChangeSeverity
+	}{
+		{FieldEmail, FieldText, ChangeCompatible},
+		{FieldText, FieldEmail, ChangeBreaking},
+		{FieldNumber, FieldCurrency, ChangeCompatible},
+		{FieldNumber, FieldText, ChangeBreaking},
+	} {
+		diff := DiffSchemas(
+			&Schema{Fields: []Field{{Name: "f", Type: tc.from}}},
+			&Schema{Fields: []Field{{Name: "f", Type: tc.to}}},
+		)
+		require.Len(t, diff.Changes, 1)
+		require.Equal(t, tc.severity, diff.Changes[0].Severity, "%s -> %s", tc.from, tc.to)
+	}
+}
+
+func TestDiffSchemas_Unchanged(t *testing.T) {
+	schema := &Schema{ID: "a", Fields: []Field{{Name: "f", Type: FieldText, Validation: &FieldValidation{}}}}
+	diff := DiffSchemas(schema, &Schema{ID: "a", Fields: []Field{{Name: "f", Type: FieldText}}})
+	require.Empty(t, diff.Changes)
+	require.False(t, diff.HasBreaking())
+}
+
+func TestRegistry_UpdateRejectsBreakingChanges(t *testing.T) {
+	ctx := context.Background()
+	config := DefaultRegistryConfig()
+	config.ValidateOnStore = false
+	config.ValidateOnLoad = false
+	config.RejectBreakingChanges = true
+	registry, err := NewRegistry(config)
+	require.NoError(t, err)
+
+	v1 := &Schema{ID: "user.profile", Type: TypeForm, Version: "1.0.0", Fields: []Field{
+		{Name: "name", Type: FieldText},
+		{Name: "phone", Type: FieldPhone},
+	}}
+	require.NoError(t, registry.Register(ctx, v1))
+
+	// Compatible changes are accepted
+	v2 := &Schema{ID: "user.profile", Type: TypeForm, Version: "1.1.0", Title: "Profile", Fields: []Field{
+		{Name: "name", Type: FieldText},
+		{Name: "phone", Type: FieldPhone},
+		{Name: "email", Type: FieldEmail},
+	}}
+	require.NoError(t, registry.Update(ctx, v2))
+
+	// Removing a field is refused without a migration
+	v3 := &Schema{ID: "user.profile", Type: TypeForm, Version: "1.2.0", Title: "Profile", Fields: []Field{
+		{Name: "name", Type: FieldText},
+		{Name: "email", Type: FieldEmail},
+	}}
+	err = registry.Update(ctx, v3)
+	var breaking *BreakingChangeError
+	require.True(t, errors.As(err, &breaking))
+	require.Equal(t, ChangeFieldRemoved, breaking.Diff.Filter(ChangeBreaking)[0].Kind)
+
+	// Migrations are persisted by name
+	err = registry.Update(ctx, v3, WithMigration(&SchemaMigration{Migrate: dropPhone}))
+	require.ErrorContains(t, err, "has no name")
+
+	require.NoError(t, registry.Update(ctx, v3, WithMigration(&SchemaMigration{
+		Name:        "user.profile.drop-phone",
+		Description: "drop phone",
+		Migrate:     dropPhone,
+	})))
+
+	migrations, err := registry.GetMigrations(ctx, "user.profile")
+	require.NoError(t, err)
+	require.Len(t, migrations, 1)
+	require.Equal(t, "1.1.0", migrations[0].FromVersion)
+	require.Equal(t, "1.2.0", migrations[0].ToVersion)
+
+	data, err := registry.MigrateData(ctx, "user.profile", "1.1.0", map[string]any{"name": "Ann", "phone": "555"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"name": "Ann"}, data)
+}
+
+func dropPhone(_ context.Context, data map[string]any) (map[string]any, error) {
+	delete(data, "phone")
+	return data, nil
+}
+
+func TestDiffSchemas_ZeroValues(t *testing.T) {
+	// A default changing from 0 to unset, or false to unset, is a change
+	for _, value := range []any{0, false, ""} {
+		diff := DiffSchemas(
+			&Schema{Fields: []Field{{Name: "f", Type: FieldText, Default: value}}},
+			&Schema{Fields: []Field{{Name: "f", Type: FieldText}}},
+		)
+		require.Equal(t, map[string]ChangeKind{"fields.f.default": ChangeFieldChanged},
+			changeKinds(diff, ChangeCompatible), "default %#v", value)
+	}
+}
+
+func TestRegistry_MigrationsPersist(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	newRegistry := func() *Registry {
+		config := DefaultRegistryConfig()
+		config.ValidateOnStore = false
+		config.ValidateOnLoad = false
+		config.EnableMemoryCache = false
+		registry, err := NewRegistry(config)
+		require.NoError(t, err)
+		storage, err := NewFilesystemStorage(dir)
+		require.NoError(t, err)
+		registry.storage = storage
+		registry.lifecycle = NewStorageLifecycleStore(storage)
+		return registry
+	}
+
+	registry := newRegistry()
+	require.NoError(t, registry.Register(ctx, &Schema{ID: "user.profile", Type: TypeForm, Title: "Profile", Version: "1.1.0", Fields: []Field{
+		{Name: "name", Type: FieldText, Label: "Name"},
+		{Name: "phone", Type: FieldPhone, Label: "Phone"},
+	}}))
+	require.NoError(t, registry.Update(ctx, &Schema{ID: "user.profile", Type: TypeForm, Title: "Profile", Version: "1.2.0", Fields: []Field{
+		{Name: "name", Type: FieldText, Label: "Name"},
+	}}, WithMigration(&SchemaMigration{Name: "user.profile.drop-phone", Migrate: dropPhone})))
+
+	// A second node sharing the storage sees the migration once it registers
+	// the function
+	other := newRegistry()
+	migrations, err := other.GetMigrations(ctx, "user.profile")
+	require.NoError(t, err)
+	require.Len(t, migrations, 1)
+	require.Equal(t, "1.1.0", migrations[0].FromVersion)
+	require.Nil(t, migrations[0].Migrate)
+	_, err = other.MigrateData(ctx, "user.profile", "1.1.0", map[string]any{"phone": "555"})
+	require.True(t, errors.Is(err, ErrMigrationNotFound))
+
+	require.NoError(t, other.RegisterMigrationFunc("user.profile.drop-phone", dropPhone))
+	data, err := other.MigrateData(ctx, "user.profile", "1.1.0", map[string]any{"name": "Ann", "phone": "555"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"name": "Ann"}, data)
+
+	// Data at the current version passes through; unknown versions fail
+	data, err = other.MigrateData(ctx, "user.profile", "1.2.0", map[string]any{"name": "Ann"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"name": "Ann"}, data)
+	_, err = other.MigrateData(ctx, "user.profile", "0.9.0", map[string]any{"name": "Ann"})
+	require.True(t, errors.Is(err, ErrMigrationNotFound))
+
+	// Migration records are not listed as schemas
+	schemas, err := other.List(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, schemas, 1)
+}
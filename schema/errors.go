@@ -32,6 +32,10 @@ var (
 	ErrPermissionDenied = NewPermissionCodeError("access_denied", "permission denied")
 	ErrUnauthorized     = NewPermissionCodeError("unauthorized", "user not authorized")
 	ErrForbidden        = NewPermissionCodeError("forbidden", "action forbidden")
+	// Field encryption errors
+	ErrEncryptionRequired    = NewInternalError("encryption_required", "schema requires field encryption")
+	ErrInvalidEncryptedValue = NewValidationError("encrypted_value", "encrypted value is invalid")
+	ErrMaskedValue           = NewValidationError("masked_value", "value is still masked")
 	// Data source errors
 	ErrDataSourceFailed   = NewDataSourceError("data_source_failed", "data source request failed")
 	ErrDataSourceTimeout  = NewDataSourceError("data_source_timeout", "data source request timeout")
@@ -43,6 +47,15 @@ var (
 	ErrWorkflowFailed   = NewWorkflowError("workflow_failed", "workflow execution failed")
 	ErrApprovalRequired = NewWorkflowError("approval_required", "approval required")
 	ErrWorkflowNotFound = NewNotFoundError("workflow", "")
+	// Lifecycle errors
+	ErrInvalidTransition  = NewWorkflowError("invalid_transition", "lifecycle transition not allowed")
+	ErrSchemaNotPublished = NewWorkflowError("schema_not_published", "schema is not published to the environment")
+	ErrSchemaArchived     = NewWorkflowError("schema_archived", "schema is archived")
+	// Migration errors
+	ErrMigrationNotFound = NewNotFoundError("migration", "")
+	// Composition errors
+	ErrInvalidReference  = NewValidationError("invalid_reference", "schema reference is invalid")
+	ErrCircularReference = NewValidationError("circular_reference", "schema composition contains a cycle")
 	// Multi-tenancy errors
 	ErrTenantMismatch = NewTenantError("tenant_mismatch", "tenant mismatch")
 	ErrTenantNotFound = NewNotFoundError("tenant", "")
@@ -19,6 +19,7 @@ type Field struct {
 	Name  string    `json:"name"`
 	Type  FieldType `json:"type"`
 	Label string    `json:"label"`
+	Ref   string    `json:"$ref,omitempty"` // Field of another schema as "id@version#field"; set values override it
 
 	// Display
 	Description string `json:"description,omitempty"`
@@ -67,6 +68,8 @@ type Field struct {
 
 	// Internal
 	evaluator *condition.Evaluator `json:"-"`
+	// explicitZeros holds zero values set in the source JSON; see MarshalJSON
+	explicitZeros jsonZeros
 }
 
 // FieldType defines all supported input and display field types
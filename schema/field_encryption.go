@@ -0,0 +1,434 @@
+package schema
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/niiniyare/ruun/pkg/encryption"
+)
+
+const (
+	// EncryptedValuePrefix marks a field value holding an encrypted payload
+	EncryptedValuePrefix = "enc:v1:"
+	// BlindIndexSuffix is appended to a searchable field's name to store its
+	// blind index next to the encrypted value
+	BlindIndexSuffix = "_bidx"
+	// DefaultDecryptPermission is required to read encrypted fields when the
+	// schema does not configure one
+	DefaultDecryptPermission = "encryption:decrypt"
+
+	// blindIndexSize is the number of HMAC bytes kept, trading a few false
+	// positives for less leakage about equal values
+	blindIndexSize = 16
+
+	// valueMask replaces the hidden part of a masked value
+	valueMask = "****"
+)
+
+// KeyEnsurer creates the first version of a key on demand. It is
+// implemented by encryption.EnvelopeKeyRepository.
+type KeyEnsurer interface {
+	EnsureKey(ctx context.Context, keyID encryption.KeyID) (*encryption.EncryptionKey, error)
+}
+
+type userContextKey struct{}
+
+// ContextWithUser attaches the acting user, whose tenant selects the
+// encryption key and whose permissions decide what is decrypted
+func ContextWithUser(ctx context.Context, user User) context.Context {
+	return context.WithValue(ctx, userContextKey{}, user)
+}
+
+// UserFromContext returns the user attached by ContextWithUser
+func UserFromContext(ctx context.Context) (User, bool) {
+	user, ok := ctx.Value(userContextKey{}).(User)
+	return user, ok && user != nil
+}
+
+// TenantKeyID returns the key that protects a tenant's fields
+func TenantKeyID(tenantID string) encryption.KeyID {
+	if tenantID == "" {
+		return encryption.DefaultKeyID
+	}
+	return encryption.KeyID("tenant-" + tenantID)
+}
+
+// BlindIndexField returns the data key holding a field's blind index
+func BlindIndexField(fieldName string) string {
+	return fieldName + BlindIndexSuffix
+}
+
+// IsEncryptedValue reports whether a field value is an encrypted payload
+func IsEncryptedValue(value any) bool {
+	s, ok := value.(string)
+	return ok && strings.HasPrefix(s, EncryptedValuePrefix)
+}
+
+// FieldEncryptor applies a schema's Security.Encryption settings to form
+// data. Sensitive fields are encrypted with their tenant's key and bound to
+// their field name, so a ciphertext cannot be replayed into another field or
+// tenant. Searchable fields also get a keyed blind index that supports
+// equality lookups without decrypting.
+type FieldEncryptor struct {
+	service     encryption.EncryptionService
+	keys        KeyEnsurer
+	indexSecret []byte
+	ensured     sync.Map // encryption.KeyID -> struct{}
+}
+
+// NewFieldEncryptor creates an encryptor. keys may be nil when tenant keys
+// are provisioned elsewhere; indexSecret keys the blind indexes and must be
+// kept separate from the data keys.
+func NewFieldEncryptor(service encryption.EncryptionService, keys KeyEnsurer, indexSecret []byte) (*FieldEncryptor, error) {
+	if service == nil {
+		return nil, fmt.Errorf("encryption service is required")
+	}
+	if len(indexSecret) < 32 {
+		return nil, fmt.Errorf("blind index secret must be at least 32 bytes")
+	}
+	return &FieldEncryptor{
+		service:     service,
+		keys:        keys,
+		indexSecret: append([]byte(nil), indexSecret...),
+	}, nil
+}
+
+// EncryptData returns a copy of data with the schema's sensitive fields
+// encrypted for the tenant. Values that are already encrypted for the same
+// field and tenant are kept as they are, so a draft loaded by a user who
+// cannot decrypt it can be saved again unchanged. Values still masked by
+// MaskValue are rejected with ErrMaskedValue rather than stored over the
+// data they hide.
+func (e *FieldEncryptor) EncryptData(ctx context.Context, schema *Schema, tenantID string, data map[string]any) (map[string]any, error) {
+	if !schemaEncrypts(schema) {
+		return data, nil
+	}
+	keyID, err := e.tenantKey(ctx, schema, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]any, len(data))
+	for name, value := range data {
+		result[name] = value
+	}
+	for _, name := range schema.Security.Encryption.Fields {
+		// Blind indexes are always derived here, never taken from the input
+		delete(result, BlindIndexField(name))
+		value, ok := data[name]
+		if !ok || isEmptyValue(value) {
+			continue
+		}
+
+		switch {
+		case IsEncryptedValue(value):
+			if value, err = e.open(ctx, keyID, name, value.(string)); err != nil {
+				return nil, err
+			}
+		case IsMaskedValue(value):
+			return nil, fmt.Errorf("%w: %s", ErrMaskedValue, name)
+		default:
+			token, err := e.seal(ctx, keyID, name, value)
+			if err != nil {
+				return nil, err
+			}
+			result[name] = token
+		}
+		if schema.Security.ShouldIndexField(name) {
+			result[BlindIndexField(name)] = e.BlindIndex(tenantID, name, value)
+		}
+	}
+	return result, nil
+}
+
+// DecryptData returns a copy of data with encrypted fields decrypted when
+// the user holds the schema's decrypt permission. For other users the
+// values stay encrypted; renderers wrapped by NewMaskingRenderer show them
+// masked.
+func (e *FieldEncryptor) DecryptData(ctx context.Context, schema *Schema, user User, data map[string]any) (map[string]any, error) {
+	if !schemaEncrypts(schema) || !CanDecrypt(schema, user) {
+		return data, nil
+	}
+	keyID, err := e.tenantKey(ctx, schema, user.GetTenantID())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]any, len(data))
+	for name, value := range data {
+		result[name] = value
+	}
+	for _, name := range schema.Security.Encryption.Fields {
+		token, ok := data[name].(string)
+		if !ok || !IsEncryptedValue(token) {
+			continue
+		}
+		value, err := e.open(ctx, keyID, name, token)
+		if err != nil {
+			return nil, err
+		}
+		result[name] = value
+	}
+	return result, nil
+}
+
+// BlindIndex returns the blind index of a value, for building equality
+// queries against stored data. Strings are compared case-insensitively
+// and without surrounding whitespace.
+func (e *FieldEncryptor) BlindIndex(tenantID, fieldName string, value any) string {
+	keyMAC := hmac.New(sha256.New, e.indexSecret)
+	fmt.Fprintf(keyMAC, "blind-index:%s:%s", tenantID, fieldName)
+
+	mac := hmac.New(sha256.New, keyMAC.Sum(nil))
+	mac.Write([]byte(strings.ToLower(strings.TrimSpace(fmt.Sprint(value)))))
+	return hex.EncodeToString(mac.Sum(nil)[:blindIndexSize])
+}
+
+// CanDecrypt reports whether a user may read a schema's encrypted fields
+func CanDecrypt(schema *Schema, user User) bool {
+	if user == nil || schema == nil || schema.Security == nil {
+		return false
+	}
+	return user.HasPermission(schema.Security.DecryptPermission())
+}
+
+// MaskValue hides a sensitive value for display. The "partial" style keeps
+// the first letter and domain of e-mail addresses and the last four
+// characters of longer values; "full" hides everything. Values that are
+// still encrypted are always hidden completely.
+func MaskValue(style string, value any) string {
+	if value == nil || style == "full" || IsEncryptedValue(value) {
+		return valueMask
+	}
+
+	s := fmt.Sprint(value)
+	if local, domain, ok := strings.Cut(s, "@"); ok && local != "" && domain != "" {
+		first, _ := utf8.DecodeRuneInString(local)
+		return string(first) + "***@" + domain
+	}
+	runes := []rune(s)
+	if len(runes) < 8 {
+		return valueMask
+	}
+	return valueMask + string(runes[len(runes)-4:])
+}
+
+// IsMaskedValue reports whether a value has the shape MaskValue produces,
+// i.e. it is a masked placeholder submitted back instead of a real value
+func IsMaskedValue(value any) bool {
+	s, ok := value.(string)
+	if !ok {
+		return false
+	}
+	if strings.HasPrefix(s, valueMask) {
+		return true
+	}
+	_, size := utf8.DecodeRuneInString(s)
+	return size > 0 && strings.HasPrefix(s[size:], "***@")
+}
+
+// tenantKey resolves the tenant's key, creating it on first use
+func (e *FieldEncryptor) tenantKey(ctx context.Context, schema *Schema, tenantID string) (encryption.KeyID, error) {
+	if tenantID == "" && schema.Tenant.IsTenantEnabled() {
+		return "", fmt.Errorf("%w: schema %s requires a tenant to encrypt fields", ErrTenantMismatch, schema.ID)
+	}
+
+	keyID := TenantKeyID(tenantID)
+	if e.keys == nil {
+		return keyID, nil
+	}
+	if _, ok := e.ensured.Load(keyID); !ok {
+		if _, err := e.keys.EnsureKey(ctx, keyID); err != nil {
+			return "", fmt.Errorf("failed to provision key %s: %w", keyID, err)
+		}
+		e.ensured.Store(keyID, struct{}{})
+	}
+	return keyID, nil
+}
+
+// sealedValue is the plaintext of an encrypted field; carrying the field
+// name stops ciphertexts being moved between fields
+type sealedValue struct {
+	Field string `json:"field"`
+	Value any    `json:"value"`
+}
+
+func (e *FieldEncryptor) seal(ctx context.Context, keyID encryption.KeyID, field string, value any) (string, error) {
+	plaintext, err := json.Marshal(sealedValue{Field: field, Value: value})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode field %s: %w", field, err)
+	}
+	payload, err := e.service.Encrypt(ctx, string(plaintext), keyID)
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt field %s: %w", field, err)
+	}
+	return EncryptedValuePrefix + base64.RawURLEncoding.EncodeToString(payload.Marshal()), nil
+}
+
+func (e *FieldEncryptor) open(ctx context.Context, keyID encryption.KeyID, field, token string) (any, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(token, EncryptedValuePrefix))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidEncryptedValue, field)
+	}
+	payload, err := encryption.UnmarshalEncryptedPayload(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidEncryptedValue, field)
+	}
+	if payload.KeyID() != keyID {
+		return nil, fmt.Errorf("%w: field %s is encrypted for another tenant", ErrTenantMismatch, field)
+	}
+
+	plaintext, err := e.service.Decrypt(ctx, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt field %s: %w", field, err)
+	}
+	var sealed sealedValue
+	if err := json.Unmarshal([]byte(plaintext), &sealed); err != nil || sealed.Field != field {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidEncryptedValue, field)
+	}
+	return sealed.Value, nil
+}
+
+func schemaEncrypts(schema *Schema) bool {
+	return schema != nil && schema.Security != nil && schema.Security.IsEncryptionEnabled()
+}
+
+func isEmptyValue(value any) bool {
+	s, ok := value.(string)
+	return value == nil || ok && s == ""
+}
+
+// EncryptingStateManager encrypts a schema's sensitive fields before drafts
+// reach the wrapped StateManager. The tenant and permissions come from the
+// user attached with ContextWithUser.
+type EncryptingStateManager struct {
+	next      StateManager
+	schemas   SchemaSource
+	encryptor *FieldEncryptor
+}
+
+// NewEncryptingStateManager wraps a StateManager; schemas loads the current
+// version of the schemas drafts are saved under
+func NewEncryptingStateManager(next StateManager, schemas SchemaSource, encryptor *FieldEncryptor) *EncryptingStateManager {
+	return &EncryptingStateManager{next: next, schemas: schemas, encryptor: encryptor}
+}
+
+func (m *EncryptingStateManager) SaveState(ctx context.Context, sessionID, schemaID string, state map[string]any) error {
+	schema, err := m.schemas.LoadSchema(ctx, schemaID, "")
+	if err != nil {
+		return err
+	}
+	var tenantID string
+	if user, ok := UserFromContext(ctx); ok {
+		tenantID = user.GetTenantID()
+	}
+	encrypted, err := m.encryptor.EncryptData(ctx, schema, tenantID, state)
+	if err != nil {
+		return err
+	}
+	return m.next.SaveState(ctx, sessionID, schemaID, encrypted)
+}
+
+// LoadState decrypts the draft for users allowed to read it; other users
+// get the encrypted values back
+func (m *EncryptingStateManager) LoadState(ctx context.Context, sessionID, schemaID string) (map[string]any, error) {
+	state, err := m.next.LoadState(ctx, sessionID, schemaID)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := m.schemas.LoadSchema(ctx, schemaID, "")
+	if err != nil {
+		return nil, err
+	}
+	user, _ := UserFromContext(ctx)
+	return m.encryptor.DecryptData(ctx, schema, user, state)
+}
+
+func (m *EncryptingStateManager) DeleteState(ctx context.Context, sessionID, schemaID string) error {
+	return m.next.DeleteState(ctx, sessionID, schemaID)
+}
+
+func (m *EncryptingStateManager) ListStates(ctx context.Context, sessionID string) ([]string, error) {
+	return m.next.ListStates(ctx, sessionID)
+}
+
+// MaskingRenderer shows a schema's sensitive fields masked unless the user
+// attached with ContextWithUser may decrypt them
+type MaskingRenderer struct {
+	next      Renderer
+	schema    *Schema
+	encryptor *FieldEncryptor
+}
+
+// NewMaskingRenderer wraps a Renderer; schema supplies the sensitive fields
+// for RenderField and Format, which only receive the field
+func NewMaskingRenderer(next Renderer, schema *Schema, encryptor *FieldEncryptor) *MaskingRenderer {
+	return &MaskingRenderer{next: next, schema: schema, encryptor: encryptor}
+}
+
+func (r *MaskingRenderer) Render(ctx context.Context, schema *Schema, data map[string]any) (string, error) {
+	if !schemaEncrypts(schema) {
+		return r.next.Render(ctx, schema, data)
+	}
+
+	user, _ := UserFromContext(ctx)
+	visible, err := r.encryptor.DecryptData(ctx, schema, user, data)
+	if err != nil {
+		return "", err
+	}
+	if !CanDecrypt(schema, user) {
+		masked := make(map[string]any, len(visible))
+		for name, value := range visible {
+			masked[name] = value
+		}
+		for _, name := range schema.Security.Encryption.Fields {
+			if value, ok := masked[name]; ok && !isEmptyValue(value) {
+				masked[name] = MaskValue(schema.Security.Encryption.Mask, value)
+			}
+		}
+		visible = masked
+	}
+	return r.next.Render(ctx, schema, visible)
+}
+
+func (r *MaskingRenderer) RenderField(ctx context.Context, field *Field, value any) (string, error) {
+	visible, err := r.visibleValue(ctx, field, value)
+	if err != nil {
+		return "", err
+	}
+	return r.next.RenderField(ctx, field, visible)
+}
+
+func (r *MaskingRenderer) Format(ctx context.Context, field *Field, value any) (string, error) {
+	visible, err := r.visibleValue(ctx, field, value)
+	if err != nil {
+		return "", err
+	}
+	return r.next.Format(ctx, field, visible)
+}
+
+// visibleValue decrypts a sensitive value for permitted users and masks it
+// for everyone else
+func (r *MaskingRenderer) visibleValue(ctx context.Context, field *Field, value any) (any, error) {
+	if field == nil || !schemaEncrypts(r.schema) || !r.schema.Security.ShouldEncryptField(field.Name) || isEmptyValue(value) {
+		return value, nil
+	}
+
+	user, _ := UserFromContext(ctx)
+	if !CanDecrypt(r.schema, user) {
+		return MaskValue(r.schema.Security.Encryption.Mask, value), nil
+	}
+	data, err := r.encryptor.DecryptData(ctx, r.schema, user, map[string]any{field.Name: value})
+	if err != nil {
+		return nil, err
+	}
+	return data[field.Name], nil
+}
@@ -0,0 +1,337 @@
+package schema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/niiniyare/ruun/pkg/encryption"
+)
+
+type fieldEncryptionFixture struct {
+	encryptor *FieldEncryptor
+	keys      *encryption.EnvelopeKeyRepository
+	schema    *Schema
+	reader    *BasicUser
+	clerk     *BasicUser
+}
+
+func newFieldEncryptionFixture(t *testing.T) *fieldEncryptionFixture {
+	t.Helper()
+	dir := t.TempDir()
+	kek, err := encryption.NewFileKEKProvider(filepath.Join(dir, "kek"), true)
+	require.NoError(t, err)
+	store, err := encryption.NewFileKeyStore(filepath.Join(dir, "keys"))
+	require.NoError(t, err)
+	metrics := encryption.NewSimpleMetrics()
+	keys, err := encryption.NewEnvelopeKeyRepository(store, kek, encryption.KeyManagementConfig{}, zap.NewNop(), metrics)
+	require.NoError(t, err)
+
+	service := encryption.NewEncryptionService(keys, 4096, zap.NewNop(), metrics)
+	encryptor, err := NewFieldEncryptor(service, keys, []byte(strings.Repeat("i", 32)))
+	require.NoError(t, err)
+
+	return &fieldEncryptionFixture{
+		encryptor: encryptor,
+		keys:      keys,
+		schema: &Schema{
+			ID:    "customer",
+			Type:  TypeForm,
+			Title: "Customer",
+			Fields: []Field{
+				{Name: "name", Type: FieldText},
+				{Name: "email", Type: FieldEmail},
+				{Name: "ssn", Type: FieldText},
+			},
+			Security: &Security{Encryption: &Encryption{
+				Enabled:    true,
+				Fields:     []string{"email", "ssn"},
+				Searchable: []string{"email"},
+				Permission: "customer:pii",
+			}},
+			Tenant: &Tenant{Enabled: true},
+		},
+		reader: &BasicUser{ID: "u1", TenantID: "acme", Permissions: []string{"customer:pii"}},
+		clerk:  &BasicUser{ID: "u2", TenantID: "acme"},
+	}
+}
+
+func (f *fieldEncryptionFixture) encrypt(t *testing.T, data map[string]any) map[string]any {
+	t.Helper()
+	encrypted, err := f.encryptor.EncryptData(context.Background(), f.schema, "acme", data)
+	require.NoError(t, err)
+	return encrypted
+}
+
+func TestFieldEncryptor_EncryptData(t *testing.T) {
+	f := newFieldEncryptionFixture(t)
+	data := map[string]any{"name": "Alice", "email": " Alice@Example.com", "ssn": "123-45-6789"}
+
+	encrypted := f.encrypt(t, data)
+	require.Equal(t, "Alice", encrypted["name"])
+	require.True(t, IsEncryptedValue(encrypted["email"]))
+	require.True(t, IsEncryptedValue(encrypted["ssn"]))
+	require.Equal(t, " Alice@Example.com", data["email"], "input is not modified")
+
+	require.Equal(t, f.encryptor.BlindIndex("acme", "email", "alice@example.com"), encrypted["email_bidx"])
+	require.NotContains(t, encrypted, "ssn_bidx", "only searchable fields are indexed")
+	require.NotEqual(t, f.encryptor.BlindIndex("acme", "email", "alice@example.com"),
+		f.encryptor.BlindIndex("globex", "email", "alice@example.com"), "indexes are per tenant")
+
+	_, err := f.keys.GetLatestKey(context.Background(), TenantKeyID("acme"))
+	require.NoError(t, err, "the tenant key is created on first use")
+}
+
+func TestFieldEncryptor_EncryptDataKeepsCiphertext(t *testing.T) {
+	f := newFieldEncryptionFixture(t)
+	encrypted := f.encrypt(t, map[string]any{"email": "alice@example.com", "ssn": "123-45-6789"})
+
+	resubmitted := map[string]any{"email": encrypted["email"], "ssn": encrypted["ssn"], "email_bidx": "forged"}
+	again := f.encrypt(t, resubmitted)
+	require.Equal(t, encrypted["email"], again["email"])
+	require.Equal(t, encrypted["email_bidx"], again["email_bidx"], "blind indexes are never taken from the input")
+
+	swapped := map[string]any{"email": encrypted["ssn"]}
+	_, err := f.encryptor.EncryptData(context.Background(), f.schema, "acme", swapped)
+	require.True(t, errors.Is(err, ErrInvalidEncryptedValue), "a ciphertext cannot move between fields: %v", err)
+
+	_, err = f.encryptor.EncryptData(context.Background(), f.schema, "globex", resubmitted)
+	require.True(t, errors.Is(err, ErrTenantMismatch), "a ciphertext cannot move between tenants: %v", err)
+
+	_, err = f.encryptor.EncryptData(context.Background(), f.schema, "", resubmitted)
+	require.True(t, errors.Is(err, ErrTenantMismatch), "tenant schemas need a tenant: %v", err)
+}
+
+func TestFieldEncryptor_DecryptData(t *testing.T) {
+	ctx := context.Background()
+	f := newFieldEncryptionFixture(t)
+	encrypted := f.encrypt(t, map[string]any{"name": "Alice", "email": "alice@example.com", "ssn": "123-45-6789"})
+
+	plain, err := f.encryptor.DecryptData(ctx, f.schema, f.reader, encrypted)
+	require.NoError(t, err)
+	require.Equal(t, "alice@example.com", plain["email"])
+	require.Equal(t, "123-45-6789", plain["ssn"])
+
+	hidden, err := f.encryptor.DecryptData(ctx, f.schema, f.clerk, encrypted)
+	require.NoError(t, err)
+	require.Equal(t, encrypted["ssn"], hidden["ssn"], "users without the permission keep the ciphertext")
+
+	outsider := &BasicUser{ID: "u3", TenantID: "globex", Permissions: []string{"customer:pii"}}
+	_, err = f.encryptor.DecryptData(ctx, f.schema, outsider, encrypted)
+	require.True(t, errors.Is(err, ErrTenantMismatch))
+}
+
+// memoryStateManager is a StateManager keeping drafts in a map
+type memoryStateManager map[string]map[string]any
+
+func (m memoryStateManager) SaveState(ctx context.Context, sessionID, schemaID string, state map[string]any) error {
+	m[sessionID+"/"+schemaID] = state
+	return nil
+}
+
+func (m memoryStateManager) LoadState(ctx context.Context, sessionID, schemaID string) (map[string]any, error) {
+	return m[sessionID+"/"+schemaID], nil
+}
+
+func (m memoryStateManager) DeleteState(ctx context.Context, sessionID, schemaID string) error {
+	delete(m, sessionID+"/"+schemaID)
+	return nil
+}
+
+func (m memoryStateManager) ListStates(ctx context.Context, sessionID string) ([]string, error) {
+	return nil, nil
+}
+
+func TestEncryptingStateManager(t *testing.T) {
+	f := newFieldEncryptionFixture(t)
+	stored := memoryStateManager{}
+	source := SchemaSourceFunc(func(ctx context.Context, id, version string) (*Schema, error) {
+		return f.schema, nil
+	})
+	drafts := NewEncryptingStateManager(stored, source, f.encryptor)
+
+	ctx := ContextWithUser(context.Background(), f.reader)
+	require.NoError(t, drafts.SaveState(ctx, "s1", "customer", map[string]any{"ssn": "123-45-6789"}))
+	require.True(t, IsEncryptedValue(stored["s1/customer"]["ssn"]), "drafts are stored encrypted")
+
+	state, err := drafts.LoadState(ctx, "s1", "customer")
+	require.NoError(t, err)
+	require.Equal(t, "123-45-6789", state["ssn"])
+
+	clerkCtx := ContextWithUser(context.Background(), f.clerk)
+	state, err = drafts.LoadState(clerkCtx, "s1", "customer")
+	require.NoError(t, err)
+	require.True(t, IsEncryptedValue(state["ssn"]))
+	require.NoError(t, drafts.SaveState(clerkCtx, "s1", "customer", state), "an unreadable draft can be saved back")
+}
+
+// formatRenderer formats values with fmt
+type formatRenderer struct{}
+
+func (formatRenderer) Render(ctx context.Context, schema *Schema, data map[string]any) (string, error) {
+	return fmt.Sprint(data["email"], " ", data["ssn"]), nil
+}
+
+func (formatRenderer) RenderField(ctx context.Context, field *Field, value any) (string, error) {
+	return fmt.Sprint(value), nil
+}
+
+func (formatRenderer) Format(ctx context.Context, field *Field, value any) (string, error) {
+	return fmt.Sprint(value), nil
+}
+
+func TestMaskingRenderer(t *testing.T) {
+	f := newFieldEncryptionFixture(t)
+	renderer := NewMaskingRenderer(formatRenderer{}, f.schema, f.encryptor)
+	encrypted := f.encrypt(t, map[string]any{"email": "alice@example.com", "ssn": "123-45-6789"})
+	name, email := &f.schema.Fields[0], &f.schema.Fields[1]
+
+	readerCtx := ContextWithUser(context.Background(), f.reader)
+	out, err := renderer.Format(readerCtx, email, encrypted["email"])
+	require.NoError(t, err)
+	require.Equal(t, "alice@example.com", out)
+
+	clerkCtx := ContextWithUser(context.Background(), f.clerk)
+	out, err = renderer.Format(clerkCtx, email, encrypted["email"])
+	require.NoError(t, err)
+	require.Equal(t, "****", out)
+	out, err = renderer.Format(clerkCtx, email, "alice@example.com")
+	require.NoError(t, err)
+	require.Equal(t, "a***@example.com", out)
+	out, err = renderer.Format(clerkCtx, name, "Alice")
+	require.NoError(t, err)
+	require.Equal(t, "Alice", out, "other fields are not masked")
+
+	out, err = renderer.Render(readerCtx, f.schema, encrypted)
+	require.NoError(t, err)
+	require.Equal(t, "alice@example.com 123-45-6789", out)
+	out, err = renderer.Render(context.Background(), f.schema, encrypted)
+	require.NoError(t, err)
+	require.Equal(t, "**** ****", out)
+}
+
+func TestMaskValue(t *testing.T) {
+	require.Equal(t, "j***@example.com", MaskValue("", "jane@example.com"))
+	require.Equal(t, "****6789", MaskValue("partial", "123-45-6789"))
+	require.Equal(t, "****", MaskValue("partial", "1234"))
+	require.Equal(t, "****", MaskValue("full", "123-45-6789"))
+	require.Equal(t, "****", MaskValue("", EncryptedValuePrefix+"AAAA"))
+
+	require.Equal(t, "É***@example.com", MaskValue("", "Émile@example.com"), "multibyte runes are kept whole")
+	require.Equal(t, "****٦٧٨٩", MaskValue("partial", "١٢٣-٤٥-٦٧٨٩"))
+	require.Equal(t, "****", MaskValue("partial", "ßßßßßß"), "short values are measured in runes")
+
+	for _, masked := range []string{"****", "****6789", "É***@example.com"} {
+		require.True(t, IsMaskedValue(masked), masked)
+	}
+	for _, plain := range []any{"", "alice@example.com", "123-45-6789", 1234, nil} {
+		require.False(t, IsMaskedValue(plain), plain)
+	}
+}
+
+func TestFieldEncryptor_EncryptDataRejectsMaskedValues(t *testing.T) {
+	f := newFieldEncryptionFixture(t)
+	_, err := f.encryptor.EncryptData(context.Background(), f.schema, "acme", map[string]any{"ssn": "****6789"})
+	require.True(t, errors.Is(err, ErrMaskedValue), "a masked value never replaces the real one: %v", err)
+
+	data := f.encrypt(t, map[string]any{"name": "****"})
+	require.Equal(t, "****", data["name"], "fields that are not encrypted are not checked")
+}
+
+func TestRuntime_SubmitEncryptsFields(t *testing.T) {
+	f := newFieldEncryptionFixture(t)
+	ctx := ContextWithUser(context.Background(), f.reader)
+
+	runtime, err := NewRuntime(f.schema).
+		WithFieldEncryptor(f.encryptor).
+		WithInitialData(map[string]any{"name": "Alice", "ssn": "123-45-6789"}).
+		Build()
+	require.NoError(t, err)
+	data, err := runtime.Submit(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "Alice", data["name"])
+	require.True(t, IsEncryptedValue(data["ssn"]))
+
+	unencrypted, err := NewRuntime(f.schema).WithInitialData(map[string]any{"ssn": "123-45-6789"}).Build()
+	require.NoError(t, err)
+	_, err = unencrypted.Submit(ctx)
+	require.True(t, errors.Is(err, ErrEncryptionRequired), "plaintext is never returned for encrypted schemas")
+	require.True(t, errors.Is(unencrypted.HandleSubmit(ctx), ErrEncryptionRequired))
+}
+
+func TestRuntime_HandleSubmitEncryptsState(t *testing.T) {
+	f := newFieldEncryptionFixture(t)
+	ctx := ContextWithUser(context.Background(), f.reader)
+
+	runtime, err := NewRuntime(f.schema).WithFieldEncryptor(f.encryptor).Build()
+	require.NoError(t, err)
+	require.NoError(t, runtime.HandleChange(ctx, "email", "alice@example.com"))
+	require.NoError(t, runtime.HandleSubmit(ctx))
+
+	values := runtime.GetState().GetAllValues()
+	require.True(t, IsEncryptedValue(values["email"]), "the state holds no plaintext after submit")
+	require.Equal(t, f.encryptor.BlindIndex("acme", "email", "alice@example.com"), values["email_bidx"])
+
+	decrypted, err := f.encryptor.DecryptData(ctx, f.schema, f.reader, values)
+	require.NoError(t, err)
+	require.Equal(t, "alice@example.com", decrypted["email"])
+}
+
+func TestRuntime_SubmitKeepsMaskedCiphertext(t *testing.T) {
+	f := newFieldEncryptionFixture(t)
+	stored := f.encrypt(t, map[string]any{"name": "Alice", "email": "alice@example.com", "ssn": "123-45-6789"})
+	clerkCtx := ContextWithUser(context.Background(), f.clerk)
+
+	// A clerk sees the sensitive fields masked and submits the form back
+	runtime, err := NewRuntime(f.schema).WithFieldEncryptor(f.encryptor).WithInitialData(stored).Build()
+	require.NoError(t, err)
+	require.NoError(t, runtime.HandleChange(clerkCtx, "name", "Alicia"))
+	require.NoError(t, runtime.HandleChange(clerkCtx, "email", MaskValue("", stored["email"])))
+	require.NoError(t, runtime.HandleChange(clerkCtx, "ssn", MaskValue("partial", "123-45-6789")))
+
+	data, err := runtime.Submit(clerkCtx)
+	require.NoError(t, err)
+	require.Equal(t, "Alicia", data["name"])
+	require.Equal(t, stored["email"], data["email"], "masked values keep the stored ciphertext")
+	require.Equal(t, stored["ssn"], data["ssn"])
+	require.Equal(t, stored["email_bidx"], data["email_bidx"])
+
+	// Without a ciphertext to stand for, a masked value is rejected
+	fresh, err := NewRuntime(f.schema).WithFieldEncryptor(f.encryptor).Build()
+	require.NoError(t, err)
+	require.NoError(t, fresh.HandleChange(clerkCtx, "ssn", "****6789"))
+	_, err = fresh.Submit(clerkCtx)
+	require.True(t, errors.Is(err, ErrMaskedValue), "got %v", err)
+}
+
+func TestRuntime_SubmitSkipsValidatingSensitiveCiphertext(t *testing.T) {
+	f := newFieldEncryptionFixture(t)
+	f.schema.Fields[2].Validation = &FieldValidation{Pattern: `^\d{3}-\d{2}-\d{4}$`}
+	stored := f.encrypt(t, map[string]any{"name": "Alice", "ssn": "123-45-6789"})
+	clerkCtx := ContextWithUser(context.Background(), f.clerk)
+
+	// The masked SSN would fail the pattern; it stands for the stored ciphertext
+	runtime, err := NewRuntime(f.schema).
+		WithFieldEncryptor(f.encryptor).
+		WithValidator(NewValidator(nil)).
+		WithInitialData(stored).
+		Build()
+	require.NoError(t, err)
+	require.NoError(t, runtime.HandleChange(clerkCtx, "ssn", MaskValue("partial", "123-45-6789")))
+	require.NoError(t, runtime.HandleSubmit(clerkCtx))
+	require.Equal(t, stored["ssn"], runtime.GetState().GetAllValues()["ssn"])
+
+	// The state now holds ciphertext, which a second submit does not validate
+	require.NoError(t, runtime.HandleSubmit(clerkCtx))
+	require.Equal(t, stored["ssn"], runtime.GetState().GetAllValues()["ssn"])
+
+	// Plaintext is still validated
+	require.NoError(t, runtime.HandleChange(clerkCtx, "ssn", "123456789"))
+	require.Error(t, runtime.HandleSubmit(clerkCtx))
+}
@@ -685,6 +685,36 @@ func (s *FieldTestSuite) TestIsVisibleWithoutEvaluator() {
 	s.Require().False(visible) // Should default to visible (false = not hidden)
 }
 
+func (s *FieldTestSuite) TestConditionalAlpineBindings() {
+	conditional := &Conditional{
+		Show: condition.NewBuilder(condition.ConjunctionAnd).AddRule("country", condition.OpEqual, "US").Build(),
+		Hide: condition.NewBuilder(condition.ConjunctionAnd).AddRule("archived", condition.OpEqual, true).Build(),
+		Required: condition.NewBuilder(condition.ConjunctionAnd).
+			AddFormula("amount > 1000").
+			Build(),
+	}
+
+	bindings, err := conditional.AlpineBindings(condition.JSOptions{DataVar: "$store.form.formData"})
+	s.Require().NoError(err)
+	s.Require().Len(bindings, 2)
+	s.Require().Contains(bindings["x-show"], "ruunCondition.test(() => !(")
+	s.Require().Contains(bindings["x-show"], `ruunCondition.get($store.form.formData, "country")`)
+	s.Require().Contains(bindings[":required"], "ruunCondition.test(() => ")
+
+	conditional.Disabled = &condition.ConditionGroup{
+		ID:          "g",
+		Conjunction: condition.ConjunctionAnd,
+		Children: []any{condition.ConditionRule{
+			ID:    "r",
+			Left:  condition.Expression{Type: condition.ValueTypeField, Field: "due"},
+			Op:    condition.OpLess,
+			Right: condition.Expression{Type: condition.ValueTypeFunc, Func: &condition.FuncCall{Type: "now"}},
+		}},
+	}
+	_, err = conditional.AlpineBindings(condition.JSOptions{})
+	s.Require().ErrorIs(err, condition.ErrJSUnsupported)
+}
+
 // ==================== Helper Methods Tests ====================
 func (s *FieldTestSuite) TestIsSelectionType() {
 	selectionTypes := []FieldType{
@@ -0,0 +1,192 @@
+package schema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/niiniyare/ruun/pkg/condition"
+)
+
+// Formula fields
+//
+// A field computes its value from a formula when it has type "formula" or
+// a "formula" entry in its config:
+//
+//	{"name": "total", "type": "formula", "config": {"formula": "price * quantity", "resultType": "currency"}}
+//
+// Formulas are compiled against the types of the other schema fields, so a
+// formula that adds a date to a string or returns text for a number field
+// is reported against the field when the schema is parsed.
+
+// FormulaResultType maps a schema field type to the type formulas use for
+// its values
+func FormulaResultType(fieldType FieldType) condition.FieldType {
+	switch fieldType {
+	case FieldNumber, FieldSlider, FieldRating, FieldYear:
+		return condition.FieldTypeNumber
+	case FieldCurrency:
+		return condition.FieldTypeDecimal
+	case FieldCheckbox, FieldSwitch:
+		return condition.FieldTypeBoolean
+	case FieldDate:
+		return condition.FieldTypeDate
+	case FieldDateTime:
+		return condition.FieldTypeDateTime
+	case FieldTime:
+		return condition.FieldTypeTime
+	case FieldSelect, FieldRadio:
+		return condition.FieldTypeSelect
+	case FieldText, FieldEmail, FieldPassword, FieldPhone, FieldURL, FieldHidden,
+		FieldTextarea, FieldRichText, FieldCode, FieldColor, FieldMonth, FieldQuarter:
+		return condition.FieldTypeText
+	default:
+		// Collections, files and layout fields are passed through untyped
+		return ""
+	}
+}
+
+// Formula returns the formula of a computed field
+func (f *Field) Formula() (string, bool) {
+	formula, ok := f.Config["formula"].(string)
+	return formula, ok && formula != ""
+}
+
+// formulaResultType returns the type a field's formula must produce: the
+// "resultType" config entry, else the field type. Formula fields default to
+// numbers.
+func (f *Field) formulaResultType() condition.FieldType {
+	if resultType, ok := f.Config["resultType"].(string); ok && resultType != "" {
+		return FormulaResultType(FieldType(resultType))
+	}
+	if f.Type == FieldFormula {
+		return condition.FieldTypeNumber
+	}
+	return FormulaResultType(f.Type)
+}
+
+// FormulaFields returns the formula environment declared by the schema:
+// every field with its formula type. Formula fields are typed by their
+// result so formulas can build on each other.
+func (s *Schema) FormulaFields() map[string]condition.Field {
+	fields := make(map[string]condition.Field, len(s.Fields))
+	for i := range s.Fields {
+		field := &s.Fields[i]
+		if field.Name == "" {
+			continue
+		}
+		fieldType := FormulaResultType(field.Type)
+		if _, ok := field.Formula(); ok || field.Type == FieldFormula {
+			fieldType = field.formulaResultType()
+		}
+		if fieldType == "" {
+			continue
+		}
+		label := field.Label
+		if label == "" {
+			label = field.Name
+		}
+		fields[field.Name] = condition.Field{
+			Name:  field.Name,
+			Label: label,
+			Type:  fieldType,
+		}
+	}
+	return fields
+}
+
+// formulaEvaluator returns the schema evaluator or a default one
+func (s *Schema) formulaEvaluator() *condition.Evaluator {
+	if s.evaluator != nil {
+		return s.evaluator
+	}
+	return condition.NewEvaluator(nil, condition.DefaultEvalOptions())
+}
+
+// ValidateFormulas compiles every field formula against the schema fields
+// and reports errors per field
+func (s *Schema) ValidateFormulas() error {
+	evaluator := s.formulaEvaluator()
+	fields := s.FormulaFields()
+	collector := NewErrorCollector()
+
+	for i := range s.Fields {
+		field := &s.Fields[i]
+		formula, ok := field.Formula()
+		if !ok {
+			if field.Type == FieldFormula {
+				collector.AddFieldError(field.Name, NewFieldError(field.Name, "missing_formula", "formula field requires a formula in config"))
+			}
+			continue
+		}
+		if err := evaluator.ValidateFormula(field.Name, formula, field.formulaResultType(), fields); err != nil {
+			collector.AddFieldError(field.Name, formulaFieldError(field.Name, err))
+		}
+	}
+
+	if collector.HasErrors() {
+		return collector.Errors()
+	}
+	return nil
+}
+
+// ComputeFormulas evaluates the formula fields in declaration order and
+// returns their values. Each result is visible to the formulas after it.
+func (s *Schema) ComputeFormulas(ctx context.Context, data map[string]any) (map[string]any, error) {
+	evaluator := s.formulaEvaluator()
+	fields := s.FormulaFields()
+
+	values := make(map[string]any, len(data))
+	for k, v := range data {
+		values[k] = v
+	}
+	evalCtx := condition.NewEvalContext(values, condition.DefaultEvalOptions())
+	for _, field := range fields {
+		if err := evalCtx.RegisterField(field); err != nil {
+			return nil, err
+		}
+	}
+
+	computed := make(map[string]any)
+	for i := range s.Fields {
+		field := &s.Fields[i]
+		formula, ok := field.Formula()
+		if !ok {
+			continue
+		}
+		value, err := evaluator.EvaluateFormula(ctx, formula, field.formulaResultType(), evalCtx)
+		if err != nil {
+			return nil, formulaFieldError(field.Name, err)
+		}
+		computed[field.Name] = value
+		values[field.Name] = value
+	}
+	return computed, nil
+}
+
+// formulaFieldError converts a formula error into a field error that keeps
+// the position of the problem
+func formulaFieldError(fieldName string, err error) SchemaError {
+	var formulaErr *condition.FormulaError
+	if !errors.As(err, &formulaErr) {
+		return NewFieldError(fieldName, "invalid_formula", err.Error())
+	}
+
+	code := "invalid_formula"
+	if errors.Is(err, condition.ErrFormulaType) {
+		code = "formula_type_mismatch"
+	}
+	schemaErr := NewFieldError(fieldName, code, formulaErr.Message).
+		WithDetail("formula", formulaErr.Formula)
+	if formulaErr.Line > 0 {
+		schemaErr = schemaErr.
+			WithDetail("line", formulaErr.Line).
+			WithDetail("column", formulaErr.Column)
+	}
+	return schemaErr
+}
+
+// formulaError describes a formula failure in a message
+func formulaError(fieldName string, err error) SchemaError {
+	return NewValidationError("formula_evaluation_failed", fmt.Sprintf("field %s: %v", fieldName, err))
+}
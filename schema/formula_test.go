@@ -0,0 +1,101 @@
+package schema
+
+import (
+	"context"
+	"testing"
+
+	"github.com/niiniyare/ruun/pkg/condition"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type FormulaTestSuite struct {
+	suite.Suite
+	ctx context.Context
+}
+
+func (suite *FormulaTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+}
+func TestFormulaTestSuite(t *testing.T) {
+	suite.Run(t, new(FormulaTestSuite))
+}
+
+func (suite *FormulaTestSuite) invoiceSchema(totalFormula string) *Schema {
+	return &Schema{
+		ID:    "invoice",
+		Title: "Invoice",
+		Fields: []Field{
+			{Name: "customer", Type: FieldText, Label: "Customer"},
+			{Name: "quantity", Type: FieldNumber, Label: "Quantity"},
+			{Name: "unit_price", Type: FieldCurrency, Label: "Unit price"},
+			{Name: "issued", Type: FieldDate, Label: "Issued"},
+			{Name: "subtotal", Type: FieldFormula, Label: "Subtotal", Config: map[string]any{
+				"formula": "quantity * unit_price", "resultType": "currency",
+			}},
+			{Name: "total", Type: FieldCurrency, Label: "Total", Config: map[string]any{
+				"formula": totalFormula,
+			}},
+			{Name: "due", Type: FieldDate, Label: "Due", Config: map[string]any{
+				"formula": "addBusinessDays(issued, 10)",
+			}},
+		},
+	}
+}
+
+func (suite *FormulaTestSuite) TestFormulaFields() {
+	fields := suite.invoiceSchema("money(subtotal * decimal('1.16'))").FormulaFields()
+	require.Equal(suite.T(), condition.FieldTypeText, fields["customer"].Type)
+	require.Equal(suite.T(), condition.FieldTypeNumber, fields["quantity"].Type)
+	require.Equal(suite.T(), condition.FieldTypeDecimal, fields["unit_price"].Type)
+	require.Equal(suite.T(), condition.FieldTypeDecimal, fields["subtotal"].Type)
+	require.Equal(suite.T(), condition.FieldTypeDate, fields["due"].Type)
+}
+
+func (suite *FormulaTestSuite) TestValidateFormulas() {
+	require.NoError(suite.T(), suite.invoiceSchema("subtotal * decimal('1.16')").ValidateFormulas())
+
+	err := suite.invoiceSchema("customer - subtotal").ValidateFormulas()
+	require.Error(suite.T(), err)
+	collection, ok := err.(*ValidationErrorCollection)
+	require.True(suite.T(), ok)
+	require.Equal(suite.T(), 1, collection.Count())
+	fieldErr := collection.Errors()[0]
+	require.Equal(suite.T(), "total", fieldErr.Field())
+	require.Equal(suite.T(), "invalid_formula", fieldErr.Code())
+	require.Equal(suite.T(), 1, fieldErr.Details()["line"])
+
+	err = suite.invoiceSchema("issued").ValidateFormulas()
+	require.Error(suite.T(), err)
+	require.Equal(suite.T(), "formula_type_mismatch", err.(*ValidationErrorCollection).Errors()[0].Code())
+}
+
+func (suite *FormulaTestSuite) TestParseReportsFormulaErrors() {
+	parser := NewParser()
+	_, err := parser.Parse(suite.ctx, []byte(`{
+		"id": "invoice",
+		"type": "form",
+		"title": "Invoice",
+		"fields": [
+			{"name": "quantity", "type": "number", "label": "Quantity"},
+			{"name": "total", "type": "formula", "label": "Total", "config": {"formula": "quantity *"}}
+		]
+	}`))
+	require.Error(suite.T(), err)
+	collection, ok := err.(*ValidationErrorCollection)
+	require.True(suite.T(), ok)
+	require.Contains(suite.T(), collection.ErrorsByField(), "total")
+}
+
+func (suite *FormulaTestSuite) TestComputeFormulas() {
+	schema := suite.invoiceSchema("money(subtotal * decimal('1.16'))")
+	computed, err := schema.ComputeFormulas(suite.ctx, map[string]any{
+		"quantity":   3,
+		"unit_price": "19.99",
+		"issued":     "2026-10-16",
+	})
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), "59.97", computed["subtotal"].(condition.Decimal).String())
+	require.Equal(suite.T(), "69.57", computed["total"].(condition.Decimal).String())
+	require.Contains(suite.T(), computed, "due")
+}
@@ -150,6 +150,17 @@ type StorageBackend interface {
 	Health(ctx context.Context) error
 }
 
+// RecordStorage is implemented by storage backends that can keep small
+// mutable records, such as schema lifecycles, outside the versioned schema
+// namespace. Records are overwritten in place and are never versioned,
+// indexed or returned by List.
+type RecordStorage interface {
+	// GetRecord returns a record, or nil when it does not exist
+	GetRecord(ctx context.Context, key string) ([]byte, error)
+	SetRecord(ctx context.Context, key string, data []byte) error
+	DeleteRecord(ctx context.Context, key string) error
+}
+
 // CacheBackend defines interface for schema caching (low-level byte caching)
 type CacheBackend interface {
 	// Get retrieves cached data
@@ -183,6 +194,17 @@ type EventDispatcher interface {
 	GetHandlers(eventType string) []EventHandler
 }
 
+// RuntimeObserver is notified synchronously of the changes a Runtime
+// completes; it cannot veto them
+type RuntimeObserver interface {
+	// OnSubmitted receives the submitted values, encrypted where the schema
+	// encrypts them
+	OnSubmitted(ctx context.Context, schema *Schema, data map[string]any)
+	// OnWorkflowTransition receives the history entry of an applied
+	// workflow action
+	OnWorkflowTransition(ctx context.Context, schema *Schema, entry WorkflowHistoryEntry)
+}
+
 // ==============================================================================
 // STATE MANAGEMENT INTERFACE
 // ==============================================================================
@@ -0,0 +1,175 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// Fields, actions and layout blocks remember the zero values their source
+// JSON sets explicitly, such as "required": false, which omitempty would
+// otherwise drop. Composition merges on key presence, so a child can set
+// required, hidden or disabled back to false over its base.
+
+// jsonZeros maps JSON keys to the zero values set on them
+type jsonZeros map[string]json.RawMessage
+
+var (
+	fieldOmitempty       = omitemptyKeys(reflect.TypeFor[Field]())
+	actionOmitempty      = omitemptyKeys(reflect.TypeFor[Action]())
+	layoutBlockOmitempty = omitemptyKeys(reflect.TypeFor[LayoutBlock]())
+)
+
+// UnmarshalJSON decodes a field and records the zero values it sets explicitly
+func (f *Field) UnmarshalJSON(data []byte) error {
+	type plain Field
+	if err := json.Unmarshal(data, (*plain)(f)); err != nil {
+		return err
+	}
+	f.explicitZeros = explicitZeros(data, fieldOmitempty)
+	return nil
+}
+
+// MarshalJSON encodes a field, keeping the zero values it sets explicitly
+func (f Field) MarshalJSON() ([]byte, error) {
+	type plain Field
+	data, err := json.Marshal(plain(f))
+	if err != nil {
+		return nil, err
+	}
+	return withExplicitZeros(data, f.explicitZeros)
+}
+
+// UnmarshalJSON decodes an action and records the zero values it sets explicitly
+func (a *Action) UnmarshalJSON(data []byte) error {
+	type plain Action
+	if err := json.Unmarshal(data, (*plain)(a)); err != nil {
+		return err
+	}
+	a.explicitZeros = explicitZeros(data, actionOmitempty)
+	return nil
+}
+
+// MarshalJSON encodes an action, keeping the zero values it sets explicitly
+func (a Action) MarshalJSON() ([]byte, error) {
+	type plain Action
+	data, err := json.Marshal(plain(a))
+	if err != nil {
+		return nil, err
+	}
+	return withExplicitZeros(data, a.explicitZeros)
+}
+
+// UnmarshalJSON decodes a layout block and records the zero values it sets
+// explicitly
+func (b *LayoutBlock) UnmarshalJSON(data []byte) error {
+	type plain LayoutBlock
+	if err := json.Unmarshal(data, (*plain)(b)); err != nil {
+		return err
+	}
+	b.explicitZeros = explicitZeros(data, layoutBlockOmitempty)
+	return nil
+}
+
+// MarshalJSON encodes a layout block, keeping the zero values it sets explicitly
+func (b LayoutBlock) MarshalJSON() ([]byte, error) {
+	type plain LayoutBlock
+	data, err := json.Marshal(plain(b))
+	if err != nil {
+		return nil, err
+	}
+	return withExplicitZeros(data, b.explicitZeros)
+}
+
+// explicitZeros returns the omitempty keys of a JSON object whose value is
+// false, 0 or ""
+func explicitZeros(data []byte, omitempty map[string]bool) jsonZeros {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil
+	}
+	var zeros jsonZeros
+	for key, value := range raw {
+		if !omitempty[key] || !isZeroJSONLiteral(value) {
+			continue
+		}
+		if zeros == nil {
+			zeros = make(jsonZeros)
+		}
+		zeros[key] = value
+	}
+	return zeros
+}
+
+func isZeroJSONLiteral(value json.RawMessage) bool {
+	switch string(bytes.TrimSpace(value)) {
+	case "false", "0", `""`:
+		return true
+	}
+	return false
+}
+
+// withExplicitZeros adds recorded zero values that marshaling dropped back to
+// an encoded object
+func withExplicitZeros(data []byte, zeros jsonZeros) ([]byte, error) {
+	if len(zeros) == 0 {
+		return data, nil
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	for key, value := range zeros {
+		if _, ok := raw[key]; !ok {
+			raw[key] = value
+		}
+	}
+	return json.Marshal(raw)
+}
+
+// jsonKeyInfo describes how a struct field is encoded
+type jsonKeyInfo struct {
+	omitempty bool
+	typ       reflect.Type
+}
+
+// jsonKeys returns the encoded keys of a struct type. Non-struct types have none.
+func jsonKeys(t reflect.Type) map[string]jsonKeyInfo {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	keys := make(map[string]jsonKeyInfo)
+	if t == nil || t.Kind() != reflect.Struct {
+		return keys
+	}
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		keys[name] = jsonKeyInfo{
+			omitempty: strings.Contains(options, "omitempty"),
+			typ:       field.Type,
+		}
+	}
+	return keys
+}
+
+// omitemptyKeys returns the keys of a struct type tagged omitempty
+func omitemptyKeys(t reflect.Type) map[string]bool {
+	keys := make(map[string]bool)
+	for name, info := range jsonKeys(t) {
+		if info.omitempty {
+			keys[name] = true
+		}
+	}
+	return keys
+}
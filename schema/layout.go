@@ -23,6 +23,7 @@ const (
 type LayoutBlock struct {
 	Type BlockType `json:"type"`
 	ID   string    `json:"id"`
+	Ref  string    `json:"$ref,omitempty"` // Block of another schema as "id@version#block"; set values override it
 
 	// Common fields across all block types
 	Title       string   `json:"title,omitempty"`
@@ -54,6 +55,9 @@ type LayoutBlock struct {
 	// Step-specific
 	Skippable  bool `json:"skippable,omitempty"`
 	Validation bool `json:"validation,omitempty"`
+
+	// explicitZeros holds zero values set in the source JSON; see MarshalJSON
+	explicitZeros jsonZeros
 }
 
 // Layout defines the visual structure of form elements
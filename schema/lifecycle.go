@@ -0,0 +1,454 @@
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// LifecycleState is the review state of a schema revision
+type LifecycleState string
+
+const (
+	LifecycleDraft     LifecycleState = "draft"
+	LifecycleInReview  LifecycleState = "in_review"
+	LifecyclePublished LifecycleState = "published"
+	LifecycleArchived  LifecycleState = "archived"
+)
+
+// lifecycleTransitions lists the states each state may move to. Published
+// revisions are immutable; they can only be archived.
+var lifecycleTransitions = map[LifecycleState][]LifecycleState{
+	LifecycleDraft:     {LifecycleInReview, LifecycleArchived},
+	LifecycleInReview:  {LifecycleDraft, LifecyclePublished, LifecycleArchived},
+	LifecyclePublished: {LifecycleArchived},
+	LifecycleArchived:  {},
+}
+
+// CanTransition reports whether a revision may move from one state to another
+func (s LifecycleState) CanTransition(to LifecycleState) bool {
+	return slices.Contains(lifecycleTransitions[s], to)
+}
+
+// Environment is a deployment stage a published revision can be served in
+type Environment string
+
+const (
+	EnvironmentDev     Environment = "dev"
+	EnvironmentStaging Environment = "staging"
+	EnvironmentProd    Environment = "prod"
+)
+
+// Environments lists the deployment stages in promotion order
+var Environments = []Environment{EnvironmentDev, EnvironmentStaging, EnvironmentProd}
+
+// ParseEnvironment normalizes an environment name, accepting the long forms
+// used by the enricher such as "production"
+func ParseEnvironment(name string) (Environment, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "dev", "development", "local":
+		return EnvironmentDev, nil
+	case "staging", "stage":
+		return EnvironmentStaging, nil
+	case "prod", "production":
+		return EnvironmentProd, nil
+	}
+	return "", fmt.Errorf("unknown environment: %s", name)
+}
+
+// SchemaRevision is one submitted version of a schema with its review state
+type SchemaRevision struct {
+	Revision  int             `json:"revision"`
+	State     LifecycleState  `json:"state"`
+	Data      json.RawMessage `json:"data"`
+	Comment   string          `json:"comment,omitempty"`
+	CreatedAt time.Time       `json:"createdAt"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+}
+
+// SchemaLifecycle tracks the revisions of a schema and which published
+// revision each environment serves
+type SchemaLifecycle struct {
+	SchemaID     string              `json:"schemaId"`
+	Revisions    []*SchemaRevision   `json:"revisions"`
+	Environments map[Environment]int `json:"environments"`
+	UpdatedAt    time.Time           `json:"updatedAt"`
+}
+
+// Revision returns a revision by number
+func (l *SchemaLifecycle) Revision(revision int) (*SchemaRevision, bool) {
+	for _, rev := range l.Revisions {
+		if rev.Revision == revision {
+			return rev, true
+		}
+	}
+	return nil, false
+}
+
+// Latest returns the most recent revision
+func (l *SchemaLifecycle) Latest() *SchemaRevision {
+	if len(l.Revisions) == 0 {
+		return nil
+	}
+	return l.Revisions[len(l.Revisions)-1]
+}
+
+// Deployed returns the revision served in an environment
+func (l *SchemaLifecycle) Deployed(env Environment) (*SchemaRevision, bool) {
+	revision, ok := l.Environments[env]
+	if !ok {
+		return nil, false
+	}
+	return l.Revision(revision)
+}
+
+func (l *SchemaLifecycle) addRevision(data []byte, now time.Time) *SchemaRevision {
+	next := 1
+	if latest := l.Latest(); latest != nil {
+		next = latest.Revision + 1
+	}
+	rev := &SchemaRevision{
+		Revision:  next,
+		State:     LifecycleDraft,
+		Data:      json.RawMessage(data),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	l.Revisions = append(l.Revisions, rev)
+	l.UpdatedAt = now
+	return rev
+}
+
+// prune drops the oldest revisions once there are more than max, keeping
+// those deployed to an environment and the latest draft. A max of zero or
+// less keeps every revision
+func (l *SchemaLifecycle) prune(max int) {
+	if max <= 0 || len(l.Revisions) <= max {
+		return
+	}
+	keep := make(map[int]bool, len(l.Environments)+1)
+	for _, revision := range l.Environments {
+		keep[revision] = true
+	}
+	for i := len(l.Revisions) - 1; i >= 0; i-- {
+		if l.Revisions[i].State == LifecycleDraft {
+			keep[l.Revisions[i].Revision] = true
+			break
+		}
+	}
+	excess := len(l.Revisions) - max
+	revisions := l.Revisions[:0]
+	for _, rev := range l.Revisions {
+		if excess > 0 && !keep[rev.Revision] {
+			excess--
+			continue
+		}
+		revisions = append(revisions, rev)
+	}
+	clear(l.Revisions[len(revisions):])
+	l.Revisions = revisions
+}
+
+// LifecycleStore persists schema lifecycles
+type LifecycleStore interface {
+	// Load returns the lifecycle of a schema, or nil when it has none
+	Load(ctx context.Context, id string) (*SchemaLifecycle, error)
+	Save(ctx context.Context, lifecycle *SchemaLifecycle) error
+	Delete(ctx context.Context, id string) error
+}
+
+// StorageLifecycleStore keeps lifecycle records in a StorageBackend, so every
+// node sharing the storage sees the same revisions and environment pointers.
+// A lifecycle holds every revision, so it is written as an unversioned record
+// when the backend implements RecordStorage; other backends store it under a
+// reserved "_lifecycle." schema ID.
+type StorageLifecycleStore struct {
+	storage StorageBackend
+}
+
+// NewStorageLifecycleStore creates a lifecycle store on top of storage
+func NewStorageLifecycleStore(storage StorageBackend) *StorageLifecycleStore {
+	return &StorageLifecycleStore{storage: storage}
+}
+
+// Load implements LifecycleStore
+func (s *StorageLifecycleStore) Load(ctx context.Context, id string) (*SchemaLifecycle, error) {
+	data, err := getRecord(ctx, s.storage, lifecycleRecordPrefix+id)
+	if err != nil {
+		return nil, fmt.Errorf("load lifecycle: %w", err)
+	}
+	if data == nil {
+		return nil, nil
+	}
+	var lifecycle SchemaLifecycle
+	if err := json.Unmarshal(data, &lifecycle); err != nil {
+		return nil, fmt.Errorf("decode lifecycle: %w", err)
+	}
+	return &lifecycle, nil
+}
+
+// Save implements LifecycleStore
+func (s *StorageLifecycleStore) Save(ctx context.Context, lifecycle *SchemaLifecycle) error {
+	data, err := json.Marshal(lifecycle)
+	if err != nil {
+		return fmt.Errorf("encode lifecycle: %w", err)
+	}
+	if err := setRecord(ctx, s.storage, lifecycleRecordPrefix+lifecycle.SchemaID, data); err != nil {
+		return fmt.Errorf("save lifecycle: %w", err)
+	}
+	return nil
+}
+
+// Delete implements LifecycleStore
+func (s *StorageLifecycleStore) Delete(ctx context.Context, id string) error {
+	return deleteRecord(ctx, s.storage, lifecycleRecordPrefix+id)
+}
+
+// SetLifecycleStore replaces the lifecycle store, which defaults to the
+// registry's storage backend
+func (r *Registry) SetLifecycleStore(store LifecycleStore) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lifecycle = store
+}
+
+// GetLifecycle returns the revisions and environment pointers of a schema
+func (r *Registry) GetLifecycle(ctx context.Context, id string) (*SchemaLifecycle, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	lifecycle, err := r.loadLifecycle(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if lifecycle == nil {
+		return nil, fmt.Errorf("schema not found: %s", id)
+	}
+	return lifecycle, nil
+}
+
+// GetRevision returns any revision of a schema, including drafts and
+// archived revisions, for review and auditing
+func (r *Registry) GetRevision(ctx context.Context, id string, revision int) (*Schema, error) {
+	lifecycle, err := r.GetLifecycle(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	rev, ok := lifecycle.Revision(revision)
+	if !ok {
+		return nil, fmt.Errorf("revision %d of schema %s not found", revision, id)
+	}
+	return r.parser.Parse(ctx, rev.Data)
+}
+
+// SubmitForReview moves a draft revision to review
+func (r *Registry) SubmitForReview(ctx context.Context, id string, revision int, comment string) error {
+	return r.Transition(ctx, id, revision, LifecycleInReview, comment)
+}
+
+// Publish approves a revision in review, making it deployable
+func (r *Registry) Publish(ctx context.Context, id string, revision int, comment string) error {
+	return r.Transition(ctx, id, revision, LifecyclePublished, comment)
+}
+
+// Reject returns a revision in review to draft
+func (r *Registry) Reject(ctx context.Context, id string, revision int, comment string) error {
+	return r.Transition(ctx, id, revision, LifecycleDraft, comment)
+}
+
+// Archive retires a revision. Archived revisions stay readable through
+// GetRevision but are no longer served by Get, even where deployed.
+func (r *Registry) Archive(ctx context.Context, id string, revision int, comment string) error {
+	return r.Transition(ctx, id, revision, LifecycleArchived, comment)
+}
+
+// Transition moves a revision to a new lifecycle state
+func (r *Registry) Transition(ctx context.Context, id string, revision int, to LifecycleState, comment string) error {
+	return r.updateLifecycle(ctx, id, func(lifecycle *SchemaLifecycle, now time.Time) error {
+		rev, ok := lifecycle.Revision(revision)
+		if !ok {
+			return fmt.Errorf("revision %d of schema %s not found", revision, id)
+		}
+		if !rev.State.CanTransition(to) {
+			return fmt.Errorf("%w: %s to %s", ErrInvalidTransition, rev.State, to)
+		}
+		rev.State = to
+		rev.Comment = comment
+		rev.UpdatedAt = now
+		return nil
+	})
+}
+
+// Deploy points an environment at a published revision
+func (r *Registry) Deploy(ctx context.Context, id string, revision int, env Environment) error {
+	return r.updateLifecycle(ctx, id, func(lifecycle *SchemaLifecycle, now time.Time) error {
+		return deployRevision(lifecycle, revision, env)
+	})
+}
+
+// Promote deploys the revision serving one environment to another, e.g.
+// staging to prod. The source revision is read and the target pointer moved
+// under the same lock, so concurrent promotes and deploys cannot interleave.
+func (r *Registry) Promote(ctx context.Context, id string, from, to Environment) error {
+	return r.updateLifecycle(ctx, id, func(lifecycle *SchemaLifecycle, now time.Time) error {
+		rev, ok := lifecycle.Deployed(from)
+		if !ok {
+			return fmt.Errorf("%w: %s has no revision of %s", ErrSchemaNotPublished, from, id)
+		}
+		return deployRevision(lifecycle, rev.Revision, to)
+	})
+}
+
+// deployRevision points env at a published revision of lifecycle
+func deployRevision(lifecycle *SchemaLifecycle, revision int, env Environment) error {
+	rev, ok := lifecycle.Revision(revision)
+	if !ok {
+		return fmt.Errorf("revision %d of schema %s not found", revision, lifecycle.SchemaID)
+	}
+	if rev.State != LifecyclePublished {
+		return fmt.Errorf("%w: revision %d is %s", ErrSchemaNotPublished, revision, rev.State)
+	}
+	if !slices.Contains(Environments, env) {
+		return fmt.Errorf("unknown environment: %s", env)
+	}
+	lifecycle.Environments[env] = revision
+	return nil
+}
+
+// updateLifecycle applies fn to a schema's lifecycle and saves it
+func (r *Registry) updateLifecycle(ctx context.Context, id string, fn func(*SchemaLifecycle, time.Time) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	lifecycle, err := r.loadLifecycle(ctx, id)
+	if err != nil {
+		return err
+	}
+	if lifecycle == nil {
+		return fmt.Errorf("schema not found: %s", id)
+	}
+	now := time.Now()
+	if err := fn(lifecycle, now); err != nil {
+		return err
+	}
+	lifecycle.UpdatedAt = now
+	if err := r.saveLifecycle(ctx, lifecycle); err != nil {
+		return err
+	}
+	// Schemas composed from this one may now resolve to another revision
+	r.resolver.Invalidate(id)
+
+	if r.config.EnableEvents {
+		actorID, tenantID := registryActor(ctx)
+		r.events.Emit(&RegistryEvent{
+			Type:      EventSchemaUpdated,
+			SchemaID:  id,
+			ActorID:   actorID,
+			TenantID:  tenantID,
+			Timestamp: now,
+			Data:      map[string]any{"lifecycle": true},
+		})
+	}
+	return nil
+}
+
+// recordRevision adds a draft revision for newly stored schema data
+func (r *Registry) recordRevision(ctx context.Context, id string, data []byte) error {
+	lifecycle, err := r.loadLifecycle(ctx, id)
+	if err != nil {
+		return err
+	}
+	if lifecycle == nil {
+		lifecycle = &SchemaLifecycle{SchemaID: id, Environments: make(map[Environment]int)}
+	}
+	lifecycle.addRevision(data, time.Now())
+	lifecycle.prune(r.config.MaxVersions)
+	return r.saveLifecycle(ctx, lifecycle)
+}
+
+// getDeployed returns the revision deployed to the caller's environment
+func (r *Registry) getDeployed(ctx context.Context, id string, opts []EnrichOption) (*Schema, error) {
+	config := &EnrichConfig{Environment: r.config.DefaultEnvironment, Extensions: make(map[string]any)}
+	for _, opt := range opts {
+		opt(config)
+	}
+	env, err := ParseEnvironment(config.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	lifecycle, err := r.loadLifecycle(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if lifecycle == nil {
+		return nil, fmt.Errorf("schema not found: %s", id)
+	}
+	rev, ok := lifecycle.Deployed(env)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s in %s", ErrSchemaNotPublished, id, env)
+	}
+	if rev.State == LifecycleArchived {
+		return nil, fmt.Errorf("%w: %s revision %d", ErrSchemaArchived, id, rev.Revision)
+	}
+
+	// Bases and references resolve to their revisions deployed in env
+	schema, err := r.parser.Parse(withResolverScope(ctx, string(env)), rev.Data)
+	if err != nil {
+		return nil, fmt.Errorf("parse schema: %w", err)
+	}
+	if r.config.ValidateOnLoad {
+		if err := schema.Validate(ctx); err != nil {
+			return nil, fmt.Errorf("schema validation failed: %w", err)
+		}
+	}
+	return schema, nil
+}
+
+// loadLifecycle reads a lifecycle through the registry cache
+func (r *Registry) loadLifecycle(ctx context.Context, id string) (*SchemaLifecycle, error) {
+	cacheKey := r.buildCacheKey(id, lifecycleCacheVersion)
+	if r.cache != nil {
+		if data, err := r.cache.Get(ctx, cacheKey); err == nil {
+			var lifecycle SchemaLifecycle
+			if err := json.Unmarshal(data, &lifecycle); err == nil {
+				return &lifecycle, nil
+			}
+		}
+	}
+
+	lifecycle, err := r.lifecycle.Load(ctx, id)
+	if err != nil || lifecycle == nil {
+		return lifecycle, err
+	}
+	if lifecycle.Environments == nil {
+		lifecycle.Environments = make(map[Environment]int)
+	}
+
+	if r.cache != nil {
+		if data, err := json.Marshal(lifecycle); err == nil {
+			if err := r.cache.Set(ctx, cacheKey, data, r.config.MemoryCacheTTL); err != nil {
+				fmt.Printf("cache set failed: %v\n", err)
+			}
+		}
+	}
+	return lifecycle, nil
+}
+
+func (r *Registry) saveLifecycle(ctx context.Context, lifecycle *SchemaLifecycle) error {
+	if err := r.lifecycle.Save(ctx, lifecycle); err != nil {
+		return err
+	}
+	if r.cache != nil {
+		if err := r.cache.Delete(ctx, r.buildCacheKey(lifecycle.SchemaID, lifecycleCacheVersion)); err != nil {
+			fmt.Printf("cache delete failed: %v\n", err)
+		}
+	}
+	return nil
+}
+
+// lifecycleCacheVersion is the cache key version slot for lifecycle records
+const lifecycleCacheVersion = "lifecycle"
@@ -0,0 +1,241 @@
+package schema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newLifecycleTestRegistry(t *testing.T) *Registry {
+	t.Helper()
+	config := DefaultRegistryConfig()
+	config.ValidateOnStore = false
+	config.ValidateOnLoad = false
+	config.EnableLifecycle = true
+	registry, err := NewRegistry(config)
+	require.NoError(t, err)
+	return registry
+}
+
+func TestParseEnvironment(t *testing.T) {
+	for name, want := range map[string]Environment{
+		"dev":        EnvironmentDev,
+		"Production": EnvironmentProd,
+		"stage":      EnvironmentStaging,
+	} {
+		env, err := ParseEnvironment(name)
+		require.NoError(t, err)
+		require.Equal(t, want, env)
+	}
+	_, err := ParseEnvironment("qa")
+	require.Error(t, err)
+}
+
+func TestLifecycleState_CanTransition(t *testing.T) {
+	require.True(t, LifecycleDraft.CanTransition(LifecycleInReview))
+	require.True(t, LifecycleInReview.CanTransition(LifecyclePublished))
+	require.True(t, LifecyclePublished.CanTransition(LifecycleArchived))
+	require.False(t, LifecycleDraft.CanTransition(LifecyclePublished))
+	require.False(t, LifecyclePublished.CanTransition(LifecycleDraft))
+	require.False(t, LifecycleArchived.CanTransition(LifecyclePublished))
+}
+
+func TestRegistry_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	registry := newLifecycleTestRegistry(t)
+
+	require.NoError(t, registry.Register(ctx, &Schema{ID: "user.profile", Type: TypeForm, Title: "Profile"}))
+
+	// Drafts are not served
+	_, err := registry.Get(ctx, "user.profile", WithEnvironment("dev"))
+	require.True(t, errors.Is(err, ErrSchemaNotPublished))
+
+	// Only published revisions can be deployed
+	require.True(t, errors.Is(registry.Deploy(ctx, "user.profile", 1, EnvironmentDev), ErrSchemaNotPublished))
+	require.True(t, errors.Is(registry.Publish(ctx, "user.profile", 1, ""), ErrInvalidTransition))
+
+	require.NoError(t, registry.SubmitForReview(ctx, "user.profile", 1, "ready"))
+	require.NoError(t, registry.Publish(ctx, "user.profile", 1, "approved"))
+	require.NoError(t, registry.Deploy(ctx, "user.profile", 1, EnvironmentDev))
+
+	schema, err := registry.Get(ctx, "user.profile", WithEnvironment("dev"))
+	require.NoError(t, err)
+	require.Equal(t, "Profile", schema.Title)
+
+	// Production has nothing deployed yet; it is the default environment
+	_, err = registry.Get(ctx, "user.profile")
+	require.True(t, errors.Is(err, ErrSchemaNotPublished))
+
+	require.NoError(t, registry.Promote(ctx, "user.profile", EnvironmentDev, EnvironmentStaging))
+	require.NoError(t, registry.Promote(ctx, "user.profile", EnvironmentStaging, EnvironmentProd))
+
+	// An update creates a draft; deployed environments keep serving revision 1
+	require.NoError(t, registry.Update(ctx, &Schema{ID: "user.profile", Type: TypeForm, Title: "User Profile"}))
+	schema, err = registry.Get(ctx, "user.profile", WithEnvironment("production"))
+	require.NoError(t, err)
+	require.Equal(t, "Profile", schema.Title)
+
+	require.NoError(t, registry.SubmitForReview(ctx, "user.profile", 2, ""))
+	require.NoError(t, registry.Publish(ctx, "user.profile", 2, ""))
+	require.NoError(t, registry.Deploy(ctx, "user.profile", 2, EnvironmentDev))
+	schema, err = registry.Get(ctx, "user.profile", WithEnvironment("dev"))
+	require.NoError(t, err)
+	require.Equal(t, "User Profile", schema.Title)
+
+	lifecycle, err := registry.GetLifecycle(ctx, "user.profile")
+	require.NoError(t, err)
+	require.Len(t, lifecycle.Revisions, 2)
+	require.Equal(t, map[Environment]int{EnvironmentDev: 2, EnvironmentStaging: 1, EnvironmentProd: 1}, lifecycle.Environments)
+
+	// Archived revisions stay readable but are no longer served
+	require.NoError(t, registry.Archive(ctx, "user.profile", 1, "superseded"))
+	_, err = registry.Get(ctx, "user.profile", WithEnvironment("prod"))
+	require.True(t, errors.Is(err, ErrSchemaArchived))
+	archived, err := registry.GetRevision(ctx, "user.profile", 1)
+	require.NoError(t, err)
+	require.Equal(t, "Profile", archived.Title)
+
+	// Lifecycle records are not listed as schemas
+	require.NoError(t, registry.Promote(ctx, "user.profile", EnvironmentDev, EnvironmentProd))
+	schemas, err := registry.List(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, schemas, 1)
+	require.Equal(t, "User Profile", schemas[0].Title)
+}
+
+func TestRegistry_LifecyclePrunesRevisions(t *testing.T) {
+	ctx := context.Background()
+	registry := newLifecycleTestRegistry(t)
+	registry.config.MaxVersions = 3
+
+	require.NoError(t, registry.Register(ctx, &Schema{ID: "user.profile", Type: TypeForm, Title: "v1"}))
+	require.NoError(t, registry.SubmitForReview(ctx, "user.profile", 1, ""))
+	require.NoError(t, registry.Publish(ctx, "user.profile", 1, ""))
+	require.NoError(t, registry.Deploy(ctx, "user.profile", 1, EnvironmentProd))
+	for i := 2; i <= 6; i++ {
+		require.NoError(t, registry.Update(ctx, &Schema{ID: "user.profile", Type: TypeForm, Title: fmt.Sprintf("v%d", i)}))
+	}
+
+	// The deployed revision survives; the oldest undeployed drafts are dropped
+	lifecycle, err := registry.GetLifecycle(ctx, "user.profile")
+	require.NoError(t, err)
+	var revisions []int
+	for _, rev := range lifecycle.Revisions {
+		revisions = append(revisions, rev.Revision)
+	}
+	require.Equal(t, []int{1, 5, 6}, revisions)
+	schema, err := registry.Get(ctx, "user.profile")
+	require.NoError(t, err)
+	require.Equal(t, "v1", schema.Title)
+
+}
+
+// plainStorage hides the RecordStorage methods of a backend
+type plainStorage struct {
+	StorageBackend
+}
+
+func TestRegistry_LifecycleIsNotVersioned(t *testing.T) {
+	ctx := context.Background()
+	registry := newLifecycleTestRegistry(t)
+	storage, err := NewFilesystemStorage(t.TempDir())
+	require.NoError(t, err)
+	registry.storage = storage
+	registry.lifecycle = NewStorageLifecycleStore(storage)
+
+	require.NoError(t, registry.Register(ctx, &Schema{ID: "user.profile", Type: TypeForm, Title: "Profile"}))
+	require.NoError(t, registry.SubmitForReview(ctx, "user.profile", 1, ""))
+	require.NoError(t, registry.Publish(ctx, "user.profile", 1, ""))
+	require.NoError(t, registry.Deploy(ctx, "user.profile", 1, EnvironmentDev))
+
+	// Only the schema write is versioned; lifecycle saves overwrite a record
+	versions, err := storage.ListVersions(ctx, "user.profile")
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+	exists, err := storage.Exists(ctx, reservedIDPrefix+lifecycleRecordPrefix+"user.profile")
+	require.NoError(t, err)
+	require.False(t, exists)
+	record, err := storage.GetRecord(ctx, lifecycleRecordPrefix+"user.profile")
+	require.NoError(t, err)
+	require.NotNil(t, record)
+
+	require.NoError(t, registry.Delete(ctx, "user.profile"))
+	record, err = storage.GetRecord(ctx, lifecycleRecordPrefix+"user.profile")
+	require.NoError(t, err)
+	require.Nil(t, record)
+}
+
+func TestRegistry_ListPagesWithoutLifecycleRecords(t *testing.T) {
+	ctx := context.Background()
+	registry := newLifecycleTestRegistry(t)
+	storage, err := NewFilesystemStorage(t.TempDir())
+	require.NoError(t, err)
+	// Without RecordStorage, lifecycle records sit next to the schemas
+	registry.storage = plainStorage{storage}
+	registry.lifecycle = NewStorageLifecycleStore(registry.storage)
+
+	for _, id := range []string{"a", "b", "c"} {
+		require.NoError(t, registry.Register(ctx, &Schema{ID: id, Type: TypeForm, Title: id}))
+		require.NoError(t, registry.SubmitForReview(ctx, id, 1, ""))
+		require.NoError(t, registry.Publish(ctx, id, 1, ""))
+		require.NoError(t, registry.Deploy(ctx, id, 1, EnvironmentProd))
+	}
+
+	page, err := registry.List(ctx, &StorageFilter{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	require.Equal(t, "a", page[0].ID)
+	require.Equal(t, "b", page[1].ID)
+
+	page, err = registry.List(ctx, &StorageFilter{Offset: 2, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	require.Equal(t, "c", page[0].ID)
+}
+
+func TestRegistry_ConcurrentPromote(t *testing.T) {
+	ctx := context.Background()
+	registry := newLifecycleTestRegistry(t)
+
+	require.NoError(t, registry.Register(ctx, &Schema{ID: "user.profile", Type: TypeForm, Title: "Profile"}))
+	require.NoError(t, registry.Update(ctx, &Schema{ID: "user.profile", Type: TypeForm, Title: "User Profile"}))
+	for _, revision := range []int{1, 2} {
+		require.NoError(t, registry.SubmitForReview(ctx, "user.profile", revision, ""))
+		require.NoError(t, registry.Publish(ctx, "user.profile", revision, ""))
+	}
+	require.NoError(t, registry.Deploy(ctx, "user.profile", 1, EnvironmentDev))
+	require.NoError(t, registry.Deploy(ctx, "user.profile", 2, EnvironmentStaging))
+
+	// Promotions in both directions race; each must move a revision that was
+	// actually serving its source environment at the time
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			from, to := EnvironmentDev, EnvironmentStaging
+			if i%2 == 1 {
+				from, to = EnvironmentStaging, EnvironmentDev
+			}
+			if err := registry.Promote(ctx, "user.profile", from, to); err != nil {
+				errs <- fmt.Errorf("promote %s to %s: %w", from, to, err)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	// Every promote copies one pointer onto the other, so after the first one
+	// both environments serve the same revision
+	lifecycle, err := registry.GetLifecycle(ctx, "user.profile")
+	require.NoError(t, err)
+	require.Equal(t, lifecycle.Environments[EnvironmentDev], lifecycle.Environments[EnvironmentStaging])
+}
@@ -2,8 +2,11 @@ package schema
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"maps"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -18,6 +21,8 @@ type Mixin struct {
 	Name        string `json:"name" validate:"required,min=1,max=200" example:"Audit Fields"`
 	Description string `json:"description,omitempty" validate:"max=500" example:"Standard audit fields for tracking changes"`
 	Version     string `json:"version,omitempty" validate:"semver" example:"1.0.0"`
+	// Parameters substituted into ${name} placeholders when applied
+	Params []MixinParam `json:"params,omitempty" validate:"dive"`
 	// Content
 	Fields   []Field   `json:"fields" validate:"dive"`            // Fields to include
 	Actions  []Action  `json:"actions,omitempty" validate:"dive"` // Actions to include
@@ -31,10 +36,33 @@ type Mixin struct {
 	Meta *Meta `json:"meta,omitempty"` // Creation/update metadata
 }
 
+// MixinParam declares a variable a mixin accepts. Every mixin also accepts
+// "prefix" (prepended to field and action IDs) and "required" (overrides
+// Required on every field).
+type MixinParam struct {
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description,omitempty"`
+	Default     any    `json:"default,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// MixinUse applies a registered mixin to a schema with parameters
+type MixinUse struct {
+	ID     string         `json:"id"`
+	Params map[string]any `json:"params,omitempty"`
+}
+
+// Built-in mixin parameters
+const (
+	MixinParamPrefix   = "prefix"
+	MixinParamRequired = "required"
+)
+
 // MixinRegistry manages available mixins with thread-safe operations
 type MixinRegistry struct {
-	mixins map[string]*Mixin
-	mu     sync.RWMutex
+	mixins    map[string]*Mixin
+	listeners []func(id string)
+	mu        sync.RWMutex
 }
 
 // NewMixinRegistry creates a new mixin registry with built-in mixins
@@ -78,6 +106,24 @@ func (r *MixinRegistry) register(mixin *Mixin, validate bool) error {
 	return nil
 }
 
+// OnChange registers a function called with the mixin ID whenever a mixin is
+// updated or unregistered
+func (r *MixinRegistry) OnChange(fn func(id string)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.listeners = append(r.listeners, fn)
+}
+
+// notify calls change listeners; callers must not hold the lock
+func (r *MixinRegistry) notify(id string) {
+	r.mu.RLock()
+	listeners := slices.Clone(r.listeners)
+	r.mu.RUnlock()
+	for _, fn := range listeners {
+		fn(id)
+	}
+}
+
 // Get retrieves a mixin by ID with thread safety
 func (r *MixinRegistry) Get(id string) (*Mixin, error) {
 	r.mu.RLock()
@@ -109,6 +155,25 @@ func (r *MixinRegistry) ApplyMixinWithEvaluator(schema *Schema, mixinID string,
 	if err != nil {
 		return err
 	}
+	return applyMixin(schema, mixin, prefix, evaluator)
+}
+
+// ApplyMixinWithParams instantiates a mixin with params and applies it to a schema
+func (r *MixinRegistry) ApplyMixinWithParams(schema *Schema, mixinID string, params map[string]any) error {
+	mixin, err := r.Get(mixinID)
+	if err != nil {
+		return err
+	}
+	instance, err := mixin.Instantiate(params)
+	if err != nil {
+		return err
+	}
+	prefix, _ := params[MixinParamPrefix].(string)
+	return applyMixin(schema, instance, prefix, nil)
+}
+
+// applyMixin copies a mixin's fields and actions into a schema
+func applyMixin(schema *Schema, mixin *Mixin, prefix string, evaluator *condition.Evaluator) error {
 	// Apply fields with optional prefix
 	for _, field := range mixin.Fields {
 		newField := field // Copy field
@@ -157,7 +222,7 @@ func (r *MixinRegistry) ApplyMixinWithEvaluator(schema *Schema, mixinID string,
 		appliedMixins = []string{}
 	}
 	mixinList, ok := appliedMixins.([]string)
-	mixinRef := mixinID
+	mixinRef := mixin.ID
 	if !ok {
 		mixinList = []string{}
 	}
@@ -168,11 +233,13 @@ func (r *MixinRegistry) ApplyMixinWithEvaluator(schema *Schema, mixinID string,
 // Unregister removes a mixin from the registry
 func (r *MixinRegistry) Unregister(mixinID string) error {
 	r.mu.Lock()
-	defer r.mu.Unlock()
 	if _, exists := r.mixins[mixinID]; !exists {
+		r.mu.Unlock()
 		return NewValidationError("mixin_not_found", fmt.Sprintf("mixin %s not found", mixinID))
 	}
 	delete(r.mixins, mixinID)
+	r.mu.Unlock()
+	r.notify(mixinID)
 	return nil
 }
 
@@ -185,11 +252,13 @@ func (r *MixinRegistry) Update(mixin *Mixin) error {
 		return fmt.Errorf("invalid mixin %s: %w", mixin.ID, err)
 	}
 	r.mu.Lock()
-	defer r.mu.Unlock()
 	if _, exists := r.mixins[mixin.ID]; !exists {
+		r.mu.Unlock()
 		return NewValidationError("mixin_not_found", fmt.Sprintf("mixin %s not found", mixin.ID))
 	}
 	r.mixins[mixin.ID] = mixin
+	r.mu.Unlock()
+	r.notify(mixin.ID)
 	return nil
 }
 
@@ -237,6 +306,7 @@ func (m *Mixin) Clone() *Mixin {
 		Name:        m.Name,
 		Description: m.Description,
 		Version:     m.Version,
+		Params:      append([]MixinParam{}, m.Params...),
 		Fields:      append([]Field{}, m.Fields...),
 		Actions:     append([]Action{}, m.Actions...),
 		Category:    m.Category,
@@ -245,6 +315,88 @@ func (m *Mixin) Clone() *Mixin {
 	}
 }
 
+// Instantiate returns a copy of the mixin with ${name} placeholders in its
+// fields and actions replaced by params or declared defaults. A placeholder
+// spanning a whole string value is replaced by the parameter value itself.
+func (m *Mixin) Instantiate(params map[string]any) (*Mixin, error) {
+	values := make(map[string]any, len(m.Params)+len(params))
+	declared := make(map[string]bool, len(m.Params))
+	for _, param := range m.Params {
+		declared[param.Name] = true
+		if _, ok := params[param.Name]; !ok {
+			if param.Required {
+				return nil, NewValidationError("mixin_param", fmt.Sprintf("mixin %s requires parameter %s", m.ID, param.Name))
+			}
+			if param.Default != nil {
+				values[param.Name] = param.Default
+			}
+		}
+	}
+	for name, value := range params {
+		if !declared[name] && name != MixinParamPrefix && name != MixinParamRequired {
+			return nil, NewValidationError("mixin_param", fmt.Sprintf("mixin %s has no parameter %s", m.ID, name))
+		}
+		values[name] = value
+	}
+
+	instance := m.Clone()
+	if len(values) > 0 {
+		if err := substituteParams(&instance.Fields, values); err != nil {
+			return nil, fmt.Errorf("instantiate mixin %s fields: %w", m.ID, err)
+		}
+		if err := substituteParams(&instance.Actions, values); err != nil {
+			return nil, fmt.Errorf("instantiate mixin %s actions: %w", m.ID, err)
+		}
+	}
+	if required, ok := values[MixinParamRequired].(bool); ok {
+		for i := range instance.Fields {
+			instance.Fields[i].Required = required
+		}
+	}
+	return instance, nil
+}
+
+// substituteParams replaces ${name} placeholders in the JSON form of target
+func substituteParams(target any, values map[string]any) error {
+	data, err := json.Marshal(target)
+	if err != nil {
+		return err
+	}
+	var tree any
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return err
+	}
+	data, err = json.Marshal(substituteValue(tree, values))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
+func substituteValue(value any, values map[string]any) any {
+	switch v := value.(type) {
+	case string:
+		if strings.HasPrefix(v, "${") && strings.HasSuffix(v, "}") {
+			if replacement, ok := values[v[2:len(v)-1]]; ok {
+				return replacement
+			}
+		}
+		for name, replacement := range values {
+			v = strings.ReplaceAll(v, "${"+name+"}", fmt.Sprint(replacement))
+		}
+		return v
+	case map[string]any:
+		for key, item := range v {
+			v[key] = substituteValue(item, values)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = substituteValue(item, values)
+		}
+	}
+	return value
+}
+
 // registerBuiltInMixins registers common mixins
 func (r *MixinRegistry) registerBuiltInMixins() {
 	// Audit Fields Mixin
@@ -3,6 +3,8 @@ package schema
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -32,6 +34,7 @@ type Parser struct {
 	// Dependency injection
 	evaluator *condition.Evaluator
 	validator *schemaValidator
+	resolver  *SchemaResolver
 
 	// Caching
 	cache     *parserCache
@@ -109,6 +112,13 @@ func WithValidator(validator schemaValidator) ParserOption {
 	}
 }
 
+// WithResolver sets the resolver that expands extends, mixins and $ref
+func WithResolver(resolver *SchemaResolver) ParserOption {
+	return func(p *Parser) {
+		p.resolver = resolver
+	}
+}
+
 // WithStrictMode enables strict parsing mode
 func WithStrictMode(enabled bool) ParserOption {
 	return func(p *Parser) {
@@ -179,7 +189,7 @@ func (p *Parser) Parse(ctx context.Context, data []byte) (*Schema, error) {
 
 	// Check cache
 	if p.config.EnableCache {
-		if cached := p.getFromCache(data); cached != nil {
+		if cached := p.getFromCache(ctx, data); cached != nil {
 			return cached, nil
 		}
 	}
@@ -211,7 +221,7 @@ func (p *Parser) Parse(ctx context.Context, data []byte) (*Schema, error) {
 
 	// Cache result
 	if p.config.EnableCache {
-		p.addToCache(data, schema)
+		p.addToCache(ctx, data, schema)
 	}
 
 	return schema, nil
@@ -420,11 +430,30 @@ func (p *Parser) postProcess(ctx context.Context, schema *Schema) error {
 		}
 	}
 
+	// Composition
+	if schema.IsComposed() {
+		if p.resolver == nil {
+			return NewParseError("unresolved_composition", "schema uses extends, mixins or $ref but the parser has no resolver")
+		}
+		resolved, err := p.resolver.Resolve(ctx, schema)
+		if err != nil {
+			return err
+		}
+		*schema = *resolved
+		if p.evaluator != nil {
+			schema.SetEvaluator(p.evaluator)
+		}
+	}
+
 	// Validation
 	if p.config.ValidateOnParse {
 		if err := p.validateSchema(ctx, schema); err != nil {
 			return err
 		}
+		// Formulas are type checked against the declared fields
+		if err := schema.ValidateFormulas(); err != nil {
+			return err
+		}
 	}
 
 	// Reference validation
@@ -829,6 +858,17 @@ func (c *parserCache) cleanup() {
 	}
 }
 
+// deleteSchema removes every entry that parsed to the given schema ID
+func (c *parserCache) deleteSchema(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, entry := range c.entries {
+		if entry.schema.ID == id {
+			delete(c.entries, key)
+		}
+	}
+}
+
 func (c *parserCache) clear() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -836,25 +876,31 @@ func (c *parserCache) clear() {
 }
 
 // Cache operations on parser
-func (p *Parser) getFromCache(data []byte) *Schema {
+func (p *Parser) getFromCache(ctx context.Context, data []byte) *Schema {
 	if p.cache == nil {
 		return nil
 	}
-	key := p.cacheKey(data)
+	key := p.cacheKey(ctx, data)
 	return p.cache.get(key)
 }
 
-func (p *Parser) addToCache(data []byte, schema *Schema) {
+func (p *Parser) addToCache(ctx context.Context, data []byte, schema *Schema) {
 	if p.cache == nil {
 		return
 	}
-	key := p.cacheKey(data)
+	key := p.cacheKey(ctx, data)
 	p.cache.set(key, schema)
 }
 
-func (p *Parser) cacheKey(data []byte) string {
-	// Simple hash for demo - use proper hash in production
-	return fmt.Sprintf("%d", len(data))
+func (p *Parser) cacheKey(ctx context.Context, data []byte) string {
+	// Key by content so a changed schema never hits a stale entry, and by
+	// resolution scope since composed schemas resolve differently per scope
+	sum := sha256.Sum256(data)
+	key := hex.EncodeToString(sum[:])
+	if scope := resolverScope(ctx); scope != "" {
+		key += "|" + scope
+	}
+	return key
 }
 
 // ClearCache clears the parser cache
@@ -864,6 +910,13 @@ func (p *Parser) ClearCache() {
 	}
 }
 
+// Invalidate removes cached parse results for a schema ID
+func (p *Parser) Invalidate(id string) {
+	if p.cache != nil {
+		p.cache.deleteSchema(id)
+	}
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // Metrics and Statistics
 // ═══════════════════════════════════════════════════════════════════════════
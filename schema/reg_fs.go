@@ -2,15 +2,41 @@ package schema
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 )
 
-// FilesystemStorage implements Storage interface using local filesystem
+const (
+	// fsVersionsDir holds immutable copies of every write, one directory per schema.
+	fsVersionsDir = ".versions"
+	// fsMetadataDir holds a metadata sidecar per schema used for filtering.
+	fsMetadataDir = ".meta"
+	// fsRecordsDir holds unversioned records such as schema lifecycles.
+	fsRecordsDir = ".records"
+)
+
+// FilesystemStorage implements StorageBackend using local filesystem
 type FilesystemStorage struct {
 	basePath string
+	mu       sync.RWMutex
+}
+
+// fsMetadata is the sidecar written next to each schema.
+type fsMetadata struct {
+	storageAttributes
+	ID        string    `json:"id"`
+	Version   string    `json:"version"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	// DeletedAt is set by Delete; the sidecar stays as a tombstone next to
+	// the version history
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
 }
 
 // NewFilesystemStorage creates a new filesystem storage backend
@@ -42,6 +68,8 @@ func (fs *FilesystemStorage) Get(ctx context.Context, id string) ([]byte, error)
 		return nil, ctx.Err()
 	default:
 	}
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
 	data, err := os.ReadFile(filePath)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -52,7 +80,8 @@ func (fs *FilesystemStorage) Get(ctx context.Context, id string) ([]byte, error)
 	return data, nil
 }
 
-// Set stores a schema by ID to the filesystem
+// Set stores a schema by ID to the filesystem, keeping an immutable copy
+// under .versions and refreshing its metadata sidecar
 func (fs *FilesystemStorage) Set(ctx context.Context, id string, data []byte) error {
 	if err := validateSchemaID(id); err != nil {
 		return err
@@ -66,26 +95,57 @@ func (fs *FilesystemStorage) Set(ctx context.Context, id string, data []byte) er
 		return ctx.Err()
 	default:
 	}
-	filePath := fs.getFilePath(id)
-	// Ensure the directory exists
-	dir := filepath.Dir(filePath)
-	if err := os.MkdirAll(dir, 0o755); err != nil {
-		return fmt.Errorf("failed to create directory for schema %s: %w", id, err)
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	now := time.Now()
+	version := newStorageVersion()
+	meta := &fsMetadata{
+		storageAttributes: extractStorageAttributes(data),
+		ID:                id,
+		Version:           version,
+		Size:              int64(len(data)),
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	if previous, err := fs.readMetadata(id); err == nil && !previous.CreatedAt.IsZero() {
+		meta.CreatedAt = previous.CreatedAt
+	}
+	// The version is written first so the current file never points past history
+	if err := writeFileAtomic(fs.getVersionPath(id, version), data); err != nil {
+		return fmt.Errorf("failed to write version %s of schema %s: %w", version, id, err)
+	}
+	if err := writeFileAtomic(fs.getFilePath(id), data); err != nil {
+		return fmt.Errorf("failed to write schema %s: %w", id, err)
+	}
+	metaData, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("failed to encode metadata for schema %s: %w", id, err)
+	}
+	if err := writeFileAtomic(fs.getMetadataPath(id), metaData); err != nil {
+		return fmt.Errorf("failed to write metadata for schema %s: %w", id, err)
+	}
+	return nil
+}
+
+// writeFileAtomic writes data to a temporary file and renames it into place
+func writeFileAtomic(filePath string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		return err
 	}
-	// Write to temporary file first for atomic operation
 	tempPath := filePath + ".tmp"
 	if err := os.WriteFile(tempPath, data, 0o644); err != nil {
-		return fmt.Errorf("failed to write temporary file for schema %s: %w", id, err)
+		return err
 	}
-	// Atomic rename
 	if err := os.Rename(tempPath, filePath); err != nil {
 		os.Remove(tempPath) // Clean up temp file on failure
-		return fmt.Errorf("failed to rename temporary file for schema %s: %w", id, err)
+		return err
 	}
 	return nil
 }
 
-// Delete removes a schema by ID from the filesystem
+// Delete tombstones a schema on the filesystem. The current file is removed
+// and the metadata sidecar records the deletion, while the version history
+// under .versions is kept
 func (fs *FilesystemStorage) Delete(ctx context.Context, id string) error {
 	if err := validateSchemaID(id); err != nil {
 		return err
@@ -96,6 +156,8 @@ func (fs *FilesystemStorage) Delete(ctx context.Context, id string) error {
 		return ctx.Err()
 	default:
 	}
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
 	filePath := fs.getFilePath(id)
 	err := os.Remove(filePath)
 	if err != nil {
@@ -104,17 +166,33 @@ func (fs *FilesystemStorage) Delete(ctx context.Context, id string) error {
 		}
 		return fmt.Errorf("failed to delete schema %s: %w", id, err)
 	}
+	meta, err := fs.readMetadata(id)
+	if err != nil {
+		meta = &fsMetadata{ID: id}
+	}
+	now := time.Now()
+	meta.DeletedAt = &now
+	metaData, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("failed to encode tombstone for schema %s: %w", id, err)
+	}
+	if err := writeFileAtomic(fs.getMetadataPath(id), metaData); err != nil {
+		return fmt.Errorf("failed to write tombstone for schema %s: %w", id, err)
+	}
 	return nil
 }
 
-// List returns all schema IDs from the filesystem
-func (fs *FilesystemStorage) List(ctx context.Context) ([]string, error) {
+// List returns the sorted schema IDs matching filter. Attributes come from the
+// metadata sidecars; schemas written before sidecars existed are parsed instead
+func (fs *FilesystemStorage) List(ctx context.Context, filter *StorageFilter) ([]string, error) {
 	// Check if context is cancelled
 	select {
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	default:
 	}
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
 	var schemaIDs []string
 	err := filepath.WalkDir(fs.basePath, func(path string, d os.DirEntry, err error) error {
 		// Check context cancellation during walk
@@ -126,6 +204,10 @@ func (fs *FilesystemStorage) List(ctx context.Context) ([]string, error) {
 		if err != nil {
 			return err
 		}
+		// Skip version history and metadata directories
+		if d.IsDir() && path != fs.basePath && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
 		// Skip directories and non-JSON files
 		if d.IsDir() || !strings.HasSuffix(d.Name(), ".json") {
 			return nil
@@ -138,13 +220,228 @@ func (fs *FilesystemStorage) List(ctx context.Context) ([]string, error) {
 		// Remove .json extension and convert path separators to dots
 		schemaID := strings.TrimSuffix(relPath, ".json")
 		schemaID = strings.ReplaceAll(schemaID, string(filepath.Separator), ".")
+		if hasStorageCriteria(filter) {
+			attrs, err := fs.attributes(schemaID, path)
+			if err != nil {
+				return err
+			}
+			if !attrs.matches(filter) {
+				return nil
+			}
+		}
 		schemaIDs = append(schemaIDs, schemaID)
 		return nil
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list schemas: %w", err)
 	}
-	return schemaIDs, nil
+	return pageStorageIDs(schemaIDs, filter), nil
+}
+
+// attributes returns the filterable attributes of a schema from its sidecar,
+// falling back to parsing the schema file
+func (fs *FilesystemStorage) attributes(id, path string) (storageAttributes, error) {
+	if meta, err := fs.readMetadata(id); err == nil {
+		return meta.storageAttributes, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return storageAttributes{}, fmt.Errorf("failed to read schema %s: %w", id, err)
+	}
+	return extractStorageAttributes(data), nil
+}
+
+// GetVersion retrieves an immutable version of a schema
+func (fs *FilesystemStorage) GetVersion(ctx context.Context, id, version string) ([]byte, error) {
+	if err := validateSchemaID(id); err != nil {
+		return nil, err
+	}
+	if err := validateSchemaID(version); err != nil {
+		return nil, fmt.Errorf("invalid version: %w", err)
+	}
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+	data, err := os.ReadFile(fs.getVersionPath(id, version))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("version %s of schema %s not found", version, id)
+		}
+		return nil, fmt.Errorf("failed to read version %s of schema %s: %w", version, id, err)
+	}
+	return data, nil
+}
+
+// ListVersions returns a schema's versions from oldest to newest
+func (fs *FilesystemStorage) ListVersions(ctx context.Context, id string) ([]string, error) {
+	if err := validateSchemaID(id); err != nil {
+		return nil, err
+	}
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+	entries, err := os.ReadDir(filepath.Join(fs.basePath, fsVersionsDir, id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, fmt.Errorf("failed to list versions of schema %s: %w", id, err)
+	}
+	versions := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			versions = append(versions, strings.TrimSuffix(entry.Name(), ".json"))
+		}
+	}
+	return sortStorageVersions(versions), nil
+}
+
+// GetBatch retrieves multiple schemas, omitting IDs that do not exist
+func (fs *FilesystemStorage) GetBatch(ctx context.Context, ids []string) (map[string][]byte, error) {
+	result := make(map[string][]byte, len(ids))
+	for _, id := range ids {
+		if err := validateSchemaID(id); err != nil {
+			return nil, fmt.Errorf("invalid schema ID %s: %w", id, err)
+		}
+		data, err := fs.Get(ctx, id)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			continue
+		}
+		result[id] = data
+	}
+	return result, nil
+}
+
+// SetBatch stores multiple schemas, validating all of them before writing any
+func (fs *FilesystemStorage) SetBatch(ctx context.Context, items map[string][]byte) error {
+	for id, data := range items {
+		if err := validateSchemaID(id); err != nil {
+			return fmt.Errorf("invalid schema ID %s: %w", id, err)
+		}
+		if len(data) == 0 {
+			return fmt.Errorf("cannot store empty schema data for %s", id)
+		}
+	}
+	for id, data := range items {
+		if err := fs.Set(ctx, id, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetMetadata returns storage metadata for a schema
+func (fs *FilesystemStorage) GetMetadata(ctx context.Context, id string) (*StorageMetadata, error) {
+	if err := validateSchemaID(id); err != nil {
+		return nil, err
+	}
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+	if meta, err := fs.readMetadata(id); err == nil {
+		if meta.DeletedAt != nil {
+			return nil, fmt.Errorf("schema %s not found", id)
+		}
+		return &StorageMetadata{
+			ID:   id,
+			Size: meta.Size,
+			BaseMetadata: BaseMetadata{
+				Version:   meta.Version,
+				CreatedAt: meta.CreatedAt,
+				UpdatedAt: meta.UpdatedAt,
+			},
+		}, nil
+	}
+	info, err := os.Stat(fs.getFilePath(id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("schema %s not found", id)
+		}
+		return nil, fmt.Errorf("failed to stat schema %s: %w", id, err)
+	}
+	return &StorageMetadata{
+		ID:   id,
+		Size: info.Size(),
+		BaseMetadata: BaseMetadata{
+			UpdatedAt: info.ModTime(),
+		},
+	}, nil
+}
+
+// Health verifies the base directory exists and is writable
+func (fs *FilesystemStorage) Health(ctx context.Context) error {
+	testFile := filepath.Join(fs.basePath, ".health_check")
+	if err := os.WriteFile(testFile, []byte("ok"), 0o644); err != nil {
+		return fmt.Errorf("base directory %s is not writable: %w", fs.basePath, err)
+	}
+	return os.Remove(testFile)
+}
+
+func (fs *FilesystemStorage) readMetadata(id string) (*fsMetadata, error) {
+	data, err := os.ReadFile(fs.getMetadataPath(id))
+	if err != nil {
+		return nil, err
+	}
+	var meta fsMetadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+func (fs *FilesystemStorage) getVersionPath(id, version string) string {
+	return filepath.Join(fs.basePath, fsVersionsDir, id, version+".json")
+}
+
+func (fs *FilesystemStorage) getMetadataPath(id string) string {
+	return filepath.Join(fs.basePath, fsMetadataDir, id+".json")
+}
+
+func (fs *FilesystemStorage) getRecordPath(key string) string {
+	return filepath.Join(fs.basePath, fsRecordsDir, key+".json")
+}
+
+// GetRecord implements RecordStorage
+func (fs *FilesystemStorage) GetRecord(ctx context.Context, key string) ([]byte, error) {
+	if err := validateSchemaID(key); err != nil {
+		return nil, err
+	}
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+	data, err := os.ReadFile(fs.getRecordPath(key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read record %s: %w", key, err)
+	}
+	return data, nil
+}
+
+// SetRecord implements RecordStorage
+func (fs *FilesystemStorage) SetRecord(ctx context.Context, key string, data []byte) error {
+	if err := validateSchemaID(key); err != nil {
+		return err
+	}
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if err := writeFileAtomic(fs.getRecordPath(key), data); err != nil {
+		return fmt.Errorf("failed to write record %s: %w", key, err)
+	}
+	return nil
+}
+
+// DeleteRecord implements RecordStorage
+func (fs *FilesystemStorage) DeleteRecord(ctx context.Context, key string) error {
+	if err := validateSchemaID(key); err != nil {
+		return err
+	}
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if err := os.Remove(fs.getRecordPath(key)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete record %s: %w", key, err)
+	}
+	return nil
 }
 
 // Exists checks if a schema exists in the filesystem
@@ -158,6 +455,8 @@ func (fs *FilesystemStorage) Exists(ctx context.Context, id string) (bool, error
 		return false, ctx.Err()
 	default:
 	}
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
 	filePath := fs.getFilePath(id)
 	_, err := os.Stat(filePath)
 	if err != nil {
@@ -229,6 +528,9 @@ func (fs *FilesystemStorage) Stats(ctx context.Context) (*StorageStats, error) {
 		if err != nil {
 			return err
 		}
+		if d.IsDir() && path != fs.basePath && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
 		if !d.IsDir() && strings.HasSuffix(d.Name(), ".json") {
 			stats.SchemaCount++
 			if info, err := d.Info(); err == nil {
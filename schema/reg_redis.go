@@ -2,14 +2,114 @@ package schema
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-redis/redis/v8"
 )
 
-// RedisStorage implements Storage interface using Redis
+// Redis key layout. A schema's keys carry its ID as hash tag, so the
+// scripts writing a schema run on one Redis Cluster slot and schemas spread
+// over the cluster. The index sets share the {_idx} tag, which lets List
+// intersect them. Schema IDs cannot contain ':' so the auxiliary keys never
+// collide with schema keys:
+//
+//	<prefix>{<id>}                       current schema data, the only key the TTL applies to
+//	<prefix>{<id>}:_versions             hash of version -> data, kept after Delete
+//	<prefix>{<id>}:_meta                 hash of storage metadata, filter attributes, the write revision and the deletedAt tombstone
+//	<prefix>{_idx}:all                   set of every live schema ID
+//	<prefix>{_idx}:<field>:<value>       set of schema IDs per type, category, module and tag
+//	<prefix>{_idx}:_attrs                hash of schema ID -> attributes the index sets hold
+//	<prefix>{_idx}:_revs                 hash of schema ID -> revision the index sets hold
+//	<prefix>_rec:{<key>}                 unversioned records such as schema lifecycles
+//
+// A write updates the schema's keys first and the index sets second, so a
+// failure in between leaves the index stale until the schema is written
+// again; List drops IDs whose schema no longer exists. Schemas written
+// before versions and index sets existed live at <prefix><id>; Migrate
+// moves them into this layout.
+const (
+	redisIndexTag      = "{_idx}:"
+	redisVersionsKey   = ":_versions"
+	redisMetaKey       = ":_meta"
+	redisIndexAttrsKey = "_attrs"
+	redisIndexRevsKey  = "_revs"
+	redisRecordKey     = "_rec:"
+
+	// redisConflictRetries bounds how often a write is retried when the
+	// schema's attributes change between reading them and running the script
+	redisConflictRetries = 5
+)
+
+// redisSetScript writes a schema, its immutable version and its metadata
+// atomically, returning the schema's new write revision. The caller reads the
+// previous attributes; the script returns -1 without writing when they
+// changed in the meantime.
+//
+// KEYS: data, versions, meta
+// ARGV: data, ttl ms, previous attributes JSON or "", attributes JSON,
+// version, now
+var redisSetScript = redis.NewScript(`
+local current = redis.call('HGET', KEYS[3], 'attrs') or ''
+if current ~= ARGV[3] then return -1 end
+local created = redis.call('HGET', KEYS[3], 'createdAt') or ARGV[6]
+redis.call('SET', KEYS[1], ARGV[1])
+local ttl = tonumber(ARGV[2])
+if ttl > 0 then redis.call('PEXPIRE', KEYS[1], ttl) end
+redis.call('HSET', KEYS[2], ARGV[5], ARGV[1])
+redis.call('HSET', KEYS[3], 'attrs', ARGV[4], 'size', string.len(ARGV[1]), 'version', ARGV[5], 'createdAt', created, 'updatedAt', ARGV[6])
+redis.call('HDEL', KEYS[3], 'deletedAt')
+return redis.call('HINCRBY', KEYS[3], 'rev', 1)
+`)
+
+// redisDeleteScript tombstones a schema: the current data is removed, the
+// version history is kept and the metadata records when the schema was
+// deleted. It returns the schema's new write revision, 0 when the schema does
+// not exist and -1 when its attributes changed since the caller read them.
+//
+// KEYS: data, meta
+// ARGV: attributes JSON or "", now
+var redisDeleteScript = redis.NewScript(`
+local current = redis.call('HGET', KEYS[2], 'attrs') or ''
+if current ~= ARGV[1] then return -1 end
+if redis.call('EXISTS', KEYS[1]) == 0 then return 0 end
+redis.call('DEL', KEYS[1])
+redis.call('HSET', KEYS[2], 'deletedAt', ARGV[2])
+return redis.call('HINCRBY', KEYS[2], 'rev', 1)
+`)
+
+// redisIndexScript moves a schema's index entries from the sets of its
+// previously indexed attributes to those of a write. A write older than the
+// indexed revision is skipped, so index updates racing each other settle on
+// the latest write. It returns -1 when the indexed attributes changed since
+// the caller read them, 0 when the write was older and 1 otherwise.
+//
+// KEYS: attributes hash, revisions hash, all-index, previous index sets..., index sets...
+// ARGV: id, previous attributes JSON or "", attributes JSON or "" once
+// deleted, number of previous index sets, revision
+var redisIndexScript = redis.NewScript(`
+local current = redis.call('HGET', KEYS[1], ARGV[1]) or ''
+if current ~= ARGV[2] then return -1 end
+local indexed = tonumber(redis.call('HGET', KEYS[2], ARGV[1]) or '0')
+if indexed >= tonumber(ARGV[5]) then return 0 end
+local previous = tonumber(ARGV[4])
+for i = 4, 3 + previous do redis.call('SREM', KEYS[i], ARGV[1]) end
+for i = 4 + previous, #KEYS do redis.call('SADD', KEYS[i], ARGV[1]) end
+redis.call('HSET', KEYS[2], ARGV[1], ARGV[5])
+if ARGV[3] == '' then
+  redis.call('HDEL', KEYS[1], ARGV[1])
+  redis.call('SREM', KEYS[3], ARGV[1])
+else
+  redis.call('HSET', KEYS[1], ARGV[1], ARGV[3])
+  redis.call('SADD', KEYS[3], ARGV[1])
+end
+return 1
+`)
+
+// RedisStorage implements StorageBackend using Redis
 type RedisStorage struct {
 	client redis.Cmdable
 	prefix string
@@ -57,63 +157,279 @@ func (rs *RedisStorage) Get(ctx context.Context, id string) ([]byte, error) {
 	return data, nil
 }
 
-// Set stores a schema by ID to Redis
+// Set stores a schema by ID to Redis, keeping an immutable version and
+// updating the secondary index sets used by List
 func (rs *RedisStorage) Set(ctx context.Context, id string, data []byte) error {
+	return rs.set(ctx, id, data, rs.ttl)
+}
+
+func (rs *RedisStorage) set(ctx context.Context, id string, data []byte, ttl time.Duration) error {
 	if err := validateSchemaID(id); err != nil {
 		return err
 	}
 	if len(data) == 0 {
 		return fmt.Errorf("cannot store empty schema data for %s", id)
 	}
-	key := rs.getKey(id)
-	var err error
-	if rs.ttl > 0 {
-		err = rs.client.Set(ctx, key, data, rs.ttl).Err()
-	} else {
-		err = rs.client.Set(ctx, key, data, 0).Err()
+	attrs, err := storageAttributesJSON(id, data)
+	if err != nil {
+		return err
 	}
+	for range redisConflictRetries {
+		previous, err := rs.attributes(ctx, id)
+		if err != nil {
+			return err
+		}
+		rev, err := redisSetScript.Run(ctx, rs.client, rs.schemaKeys(id), rs.setArgs(data, ttl, previous, attrs)...).Int64()
+		if err != nil {
+			return fmt.Errorf("failed to set schema %s in Redis: %w", id, err)
+		}
+		if rev > 0 {
+			return rs.index(ctx, id, attrs, rev)
+		}
+	}
+	return fmt.Errorf("failed to set schema %s in Redis: concurrent updates", id)
+}
+
+// attributes returns the stored attributes JSON of a schema, or "" when it has none
+func (rs *RedisStorage) attributes(ctx context.Context, id string) (string, error) {
+	attrs, err := rs.client.HGet(ctx, rs.metaKey(id), "attrs").Result()
+	if err != nil && err != redis.Nil {
+		return "", fmt.Errorf("failed to read attributes of schema %s from Redis: %w", id, err)
+	}
+	return attrs, nil
+}
+
+// storageAttributesJSON encodes the filter attributes of schema data
+func storageAttributesJSON(id string, data []byte) (string, error) {
+	attrs, err := json.Marshal(extractStorageAttributes(data))
 	if err != nil {
-		return fmt.Errorf("failed to set schema %s in Redis: %w", id, err)
+		return "", fmt.Errorf("failed to encode attributes of schema %s: %w", id, err)
 	}
-	return nil
+	return string(attrs), nil
 }
 
-// Delete removes a schema by ID from Redis
+// schemaKeys returns the keys of redisSetScript for a schema
+func (rs *RedisStorage) schemaKeys(id string) []string {
+	return []string{rs.getKey(id), rs.versionsKey(id), rs.metaKey(id)}
+}
+
+// setArgs builds the arguments of redisSetScript for a schema whose stored
+// attributes are previous
+func (rs *RedisStorage) setArgs(data []byte, ttl time.Duration, previous, attrs string) []any {
+	return []any{data, ttl.Milliseconds(), previous, attrs, newStorageVersion(), time.Now().UTC().Format(time.RFC3339Nano)}
+}
+
+// index updates the index sets for revision rev of a schema, whose
+// attributes are attrs or "" once it is deleted
+func (rs *RedisStorage) index(ctx context.Context, id, attrs string, rev int64) error {
+	for range redisConflictRetries {
+		previous, err := rs.indexedAttributes(ctx, id)
+		if err != nil {
+			return err
+		}
+		keys, args := rs.indexArgs(id, previous, attrs, rev)
+		applied, err := redisIndexScript.Run(ctx, rs.client, keys, args...).Int64()
+		if err != nil {
+			return fmt.Errorf("failed to index schema %s in Redis: %w", id, err)
+		}
+		if applied >= 0 {
+			return nil
+		}
+	}
+	return fmt.Errorf("failed to index schema %s in Redis: concurrent updates", id)
+}
+
+// indexedAttributes returns the attributes JSON the index sets hold for a
+// schema, or "" when it is not indexed
+func (rs *RedisStorage) indexedAttributes(ctx context.Context, id string) (string, error) {
+	attrs, err := rs.client.HGet(ctx, rs.indexKey(redisIndexAttrsKey), id).Result()
+	if err != nil && err != redis.Nil {
+		return "", fmt.Errorf("failed to read indexed attributes of schema %s from Redis: %w", id, err)
+	}
+	return attrs, nil
+}
+
+// indexArgs builds the keys and arguments of redisIndexScript
+func (rs *RedisStorage) indexArgs(id, previous, attrs string, rev int64) ([]string, []any) {
+	previousKeys := rs.attributeIndexKeys(previous)
+	keys := []string{rs.indexKey(redisIndexAttrsKey), rs.indexKey(redisIndexRevsKey), rs.indexKey("all")}
+	keys = append(keys, previousKeys...)
+	keys = append(keys, rs.attributeIndexKeys(attrs)...)
+	return keys, []any{id, previous, attrs, len(previousKeys), rev}
+}
+
+// attributeIndexKeys returns the secondary index sets of an attributes JSON
+func (rs *RedisStorage) attributeIndexKeys(attrsJSON string) []string {
+	if attrsJSON == "" {
+		return nil
+	}
+	var attrs storageAttributes
+	if err := json.Unmarshal([]byte(attrsJSON), &attrs); err != nil {
+		return nil
+	}
+	var keys []string
+	if attrs.Type != "" {
+		keys = append(keys, rs.indexKey("type:"+attrs.Type))
+	}
+	if attrs.Category != "" {
+		keys = append(keys, rs.indexKey("category:"+attrs.Category))
+	}
+	if attrs.Module != "" {
+		keys = append(keys, rs.indexKey("module:"+attrs.Module))
+	}
+	for _, tag := range attrs.Tags {
+		keys = append(keys, rs.indexKey("tag:"+tag))
+	}
+	return keys
+}
+
+// Delete tombstones a schema in Redis. It disappears from Get, Exists and
+// List, while its version history stays readable through GetVersion
 func (rs *RedisStorage) Delete(ctx context.Context, id string) error {
 	if err := validateSchemaID(id); err != nil {
 		return err
 	}
-	key := rs.getKey(id)
-	deleted, err := rs.client.Del(ctx, key).Result()
+	for range redisConflictRetries {
+		attrs, err := rs.attributes(ctx, id)
+		if err != nil {
+			return err
+		}
+		keys := []string{rs.getKey(id), rs.metaKey(id)}
+		rev, err := redisDeleteScript.Run(ctx, rs.client, keys, attrs, time.Now().UTC().Format(time.RFC3339Nano)).Int64()
+		if err != nil {
+			return fmt.Errorf("failed to delete schema %s from Redis: %w", id, err)
+		}
+		switch {
+		case rev == 0:
+			return fmt.Errorf("schema %s not found", id)
+		case rev > 0:
+			return rs.index(ctx, id, "", rev)
+		}
+	}
+	return fmt.Errorf("failed to delete schema %s from Redis: concurrent updates", id)
+}
+
+// List returns the sorted schema IDs matching filter. Criteria are resolved
+// server-side by intersecting the secondary index sets
+func (rs *RedisStorage) List(ctx context.Context, filter *StorageFilter) ([]string, error) {
+	indexes := []string{rs.indexKey("all")}
+	if filter != nil {
+		if filter.Type != "" {
+			indexes = append(indexes, rs.indexKey("type:"+filter.Type))
+		}
+		if filter.Category != "" {
+			indexes = append(indexes, rs.indexKey("category:"+filter.Category))
+		}
+		if filter.Module != "" {
+			indexes = append(indexes, rs.indexKey("module:"+filter.Module))
+		}
+		for _, tag := range filter.Tags {
+			indexes = append(indexes, rs.indexKey("tag:"+tag))
+		}
+	}
+	var schemaIDs []string
+	var err error
+	if len(indexes) == 1 {
+		schemaIDs, err = rs.client.SMembers(ctx, indexes[0]).Result()
+	} else {
+		schemaIDs, err = rs.client.SInter(ctx, indexes...).Result()
+	}
 	if err != nil {
-		return fmt.Errorf("failed to delete schema %s from Redis: %w", id, err)
+		return nil, fmt.Errorf("failed to list schemas from Redis: %w", err)
 	}
-	if deleted == 0 {
-		return fmt.Errorf("schema %s not found", id)
+	// Index sets outlive expired schemas and writes interrupted before the
+	// index update; drop IDs whose data is gone
+	if schemaIDs, err = rs.live(ctx, schemaIDs); err != nil {
+		return nil, err
 	}
-	return nil
+	return pageStorageIDs(schemaIDs, filter), nil
 }
 
-// List returns all schema IDs from Redis
-func (rs *RedisStorage) List(ctx context.Context) ([]string, error) {
-	pattern := rs.prefix + "*"
-	var allKeys []string
-	iter := rs.client.Scan(ctx, 0, pattern, 0).Iterator()
-	for iter.Next(ctx) {
-		allKeys = append(allKeys, iter.Val())
+// live filters out IDs whose schema key has expired
+func (rs *RedisStorage) live(ctx context.Context, ids []string) ([]string, error) {
+	if len(ids) == 0 {
+		return ids, nil
 	}
-	if err := iter.Err(); err != nil {
-		return nil, fmt.Errorf("failed to scan Redis keys: %w", err)
+	pipe := rs.client.Pipeline()
+	cmds := make([]*redis.IntCmd, len(ids))
+	for i, id := range ids {
+		cmds[i] = pipe.Exists(ctx, rs.getKey(id))
 	}
-	// Convert keys back to schema IDs
-	var schemaIDs []string
-	for _, key := range allKeys {
-		if strings.HasPrefix(key, rs.prefix) {
-			schemaID := strings.TrimPrefix(key, rs.prefix)
-			schemaIDs = append(schemaIDs, schemaID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to check schema expiry in Redis: %w", err)
+	}
+	live := make([]string, 0, len(ids))
+	for i, cmd := range cmds {
+		if cmd.Val() > 0 {
+			live = append(live, ids[i])
 		}
 	}
-	return schemaIDs, nil
+	return live, nil
+}
+
+// GetVersion retrieves an immutable version of a schema
+func (rs *RedisStorage) GetVersion(ctx context.Context, id, version string) ([]byte, error) {
+	if err := validateSchemaID(id); err != nil {
+		return nil, err
+	}
+	data, err := rs.client.HGet(ctx, rs.versionsKey(id), version).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, fmt.Errorf("version %s of schema %s not found", version, id)
+		}
+		return nil, fmt.Errorf("failed to get version %s of schema %s from Redis: %w", version, id, err)
+	}
+	return data, nil
+}
+
+// ListVersions returns a schema's versions from oldest to newest
+func (rs *RedisStorage) ListVersions(ctx context.Context, id string) ([]string, error) {
+	if err := validateSchemaID(id); err != nil {
+		return nil, err
+	}
+	versions, err := rs.client.HKeys(ctx, rs.versionsKey(id)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list versions of schema %s from Redis: %w", id, err)
+	}
+	return sortStorageVersions(versions), nil
+}
+
+// GetMetadata returns storage metadata for a schema
+func (rs *RedisStorage) GetMetadata(ctx context.Context, id string) (*StorageMetadata, error) {
+	if err := validateSchemaID(id); err != nil {
+		return nil, err
+	}
+	fields, err := rs.client.HGetAll(ctx, rs.metaKey(id)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metadata of schema %s from Redis: %w", id, err)
+	}
+	if len(fields) == 0 || fields["deletedAt"] != "" {
+		return nil, fmt.Errorf("schema %s not found", id)
+	}
+	// The metadata outlives schema data that expired with the TTL
+	if exists, err := rs.Exists(ctx, id); err != nil {
+		return nil, err
+	} else if !exists {
+		return nil, fmt.Errorf("schema %s not found", id)
+	}
+	meta := &StorageMetadata{
+		ID: id,
+		BaseMetadata: BaseMetadata{
+			Version: fields["version"],
+		},
+	}
+	meta.Size, _ = strconv.ParseInt(fields["size"], 10, 64)
+	meta.CreatedAt, _ = time.Parse(time.RFC3339Nano, fields["createdAt"])
+	meta.UpdatedAt, _ = time.Parse(time.RFC3339Nano, fields["updatedAt"])
+	return meta, nil
+}
+
+// Health checks Redis connectivity
+func (rs *RedisStorage) Health(ctx context.Context) error {
+	if err := rs.client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("redis unavailable: %w", err)
+	}
+	return nil
 }
 
 // Exists checks if a schema exists in Redis
@@ -131,7 +447,105 @@ func (rs *RedisStorage) Exists(ctx context.Context, id string) (bool, error) {
 
 // getKey converts schema ID to Redis key
 func (rs *RedisStorage) getKey(id string) string {
-	return rs.prefix + id
+	return rs.prefix + "{" + id + "}"
+}
+
+func (rs *RedisStorage) versionsKey(id string) string {
+	return rs.getKey(id) + redisVersionsKey
+}
+
+func (rs *RedisStorage) metaKey(id string) string {
+	return rs.getKey(id) + redisMetaKey
+}
+
+func (rs *RedisStorage) indexKey(name string) string {
+	return rs.prefix + redisIndexTag + name
+}
+
+func (rs *RedisStorage) recordKey(key string) string {
+	return rs.prefix + redisRecordKey + "{" + key + "}"
+}
+
+// GetRecord implements RecordStorage
+func (rs *RedisStorage) GetRecord(ctx context.Context, key string) ([]byte, error) {
+	if err := validateSchemaID(key); err != nil {
+		return nil, err
+	}
+	data, err := rs.client.Get(ctx, rs.recordKey(key)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get record %s from Redis: %w", key, err)
+	}
+	return data, nil
+}
+
+// SetRecord implements RecordStorage. Records do not expire with the schema TTL
+func (rs *RedisStorage) SetRecord(ctx context.Context, key string, data []byte) error {
+	if err := validateSchemaID(key); err != nil {
+		return err
+	}
+	if err := rs.client.Set(ctx, rs.recordKey(key), data, 0).Err(); err != nil {
+		return fmt.Errorf("failed to set record %s in Redis: %w", key, err)
+	}
+	return nil
+}
+
+// DeleteRecord implements RecordStorage
+func (rs *RedisStorage) DeleteRecord(ctx context.Context, key string) error {
+	if err := validateSchemaID(key); err != nil {
+		return err
+	}
+	if err := rs.client.Del(ctx, rs.recordKey(key)).Err(); err != nil {
+		return fmt.Errorf("failed to delete record %s from Redis: %w", key, err)
+	}
+	return nil
+}
+
+// Migrate moves schemas stored at <prefix><id>, the layout used before
+// versions and index sets existed, into the current layout so they are
+// versioned and listed. It is a one-off step: call it once when upgrading
+// storage written by an earlier release, before serving reads. A legacy key
+// is removed once its schema has been written; if the schema already exists
+// in the current layout the legacy copy is discarded. It returns the number of
+// schemas moved. On Redis Cluster the scan only reaches the node the client
+// routes it to, so run Migrate against each master
+func (rs *RedisStorage) Migrate(ctx context.Context) (int, error) {
+	moved := 0
+	iter := rs.client.Scan(ctx, 0, rs.prefix+"*", 0).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		id := strings.TrimPrefix(key, rs.prefix)
+		// Current keys start with a hash tag or contain ':', which schema IDs cannot
+		if strings.HasPrefix(id, "{") || validateSchemaID(id) != nil {
+			continue
+		}
+		data, err := rs.client.Get(ctx, key).Bytes()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return moved, fmt.Errorf("failed to read legacy schema %s from Redis: %w", id, err)
+		}
+		exists, err := rs.client.Exists(ctx, rs.getKey(id)).Result()
+		if err != nil {
+			return moved, fmt.Errorf("failed to check schema %s in Redis: %w", id, err)
+		}
+		if exists == 0 && len(data) > 0 {
+			if err := rs.set(ctx, id, data, rs.ttl); err != nil {
+				return moved, fmt.Errorf("failed to migrate schema %s: %w", id, err)
+			}
+			moved++
+		}
+		if err := rs.client.Del(ctx, key).Err(); err != nil {
+			return moved, fmt.Errorf("failed to remove legacy schema %s from Redis: %w", id, err)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return moved, fmt.Errorf("failed to scan Redis for legacy schemas: %w", err)
+	}
+	return moved, nil
 }
 
 // GetPrefix returns the key prefix used by this storage
@@ -150,14 +564,11 @@ func (rs *RedisStorage) Stats(ctx context.Context) (*RedisStats, error) {
 		Type: "redis",
 	}
 	// Get schema count
-	pattern := rs.prefix + "*"
-	iter := rs.client.Scan(ctx, 0, pattern, 0).Iterator()
-	for iter.Next(ctx) {
-		stats.SchemaCount++
-	}
-	if err := iter.Err(); err != nil {
+	count, err := rs.client.SCard(ctx, rs.indexKey("all")).Result()
+	if err != nil {
 		return nil, fmt.Errorf("failed to calculate schema count: %w", err)
 	}
+	stats.SchemaCount = int(count)
 	// Get memory usage if available (Redis INFO command)
 	if infoCmd := rs.client.Info(ctx, "memory"); infoCmd.Err() == nil {
 		info := infoCmd.Val()
@@ -204,10 +615,16 @@ func (rs *RedisStorage) FlushAll(ctx context.Context) error {
 	if err := iter.Err(); err != nil {
 		return fmt.Errorf("failed to scan Redis keys for flush: %w", err)
 	}
-	if len(keys) > 0 {
-		if err := rs.client.Del(ctx, keys...).Err(); err != nil {
-			return fmt.Errorf("failed to delete Redis keys: %w", err)
-		}
+	if len(keys) == 0 {
+		return nil
+	}
+	// Keys are deleted one per command since they span cluster slots
+	pipe := rs.client.Pipeline()
+	for _, key := range keys {
+		pipe.Del(ctx, key)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to delete Redis keys: %w", err)
 	}
 	return nil
 }
@@ -246,19 +663,14 @@ func (rs *RedisStorage) SetWithCustomTTL(ctx context.Context, id string, data []
 	if err := validateSchemaID(id); err != nil {
 		return err
 	}
-	if len(data) == 0 {
-		return fmt.Errorf("cannot store empty schema data for %s", id)
-	}
-	key := rs.getKey(id)
-	err := rs.client.Set(ctx, key, data, ttl).Err()
-	if err != nil {
+	if err := rs.set(ctx, id, data, ttl); err != nil {
 		return fmt.Errorf("failed to set schema %s in Redis with TTL %v: %w", id, ttl, err)
 	}
 	return nil
 }
 
-// BatchGet retrieves multiple schemas in a single operation
-func (rs *RedisStorage) BatchGet(ctx context.Context, ids []string) (map[string][]byte, error) {
+// GetBatch retrieves multiple schemas in a single operation, omitting IDs that do not exist
+func (rs *RedisStorage) GetBatch(ctx context.Context, ids []string) (map[string][]byte, error) {
 	if len(ids) == 0 {
 		return make(map[string][]byte), nil
 	}
@@ -268,34 +680,36 @@ func (rs *RedisStorage) BatchGet(ctx context.Context, ids []string) (map[string]
 			return nil, fmt.Errorf("invalid schema ID %s: %w", id, err)
 		}
 	}
-	// Convert IDs to keys
-	keys := make([]string, len(ids))
+	// Read each schema with its own GET, since the keys span cluster slots
+	pipe := rs.client.Pipeline()
+	cmds := make([]*redis.StringCmd, len(ids))
 	for i, id := range ids {
-		keys[i] = rs.getKey(id)
+		cmds[i] = pipe.Get(ctx, rs.getKey(id))
 	}
-	// Use MGET for batch retrieval
-	values, err := rs.client.MGet(ctx, keys...).Result()
-	if err != nil {
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
 		return nil, fmt.Errorf("failed to batch get schemas from Redis: %w", err)
 	}
 	// Build result map
 	result := make(map[string][]byte)
-	for i, value := range values {
-		if value != nil {
-			if str, ok := value.(string); ok {
-				result[ids[i]] = []byte(str)
-			}
+	for i, cmd := range cmds {
+		if data, err := cmd.Bytes(); err == nil {
+			result[ids[i]] = data
 		}
 	}
 	return result, nil
 }
 
-// BatchSet stores multiple schemas in a single operation
-func (rs *RedisStorage) BatchSet(ctx context.Context, schemas map[string][]byte) error {
+// SetBatch stores multiple schemas in pipelined round trips: one reading the
+// previous attributes and one running the set script, then the same for the
+// index sets. Schemas whose attributes changed in between are retried
+// individually
+func (rs *RedisStorage) SetBatch(ctx context.Context, schemas map[string][]byte) error {
 	if len(schemas) == 0 {
 		return nil
 	}
 	// Validate all IDs and data first
+	ids := make([]string, 0, len(schemas))
+	attrs := make(map[string]string, len(schemas))
 	for id, data := range schemas {
 		if err := validateSchemaID(id); err != nil {
 			return fmt.Errorf("invalid schema ID %s: %w", id, err)
@@ -303,20 +717,108 @@ func (rs *RedisStorage) BatchSet(ctx context.Context, schemas map[string][]byte)
 		if len(data) == 0 {
 			return fmt.Errorf("cannot store empty schema data for %s", id)
 		}
-	}
-	// Use pipeline for batch operations
-	pipe := rs.client.Pipeline()
-	for id, data := range schemas {
-		key := rs.getKey(id)
-		if rs.ttl > 0 {
-			pipe.Set(ctx, key, data, rs.ttl)
-		} else {
-			pipe.Set(ctx, key, data, 0)
+		encoded, err := storageAttributesJSON(id, data)
+		if err != nil {
+			return err
 		}
+		ids = append(ids, id)
+		attrs[id] = encoded
 	}
-	_, err := pipe.Exec(ctx)
+
+	previous, err := rs.batchRead(ctx, ids, rs.metaKeyField)
 	if err != nil {
+		return err
+	}
+	if err := redisSetScript.Load(ctx, rs.client).Err(); err != nil {
+		return fmt.Errorf("failed to load Redis set script: %w", err)
+	}
+	writes := rs.client.Pipeline()
+	setCmds := make([]*redis.Cmd, len(ids))
+	for i, id := range ids {
+		setCmds[i] = redisSetScript.EvalSha(ctx, writes, rs.schemaKeys(id), rs.setArgs(schemas[id], rs.ttl, previous[i], attrs[id])...)
+	}
+	if _, err := writes.Exec(ctx); err != nil {
 		return fmt.Errorf("failed to batch set schemas in Redis: %w", err)
 	}
+	revs := make(map[string]int64, len(ids))
+	for i, id := range ids {
+		if rev, _ := setCmds[i].Int64(); rev > 0 {
+			revs[id] = rev
+			continue
+		}
+		if err := rs.set(ctx, id, schemas[id], rs.ttl); err != nil {
+			return err
+		}
+	}
+	return rs.indexBatch(ctx, revs, attrs)
+}
+
+// indexBatch updates the index sets of written schemas in pipelined round
+// trips, retrying those whose indexed attributes changed individually
+func (rs *RedisStorage) indexBatch(ctx context.Context, revs map[string]int64, attrs map[string]string) error {
+	if len(revs) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(revs))
+	for id := range revs {
+		ids = append(ids, id)
+	}
+	indexed, err := rs.batchRead(ctx, ids, rs.indexKeyField)
+	if err != nil {
+		return err
+	}
+	if err := redisIndexScript.Load(ctx, rs.client).Err(); err != nil {
+		return fmt.Errorf("failed to load Redis index script: %w", err)
+	}
+	writes := rs.client.Pipeline()
+	cmds := make([]*redis.Cmd, len(ids))
+	for i, id := range ids {
+		keys, args := rs.indexArgs(id, indexed[i], attrs[id], revs[id])
+		cmds[i] = redisIndexScript.EvalSha(ctx, writes, keys, args...)
+	}
+	if _, err := writes.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to batch index schemas in Redis: %w", err)
+	}
+	for i, id := range ids {
+		if applied, err := cmds[i].Int64(); err == nil && applied >= 0 {
+			continue
+		}
+		if err := rs.index(ctx, id, attrs[id], revs[id]); err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+// batchRead reads the attributes of several schemas in one pipelined round
+// trip, from the hash field that field returns for each ID
+func (rs *RedisStorage) batchRead(ctx context.Context, ids []string, field func(id string) (key, name string)) ([]string, error) {
+	reads := rs.client.Pipeline()
+	cmds := make([]*redis.StringCmd, len(ids))
+	for i, id := range ids {
+		key, name := field(id)
+		cmds[i] = reads.HGet(ctx, key, name)
+	}
+	if _, err := reads.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to read schema attributes from Redis: %w", err)
+	}
+	values := make([]string, len(ids))
+	for i, cmd := range cmds {
+		value, err := cmd.Result()
+		if err != nil && err != redis.Nil {
+			return nil, fmt.Errorf("failed to read attributes of schema %s from Redis: %w", ids[i], err)
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// metaKeyField locates a schema's stored attributes
+func (rs *RedisStorage) metaKeyField(id string) (string, string) {
+	return rs.metaKey(id), "attrs"
+}
+
+// indexKeyField locates the attributes the index sets hold for a schema
+func (rs *RedisStorage) indexKeyField(id string) (string, string) {
+	return rs.indexKey(redisIndexAttrsKey), id
+}
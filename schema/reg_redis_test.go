@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -11,6 +12,84 @@ import (
 	"github.com/go-redis/redismock/v8"
 )
 
+var _ StorageBackend = (*RedisStorage)(nil)
+
+// redisTestKey returns the key of a schema, or of one of its auxiliary
+// hashes when suffix is ":_versions" or ":_meta"
+func redisTestKey(prefix, id, suffix string) string {
+	return prefix + "{" + id + "}" + suffix
+}
+
+// redisTestIndexKey returns the key of an index set or hash
+func redisTestIndexKey(prefix, name string) string {
+	return prefix + "{_idx}:" + name
+}
+
+// skipTrailingArgs matches script arguments except the last n, which hold
+// generated versions and timestamps
+func skipTrailingArgs(n int) redismock.CustomMatch {
+	return func(expected, actual []interface{}) error {
+		if len(expected) != len(actual) {
+			return fmt.Errorf("expected %d arguments, got %d", len(expected), len(actual))
+		}
+		for i := range expected[:len(expected)-n] {
+			if fmt.Sprint(expected[i]) != fmt.Sprint(actual[i]) {
+				return fmt.Errorf("argument %d: expected %v, got %v", i, expected[i], actual[i])
+			}
+		}
+		return nil
+	}
+}
+
+// expectRedisIndex expects the indexed attribute read and the index script
+// moving a schema from the sets of previous to those of attrs
+func expectRedisIndex(mock redismock.ClientMock, prefix, id, previous, attrs string, rev int64) *redismock.ExpectedCmd {
+	if previous == "" {
+		mock.ExpectHGet(redisTestIndexKey(prefix, "_attrs"), id).RedisNil()
+	} else {
+		mock.ExpectHGet(redisTestIndexKey(prefix, "_attrs"), id).SetVal(previous)
+	}
+	storage := &RedisStorage{prefix: prefix}
+	previousKeys := storage.attributeIndexKeys(previous)
+	keys := []string{redisTestIndexKey(prefix, "_attrs"), redisTestIndexKey(prefix, "_revs"), redisTestIndexKey(prefix, "all")}
+	keys = append(keys, previousKeys...)
+	keys = append(keys, storage.attributeIndexKeys(attrs)...)
+	return mock.ExpectEvalSha(redisIndexScript.Hash(), keys, id, previous, attrs, len(previousKeys), rev)
+}
+
+// expectRedisSet expects the attribute read, the set script and the index
+// update for a new schema
+func expectRedisSet(mock redismock.ClientMock, prefix, id string, data []byte, ttl time.Duration) {
+	mock.ExpectHGet(redisTestKey(prefix, id, ":_meta"), "attrs").RedisNil()
+	attrs, _ := json.Marshal(extractStorageAttributes(data))
+	keys := []string{redisTestKey(prefix, id, ""), redisTestKey(prefix, id, ":_versions"), redisTestKey(prefix, id, ":_meta")}
+	mock.CustomMatch(skipTrailingArgs(2)).
+		ExpectEvalSha(redisSetScript.Hash(), keys, data, ttl.Milliseconds(), "", string(attrs), "", "").
+		SetVal(int64(1))
+	expectRedisIndex(mock, prefix, id, "", string(attrs), 1).SetVal(int64(1))
+}
+
+// expectRedisDelete expects the attribute read and the delete script for a
+// schema without filter attributes, and when the script returns a revision,
+// the removal from the index sets
+func expectRedisDelete(mock redismock.ClientMock, prefix, id string, rev int64) {
+	mock.ExpectHGet(redisTestKey(prefix, id, ":_meta"), "attrs").SetVal(`{}`)
+	keys := []string{redisTestKey(prefix, id, ""), redisTestKey(prefix, id, ":_meta")}
+	mock.CustomMatch(skipTrailingArgs(1)).
+		ExpectEvalSha(redisDeleteScript.Hash(), keys, `{}`, "").
+		SetVal(rev)
+	if rev > 0 {
+		expectRedisIndex(mock, prefix, id, `{}`, "", rev).SetVal(int64(1))
+	}
+}
+
+// expectRedisLive expects the existence checks List makes for listed IDs
+func expectRedisLive(mock redismock.ClientMock, prefix string, ids ...string) {
+	for _, id := range ids {
+		mock.ExpectExists(redisTestKey(prefix, id, "")).SetVal(1)
+	}
+}
+
 func TestNewRedisStorage(t *testing.T) {
 	client := redis.NewClient(&redis.Options{
 		Addr: "localhost:6379",
@@ -143,10 +222,10 @@ func TestRedisStorage_SetAndGet(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if !tt.wantErr {
-				// Mock SET command
-				mock.ExpectSet("test:"+tt.schemaID, tt.data, 0).SetVal("OK")
+				// Mock set script
+				expectRedisSet(mock, "test:", tt.schemaID, tt.data, 0)
 				// Mock GET command
-				mock.ExpectGet("test:" + tt.schemaID).SetVal(string(tt.data))
+				mock.ExpectGet(redisTestKey("test:", tt.schemaID, "")).SetVal(string(tt.data))
 			}
 			// Test Set
 			err := storage.Set(ctx, tt.schemaID, tt.data)
@@ -187,15 +266,15 @@ func TestRedisStorage_Delete(t *testing.T) {
 	ctx := context.Background()
 	testData := []byte(`{"id": "test.delete", "title": "Test Delete"}`)
 	// Mock SET for initial setup
-	mock.ExpectSet("test:test.delete", testData, 0).SetVal("OK")
+	expectRedisSet(mock, "test:", "test.delete", testData, 0)
 	// Mock EXISTS for checking existence
-	mock.ExpectExists("test:test.delete").SetVal(1)
-	// Mock DEL for deletion (returns 1 for successful deletion)
-	mock.ExpectDel("test:test.delete").SetVal(1)
+	mock.ExpectExists(redisTestKey("test:", "test.delete", "")).SetVal(1)
+	// Mock delete script (returns the new revision for successful deletion)
+	expectRedisDelete(mock, "test:", "test.delete", 2)
 	// Mock EXISTS again for verification
-	mock.ExpectExists("test:test.delete").SetVal(0)
-	// Mock DEL for non-existent schema (returns 0)
-	mock.ExpectDel("test:non.existent").SetVal(0)
+	mock.ExpectExists(redisTestKey("test:", "test.delete", "")).SetVal(0)
+	// Mock delete script for non-existent schema (returns 0)
+	expectRedisDelete(mock, "test:", "non.existent", 0)
 	// First, store a schema
 	err = storage.Set(ctx, "test.delete", testData)
 	if err != nil {
@@ -241,16 +320,16 @@ func TestRedisStorage_List(t *testing.T) {
 		t.Fatalf("Failed to create Redis storage: %v", err)
 	}
 	ctx := context.Background()
-	// Mock SCAN command to return schema keys
-	expectedKeys := []string{
-		"test:user.profile",
-		"test:user.settings",
-		"test:forms.registration",
-		"test:admin.dashboard",
-	}
-	mock.ExpectScan(0, "test:*", 0).SetVal(expectedKeys, 0)
+	// Mock SMEMBERS on the index of all schemas
+	mock.ExpectSMembers(redisTestIndexKey("test:", "all")).SetVal([]string{
+		"user.profile",
+		"user.settings",
+		"forms.registration",
+		"admin.dashboard",
+	})
+	expectRedisLive(mock, "test:", "user.profile", "user.settings", "forms.registration", "admin.dashboard")
 	// List all schemas
-	schemaIDs, err := storage.List(ctx)
+	schemaIDs, err := storage.List(ctx, nil)
 	if err != nil {
 		t.Errorf("List() error = %v", err)
 		return
@@ -290,9 +369,9 @@ func TestRedisStorage_Exists(t *testing.T) {
 	}
 	ctx := context.Background()
 	// Mock EXISTS commands
-	mock.ExpectExists("test:non.existent").SetVal(0)
-	mock.ExpectSet("test:test.exists", []byte(`{"id": "test.exists"}`), 0).SetVal("OK")
-	mock.ExpectExists("test:test.exists").SetVal(1)
+	mock.ExpectExists(redisTestKey("test:", "non.existent", "")).SetVal(0)
+	expectRedisSet(mock, "test:", "test.exists", []byte(`{"id": "test.exists"}`), 0)
+	mock.ExpectExists(redisTestKey("test:", "test.exists", "")).SetVal(1)
 	// Test non-existent schema
 	exists, err := storage.Exists(ctx, "non.existent")
 	if err != nil {
@@ -331,8 +410,8 @@ func TestRedisStorage_TTL(t *testing.T) {
 	}
 	ctx := context.Background()
 	testData := []byte(`{"id": "test.ttl"}`)
-	// Mock SET command with TTL
-	mock.ExpectSet("test:test.ttl", testData, ttl).SetVal("OK")
+	// Mock set script with TTL, which only the current data key gets
+	expectRedisSet(mock, "test:", "test.ttl", testData, ttl)
 	err = storage.Set(ctx, "test.ttl", testData)
 	if err != nil {
 		t.Errorf("Set() with TTL error = %v", err)
@@ -351,13 +430,13 @@ func TestRedisStorage_Stats(t *testing.T) {
 		t.Fatalf("Failed to create Redis storage: %v", err)
 	}
 	ctx := context.Background()
-	// Mock SCAN command for counting schemas
+	// Mock SCARD command for counting schemas
 	expectedKeys := []string{
-		"test:schema1",
-		"test:schema2",
-		"test:schema3",
+		"schema1",
+		"schema2",
+		"schema3",
 	}
-	mock.ExpectScan(0, "test:*", 0).SetVal(expectedKeys, 0)
+	mock.ExpectSCard(redisTestIndexKey("test:", "all")).SetVal(int64(len(expectedKeys)))
 	// Mock INFO command for memory usage
 	infoResponse := "# Memory\r\nused_memory:1048576\r\nused_memory_human:1.00M\r\n"
 	mock.ExpectInfo("memory").SetVal(infoResponse)
@@ -383,6 +462,60 @@ func TestRedisStorage_Stats(t *testing.T) {
 		t.Errorf("Redis mock expectations not met: %v", err)
 	}
 }
+func TestRedisStorage_ListFilter(t *testing.T) {
+	client, mock := redismock.NewClientMock()
+	config, _ := NewRedisStorageConfig(client).Build()
+	config.SetRedisConfig(client, "test:", 0)
+	storage, err := NewRedisStorage(*config)
+	if err != nil {
+		t.Fatalf("Failed to create Redis storage: %v", err)
+	}
+	ctx := context.Background()
+	// Criteria are intersected server-side
+	mock.ExpectSInter(redisTestIndexKey("test:", "all"), redisTestIndexKey("test:", "type:form"), redisTestIndexKey("test:", "tag:public")).SetVal([]string{"c", "a", "b"})
+	expectRedisLive(mock, "test:", "c", "a", "b")
+	schemaIDs, err := storage.List(ctx, &StorageFilter{Type: "form", Tags: []string{"public"}, Offset: 1, Limit: 1})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(schemaIDs) != 1 || schemaIDs[0] != "b" {
+		t.Errorf("List() = %v, want [b]", schemaIDs)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Redis mock expectations not met: %v", err)
+	}
+}
+func TestRedisStorage_Versions(t *testing.T) {
+	client, mock := redismock.NewClientMock()
+	config, _ := NewRedisStorageConfig(client).Build()
+	config.SetRedisConfig(client, "test:", 0)
+	storage, err := NewRedisStorage(*config)
+	if err != nil {
+		t.Fatalf("Failed to create Redis storage: %v", err)
+	}
+	ctx := context.Background()
+	mock.ExpectHKeys(redisTestKey("test:", "user.profile", ":_versions")).SetVal([]string{"1700000000000000002", "999", "1700000000000000001"})
+	mock.ExpectHGet(redisTestKey("test:", "user.profile", ":_versions"), "999").SetVal(`{"id":"user.profile"}`)
+	mock.ExpectHGet(redisTestKey("test:", "user.profile", ":_versions"), "1").SetErr(redis.Nil)
+	versions, err := storage.ListVersions(ctx, "user.profile")
+	if err != nil {
+		t.Fatalf("ListVersions() error = %v", err)
+	}
+	want := []string{"999", "1700000000000000001", "1700000000000000002"}
+	if fmt.Sprint(versions) != fmt.Sprint(want) {
+		t.Errorf("ListVersions() = %v, want %v", versions, want)
+	}
+	data, err := storage.GetVersion(ctx, "user.profile", "999")
+	if err != nil || string(data) != `{"id":"user.profile"}` {
+		t.Errorf("GetVersion() = %s, %v", data, err)
+	}
+	if _, err := storage.GetVersion(ctx, "user.profile", "1"); err == nil {
+		t.Error("GetVersion() should return error for unknown version")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Redis mock expectations not met: %v", err)
+	}
+}
 func TestRedisStorage_GetWithTTL(t *testing.T) {
 	// Skip this test for now due to pipeline mocking complexity
 	t.Skip("Skipping pipeline test due to mock limitations")
@@ -421,9 +554,11 @@ func TestRedisStorage_FlushAll(t *testing.T) {
 	}
 	ctx := context.Background()
 	// Mock SCAN and DEL commands
-	keys := []string{"test:schema1", "test:schema2", "test:schema3"}
+	keys := []string{"test:{schema1}", "test:{schema1}:_meta", "test:{_idx}:all"}
 	mock.ExpectScan(0, "test:*", 0).SetVal(keys, 0)
-	mock.ExpectDel(keys...).SetVal(int64(len(keys)))
+	for _, key := range keys {
+		mock.ExpectDel(key).SetVal(1)
+	}
 	err = storage.FlushAll(ctx)
 	if err != nil {
 		t.Errorf("FlushAll() error = %v", err)
@@ -443,9 +578,9 @@ func BenchmarkRedisStorage_Set(b *testing.B) {
 	}
 	ctx := context.Background()
 	testData := []byte(`{"id": "benchmark", "title": "Benchmark Schema", "fields": [{"name": "test", "type": "text"}]}`)
-	// Mock all SET operations
+	// Mock all set script calls
 	for i := 0; i < b.N; i++ {
-		mock.ExpectSet(fmt.Sprintf("bench:benchmark.schema.%d", i), testData, 0).SetVal("OK")
+		expectRedisSet(mock, "bench:", fmt.Sprintf("benchmark.schema.%d", i), testData, 0)
 	}
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -468,7 +603,7 @@ func BenchmarkRedisStorage_Get(b *testing.B) {
 	testData := []byte(`{"id": "benchmark", "title": "Benchmark Schema"}`)
 	// Mock all GET operations
 	for i := 0; i < b.N; i++ {
-		mock.ExpectGet(fmt.Sprintf("bench:benchmark.schema.%d", i)).SetVal(string(testData))
+		mock.ExpectGet(redisTestKey("bench:", fmt.Sprintf("benchmark.schema.%d", i), "")).SetVal(string(testData))
 	}
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -479,3 +614,153 @@ func BenchmarkRedisStorage_Get(b *testing.B) {
 		}
 	}
 }
+func TestRedisStorage_KeysShareSlotPerSchema(t *testing.T) {
+	client, mock := redismock.NewClientMock()
+	config, _ := NewRedisStorageConfig(client).Build()
+	config.SetRedisConfig(client, "test:", time.Hour)
+	storage, err := NewRedisStorage(*config)
+	if err != nil {
+		t.Fatalf("Failed to create Redis storage: %v", err)
+	}
+	ctx := context.Background()
+	data := []byte(`{"id":"invoice","type":"form","tags":["billing"]}`)
+	previous := `{"type":"form","category":"finance"}`
+	attrs, _ := json.Marshal(extractStorageAttributes(data))
+	schemaKeys := []string{redisTestKey("test:", "invoice", ""), redisTestKey("test:", "invoice", ":_versions"), redisTestKey("test:", "invoice", ":_meta")}
+	for _, key := range schemaKeys {
+		if !strings.HasPrefix(key, "test:{invoice}") {
+			t.Fatalf("key %s lacks the schema's hash tag", key)
+		}
+	}
+	indexKeys := []string{
+		redisTestIndexKey("test:", "_attrs"), redisTestIndexKey("test:", "_revs"), redisTestIndexKey("test:", "all"),
+		// Index sets of the previous attributes, then of the new ones
+		redisTestIndexKey("test:", "type:form"), redisTestIndexKey("test:", "category:finance"),
+		redisTestIndexKey("test:", "type:form"), redisTestIndexKey("test:", "tag:billing"),
+	}
+	for _, key := range indexKeys {
+		if !strings.HasPrefix(key, "test:{_idx}:") {
+			t.Fatalf("key %s lacks the index hash tag", key)
+		}
+	}
+	// The attributes change between the read and the script, so the write is retried
+	mock.ExpectHGet(redisTestKey("test:", "invoice", ":_meta"), "attrs").SetVal(`{"type":"form"}`)
+	mock.CustomMatch(skipTrailingArgs(2)).ExpectEvalSha(redisSetScript.Hash(), schemaKeys, data, time.Hour.Milliseconds(), `{"type":"form"}`, string(attrs), "", "").SetVal(int64(-1))
+	mock.ExpectHGet(redisTestKey("test:", "invoice", ":_meta"), "attrs").SetVal(previous)
+	mock.CustomMatch(skipTrailingArgs(2)).ExpectEvalSha(redisSetScript.Hash(), schemaKeys, data, time.Hour.Milliseconds(), previous, string(attrs), "", "").SetVal(int64(7))
+	// The index sets are moved with the revision the write returned, and
+	// retried when another write re-indexed the schema in between
+	mock.ExpectHGet(redisTestIndexKey("test:", "_attrs"), "invoice").SetVal(`{"type":"form"}`)
+	mock.ExpectEvalSha(redisIndexScript.Hash(), append(indexKeys[:4:4], indexKeys[5:]...), "invoice", `{"type":"form"}`, string(attrs), 1, int64(7)).SetVal(int64(-1))
+	mock.ExpectHGet(redisTestIndexKey("test:", "_attrs"), "invoice").SetVal(previous)
+	mock.ExpectEvalSha(redisIndexScript.Hash(), indexKeys, "invoice", previous, string(attrs), 2, int64(7)).SetVal(int64(1))
+	if err := storage.Set(ctx, "invoice", data); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Redis mock expectations not met: %v", err)
+	}
+}
+func TestRedisStorage_ExpiryKeepsVersions(t *testing.T) {
+	client, mock := redismock.NewClientMock()
+	config, _ := NewRedisStorageConfig(client).Build()
+	config.SetRedisConfig(client, "test:", time.Hour)
+	storage, err := NewRedisStorage(*config)
+	if err != nil {
+		t.Fatalf("Failed to create Redis storage: %v", err)
+	}
+	ctx := context.Background()
+	mock.ExpectSMembers(redisTestIndexKey("test:", "all")).SetVal([]string{"invoice", "expired"})
+	mock.ExpectExists(redisTestKey("test:", "invoice", "")).SetVal(1)
+	mock.ExpectExists(redisTestKey("test:", "expired", "")).SetVal(0)
+	// Metadata and versions outlive the expired data, but the schema is gone
+	mock.ExpectHGetAll(redisTestKey("test:", "expired", ":_meta")).SetVal(map[string]string{"version": "1700000000000000001"})
+	mock.ExpectExists(redisTestKey("test:", "expired", "")).SetVal(0)
+	mock.ExpectHKeys(redisTestKey("test:", "expired", ":_versions")).SetVal([]string{"1700000000000000001"})
+	schemaIDs, err := storage.List(ctx, nil)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if fmt.Sprint(schemaIDs) != "[invoice]" {
+		t.Errorf("List() = %v, want [invoice]", schemaIDs)
+	}
+	if _, err := storage.GetMetadata(ctx, "expired"); err == nil {
+		t.Error("GetMetadata() should return error for an expired schema")
+	}
+	if versions, err := storage.ListVersions(ctx, "expired"); err != nil || len(versions) != 1 {
+		t.Errorf("ListVersions() = %v, %v, want the version history kept", versions, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Redis mock expectations not met: %v", err)
+	}
+}
+func TestRedisStorage_DeleteKeepsVersions(t *testing.T) {
+	client, mock := redismock.NewClientMock()
+	config, _ := NewRedisStorageConfig(client).Build()
+	config.SetRedisConfig(client, "test:", 0)
+	storage, err := NewRedisStorage(*config)
+	if err != nil {
+		t.Fatalf("Failed to create Redis storage: %v", err)
+	}
+	ctx := context.Background()
+	// The delete script is not given the versions hash, so it cannot remove it
+	expectRedisDelete(mock, "test:", "user.profile", 3)
+	mock.ExpectHGetAll(redisTestKey("test:", "user.profile", ":_meta")).SetVal(map[string]string{
+		"version": "1700000000000000001", "deletedAt": "2026-01-01T00:00:00Z",
+	})
+	mock.ExpectHKeys(redisTestKey("test:", "user.profile", ":_versions")).SetVal([]string{"1700000000000000001"})
+	if err := storage.Delete(ctx, "user.profile"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := storage.GetMetadata(ctx, "user.profile"); err == nil {
+		t.Error("GetMetadata() should return error for a tombstoned schema")
+	}
+	versions, err := storage.ListVersions(ctx, "user.profile")
+	if err != nil || len(versions) != 1 {
+		t.Errorf("ListVersions() = %v, %v, want the kept version", versions, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Redis mock expectations not met: %v", err)
+	}
+}
+func TestRedisStorage_MigratesLegacyKeys(t *testing.T) {
+	client, mock := redismock.NewClientMock()
+	config, _ := NewRedisStorageConfig(client).Build()
+	config.SetRedisConfig(client, "test:", 0)
+	storage, err := NewRedisStorage(*config)
+	if err != nil {
+		t.Fatalf("Failed to create Redis storage: %v", err)
+	}
+	ctx := context.Background()
+	legacy := []byte(`{"id":"legacy.form"}`)
+	mock.ExpectScan(0, "test:*", 0).SetVal([]string{
+		"test:legacy.form",
+		"test:stale.form",
+		redisTestKey("test:", "current.form", ""),
+		redisTestIndexKey("test:", "all"),
+	}, 0)
+	// A schema only stored in the legacy layout is moved
+	mock.ExpectGet("test:legacy.form").SetVal(string(legacy))
+	mock.ExpectExists(redisTestKey("test:", "legacy.form", "")).SetVal(0)
+	expectRedisSet(mock, "test:", "legacy.form", legacy, 0)
+	mock.ExpectDel("test:legacy.form").SetVal(1)
+	// A legacy copy of a schema already in the current layout is discarded
+	mock.ExpectGet("test:stale.form").SetVal(`{"id":"stale.form"}`)
+	mock.ExpectExists(redisTestKey("test:", "stale.form", "")).SetVal(1)
+	mock.ExpectDel("test:stale.form").SetVal(1)
+	moved, err := storage.Migrate(ctx)
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if moved != 1 {
+		t.Errorf("Migrate() moved %d schemas, want 1", moved)
+	}
+	// Reads never scan for legacy keys
+	mock.ExpectGet(redisTestKey("test:", "legacy.form", "")).SetVal(string(legacy))
+	if _, err := storage.Get(ctx, "legacy.form"); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Redis mock expectations not met: %v", err)
+	}
+}
@@ -3,18 +3,47 @@ package schema
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net/url"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
 
-// S3Storage implements Storage interface using AWS S3
+// S3 key layout below the key prefix. Schema IDs cannot contain '/' and path
+// segments derived from them never start with '.', so these prefixes never
+// collide with schema keys:
+//
+//	<id path>.json                               current schema data
+//	.versions/<id>/<version>.json                immutable versions, kept after Delete
+//	.index/<field>/<escaped value>/<id>          empty marker objects per type, category, module and tag
+//	.tombstones/<id>                             empty marker recording when a schema was deleted
+//	.records/<key>.json                          unversioned records such as schema lifecycles
+const (
+	s3VersionsPrefix   = ".versions/"
+	s3IndexPrefix      = ".index/"
+	s3TombstonesPrefix = ".tombstones/"
+	s3RecordsPrefix    = ".records/"
+)
+
+// S3API is the subset of the S3 client used by S3Storage
+type S3API interface {
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+	DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error)
+	HeadObject(ctx context.Context, params *s3.HeadObjectInput, optFns ...func(*s3.Options)) (*s3.HeadObjectOutput, error)
+	HeadBucket(ctx context.Context, params *s3.HeadBucketInput, optFns ...func(*s3.Options)) (*s3.HeadBucketOutput, error)
+	ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error)
+}
+
+// S3Storage implements StorageBackend using AWS S3
 type S3Storage struct {
-	client    *s3.Client
+	client    S3API
 	bucket    string
 	keyPrefix string
 }
@@ -34,8 +63,8 @@ func NewS3Storage(config S3Config) (*S3Storage, error) {
 		return nil, fmt.Errorf("S3 bucket name is required")
 	}
 
-	// Type assert to S3 client
-	s3Client, ok := client.(*s3.Client)
+	// Accept *s3.Client or any compatible implementation
+	s3Client, ok := client.(S3API)
 	if !ok {
 		return nil, fmt.Errorf("invalid S3 client type")
 	}
@@ -63,7 +92,7 @@ func (s3s *S3Storage) Get(ctx context.Context, id string) ([]byte, error) {
 		Key:    aws.String(key),
 	})
 	if err != nil {
-		if strings.Contains(err.Error(), "NoSuchKey") {
+		if isS3NotFound(err) {
 			return nil, fmt.Errorf("schema %s not found", id)
 		}
 		return nil, fmt.Errorf("failed to get schema %s from S3: %w", id, err)
@@ -76,92 +105,175 @@ func (s3s *S3Storage) Get(ctx context.Context, id string) ([]byte, error) {
 	return data, nil
 }
 
-// Set stores a schema by ID to S3
+// Set stores a schema by ID to S3. An immutable version object is written
+// before the current object, and the index markers used by List are updated
 func (s3s *S3Storage) Set(ctx context.Context, id string, data []byte) error {
-	if err := validateSchemaID(id); err != nil {
-		return err
-	}
-	if len(data) == 0 {
-		return fmt.Errorf("cannot store empty schema data for %s", id)
-	}
-	key := s3s.getKey(id)
-	_, err := s3s.client.PutObject(ctx, &s3.PutObjectInput{
-		Bucket:      aws.String(s3s.bucket),
-		Key:         aws.String(key),
-		Body:        bytes.NewReader(data),
-		ContentType: aws.String("application/json"),
-		Metadata: map[string]string{
-			"schema-id": id,
-			"type":      "schema",
-		},
-	})
-	if err != nil {
-		return fmt.Errorf("failed to store schema %s in S3: %w", id, err)
-	}
-	return nil
+	return s3s.SetWithMetadata(ctx, id, data, nil)
 }
 
-// Delete removes a schema by ID from S3
+// Delete tombstones a schema in S3. The current object and its index markers
+// are removed and a tombstone marker records the deletion, while the version
+// objects are kept
 func (s3s *S3Storage) Delete(ctx context.Context, id string) error {
 	if err := validateSchemaID(id); err != nil {
 		return err
 	}
 	key := s3s.getKey(id)
 	// Check if object exists first
-	_, err := s3s.client.HeadObject(ctx, &s3.HeadObjectInput{
+	head, err := s3s.client.HeadObject(ctx, &s3.HeadObjectInput{
 		Bucket: aws.String(s3s.bucket),
 		Key:    aws.String(key),
 	})
 	if err != nil {
-		if strings.Contains(err.Error(), "NoSuchKey") {
+		if isS3NotFound(err) {
 			return fmt.Errorf("schema %s not found", id)
 		}
 		return fmt.Errorf("failed to check schema %s in S3: %w", id, err)
 	}
+	tombstone := map[string]string{
+		"schema-id":      id,
+		"type":           "tombstone",
+		"schema-version": head.Metadata["schema-version"],
+		"deleted-at":     time.Now().UTC().Format(time.RFC3339Nano),
+	}
+	if err := s3s.putObject(ctx, s3s.tombstoneKey(id), nil, tombstone, nil); err != nil {
+		return fmt.Errorf("failed to tombstone schema %s in S3: %w", id, err)
+	}
+	for _, marker := range s3s.indexKeys(id, attributesFromS3Metadata(head.Metadata)) {
+		if err := s3s.deleteObject(ctx, marker); err != nil {
+			return fmt.Errorf("failed to delete index entry of schema %s from S3: %w", id, err)
+		}
+	}
 	// Delete the object
-	_, err = s3s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
-		Bucket: aws.String(s3s.bucket),
-		Key:    aws.String(key),
-	})
-	if err != nil {
+	if err := s3s.deleteObject(ctx, key); err != nil {
 		return fmt.Errorf("failed to delete schema %s from S3: %w", id, err)
 	}
 	return nil
 }
 
-// List returns all schema IDs from S3
-func (s3s *S3Storage) List(ctx context.Context) ([]string, error) {
-	var schemaIDs []string
-	paginator := s3.NewListObjectsV2Paginator(s3s.client, &s3.ListObjectsV2Input{
-		Bucket: aws.String(s3s.bucket),
-		Prefix: aws.String(s3s.keyPrefix),
-	})
-	for paginator.HasMorePages() {
-		// Check context cancellation
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		default:
-		}
-		page, err := paginator.NextPage(ctx)
+// List returns the sorted schema IDs matching filter. Criteria are resolved
+// server-side by listing the index marker prefixes and intersecting them
+func (s3s *S3Storage) List(ctx context.Context, filter *StorageFilter) ([]string, error) {
+	if !hasStorageCriteria(filter) {
+		keys, err := s3s.listKeys(ctx, s3s.keyPrefix)
 		if err != nil {
 			return nil, fmt.Errorf("failed to list objects in S3: %w", err)
 		}
-		for _, obj := range page.Contents {
-			if obj.Key != nil {
-				// Convert S3 key back to schema ID
-				key := *obj.Key
-				if strings.HasPrefix(key, s3s.keyPrefix) && strings.HasSuffix(key, ".json") {
-					schemaID := strings.TrimPrefix(key, s3s.keyPrefix)
-					schemaID = strings.TrimSuffix(schemaID, ".json")
-					// Convert path separators back to dots
-					schemaID = strings.ReplaceAll(schemaID, "/", ".")
-					schemaIDs = append(schemaIDs, schemaID)
-				}
+		var schemaIDs []string
+		for _, key := range keys {
+			if schemaID, ok := s3s.schemaIDFromKey(key); ok {
+				schemaIDs = append(schemaIDs, schemaID)
 			}
 		}
+		return pageStorageIDs(schemaIDs, filter), nil
+	}
+	var prefixes []string
+	if filter.Type != "" {
+		prefixes = append(prefixes, s3s.indexPrefix("type", filter.Type))
+	}
+	if filter.Category != "" {
+		prefixes = append(prefixes, s3s.indexPrefix("category", filter.Category))
+	}
+	if filter.Module != "" {
+		prefixes = append(prefixes, s3s.indexPrefix("module", filter.Module))
+	}
+	for _, tag := range filter.Tags {
+		prefixes = append(prefixes, s3s.indexPrefix("tag", tag))
+	}
+	var matched map[string]bool
+	for _, prefix := range prefixes {
+		keys, err := s3s.listKeys(ctx, prefix)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list index in S3: %w", err)
+		}
+		found := make(map[string]bool, len(keys))
+		for _, key := range keys {
+			schemaID := strings.TrimPrefix(key, prefix)
+			if matched == nil || matched[schemaID] {
+				found[schemaID] = true
+			}
+		}
+		matched = found
+		if len(matched) == 0 {
+			break
+		}
+	}
+	schemaIDs := make([]string, 0, len(matched))
+	for schemaID := range matched {
+		schemaIDs = append(schemaIDs, schemaID)
 	}
-	return schemaIDs, nil
+	return pageStorageIDs(schemaIDs, filter), nil
+}
+
+// GetVersion retrieves an immutable version of a schema
+func (s3s *S3Storage) GetVersion(ctx context.Context, id, version string) ([]byte, error) {
+	if err := validateSchemaID(id); err != nil {
+		return nil, err
+	}
+	if version == "" || strings.ContainsAny(version, "/\\") {
+		return nil, fmt.Errorf("invalid version %q", version)
+	}
+	result, err := s3s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s3s.bucket),
+		Key:    aws.String(s3s.versionPrefix(id) + version + ".json"),
+	})
+	if err != nil {
+		if isS3NotFound(err) {
+			return nil, fmt.Errorf("version %s of schema %s not found", version, id)
+		}
+		return nil, fmt.Errorf("failed to get version %s of schema %s from S3: %w", version, id, err)
+	}
+	defer result.Body.Close()
+	data, err := io.ReadAll(result.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read version %s of schema %s from S3: %w", version, id, err)
+	}
+	return data, nil
+}
+
+// ListVersions returns a schema's versions from oldest to newest
+func (s3s *S3Storage) ListVersions(ctx context.Context, id string) ([]string, error) {
+	if err := validateSchemaID(id); err != nil {
+		return nil, err
+	}
+	prefix := s3s.versionPrefix(id)
+	keys, err := s3s.listKeys(ctx, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list versions of schema %s in S3: %w", id, err)
+	}
+	versions := make([]string, 0, len(keys))
+	for _, key := range keys {
+		versions = append(versions, strings.TrimSuffix(strings.TrimPrefix(key, prefix), ".json"))
+	}
+	return sortStorageVersions(versions), nil
+}
+
+// GetMetadata returns storage metadata for a schema from its object metadata
+func (s3s *S3Storage) GetMetadata(ctx context.Context, id string) (*StorageMetadata, error) {
+	info, err := s3s.GetObjectInfo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	meta := &StorageMetadata{
+		ID:   id,
+		Size: info.Size,
+		BaseMetadata: BaseMetadata{
+			Version: info.Metadata["schema-version"],
+		},
+	}
+	meta.CreatedAt, _ = time.Parse(time.RFC3339Nano, info.Metadata["created-at"])
+	if info.LastModified != nil {
+		meta.UpdatedAt = *info.LastModified
+	}
+	return meta, nil
+}
+
+// Health checks that the bucket is reachable
+func (s3s *S3Storage) Health(ctx context.Context) error {
+	if _, err := s3s.client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: aws.String(s3s.bucket)}); err != nil {
+		return fmt.Errorf("S3 bucket %s unavailable: %w", s3s.bucket, err)
+	}
+	return nil
 }
 
 // Exists checks if a schema exists in S3
@@ -175,7 +287,7 @@ func (s3s *S3Storage) Exists(ctx context.Context, id string) (bool, error) {
 		Key:    aws.String(key),
 	})
 	if err != nil {
-		if strings.Contains(err.Error(), "NoSuchKey") {
+		if isS3NotFound(err) {
 			return false, nil
 		}
 		return false, fmt.Errorf("failed to check if schema %s exists in S3: %w", id, err)
@@ -192,6 +304,212 @@ func (s3s *S3Storage) getKey(id string) string {
 	return s3s.keyPrefix + path + ".json"
 }
 
+// schemaIDFromKey converts an S3 key back to a schema ID, rejecting the
+// version and index objects
+func (s3s *S3Storage) schemaIDFromKey(key string) (string, bool) {
+	if !strings.HasPrefix(key, s3s.keyPrefix) || !strings.HasSuffix(key, ".json") {
+		return "", false
+	}
+	path := strings.TrimSuffix(strings.TrimPrefix(key, s3s.keyPrefix), ".json")
+	if strings.HasPrefix(path, s3VersionsPrefix) || strings.HasPrefix(path, s3IndexPrefix) || strings.HasPrefix(path, s3TombstonesPrefix) || strings.HasPrefix(path, s3RecordsPrefix) {
+		return "", false
+	}
+	// Convert path separators back to dots
+	return strings.ReplaceAll(path, "/", "."), true
+}
+
+func (s3s *S3Storage) versionPrefix(id string) string {
+	return s3s.keyPrefix + s3VersionsPrefix + id + "/"
+}
+
+func (s3s *S3Storage) tombstoneKey(id string) string {
+	return s3s.keyPrefix + s3TombstonesPrefix + id
+}
+
+func (s3s *S3Storage) recordKey(key string) string {
+	return s3s.keyPrefix + s3RecordsPrefix + key + ".json"
+}
+
+func (s3s *S3Storage) indexPrefix(field, value string) string {
+	return s3s.keyPrefix + s3IndexPrefix + field + "/" + url.PathEscape(value) + "/"
+}
+
+// indexKeys returns the index marker keys of a schema's attributes
+func (s3s *S3Storage) indexKeys(id string, attrs storageAttributes) []string {
+	var keys []string
+	if attrs.Type != "" {
+		keys = append(keys, s3s.indexPrefix("type", attrs.Type)+id)
+	}
+	if attrs.Category != "" {
+		keys = append(keys, s3s.indexPrefix("category", attrs.Category)+id)
+	}
+	if attrs.Module != "" {
+		keys = append(keys, s3s.indexPrefix("module", attrs.Module)+id)
+	}
+	for _, tag := range attrs.Tags {
+		keys = append(keys, s3s.indexPrefix("tag", tag)+id)
+	}
+	return keys
+}
+
+// listKeys returns every object key under prefix
+func (s3s *S3Storage) listKeys(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	paginator := s3.NewListObjectsV2Paginator(s3s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s3s.bucket),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		// Check context cancellation
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range page.Contents {
+			if obj.Key != nil {
+				keys = append(keys, *obj.Key)
+			}
+		}
+	}
+	return keys, nil
+}
+
+func (s3s *S3Storage) putObject(ctx context.Context, key string, data []byte, metadata map[string]string, tags url.Values) error {
+	input := &s3.PutObjectInput{
+		Bucket:   aws.String(s3s.bucket),
+		Key:      aws.String(key),
+		Body:     bytes.NewReader(data),
+		Metadata: metadata,
+	}
+	if len(data) > 0 {
+		input.ContentType = aws.String("application/json")
+	}
+	if len(tags) > 0 {
+		input.Tagging = aws.String(tags.Encode())
+	}
+	_, err := s3s.client.PutObject(ctx, input)
+	return err
+}
+
+func (s3s *S3Storage) deleteObject(ctx context.Context, key string) error {
+	_, err := s3s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s3s.bucket),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+// s3SchemaMetadata builds the object metadata recording a schema's filter attributes
+func s3SchemaMetadata(id, version, createdAt string, attrs storageAttributes) map[string]string {
+	metadata := map[string]string{
+		"schema-id":      id,
+		"type":           "schema",
+		"schema-version": version,
+		"created-at":     createdAt,
+	}
+	if attrs.Type != "" {
+		metadata["schema-type"] = attrs.Type
+	}
+	if attrs.Category != "" {
+		metadata["schema-category"] = attrs.Category
+	}
+	if attrs.Module != "" {
+		metadata["schema-module"] = attrs.Module
+	}
+	if len(attrs.Tags) > 0 {
+		metadata["schema-tags"] = strings.Join(attrs.Tags, ",")
+	}
+	return metadata
+}
+
+// attributesFromS3Metadata reverses s3SchemaMetadata
+func attributesFromS3Metadata(metadata map[string]string) storageAttributes {
+	attrs := storageAttributes{
+		Type:     metadata["schema-type"],
+		Category: metadata["schema-category"],
+		Module:   metadata["schema-module"],
+	}
+	if tags := metadata["schema-tags"]; tags != "" {
+		attrs.Tags = strings.Split(tags, ",")
+	}
+	return attrs
+}
+
+// s3SchemaTags builds the object tags used for lifecycle and access policies
+func s3SchemaTags(kind string, attrs storageAttributes) url.Values {
+	tags := url.Values{"kind": {kind}}
+	if attrs.Type != "" {
+		tags.Set("type", attrs.Type)
+	}
+	if attrs.Category != "" {
+		tags.Set("category", attrs.Category)
+	}
+	if attrs.Module != "" {
+		tags.Set("module", attrs.Module)
+	}
+	return tags
+}
+
+// isS3NotFound reports whether err means the object does not exist
+func isS3NotFound(err error) bool {
+	var noSuchKey *types.NoSuchKey
+	var notFound *types.NotFound
+	if errors.As(err, &noSuchKey) || errors.As(err, &notFound) {
+		return true
+	}
+	return strings.Contains(err.Error(), "NoSuchKey") || strings.Contains(err.Error(), "NotFound")
+}
+
+// GetRecord implements RecordStorage
+func (s3s *S3Storage) GetRecord(ctx context.Context, key string) ([]byte, error) {
+	if err := validateSchemaID(key); err != nil {
+		return nil, err
+	}
+	result, err := s3s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s3s.bucket),
+		Key:    aws.String(s3s.recordKey(key)),
+	})
+	if err != nil {
+		if isS3NotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get record %s from S3: %w", key, err)
+	}
+	defer result.Body.Close()
+	data, err := io.ReadAll(result.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read record %s from S3: %w", key, err)
+	}
+	return data, nil
+}
+
+// SetRecord implements RecordStorage
+func (s3s *S3Storage) SetRecord(ctx context.Context, key string, data []byte) error {
+	if err := validateSchemaID(key); err != nil {
+		return err
+	}
+	if err := s3s.putObject(ctx, s3s.recordKey(key), data, nil, nil); err != nil {
+		return fmt.Errorf("failed to put record %s to S3: %w", key, err)
+	}
+	return nil
+}
+
+// DeleteRecord implements RecordStorage
+func (s3s *S3Storage) DeleteRecord(ctx context.Context, key string) error {
+	if err := validateSchemaID(key); err != nil {
+		return err
+	}
+	if err := s3s.deleteObject(ctx, s3s.recordKey(key)); err != nil && !isS3NotFound(err) {
+		return fmt.Errorf("failed to delete record %s from S3: %w", key, err)
+	}
+	return nil
+}
+
 // GetBucket returns the S3 bucket name used by this storage
 func (s3s *S3Storage) GetBucket() string {
 	return s3s.bucket
@@ -225,7 +543,10 @@ func (s3s *S3Storage) Stats(ctx context.Context) (*S3Stats, error) {
 			return nil, fmt.Errorf("failed to calculate S3 stats: %w", err)
 		}
 		for _, obj := range page.Contents {
-			if obj.Key != nil && strings.HasSuffix(*obj.Key, ".json") {
+			if obj.Key == nil {
+				continue
+			}
+			if _, ok := s3s.schemaIDFromKey(*obj.Key); ok {
 				stats.SchemaCount++
 				if obj.Size != nil {
 					stats.TotalSize += *obj.Size
@@ -245,8 +566,8 @@ type S3Stats struct {
 	TotalSize   int64  `json:"total_size_bytes"`
 }
 
-// BatchGet retrieves multiple schemas in a single operation using parallel requests
-func (s3s *S3Storage) BatchGet(ctx context.Context, ids []string) (map[string][]byte, error) {
+// GetBatch retrieves multiple schemas using parallel requests, omitting IDs that do not exist
+func (s3s *S3Storage) GetBatch(ctx context.Context, ids []string) (map[string][]byte, error) {
 	if len(ids) == 0 {
 		return make(map[string][]byte), nil
 	}
@@ -267,8 +588,13 @@ func (s3s *S3Storage) BatchGet(ctx context.Context, ids []string) (map[string][]
 		go func(schemaID string) {
 			data, err := s3s.Get(ctx, schemaID)
 			if err != nil {
-				errChan <- fmt.Errorf("failed to get schema %s: %w", schemaID, err)
-				return
+				exists, existsErr := s3s.Exists(ctx, schemaID)
+				if existsErr == nil && !exists {
+					data = nil
+				} else {
+					errChan <- fmt.Errorf("failed to get schema %s: %w", schemaID, err)
+					return
+				}
 			}
 			dataChan <- struct {
 				id   string
@@ -282,7 +608,9 @@ func (s3s *S3Storage) BatchGet(ctx context.Context, ids []string) (map[string][]
 		case err := <-errChan:
 			return nil, err
 		case data := <-dataChan:
-			result[data.id] = data.data
+			if data.data != nil {
+				result[data.id] = data.data
+			}
 		case <-ctx.Done():
 			return nil, ctx.Err()
 		}
@@ -290,8 +618,8 @@ func (s3s *S3Storage) BatchGet(ctx context.Context, ids []string) (map[string][]
 	return result, nil
 }
 
-// BatchSet stores multiple schemas using parallel requests
-func (s3s *S3Storage) BatchSet(ctx context.Context, schemas map[string][]byte) error {
+// SetBatch stores multiple schemas using parallel requests
+func (s3s *S3Storage) SetBatch(ctx context.Context, schemas map[string][]byte) error {
 	if len(schemas) == 0 {
 		return nil
 	}
@@ -378,7 +706,7 @@ func (s3s *S3Storage) GetObjectInfo(ctx context.Context, id string) (*S3ObjectIn
 		Key:    aws.String(key),
 	})
 	if err != nil {
-		if strings.Contains(err.Error(), "NoSuchKey") {
+		if isS3NotFound(err) {
 			return nil, fmt.Errorf("schema %s not found", id)
 		}
 		return nil, fmt.Errorf("failed to get object info for schema %s: %w", id, err)
@@ -406,7 +734,8 @@ type S3ObjectInfo struct {
 	Metadata     map[string]string `json:"metadata"`
 }
 
-// SetWithMetadata stores a schema with custom metadata
+// SetWithMetadata stores a schema with custom metadata. Custom entries are
+// merged over the defaults but cannot override the storage-managed keys
 func (s3s *S3Storage) SetWithMetadata(ctx context.Context, id string, data []byte, metadata map[string]string) error {
 	if err := validateSchemaID(id); err != nil {
 		return err
@@ -415,23 +744,59 @@ func (s3s *S3Storage) SetWithMetadata(ctx context.Context, id string, data []byt
 		return fmt.Errorf("cannot store empty schema data for %s", id)
 	}
 	key := s3s.getKey(id)
-	// Merge with default metadata
-	finalMetadata := map[string]string{
-		"schema-id": id,
-		"type":      "schema",
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	createdAt := now
+	var previous storageAttributes
+	head, err := s3s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s3s.bucket),
+		Key:    aws.String(key),
+	})
+	switch {
+	case err == nil:
+		previous = attributesFromS3Metadata(head.Metadata)
+		if created := head.Metadata["created-at"]; created != "" {
+			createdAt = created
+		}
+	case isS3NotFound(err):
+		// Recreating a deleted schema clears its tombstone
+		if err := s3s.deleteObject(ctx, s3s.tombstoneKey(id)); err != nil {
+			return fmt.Errorf("failed to clear tombstone of schema %s in S3: %w", id, err)
+		}
+	default:
+		return fmt.Errorf("failed to check schema %s in S3: %w", id, err)
 	}
+	attrs := extractStorageAttributes(data)
+	version := newStorageVersion()
+	// Merge with default metadata
+	finalMetadata := make(map[string]string, len(metadata)+8)
 	for k, v := range metadata {
 		finalMetadata[k] = v
 	}
-	_, err := s3s.client.PutObject(ctx, &s3.PutObjectInput{
-		Bucket:      aws.String(s3s.bucket),
-		Key:         aws.String(key),
-		Body:        bytes.NewReader(data),
-		ContentType: aws.String("application/json"),
-		Metadata:    finalMetadata,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to store schema %s with metadata in S3: %w", id, err)
+	for k, v := range s3SchemaMetadata(id, version, createdAt, attrs) {
+		finalMetadata[k] = v
+	}
+	if err := s3s.putObject(ctx, s3s.versionPrefix(id)+version+".json", data, finalMetadata, s3SchemaTags("version", attrs)); err != nil {
+		return fmt.Errorf("failed to store version of schema %s in S3: %w", id, err)
+	}
+	if err := s3s.putObject(ctx, key, data, finalMetadata, s3SchemaTags("schema", attrs)); err != nil {
+		return fmt.Errorf("failed to store schema %s in S3: %w", id, err)
+	}
+	current := make(map[string]bool)
+	for _, marker := range s3s.indexKeys(id, attrs) {
+		current[marker] = true
+	}
+	for _, marker := range s3s.indexKeys(id, previous) {
+		if current[marker] {
+			continue
+		}
+		if err := s3s.deleteObject(ctx, marker); err != nil {
+			return fmt.Errorf("failed to delete stale index entry of schema %s from S3: %w", id, err)
+		}
+	}
+	for marker := range current {
+		if err := s3s.putObject(ctx, marker, nil, nil, nil); err != nil {
+			return fmt.Errorf("failed to index schema %s in S3: %w", id, err)
+		}
 	}
 	return nil
 }
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -19,6 +20,7 @@ import (
 type MockS3Client struct {
 	objects  map[string][]byte
 	metadata map[string]map[string]string
+	mu       sync.Mutex
 }
 
 func NewMockS3Client() *MockS3Client {
@@ -29,6 +31,8 @@ func NewMockS3Client() *MockS3Client {
 }
 
 func (m *MockS3Client) GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	key := aws.ToString(params.Key)
 	data, exists := m.objects[key]
 	if !exists {
@@ -40,6 +44,8 @@ func (m *MockS3Client) GetObject(ctx context.Context, params *s3.GetObjectInput,
 }
 
 func (m *MockS3Client) PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	key := aws.ToString(params.Key)
 	data, err := io.ReadAll(params.Body)
 	if err != nil {
@@ -55,6 +61,8 @@ func (m *MockS3Client) PutObject(ctx context.Context, params *s3.PutObjectInput,
 }
 
 func (m *MockS3Client) DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	key := aws.ToString(params.Key)
 	delete(m.objects, key)
 	delete(m.metadata, key)
@@ -62,6 +70,8 @@ func (m *MockS3Client) DeleteObject(ctx context.Context, params *s3.DeleteObject
 }
 
 func (m *MockS3Client) HeadObject(ctx context.Context, params *s3.HeadObjectInput, optFns ...func(*s3.Options)) (*s3.HeadObjectOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	key := aws.ToString(params.Key)
 	data, exists := m.objects[key]
 	if !exists {
@@ -82,6 +92,8 @@ func (m *MockS3Client) HeadObject(ctx context.Context, params *s3.HeadObjectInpu
 }
 
 func (m *MockS3Client) ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	prefix := aws.ToString(params.Prefix)
 	var contents []types.Object
 	for key, data := range m.objects {
@@ -100,6 +112,17 @@ func (m *MockS3Client) ListObjectsV2(ctx context.Context, params *s3.ListObjects
 	}, nil
 }
 
+func (m *MockS3Client) HeadBucket(ctx context.Context, params *s3.HeadBucketInput, optFns ...func(*s3.Options)) (*s3.HeadBucketOutput, error) {
+	return &s3.HeadBucketOutput{}, nil
+}
+
+var _ StorageBackend = (*S3Storage)(nil)
+
+func newMockS3Storage() (*S3Storage, *MockS3Client) {
+	client := NewMockS3Client()
+	return &S3Storage{client: client, bucket: "test-bucket", keyPrefix: "schemas/"}, client
+}
+
 func TestNewS3Storage(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -379,18 +402,18 @@ func TestS3Storage_BatchOperations_Structure(t *testing.T) {
 		keyPrefix: "schemas/",
 	}
 	ctx := context.Background()
-	// Test BatchGet with empty input
-	result, err := storage.BatchGet(ctx, []string{})
+	// Test GetBatch with empty input
+	result, err := storage.GetBatch(ctx, []string{})
 	if err != nil {
-		t.Errorf("BatchGet with empty input should not error: %v", err)
+		t.Errorf("GetBatch with empty input should not error: %v", err)
 	}
 	if len(result) != 0 {
-		t.Errorf("BatchGet with empty input should return empty map")
+		t.Errorf("GetBatch with empty input should return empty map")
 	}
-	// Test BatchSet with empty input
-	err = storage.BatchSet(ctx, map[string][]byte{})
+	// Test SetBatch with empty input
+	err = storage.SetBatch(ctx, map[string][]byte{})
 	if err != nil {
-		t.Errorf("BatchSet with empty input should not error: %v", err)
+		t.Errorf("SetBatch with empty input should not error: %v", err)
 	}
 	// Test BatchDelete with empty input
 	err = storage.BatchDelete(ctx, []string{})
@@ -402,6 +425,102 @@ func TestS3Storage_BatchOperations_Structure(t *testing.T) {
 	_ = ctx
 }
 
+func TestS3Storage_Versions(t *testing.T) {
+	storage, client := newMockS3Storage()
+	ctx := context.Background()
+	if err := storage.Set(ctx, "user.profile", []byte(`{"id":"user.profile","title":"v1"}`)); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := storage.Set(ctx, "user.profile", []byte(`{"id":"user.profile","title":"v2"}`)); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	versions, err := storage.ListVersions(ctx, "user.profile")
+	if err != nil {
+		t.Fatalf("ListVersions() error = %v", err)
+	}
+	if len(versions) != 2 {
+		t.Fatalf("ListVersions() returned %d versions, want 2", len(versions))
+	}
+	data, err := storage.GetVersion(ctx, "user.profile", versions[0])
+	if err != nil || !strings.Contains(string(data), "v1") {
+		t.Errorf("GetVersion() = %s, %v, want first version", data, err)
+	}
+	meta, err := storage.GetMetadata(ctx, "user.profile")
+	if err != nil {
+		t.Fatalf("GetMetadata() error = %v", err)
+	}
+	if meta.Version != versions[1] {
+		t.Errorf("GetMetadata() version = %s, want %s", meta.Version, versions[1])
+	}
+	// Versions are not listed as schemas
+	ids, err := storage.List(ctx, nil)
+	if err != nil || len(ids) != 1 || ids[0] != "user.profile" {
+		t.Errorf("List() = %v, %v, want [user.profile]", ids, err)
+	}
+	if err := storage.Delete(ctx, "user.profile"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	// Delete tombstones the schema and keeps its history
+	if exists, _ := storage.Exists(ctx, "user.profile"); exists {
+		t.Error("Exists() = true after Delete()")
+	}
+	if ids, _ := storage.List(ctx, nil); len(ids) != 0 {
+		t.Errorf("List() = %v after Delete(), want none", ids)
+	}
+	if kept, _ := storage.ListVersions(ctx, "user.profile"); len(kept) != 2 {
+		t.Errorf("ListVersions() after Delete() = %v, want the 2 versions", kept)
+	}
+	if _, err := storage.GetVersion(ctx, "user.profile", versions[0]); err != nil {
+		t.Errorf("GetVersion() after Delete() error = %v", err)
+	}
+	tombstone := client.metadata[storage.tombstoneKey("user.profile")]
+	if tombstone["deleted-at"] == "" || tombstone["schema-version"] != versions[1] {
+		t.Errorf("tombstone metadata = %v", tombstone)
+	}
+	if err := storage.Set(ctx, "user.profile", []byte(`{"id":"user.profile","title":"v3"}`)); err != nil {
+		t.Fatalf("Set() after Delete() error = %v", err)
+	}
+	if _, ok := client.objects[storage.tombstoneKey("user.profile")]; ok {
+		t.Error("Set() after Delete() left the tombstone behind")
+	}
+}
+
+func TestS3Storage_ListFilter(t *testing.T) {
+	storage, _ := newMockS3Storage()
+	ctx := context.Background()
+	schemas := map[string][]byte{
+		"user.form":    []byte(`{"id":"user.form","type":"form","module":"crm","tags":["public"]}`),
+		"user.table":   []byte(`{"id":"user.table","type":"table","module":"crm","tags":["public"]}`),
+		"invoice.form": []byte(`{"id":"invoice.form","type":"form","module":"billing"}`),
+	}
+	if err := storage.SetBatch(ctx, schemas); err != nil {
+		t.Fatalf("SetBatch() error = %v", err)
+	}
+	ids, err := storage.List(ctx, &StorageFilter{Type: "form"})
+	if err != nil || fmt.Sprint(ids) != "[invoice.form user.form]" {
+		t.Errorf("List(type=form) = %v, %v", ids, err)
+	}
+	ids, err = storage.List(ctx, &StorageFilter{Module: "crm", Tags: []string{"public"}, Limit: 1})
+	if err != nil || fmt.Sprint(ids) != "[user.form]" {
+		t.Errorf("List(module=crm, tag=public, limit=1) = %v, %v", ids, err)
+	}
+	// Changing attributes moves the schema between index entries
+	if err := storage.Set(ctx, "user.form", []byte(`{"id":"user.form","type":"wizard"}`)); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	ids, err = storage.List(ctx, &StorageFilter{Type: "form"})
+	if err != nil || fmt.Sprint(ids) != "[invoice.form]" {
+		t.Errorf("List(type=form) after update = %v, %v", ids, err)
+	}
+	batch, err := storage.GetBatch(ctx, []string{"user.table", "missing"})
+	if err != nil || len(batch) != 1 {
+		t.Errorf("GetBatch() = %v, %v, want only user.table", batch, err)
+	}
+	if err := storage.Health(ctx); err != nil {
+		t.Errorf("Health() error = %v", err)
+	}
+}
+
 func TestS3Storage_Configuration(t *testing.T) {
 	// Test different prefix configurations
 	tests := []struct {
@@ -16,9 +16,13 @@ type Registry struct {
 	parser    *Parser
 	validator schemaValidator
 	events    *RegistryEventBus
+	lifecycle LifecycleStore
+	resolver  *SchemaResolver
 	metrics   *RegistryMetrics
 	config    *RegistryConfig
 
+	migrationFuncs map[string]MigrationFunc
+
 	mu sync.RWMutex
 }
 
@@ -38,6 +42,9 @@ func DefaultRegistryConfig() *RegistryConfig {
 		MaxVersions:            10,
 		EnableEvents:           true,
 		EnableMetrics:          true,
+		EnableLifecycle:        false,
+		DefaultEnvironment:     string(EnvironmentProd),
+		RejectBreakingChanges:  false,
 	}
 }
 
@@ -52,10 +59,11 @@ func NewRegistry(config *RegistryConfig) (*Registry, error) {
 	}
 
 	registry := &Registry{
-		parser:  NewParser(),
 		config:  config,
 		events:  NewRegistryEventBus(),
 		metrics: NewRegistryMetrics(),
+
+		migrationFuncs: make(map[string]MigrationFunc),
 	}
 
 	// Initialize storage
@@ -64,6 +72,12 @@ func NewRegistry(config *RegistryConfig) (*Registry, error) {
 		return nil, fmt.Errorf("create storage backend: %w", err)
 	}
 	registry.storage = storage
+	registry.lifecycle = NewStorageLifecycleStore(storage)
+
+	// Resolve extends, mixins and $ref against this registry
+	registry.resolver = NewSchemaResolver(registrySource{registry}, nil)
+	registry.parser = NewParser(WithResolver(registry.resolver))
+	registry.resolver.OnInvalidate = registry.parser.Invalidate
 
 	// Initialize cache if enabled
 	if config.EnableMemoryCache || config.EnableDistributedCache {
@@ -94,8 +108,8 @@ func (r *Registry) Register(ctx context.Context, schema *Schema) error {
 
 	// Validate schema if enabled
 	if r.config.ValidateOnStore {
-		if err := schema.Validate(ctx); err != nil {
-			return fmt.Errorf("schema validation failed: %w", err)
+		if err := r.validateForStore(ctx, schema); err != nil {
+			return err
 		}
 	}
 
@@ -119,6 +133,13 @@ func (r *Registry) Register(ctx context.Context, schema *Schema) error {
 		return fmt.Errorf("store schema: %w", err)
 	}
 
+	// Record a draft revision; it is served once published and deployed
+	if r.config.EnableLifecycle {
+		if err := r.recordRevision(ctx, schema.ID, data); err != nil {
+			return fmt.Errorf("record revision: %w", err)
+		}
+	}
+
 	// Cache if enabled
 	if r.cache != nil {
 		cacheKey := r.buildCacheKey(schema.ID, "")
@@ -130,9 +151,12 @@ func (r *Registry) Register(ctx context.Context, schema *Schema) error {
 
 	// Emit event
 	if r.config.EnableEvents {
+		actorID, tenantID := registryActor(ctx)
 		r.events.Emit(&RegistryEvent{
 			Type:      EventSchemaRegistered,
 			SchemaID:  schema.ID,
+			ActorID:   actorID,
+			TenantID:  tenantID,
 			Timestamp: time.Now(),
 		})
 	}
@@ -145,8 +169,10 @@ func (r *Registry) Register(ctx context.Context, schema *Schema) error {
 	return nil
 }
 
-// Get retrieves a schema by ID
-func (r *Registry) Get(ctx context.Context, id string) (*Schema, error) {
+// Get retrieves a schema by ID. With lifecycle enabled it returns the
+// revision deployed to the caller's environment, set with WithEnvironment
+// and defaulting to RegistryConfig.DefaultEnvironment.
+func (r *Registry) Get(ctx context.Context, id string, opts ...EnrichOption) (*Schema, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -157,6 +183,21 @@ func (r *Registry) Get(ctx context.Context, id string) (*Schema, error) {
 		}
 	}()
 
+	if r.config.EnableLifecycle {
+		schema, err := r.getDeployed(ctx, id, opts)
+		if err != nil {
+			return nil, err
+		}
+		if r.config.EnableEvents {
+			r.events.Emit(&RegistryEvent{
+				Type:      EventSchemaAccessed,
+				SchemaID:  id,
+				Timestamp: time.Now(),
+			})
+		}
+		return schema, nil
+	}
+
 	// Try cache first
 	if r.cache != nil {
 		cacheKey := r.buildCacheKey(id, "")
@@ -212,7 +253,12 @@ func (r *Registry) Get(ctx context.Context, id string) (*Schema, error) {
 }
 
 // Update updates an existing schema
-func (r *Registry) Update(ctx context.Context, schema *Schema) error {
+func (r *Registry) Update(ctx context.Context, schema *Schema, opts ...UpdateOption) error {
+	options := &updateOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -234,8 +280,15 @@ func (r *Registry) Update(ctx context.Context, schema *Schema) error {
 
 	// Validate if enabled
 	if r.config.ValidateOnStore {
-		if err := schema.Validate(ctx); err != nil {
-			return fmt.Errorf("schema validation failed: %w", err)
+		if err := r.validateForStore(ctx, schema); err != nil {
+			return err
+		}
+	}
+
+	// Refuse breaking changes unless a migration is supplied
+	if r.config.RejectBreakingChanges || options.migration != nil {
+		if err := r.checkBreakingChanges(ctx, schema, options.migration); err != nil {
+			return err
 		}
 	}
 
@@ -249,8 +302,20 @@ func (r *Registry) Update(ctx context.Context, schema *Schema) error {
 	if err := r.storage.Set(ctx, schema.ID, data); err != nil {
 		return fmt.Errorf("store schema: %w", err)
 	}
+	if options.migration != nil {
+		if err := r.recordMigration(ctx, schema.ID, options.migration); err != nil {
+			return fmt.Errorf("record migration: %w", err)
+		}
+	}
 
-	// Invalidate cache
+	// Record a draft revision; deployed revisions keep being served
+	if r.config.EnableLifecycle {
+		if err := r.recordRevision(ctx, schema.ID, data); err != nil {
+			return fmt.Errorf("record revision: %w", err)
+		}
+	}
+
+	// Invalidate cache, including schemas composed from this one
 	if r.cache != nil {
 		cacheKey := r.buildCacheKey(schema.ID, "")
 		if err := r.cache.Delete(ctx, cacheKey); err != nil {
@@ -258,12 +323,16 @@ func (r *Registry) Update(ctx context.Context, schema *Schema) error {
 			fmt.Printf("cache delete failed: %v\n", err)
 		}
 	}
+	r.resolver.Invalidate(schema.ID)
 
 	// Emit event
 	if r.config.EnableEvents {
+		actorID, tenantID := registryActor(ctx)
 		r.events.Emit(&RegistryEvent{
 			Type:      EventSchemaUpdated,
 			SchemaID:  schema.ID,
+			ActorID:   actorID,
+			TenantID:  tenantID,
 			Timestamp: time.Now(),
 		})
 	}
@@ -287,21 +356,32 @@ func (r *Registry) Delete(ctx context.Context, id string) error {
 	if err := r.storage.Delete(ctx, id); err != nil {
 		return fmt.Errorf("storage delete: %w", err)
 	}
+	if err := r.lifecycle.Delete(ctx, id); err != nil {
+		return fmt.Errorf("lifecycle delete: %w", err)
+	}
+	if err := deleteRecord(ctx, r.storage, migrationRecordPrefix+id); err != nil {
+		return fmt.Errorf("migrations delete: %w", err)
+	}
 
 	// Invalidate cache
 	if r.cache != nil {
-		cacheKey := r.buildCacheKey(id, "")
-		if err := r.cache.Delete(ctx, cacheKey); err != nil {
-			// Log but don't fail
-			fmt.Printf("cache delete failed: %v\n", err)
+		for _, cacheKey := range []string{r.buildCacheKey(id, ""), r.buildCacheKey(id, lifecycleCacheVersion)} {
+			if err := r.cache.Delete(ctx, cacheKey); err != nil {
+				// Log but don't fail
+				fmt.Printf("cache delete failed: %v\n", err)
+			}
 		}
 	}
+	r.resolver.Invalidate(id)
 
 	// Emit event
 	if r.config.EnableEvents {
+		actorID, tenantID := registryActor(ctx)
 		r.events.Emit(&RegistryEvent{
 			Type:      EventSchemaDeleted,
 			SchemaID:  id,
+			ActorID:   actorID,
+			TenantID:  tenantID,
 			Timestamp: time.Now(),
 		})
 	}
@@ -327,7 +407,7 @@ func (r *Registry) List(ctx context.Context, filter *StorageFilter) ([]*Schema,
 	}()
 
 	// Get IDs from storage
-	ids, err := r.storage.List(ctx, filter)
+	ids, err := r.listSchemaIDs(ctx, filter)
 	if err != nil {
 		return nil, fmt.Errorf("storage list: %w", err)
 	}
@@ -462,6 +542,9 @@ func (r *Registry) InvalidateCache(ctx context.Context, id string) error {
 		return nil
 	}
 
+	if err := r.cache.Delete(ctx, r.buildCacheKey(id, lifecycleCacheVersion)); err != nil {
+		return err
+	}
 	cacheKey := r.buildCacheKey(id, "")
 	return r.cache.Delete(ctx, cacheKey)
 }
@@ -471,6 +554,15 @@ func (r *Registry) GetMetrics() *RegistryMetrics {
 	return r.metrics
 }
 
+// registryActor returns the user making a change and their tenant, as
+// attached to ctx with ContextWithUser
+func registryActor(ctx context.Context) (actorID, tenantID string) {
+	if user, ok := UserFromContext(ctx); ok {
+		return user.GetID(), user.GetTenantID()
+	}
+	return "", ""
+}
+
 // Subscribe subscribes to registry events
 func (r *Registry) Subscribe(handler RegistryEventHandler) {
 	if r.config.EnableEvents {
@@ -478,6 +570,49 @@ func (r *Registry) Subscribe(handler RegistryEventHandler) {
 	}
 }
 
+// ConnectEvents propagates this registry's register, update and delete
+// events over transport and invalidates the local registry and parser caches
+// when other nodes change a schema. Remote events are also delivered to
+// handlers added with Subscribe, with RegistryEvent.Remote set.
+func (r *Registry) ConnectEvents(ctx context.Context, transport RegistryEventTransport) error {
+	if !r.config.EnableEvents {
+		return fmt.Errorf("events not enabled")
+	}
+	if transport == nil {
+		return fmt.Errorf("event transport is required")
+	}
+	return r.events.Connect(ctx, transport, r.handleRemoteEvent)
+}
+
+// DisconnectEvents stops propagating events between nodes
+func (r *Registry) DisconnectEvents() {
+	r.events.Disconnect()
+}
+
+// NodeID returns the ID that identifies this registry's events to other nodes
+func (r *Registry) NodeID() string {
+	return r.events.NodeID()
+}
+
+// handleRemoteEvent drops cached copies of a schema changed on another node
+func (r *Registry) handleRemoteEvent(event *RegistryEvent) {
+	ctx := context.Background()
+
+	switch event.Type {
+	case EventSchemaRegistered, EventSchemaUpdated, EventSchemaDeleted:
+		if err := r.InvalidateCache(ctx, event.SchemaID); err != nil {
+			fmt.Printf("cache invalidate failed: %v\n", err)
+		}
+		r.resolver.Invalidate(event.SchemaID)
+	case EventRegistryResync:
+		if err := r.ClearCache(ctx); err != nil {
+			fmt.Printf("cache clear failed: %v\n", err)
+		}
+		r.resolver.Clear()
+		r.parser.ClearCache()
+	}
+}
+
 // buildCacheKey builds cache key
 func (r *Registry) buildCacheKey(id, version string) string {
 	if version == "" {
@@ -1,19 +1,89 @@
 package schema
 
 import (
+	"context"
 	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RegistryEventTransport carries registry events between registry instances.
+// Publish may deliver an event more than once; receivers de-duplicate by
+// event ID.
+type RegistryEventTransport interface {
+	// Publish sends an event to every subscribed node, including the sender
+	Publish(ctx context.Context, event *RegistryEvent) error
+	// Subscribe delivers events to handler until the returned cancel func is called
+	Subscribe(ctx context.Context, handler RegistryEventHandler) (cancel func(), err error)
+}
+
+const (
+	// registryEventDedupWindow is how long delivered event IDs are remembered
+	registryEventDedupWindow = 10 * time.Minute
+	// registryEventRetryMax caps the delay between publish retries
+	registryEventRetryMax = 30 * time.Second
+	// DefaultRegistryEventOutboxSize is the number of unpublished events kept
+	// when RegistryEventBus.MaxPending is not set
+	DefaultRegistryEventOutboxSize = 10000
 )
 
-// RegistryEventBus manages event subscriptions
+// RegistryEventBus manages event subscriptions. Events are always delivered
+// to local handlers; when a transport is attached, mutating events are also
+// published to other nodes and their events are delivered here.
 type RegistryEventBus struct {
 	handlers []RegistryEventHandler
 	mu       sync.RWMutex
+
+	nodeID    string
+	transport RegistryEventTransport
+	cancel    func()
+	onRemote  RegistryEventHandler
+
+	// Outbox of events awaiting a successful publish
+	outbox   []*RegistryEvent
+	outboxMu sync.Mutex
+	notify   chan struct{}
+	done     chan struct{}
+	dropped  atomic.Int64
+
+	// Recently delivered remote event IDs
+	seen   map[string]time.Time
+	seenMu sync.Mutex
+
+	// OnPublishError receives transport failures before the event is retried
+	OnPublishError func(event *RegistryEvent, err error)
+
+	// MaxPending caps the outbox while the transport is failing. When it is
+	// full the oldest event is dropped to make room; other nodes then miss
+	// that event and keep stale cache entries until they expire. Zero means
+	// DefaultRegistryEventOutboxSize.
+	MaxPending int
+	// OnDrop receives events dropped because the outbox was full
+	OnDrop func(event *RegistryEvent)
 }
 
 // NewRegistryEventBus creates event bus
 func NewRegistryEventBus() *RegistryEventBus {
 	return &RegistryEventBus{
 		handlers: []RegistryEventHandler{},
+		nodeID:   uuid.NewString(),
+		seen:     make(map[string]time.Time),
+	}
+}
+
+// NodeID returns the ID stamped on events emitted by this bus
+func (b *RegistryEventBus) NodeID() string {
+	return b.nodeID
+}
+
+// SetNodeID overrides the generated node ID, e.g. with a pod name
+func (b *RegistryEventBus) SetNodeID(nodeID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if nodeID != "" {
+		b.nodeID = nodeID
 	}
 }
 
@@ -24,12 +94,271 @@ func (b *RegistryEventBus) Subscribe(handler RegistryEventHandler) {
 	b.handlers = append(b.handlers, handler)
 }
 
-// Emit publishes event
+// Emit publishes event to local handlers and, for mutating events, to the
+// attached transport. Publishing is asynchronous and retried until it succeeds.
 func (b *RegistryEventBus) Emit(event *RegistryEvent) {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 
+	if event.ID == "" {
+		event.ID = uuid.NewString()
+	}
+	if event.Origin == "" {
+		event.Origin = b.nodeID
+	}
+
 	for _, handler := range b.handlers {
 		go handler(event)
 	}
+
+	if b.transport != nil && isPropagatedEvent(event.Type) {
+		b.enqueue(event)
+	}
+}
+
+// Connect attaches a transport. Remote events are passed to onRemote before
+// local handlers; onRemote may be nil. Connect replaces any previous transport.
+func (b *RegistryEventBus) Connect(ctx context.Context, transport RegistryEventTransport, onRemote RegistryEventHandler) error {
+	b.Disconnect()
+
+	cancel, err := transport.Subscribe(ctx, b.receive)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	b.transport = transport
+	b.cancel = cancel
+	b.onRemote = onRemote
+	b.notify = make(chan struct{}, 1)
+	b.done = make(chan struct{})
+	notify, done := b.notify, b.done
+	b.mu.Unlock()
+
+	go b.publishLoop(transport, notify, done)
+	return nil
+}
+
+// Disconnect detaches the transport. Events still in the outbox are kept and
+// published once a transport is connected again.
+func (b *RegistryEventBus) Disconnect() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.transport == nil {
+		return
+	}
+	b.cancel()
+	close(b.done)
+	b.transport = nil
+	b.cancel = nil
+	b.onRemote = nil
+}
+
+// Pending returns the number of events not yet published
+func (b *RegistryEventBus) Pending() int {
+	b.outboxMu.Lock()
+	defer b.outboxMu.Unlock()
+	return len(b.outbox)
+}
+
+// Dropped returns the number of events dropped because the outbox was full
+func (b *RegistryEventBus) Dropped() int64 {
+	return b.dropped.Load()
+}
+
+// isPropagatedEvent reports whether other nodes need the event. Accesses are
+// local-only; they do not affect other nodes' caches.
+func isPropagatedEvent(eventType RegistryEventType) bool {
+	return eventType != EventSchemaAccessed
+}
+
+// enqueue appends an event to the outbox, dropping the oldest events when
+// the outbox is full
+func (b *RegistryEventBus) enqueue(event *RegistryEvent) {
+	limit := b.MaxPending
+	if limit <= 0 {
+		limit = DefaultRegistryEventOutboxSize
+	}
+
+	b.outboxMu.Lock()
+	var dropped []*RegistryEvent
+	if overflow := len(b.outbox) + 1 - limit; overflow > 0 {
+		dropped = append(dropped, b.outbox[:overflow]...)
+		b.outbox = append(b.outbox[:0:0], b.outbox[overflow:]...)
+	}
+	b.outbox = append(b.outbox, event)
+	b.outboxMu.Unlock()
+
+	for _, event := range dropped {
+		b.dropped.Add(1)
+		if b.OnDrop != nil {
+			b.OnDrop(event)
+		}
+	}
+
+	select {
+	case b.notify <- struct{}{}:
+	default:
+	}
+}
+
+// publishLoop drains the outbox in order, retrying the head event with
+// exponential backoff until the transport accepts it.
+func (b *RegistryEventBus) publishLoop(transport RegistryEventTransport, notify <-chan struct{}, done <-chan struct{}) {
+	backoff := 100 * time.Millisecond
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-notify:
+		case <-timer.C:
+		}
+
+		for {
+			b.outboxMu.Lock()
+			if len(b.outbox) == 0 {
+				b.outboxMu.Unlock()
+				break
+			}
+			event := b.outbox[0]
+			b.outboxMu.Unlock()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			err := transport.Publish(ctx, event)
+			cancel()
+			if err != nil {
+				if b.OnPublishError != nil {
+					b.OnPublishError(event, err)
+				}
+				timer.Reset(backoff)
+				backoff = min(backoff*2, registryEventRetryMax)
+				break
+			}
+			backoff = 100 * time.Millisecond
+
+			// The head may have been dropped while it was being published
+			b.outboxMu.Lock()
+			if len(b.outbox) > 0 && b.outbox[0] == event {
+				b.outbox = b.outbox[1:]
+			}
+			b.outboxMu.Unlock()
+
+			select {
+			case <-done:
+				return
+			default:
+			}
+		}
+	}
+}
+
+// receive handles an event from the transport. Events emitted by this node
+// and events already delivered are dropped.
+func (b *RegistryEventBus) receive(event *RegistryEvent) {
+	b.mu.RLock()
+	nodeID, onRemote := b.nodeID, b.onRemote
+	handlers := append([]RegistryEventHandler(nil), b.handlers...)
+	b.mu.RUnlock()
+
+	if event.Origin == nodeID || !b.markSeen(event.ID) {
+		return
+	}
+	event.Remote = true
+
+	if onRemote != nil {
+		onRemote(event)
+	}
+	for _, handler := range handlers {
+		go handler(event)
+	}
+}
+
+// markSeen records an event ID, returning false when it was already seen
+// within the de-duplication window. Events without an ID are never dropped.
+func (b *RegistryEventBus) markSeen(id string) bool {
+	if id == "" {
+		return true
+	}
+
+	b.seenMu.Lock()
+	defer b.seenMu.Unlock()
+
+	now := time.Now()
+	if seenAt, ok := b.seen[id]; ok && now.Sub(seenAt) < registryEventDedupWindow {
+		return false
+	}
+	b.seen[id] = now
+
+	if len(b.seen) > 1024 {
+		for key, seenAt := range b.seen {
+			if now.Sub(seenAt) >= registryEventDedupWindow {
+				delete(b.seen, key)
+			}
+		}
+	}
+	return true
+}
+
+// MemoryRegistryTransport connects registries within a single process. It is
+// the loopback transport for tests and for running several registries in one
+// binary.
+type MemoryRegistryTransport struct {
+	subscribers map[int]chan *RegistryEvent
+	next        int
+	mu          sync.RWMutex
+}
+
+// NewMemoryRegistryTransport creates an in-process transport
+func NewMemoryRegistryTransport() *MemoryRegistryTransport {
+	return &MemoryRegistryTransport{
+		subscribers: make(map[int]chan *RegistryEvent),
+	}
+}
+
+// Publish delivers a copy of event to every subscriber, blocking while a
+// subscriber's buffer is full
+func (t *MemoryRegistryTransport) Publish(ctx context.Context, event *RegistryEvent) error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	for _, ch := range t.subscribers {
+		clone := *event
+		select {
+		case ch <- &clone:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Subscribe delivers events to handler in publish order
+func (t *MemoryRegistryTransport) Subscribe(ctx context.Context, handler RegistryEventHandler) (func(), error) {
+	ch := make(chan *RegistryEvent, 256)
+
+	t.mu.Lock()
+	id := t.next
+	t.next++
+	t.subscribers[id] = ch
+	t.mu.Unlock()
+
+	go func() {
+		for event := range ch {
+			handler(event)
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			t.mu.Lock()
+			delete(t.subscribers, id)
+			t.mu.Unlock()
+			close(ch)
+		})
+	}, nil
 }
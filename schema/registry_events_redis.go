@@ -0,0 +1,93 @@
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// RedisRegistryTransport carries registry events between nodes over Redis
+// pub/sub. Pub/sub does not buffer messages for disconnected subscribers, so
+// after every reconnect the handler receives an EventRegistryResync event and
+// must treat all cached schemas as stale.
+type RedisRegistryTransport struct {
+	client  redis.UniversalClient
+	channel string
+}
+
+// NewRedisRegistryTransport creates a Redis transport. An empty channel
+// defaults to "schema:registry:events".
+func NewRedisRegistryTransport(client redis.UniversalClient, channel string) (*RedisRegistryTransport, error) {
+	if client == nil {
+		return nil, fmt.Errorf("redis client is required")
+	}
+	if channel == "" {
+		channel = "schema:registry:events"
+	}
+	return &RedisRegistryTransport{client: client, channel: channel}, nil
+}
+
+// Publish sends an event to the channel
+func (t *RedisRegistryTransport) Publish(ctx context.Context, event *RegistryEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode registry event: %w", err)
+	}
+	if err := t.client.Publish(ctx, t.channel, data).Err(); err != nil {
+		return fmt.Errorf("failed to publish registry event: %w", err)
+	}
+	return nil
+}
+
+// Subscribe listens on the channel until cancelled. Undecodable messages are skipped.
+func (t *RedisRegistryTransport) Subscribe(ctx context.Context, handler RegistryEventHandler) (func(), error) {
+	pubsub := t.client.Subscribe(ctx, t.channel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, fmt.Errorf("failed to subscribe to registry events: %w", err)
+	}
+
+	go func() {
+		for {
+			msg, err := pubsub.Receive(context.Background())
+			if err != nil {
+				if err == redis.ErrClosed {
+					return
+				}
+				// The client reconnects and resubscribes on the next Receive
+				time.Sleep(time.Second)
+				continue
+			}
+			if event := t.decode(msg); event != nil {
+				handler(event)
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { pubsub.Close() })
+	}, nil
+}
+
+// decode converts a pub/sub message to an event. A subscription confirmation
+// after the initial one means the connection was re-established and events
+// may have been missed.
+func (t *RedisRegistryTransport) decode(msg any) *RegistryEvent {
+	switch msg := msg.(type) {
+	case *redis.Subscription:
+		if msg.Kind == "subscribe" {
+			return &RegistryEvent{Type: EventRegistryResync}
+		}
+	case *redis.Message:
+		var event RegistryEvent
+		if err := json.Unmarshal([]byte(msg.Payload), &event); err == nil {
+			return &event
+		}
+	}
+	return nil
+}
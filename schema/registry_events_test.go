@@ -0,0 +1,174 @@
+package schema
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/go-redis/redismock/v8"
+	"github.com/stretchr/testify/require"
+)
+
+func newEventTestRegistry(t *testing.T, storage StorageBackend) *Registry {
+	t.Helper()
+	config := DefaultRegistryConfig()
+	config.ValidateOnStore = false
+	config.ValidateOnLoad = false
+	registry, err := NewRegistry(config)
+	require.NoError(t, err)
+	registry.storage = storage
+	return registry
+}
+
+func TestRegistry_RemoteEventsInvalidateCache(t *testing.T) {
+	ctx := context.Background()
+	storage := NewMemoryStorage()
+	transport := NewMemoryRegistryTransport()
+
+	nodeA := newEventTestRegistry(t, storage)
+	nodeB := newEventTestRegistry(t, storage)
+	require.NoError(t, nodeA.ConnectEvents(ctx, transport))
+	require.NoError(t, nodeB.ConnectEvents(ctx, transport))
+	defer nodeA.DisconnectEvents()
+	defer nodeB.DisconnectEvents()
+
+	remote := make(chan *RegistryEvent, 4)
+	nodeB.Subscribe(func(event *RegistryEvent) {
+		if event.Remote {
+			remote <- event
+		}
+	})
+
+	require.NoError(t, nodeA.Register(ctx, &Schema{ID: "user.profile", Type: TypeForm, Title: "Profile"}))
+	<-remote
+
+	// Warm node B's cache, then update the schema on node A
+	schema, err := nodeB.Get(ctx, "user.profile")
+	require.NoError(t, err)
+	require.Equal(t, "Profile", schema.Title)
+
+	require.NoError(t, nodeA.Update(ctx, &Schema{ID: "user.profile", Type: TypeForm, Title: "User Profile"}))
+	select {
+	case event := <-remote:
+		require.Equal(t, EventSchemaUpdated, event.Type)
+		require.Equal(t, nodeA.NodeID(), event.Origin)
+	case <-time.After(time.Second):
+		t.Fatal("update was not propagated")
+	}
+
+	schema, err = nodeB.Get(ctx, "user.profile")
+	require.NoError(t, err)
+	require.Equal(t, "User Profile", schema.Title)
+}
+
+func TestRegistryEventBus_Dedup(t *testing.T) {
+	bus := NewRegistryEventBus()
+	var received atomic.Int32
+	var wg sync.WaitGroup
+	bus.onRemote = func(*RegistryEvent) {
+		received.Add(1)
+		wg.Done()
+	}
+
+	wg.Add(1)
+	event := &RegistryEvent{ID: "evt-1", Type: EventSchemaUpdated, SchemaID: "a", Origin: "other"}
+	bus.receive(event)
+	bus.receive(&RegistryEvent{ID: "evt-1", Type: EventSchemaUpdated, SchemaID: "a", Origin: "other"})
+	bus.receive(&RegistryEvent{ID: "evt-2", Type: EventSchemaUpdated, SchemaID: "a", Origin: bus.NodeID()})
+	wg.Wait()
+
+	require.Equal(t, int32(1), received.Load())
+	require.True(t, event.Remote)
+}
+
+// flakyTransport fails the first publish attempts
+type flakyTransport struct {
+	*MemoryRegistryTransport
+	failures atomic.Int32
+}
+
+func (t *flakyTransport) Publish(ctx context.Context, event *RegistryEvent) error {
+	if t.failures.Add(-1) >= 0 {
+		return errors.New("connection refused")
+	}
+	return t.MemoryRegistryTransport.Publish(ctx, event)
+}
+
+func TestRegistryEventBus_RetriesPublish(t *testing.T) {
+	ctx := context.Background()
+	transport := &flakyTransport{MemoryRegistryTransport: NewMemoryRegistryTransport()}
+	transport.failures.Store(2)
+
+	delivered := make(chan *RegistryEvent, 1)
+	cancel, err := transport.Subscribe(ctx, func(event *RegistryEvent) { delivered <- event })
+	require.NoError(t, err)
+	defer cancel()
+
+	bus := NewRegistryEventBus()
+	var publishErrors atomic.Int32
+	bus.OnPublishError = func(*RegistryEvent, error) { publishErrors.Add(1) }
+	require.NoError(t, bus.Connect(ctx, transport, nil))
+	defer bus.Disconnect()
+
+	bus.Emit(&RegistryEvent{Type: EventSchemaAccessed, SchemaID: "a"})
+	bus.Emit(&RegistryEvent{Type: EventSchemaDeleted, SchemaID: "a"})
+
+	select {
+	case event := <-delivered:
+		require.Equal(t, EventSchemaDeleted, event.Type)
+	case <-time.After(2 * time.Second):
+		t.Fatal("event was not published after retries")
+	}
+	require.Equal(t, int32(2), publishErrors.Load())
+	require.Equal(t, 0, bus.Pending())
+}
+
+func TestRegistryEventBus_OutboxCap(t *testing.T) {
+	ctx := context.Background()
+	transport := &flakyTransport{MemoryRegistryTransport: NewMemoryRegistryTransport()}
+	transport.failures.Store(1 << 20)
+
+	bus := NewRegistryEventBus()
+	bus.MaxPending = 3
+	var dropped []string
+	var mu sync.Mutex
+	bus.OnDrop = func(event *RegistryEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		dropped = append(dropped, event.SchemaID)
+	}
+	require.NoError(t, bus.Connect(ctx, transport, nil))
+	defer bus.Disconnect()
+
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		bus.Emit(&RegistryEvent{Type: EventSchemaUpdated, SchemaID: id})
+	}
+
+	require.Equal(t, 3, bus.Pending())
+	require.Equal(t, int64(2), bus.Dropped())
+	mu.Lock()
+	require.Equal(t, []string{"a", "b"}, dropped, "the oldest events are dropped")
+	mu.Unlock()
+}
+
+func TestRedisRegistryTransport(t *testing.T) {
+	client, mock := redismock.NewClientMock()
+	transport, err := NewRedisRegistryTransport(client, "")
+	require.NoError(t, err)
+
+	event := &RegistryEvent{ID: "evt-1", Type: EventSchemaUpdated, SchemaID: "user.profile", Origin: "node-a", Timestamp: time.Unix(1700000000, 0).UTC()}
+	mock.ExpectPublish("schema:registry:events", []byte(`{"id":"evt-1","type":"schema.updated","schemaId":"user.profile","origin":"node-a","timestamp":"2023-11-14T22:13:20Z"}`)).SetVal(1)
+	require.NoError(t, transport.Publish(context.Background(), event))
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	decoded := transport.decode(&redis.Message{Payload: `{"id":"evt-1","type":"schema.updated","schemaId":"user.profile","origin":"node-a"}`})
+	require.Equal(t, "user.profile", decoded.SchemaID)
+	require.Equal(t, "node-a", decoded.Origin)
+
+	require.Equal(t, EventRegistryResync, transport.decode(&redis.Subscription{Kind: "subscribe"}).Type)
+	require.Nil(t, transport.decode(&redis.Message{Payload: "not json"}))
+}
@@ -0,0 +1,667 @@
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SchemaRef addresses a schema, optionally pinned to a version, and
+// optionally a field or layout block inside it: "id@version#fragment"
+type SchemaRef struct {
+	ID       string
+	Version  string
+	Fragment string
+}
+
+// ParseSchemaRef parses "id", "id@version", "id#fragment" or "id@version#fragment"
+func ParseSchemaRef(ref string) (SchemaRef, error) {
+	var parsed SchemaRef
+	rest, fragment, hasFragment := strings.Cut(ref, "#")
+	parsed.ID, parsed.Version, _ = strings.Cut(rest, "@")
+	parsed.Fragment = fragment
+	if parsed.ID == "" || (hasFragment && fragment == "") || strings.HasSuffix(rest, "@") {
+		return SchemaRef{}, fmt.Errorf("%w: %q", ErrInvalidReference, ref)
+	}
+	return parsed, nil
+}
+
+// String formats the reference
+func (r SchemaRef) String() string {
+	ref := r.ID
+	if r.Version != "" {
+		ref += "@" + r.Version
+	}
+	if r.Fragment != "" {
+		ref += "#" + r.Fragment
+	}
+	return ref
+}
+
+// key identifies the referenced schema version in the resolver cache
+func (r SchemaRef) key() string {
+	return r.ID + "@" + r.Version
+}
+
+// resolverScopeKey is the context key of the resolution scope
+type resolverScopeKey struct{}
+
+// withResolverScope returns a context in which references resolve within
+// scope, such as the environment a schema is served in. The resolver and
+// parser caches keep separate entries per scope.
+func withResolverScope(ctx context.Context, scope string) context.Context {
+	return context.WithValue(ctx, resolverScopeKey{}, scope)
+}
+
+// resolverScope returns the resolution scope of ctx, or ""
+func resolverScope(ctx context.Context) string {
+	scope, _ := ctx.Value(resolverScopeKey{}).(string)
+	return scope
+}
+
+// IsComposed reports whether the schema uses extends, mixins or $ref and
+// must be resolved before use
+func (s *Schema) IsComposed() bool {
+	if s.Extends != "" || len(s.Mixins) > 0 {
+		return true
+	}
+	for _, field := range s.Fields {
+		if field.Ref != "" {
+			return true
+		}
+	}
+	composed := false
+	s.walkLayoutBlocks(func(block *LayoutBlock) {
+		composed = composed || block.Ref != ""
+	})
+	return composed
+}
+
+// walkLayoutBlocks calls fn for every block of every layout list
+func (s *Schema) walkLayoutBlocks(fn func(*LayoutBlock)) {
+	if s.Layout == nil {
+		return
+	}
+	for _, blocks := range [][]LayoutBlock{s.Layout.Blocks, s.Layout.Sections, s.Layout.Groups, s.Layout.Tabs, s.Layout.Steps} {
+		for i := range blocks {
+			fn(&blocks[i])
+		}
+	}
+}
+
+// validateComposition checks the syntax of composition references
+func (s *Schema) validateComposition() error {
+	collector := NewErrorCollector()
+	if s.Extends != "" {
+		if _, err := ParseSchemaRef(s.Extends); err != nil {
+			collector.AddValidationError("extends", "invalid_reference", err.Error())
+		}
+	}
+	for _, use := range s.Mixins {
+		if use.ID == "" {
+			collector.AddValidationError("mixins", "invalid_reference", "mixin ID is required")
+		}
+	}
+	for _, field := range s.Fields {
+		if field.Ref == "" {
+			continue
+		}
+		if ref, err := ParseSchemaRef(field.Ref); err != nil || ref.Fragment == "" {
+			collector.AddValidationError(field.Name, "invalid_reference",
+				fmt.Sprintf("field reference must name a field: %q", field.Ref))
+		}
+	}
+	s.walkLayoutBlocks(func(block *LayoutBlock) {
+		if block.Ref == "" {
+			return
+		}
+		if ref, err := ParseSchemaRef(block.Ref); err != nil || ref.Fragment == "" {
+			collector.AddValidationError(block.ID, "invalid_reference",
+				fmt.Sprintf("layout reference must name a block: %q", block.Ref))
+		}
+	})
+	if collector.HasErrors() {
+		return collector.Errors()
+	}
+	return nil
+}
+
+// SchemaSource loads the schemas named by extends and $ref. An empty version
+// means the current one.
+type SchemaSource interface {
+	LoadSchema(ctx context.Context, id, version string) (*Schema, error)
+}
+
+// SchemaSourceFunc adapts a function to SchemaSource
+type SchemaSourceFunc func(ctx context.Context, id, version string) (*Schema, error)
+
+// LoadSchema calls f
+func (f SchemaSourceFunc) LoadSchema(ctx context.Context, id, version string) (*Schema, error) {
+	return f(ctx, id, version)
+}
+
+// SchemaResolver expands extends, mixins and $ref into a self-contained
+// schema. Referenced schemas are resolved once and cached; Invalidate drops a
+// schema and everything composed from it.
+type SchemaResolver struct {
+	source SchemaSource
+	mixins *MixinRegistry
+
+	cache      map[string]*resolvedSchema
+	dependents map[string]map[string]bool // dependency key -> dependent schema IDs
+
+	// OnInvalidate is called with the ID of every schema whose resolved form
+	// became stale, so callers can drop their own caches
+	OnInvalidate func(id string)
+
+	mu sync.RWMutex
+}
+
+// resolvedSchema is a cached resolution and the dependencies it was built from
+type resolvedSchema struct {
+	schema *Schema
+	deps   map[string]bool
+}
+
+// NewSchemaResolver creates a resolver. A nil mixin registry uses the built-in mixins.
+func NewSchemaResolver(source SchemaSource, mixins *MixinRegistry) *SchemaResolver {
+	if mixins == nil {
+		mixins = NewMixinRegistry()
+	}
+	resolver := &SchemaResolver{
+		source:     source,
+		mixins:     mixins,
+		cache:      make(map[string]*resolvedSchema),
+		dependents: make(map[string]map[string]bool),
+	}
+	mixins.OnChange(resolver.InvalidateMixin)
+	return resolver
+}
+
+// Mixins returns the mixin registry used for MixinUse entries
+func (r *SchemaResolver) Mixins() *MixinRegistry {
+	return r.mixins
+}
+
+// Resolve returns a copy of schema with its base, mixins and references
+// expanded. Schemas without composition are returned unchanged.
+func (r *SchemaResolver) Resolve(ctx context.Context, schema *Schema) (*Schema, error) {
+	if !schema.IsComposed() {
+		return schema, nil
+	}
+	res := &resolution{resolver: r, stack: []string{schema.ID}, deps: make(map[string]bool)}
+	resolved, err := res.resolve(ctx, schema)
+	if err != nil {
+		return nil, err
+	}
+	r.track(schema.ID, res.deps)
+	return resolved, nil
+}
+
+// Invalidate drops cached resolutions of a schema and of every schema
+// composed from it, and reports each affected ID to OnInvalidate
+func (r *SchemaResolver) Invalidate(id string) {
+	r.invalidate(schemaDependency(id), id)
+}
+
+// InvalidateMixin drops cached resolutions of every schema using a mixin
+func (r *SchemaResolver) InvalidateMixin(id string) {
+	r.invalidate(mixinDependency(id), "")
+}
+
+// Clear drops all cached resolutions
+func (r *SchemaResolver) Clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cache = make(map[string]*resolvedSchema)
+	r.dependents = make(map[string]map[string]bool)
+}
+
+// Dependents returns the IDs of schemas composed from the given schema
+func (r *SchemaResolver) Dependents(id string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ids := make([]string, 0, len(r.dependents[schemaDependency(id)]))
+	for dependent := range r.dependents[schemaDependency(id)] {
+		ids = append(ids, dependent)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func (r *SchemaResolver) invalidate(dependency, id string) {
+	r.mu.Lock()
+	affected := make(map[string]bool)
+	if id != "" {
+		affected[id] = true
+	}
+	for dependent := range r.dependents[dependency] {
+		affected[dependent] = true
+	}
+	delete(r.dependents, dependency)
+	for key, entry := range r.cache {
+		id, _, _ := strings.Cut(key, "@")
+		if affected[id] || entry.deps[dependency] {
+			delete(r.cache, key)
+		}
+	}
+	r.mu.Unlock()
+
+	if r.OnInvalidate != nil {
+		for id := range affected {
+			r.OnInvalidate(id)
+		}
+	}
+}
+
+// track records that id was resolved from deps
+func (r *SchemaResolver) track(id string, deps map[string]bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for dep := range deps {
+		if r.dependents[dep] == nil {
+			r.dependents[dep] = make(map[string]bool)
+		}
+		r.dependents[dep][id] = true
+	}
+}
+
+func schemaDependency(id string) string { return "schema:" + id }
+func mixinDependency(id string) string  { return "mixin:" + id }
+
+// resolution is one Resolve call. stack holds the schemas being resolved to
+// detect cycles; deps collects every schema and mixin used, transitively.
+type resolution struct {
+	resolver *SchemaResolver
+	stack    []string
+	deps     map[string]bool
+}
+
+func (res *resolution) resolve(ctx context.Context, schema *Schema) (*Schema, error) {
+	if err := schema.validateComposition(); err != nil {
+		return nil, err
+	}
+	out, err := schema.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	if out.Extends != "" {
+		ref, _ := ParseSchemaRef(out.Extends)
+		base, err := res.load(ctx, ref)
+		if err != nil {
+			return nil, fmt.Errorf("extend %s: %w", ref, err)
+		}
+		if out, err = extendSchema(base, out); err != nil {
+			return nil, fmt.Errorf("extend %s: %w", ref, err)
+		}
+	}
+
+	for _, use := range out.Mixins {
+		res.deps[mixinDependency(use.ID)] = true
+		if err := res.resolver.mixins.ApplyMixinWithParams(out, use.ID, use.Params); err != nil {
+			return nil, fmt.Errorf("apply mixin %s: %w", use.ID, err)
+		}
+	}
+
+	for i := range out.Fields {
+		if out.Fields[i].Ref == "" {
+			continue
+		}
+		if err := res.resolveField(ctx, &out.Fields[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	var blockErr error
+	out.walkLayoutBlocks(func(block *LayoutBlock) {
+		if block.Ref != "" && blockErr == nil {
+			blockErr = res.resolveBlock(ctx, out, block)
+		}
+	})
+	if blockErr != nil {
+		return nil, blockErr
+	}
+
+	out.Extends = ""
+	out.Mixins = nil
+	out.buildIndexes()
+	return out, nil
+}
+
+// load returns the resolved form of a referenced schema
+func (res *resolution) load(ctx context.Context, ref SchemaRef) (*Schema, error) {
+	if slices.Contains(res.stack, ref.ID) {
+		chain := append(slices.Clone(res.stack), ref.ID)
+		return nil, fmt.Errorf("%w: %s", ErrCircularReference, strings.Join(chain, " -> "))
+	}
+	res.deps[schemaDependency(ref.ID)] = true
+
+	r := res.resolver
+	key := ref.key()
+	if scope := resolverScope(ctx); scope != "" {
+		key += "|" + scope
+	}
+	r.mu.RLock()
+	cached, ok := r.cache[key]
+	r.mu.RUnlock()
+	if ok {
+		for dep := range cached.deps {
+			res.deps[dep] = true
+		}
+		return cached.schema, nil
+	}
+
+	if r.source == nil {
+		return nil, fmt.Errorf("%w: no schema source to load %s", ErrInvalidReference, ref)
+	}
+	raw, err := r.source.LoadSchema(ctx, ref.ID, ref.Version)
+	if err != nil {
+		return nil, fmt.Errorf("load %s: %w", ref, err)
+	}
+
+	sub := &resolution{resolver: r, stack: append(slices.Clone(res.stack), ref.ID), deps: make(map[string]bool)}
+	resolved := raw
+	if raw.IsComposed() {
+		if resolved, err = sub.resolve(ctx, raw); err != nil {
+			return nil, err
+		}
+	}
+
+	r.mu.Lock()
+	r.cache[key] = &resolvedSchema{schema: resolved, deps: sub.deps}
+	r.mu.Unlock()
+	r.track(ref.ID, sub.deps)
+
+	for dep := range sub.deps {
+		res.deps[dep] = true
+	}
+	return resolved, nil
+}
+
+// resolveField replaces a $ref field with the referenced field, keeping
+// values set on the referencing field
+func (res *resolution) resolveField(ctx context.Context, field *Field) error {
+	ref, _ := ParseSchemaRef(field.Ref)
+	source, err := res.load(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("field %s: %w", field.Name, err)
+	}
+	target, ok := source.GetField(ref.Fragment)
+	if !ok {
+		return fmt.Errorf("field %s: %w: %s has no field %s", field.Name, ErrInvalidReference, ref.ID, ref.Fragment)
+	}
+	var merged Field
+	if err := overlay(target, field, &merged); err != nil {
+		return fmt.Errorf("field %s: %w", field.Name, err)
+	}
+	*field = merged
+	return nil
+}
+
+// resolveBlock replaces a $ref layout block with the referenced block and
+// adds the fields it lists that the schema does not have yet
+func (res *resolution) resolveBlock(ctx context.Context, schema *Schema, block *LayoutBlock) error {
+	ref, _ := ParseSchemaRef(block.Ref)
+	source, err := res.load(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("layout block %s: %w", block.ID, err)
+	}
+	var target *LayoutBlock
+	source.walkLayoutBlocks(func(candidate *LayoutBlock) {
+		if target == nil && candidate.ID == ref.Fragment {
+			target = candidate
+		}
+	})
+	if target == nil {
+		return fmt.Errorf("layout block %s: %w: %s has no block %s", block.ID, ErrInvalidReference, ref.ID, ref.Fragment)
+	}
+
+	var merged LayoutBlock
+	if err := overlay(target, block, &merged); err != nil {
+		return fmt.Errorf("layout block %s: %w", block.ID, err)
+	}
+	*block = merged
+
+	if source, err = source.Clone(); err != nil {
+		return err
+	}
+	for _, name := range block.Fields {
+		if schema.HasField(name) {
+			continue
+		}
+		field, ok := source.GetField(name)
+		if !ok {
+			return fmt.Errorf("layout block %s: %w: %s has no field %s", block.ID, ErrInvalidReference, ref.ID, name)
+		}
+		schema.AddField(*field)
+	}
+	return nil
+}
+
+// extendSchema merges child over base. Child fields and actions override
+// base entries with the same name or ID; new ones are appended.
+func extendSchema(base, child *Schema) (*Schema, error) {
+	// Copy base so the cached resolution is never shared
+	base, err := base.Clone()
+	if err != nil {
+		return nil, err
+	}
+	var out Schema
+	if err := overlay(base, child, &out); err != nil {
+		return nil, err
+	}
+	out.evaluator = child.evaluator
+
+	out.Fields = base.Fields
+	for _, field := range child.Fields {
+		i := slices.IndexFunc(out.Fields, func(f Field) bool { return f.Name == field.Name })
+		if i < 0 {
+			out.Fields = append(out.Fields, field)
+			continue
+		}
+		var merged Field
+		if err := overlay(out.Fields[i], field, &merged); err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		out.Fields[i] = merged
+	}
+
+	out.Actions = base.Actions
+	for _, action := range child.Actions {
+		i := slices.IndexFunc(out.Actions, func(a Action) bool { return a.ID == action.ID })
+		if i < 0 {
+			out.Actions = append(out.Actions, action)
+			continue
+		}
+		var merged Action
+		if err := overlay(out.Actions[i], action, &merged); err != nil {
+			return nil, fmt.Errorf("action %s: %w", action.ID, err)
+		}
+		out.Actions[i] = merged
+	}
+
+	for _, tag := range base.Tags {
+		if !slices.Contains(out.Tags, tag) {
+			out.Tags = append(out.Tags, tag)
+		}
+	}
+	out.buildIndexes()
+	return &out, nil
+}
+
+// overlay decodes base into target, then applies every value present on
+// override, including explicit false, 0 and "". Keys the override's type
+// always encodes, such as a field's label, keep the base value when zero,
+// since their presence says nothing. Nested objects are merged key by key;
+// lists replace.
+func overlay(base, override, target any) error {
+	baseMap, err := toJSONMap(base)
+	if err != nil {
+		return err
+	}
+	overrideMap, err := toJSONMap(override)
+	if err != nil {
+		return err
+	}
+	delete(overrideMap, "$ref")
+	mergeJSONMaps(baseMap, overrideMap, reflect.TypeOf(target))
+	delete(baseMap, "$ref")
+
+	data, err := json.Marshal(baseMap)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
+func toJSONMap(v any) (map[string]any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]any)
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// mergeJSONMaps merges src into dst by key presence. t is the Go type the
+// maps encode, used to recognize keys that are encoded even when unset.
+func mergeJSONMaps(dst, src map[string]any, t reflect.Type) {
+	keys := jsonKeys(t)
+	for key, value := range src {
+		if value == nil {
+			continue
+		}
+		info, known := keys[key]
+		if known && !info.omitempty && isZeroJSON(value) {
+			continue
+		}
+		if nested, ok := value.(map[string]any); ok {
+			if existing, ok := dst[key].(map[string]any); ok {
+				mergeJSONMaps(existing, nested, info.typ)
+				continue
+			}
+		}
+		dst[key] = value
+	}
+}
+
+func isZeroJSON(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	case float64:
+		return v == 0
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	}
+	return false
+}
+
+// Mixins returns the registry of mixins schemas can apply
+func (r *Registry) Mixins() *MixinRegistry {
+	return r.resolver.Mixins()
+}
+
+// Resolve expands extends, mixins and $ref of a schema against this registry
+func (r *Registry) Resolve(ctx context.Context, schema *Schema) (*Schema, error) {
+	return r.resolver.Resolve(ctx, schema)
+}
+
+// validateForStore validates the resolved form of a schema
+func (r *Registry) validateForStore(ctx context.Context, schema *Schema) error {
+	resolved, err := r.resolver.Resolve(ctx, schema)
+	if err != nil {
+		return fmt.Errorf("schema resolution failed: %w", err)
+	}
+	if err := resolved.Validate(ctx); err != nil {
+		return fmt.Errorf("schema validation failed: %w", err)
+	}
+	return nil
+}
+
+// registrySource loads referenced schemas from registry storage without
+// taking the registry lock, so it can be used while resolving inside Get.
+// A version matches the schema's Version, a stored version or, when the
+// lifecycle is enabled, a revision number. With the lifecycle enabled, an
+// unpinned reference resolved for an environment loads the revision deployed
+// there; outside an environment, such as when validating a draft for
+// storage, it loads the latest stored schema.
+type registrySource struct {
+	r *Registry
+}
+
+func (s registrySource) LoadSchema(ctx context.Context, id, version string) (*Schema, error) {
+	if scope := resolverScope(ctx); version == "" && scope != "" && s.r.config.EnableLifecycle {
+		if schema, err := s.loadDeployed(ctx, id, Environment(scope)); schema != nil || err != nil {
+			return schema, err
+		}
+	}
+
+	data, err := s.r.storage.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("storage get: %w", err)
+	}
+	schema, err := decodeSchema(data)
+	if err != nil || version == "" || schema.Version == version {
+		return schema, err
+	}
+
+	if s.r.config.EnableLifecycle {
+		if revision, err := strconv.Atoi(version); err == nil {
+			lifecycle, err := s.r.loadLifecycle(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			if rev, ok := lifecycle.Revision(revision); ok {
+				return decodeSchema(rev.Data)
+			}
+		}
+	}
+
+	data, err = s.r.storage.GetVersion(ctx, id, version)
+	if err != nil {
+		return nil, fmt.Errorf("version %s of schema %s not found: %w", version, id, err)
+	}
+	return decodeSchema(data)
+}
+
+// loadDeployed returns the revision of a schema deployed to env, or nil when
+// the schema has no lifecycle
+func (s registrySource) loadDeployed(ctx context.Context, id string, env Environment) (*Schema, error) {
+	lifecycle, err := s.r.loadLifecycle(ctx, id)
+	if err != nil || lifecycle == nil {
+		return nil, err
+	}
+	rev, ok := lifecycle.Deployed(env)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s in %s", ErrSchemaNotPublished, id, env)
+	}
+	if rev.State == LifecycleArchived {
+		return nil, fmt.Errorf("%w: %s revision %d", ErrSchemaArchived, id, rev.Revision)
+	}
+	return decodeSchema(rev.Data)
+}
+
+func decodeSchema(data []byte) (*Schema, error) {
+	var schema Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("unmarshal schema: %w", err)
+	}
+	schema.buildIndexes()
+	return &schema, nil
+}
@@ -0,0 +1,295 @@
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newResolverTestRegistry(t *testing.T) *Registry {
+	t.Helper()
+	config := DefaultRegistryConfig()
+	config.ValidateOnStore = false
+	config.ValidateOnLoad = false
+	registry, err := NewRegistry(config)
+	require.NoError(t, err)
+	return registry
+}
+
+func TestParseSchemaRef(t *testing.T) {
+	ref, err := ParseSchemaRef("user.base@1.1.0#email")
+	require.NoError(t, err)
+	require.Equal(t, SchemaRef{ID: "user.base", Version: "1.1.0", Fragment: "email"}, ref)
+	require.Equal(t, "user.base@1.1.0#email", ref.String())
+
+	ref, err = ParseSchemaRef("user.base")
+	require.NoError(t, err)
+	require.Equal(t, SchemaRef{ID: "user.base"}, ref)
+
+	for _, invalid := range []string{"", "@1.0.0", "user.base@", "user.base#"} {
+		_, err := ParseSchemaRef(invalid)
+		require.True(t, errors.Is(err, ErrInvalidReference), invalid)
+	}
+}
+
+func TestMixin_Instantiate(t *testing.T) {
+	mixin := &Mixin{
+		ID:   "money",
+		Name: "Money",
+		Params: []MixinParam{
+			{Name: "currency", Default: "USD"},
+			{Name: "label", Required: true},
+		},
+		Fields: []Field{
+			{Name: "amount", Type: FieldCurrency, Label: "${label} (${currency})", Default: "${currency}"},
+		},
+	}
+
+	_, err := mixin.Instantiate(nil)
+	require.Error(t, err)
+	_, err = mixin.Instantiate(map[string]any{"label": "Price", "unknown": 1})
+	require.Error(t, err)
+
+	instance, err := mixin.Instantiate(map[string]any{"label": "Price", "required": true})
+	require.NoError(t, err)
+	require.Equal(t, "Price (USD)", instance.Fields[0].Label)
+	require.Equal(t, "USD", instance.Fields[0].Default)
+	require.True(t, instance.Fields[0].Required)
+	require.Equal(t, "${label} (${currency})", mixin.Fields[0].Label)
+}
+
+func TestRegistry_ResolveComposition(t *testing.T) {
+	ctx := context.Background()
+	registry := newResolverTestRegistry(t)
+
+	require.NoError(t, registry.Register(ctx, &Schema{
+		ID: "contact.base", Type: TypeForm, Version: "1.0.0", Title: "Contact",
+		Fields: []Field{
+			{Name: "email", Type: FieldEmail, Label: "Email", Required: true},
+			{Name: "phone", Type: FieldPhone, Label: "Phone"},
+		},
+		Actions: []Action{{ID: "save", Type: ActionSubmit, Text: "Save"}},
+		Layout: &Layout{Type: LayoutSections, Sections: []Section{
+			{ID: "reach", Type: BlockTypeSection, Title: "Reach", Fields: []string{"email", "phone"}},
+		}},
+	}))
+	require.NoError(t, registry.Register(ctx, &Schema{
+		ID: "customer", Type: TypeForm, Title: "Customer", Extends: "contact.base",
+		Fields: []Field{
+			{Name: "phone", Label: "Mobile"},
+			{Name: "name", Type: FieldText, Label: "Name"},
+		},
+		Mixins: []MixinUse{{ID: "audit_fields", Params: map[string]any{"prefix": "meta"}}},
+	}))
+	require.NoError(t, registry.Register(ctx, &Schema{
+		ID: "supplier", Type: TypeForm, Title: "Supplier",
+		Fields: []Field{
+			{Name: "billing_email", Ref: "customer#email", Label: "Billing email"},
+		},
+		Layout: &Layout{Type: LayoutSections, Sections: []Section{
+			{ID: "contact", Ref: "contact.base@1.0.0#reach", Title: "Contact"},
+		}},
+	}))
+
+	customer, err := registry.Get(ctx, "customer")
+	require.NoError(t, err)
+	require.Equal(t, "Customer", customer.Title)
+	phone, ok := customer.GetField("phone")
+	require.True(t, ok)
+	require.Equal(t, FieldPhone, phone.Type)
+	require.Equal(t, "Mobile", phone.Label)
+	require.True(t, customer.HasField("name"))
+	require.True(t, customer.HasField("meta_created_at"))
+	require.Len(t, customer.Actions, 1)
+	require.Empty(t, customer.Extends)
+
+	supplier, err := registry.Get(ctx, "supplier")
+	require.NoError(t, err)
+	billing, ok := supplier.GetField("billing_email")
+	require.True(t, ok)
+	require.Equal(t, FieldEmail, billing.Type)
+	require.Equal(t, "Billing email", billing.Label)
+	require.True(t, billing.Required)
+	require.Equal(t, "Contact", supplier.Layout.Sections[0].Title)
+	require.Equal(t, []string{"email", "phone"}, supplier.Layout.Sections[0].Fields)
+	require.True(t, supplier.HasField("email"))
+	require.True(t, supplier.HasField("phone"))
+
+	require.Equal(t, []string{"customer", "supplier"}, registry.resolver.Dependents("contact.base"))
+
+	// Changing the base re-resolves everything composed from it
+	require.NoError(t, registry.Update(ctx, &Schema{
+		ID: "contact.base", Type: TypeForm, Version: "1.0.0", Title: "Contact",
+		Fields: []Field{
+			{Name: "email", Type: FieldEmail, Label: "E-mail", Required: true},
+			{Name: "phone", Type: FieldPhone, Label: "Phone"},
+		},
+		Layout: &Layout{Type: LayoutSections, Sections: []Section{
+			{ID: "reach", Type: BlockTypeSection, Title: "Reach", Fields: []string{"email"}},
+		}},
+	}))
+	customer, err = registry.Get(ctx, "customer")
+	require.NoError(t, err)
+	email, _ := customer.GetField("email")
+	require.Equal(t, "E-mail", email.Label)
+	supplier, err = registry.Get(ctx, "supplier")
+	require.NoError(t, err)
+	require.Equal(t, []string{"email"}, supplier.Layout.Sections[0].Fields)
+	require.False(t, supplier.HasField("phone"))
+}
+
+func TestRegistry_ResolveCycle(t *testing.T) {
+	ctx := context.Background()
+	registry := newResolverTestRegistry(t)
+
+	require.NoError(t, registry.Register(ctx, &Schema{ID: "a", Type: TypeForm, Title: "A", Extends: "b"}))
+	require.NoError(t, registry.Register(ctx, &Schema{ID: "b", Type: TypeForm, Title: "B", Extends: "c"}))
+	require.NoError(t, registry.Register(ctx, &Schema{ID: "c", Type: TypeForm, Title: "C", Extends: "a"}))
+
+	_, err := registry.Get(ctx, "a")
+	require.True(t, errors.Is(err, ErrCircularReference))
+}
+
+func TestRegistry_ResolveMixinChange(t *testing.T) {
+	ctx := context.Background()
+	registry := newResolverTestRegistry(t)
+	require.NoError(t, registry.Mixins().Register(&Mixin{
+		ID: "notes", Name: "Notes",
+		Fields: []Field{{Name: "notes", Type: FieldTextarea, Label: "Notes"}},
+	}))
+	require.NoError(t, registry.Register(ctx, &Schema{
+		ID: "ticket", Type: TypeForm, Title: "Ticket",
+		Mixins: []MixinUse{{ID: "notes", Params: map[string]any{"required": true}}},
+	}))
+
+	ticket, err := registry.Get(ctx, "ticket")
+	require.NoError(t, err)
+	notes, ok := ticket.GetField("notes")
+	require.True(t, ok)
+	require.True(t, notes.Required)
+
+	require.NoError(t, registry.Mixins().Update(&Mixin{
+		ID: "notes", Name: "Notes",
+		Fields: []Field{{Name: "notes", Type: FieldTextarea, Label: "Comments"}},
+	}))
+	ticket, err = registry.Get(ctx, "ticket")
+	require.NoError(t, err)
+	notes, _ = ticket.GetField("notes")
+	require.Equal(t, "Comments", notes.Label)
+}
+
+func TestRegistry_ResolveExplicitZeroOverrides(t *testing.T) {
+	ctx := context.Background()
+	registry := newResolverTestRegistry(t)
+
+	require.NoError(t, registry.Register(ctx, &Schema{
+		ID: "person.base", Type: TypeForm, Title: "Person",
+		Fields: []Field{
+			{Name: "email", Type: FieldEmail, Label: "Email", Required: true},
+			{Name: "nickname", Type: FieldText, Label: "Nickname", Hidden: true},
+			{Name: "bio", Type: FieldTextarea, Label: "Bio", Validation: &FieldValidation{MinLength: intPtr(5)}},
+		},
+	}))
+
+	for _, tc := range []struct {
+		name  string
+		field string
+		check func(t *testing.T, field *Field)
+	}{
+		{"required false", `{"name": "email", "required": false}`, func(t *testing.T, field *Field) {
+			require.False(t, field.Required)
+		}},
+		{"hidden false", `{"name": "nickname", "hidden": false}`, func(t *testing.T, field *Field) {
+			require.False(t, field.Hidden)
+		}},
+		{"minLength zero", `{"name": "bio", "validation": {"minLength": 0}}`, func(t *testing.T, field *Field) {
+			require.NotNil(t, field.Validation)
+			require.NotNil(t, field.Validation.MinLength)
+			require.Equal(t, 0, *field.Validation.MinLength)
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var child Schema
+			require.NoError(t, json.Unmarshal([]byte(`{"id": "person", "type": "form", "title": "Person",
+				"extends": "person.base", "fields": [`+tc.field+`]}`), &child))
+			require.NoError(t, registry.Register(ctx, &child))
+			t.Cleanup(func() { require.NoError(t, registry.Delete(ctx, "person")) })
+
+			person, err := registry.Get(ctx, "person")
+			require.NoError(t, err)
+			var name struct {
+				Name string `json:"name"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(tc.field), &name))
+			field, ok := person.GetField(name.Name)
+			require.True(t, ok)
+			// Unset keys keep the base value
+			require.NotEmpty(t, field.Type)
+			require.NotEmpty(t, field.Label)
+			tc.check(t, field)
+		})
+	}
+
+	// The same applies to $ref fields
+	var ref Schema
+	require.NoError(t, json.Unmarshal([]byte(`{"id": "contact", "type": "form", "title": "Contact",
+		"fields": [{"name": "email", "$ref": "person.base#email", "required": false}]}`), &ref))
+	require.NoError(t, registry.Register(ctx, &ref))
+	contact, err := registry.Get(ctx, "contact")
+	require.NoError(t, err)
+	email, ok := contact.GetField("email")
+	require.True(t, ok)
+	require.Equal(t, FieldEmail, email.Type)
+	require.False(t, email.Required)
+}
+
+func TestRegistry_ResolveDeployedBase(t *testing.T) {
+	ctx := context.Background()
+	registry := newLifecycleTestRegistry(t)
+	publish := func(id string, revision int, envs ...Environment) {
+		t.Helper()
+		require.NoError(t, registry.SubmitForReview(ctx, id, revision, ""))
+		require.NoError(t, registry.Publish(ctx, id, revision, ""))
+		for _, env := range envs {
+			require.NoError(t, registry.Deploy(ctx, id, revision, env))
+		}
+	}
+
+	base := func(label string) *Schema {
+		return &Schema{ID: "contact.base", Type: TypeForm, Title: "Contact", Fields: []Field{
+			{Name: "email", Type: FieldEmail, Label: label},
+		}}
+	}
+	require.NoError(t, registry.Register(ctx, base("Email")))
+	publish("contact.base", 1, EnvironmentDev, EnvironmentProd)
+	require.NoError(t, registry.Register(ctx, &Schema{ID: "customer", Type: TypeForm, Title: "Customer", Extends: "contact.base"}))
+	publish("customer", 1, EnvironmentDev, EnvironmentProd)
+
+	emailLabel := func(env string) string {
+		t.Helper()
+		customer, err := registry.Get(ctx, "customer", WithEnvironment(env))
+		require.NoError(t, err)
+		email, ok := customer.GetField("email")
+		require.True(t, ok)
+		return email.Label
+	}
+	require.Equal(t, "Email", emailLabel("prod"))
+
+	// An undeployed draft of the base is not picked up
+	require.NoError(t, registry.Update(ctx, base("E-mail address")))
+	require.Equal(t, "Email", emailLabel("prod"))
+	require.Equal(t, "Email", emailLabel("dev"))
+
+	// Deploying the base to dev changes dev only
+	publish("contact.base", 2, EnvironmentDev)
+	require.Equal(t, "E-mail address", emailLabel("dev"))
+	require.Equal(t, "Email", emailLabel("prod"))
+
+	// A child cannot be served where its base is not deployed
+	require.NoError(t, registry.Deploy(ctx, "customer", 1, EnvironmentStaging))
+	_, err := registry.Get(ctx, "customer", WithEnvironment("staging"))
+	require.True(t, errors.Is(err, ErrSchemaNotPublished))
+}
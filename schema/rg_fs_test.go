@@ -11,6 +11,8 @@ import (
 	"time"
 )
 
+var _ StorageBackend = (*FilesystemStorage)(nil)
+
 func TestNewFilesystemStorage(t *testing.T) {
 	tempDir := t.TempDir()
 	tests := []struct {
@@ -204,7 +206,7 @@ func TestFilesystemStorage_List(t *testing.T) {
 		}
 	}
 	// List all schemas
-	schemaIDs, err := storage.List(ctx)
+	schemaIDs, err := storage.List(ctx, nil)
 	if err != nil {
 		t.Errorf("List() error = %v", err)
 		return
@@ -224,6 +226,107 @@ func TestFilesystemStorage_List(t *testing.T) {
 		}
 	}
 }
+func TestFilesystemStorage_ListFilter(t *testing.T) {
+	storage, _ := setupFilesystemTest(t)
+	ctx := context.Background()
+	testSchemas := map[string][]byte{
+		"user.form":    []byte(`{"id": "user.form", "type": "form", "module": "crm", "tags": ["public"]}`),
+		"user.table":   []byte(`{"id": "user.table", "type": "table", "module": "crm", "tags": ["public"]}`),
+		"invoice.form": []byte(`{"id": "invoice.form", "type": "form", "module": "billing"}`),
+	}
+	if err := storage.SetBatch(ctx, testSchemas); err != nil {
+		t.Fatalf("SetBatch() error = %v", err)
+	}
+	tests := []struct {
+		name   string
+		filter *StorageFilter
+		want   string
+	}{
+		{name: "by type", filter: &StorageFilter{Type: "form"}, want: "[invoice.form user.form]"},
+		{name: "by module and tag", filter: &StorageFilter{Module: "crm", Tags: []string{"public"}}, want: "[user.form user.table]"},
+		{name: "paged", filter: &StorageFilter{Offset: 1, Limit: 1}, want: "[user.form]"},
+		{name: "no match", filter: &StorageFilter{Category: "reports"}, want: "[]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schemaIDs, err := storage.List(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			if got := fmt.Sprint(schemaIDs); got != tt.want {
+				t.Errorf("List() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+func TestFilesystemStorage_Versions(t *testing.T) {
+	storage, _ := setupFilesystemTest(t)
+	ctx := context.Background()
+	for i := 1; i <= 3; i++ {
+		data := []byte(fmt.Sprintf(`{"id": "user.profile", "revision": %d}`, i))
+		if err := storage.Set(ctx, "user.profile", data); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+	}
+	versions, err := storage.ListVersions(ctx, "user.profile")
+	if err != nil {
+		t.Fatalf("ListVersions() error = %v", err)
+	}
+	if len(versions) != 3 {
+		t.Fatalf("ListVersions() returned %d versions, want 3", len(versions))
+	}
+	data, err := storage.GetVersion(ctx, "user.profile", versions[0])
+	if err != nil {
+		t.Fatalf("GetVersion() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"revision": 1`) {
+		t.Errorf("GetVersion() = %s, want first revision", data)
+	}
+	meta, err := storage.GetMetadata(ctx, "user.profile")
+	if err != nil {
+		t.Fatalf("GetMetadata() error = %v", err)
+	}
+	if meta.Version != versions[2] {
+		t.Errorf("GetMetadata() version = %s, want %s", meta.Version, versions[2])
+	}
+	// Version history is not listed as schemas
+	schemaIDs, err := storage.List(ctx, nil)
+	if err != nil || len(schemaIDs) != 1 {
+		t.Errorf("List() = %v, %v, want only user.profile", schemaIDs, err)
+	}
+	if err := storage.Delete(ctx, "user.profile"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	// Delete tombstones the schema and keeps its history
+	if exists, _ := storage.Exists(ctx, "user.profile"); exists {
+		t.Error("Exists() = true after Delete()")
+	}
+	if _, err := storage.GetMetadata(ctx, "user.profile"); err == nil {
+		t.Error("GetMetadata() should return error after Delete()")
+	}
+	if schemaIDs, _ := storage.List(ctx, nil); len(schemaIDs) != 0 {
+		t.Errorf("List() = %v after Delete(), want none", schemaIDs)
+	}
+	if kept, _ := storage.ListVersions(ctx, "user.profile"); len(kept) != 3 {
+		t.Errorf("ListVersions() after Delete() = %v, want the 3 versions", kept)
+	}
+	if _, err := storage.GetVersion(ctx, "user.profile", versions[0]); err != nil {
+		t.Errorf("GetVersion() after Delete() error = %v", err)
+	}
+	if err := storage.Delete(ctx, "user.profile"); err == nil {
+		t.Error("Delete() of a deleted schema should return error")
+	}
+	// Recreating the schema clears the tombstone and extends the history
+	if err := storage.Set(ctx, "user.profile", []byte(`{"id": "user.profile", "revision": 4}`)); err != nil {
+		t.Fatalf("Set() after Delete() error = %v", err)
+	}
+	if _, err := storage.GetMetadata(ctx, "user.profile"); err != nil {
+		t.Errorf("GetMetadata() after recreate error = %v", err)
+	}
+	if kept, _ := storage.ListVersions(ctx, "user.profile"); len(kept) != 4 {
+		t.Errorf("ListVersions() after recreate = %v, want 4 versions", kept)
+	}
+}
 func TestFilesystemStorage_Exists(t *testing.T) {
 	storage, _ := setupFilesystemTest(t)
 	ctx := context.Background()
@@ -484,7 +587,7 @@ func TestFilesystemStorage_ConcurrentAccess(t *testing.T) {
 		t.Errorf("Concurrent operation failed: %v", err)
 	}
 	// Verify final state
-	schemaIDs, err := storage.List(ctx)
+	schemaIDs, err := storage.List(ctx, nil)
 	if err != nil {
 		t.Errorf("Failed to list schemas after concurrent operations: %v", err)
 	}
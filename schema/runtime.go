@@ -4,8 +4,11 @@ package schema
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sync"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // Runtime executes schema with state management
@@ -18,6 +21,8 @@ type Runtime struct {
 	validator   Validator
 	renderer    Renderer
 	conditional ConditionalEngine
+	encryptor   *FieldEncryptor
+	observers   []RuntimeObserver
 
 	// Configuration
 	config *RuntimeConfig
@@ -97,6 +102,24 @@ func (s *State) GetAllValues() map[string]any {
 	return values
 }
 
+// replaceValues swaps every value at once, leaving dirty flags as they are
+func (s *State) replaceValues(values map[string]any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values = make(map[string]any, len(values))
+	for k, v := range values {
+		s.values[k] = v
+	}
+}
+
+// initialValue returns the value a field started with
+func (s *State) initialValue(name string) (any, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	val, ok := s.initial[name]
+	return val, ok
+}
+
 // GetErrors gets field errors
 func (s *State) GetErrors(name string) []string {
 	s.mu.RLock()
@@ -213,6 +236,8 @@ type RuntimeBuilder struct {
 	validator   Validator
 	renderer    Renderer
 	conditional ConditionalEngine
+	encryptor   *FieldEncryptor
+	observers   []RuntimeObserver
 	initialData map[string]any
 }
 
@@ -245,6 +270,18 @@ func (b *RuntimeBuilder) WithConditionalEngine(engine ConditionalEngine) *Runtim
 	return b
 }
 
+// WithFieldEncryptor encrypts the schema's sensitive fields on submit
+func (b *RuntimeBuilder) WithFieldEncryptor(encryptor *FieldEncryptor) *RuntimeBuilder {
+	b.encryptor = encryptor
+	return b
+}
+
+// WithObserver notifies observer of submissions and workflow transitions
+func (b *RuntimeBuilder) WithObserver(observer RuntimeObserver) *RuntimeBuilder {
+	b.observers = append(b.observers, observer)
+	return b
+}
+
 func (b *RuntimeBuilder) WithInitialData(data map[string]any) *RuntimeBuilder {
 	for k, v := range data {
 		b.initialData[k] = v
@@ -270,6 +307,8 @@ func (b *RuntimeBuilder) Build() (*Runtime, error) {
 		validator:   b.validator,
 		renderer:    b.renderer,
 		conditional: b.conditional,
+		encryptor:   b.encryptor,
+		observers:   b.observers,
 		config:      b.config,
 		createdAt:   time.Now(),
 		updatedAt:   time.Now(),
@@ -339,11 +378,18 @@ func (r *Runtime) HandleBlur(ctx context.Context, field string) error {
 	return nil
 }
 
-// HandleSubmit handles form submission
+// HandleSubmit handles form submission. Once the form validates, fields
+// the schema marks for encryption are encrypted in the state with the
+// tenant key of the user attached by ContextWithUser, so the values read
+// afterwards never hold plaintext. Encrypted and masked values are not
+// validated, since their rules apply to the plaintext. A schema that
+// requires encryption fails to submit without a FieldEncryptor. Observers
+// are notified of the submitted values.
 func (r *Runtime) HandleSubmit(ctx context.Context) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	r.restoreMaskedValues()
 	if r.config.ValidateOnSubmit && r.validator != nil {
 		if err := r.validateAll(ctx); err != nil {
 			return err
@@ -354,9 +400,92 @@ func (r *Runtime) HandleSubmit(ctx context.Context) error {
 		return fmt.Errorf("form has validation errors")
 	}
 
+	if err := r.encryptState(ctx); err != nil {
+		return err
+	}
+	for _, observer := range r.observers {
+		observer.OnSubmitted(ctx, r.schema, r.state.GetAllValues())
+	}
 	return nil
 }
 
+// ApplyWorkflowAction applies an available action of the schema's workflow
+// for the user attached by ContextWithUser, moving the workflow to the
+// action's stage and status and appending the transition to its history.
+// Actions with permissions need a user holding one of them. Observers are
+// notified of the history entry, which is returned.
+func (r *Runtime) ApplyWorkflowAction(ctx context.Context, actionID, note string) (*WorkflowHistoryEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	workflow := r.schema.Workflow
+	if !workflow.IsWorkflowEnabled() {
+		return nil, fmt.Errorf("%w: %s", ErrWorkflowNotFound, r.schema.ID)
+	}
+	var action *WorkflowAction
+	for _, available := range workflow.GetAvailableActions(ctx, r.state.GetAllValues(), r.schema.GetEvaluator()) {
+		if available.ID == actionID {
+			action = &available
+			break
+		}
+	}
+	if action == nil {
+		return nil, fmt.Errorf("%w: action %s is not available in stage %s", ErrInvalidTransition, actionID, workflow.Stage)
+	}
+
+	user, hasUser := UserFromContext(ctx)
+	if len(action.Permissions) > 0 {
+		allowed := false
+		for _, permission := range action.Permissions {
+			if hasUser && user.HasPermission(permission) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return nil, fmt.Errorf("%w: workflow action %s", ErrPermissionDenied, actionID)
+		}
+	}
+	if action.RequireNote && note == "" {
+		return nil, fmt.Errorf("%w: workflow action %s requires a note", ErrRequiredField, actionID)
+	}
+
+	entry := WorkflowHistoryEntry{
+		ID:        uuid.NewString(),
+		Action:    action.ID,
+		FromStage: workflow.Stage,
+		ToStage:   workflow.Stage,
+		Note:      note,
+		Timestamp: time.Now().UTC(),
+	}
+	if action.ToStage != "" {
+		entry.ToStage = action.ToStage
+	}
+	if hasUser {
+		entry.ActorID = user.GetID()
+	}
+	workflow.Stage = entry.ToStage
+	if action.ToStatus != "" {
+		workflow.Status = action.ToStatus
+	}
+	workflow.History = append(workflow.History, entry)
+	r.updatedAt = time.Now()
+
+	for _, observer := range r.observers {
+		observer.OnWorkflowTransition(ctx, r.schema, entry)
+	}
+	return &entry, nil
+}
+
+// Submit handles form submission and returns the values to persist, with
+// sensitive fields encrypted as by HandleSubmit
+func (r *Runtime) Submit(ctx context.Context) (map[string]any, error) {
+	if err := r.HandleSubmit(ctx); err != nil {
+		return nil, err
+	}
+	return r.state.GetAllValues(), nil
+}
+
 // GetState returns current state
 func (r *Runtime) GetState() *State {
 	return r.state
@@ -389,16 +518,68 @@ func (r *Runtime) validateField(ctx context.Context, fieldName string) error {
 	return nil
 }
 
-// validateAll validates all fields
+// validateAll validates all fields, except sensitive ones holding an
+// encrypted or masked value
 func (r *Runtime) validateAll(ctx context.Context) error {
 	data := r.state.GetAllValues()
-	result := r.validator.ValidateSchema(ctx, *r.schema, data)
+	schema := *r.schema
+	if schemaEncrypts(r.schema) {
+		sensitive := r.schema.Security.Encryption.Fields
+		schema.Fields = slices.DeleteFunc(slices.Clone(schema.Fields), func(field Field) bool {
+			value := data[field.Name]
+			return slices.Contains(sensitive, field.Name) && (IsEncryptedValue(value) || IsMaskedValue(value))
+		})
+	}
+	result := r.validator.ValidateSchema(ctx, schema, data)
 	if !result.IsValid() {
 		return fmt.Errorf("schema validation failed")
 	}
 	return nil
 }
 
+// restoreMaskedValues puts back the ciphertext the form was loaded with
+// for sensitive fields a user who cannot decrypt left masked
+func (r *Runtime) restoreMaskedValues() {
+	if !schemaEncrypts(r.schema) {
+		return
+	}
+	data := r.state.GetAllValues()
+	restored := false
+	for _, name := range r.schema.Security.Encryption.Fields {
+		if initial, ok := r.state.initialValue(name); ok && IsEncryptedValue(initial) && IsMaskedValue(data[name]) {
+			data[name] = initial
+			restored = true
+		}
+	}
+	if restored {
+		r.state.replaceValues(data)
+	}
+}
+
+// encryptState replaces the sensitive values in the state with their
+// encrypted form. Masked values still left once restoreMaskedValues ran
+// stand for no ciphertext and are rejected by EncryptData.
+func (r *Runtime) encryptState(ctx context.Context) error {
+	if !schemaEncrypts(r.schema) {
+		return nil
+	}
+	if r.encryptor == nil {
+		return fmt.Errorf("%w: %s", ErrEncryptionRequired, r.schema.ID)
+	}
+
+	data := r.state.GetAllValues()
+	var tenantID string
+	if user, ok := UserFromContext(ctx); ok {
+		tenantID = user.GetTenantID()
+	}
+	encrypted, err := r.encryptor.EncryptData(ctx, r.schema, tenantID, data)
+	if err != nil {
+		return err
+	}
+	r.state.replaceValues(encrypted)
+	return nil
+}
+
 // applyConditionals applies conditional logic
 func (r *Runtime) applyConditionals(ctx context.Context) error {
 	// TODO:Implementation depends on ConditionalEngine interface
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
@@ -114,3 +115,77 @@ func (s *RuntimeTestSuite) testEnrichedSchemaIntegration() { // Disabled
 func TestRuntimeTestSuite(t *testing.T) {
 	suite.Run(t, new(RuntimeTestSuite))
 }
+
+type recordingObserver struct {
+	submitted   []map[string]any
+	transitions []WorkflowHistoryEntry
+}
+
+func (o *recordingObserver) OnSubmitted(ctx context.Context, schema *Schema, data map[string]any) {
+	o.submitted = append(o.submitted, data)
+}
+
+func (o *recordingObserver) OnWorkflowTransition(ctx context.Context, schema *Schema, entry WorkflowHistoryEntry) {
+	o.transitions = append(o.transitions, entry)
+}
+
+func TestRuntime_ObserverSeesSubmissions(t *testing.T) {
+	observer := &recordingObserver{}
+	runtime, err := NewRuntime(&Schema{ID: "customer"}).
+		WithObserver(observer).
+		WithInitialData(map[string]any{"name": "Ada"}).
+		Build()
+	require.NoError(t, err)
+
+	require.NoError(t, runtime.HandleSubmit(context.Background()))
+	require.Len(t, observer.submitted, 1)
+	assert.Equal(t, "Ada", observer.submitted[0]["name"])
+}
+
+func TestRuntime_ApplyWorkflowAction(t *testing.T) {
+	newRuntime := func(observer RuntimeObserver) *Runtime {
+		s := &Schema{ID: "invoice", Workflow: &Workflow{
+			Enabled: true,
+			Stage:   "review",
+			Status:  "pending",
+			Actions: []WorkflowAction{
+				{ID: "approve", Type: "approve", ToStage: "approved", ToStatus: "done", Permissions: []string{"invoice.approve"}},
+				{ID: "reject", Type: "reject", ToStage: "rejected", RequireNote: true},
+			},
+		}}
+		return NewRuntime(s).WithObserver(observer).MustBuild()
+	}
+	approver := ContextWithUser(context.Background(), &BasicUser{ID: "u1", TenantID: "acme", Permissions: []string{"invoice.approve"}})
+
+	t.Run("applies the action", func(t *testing.T) {
+		observer := &recordingObserver{}
+		runtime := newRuntime(observer)
+		entry, err := runtime.ApplyWorkflowAction(approver, "approve", "")
+		require.NoError(t, err)
+		assert.Equal(t, "review", entry.FromStage)
+		assert.Equal(t, "approved", entry.ToStage)
+		assert.Equal(t, "u1", entry.ActorID)
+
+		workflow := runtime.GetSchema().Workflow
+		assert.Equal(t, "approved", workflow.Stage)
+		assert.Equal(t, "done", workflow.Status)
+		assert.Equal(t, []WorkflowHistoryEntry{*entry}, workflow.History)
+		assert.Equal(t, []WorkflowHistoryEntry{*entry}, observer.transitions)
+	})
+
+	t.Run("rejects", func(t *testing.T) {
+		observer := &recordingObserver{}
+		runtime := newRuntime(observer)
+		_, err := runtime.ApplyWorkflowAction(approver, "archive", "")
+		assert.ErrorIs(t, err, ErrInvalidTransition)
+		_, err = runtime.ApplyWorkflowAction(context.Background(), "approve", "")
+		assert.ErrorIs(t, err, ErrPermissionDenied)
+		_, err = runtime.ApplyWorkflowAction(approver, "reject", "")
+		assert.ErrorIs(t, err, ErrRequiredField)
+		assert.Empty(t, observer.transitions)
+		assert.Equal(t, "review", runtime.GetSchema().Workflow.Stage)
+
+		_, err = NewRuntime(&Schema{ID: "plain"}).MustBuild().ApplyWorkflowAction(approver, "approve", "")
+		assert.ErrorIs(t, err, ErrWorkflowNotFound)
+	})
+}
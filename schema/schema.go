@@ -33,6 +33,10 @@ type Schema struct {
 	Actions []Action `json:"actions"`
 	Layout  *Layout  `json:"layout,omitempty"`
 
+	// Composition (see SchemaResolver)
+	Extends string     `json:"extends,omitempty"` // Base schema as "id" or "id@version"
+	Mixins  []MixinUse `json:"mixins,omitempty"`  // Parameterised mixins to apply
+
 	// Configuration
 	Config     *Config      `json:"config,omitempty"`
 	Security   *Security    `json:"security,omitempty"`
@@ -536,9 +536,19 @@ type LegacyRegistryConfig struct {
 	ValidateOnStore        bool
 	ValidateOnLoad         bool
 	EnableVersioning       bool
-	MaxVersions            int
-	EnableEvents           bool
-	EnableMetrics          bool
+	// MaxVersions caps the lifecycle revisions kept per schema. Revisions
+	// deployed to an environment and the latest draft are never pruned
+	MaxVersions   int
+	EnableEvents  bool
+	EnableMetrics bool
+	// EnableLifecycle makes Register and Update create draft revisions that
+	// are only served once published and deployed to an environment
+	EnableLifecycle bool
+	// DefaultEnvironment is served when Get is called without WithEnvironment
+	DefaultEnvironment string
+	// RejectBreakingChanges makes Update refuse breaking schema changes
+	// unless a migration is supplied with WithMigration
+	RejectBreakingChanges bool
 }
 
 // ToUnifiedConfig converts legacy config to unified config
@@ -102,6 +102,11 @@ func (s *FileStorage) List(ctx context.Context, filter *StorageFilter) ([]string
 			return err
 		}
 
+		// Skip version history and records
+		if info.IsDir() && path != s.basePath && strings.HasPrefix(info.Name(), ".") {
+			return filepath.SkipDir
+		}
+
 		if !info.IsDir() && strings.HasSuffix(path, ".json") {
 			// Extract ID from path
 			rel, _ := filepath.Rel(s.basePath, path)
@@ -212,3 +217,56 @@ func (s *FileStorage) getSchemaPath(id string) string {
 func (s *FileStorage) getVersionPath(id, version string) string {
 	return filepath.Join(s.basePath, ".versions", id, version+".json")
 }
+
+func (s *FileStorage) getRecordPath(key string) string {
+	return filepath.Join(s.basePath, ".records", key+".json")
+}
+
+// GetRecord implements RecordStorage
+func (s *FileStorage) GetRecord(ctx context.Context, key string) ([]byte, error) {
+	if err := validateSchemaID(key); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	data, err := os.ReadFile(s.getRecordPath(key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read record: %w", err)
+	}
+
+	return data, nil
+}
+
+// SetRecord implements RecordStorage
+func (s *FileStorage) SetRecord(ctx context.Context, key string, data []byte) error {
+	if err := validateSchemaID(key); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := writeFileAtomic(s.getRecordPath(key), data); err != nil {
+		return fmt.Errorf("write record: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteRecord implements RecordStorage
+func (s *FileStorage) DeleteRecord(ctx context.Context, key string) error {
+	if err := validateSchemaID(key); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.getRecordPath(key)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove record: %w", err)
+	}
+
+	return nil
+}
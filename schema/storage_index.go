@@ -0,0 +1,103 @@
+package schema
+
+import (
+	"encoding/json"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// storageAttributes are the schema fields StorageFilter matches on. Backends
+// extract them at write time and index them so List can filter without
+// loading every schema.
+type storageAttributes struct {
+	Type     string   `json:"type,omitempty"`
+	Category string   `json:"category,omitempty"`
+	Module   string   `json:"module,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+// extractStorageAttributes reads the filterable fields from serialized schema
+// data. Data that is not a JSON object yields empty attributes.
+func extractStorageAttributes(data []byte) storageAttributes {
+	var attrs storageAttributes
+	_ = json.Unmarshal(data, &attrs)
+	return attrs
+}
+
+// matches reports whether the attributes satisfy every criterion of filter.
+func (a storageAttributes) matches(filter *StorageFilter) bool {
+	if filter == nil {
+		return true
+	}
+	if filter.Type != "" && a.Type != filter.Type {
+		return false
+	}
+	if filter.Category != "" && a.Category != filter.Category {
+		return false
+	}
+	if filter.Module != "" && a.Module != filter.Module {
+		return false
+	}
+	for _, tag := range filter.Tags {
+		if !slices.Contains(a.Tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasStorageCriteria reports whether filter restricts results beyond paging.
+func hasStorageCriteria(filter *StorageFilter) bool {
+	return filter != nil && (filter.Type != "" || filter.Category != "" || filter.Module != "" || len(filter.Tags) > 0)
+}
+
+// pageStorageIDs sorts ids and applies the filter's offset and limit.
+func pageStorageIDs(ids []string, filter *StorageFilter) []string {
+	sort.Strings(ids)
+	if filter == nil {
+		return ids
+	}
+	if filter.Offset > 0 {
+		if filter.Offset >= len(ids) {
+			return []string{}
+		}
+		ids = ids[filter.Offset:]
+	}
+	if filter.Limit > 0 && filter.Limit < len(ids) {
+		ids = ids[:filter.Limit]
+	}
+	return ids
+}
+
+var (
+	storageVersionMu   sync.Mutex
+	lastStorageVersion int64
+)
+
+// newStorageVersion returns a unique, lexically sortable version identifier
+// derived from the current time in nanoseconds.
+func newStorageVersion() string {
+	storageVersionMu.Lock()
+	defer storageVersionMu.Unlock()
+
+	now := time.Now().UnixNano()
+	if now <= lastStorageVersion {
+		now = lastStorageVersion + 1
+	}
+	lastStorageVersion = now
+	return strconv.FormatInt(now, 10)
+}
+
+// sortStorageVersions orders version identifiers from oldest to newest.
+func sortStorageVersions(versions []string) []string {
+	sort.Slice(versions, func(i, j int) bool {
+		if len(versions[i]) != len(versions[j]) {
+			return len(versions[i]) < len(versions[j])
+		}
+		return versions[i] < versions[j]
+	})
+	return versions
+}
@@ -12,6 +12,7 @@ type MemoryStorage struct {
 	schemas  map[string][]byte
 	versions map[string]map[string][]byte
 	metadata map[string]*StorageMetadata
+	records  map[string][]byte
 	mu       sync.RWMutex
 }
 
@@ -21,6 +22,7 @@ func NewMemoryStorage() *MemoryStorage {
 		schemas:  make(map[string][]byte),
 		versions: make(map[string]map[string][]byte),
 		metadata: make(map[string]*StorageMetadata),
+		records:  make(map[string][]byte),
 	}
 }
 
@@ -202,3 +204,29 @@ func (s *MemoryStorage) GetMetadata(ctx context.Context, id string) (*StorageMet
 func (s *MemoryStorage) Health(ctx context.Context) error {
 	return nil
 }
+
+// GetRecord implements RecordStorage
+func (s *MemoryStorage) GetRecord(ctx context.Context, key string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.records[key], nil
+}
+
+// SetRecord implements RecordStorage
+func (s *MemoryStorage) SetRecord(ctx context.Context, key string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records[key] = data
+	return nil
+}
+
+// DeleteRecord implements RecordStorage
+func (s *MemoryStorage) DeleteRecord(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, key)
+	return nil
+}
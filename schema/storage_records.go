@@ -0,0 +1,84 @@
+package schema
+
+import (
+	"context"
+	"strings"
+)
+
+// Registry records such as schema lifecycles and migrations are keyed
+// "<kind>.<schema id>". Backends implementing RecordStorage keep them outside
+// the versioned schema namespace; other backends store them as schemas under
+// the reserved ID "_<kind>.<schema id>", which List filters out.
+const (
+	lifecycleRecordPrefix = "lifecycle."
+	migrationRecordPrefix = "migrations."
+
+	// reservedIDPrefix turns a record key into a reserved schema ID
+	reservedIDPrefix = "_"
+)
+
+// isRecordID reports whether a storage ID holds a registry record
+func isRecordID(id string) bool {
+	if !strings.HasPrefix(id, reservedIDPrefix) {
+		return false
+	}
+	key := strings.TrimPrefix(id, reservedIDPrefix)
+	return strings.HasPrefix(key, lifecycleRecordPrefix) || strings.HasPrefix(key, migrationRecordPrefix)
+}
+
+// getRecord returns a registry record, or nil when it does not exist
+func getRecord(ctx context.Context, storage StorageBackend, key string) ([]byte, error) {
+	if records, ok := storage.(RecordStorage); ok {
+		return records.GetRecord(ctx, key)
+	}
+	exists, err := storage.Exists(ctx, reservedIDPrefix+key)
+	if err != nil || !exists {
+		return nil, err
+	}
+	return storage.Get(ctx, reservedIDPrefix+key)
+}
+
+// setRecord writes a registry record
+func setRecord(ctx context.Context, storage StorageBackend, key string, data []byte) error {
+	if records, ok := storage.(RecordStorage); ok {
+		return records.SetRecord(ctx, key, data)
+	}
+	return storage.Set(ctx, reservedIDPrefix+key, data)
+}
+
+// deleteRecord removes a registry record
+func deleteRecord(ctx context.Context, storage StorageBackend, key string) error {
+	if records, ok := storage.(RecordStorage); ok {
+		return records.DeleteRecord(ctx, key)
+	}
+	exists, err := storage.Exists(ctx, reservedIDPrefix+key)
+	if err != nil || !exists {
+		return err
+	}
+	return storage.Delete(ctx, reservedIDPrefix+key)
+}
+
+// listSchemaIDs lists schema IDs from storage. Backends without RecordStorage
+// keep registry records next to schemas, so their IDs are listed unpaged and
+// filtered out before the page is taken.
+func (r *Registry) listSchemaIDs(ctx context.Context, filter *StorageFilter) ([]string, error) {
+	if _, ok := r.storage.(RecordStorage); ok {
+		return r.storage.List(ctx, filter)
+	}
+	unpaged := &StorageFilter{}
+	if filter != nil {
+		*unpaged = *filter
+		unpaged.Offset, unpaged.Limit = 0, 0
+	}
+	ids, err := r.storage.List(ctx, unpaged)
+	if err != nil {
+		return nil, err
+	}
+	schemaIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !isRecordID(id) {
+			schemaIDs = append(schemaIDs, id)
+		}
+	}
+	return pageStorageIDs(schemaIDs, filter), nil
+}
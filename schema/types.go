@@ -143,6 +143,9 @@ const (
 	EventSchemaUpdated    RegistryEventType = "schema.updated"
 	EventSchemaDeleted    RegistryEventType = "schema.deleted"
 	EventSchemaAccessed   RegistryEventType = "schema.accessed"
+	// EventRegistryResync is delivered when a transport may have missed remote
+	// events, e.g. after a reconnect. Receivers drop all cached schemas.
+	EventRegistryResync RegistryEventType = "registry.resync"
 )
 
 // Format represents data formats
@@ -395,6 +398,13 @@ type Encryption struct {
 	Algorithm   string   `json:"algorithm,omitempty" validate:"oneof=AES-256-GCM ChaCha20-Poly1305"`
 	KeyRotation bool     `json:"keyRotation,omitempty"`
 	KeyVersion  int      `json:"keyVersion,omitempty"`
+	// Searchable lists encrypted fields that also store a blind index
+	Searchable []string `json:"searchable,omitempty" validate:"dive,fieldname"`
+	// Permission is required to read decrypted values; defaults to
+	// DefaultDecryptPermission
+	Permission string `json:"permission,omitempty"`
+	// Mask is how values are shown to other users: "partial" (default) or "full"
+	Mask string `json:"mask,omitempty" validate:"omitempty,oneof=partial full"`
 }
 
 // Tenant defines multi-tenancy configuration
@@ -704,10 +714,15 @@ type FieldOption struct {
 
 // RegistryEvent represents a registry event
 type RegistryEvent struct {
-	Type      RegistryEventType
-	SchemaID  string
-	Timestamp time.Time
-	Data      map[string]any
+	ID        string            `json:"id"`
+	Type      RegistryEventType `json:"type"`
+	SchemaID  string            `json:"schemaId,omitempty"`
+	ActorID   string            `json:"actorId,omitempty"`  // user who made the change, from ContextWithUser
+	TenantID  string            `json:"tenantId,omitempty"` // the acting user's tenant
+	Origin    string            `json:"origin"`             // node ID of the emitting registry
+	Timestamp time.Time         `json:"timestamp"`
+	Data      map[string]any    `json:"data,omitempty"`
+	Remote    bool              `json:"-"` // set when received from another node
 }
 
 // RegistryEventHandler handles registry events
@@ -834,6 +849,19 @@ func (s *Security) ShouldEncryptField(fieldName string) bool {
 	return slices.Contains(s.Encryption.Fields, fieldName)
 }
 
+// ShouldIndexField reports whether an encrypted field keeps a blind index
+func (s *Security) ShouldIndexField(fieldName string) bool {
+	return s.ShouldEncryptField(fieldName) && slices.Contains(s.Encryption.Searchable, fieldName)
+}
+
+// DecryptPermission returns the permission needed to read encrypted fields
+func (s *Security) DecryptPermission() string {
+	if s.Encryption == nil || s.Encryption.Permission == "" {
+		return DefaultDecryptPermission
+	}
+	return s.Encryption.Permission
+}
+
 // Tenant helper methods
 func (t *Tenant) IsTenantEnabled() bool {
 	return t != nil && t.Enabled
@@ -0,0 +1,1029 @@
+package theme
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BrandingPolicy constrains what a tenant may submit as branding overrides.
+type BrandingPolicy struct {
+	// MinContrastRatio is the minimum WCAG contrast ratio the primary and
+	// secondary colours must reach against each ContrastAgainst colour.
+	MinContrastRatio float64
+	// ContrastAgainst lists semantic colour keys of the base palette.
+	ContrastAgainst []string
+
+	AllowedCSSProperties []string
+	AllowedCSSElements   []string
+	AllowedPseudoClasses []string
+	AllowedAssetHosts    []string
+	MaxCustomCSSSize     int
+	MaxCustomTokens      int
+
+	Logo    AssetConstraints
+	Favicon AssetConstraints
+}
+
+// AssetConstraints describes acceptable dimensions and formats of a branding image.
+type AssetConstraints struct {
+	MIMETypes []string
+	MinWidth  int
+	MinHeight int
+	MaxWidth  int
+	MaxHeight int
+	MaxBytes  int64
+	// Square requires width and height to be equal.
+	Square bool
+}
+
+// DefaultBrandingPolicy returns a conservative policy suitable for self-service branding.
+func DefaultBrandingPolicy() *BrandingPolicy {
+	return &BrandingPolicy{
+		MinContrastRatio: 4.5,
+		ContrastAgainst:  []string{"background"},
+		AllowedCSSProperties: []string{
+			"color", "background", "background-color", "background-image",
+			"background-position", "background-repeat", "background-size",
+			"border", "border-color", "border-radius", "border-style", "border-width",
+			"border-top", "border-right", "border-bottom", "border-left",
+			"box-shadow", "font-family", "font-size", "font-style", "font-weight",
+			"letter-spacing", "line-height", "text-align", "text-decoration",
+			"text-transform", "margin", "margin-top", "margin-right", "margin-bottom",
+			"margin-left", "padding", "padding-top", "padding-right", "padding-bottom",
+			"padding-left", "opacity", "outline", "outline-color", "outline-offset",
+			"max-width", "max-height", "transition",
+		},
+		AllowedCSSElements: []string{
+			"a", "button", "h1", "h2", "h3", "h4", "h5", "h6", "p", "span",
+			"img", "label", "input", "select", "textarea", "table", "th", "td",
+			"ul", "ol", "li", "nav", "header", "footer",
+		},
+		AllowedPseudoClasses: []string{
+			"hover", "focus", "focus-visible", "focus-within", "active", "visited",
+			"disabled", "checked", "first-child", "last-child",
+		},
+		MaxCustomCSSSize: 32 << 10,
+		MaxCustomTokens:  100,
+		Logo: AssetConstraints{
+			MIMETypes: []string{"image/png", "image/svg+xml", "image/webp", "image/jpeg"},
+			MinWidth:  32,
+			MinHeight: 32,
+			MaxWidth:  1024,
+			MaxHeight: 512,
+			MaxBytes:  512 << 10,
+		},
+		Favicon: AssetConstraints{
+			MIMETypes: []string{"image/png", "image/svg+xml", "image/x-icon", "image/vnd.microsoft.icon"},
+			MinWidth:  16,
+			MinHeight: 16,
+			MaxWidth:  512,
+			MaxHeight: 512,
+			MaxBytes:  128 << 10,
+			Square:    true,
+		},
+	}
+}
+
+// AssetInfo describes a branding image after inspection.
+type AssetInfo struct {
+	MIMEType string
+	Width    int
+	Height   int
+	Size     int64
+}
+
+// AssetInspector resolves a logo or favicon reference to its format and dimensions.
+// Implementations may read from a CDN, object storage or an upload service.
+type AssetInspector interface {
+	Inspect(ctx context.Context, ref string) (*AssetInfo, error)
+}
+
+// BrandingValidator validates and sanitises tenant branding overrides.
+type BrandingValidator struct {
+	policy    *BrandingPolicy
+	inspector AssetInspector
+}
+
+// NewBrandingValidator creates a branding validator. A nil policy uses
+// DefaultBrandingPolicy and a nil inspector uses DefaultAssetInspector.
+func NewBrandingValidator(policy *BrandingPolicy, inspector AssetInspector) *BrandingValidator {
+	if policy == nil {
+		policy = DefaultBrandingPolicy()
+	}
+	if inspector == nil {
+		inspector = NewDefaultAssetInspector(policy.AllowedAssetHosts, nil)
+	}
+	return &BrandingValidator{
+		policy:    policy,
+		inspector: inspector,
+	}
+}
+
+// Policy returns the policy enforced by the validator.
+func (v *BrandingValidator) Policy() *BrandingPolicy {
+	return v.policy
+}
+
+// BrandingValidationResult contains validation issues and the sanitised overrides.
+type BrandingValidationResult struct {
+	Valid     bool
+	Issues    []ValidationIssue
+	Sanitized *BrandingOverrides
+}
+
+// Validate checks branding overrides against the resolved base tokens of the
+// tenant's theme and returns a sanitised copy. Only issues with severity
+// "error" make the result invalid; dropped CSS declarations are reported as warnings.
+func (v *BrandingValidator) Validate(ctx context.Context, base *Tokens, branding *BrandingOverrides) *BrandingValidationResult {
+	result := &BrandingValidationResult{
+		Valid:  true,
+		Issues: make([]ValidationIssue, 0),
+	}
+	if branding == nil {
+		result.Valid = false
+		result.Issues = append(result.Issues, ValidationIssue{
+			Severity: "error",
+			Message:  "branding overrides cannot be nil",
+			Path:     "branding",
+		})
+		return result
+	}
+
+	sanitized := *branding
+	sanitized.CustomTokens = cloneStringMap(branding.CustomTokens)
+
+	result.Issues = append(result.Issues, v.validateColors(base, branding)...)
+	result.Issues = append(result.Issues, v.validateCustomTokens(branding.CustomTokens)...)
+	result.Issues = append(result.Issues, v.validateAsset(ctx, "logo", branding.Logo, v.policy.Logo)...)
+	result.Issues = append(result.Issues, v.validateAsset(ctx, "favicon", branding.Favicon, v.policy.Favicon)...)
+
+	if branding.CustomCSS != "" {
+		if v.policy.MaxCustomCSSSize > 0 && len(branding.CustomCSS) > v.policy.MaxCustomCSSSize {
+			result.Issues = append(result.Issues, ValidationIssue{
+				Severity: "error",
+				Message:  fmt.Sprintf("custom CSS exceeds maximum size of %d bytes", v.policy.MaxCustomCSSSize),
+				Path:     "branding.customCSS",
+			})
+		} else {
+			css, issues := v.SanitizeCSS(branding.CustomCSS, "")
+			sanitized.CustomCSS = css
+			result.Issues = append(result.Issues, issues...)
+		}
+	}
+
+	for _, issue := range result.Issues {
+		if issue.Severity == "error" {
+			result.Valid = false
+			break
+		}
+	}
+	result.Sanitized = &sanitized
+
+	return result
+}
+
+// validateColors checks that branding colours parse and contrast with the base palette.
+func (v *BrandingValidator) validateColors(base *Tokens, branding *BrandingOverrides) []ValidationIssue {
+	issues := make([]ValidationIssue, 0)
+
+	colors := []struct {
+		path  string
+		value string
+	}{
+		{"branding.primaryColor", branding.PrimaryColor},
+		{"branding.secondaryColor", branding.SecondaryColor},
+	}
+
+	for _, color := range colors {
+		if color.value == "" {
+			continue
+		}
+		if _, ok := parseColor(color.value); !ok {
+			issues = append(issues, ValidationIssue{
+				Severity: "error",
+				Message:  fmt.Sprintf("unsupported colour value %q, use hex or rgb()", color.value),
+				Path:     color.path,
+			})
+			continue
+		}
+
+		for _, key := range v.policy.ContrastAgainst {
+			against := basePaletteColor(base, key)
+			if against == "" {
+				continue
+			}
+			ratio, err := ContrastRatio(color.value, against)
+			if err != nil {
+				issues = append(issues, ValidationIssue{
+					Severity: "info",
+					Message:  fmt.Sprintf("contrast against semantic colour %q skipped: %s", key, err.Error()),
+					Path:     color.path,
+				})
+				continue
+			}
+			if ratio < v.policy.MinContrastRatio {
+				issues = append(issues, ValidationIssue{
+					Severity: "error",
+					Message: fmt.Sprintf("contrast ratio %.2f:1 against semantic colour %q is below the required %.1f:1",
+						ratio, key, v.policy.MinContrastRatio),
+					Path: color.path,
+				})
+			}
+		}
+	}
+
+	return issues
+}
+
+// validateCustomTokens checks that custom token overrides target known token paths.
+func (v *BrandingValidator) validateCustomTokens(tokens map[string]string) []ValidationIssue {
+	issues := make([]ValidationIssue, 0)
+
+	if v.policy.MaxCustomTokens > 0 && len(tokens) > v.policy.MaxCustomTokens {
+		issues = append(issues, ValidationIssue{
+			Severity: "error",
+			Message:  fmt.Sprintf("too many custom tokens (%d), maximum is %d", len(tokens), v.policy.MaxCustomTokens),
+			Path:     "branding.customTokens",
+		})
+		return issues
+	}
+
+	for path, value := range tokens {
+		segments := strings.Split(path, ".")
+		if len(segments) < 3 || !slices.Contains([]string{"primitives", "semantic", "components"}, segments[0]) {
+			issues = append(issues, ValidationIssue{
+				Severity: "error",
+				Message:  fmt.Sprintf("invalid token path %q", path),
+				Path:     "branding.customTokens",
+			})
+			continue
+		}
+		if unsafeCSSValue(value) {
+			issues = append(issues, ValidationIssue{
+				Severity: "error",
+				Message:  fmt.Sprintf("token %q contains a disallowed value", path),
+				Path:     "branding.customTokens." + path,
+			})
+		}
+	}
+
+	return issues
+}
+
+// validateAsset checks a logo or favicon reference against its constraints.
+func (v *BrandingValidator) validateAsset(ctx context.Context, name, ref string, constraints AssetConstraints) []ValidationIssue {
+	issues := make([]ValidationIssue, 0)
+	if ref == "" {
+		return issues
+	}
+	path := "branding." + name
+
+	if !v.allowedURL(ref) {
+		issues = append(issues, ValidationIssue{
+			Severity: "error",
+			Message:  fmt.Sprintf("%s must be a relative URL, a data URI or hosted on an allowed host", name),
+			Path:     path,
+		})
+		return issues
+	}
+
+	info, err := v.inspector.Inspect(ctx, ref)
+	if err != nil {
+		issues = append(issues, ValidationIssue{
+			Severity: "error",
+			Message:  fmt.Sprintf("failed to inspect %s: %s", name, err.Error()),
+			Path:     path,
+		})
+		return issues
+	}
+
+	if len(constraints.MIMETypes) > 0 && !slices.Contains(constraints.MIMETypes, info.MIMEType) {
+		issues = append(issues, ValidationIssue{
+			Severity: "error",
+			Message:  fmt.Sprintf("%s type %s is not allowed (allowed: %s)", name, info.MIMEType, strings.Join(constraints.MIMETypes, ", ")),
+			Path:     path,
+		})
+	}
+	if constraints.MaxBytes > 0 && info.Size > constraints.MaxBytes {
+		issues = append(issues, ValidationIssue{
+			Severity: "error",
+			Message:  fmt.Sprintf("%s is %d bytes, maximum is %d", name, info.Size, constraints.MaxBytes),
+			Path:     path,
+		})
+	}
+
+	// Vector images without intrinsic dimensions scale freely.
+	if info.Width == 0 && info.Height == 0 && info.MIMEType == "image/svg+xml" {
+		return issues
+	}
+
+	if info.Width < constraints.MinWidth || info.Height < constraints.MinHeight {
+		issues = append(issues, ValidationIssue{
+			Severity: "error",
+			Message: fmt.Sprintf("%s is %dx%d, minimum is %dx%d", name, info.Width, info.Height,
+				constraints.MinWidth, constraints.MinHeight),
+			Path: path,
+		})
+	}
+	if (constraints.MaxWidth > 0 && info.Width > constraints.MaxWidth) ||
+		(constraints.MaxHeight > 0 && info.Height > constraints.MaxHeight) {
+		issues = append(issues, ValidationIssue{
+			Severity: "error",
+			Message: fmt.Sprintf("%s is %dx%d, maximum is %dx%d", name, info.Width, info.Height,
+				constraints.MaxWidth, constraints.MaxHeight),
+			Path: path,
+		})
+	}
+	if constraints.Square && info.Width != info.Height {
+		issues = append(issues, ValidationIssue{
+			Severity: "error",
+			Message:  fmt.Sprintf("%s must be square, got %dx%d", name, info.Width, info.Height),
+			Path:     path,
+		})
+	}
+
+	return issues
+}
+
+// allowedURL reports whether a URL is relative, an image data URI or on an allowed host.
+func (v *BrandingValidator) allowedURL(ref string) bool {
+	ref = strings.TrimSpace(ref)
+	if strings.HasPrefix(ref, "data:") {
+		return strings.HasPrefix(ref, "data:image/")
+	}
+
+	u, err := url.Parse(ref)
+	if err != nil {
+		return false
+	}
+	if u.Scheme == "" && u.Host == "" {
+		return !strings.HasPrefix(ref, "//")
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return false
+	}
+	return slices.Contains(v.policy.AllowedAssetHosts, strings.ToLower(u.Hostname()))
+}
+
+var (
+	cssCommentPattern  = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	cssURLPattern      = regexp.MustCompile(`(?i)url\(\s*(?:"([^"]*)"|'([^']*)'|([^)]*))\s*\)`)
+	cssImageSetPattern = regexp.MustCompile(`(?i)(?:-webkit-)?image-set\(`)
+	cssStringPattern   = regexp.MustCompile(`"([^"]*)"|'([^']*)'`)
+	cssCompoundPattern = regexp.MustCompile(`^([a-z][a-z0-9]*)?((?:\.[a-zA-Z_][a-zA-Z0-9_-]*|:[a-z-]+)*)$`)
+	cssPseudoPattern   = regexp.MustCompile(`:([a-z-]+)`)
+	// cssMediaPattern allows media types, features and their values only:
+	// no markup, strings, escapes, urls or nested blocks
+	cssMediaPattern = regexp.MustCompile(`^@media\s+[a-zA-Z0-9\s(),:./%-]+$`)
+)
+
+// SanitizeCSS rewrites custom CSS so that only allowed selectors and properties
+// remain. Rules are emitted one per line; when scope is non-empty every selector
+// is prefixed with it. @import and other at-rules except @media are rejected.
+func (v *BrandingValidator) SanitizeCSS(css, scope string) (string, []ValidationIssue) {
+	var buf bytes.Buffer
+	issues := v.sanitizeBlock(&buf, cssCommentPattern.ReplaceAllString(css, ""), scope)
+	return strings.TrimSpace(buf.String()), issues
+}
+
+// sanitizeBlock sanitises a sequence of rules, recursing into @media blocks.
+func (v *BrandingValidator) sanitizeBlock(buf *bytes.Buffer, css, scope string) []ValidationIssue {
+	issues := make([]ValidationIssue, 0)
+
+	for len(strings.TrimSpace(css)) > 0 {
+		css = strings.TrimSpace(css)
+		open := strings.IndexByte(css, '{')
+		semi := strings.IndexByte(css, ';')
+
+		// Statement at-rules such as @import end with a semicolon before any block.
+		if strings.HasPrefix(css, "@") && semi >= 0 && (open < 0 || semi < open) {
+			statement := strings.TrimSpace(css[:semi])
+			issues = append(issues, ValidationIssue{
+				Severity: "error",
+				Message:  fmt.Sprintf("at-rule %q is not allowed", atRuleName(statement)),
+				Path:     "branding.customCSS",
+			})
+			css = css[semi+1:]
+			continue
+		}
+		if open < 0 {
+			issues = append(issues, ValidationIssue{
+				Severity: "warning",
+				Message:  "trailing CSS without a rule block was dropped",
+				Path:     "branding.customCSS",
+			})
+			break
+		}
+
+		end := matchingBrace(css, open)
+		if end < 0 {
+			issues = append(issues, ValidationIssue{
+				Severity: "error",
+				Message:  "custom CSS has unbalanced braces",
+				Path:     "branding.customCSS",
+			})
+			break
+		}
+
+		prelude := strings.TrimSpace(css[:open])
+		body := css[open+1 : end]
+		css = css[end+1:]
+
+		if strings.HasPrefix(prelude, "@") {
+			name := atRuleName(prelude)
+			if name != "@media" {
+				issues = append(issues, ValidationIssue{
+					Severity: "error",
+					Message:  fmt.Sprintf("at-rule %q is not allowed", name),
+					Path:     "branding.customCSS",
+				})
+				continue
+			}
+			if !cssMediaPattern.MatchString(prelude) || strings.Contains(strings.ToLower(prelude), "url(") {
+				issues = append(issues, ValidationIssue{
+					Severity: "error",
+					Message:  fmt.Sprintf("media query %q is not allowed", prelude),
+					Path:     "branding.customCSS",
+				})
+				continue
+			}
+			var inner bytes.Buffer
+			issues = append(issues, v.sanitizeBlock(&inner, body, scope)...)
+			if inner.Len() > 0 {
+				fmt.Fprintf(buf, "%s {\n%s}\n", prelude, inner.String())
+			}
+			continue
+		}
+
+		selectors, selectorIssues := v.sanitizeSelectors(prelude, scope)
+		issues = append(issues, selectorIssues...)
+		declarations, declarationIssues := v.sanitizeDeclarations(body)
+		issues = append(issues, declarationIssues...)
+
+		if len(selectors) == 0 || len(declarations) == 0 {
+			continue
+		}
+		fmt.Fprintf(buf, "%s { %s; }\n", strings.Join(selectors, ", "), strings.Join(declarations, "; "))
+	}
+
+	return issues
+}
+
+// sanitizeSelectors keeps the allowed selectors of a comma-separated selector list.
+func (v *BrandingValidator) sanitizeSelectors(prelude, scope string) ([]string, []ValidationIssue) {
+	issues := make([]ValidationIssue, 0)
+	selectors := make([]string, 0)
+
+	for _, selector := range strings.Split(prelude, ",") {
+		selector = strings.Join(strings.Fields(selector), " ")
+		if selector == "" {
+			continue
+		}
+		if !v.allowedSelector(selector) {
+			issues = append(issues, ValidationIssue{
+				Severity: "warning",
+				Message:  fmt.Sprintf("selector %q is not allowed and was dropped", selector),
+				Path:     "branding.customCSS",
+			})
+			continue
+		}
+		if scope != "" {
+			selector = scope + " " + selector
+		}
+		selectors = append(selectors, selector)
+	}
+
+	return selectors, issues
+}
+
+// allowedSelector reports whether every compound selector uses allowed
+// elements, classes and pseudo-classes only.
+func (v *BrandingValidator) allowedSelector(selector string) bool {
+	normalized := strings.NewReplacer(">", " ", "+", " ", "~", " ").Replace(selector)
+	for _, compound := range strings.Fields(normalized) {
+		match := cssCompoundPattern.FindStringSubmatch(compound)
+		if match == nil {
+			return false
+		}
+		if match[1] != "" && !slices.Contains(v.policy.AllowedCSSElements, match[1]) {
+			return false
+		}
+		for _, pseudo := range cssPseudoPattern.FindAllStringSubmatch(match[2], -1) {
+			if !slices.Contains(v.policy.AllowedPseudoClasses, pseudo[1]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// sanitizeDeclarations keeps the allowed declarations of a rule body.
+func (v *BrandingValidator) sanitizeDeclarations(body string) ([]string, []ValidationIssue) {
+	issues := make([]ValidationIssue, 0)
+	declarations := make([]string, 0)
+
+	for _, declaration := range splitDeclarations(body) {
+		colon := strings.IndexByte(declaration, ':')
+		if colon < 0 {
+			continue
+		}
+		property := strings.ToLower(strings.TrimSpace(declaration[:colon]))
+		value := strings.Join(strings.Fields(declaration[colon+1:]), " ")
+		if property == "" || value == "" {
+			continue
+		}
+
+		if !strings.HasPrefix(property, "--") && !slices.Contains(v.policy.AllowedCSSProperties, property) {
+			issues = append(issues, ValidationIssue{
+				Severity: "warning",
+				Message:  fmt.Sprintf("property %q is not allowed and was dropped", property),
+				Path:     "branding.customCSS",
+			})
+			continue
+		}
+		if unsafeCSSValue(value) {
+			issues = append(issues, ValidationIssue{
+				Severity: "error",
+				Message:  fmt.Sprintf("value of property %q contains a disallowed construct", property),
+				Path:     "branding.customCSS",
+			})
+			continue
+		}
+		if foreign := v.foreignURL(value); foreign != "" {
+			issues = append(issues, ValidationIssue{
+				Severity: "error",
+				Message:  fmt.Sprintf("url(%s) references a host that is not allowed", foreign),
+				Path:     "branding.customCSS",
+			})
+			continue
+		}
+
+		declarations = append(declarations, property+": "+value)
+	}
+
+	return declarations, issues
+}
+
+// foreignURL returns the first url() or image-set() target in value that is
+// not allowed. image-set() accepts bare strings as well as url() references.
+func (v *BrandingValidator) foreignURL(value string) string {
+	for _, match := range cssURLPattern.FindAllStringSubmatch(value, -1) {
+		target := strings.TrimSpace(match[1] + match[2] + match[3])
+		if !v.allowedURL(target) {
+			return target
+		}
+	}
+	for _, loc := range cssImageSetPattern.FindAllStringIndex(value, -1) {
+		args := value[loc[1]:]
+		if end := matchingParen(value, loc[1]-1); end >= 0 {
+			args = value[loc[1]:end]
+		}
+		args = cssURLPattern.ReplaceAllString(args, "")
+		for _, match := range cssStringPattern.FindAllStringSubmatch(args, -1) {
+			target := strings.TrimSpace(match[1] + match[2])
+			if !v.allowedURL(target) {
+				return target
+			}
+		}
+	}
+	return ""
+}
+
+// unsafeCSSValue reports values that can execute script or escape the declaration.
+func unsafeCSSValue(value string) bool {
+	lower := strings.ToLower(value)
+	for _, needle := range []string{"expression(", "javascript:", "vbscript:", "behavior:", "-moz-binding", "</", "\\"} {
+		if strings.Contains(lower, needle) {
+			return true
+		}
+	}
+	return strings.ContainsAny(value, "{}<>")
+}
+
+// splitDeclarations splits a rule body on semicolons outside quotes and parentheses.
+func splitDeclarations(body string) []string {
+	parts := make([]string, 0)
+	depth := 0
+	var quote rune
+	start := 0
+
+	for i, r := range body {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			if depth > 0 {
+				depth--
+			}
+		case r == ';' && depth == 0:
+			parts = append(parts, body[start:i])
+			start = i + 1
+		}
+	}
+	parts = append(parts, body[start:])
+
+	return parts
+}
+
+// matchingBrace returns the index of the brace closing the one at open.
+func matchingBrace(css string, open int) int {
+	depth := 0
+	for i := open; i < len(css); i++ {
+		switch css[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// matchingParen returns the index of the parenthesis closing the one at open.
+func matchingParen(value string, open int) int {
+	depth := 0
+	for i := open; i < len(value); i++ {
+		switch value[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// atRuleName returns the lower-cased name of an at-rule prelude.
+func atRuleName(prelude string) string {
+	end := strings.IndexAny(prelude, " \t\n(\"'")
+	if end < 0 {
+		end = len(prelude)
+	}
+	return strings.ToLower(prelude[:end])
+}
+
+// basePaletteColor returns a resolved semantic colour, falling back to primitives.
+func basePaletteColor(tokens *Tokens, key string) string {
+	if tokens == nil {
+		return ""
+	}
+	if tokens.Semantic != nil && tokens.Semantic.Colors != nil {
+		if value, ok := tokens.Semantic.Colors[key]; ok {
+			return value
+		}
+	}
+	if tokens.Primitives != nil && tokens.Primitives.Colors != nil {
+		return tokens.Primitives.Colors[key]
+	}
+	return ""
+}
+
+// ContrastRatio returns the WCAG 2.x contrast ratio between two colours.
+// Colours must be hex (#rgb, #rrggbb) or rgb()/rgba() values.
+func ContrastRatio(foreground, background string) (float64, error) {
+	fg, ok := parseColor(foreground)
+	if !ok {
+		return 0, NewErrorf(ErrCodeValidation, "unsupported colour: %s", foreground)
+	}
+	bg, ok := parseColor(background)
+	if !ok {
+		return 0, NewErrorf(ErrCodeValidation, "unsupported colour: %s", background)
+	}
+
+	l1, l2 := relativeLuminance(fg), relativeLuminance(bg)
+	if l1 < l2 {
+		l1, l2 = l2, l1
+	}
+	return (l1 + 0.05) / (l2 + 0.05), nil
+}
+
+// parseColor parses hex and rgb() colours into 0-255 channels.
+func parseColor(value string) ([3]float64, bool) {
+	var rgb [3]float64
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	if strings.HasPrefix(value, "#") {
+		hex := value[1:]
+		if len(hex) == 3 || len(hex) == 4 {
+			hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+		}
+		if len(hex) == 8 {
+			hex = hex[:6]
+		}
+		if len(hex) != 6 {
+			return rgb, false
+		}
+		for i := 0; i < 3; i++ {
+			channel, err := strconv.ParseUint(hex[i*2:i*2+2], 16, 8)
+			if err != nil {
+				return rgb, false
+			}
+			rgb[i] = float64(channel)
+		}
+		return rgb, true
+	}
+
+	if strings.HasPrefix(value, "rgb(") || strings.HasPrefix(value, "rgba(") {
+		inner := value[strings.IndexByte(value, '(')+1:]
+		inner = strings.TrimSuffix(inner, ")")
+		inner = strings.NewReplacer(",", " ", "/", " ").Replace(inner)
+		parts := strings.Fields(inner)
+		if len(parts) < 3 {
+			return rgb, false
+		}
+		for i := 0; i < 3; i++ {
+			part := parts[i]
+			scale := 1.0
+			if strings.HasSuffix(part, "%") {
+				part = strings.TrimSuffix(part, "%")
+				scale = 2.55
+			}
+			channel, err := strconv.ParseFloat(part, 64)
+			if err != nil || channel < 0 {
+				return rgb, false
+			}
+			rgb[i] = math.Min(channel*scale, 255)
+		}
+		return rgb, true
+	}
+
+	return rgb, false
+}
+
+// relativeLuminance computes WCAG relative luminance of an sRGB colour.
+func relativeLuminance(rgb [3]float64) float64 {
+	var linear [3]float64
+	for i, channel := range rgb {
+		c := channel / 255
+		if c <= 0.03928 {
+			linear[i] = c / 12.92
+		} else {
+			linear[i] = math.Pow((c+0.055)/1.055, 2.4)
+		}
+	}
+	return 0.2126*linear[0] + 0.7152*linear[1] + 0.0722*linear[2]
+}
+
+// DefaultAssetInspector inspects data URIs in-process and fetches http(s)
+// assets from allowed hosts.
+type DefaultAssetInspector struct {
+	allowedHosts []string
+	client       *http.Client
+	maxBytes     int64
+}
+
+// NewDefaultAssetInspector creates an inspector. A nil client uses a client
+// with a 10s timeout. Redirects are followed only to allowed hosts, so an
+// allowed host cannot send the inspector to an internal address.
+func NewDefaultAssetInspector(allowedHosts []string, client *http.Client) *DefaultAssetInspector {
+	i := &DefaultAssetInspector{
+		allowedHosts: allowedHosts,
+		maxBytes:     4 << 20,
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	checked := *client
+	next := client.CheckRedirect
+	checked.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if err := i.checkURL(req.URL); err != nil {
+			return err
+		}
+		if next != nil {
+			return next(req, via)
+		}
+		if len(via) >= 10 {
+			return NewError(ErrCodeStorage, "too many asset redirects")
+		}
+		return nil
+	}
+	i.client = &checked
+	return i
+}
+
+// Inspect decodes the asset header to determine its type and dimensions.
+func (i *DefaultAssetInspector) Inspect(ctx context.Context, ref string) (*AssetInfo, error) {
+	data, declaredType, err := i.load(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	return inspectImage(data, declaredType)
+}
+
+// load reads asset bytes from a data URI or an allowed remote host.
+func (i *DefaultAssetInspector) load(ctx context.Context, ref string) ([]byte, string, error) {
+	if strings.HasPrefix(ref, "data:") {
+		return decodeDataURI(ref)
+	}
+
+	u, err := url.Parse(ref)
+	if err != nil {
+		return nil, "", WrapError(ErrCodeValidation, "invalid asset URL", err)
+	}
+	if err := i.checkURL(u); err != nil {
+		return nil, "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ref, nil)
+	if err != nil {
+		return nil, "", WrapError(ErrCodeValidation, "invalid asset request", err)
+	}
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return nil, "", WrapError(ErrCodeStorage, "failed to fetch asset", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", NewErrorf(ErrCodeStorage, "failed to fetch asset: HTTP %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, i.maxBytes+1))
+	if err != nil {
+		return nil, "", WrapError(ErrCodeStorage, "failed to read asset", err)
+	}
+	contentType := strings.TrimSpace(strings.Split(resp.Header.Get("Content-Type"), ";")[0])
+	return data, contentType, nil
+}
+
+// checkURL accepts absolute http(s) URLs on an allowed host
+func (i *DefaultAssetInspector) checkURL(u *url.URL) error {
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return NewError(ErrCodeValidation, "relative asset URLs cannot be inspected without a custom AssetInspector")
+	}
+	if !slices.Contains(i.allowedHosts, strings.ToLower(u.Hostname())) {
+		return NewErrorf(ErrCodeValidation, "asset host not allowed: %s", u.Hostname())
+	}
+	return nil
+}
+
+// decodeDataURI decodes a base64 or percent-encoded data URI.
+func decodeDataURI(ref string) ([]byte, string, error) {
+	comma := strings.IndexByte(ref, ',')
+	if comma < 0 {
+		return nil, "", NewError(ErrCodeValidation, "malformed data URI")
+	}
+	meta := strings.TrimPrefix(ref[:comma], "data:")
+	payload := ref[comma+1:]
+
+	params := strings.Split(meta, ";")
+	mediaType := strings.ToLower(params[0])
+
+	if slices.Contains(params[1:], "base64") {
+		data, err := base64.StdEncoding.DecodeString(payload)
+		if err != nil {
+			return nil, "", WrapError(ErrCodeValidation, "invalid base64 data URI", err)
+		}
+		return data, mediaType, nil
+	}
+
+	decoded, err := url.PathUnescape(payload)
+	if err != nil {
+		return nil, "", WrapError(ErrCodeValidation, "invalid data URI encoding", err)
+	}
+	return []byte(decoded), mediaType, nil
+}
+
+// inspectImage sniffs the image format and reads its dimensions.
+func inspectImage(data []byte, declaredType string) (*AssetInfo, error) {
+	info := &AssetInfo{Size: int64(len(data))}
+
+	sniffed := http.DetectContentType(data)
+	switch {
+	case isSVG(data, declaredType, sniffed):
+		info.MIMEType = "image/svg+xml"
+		info.Width, info.Height = svgDimensions(data)
+		return info, nil
+	case bytes.HasPrefix(data, []byte{0, 0, 1, 0}):
+		info.MIMEType = "image/x-icon"
+		if len(data) >= 8 {
+			// ICO directory entries store 0 for 256px.
+			info.Width, info.Height = int(data[6]), int(data[7])
+			if info.Width == 0 {
+				info.Width = 256
+			}
+			if info.Height == 0 {
+				info.Height = 256
+			}
+		}
+		return info, nil
+	case sniffed == "image/webp":
+		info.MIMEType = sniffed
+		info.Width, info.Height = webpDimensions(data)
+		return info, nil
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, WrapError(ErrCodeValidation, "unrecognised image format", err)
+	}
+	info.MIMEType = "image/" + format
+	info.Width = config.Width
+	info.Height = config.Height
+
+	return info, nil
+}
+
+// isSVG reports whether data looks like an SVG document.
+func isSVG(data []byte, declaredType, sniffed string) bool {
+	if declaredType == "image/svg+xml" {
+		return true
+	}
+	if !strings.HasPrefix(sniffed, "text/") {
+		return false
+	}
+	return bytes.Contains(bytes.ToLower(data[:min(len(data), 512)]), []byte("<svg"))
+}
+
+// svgDimensions reads width and height (or the viewBox) from the root svg element.
+func svgDimensions(data []byte) (int, int) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return 0, 0
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "svg" {
+			continue
+		}
+
+		var width, height int
+		var viewBox string
+		for _, attr := range start.Attr {
+			switch attr.Name.Local {
+			case "width":
+				width = parseSVGLength(attr.Value)
+			case "height":
+				height = parseSVGLength(attr.Value)
+			case "viewBox":
+				viewBox = attr.Value
+			}
+		}
+		if (width == 0 || height == 0) && viewBox != "" {
+			fields := strings.Fields(strings.ReplaceAll(viewBox, ",", " "))
+			if len(fields) == 4 {
+				width = parseSVGLength(fields[2])
+				height = parseSVGLength(fields[3])
+			}
+		}
+		return width, height
+	}
+}
+
+// parseSVGLength parses a unitless or px SVG length.
+func parseSVGLength(value string) int {
+	value = strings.TrimSuffix(strings.TrimSpace(value), "px")
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+	return int(math.Round(n))
+}
+
+// webpDimensions reads the canvas size of lossy, lossless and extended WebP images.
+func webpDimensions(data []byte) (int, int) {
+	if len(data) < 30 {
+		return 0, 0
+	}
+	switch string(data[12:16]) {
+	case "VP8 ":
+		return int(data[26]) | int(data[27]&0x3f)<<8, int(data[28]) | int(data[29]&0x3f)<<8
+	case "VP8L":
+		bits := uint32(data[21]) | uint32(data[22])<<8 | uint32(data[23])<<16 | uint32(data[24])<<24
+		return int(bits&0x3fff) + 1, int((bits>>14)&0x3fff) + 1
+	case "VP8X":
+		width := int(data[24]) | int(data[25])<<8 | int(data[26])<<16
+		height := int(data[27]) | int(data[28])<<8 | int(data[29])<<16
+		return width + 1, height + 1
+	}
+	return 0, 0
+}
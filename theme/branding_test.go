@@ -0,0 +1,339 @@
+package theme
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func pngDataURI(t *testing.T, width, height int) string {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))))
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func newBrandingTestTheme(id string) *Theme {
+	theme := newTestTheme(id)
+	theme.Tokens.Semantic.Colors["background"] = "#FFFFFF"
+	return theme
+}
+
+func TestContrastRatio(t *testing.T) {
+	ratio, err := ContrastRatio("#000000", "#FFFFFF")
+	require.NoError(t, err)
+	require.InDelta(t, 21.0, ratio, 0.01)
+
+	ratio, err = ContrastRatio("rgb(255, 255, 255)", "#fff")
+	require.NoError(t, err)
+	require.InDelta(t, 1.0, ratio, 0.01)
+
+	_, err = ContrastRatio("oklch(0.5 0.1 200)", "#fff")
+	require.Error(t, err)
+}
+
+func TestBrandingValidator_SanitizeCSS(t *testing.T) {
+	v := NewBrandingValidator(nil, nil)
+
+	t.Run("keeps allowed rules and scopes selectors", func(t *testing.T) {
+		css, issues := v.SanitizeCSS(".btn:hover, a { color: #123456; position: fixed; }", ".theme-x")
+		require.Equal(t, ".theme-x .btn:hover, .theme-x a { color: #123456; }", css)
+		require.Len(t, issues, 1)
+		require.Equal(t, "warning", issues[0].Severity)
+	})
+
+	t.Run("rejects imports and foreign urls", func(t *testing.T) {
+		css, issues := v.SanitizeCSS(`@import url("https://evil.example/x.css"); .logo { background-image: url(https://evil.example/a.png); color: red; }`, "")
+		require.Equal(t, ".logo { color: red; }", css)
+		require.Len(t, issues, 2)
+		for _, issue := range issues {
+			require.Equal(t, "error", issue.Severity)
+		}
+	})
+
+	t.Run("drops disallowed selectors", func(t *testing.T) {
+		css, issues := v.SanitizeCSS("body, #app, [data-x], * { color: red; }", "")
+		require.Empty(t, css)
+		require.Len(t, issues, 4)
+	})
+
+	t.Run("recurses into media queries", func(t *testing.T) {
+		css, issues := v.SanitizeCSS("@media (max-width: 600px) { .btn { padding: 4px; } }", "")
+		require.Empty(t, issues)
+		require.Contains(t, css, "@media (max-width: 600px) {\n.btn { padding: 4px; }\n}")
+	})
+
+	t.Run("rejects media queries that are not media queries", func(t *testing.T) {
+		for _, prelude := range []string{
+			"@media </style><script>alert(1)</script>",
+			`@media screen and (max-width: "600px")`,
+			`@media \3c/style`,
+			"@media screen and (min-width: url(x))",
+		} {
+			css, issues := v.SanitizeCSS(prelude+" { a { color: red } }", "")
+			require.NotContains(t, css, "<", prelude)
+			require.NotContains(t, css, "@media", prelude)
+			require.NotEmpty(t, issues, prelude)
+			require.Equal(t, "error", issues[0].Severity, prelude)
+		}
+	})
+
+	t.Run("checks image-set targets against allowed hosts", func(t *testing.T) {
+		css, issues := v.SanitizeCSS(`.hero { background-image: image-set("https://evil.example/a.png" 1x, url(/img/a@2x.png) 2x); }`, "")
+		require.Empty(t, css)
+		require.Len(t, issues, 1)
+		require.Equal(t, "error", issues[0].Severity)
+
+		css, issues = v.SanitizeCSS(`.hero { background-image: -webkit-image-set("/img/a.png" 1x, "/img/a@2x.png" type("image/png") 2x); }`, "")
+		require.Empty(t, issues)
+		require.Contains(t, css, "image-set")
+	})
+
+	t.Run("allows relative and data urls", func(t *testing.T) {
+		css, issues := v.SanitizeCSS(".hero { background-image: url('/img/hero.png'); }", "")
+		require.Empty(t, issues)
+		require.Contains(t, css, "url('/img/hero.png')")
+	})
+}
+
+func TestDefaultAssetInspector_Redirects(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 4))))
+	var internalHits atomic.Int32
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		internalHits.Add(1)
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(buf.Bytes())
+	}))
+	defer internal.Close()
+	internalURL, err := url.Parse(internal.URL)
+	require.NoError(t, err)
+	// The internal server is reached as localhost, which is not allowed
+	internalURL.Host = "localhost:" + internalURL.Port()
+
+	allowed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target := internal.URL
+		if r.URL.Path == "/external" {
+			target = internalURL.String()
+		}
+		http.Redirect(w, r, target+"/logo.png", http.StatusFound)
+	}))
+	defer allowed.Close()
+
+	inspector := NewDefaultAssetInspector([]string{"127.0.0.1"}, nil)
+	_, err = inspector.Inspect(context.Background(), allowed.URL+"/external")
+	require.Error(t, err)
+	require.Zero(t, internalHits.Load(), "redirects to hosts that are not allowed are refused")
+
+	info, err := inspector.Inspect(context.Background(), allowed.URL+"/same-host")
+	require.NoError(t, err, "redirects between allowed hosts are followed")
+	require.Equal(t, 4, info.Width)
+}
+
+func TestBrandingValidator_Validate(t *testing.T) {
+	v := NewBrandingValidator(nil, nil)
+	base := &Tokens{Semantic: &SemanticTokens{Colors: map[string]string{"background": "#FFFFFF"}}}
+	ctx := context.Background()
+
+	t.Run("low contrast colour is rejected", func(t *testing.T) {
+		result := v.Validate(ctx, base, &BrandingOverrides{PrimaryColor: "#FFFF00"})
+		require.False(t, result.Valid)
+		require.Equal(t, "branding.primaryColor", result.Issues[0].Path)
+	})
+
+	t.Run("logo dimensions are checked", func(t *testing.T) {
+		result := v.Validate(ctx, base, &BrandingOverrides{
+			PrimaryColor: "#1D4ED8",
+			Logo:         pngDataURI(t, 8, 8),
+			Favicon:      pngDataURI(t, 32, 32),
+		})
+		require.False(t, result.Valid)
+		require.Len(t, result.Issues, 1)
+		require.Equal(t, "branding.logo", result.Issues[0].Path)
+	})
+
+	t.Run("favicon must be square", func(t *testing.T) {
+		result := v.Validate(ctx, base, &BrandingOverrides{Favicon: pngDataURI(t, 32, 16)})
+		require.False(t, result.Valid)
+	})
+
+	t.Run("foreign asset hosts are rejected", func(t *testing.T) {
+		result := v.Validate(ctx, base, &BrandingOverrides{Logo: "https://cdn.example.com/logo.png"})
+		require.False(t, result.Valid)
+	})
+
+	t.Run("valid branding is sanitised", func(t *testing.T) {
+		result := v.Validate(ctx, base, &BrandingOverrides{
+			PrimaryColor: "#1D4ED8",
+			Logo:         pngDataURI(t, 200, 60),
+			CustomCSS:    ".brand { color: #1D4ED8; float: left; }",
+		})
+		require.True(t, result.Valid)
+		require.Equal(t, ".brand { color: #1D4ED8; }", result.Sanitized.CustomCSS)
+	})
+}
+
+func TestTenantManager_BrandingWorkflow(t *testing.T) {
+	ctx := context.Background()
+	config := DefaultManagerConfig()
+	config.EnableValidation = false
+	config.CompilerConfig.EnableCaching = false
+
+	manager, err := NewManager(config, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, manager.RegisterTheme(ctx, newBrandingTestTheme("default")))
+
+	tm := NewTenantManager(manager, NewSimpleTenantStorage())
+	require.NoError(t, tm.ConfigureTenant(ctx, &TenantConfig{TenantID: "acme", DefaultTheme: "default"}))
+
+	t.Run("rejected submission is stored with issues", func(t *testing.T) {
+		submission, err := tm.SubmitBranding(ctx, "acme", "alice", &BrandingOverrides{PrimaryColor: "#EEEEEE"})
+		require.NoError(t, err)
+		require.Equal(t, BrandingStatusRejected, submission.Status)
+		require.NotEmpty(t, submission.Issues)
+
+		_, err = tm.PreviewBranding(ctx, submission.ID)
+		require.True(t, IsValidationError(err))
+	})
+
+	t.Run("preview is scoped and publish is atomic", func(t *testing.T) {
+		submission, err := tm.SubmitBranding(ctx, "acme", "alice", &BrandingOverrides{
+			PrimaryColor: "#1D4ED8",
+			CustomCSS:    ".brand { color: #1D4ED8; }",
+		})
+		require.NoError(t, err)
+		require.Equal(t, BrandingStatusPreview, submission.Status)
+
+		preview, err := tm.PreviewBranding(ctx, submission.ID)
+		require.NoError(t, err)
+		require.Contains(t, preview.CSS, "."+submission.PreviewClass)
+		require.Contains(t, preview.CSS, "."+submission.PreviewClass+" .brand")
+		require.Contains(t, preview.CSS, "#1D4ED8")
+
+		before, err := tm.GetTenantConfig(ctx, "acme")
+		require.NoError(t, err)
+		require.Nil(t, before.Branding)
+
+		published, err := tm.PublishBranding(ctx, submission.ID, "admin")
+		require.NoError(t, err)
+		require.Equal(t, "#1D4ED8", published.Branding.PrimaryColor)
+		require.Nil(t, before.Branding)
+
+		stored, err := tm.GetBrandingSubmission(ctx, submission.ID)
+		require.NoError(t, err)
+		require.Equal(t, BrandingStatusPublished, stored.Status)
+		require.Equal(t, "admin", stored.PublishedBy)
+
+		_, err = tm.PublishBranding(ctx, submission.ID, "admin")
+		require.True(t, IsValidationError(err))
+	})
+
+	t.Run("stale submission cannot be published", func(t *testing.T) {
+		stale, err := tm.SubmitBranding(ctx, "acme", "alice", &BrandingOverrides{PrimaryColor: "#000000"})
+		require.NoError(t, err)
+		fresh, err := tm.SubmitBranding(ctx, "acme", "bob", &BrandingOverrides{PrimaryColor: "#111111"})
+		require.NoError(t, err)
+
+		_, err = tm.PublishBranding(ctx, fresh.ID, "admin")
+		require.NoError(t, err)
+
+		_, err = tm.PublishBranding(ctx, stale.ID, "admin")
+		require.True(t, IsValidationError(err))
+
+		superseded, err := tm.GetBrandingSubmission(ctx, stale.ID)
+		require.NoError(t, err)
+		require.Equal(t, BrandingStatusSuperseded, superseded.Status)
+	})
+
+	t.Run("published custom CSS is scoped like the preview", func(t *testing.T) {
+		submission, err := tm.SubmitBranding(ctx, "acme", "alice", &BrandingOverrides{CustomCSS: "a, .brand { color: #1D4ED8; }"})
+		require.NoError(t, err)
+		_, err = tm.PublishBranding(ctx, submission.ID, "admin")
+		require.NoError(t, err)
+
+		compiled, err := tm.GetTenantTheme(ctx, "acme", nil)
+		require.NoError(t, err)
+		require.Contains(t, compiled.CSS, ".theme-default a, .theme-default .brand { color: #1D4ED8; }")
+		for _, line := range strings.Split(compiled.CSS, "\n") {
+			require.False(t, strings.HasPrefix(line, "a, .brand"), "unscoped tenant CSS: %s", line)
+		}
+	})
+}
+
+func TestTenantManager_SetBrandingValidates(t *testing.T) {
+	ctx := context.Background()
+	config := DefaultManagerConfig()
+	config.EnableValidation = false
+	config.CompilerConfig.EnableCaching = false
+
+	manager, err := NewManager(config, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, manager.RegisterTheme(ctx, newBrandingTestTheme("default")))
+
+	tm := NewTenantManager(manager, NewSimpleTenantStorage())
+	require.NoError(t, tm.ConfigureTenant(ctx, &TenantConfig{TenantID: "acme", DefaultTheme: "default"}))
+
+	err = tm.SetBranding(ctx, "acme", &BrandingOverrides{PrimaryColor: "#EEEEEE"})
+	require.True(t, IsValidationError(err))
+	var themeErr *Error
+	require.ErrorAs(t, err, &themeErr)
+	require.NotEmpty(t, themeErr.Details["issues"])
+
+	err = tm.SetBranding(ctx, "acme", &BrandingOverrides{CustomCSS: `.logo { background: url(https://evil.example/x.png); }`})
+	require.True(t, IsValidationError(err))
+
+	require.NoError(t, tm.SetBranding(ctx, "acme", &BrandingOverrides{CustomCSS: ".brand { color: #1D4ED8; position: fixed; }"}))
+	stored, err := tm.GetTenantConfig(ctx, "acme")
+	require.NoError(t, err)
+	require.Equal(t, ".brand { color: #1D4ED8; }", stored.Branding.CustomCSS)
+
+	err = tm.ConfigureTenant(ctx, &TenantConfig{TenantID: "globex", DefaultTheme: "default", Branding: &BrandingOverrides{SecondaryColor: "#FAFAFA"}})
+	require.True(t, IsValidationError(err))
+}
+
+func TestTenantManager_ConcurrentWriters(t *testing.T) {
+	ctx := context.Background()
+	config := DefaultManagerConfig()
+	config.EnableValidation = false
+
+	manager, err := NewManager(config, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, manager.RegisterTheme(ctx, newBrandingTestTheme("default")))
+
+	tm := NewTenantManager(manager, NewSimpleTenantStorage())
+	require.NoError(t, tm.ConfigureTenant(ctx, &TenantConfig{TenantID: "acme", DefaultTheme: "default"}))
+	submission, err := tm.SubmitBranding(ctx, "acme", "alice", &BrandingOverrides{PrimaryColor: "#1D4ED8"})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.NoError(t, tm.EnableFeature(ctx, "acme", fmt.Sprintf("feature-%d", i)))
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		// The publish either wins or sees the configuration changed
+		_, _ = tm.PublishBranding(ctx, submission.ID, "admin")
+	}()
+	wg.Wait()
+
+	stored, err := tm.GetTenantConfig(ctx, "acme")
+	require.NoError(t, err)
+	require.Len(t, stored.Features, 8)
+}
@@ -0,0 +1,345 @@
+package theme
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ChunkKind classifies a compiled CSS chunk by how pages load it.
+type ChunkKind string
+
+const (
+	// ChunkKindCritical holds the theme variables and base styles every page
+	// needs for first paint; layouts inline it.
+	ChunkKindCritical ChunkKind = "critical"
+	// ChunkKindComponent holds the variables of a single component's tokens.
+	ChunkKindComponent ChunkKind = "component"
+	// ChunkKindOverlay holds dark-mode, high-contrast or right-to-left overrides.
+	ChunkKindOverlay ChunkKind = "overlay"
+	// ChunkKindDeferred holds utilities and custom CSS that can load after first paint.
+	ChunkKindDeferred ChunkKind = "deferred"
+)
+
+// CSSChunk is one independently loadable piece of a compiled theme.
+type CSSChunk struct {
+	Name      string    `json:"name"`
+	Kind      ChunkKind `json:"kind"`
+	Component string    `json:"component,omitempty"`
+	Media     string    `json:"media,omitempty"`
+	Hash      string    `json:"hash"`
+	Filename  string    `json:"filename"`
+	Size      int       `json:"size"`
+	CSS       string    `json:"-"`
+}
+
+// ChunkManifest maps chunk names to content-hashed filenames. Layouts inline
+// CriticalCSS and lazy-load the remaining chunks a page needs.
+type ChunkManifest struct {
+	ThemeID     string               `json:"themeId"`
+	TenantID    string               `json:"tenantId,omitempty"`
+	DarkMode    bool                 `json:"darkMode,omitempty"`
+	CriticalCSS string               `json:"criticalCss"`
+	Chunks      map[string]*CSSChunk `json:"chunks"`
+	GeneratedAt time.Time            `json:"generatedAt"`
+}
+
+// ChunkOptions controls which chunks CompileChunks emits.
+type ChunkOptions struct {
+	// Components restricts component chunks to the listed component names.
+	// An empty list emits a chunk for every component in the tokens.
+	Components []string
+	// HashLength is the number of hex characters of the content hash used in filenames.
+	HashLength int
+}
+
+// DefaultChunkOptions returns options emitting every chunk with 10-character hashes.
+func DefaultChunkOptions() *ChunkOptions {
+	return &ChunkOptions{HashLength: 10}
+}
+
+// ChunkedCSS is the result of splitting a compiled theme into chunks.
+type ChunkedCSS struct {
+	// Theme is the processed theme the chunks were compiled from.
+	Theme    *Theme
+	Chunks   []*CSSChunk
+	Manifest *ChunkManifest
+}
+
+// CSS joins the chunks into a single stylesheet in load order, wrapping
+// chunks that carry a media condition in an @media block.
+func (cc *ChunkedCSS) CSS() string {
+	var buf strings.Builder
+	for _, chunk := range cc.Chunks {
+		if chunk.Media != "" {
+			fmt.Fprintf(&buf, "@media %s {\n%s\n}\n", chunk.Media, chunk.CSS)
+			continue
+		}
+		buf.WriteString(chunk.CSS)
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+// Files returns the chunk contents keyed by filename, ready to be written to a static directory.
+func (cc *ChunkedCSS) Files() map[string]string {
+	files := make(map[string]string, len(cc.Chunks))
+	for _, chunk := range cc.Chunks {
+		files[chunk.Filename] = chunk.CSS
+	}
+	return files
+}
+
+// CompileChunks compiles design tokens into separate critical, component,
+// overlay and deferred CSS chunks with content-hashed filenames.
+func (c *Compiler) CompileChunks(ctx context.Context, tokens *Tokens, theme *Theme, opts *ChunkOptions) (*ChunkedCSS, error) {
+	start := time.Now()
+
+	if err := c.validateInputs(tokens, theme); err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = DefaultChunkOptions()
+	}
+
+	chunks := make([]*CSSChunk, 0)
+	add := func(name string, kind ChunkKind, component, media, css string) {
+		if strings.TrimSpace(css) == "" {
+			return
+		}
+		if c.config.OptimizeOutput {
+			css = c.optimizeCSS(css)
+		}
+		chunks = append(chunks, c.newChunk(theme.ID, name, kind, component, media, css, opts.HashLength))
+	}
+
+	add("critical", ChunkKindCritical, "", "", c.generateCriticalCSS(tokens, theme))
+
+	for _, component := range c.usedComponents(tokens, opts.Components) {
+		add("component-"+component, ChunkKindComponent, component, "", c.generateComponentCSS(tokens, theme, component))
+	}
+
+	if theme.DarkMode != nil && theme.DarkMode.Enabled {
+		var buf bytes.Buffer
+		c.writeDarkMode(&buf, theme)
+		add("dark", ChunkKindOverlay, "", "", buf.String())
+	}
+
+	add("high-contrast", ChunkKindOverlay, "", highContrastMedia, c.generateHighContrastCSS(tokens, theme))
+
+	if c.config.GenerateRTL {
+		var buf bytes.Buffer
+		c.writeDirectionalStyles(&buf, tokens, theme)
+		add("rtl", ChunkKindOverlay, "", "", buf.String())
+	}
+
+	if c.config.GenerateUtilities {
+		var buf bytes.Buffer
+		c.writeUtilityClasses(&buf, tokens)
+		add("utilities", ChunkKindDeferred, "", "", buf.String())
+	}
+
+	if theme.CustomCSS != "" {
+		add("custom", ChunkKindDeferred, "", "", theme.CustomCSS)
+	}
+
+	manifest := &ChunkManifest{
+		ThemeID:     theme.ID,
+		Chunks:      make(map[string]*CSSChunk, len(chunks)),
+		GeneratedAt: time.Now(),
+	}
+	for _, chunk := range chunks {
+		manifest.Chunks[chunk.Name] = chunk
+		if chunk.Kind == ChunkKindCritical {
+			manifest.CriticalCSS = chunk.CSS
+		}
+	}
+
+	c.recordStats(time.Since(start), false)
+
+	return &ChunkedCSS{Theme: theme, Chunks: chunks, Manifest: manifest}, nil
+}
+
+// generateCriticalCSS writes base styles, the theme scope and accessibility rules.
+func (c *Compiler) generateCriticalCSS(tokens *Tokens, theme *Theme) string {
+	var buf bytes.Buffer
+
+	if c.config.IncludeBaseStyles {
+		c.writeBaseStyles(&buf)
+	}
+	c.writeThemeScope(&buf, tokens, theme)
+
+	if theme.Accessibility != nil {
+		buf.WriteString(c.generateAccessibilityCSS(&AccessibilityConfig{
+			FocusIndicator:    theme.Accessibility.FocusIndicator,
+			FocusOutlineColor: theme.Accessibility.FocusOutlineColor,
+			FocusOutlineWidth: theme.Accessibility.FocusOutlineWidth,
+			ReducedMotion:     theme.Accessibility.ReducedMotion,
+		}))
+	}
+
+	return buf.String()
+}
+
+// generateComponentCSS writes the variables of one component's variants inside the theme scope.
+func (c *Compiler) generateComponentCSS(tokens *Tokens, theme *Theme, component string) string {
+	if tokens.Components == nil {
+		return ""
+	}
+	variants, ok := (*tokens.Components)[component]
+	if !ok || len(variants) == 0 {
+		return ""
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s {\n", c.themeSelector(theme))
+
+	variantNames := make([]string, 0, len(variants))
+	for variant := range variants {
+		variantNames = append(variantNames, variant)
+	}
+	sort.Strings(variantNames)
+
+	for _, variant := range variantNames {
+		properties := map[string]string(variants[variant])
+		for _, property := range c.sortedKeys(properties) {
+			for _, varName := range c.componentVariableNames(component, variant, property) {
+				fmt.Fprintf(&buf, "  %s: %s;\n", varName, properties[property])
+			}
+		}
+	}
+
+	buf.WriteString("}\n")
+	return buf.String()
+}
+
+// generateHighContrastCSS writes the high-contrast colour scheme and border rules.
+func (c *Compiler) generateHighContrastCSS(tokens *Tokens, theme *Theme) string {
+	var buf bytes.Buffer
+
+	if tokens.Primitives != nil && tokens.Primitives.Colors != nil {
+		scope := c.themeSelector(theme)
+		var vars bytes.Buffer
+		if primary, exists := tokens.Primitives.Colors["primary.600"]; exists {
+			fmt.Fprintf(&vars, "  --color-primary-500: %s;\n", primary)
+		}
+		if foreground, exists := tokens.Primitives.Colors["gray.950"]; exists {
+			fmt.Fprintf(&vars, "  --color-foreground: %s;\n", foreground)
+		}
+		if vars.Len() > 0 {
+			fmt.Fprintf(&buf, "%s {\n%s}\n", scope, vars.String())
+		}
+	}
+
+	if theme.Accessibility != nil && theme.Accessibility.HighContrast {
+		fmt.Fprintf(&buf, "%s * {\n  border-width: 2px;\n}\n", c.themeSelector(theme))
+	}
+
+	return buf.String()
+}
+
+// usedComponents returns the sorted component names to emit chunks for.
+func (c *Compiler) usedComponents(tokens *Tokens, wanted []string) []string {
+	if tokens.Components == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(*tokens.Components))
+	for name := range *tokens.Components {
+		if len(wanted) == 0 || slices.Contains(wanted, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// themeSelector returns the selector the theme's variables are scoped to.
+func (c *Compiler) themeSelector(theme *Theme) string {
+	if c.config.GenerateThemeClasses {
+		return "." + c.makeThemeClassName(theme.ID)
+	}
+	return ":root"
+}
+
+// newChunk hashes chunk content and derives its filename.
+func (c *Compiler) newChunk(themeID, name string, kind ChunkKind, component, media, css string, hashLength int) *CSSChunk {
+	sum := sha256.Sum256([]byte(css))
+	hash := hex.EncodeToString(sum[:])
+	if hashLength > 0 && hashLength < len(hash) {
+		hash = hash[:hashLength]
+	}
+
+	return &CSSChunk{
+		Name:      name,
+		Kind:      kind,
+		Component: component,
+		Media:     media,
+		Hash:      hash,
+		Filename:  fmt.Sprintf("%s.%s.%s.css", strings.ToLower(themeID), name, hash),
+		Size:      len(css),
+		CSS:       css,
+	}
+}
+
+// Chunk returns a chunk by name.
+func (m *ChunkManifest) Chunk(name string) (*CSSChunk, bool) {
+	chunk, ok := m.Chunks[name]
+	return chunk, ok
+}
+
+// ChunksFor returns the chunks a page using the given components should lazy-load:
+// their component chunks, every overlay and every deferred chunk, in a stable order.
+// The critical chunk is excluded because layouts inline it.
+func (m *ChunkManifest) ChunksFor(components ...string) []*CSSChunk {
+	result := make([]*CSSChunk, 0)
+	for _, chunk := range m.Chunks {
+		switch chunk.Kind {
+		case ChunkKindComponent:
+			if slices.Contains(components, chunk.Component) {
+				result = append(result, chunk)
+			}
+		case ChunkKindOverlay, ChunkKindDeferred:
+			result = append(result, chunk)
+		}
+	}
+
+	order := map[ChunkKind]int{ChunkKindComponent: 0, ChunkKindOverlay: 1, ChunkKindDeferred: 2}
+	sort.Slice(result, func(i, j int) bool {
+		if order[result[i].Kind] != order[result[j].Kind] {
+			return order[result[i].Kind] < order[result[j].Kind]
+		}
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+// SafeInlineCSS prepares CSS for a <style> element. "</" cannot occur in
+// valid CSS outside strings, where "<\\/" is equivalent, so escaping it keeps
+// tenant-influenced CSS from closing the element early.
+func SafeInlineCSS(css string) string {
+	return strings.ReplaceAll(css, "</", `<\/`)
+}
+
+// ToJSON serialises the manifest without chunk contents other than the critical CSS.
+func (m *ChunkManifest) ToJSON() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// ChunkManifestFromJSON parses a manifest written by ToJSON.
+func ChunkManifestFromJSON(data []byte) (*ChunkManifest, error) {
+	var manifest ChunkManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, WrapError(ErrCodeValidation, "invalid chunk manifest", err)
+	}
+	return &manifest, nil
+}
@@ -0,0 +1,158 @@
+package theme
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompiler_CompileChunks(t *testing.T) {
+	ctx := context.Background()
+	config := DefaultCompilerConfig()
+	config.EnableCaching = false
+
+	c, err := NewCompiler(config)
+	require.NoError(t, err)
+
+	theme := GetDefaultTheme()
+	theme.CustomCSS = ".brand { color: red; }"
+	resolver, err := NewResolver(DefaultResolverConfig())
+	require.NoError(t, err)
+	tokens, err := resolver.ResolveAll(ctx, theme, "", false)
+	require.NoError(t, err)
+
+	t.Run("splits critical, component, overlay and deferred chunks", func(t *testing.T) {
+		chunked, err := c.CompileChunks(ctx, tokens, theme, nil)
+		require.NoError(t, err)
+
+		manifest := chunked.Manifest
+		require.NotEmpty(t, manifest.CriticalCSS)
+		require.Contains(t, manifest.CriticalCSS, ".theme-"+strings.ToLower(theme.ID))
+
+		button, ok := manifest.Chunk("component-button")
+		require.True(t, ok)
+		require.Equal(t, ChunkKindComponent, button.Kind)
+		require.Contains(t, button.CSS, "-component-button-primary-background")
+		require.NotContains(t, manifest.CriticalCSS, "-component-button-")
+
+		custom, ok := manifest.Chunk("custom")
+		require.True(t, ok)
+		require.Equal(t, ChunkKindDeferred, custom.Kind)
+
+		for _, chunk := range chunked.Chunks {
+			require.Equal(t, strings.ToLower(theme.ID)+"."+chunk.Name+"."+chunk.Hash+".css", chunk.Filename)
+			require.Len(t, chunk.Hash, 10)
+		}
+		require.Len(t, chunked.Files(), len(chunked.Chunks))
+	})
+
+	t.Run("restricts component chunks to used components", func(t *testing.T) {
+		chunked, err := c.CompileChunks(ctx, tokens, theme, &ChunkOptions{Components: []string{"button"}})
+		require.NoError(t, err)
+
+		for _, chunk := range chunked.Chunks {
+			if chunk.Kind == ChunkKindComponent {
+				require.Equal(t, "button", chunk.Component)
+			}
+		}
+
+		lazy := chunked.Manifest.ChunksFor("button")
+		require.Equal(t, ChunkKindComponent, lazy[0].Kind)
+		for _, chunk := range lazy {
+			require.NotEqual(t, ChunkKindCritical, chunk.Kind)
+		}
+	})
+
+	t.Run("hashes are stable and content addressed", func(t *testing.T) {
+		first, err := c.CompileChunks(ctx, tokens, theme, nil)
+		require.NoError(t, err)
+		second, err := c.CompileChunks(ctx, tokens, theme, nil)
+		require.NoError(t, err)
+
+		for name, chunk := range first.Manifest.Chunks {
+			require.Equal(t, chunk.Filename, second.Manifest.Chunks[name].Filename)
+		}
+
+		changed := theme.Clone()
+		changed.CustomCSS = ".brand { color: blue; }"
+		third, err := c.CompileChunks(ctx, tokens, changed, nil)
+		require.NoError(t, err)
+		require.NotEqual(t, first.Manifest.Chunks["custom"].Hash, third.Manifest.Chunks["custom"].Hash)
+		require.Equal(t, first.Manifest.Chunks["critical"].Hash, third.Manifest.Chunks["critical"].Hash)
+	})
+
+	t.Run("manifest round-trips through JSON", func(t *testing.T) {
+		chunked, err := c.CompileChunks(ctx, tokens, theme, nil)
+		require.NoError(t, err)
+
+		data, err := chunked.Manifest.ToJSON()
+		require.NoError(t, err)
+		parsed, err := ChunkManifestFromJSON(data)
+		require.NoError(t, err)
+		require.Equal(t, chunked.Manifest.CriticalCSS, parsed.CriticalCSS)
+		require.Equal(t, chunked.Manifest.Chunks["critical"].Filename, parsed.Chunks["critical"].Filename)
+	})
+
+	t.Run("high-contrast overlay matches the compiler and stays scoped", func(t *testing.T) {
+		contrast := theme.Clone()
+		contrast.Accessibility = &AccessibilityConfig{HighContrast: true}
+
+		chunked, err := c.CompileChunks(ctx, tokens, contrast, nil)
+		require.NoError(t, err)
+		overlay, ok := chunked.Manifest.Chunk("high-contrast")
+		require.True(t, ok)
+		require.Equal(t, highContrastMedia, overlay.Media)
+		require.Contains(t, overlay.CSS, ".theme-"+strings.ToLower(theme.ID)+" *")
+		require.NotContains(t, strings.ReplaceAll(overlay.CSS, ".theme-"+strings.ToLower(theme.ID)+" *", ""), "*")
+
+		schemes := DefaultCompilerConfig()
+		schemes.EnableCaching = false
+		schemes.EnableMinify = false
+		schemes.GenerateColorSchemes = true
+		full, err := NewCompiler(schemes)
+		require.NoError(t, err)
+		css, err := full.Compile(ctx, tokens, contrast)
+		require.NoError(t, err)
+		require.Contains(t, css, "@media "+overlay.Media)
+	})
+
+	t.Run("joined CSS wraps media chunks", func(t *testing.T) {
+		contrast := theme.Clone()
+		contrast.Accessibility = &AccessibilityConfig{HighContrast: true}
+		chunked, err := c.CompileChunks(ctx, tokens, contrast, nil)
+		require.NoError(t, err)
+
+		css := chunked.CSS()
+		require.True(t, strings.HasPrefix(css, chunked.Manifest.CriticalCSS))
+		require.Contains(t, css, "@media "+highContrastMedia+" {\n"+chunked.Manifest.Chunks["high-contrast"].CSS+"\n}")
+		require.Contains(t, css, chunked.Manifest.Chunks["custom"].CSS)
+	})
+}
+
+func TestSafeInlineCSS(t *testing.T) {
+	css := `.brand { font-family: "</style><script>alert(1)</script>"; }`
+	safe := SafeInlineCSS(css)
+	require.NotContains(t, strings.ToLower(safe), "</style")
+	require.Contains(t, safe, `<\/style>`)
+	require.Equal(t, ".a { color: red; }", SafeInlineCSS(".a { color: red; }"))
+}
+
+func TestGenerateThemeBundle(t *testing.T) {
+	ctx := context.Background()
+	bundle, err := NewAdvancedSetup(ctx, nil, nil, nil)
+	require.NoError(t, err)
+	theme := GetDefaultTheme()
+	require.NoError(t, bundle.Manager.RegisterTheme(ctx, theme))
+
+	stats := bundle.Manager.compiler.GetStats()
+	before := stats.TotalCompilations
+
+	result, err := GenerateThemeBundle(ctx, bundle, theme.ID)
+	require.NoError(t, err)
+	require.Equal(t, before+1, bundle.Manager.compiler.GetStats().TotalCompilations, "the theme is compiled once")
+	require.Equal(t, result.Chunks.CSS(), result.CSS)
+	require.Equal(t, len(result.CSS), result.Metadata["bundleSize"])
+	require.Equal(t, theme.ID, result.Theme.ID)
+}
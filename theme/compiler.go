@@ -14,6 +14,10 @@ import (
 	"github.com/dgraph-io/ristretto"
 )
 
+// highContrastMedia matches users who ask for more contrast: "more" is the
+// standard value and "high" the one early implementations shipped.
+const highContrastMedia = "(prefers-contrast: more), (prefers-contrast: high)"
+
 // Compiler compiles design tokens into optimized CSS with caching and advanced features.
 type Compiler struct {
 	cache      *ristretto.Cache
@@ -41,6 +45,8 @@ type CompilerConfig struct {
 	OptimizeOutput        bool // New: Enable CSS optimizations
 	GenerateColorSchemes  bool // New: Generate color scheme variations
 	IncludeBaseStyles     bool // New: Include base CSS reset/normalization
+	LogicalProperties     bool // Emit flow-relative properties for .mt-/.mb-/.pt-/.pb- and logical component variable names
+	GenerateRTL           bool // Emit [dir="rtl"] overrides and per-script font stacks
 }
 
 // DefaultCompilerConfig returns production-ready compiler configuration.
@@ -61,6 +67,8 @@ func DefaultCompilerConfig() *CompilerConfig {
 		OptimizeOutput:        true,
 		GenerateColorSchemes:  false,
 		IncludeBaseStyles:     false,
+		LogicalProperties:     false,
+		GenerateRTL:           true,
 	}
 }
 
@@ -160,6 +168,9 @@ func (c *Compiler) generateCSS(_ context.Context, tokens *Tokens, theme *Theme)
 		c.writeDarkMode(&buf, theme)
 	}
 
+	// Generate direction overrides
+	c.writeDirectionalStyles(&buf, tokens, theme)
+
 	// Generate utilities
 	if c.config.GenerateUtilities {
 		c.writeUtilityClasses(&buf, tokens)
@@ -302,18 +313,31 @@ func (c *Compiler) writeUtilityClasses(buf *bytes.Buffer, tokens *Tokens) {
 	if tokens.Primitives != nil {
 		// Spacing utilities
 		if tokens.Primitives.Spacing != nil {
-			for key, value := range tokens.Primitives.Spacing {
+			for _, key := range c.sortedKeys(tokens.Primitives.Spacing) {
+				value := tokens.Primitives.Spacing[key]
 				className := strings.ReplaceAll(key, ".", "-")
 				fmt.Fprintf(buf, ".m-%s { margin: %s; }\n", className, value)
 				fmt.Fprintf(buf, ".p-%s { padding: %s; }\n", className, value)
-				fmt.Fprintf(buf, ".mt-%s { margin-top: %s; }\n", className, value)
-				fmt.Fprintf(buf, ".mb-%s { margin-bottom: %s; }\n", className, value)
+				for _, side := range []struct{ class, property string }{
+					{"mt", "margin-top"}, {"mb", "margin-bottom"},
+					{"pt", "padding-top"}, {"pb", "padding-bottom"},
+				} {
+					fmt.Fprintf(buf, ".%s-%s { %s: %s; }\n", side.class, className, c.cssProperty(side.property), value)
+				}
+				// Start and end utilities always follow the writing direction
+				for _, side := range []struct{ class, property string }{
+					{"ms", "margin-left"}, {"me", "margin-right"},
+					{"ps", "padding-left"}, {"pe", "padding-right"},
+				} {
+					fmt.Fprintf(buf, ".%s-%s { %s: %s; }\n", side.class, className, LogicalProperty(side.property), value)
+				}
 			}
 		}
 
 		// Color utilities
 		if tokens.Primitives.Colors != nil {
-			for key, value := range tokens.Primitives.Colors {
+			for _, key := range c.sortedKeys(tokens.Primitives.Colors) {
+				value := tokens.Primitives.Colors[key]
 				className := strings.ReplaceAll(key, ".", "-")
 				fmt.Fprintf(buf, ".text-%s { color: %s; }\n", className, value)
 				fmt.Fprintf(buf, ".bg-%s { background-color: %s; }\n", className, value)
@@ -323,9 +347,27 @@ func (c *Compiler) writeUtilityClasses(buf *bytes.Buffer, tokens *Tokens) {
 
 		// Radius utilities
 		if tokens.Primitives.Radius != nil {
-			for key, value := range tokens.Primitives.Radius {
+			for _, key := range c.sortedKeys(tokens.Primitives.Radius) {
+				value := tokens.Primitives.Radius[key]
 				className := strings.ReplaceAll(key, ".", "-")
 				fmt.Fprintf(buf, ".rounded-%s { border-radius: %s; }\n", className, value)
+				fmt.Fprintf(buf, ".rounded-s-%s { %s: %s; %s: %s; }\n", className,
+					LogicalProperty("border-top-left-radius"), value, LogicalProperty("border-bottom-left-radius"), value)
+				fmt.Fprintf(buf, ".rounded-e-%s { %s: %s; %s: %s; }\n", className,
+					LogicalProperty("border-top-right-radius"), value, LogicalProperty("border-bottom-right-radius"), value)
+			}
+		}
+
+		// Border width utilities
+		if tokens.Primitives.Borders != nil {
+			for _, key := range c.sortedKeys(tokens.Primitives.Borders) {
+				if !strings.HasPrefix(key, "width-") {
+					continue
+				}
+				value := tokens.Primitives.Borders[key]
+				className := strings.TrimPrefix(strings.ReplaceAll(key, ".", "-"), "width-")
+				fmt.Fprintf(buf, ".border-s-%s { %s: %s; }\n", className, LogicalProperty("border-left-width"), value)
+				fmt.Fprintf(buf, ".border-e-%s { %s: %s; }\n", className, LogicalProperty("border-right-width"), value)
 			}
 		}
 	}
@@ -343,7 +385,7 @@ func (c *Compiler) writeColorSchemes(buf *bytes.Buffer, tokens *Tokens, theme *T
 	}
 
 	// Generate high contrast scheme
-	buf.WriteString("@media (prefers-contrast: high) {\n")
+	fmt.Fprintf(buf, "@media %s {\n", highContrastMedia)
 	if c.config.GenerateThemeClasses {
 		themeClass := c.makeThemeClassName(theme.ID)
 		fmt.Fprintf(buf, "  .%s {\n", themeClass)
@@ -440,8 +482,14 @@ func (c *Compiler) writeSemanticVariables(buf *bytes.Buffer, semantic *SemanticT
 		"interactive":      semantic.Interactive,
 	}
 
-	for prefix, tokens := range categories {
-		if tokens != nil {
+	prefixes := make([]string, 0, len(categories))
+	for prefix := range categories {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+
+	for _, prefix := range prefixes {
+		if tokens := categories[prefix]; tokens != nil {
 			c.writeCategoryVariables(buf, prefix, tokens)
 		}
 	}
@@ -537,16 +585,42 @@ func (c *Compiler) generateShadowProperties(buf *bytes.Buffer, tokens *Tokens, t
 		return
 	}
 
-	shadowStyle := c.getThemePreference(theme, "shadowStyle", "")
-
-	for key, value := range tokens.Primitives.Shadows {
-		if shadowStyle == "" || strings.Contains(key, shadowStyle) || shadowStyle == "all" {
+	for _, key := range c.sortedKeys(tokens.Primitives.Shadows) {
+		value := tokens.Primitives.Shadows[key]
+		if c.includeShadow(theme, key) {
 			varName := fmt.Sprintf("--shadow-%s", strings.ReplaceAll(key, ".", "-"))
 			fmt.Fprintf(buf, "  %s: %s;\n", varName, value)
 		}
 	}
 }
 
+// cssProperty returns the property to emit for a physical CSS property,
+// translated to its logical name when logical properties are enabled.
+func (c *Compiler) cssProperty(property string) string {
+	if c.config.LogicalProperties {
+		return LogicalProperty(property)
+	}
+	return property
+}
+
+// componentVariableNames returns the variable names a component property is
+// emitted under. With logical properties enabled the logical name comes
+// first and the original name is kept as an alias.
+func (c *Compiler) componentVariableNames(component, variant, property string) []string {
+	original := c.makeVariableName("component", component+"-"+variant+"-"+property)
+	logical := c.cssProperty(property)
+	if logical == property {
+		return []string{original}
+	}
+	return []string{c.makeVariableName("component", component+"-"+variant+"-"+logical), original}
+}
+
+// includeShadow reports whether a shadow token matches the theme's preferred shadow style.
+func (c *Compiler) includeShadow(theme *Theme, key string) bool {
+	shadowStyle := c.getThemePreference(theme, "shadowStyle", "")
+	return shadowStyle == "" || strings.Contains(key, shadowStyle) || shadowStyle == "all"
+}
+
 // generateChartColors generates chart color variables
 func (c *Compiler) generateChartColors(buf *bytes.Buffer, tokens *Tokens, theme *Theme) {
 	chartColors := map[string]string{
@@ -570,8 +644,8 @@ func (c *Compiler) generateChartColors(buf *bytes.Buffer, tokens *Tokens, theme
 	}
 
 	buf.WriteString("  /* Chart Colors */\n")
-	for key, value := range chartColors {
-		fmt.Fprintf(buf, "  --%s: %s;\n", key, value)
+	for _, key := range c.sortedKeys(chartColors) {
+		fmt.Fprintf(buf, "  --%s: %s;\n", key, chartColors[key])
 	}
 }
 
@@ -590,7 +664,8 @@ func (c *Compiler) generateThemeSpecificProperties(buf *bytes.Buffer, theme *The
 		"hoverScale":   "hover-scale",
 	}
 
-	for themeKey, cssProp := range properties {
+	for _, themeKey := range c.sortedKeys(properties) {
+		cssProp := properties[themeKey]
 		if value := c.getThemePreference(theme, themeKey, ""); value != "" {
 			fmt.Fprintf(buf, "  --%s: %s;\n", cssProp, value)
 		}
@@ -598,8 +673,8 @@ func (c *Compiler) generateThemeSpecificProperties(buf *bytes.Buffer, theme *The
 
 	// Custom properties
 	if customProps := c.getThemeMap(theme, "customProperties"); customProps != nil {
-		for prop, value := range customProps {
-			fmt.Fprintf(buf, "  --%s: %s;\n", prop, value)
+		for _, prop := range c.sortedKeys(customProps) {
+			fmt.Fprintf(buf, "  --%s: %s;\n", prop, customProps[prop])
 		}
 	}
 }
@@ -615,8 +690,8 @@ func (c *Compiler) generateDarkModeCSSProperties(darkTokens *Tokens, theme *Them
 
 	// Dark mode chart colors
 	if darkCharts := c.getThemeMap(theme, "darkChartColors"); darkCharts != nil {
-		for key, value := range darkCharts {
-			buf.WriteString(fmt.Sprintf("  --%s: %s;\n", key, value))
+		for _, key := range c.sortedKeys(darkCharts) {
+			buf.WriteString(fmt.Sprintf("  --%s: %s;\n", key, darkCharts[key]))
 		}
 	}
 
@@ -629,8 +704,8 @@ func (c *Compiler) generateDarkModeCSSProperties(darkTokens *Tokens, theme *Them
 
 	// Dark mode specific properties
 	if darkProps := c.getThemeMap(theme, "darkModeProperties"); darkProps != nil {
-		for prop, value := range darkProps {
-			buf.WriteString(fmt.Sprintf("  --%s: %s;\n", prop, value))
+		for _, prop := range c.sortedKeys(darkProps) {
+			buf.WriteString(fmt.Sprintf("  --%s: %s;\n", prop, darkProps[prop]))
 		}
 	}
 
@@ -784,7 +859,7 @@ func (c *Compiler) generateAccessibilityCSS(config *AccessibilityConfig) string
 	}
 
 	if config.HighContrast {
-		buf.WriteString("@media (prefers-contrast: high) {\n")
+		fmt.Fprintf(&buf, "@media %s {\n", highContrastMedia)
 		buf.WriteString("  * {\n")
 		buf.WriteString("    border-width: 2px;\n")
 		buf.WriteString("  }\n")
@@ -0,0 +1,305 @@
+package theme
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// logicalProperties maps physical CSS property names to their flow-relative
+// equivalents so spacing and borders follow the document direction.
+var logicalProperties = map[string]string{
+	"margin-top":                 "margin-block-start",
+	"margin-bottom":              "margin-block-end",
+	"margin-left":                "margin-inline-start",
+	"margin-right":               "margin-inline-end",
+	"padding-top":                "padding-block-start",
+	"padding-bottom":             "padding-block-end",
+	"padding-left":               "padding-inline-start",
+	"padding-right":              "padding-inline-end",
+	"border-top":                 "border-block-start",
+	"border-bottom":              "border-block-end",
+	"border-left":                "border-inline-start",
+	"border-right":               "border-inline-end",
+	"border-left-width":          "border-inline-start-width",
+	"border-right-width":         "border-inline-end-width",
+	"border-left-color":          "border-inline-start-color",
+	"border-right-color":         "border-inline-end-color",
+	"border-left-style":          "border-inline-start-style",
+	"border-right-style":         "border-inline-end-style",
+	"border-top-left-radius":     "border-start-start-radius",
+	"border-top-right-radius":    "border-start-end-radius",
+	"border-bottom-left-radius":  "border-end-start-radius",
+	"border-bottom-right-radius": "border-end-end-radius",
+	"left":                       "inset-inline-start",
+	"right":                      "inset-inline-end",
+}
+
+// LogicalProperty returns the flow-relative name of a physical CSS property,
+// or the name unchanged when it has no logical equivalent.
+func LogicalProperty(name string) string {
+	if logical, ok := logicalProperties[name]; ok {
+		return logical
+	}
+	return name
+}
+
+// scriptLanguages lists the languages whose text uses each ISO 15924 script.
+// Typography tokens suffixed with a script code, such as "fontFamily.sans.arab",
+// provide the font stack for those languages.
+var scriptLanguages = map[string][]string{
+	"arab": {"ar", "fa", "ur", "ps", "sd", "ug", "ckb"},
+	"hebr": {"he", "iw", "yi"},
+	"thaa": {"dv"},
+}
+
+// fontFamilyTokens lists the typography tokens that hold font stacks and the
+// CSS property each one feeds.
+var fontFamilyTokens = []struct {
+	token    string
+	property string
+}{
+	{"fontFamily.sans", "--font-sans"},
+	{"font-sans", "--font-sans"},
+	{"fontFamily.serif", "--font-serif"},
+	{"font-serif", "--font-serif"},
+	{"fontFamily.mono", "--font-mono"},
+	{"font-mono", "--font-mono"},
+}
+
+// mirroredShorthands lists component properties whose four-value shorthand
+// has distinct left and right values that must swap in right-to-left layouts.
+var mirroredShorthands = []string{"padding", "margin", "border-width", "border-radius", "inset"}
+
+// writeDirectionalStyles writes the right-to-left overrides for asymmetric
+// values, the icon flip rules and the per-script font stacks.
+func (c *Compiler) writeDirectionalStyles(buf *bytes.Buffer, tokens *Tokens, theme *Theme) {
+	if !c.config.GenerateRTL {
+		return
+	}
+
+	if c.config.IncludeComments {
+		buf.WriteString("/* Direction */\n")
+	}
+
+	scopes := c.rtlSelectors(theme)
+
+	var vars bytes.Buffer
+	c.writeMirroredShadows(&vars, tokens, theme)
+	c.writeMirroredComponents(&vars, tokens)
+	if vars.Len() > 0 {
+		fmt.Fprintf(buf, "%s {\n%s}\n", strings.Join(scopes, ", "), vars.String())
+	}
+
+	flips := make([]string, 0, len(scopes)*2)
+	for _, scope := range scopes {
+		flips = append(flips, scope+" .rtl-flip", scope+" .icon-directional")
+	}
+	fmt.Fprintf(buf, "%s {\n  transform: scaleX(-1);\n}\n", strings.Join(flips, ", "))
+
+	c.writeScriptFonts(buf, tokens, theme)
+	buf.WriteString("\n")
+}
+
+// rtlSelectors returns the selectors matching the theme scope inside, or on,
+// a right-to-left element.
+func (c *Compiler) rtlSelectors(theme *Theme) []string {
+	if !c.config.GenerateThemeClasses {
+		return []string{`[dir="rtl"]`}
+	}
+	themeClass := "." + c.makeThemeClassName(theme.ID)
+	return []string{`[dir="rtl"] ` + themeClass, themeClass + `[dir="rtl"]`}
+}
+
+// writeMirroredShadows writes shadow variables with their horizontal offsets negated.
+func (c *Compiler) writeMirroredShadows(buf *bytes.Buffer, tokens *Tokens, theme *Theme) {
+	if tokens.Primitives == nil || tokens.Primitives.Shadows == nil {
+		return
+	}
+
+	for _, key := range c.sortedKeys(tokens.Primitives.Shadows) {
+		value := tokens.Primitives.Shadows[key]
+		mirrored := MirrorShadow(value)
+		if mirrored == value {
+			continue
+		}
+		if c.config.EnableVariables {
+			fmt.Fprintf(buf, "  %s: %s;\n", c.makeVariableName("shadow", key), mirrored)
+		}
+		if c.config.GenerateCSSProperties && c.includeShadow(theme, key) {
+			fmt.Fprintf(buf, "  --shadow-%s: %s;\n", strings.ReplaceAll(key, ".", "-"), mirrored)
+		}
+	}
+}
+
+// writeMirroredComponents writes component variables whose four-value
+// shorthands differ between the left and right sides.
+func (c *Compiler) writeMirroredComponents(buf *bytes.Buffer, tokens *Tokens) {
+	if tokens.Components == nil {
+		return
+	}
+
+	for _, component := range c.usedComponents(tokens, nil) {
+		variants := (*tokens.Components)[component]
+		variantNames := make([]string, 0, len(variants))
+		for variant := range variants {
+			variantNames = append(variantNames, variant)
+		}
+		sort.Strings(variantNames)
+
+		for _, variant := range variantNames {
+			properties := map[string]string(variants[variant])
+			for _, property := range c.sortedKeys(properties) {
+				value := properties[property]
+				mirrored := mirrorShorthand(property, value)
+				if mirrored == value {
+					continue
+				}
+				for _, varName := range c.componentVariableNames(component, variant, property) {
+					fmt.Fprintf(buf, "  %s: %s;\n", varName, mirrored)
+				}
+			}
+		}
+	}
+}
+
+// writeScriptFonts writes font stacks for languages whose script has its own
+// typography tokens.
+func (c *Compiler) writeScriptFonts(buf *bytes.Buffer, tokens *Tokens, theme *Theme) {
+	if tokens.Primitives == nil || tokens.Primitives.Typography == nil {
+		return
+	}
+
+	scripts := make([]string, 0, len(scriptLanguages))
+	for script := range scriptLanguages {
+		scripts = append(scripts, script)
+	}
+	sort.Strings(scripts)
+
+	for _, script := range scripts {
+		var decls bytes.Buffer
+		seen := make(map[string]bool)
+		for _, font := range fontFamilyTokens {
+			stack, ok := tokens.Primitives.Typography[font.token+"."+script]
+			if !ok {
+				continue
+			}
+			if c.config.EnableVariables {
+				fmt.Fprintf(&decls, "  %s: %s;\n", c.makeVariableName("font", font.token), stack)
+			}
+			if !seen[font.property] {
+				seen[font.property] = true
+				fmt.Fprintf(&decls, "  %s: %s;\n", font.property, stack)
+				if font.property == "--font-sans" {
+					decls.WriteString("  font-family: var(--font-sans);\n")
+				}
+			}
+		}
+		if decls.Len() == 0 {
+			continue
+		}
+
+		selectors := make([]string, 0)
+		for _, language := range scriptLanguages[script] {
+			if c.config.GenerateThemeClasses {
+				themeClass := "." + c.makeThemeClassName(theme.ID)
+				selectors = append(selectors, fmt.Sprintf("%s:lang(%s)", themeClass, language), fmt.Sprintf("%s *:lang(%s)", themeClass, language))
+			} else {
+				selectors = append(selectors, fmt.Sprintf(":lang(%s)", language))
+			}
+		}
+		fmt.Fprintf(buf, "%s {\n%s}\n", strings.Join(selectors, ", "), decls.String())
+	}
+}
+
+// MirrorShadow negates the horizontal offset of every layer in a box-shadow
+// value so the shadow falls on the opposite side in right-to-left layouts.
+func MirrorShadow(value string) string {
+	layers := splitTopLevel(value, ',')
+	for i, layer := range layers {
+		parts := splitTopLevel(strings.TrimSpace(layer), ' ')
+		for j, part := range parts {
+			if isCSSLength(part) {
+				parts[j] = negateLength(part)
+				break
+			}
+		}
+		layers[i] = strings.Join(parts, " ")
+	}
+	return strings.Join(layers, ", ")
+}
+
+// mirrorShorthand swaps the left and right values of a four-value shorthand.
+func mirrorShorthand(property, value string) string {
+	matched := false
+	for _, shorthand := range mirroredShorthands {
+		if property == shorthand {
+			matched = true
+			break
+		}
+	}
+	if !matched || strings.Contains(value, "/") {
+		return value
+	}
+
+	parts := splitTopLevel(value, ' ')
+	if len(parts) != 4 {
+		return value
+	}
+	if property == "border-radius" {
+		// top-left top-right bottom-right bottom-left
+		return strings.Join([]string{parts[1], parts[0], parts[3], parts[2]}, " ")
+	}
+	// top right bottom left
+	return strings.Join([]string{parts[0], parts[3], parts[2], parts[1]}, " ")
+}
+
+// splitTopLevel splits s on sep outside parentheses, dropping empty fields.
+func splitTopLevel(s string, sep rune) []string {
+	parts := make([]string, 0)
+	depth := 0
+	start := 0
+	for i, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+			}
+		case sep:
+			if depth == 0 {
+				if field := strings.TrimSpace(s[start:i]); field != "" {
+					parts = append(parts, field)
+				}
+				start = i + 1
+			}
+		}
+	}
+	if field := strings.TrimSpace(s[start:]); field != "" {
+		parts = append(parts, field)
+	}
+	return parts
+}
+
+// isCSSLength reports whether a token is a numeric CSS length such as "0", "-2px" or ".5rem".
+func isCSSLength(token string) bool {
+	trimmed := strings.TrimLeft(token, "+-")
+	return trimmed != "" && (trimmed[0] >= '0' && trimmed[0] <= '9' || trimmed[0] == '.')
+}
+
+// negateLength flips the sign of a CSS length, leaving zero unchanged.
+func negateLength(length string) string {
+	if strings.Trim(length, "0.+-abcdefghijklmnopqrstuvwxyz%") == "" {
+		return length
+	}
+	switch length[0] {
+	case '-':
+		return length[1:]
+	case '+':
+		return "-" + length[1:]
+	default:
+		return "-" + length
+	}
+}
@@ -0,0 +1,171 @@
+package theme
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMirrorShadow(t *testing.T) {
+	require.Equal(t, "-4px 2px 6px rgba(0, 0, 0, 0.1)", MirrorShadow("4px 2px 6px rgba(0, 0, 0, 0.1)"))
+	require.Equal(t, "inset 2px 0 0 #000, -1px 1px 0 red", MirrorShadow("inset -2px 0 0 #000, 1px 1px 0 red"))
+	require.Equal(t, "0 1px 3px 0 rgba(0, 0, 0, 0.1)", MirrorShadow("0 1px 3px 0 rgba(0, 0, 0, 0.1)"))
+	require.Equal(t, "none", MirrorShadow("none"))
+}
+
+func TestMirrorShorthand(t *testing.T) {
+	require.Equal(t, "1px 4px 3px 2px", mirrorShorthand("padding", "1px 2px 3px 4px"))
+	require.Equal(t, "2px 1px 4px 3px", mirrorShorthand("border-radius", "1px 2px 3px 4px"))
+	require.Equal(t, "1px 2px", mirrorShorthand("padding", "1px 2px"))
+	require.Equal(t, "1px 2px 3px 4px", mirrorShorthand("color", "1px 2px 3px 4px"))
+}
+
+func TestCompiler_DirectionalCSS(t *testing.T) {
+	ctx := context.Background()
+	config := DefaultCompilerConfig()
+	config.EnableCaching = false
+	config.EnableMinify = false
+	config.GenerateUtilities = true
+
+	c, err := NewCompiler(config)
+	require.NoError(t, err)
+
+	theme := GetDefaultTheme()
+	resolver, err := NewResolver(DefaultResolverConfig())
+	require.NoError(t, err)
+	tokens, err := resolver.ResolveAll(ctx, theme, "", false)
+	require.NoError(t, err)
+	tokens.Primitives.Shadows["card"] = "4px 4px 8px rgba(0, 0, 0, 0.2)"
+	tokens.Primitives.Typography["font-sans.arab"] = "'Noto Sans Arabic', Tahoma, sans-serif"
+	(*tokens.Components)["button"]["primary"]["padding"] = "0.5rem 1rem 0.5rem 2rem"
+	(*tokens.Components)["button"]["primary"]["margin-left"] = "0.25rem"
+
+	css, err := c.Compile(ctx, tokens, theme)
+	require.NoError(t, err)
+
+	themeClass := ".theme-" + strings.ToLower(theme.ID)
+
+	t.Run("existing utilities and variables keep physical output by default", func(t *testing.T) {
+		require.Contains(t, css, ".mt-4 { margin-top:")
+		require.Contains(t, css, ".mb-4 { margin-bottom:")
+		require.NotContains(t, css, "margin-block-start")
+
+		chunked, err := c.CompileChunks(ctx, tokens, theme, nil)
+		require.NoError(t, err)
+		require.Contains(t, chunked.Manifest.Chunks["component-button"].CSS, "-component-button-primary-margin-left: 0.25rem;")
+	})
+
+	t.Run("start and end utilities use logical properties", func(t *testing.T) {
+		require.Contains(t, css, ".ps-4 { padding-inline-start:")
+		require.Contains(t, css, ".border-s-2 { border-inline-start-width: 2px; }")
+		require.Contains(t, css, "border-start-start-radius")
+	})
+
+	t.Run("logical output is opt-in and keeps aliases", func(t *testing.T) {
+		logical := *config
+		logical.LogicalProperties = true
+		lc, err := NewCompiler(&logical)
+		require.NoError(t, err)
+
+		css, err := lc.Compile(ctx, tokens, theme)
+		require.NoError(t, err)
+		require.Contains(t, css, ".mt-4 { margin-block-start:")
+		require.NotContains(t, css, "margin-top")
+
+		chunked, err := lc.CompileChunks(ctx, tokens, theme, nil)
+		require.NoError(t, err)
+		button := chunked.Manifest.Chunks["component-button"].CSS
+		require.Contains(t, button, "-component-button-primary-margin-inline-start: 0.25rem;")
+		require.Contains(t, button, "-component-button-primary-margin-left: 0.25rem;", "the original name stays as an alias")
+	})
+
+	t.Run("rtl scope mirrors asymmetric values", func(t *testing.T) {
+		require.Contains(t, css, `[dir="rtl"] `+themeClass+", "+themeClass+`[dir="rtl"] {`)
+		require.Contains(t, css, "--shadow-card: -4px 4px 8px rgba(0, 0, 0, 0.2);")
+		require.Contains(t, css, "-component-button-primary-padding: 0.5rem 2rem 0.5rem 1rem;")
+		require.Contains(t, css, `[dir="rtl"] `+themeClass+" .rtl-flip")
+	})
+
+	t.Run("script font stacks are scoped by language", func(t *testing.T) {
+		require.Contains(t, css, themeClass+":lang(ar), "+themeClass+" *:lang(ar)")
+		require.Contains(t, css, "--font-sans: 'Noto Sans Arabic', Tahoma, sans-serif;")
+	})
+
+	t.Run("no rtl overrides when disabled", func(t *testing.T) {
+		physical := *config
+		physical.GenerateRTL = false
+		pc, err := NewCompiler(&physical)
+		require.NoError(t, err)
+
+		css, err := pc.Compile(ctx, tokens, theme)
+		require.NoError(t, err)
+		require.Contains(t, css, ".mt-4 { margin-top:")
+		require.NotContains(t, css, `[dir="rtl"]`)
+	})
+
+	t.Run("rtl overlay chunk", func(t *testing.T) {
+		chunked, err := c.CompileChunks(ctx, tokens, theme, nil)
+		require.NoError(t, err)
+		rtl, ok := chunked.Manifest.Chunk("rtl")
+		require.True(t, ok)
+		require.Equal(t, ChunkKindOverlay, rtl.Kind)
+		require.Contains(t, rtl.CSS, "scaleX(-1)")
+	})
+}
+
+func TestThemeSwitcher_LocaleDirection(t *testing.T) {
+	ctx := context.Background()
+	config := DefaultManagerConfig()
+	config.EnableValidation = false
+
+	manager, err := NewManager(config, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, manager.RegisterTheme(ctx, newTestTheme("default")))
+
+	prefs := NewSimpleUserPreferenceStorage()
+	switcher := NewThemeSwitcher(manager, nil, prefs, nil)
+
+	direction, err := switcher.SetLocale(ctx, "u1", "", "ar-EG")
+	require.NoError(t, err)
+	require.Equal(t, "rtl", direction)
+
+	result, err := switcher.SwitchTheme(ctx, "default", "u1", "")
+	require.NoError(t, err)
+	require.Equal(t, "ar-EG", result.Locale)
+	require.Equal(t, "rtl", result.Direction)
+
+	result, err = switcher.SwitchTheme(WithLocale(ctx, "en-US"), "default", "u1", "")
+	require.NoError(t, err)
+	require.Equal(t, "ltr", result.Direction)
+
+	data, err := switcher.GetAlpineJSData(WithLocale(ctx, "he"))
+	require.NoError(t, err)
+	require.Equal(t, "rtl", data.Direction)
+}
+
+func TestThemeSwitcher_SetLocaleForTenant(t *testing.T) {
+	ctx := context.Background()
+	config := DefaultManagerConfig()
+	config.EnableValidation = false
+
+	manager, err := NewManager(config, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, manager.RegisterTheme(ctx, newTestTheme("default")))
+
+	prefs, err := NewFileUserPreferenceStorage(t.TempDir())
+	require.NoError(t, err)
+	switcher := NewThemeSwitcher(manager, nil, prefs, nil)
+
+	_, err = switcher.SetLocale(ctx, "u1", "acme", "ar-EG")
+	require.NoError(t, err)
+
+	result, err := switcher.SwitchTheme(ctx, "default", "u1", "acme")
+	require.NoError(t, err)
+	require.Equal(t, "ar-EG", result.Locale, "the locale is saved in the tenant's preferences")
+
+	result, err = switcher.SwitchTheme(ctx, "default", "u1", "globex")
+	require.NoError(t, err)
+	require.Empty(t, result.Locale, "other tenants keep their own preferences")
+}
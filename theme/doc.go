@@ -198,6 +198,24 @@
 // compiled, err := manager.GetTheme(ctx, "corporate", evalData)
 // ```
 //
+// Instead of assembling evalData by hand, build the standard evaluation
+// context (tenant, user preferences, locale and direction, feature flags,
+// time and device class). Expressions referencing variables it does not
+// provide are rejected by RegisterTheme.
+//
+// ```go
+// builder := theme.NewEvalContextBuilder(tenantMgr, userPrefs, cfg.Features)
+//
+//	ec, err := builder.Build(ctx, &theme.EvalRequest{
+//	    TenantID:  "acme-corp",
+//	    UserID:    userID,
+//	    Locale:    "ar-SA",
+//	    UserAgent: r.UserAgent(),
+//	})
+//
+// compiled, err := manager.GetThemeForContext(ctx, "corporate", ec)
+// ```
+//
 // ### Validation
 //
 // ```go
@@ -14,6 +14,7 @@ const (
 	ErrCodeCompilation     = "COMPILATION_ERROR"
 	ErrCodeResolution      = "RESOLUTION_ERROR"
 	ErrCodeCondition       = "CONDITION_ERROR"
+	ErrCodeConflict        = "CONFLICT"
 )
 
 type Error struct {
@@ -86,6 +87,7 @@ var (
 	ErrCompilationFailed  = NewError(ErrCodeCompilation, "theme compilation failed")
 	ErrResolutionFailed   = NewError(ErrCodeResolution, "token resolution failed")
 	ErrConditionFailed    = NewError(ErrCodeCondition, "condition evaluation failed")
+	ErrStaleWrite         = NewError(ErrCodeConflict, "stale write rejected")
 )
 
 func IsNotFoundError(err error) bool {
@@ -104,6 +106,14 @@ func IsValidationError(err error) bool {
 	return false
 }
 
+func IsConflictError(err error) bool {
+	var themeErr *Error
+	if errors.As(err, &themeErr) {
+		return themeErr.Code == ErrCodeConflict
+	}
+	return false
+}
+
 func IsCircularReferenceError(err error) bool {
 	var themeErr *Error
 	if errors.As(err, &themeErr) {
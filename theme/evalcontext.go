@@ -0,0 +1,477 @@
+package theme
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/ast"
+	"github.com/expr-lang/expr/parser"
+	"github.com/expr-lang/expr/vm"
+)
+
+// DeviceClass is a coarse classification of the client device.
+type DeviceClass string
+
+const (
+	DeviceDesktop DeviceClass = "desktop"
+	DeviceTablet  DeviceClass = "tablet"
+	DeviceMobile  DeviceClass = "mobile"
+	DeviceTV      DeviceClass = "tv"
+)
+
+// FeatureFlagSource provides global feature flags, such as config.FeatureConfig.
+type FeatureFlagSource interface {
+	Flags() map[string]bool
+}
+
+// EvalContext is the standard input for theme condition expressions. ToMap
+// exposes it to expressions under the variables listed by DefaultEvalVariables.
+type EvalContext struct {
+	TenantID    string
+	Tenant      *TenantConfig
+	UserID      string
+	Preferences *UserPreferences
+	Locale      string
+	Direction   string
+	Features    map[string]bool
+	Time        time.Time
+	Device      DeviceClass
+	Touch       bool
+	DarkMode    bool
+}
+
+// EvalRequest carries the per-request inputs of an evaluation context.
+type EvalRequest struct {
+	TenantID  string
+	UserID    string
+	Locale    string
+	UserAgent string
+	// Device overrides detection from UserAgent when set.
+	Device DeviceClass
+	// Time overrides the builder clock when set.
+	Time time.Time
+	// Location converts the evaluation time to the user's time zone.
+	Location *time.Location
+}
+
+// EvalContextBuilder assembles EvalContexts from tenant configuration, stored
+// user preferences and global feature flags.
+type EvalContextBuilder struct {
+	tenants     *TenantManager
+	preferences UserPreferenceStorage
+	features    FeatureFlagSource
+	clock       func() time.Time
+}
+
+// NewEvalContextBuilder creates a builder. Any dependency may be nil.
+func NewEvalContextBuilder(tenants *TenantManager, preferences UserPreferenceStorage, features FeatureFlagSource) *EvalContextBuilder {
+	return &EvalContextBuilder{
+		tenants:     tenants,
+		preferences: preferences,
+		features:    features,
+		clock:       time.Now,
+	}
+}
+
+// WithClock replaces the clock used when a request has no explicit time.
+func (b *EvalContextBuilder) WithClock(clock func() time.Time) *EvalContextBuilder {
+	b.clock = clock
+	return b
+}
+
+// Build assembles the evaluation context for a request. Missing tenants and
+// preferences are not errors; the corresponding variables fall back to defaults.
+func (b *EvalContextBuilder) Build(ctx context.Context, req *EvalRequest) (*EvalContext, error) {
+	if req == nil {
+		req = &EvalRequest{}
+	}
+
+	tenantID := req.TenantID
+	if tenantID == "" {
+		tenantID = getTenantID(ctx)
+	}
+	locale := req.Locale
+	if locale == "" {
+		locale = getLocale(ctx)
+	}
+
+	ec := &EvalContext{
+		TenantID:  tenantID,
+		UserID:    req.UserID,
+		Locale:    locale,
+		Direction: LocaleDirection(locale),
+		Features:  make(map[string]bool),
+		Time:      req.Time,
+		Device:    req.Device,
+		DarkMode:  getDarkMode(ctx),
+	}
+
+	if ec.Time.IsZero() {
+		ec.Time = b.clock()
+	}
+	if req.Location != nil {
+		ec.Time = ec.Time.In(req.Location)
+	}
+	if ec.Device == "" {
+		ec.Device, ec.Touch = DetectDevice(req.UserAgent)
+	} else {
+		ec.Touch = ec.Device == DeviceMobile || ec.Device == DeviceTablet
+	}
+
+	if b.features != nil {
+		for name, enabled := range b.features.Flags() {
+			ec.Features[name] = enabled
+		}
+	}
+
+	if tenantID != "" && b.tenants != nil {
+		tenant, err := b.tenants.GetTenantConfig(ctx, tenantID)
+		if err != nil && !IsNotFoundError(err) {
+			return nil, err
+		}
+		if tenant != nil {
+			ec.Tenant = tenant
+			for name, enabled := range tenant.Features {
+				ec.Features[name] = enabled
+			}
+		}
+	}
+
+	if req.UserID != "" && b.preferences != nil {
+		prefs, err := b.preferences.LoadPreferences(ctx, req.UserID)
+		if err != nil && !IsNotFoundError(err) {
+			return nil, fmt.Errorf("failed to load preferences: %w", err)
+		}
+		if prefs != nil {
+			ec.Preferences = prefs
+			ec.DarkMode = ec.DarkMode || prefs.DarkMode
+			if ec.Locale == "" && prefs.Locale != "" {
+				ec.Locale = prefs.Locale
+				ec.Direction = LocaleDirection(prefs.Locale)
+			}
+		}
+	}
+
+	return ec, nil
+}
+
+// ToMap converts the context into the data map passed to ConditionEvaluator.
+func (ec *EvalContext) ToMap() map[string]any {
+	language, region := splitLocale(ec.Locale)
+	direction := ec.Direction
+	if direction == "" {
+		direction = LocaleDirection(ec.Locale)
+	}
+
+	tenant := map[string]any{
+		"id":       ec.TenantID,
+		"features": map[string]any{},
+		"custom":   map[string]any{},
+	}
+	if ec.Tenant != nil {
+		tenant["defaultTheme"] = ec.Tenant.DefaultTheme
+		tenant["features"] = boolMapToAny(ec.Tenant.Features)
+		if ec.Tenant.CustomData != nil {
+			tenant["custom"] = ec.Tenant.CustomData
+		}
+	}
+
+	preferences := map[string]any{
+		"darkMode":     ec.DarkMode,
+		"autoDarkMode": false,
+		"currentTheme": "",
+		"customTokens": map[string]any{},
+	}
+	if ec.Preferences != nil {
+		preferences["darkMode"] = ec.Preferences.DarkMode
+		preferences["autoDarkMode"] = ec.Preferences.AutoDarkMode
+		preferences["currentTheme"] = ec.Preferences.CurrentTheme
+		tokens := make(map[string]any, len(ec.Preferences.CustomTokens))
+		for key, value := range ec.Preferences.CustomTokens {
+			tokens[key] = value
+		}
+		preferences["customTokens"] = tokens
+	}
+
+	now := ec.Time
+	return map[string]any{
+		"tenant": tenant,
+		"user": map[string]any{
+			"id":          ec.UserID,
+			"preferences": preferences,
+		},
+		"locale": map[string]any{
+			"code":      ec.Locale,
+			"language":  language,
+			"region":    region,
+			"direction": direction,
+			"rtl":       direction == "rtl",
+		},
+		"features": boolMapToAny(ec.Features),
+		"time": map[string]any{
+			"now":       now,
+			"year":      now.Year(),
+			"month":     int(now.Month()),
+			"day":       now.Day(),
+			"hour":      now.Hour(),
+			"minute":    now.Minute(),
+			"weekday":   strings.ToLower(now.Weekday().String()),
+			"dayOfYear": now.YearDay(),
+			"date":      now.Format("2006-01-02"),
+			"monthDay":  now.Format("01-02"),
+			"timezone":  now.Location().String(),
+			"weekend":   now.Weekday() == time.Saturday || now.Weekday() == time.Sunday,
+		},
+		"device": map[string]any{
+			"class": string(ec.Device),
+			"touch": ec.Touch,
+		},
+		"darkMode": ec.DarkMode,
+	}
+}
+
+// DefaultEvalVariables returns the variable paths EvalContext.ToMap provides.
+// A trailing ".*" admits any key below that path.
+func DefaultEvalVariables() []string {
+	return []string{
+		"tenant.id", "tenant.defaultTheme", "tenant.features.*", "tenant.custom.*",
+		"user.id", "user.preferences.darkMode", "user.preferences.autoDarkMode",
+		"user.preferences.currentTheme", "user.preferences.customTokens.*",
+		"locale.code", "locale.language", "locale.region", "locale.direction", "locale.rtl",
+		"features.*",
+		"time.now", "time.year", "time.month", "time.day", "time.hour", "time.minute",
+		"time.weekday", "time.dayOfYear", "time.date", "time.monthDay", "time.timezone", "time.weekend",
+		"device.class", "device.touch",
+		"darkMode",
+	}
+}
+
+// ExpressionVariables parses an expr-lang expression and returns the dotted
+// variable paths it reads, e.g. "time.hour" or "features.beta".
+func ExpressionVariables(expression string) ([]string, error) {
+	tree, err := parser.Parse(expression)
+	if err != nil {
+		return nil, WrapError(ErrCodeCondition, "failed to parse expression", err)
+	}
+
+	collector := &variableCollector{
+		paths:   make(map[string]bool),
+		callees: make(map[ast.Node]bool),
+		locals:  make(map[string]bool),
+	}
+	ast.Walk(&tree.Node, collector)
+
+	paths := make([]string, 0, len(collector.paths))
+	for path := range collector.paths {
+		covered := false
+		for other := range collector.paths {
+			if strings.HasPrefix(other, path+".") {
+				covered = true
+				break
+			}
+		}
+		if !covered && !collector.locals[strings.SplitN(path, ".", 2)[0]] {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+// variableCollector gathers identifier and member-access paths from an expression tree.
+type variableCollector struct {
+	paths   map[string]bool
+	callees map[ast.Node]bool
+	locals  map[string]bool
+}
+
+// Visit records the path of every identifier or member chain that is not a function callee.
+func (vc *variableCollector) Visit(node *ast.Node) {
+	switch n := (*node).(type) {
+	case *ast.CallNode:
+		vc.callees[n.Callee] = true
+	case *ast.VariableDeclaratorNode:
+		vc.locals[n.Name] = true
+	case *ast.IdentifierNode, *ast.MemberNode:
+		if vc.callees[n] {
+			return
+		}
+		if path, ok := memberPath(n); ok {
+			vc.paths[path] = true
+		}
+	}
+}
+
+// memberPath returns the dotted path of an identifier or a chain of constant member accesses.
+func memberPath(node ast.Node) (string, bool) {
+	switch n := node.(type) {
+	case *ast.IdentifierNode:
+		return n.Value, true
+	case *ast.ChainNode:
+		return memberPath(n.Node)
+	case *ast.MemberNode:
+		property, ok := n.Property.(*ast.StringNode)
+		if !ok || n.Method {
+			return "", false
+		}
+		parent, ok := memberPath(n.Node)
+		if !ok {
+			return "", false
+		}
+		return parent + "." + property.Value, true
+	}
+	return "", false
+}
+
+// UnknownVariables returns the paths of an expression that none of the known
+// variables admit. Parents of known paths, such as "locale", are accepted.
+func UnknownVariables(expression string, known []string) ([]string, error) {
+	paths, err := ExpressionVariables(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	unknown := make([]string, 0)
+	for _, path := range paths {
+		if !variableKnown(path, known) {
+			unknown = append(unknown, path)
+		}
+	}
+	return unknown, nil
+}
+
+// variableKnown reports whether path is admitted by the known variable patterns.
+func variableKnown(path string, known []string) bool {
+	for _, pattern := range known {
+		if prefix, wildcard := strings.CutSuffix(pattern, ".*"); wildcard {
+			if path == prefix || strings.HasPrefix(path, prefix+".") {
+				return true
+			}
+		}
+		if path == pattern || strings.HasPrefix(pattern, path+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// ExprConditionEvaluator evaluates theme conditions with expr-lang, caching
+// compiled programs by expression text.
+type ExprConditionEvaluator struct {
+	programs map[string]*vm.Program
+	mu       sync.RWMutex
+}
+
+// NewExprConditionEvaluator creates an expr-lang based ConditionEvaluator.
+func NewExprConditionEvaluator() *ExprConditionEvaluator {
+	return &ExprConditionEvaluator{
+		programs: make(map[string]*vm.Program),
+	}
+}
+
+// Evaluate compiles (once) and runs a boolean expression against data.
+func (e *ExprConditionEvaluator) Evaluate(ctx context.Context, expression string, data map[string]any) (bool, error) {
+	e.mu.RLock()
+	program, ok := e.programs[expression]
+	e.mu.RUnlock()
+
+	if !ok {
+		compiled, err := expr.Compile(expression, expr.AsBool())
+		if err != nil {
+			return false, WrapError(ErrCodeCondition, "failed to compile expression", err)
+		}
+		e.mu.Lock()
+		e.programs[expression] = compiled
+		e.mu.Unlock()
+		program = compiled
+	}
+
+	result, err := expr.Run(program, data)
+	if err != nil {
+		return false, WrapError(ErrCodeCondition, "failed to evaluate expression", err)
+	}
+
+	matched, _ := result.(bool)
+	return matched, nil
+}
+
+// rtlLanguages lists the primary language subtags written right to left.
+var rtlLanguages = map[string]bool{
+	"ar": true, "he": true, "iw": true, "fa": true, "ur": true, "ps": true,
+	"sd": true, "ug": true, "yi": true, "dv": true, "ckb": true,
+}
+
+// IsRTLLocale reports whether a locale such as "ar", "ar-SA" or "he_IL" is right to left.
+func IsRTLLocale(locale string) bool {
+	language, _ := splitLocale(locale)
+	return rtlLanguages[language]
+}
+
+// rtlLanguageList returns the right-to-left language subtags in sorted order.
+func rtlLanguageList() []string {
+	languages := make([]string, 0, len(rtlLanguages))
+	for language := range rtlLanguages {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// LocaleDirection returns "rtl" or "ltr" for a locale.
+func LocaleDirection(locale string) string {
+	if IsRTLLocale(locale) {
+		return "rtl"
+	}
+	return "ltr"
+}
+
+// splitLocale splits a BCP 47 or POSIX locale into lower-case language and upper-case region.
+func splitLocale(locale string) (string, string) {
+	locale = strings.ReplaceAll(strings.TrimSpace(locale), "_", "-")
+	if locale == "" {
+		return "", ""
+	}
+	parts := strings.Split(locale, "-")
+	language := strings.ToLower(parts[0])
+	region := ""
+	for _, part := range parts[1:] {
+		if len(part) == 2 || (len(part) == 3 && part[0] >= '0' && part[0] <= '9') {
+			region = strings.ToUpper(part)
+			break
+		}
+	}
+	return language, region
+}
+
+// DetectDevice classifies a User-Agent string and reports whether the device is touch-first.
+func DetectDevice(userAgent string) (DeviceClass, bool) {
+	ua := strings.ToLower(userAgent)
+	switch {
+	case ua == "":
+		return DeviceDesktop, false
+	case strings.Contains(ua, "smart-tv") || strings.Contains(ua, "smarttv") ||
+		strings.Contains(ua, "appletv") || strings.Contains(ua, "googletv"):
+		return DeviceTV, false
+	case strings.Contains(ua, "ipad") || strings.Contains(ua, "tablet") ||
+		(strings.Contains(ua, "android") && !strings.Contains(ua, "mobile")):
+		return DeviceTablet, true
+	case strings.Contains(ua, "mobi") || strings.Contains(ua, "iphone") ||
+		strings.Contains(ua, "ipod") || strings.Contains(ua, "windows phone"):
+		return DeviceMobile, true
+	}
+	return DeviceDesktop, false
+}
+
+func boolMapToAny(m map[string]bool) map[string]any {
+	result := make(map[string]any, len(m))
+	for key, value := range m {
+		result[key] = value
+	}
+	return result
+}
@@ -0,0 +1,110 @@
+package theme
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type staticFlags map[string]bool
+
+func (f staticFlags) Flags() map[string]bool { return f }
+
+func TestExpressionVariables(t *testing.T) {
+	paths, err := ExpressionVariables(`time.hour >= 18 && locale.rtl && features["beta"] && user.preferences.darkMode == true`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"features.beta", "locale.rtl", "time.hour", "user.preferences.darkMode"}, paths)
+
+	paths, err = ExpressionVariables(`let h = time.hour; h > 6 && len(tenant.id) > 0`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"tenant.id", "time.hour"}, paths)
+
+	_, err = ExpressionVariables("time.hour >=")
+	require.Error(t, err)
+}
+
+func TestUnknownVariables(t *testing.T) {
+	unknown, err := UnknownVariables(`device.class == "mobile" && weather.sunny && tenant.features.x`, DefaultEvalVariables())
+	require.NoError(t, err)
+	require.Equal(t, []string{"weather.sunny"}, unknown)
+}
+
+func TestEvalContextBuilder_Build(t *testing.T) {
+	ctx := context.Background()
+
+	tenants := NewTenantManager(nil, NewSimpleTenantStorage())
+	require.NoError(t, tenants.storage.SaveTenant(ctx, &TenantConfig{
+		TenantID: "acme",
+		Features: map[string]bool{"beta": true},
+	}))
+
+	prefs := NewSimpleUserPreferenceStorage()
+	require.NoError(t, prefs.SavePreferences(ctx, "u1", &UserPreferences{CurrentTheme: "ocean", DarkMode: true}))
+
+	now := time.Date(2026, time.December, 24, 20, 30, 0, 0, time.UTC)
+	builder := NewEvalContextBuilder(tenants, prefs, staticFlags{"enable_new_dashboard": true, "beta": false}).
+		WithClock(func() time.Time { return now })
+
+	ec, err := builder.Build(ctx, &EvalRequest{
+		TenantID:  "acme",
+		UserID:    "u1",
+		Locale:    "ar_SA",
+		UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) Mobile/15E148",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "rtl", ec.Direction)
+	require.Equal(t, DeviceMobile, ec.Device)
+	require.True(t, ec.DarkMode)
+	require.True(t, ec.Features["beta"], "tenant features override global flags")
+	require.True(t, ec.Features["enable_new_dashboard"])
+
+	data := ec.ToMap()
+	evaluator := NewExprConditionEvaluator()
+	for _, expression := range []string{
+		`locale.rtl && locale.language == "ar" && locale.region == "SA"`,
+		`time.monthDay == "12-24" && time.hour >= 18`,
+		`features.beta && tenant.features.beta`,
+		`user.preferences.darkMode && user.preferences.currentTheme == "ocean"`,
+		`device.class == "mobile" && device.touch`,
+	} {
+		matched, err := evaluator.Evaluate(ctx, expression, data)
+		require.NoError(t, err, expression)
+		require.True(t, matched, expression)
+	}
+}
+
+func TestManager_RegisterThemeValidatesConditionVariables(t *testing.T) {
+	ctx := context.Background()
+	config := DefaultManagerConfig()
+	config.EnableValidation = false
+	require.False(t, config.ValidateConditionVariables, "condition variable checks are opt-in")
+
+	theme := newBrandingTestTheme("seasonal")
+	theme.Conditions = []*Condition{{
+		ID:         "holidays",
+		Expression: `time.monthDay == "12-24" && weather.snow`,
+		Overrides:  map[string]string{"primitives.colors.primary": "#B91C1C"},
+	}}
+
+	lenient, err := NewManager(config, nil, NewExprConditionEvaluator())
+	require.NoError(t, err)
+	require.NoError(t, lenient.RegisterTheme(ctx, theme.Clone()), "existing themes still register by default")
+
+	config.ValidateConditionVariables = true
+	manager, err := NewManager(config, nil, NewExprConditionEvaluator())
+	require.NoError(t, err)
+
+	err = manager.RegisterTheme(ctx, theme)
+	require.True(t, IsValidationError(err))
+	require.Contains(t, err.Error(), "weather.snow")
+
+	theme.Conditions[0].Expression = `time.monthDay == "12-24"`
+	require.NoError(t, manager.RegisterTheme(ctx, theme))
+
+	ec := &EvalContext{Time: time.Date(2026, time.December, 24, 9, 0, 0, 0, time.UTC)}
+	compiled, err := manager.GetThemeForContext(ctx, "seasonal", ec)
+	require.NoError(t, err)
+	require.Equal(t, "#B91C1C", compiled.Theme.Tokens.Primitives.Colors["primary"])
+}
@@ -47,6 +47,12 @@ type ManagerConfig struct {
 	StrictMode         bool
 	MaxThemes          int
 
+	// ValidateConditionVariables rejects themes whose condition expressions
+	// read variables outside ConditionVariables. It is off by default so
+	// themes registered before the evaluation context was defined keep working.
+	ValidateConditionVariables bool
+	ConditionVariables         []string
+
 	ResolverConfig  *ResolverConfig
 	CompilerConfig  *CompilerConfig
 	ValidatorConfig *ValidatorConfig
@@ -62,9 +68,13 @@ func DefaultManagerConfig() *ManagerConfig {
 		AutoCompile:        true,
 		StrictMode:         false,
 		MaxThemes:          100,
-		ResolverConfig:     DefaultResolverConfig(),
-		CompilerConfig:     DefaultCompilerConfig(),
-		ValidatorConfig:    DefaultValidatorConfig(),
+
+		ValidateConditionVariables: false,
+		ConditionVariables:         DefaultEvalVariables(),
+
+		ResolverConfig:  DefaultResolverConfig(),
+		CompilerConfig:  DefaultCompilerConfig(),
+		ValidatorConfig: DefaultValidatorConfig(),
 	}
 }
 
@@ -121,6 +131,10 @@ func (m *Manager) RegisterTheme(ctx context.Context, theme *Theme) error {
 		}
 	}
 
+	if err := m.validateConditionVariables(theme); err != nil {
+		return err
+	}
+
 	m.mu.Lock()
 	if len(m.themes) >= m.config.MaxThemes {
 		m.mu.Unlock()
@@ -145,11 +159,67 @@ func (m *Manager) RegisterTheme(ctx context.Context, theme *Theme) error {
 
 // GetTheme retrieves and optionally compiles a theme with conditional overrides.
 func (m *Manager) GetTheme(ctx context.Context, themeID string, evalData map[string]any) (*CompiledTheme, error) {
-	theme, err := m.loadTheme(ctx, themeID)
+	processedTheme, resolvedTokens, err := m.prepareTheme(ctx, themeID, evalData)
 	if err != nil {
 		return nil, err
 	}
 
+	css, err := m.compiler.Compile(ctx, resolvedTokens, processedTheme)
+	if err != nil {
+		return nil, WrapError(ErrCodeCompilation, "failed to compile CSS", err)
+	}
+
+	return &CompiledTheme{
+		Theme:          processedTheme,
+		ResolvedTokens: resolvedTokens,
+		CSS:            css,
+		DarkMode:       getDarkMode(ctx),
+		TenantID:       getTenantID(ctx),
+		CompiledAt:     time.Now(),
+	}, nil
+}
+
+// GetThemeForContext retrieves a compiled theme, evaluating conditions against
+// a standard evaluation context and honouring its dark mode and tenant.
+func (m *Manager) GetThemeForContext(ctx context.Context, themeID string, ec *EvalContext) (*CompiledTheme, error) {
+	if ec == nil {
+		return m.GetTheme(ctx, themeID, nil)
+	}
+	if ec.TenantID != "" {
+		ctx = WithTenant(ctx, ec.TenantID)
+	}
+	if ec.DarkMode {
+		ctx = WithDarkMode(ctx, true)
+	}
+	return m.GetTheme(ctx, themeID, ec.ToMap())
+}
+
+// GetThemeChunks retrieves a theme and compiles it into critical, component,
+// overlay and deferred CSS chunks described by a manifest.
+func (m *Manager) GetThemeChunks(ctx context.Context, themeID string, evalData map[string]any, opts *ChunkOptions) (*ChunkedCSS, error) {
+	processedTheme, resolvedTokens, err := m.prepareTheme(ctx, themeID, evalData)
+	if err != nil {
+		return nil, err
+	}
+
+	chunked, err := m.compiler.CompileChunks(ctx, resolvedTokens, processedTheme, opts)
+	if err != nil {
+		return nil, WrapError(ErrCodeCompilation, "failed to compile CSS chunks", err)
+	}
+
+	chunked.Manifest.TenantID = getTenantID(ctx)
+	chunked.Manifest.DarkMode = getDarkMode(ctx)
+
+	return chunked, nil
+}
+
+// prepareTheme loads a theme, applies conditional overrides and resolves its tokens.
+func (m *Manager) prepareTheme(ctx context.Context, themeID string, evalData map[string]any) (*Theme, *Tokens, error) {
+	theme, err := m.loadTheme(ctx, themeID)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	tenantID := getTenantID(ctx)
 	darkMode := getDarkMode(ctx)
 
@@ -158,7 +228,7 @@ func (m *Manager) GetTheme(ctx context.Context, themeID string, evalData map[str
 	if m.config.EnableConditionals && len(theme.Conditions) > 0 && m.evaluator != nil {
 		processedTheme, err = m.applyConditionalOverrides(ctx, processedTheme, evalData)
 		if err != nil {
-			return nil, WrapError(ErrCodeCondition, "failed to apply conditional overrides", err)
+			return nil, nil, WrapError(ErrCodeCondition, "failed to apply conditional overrides", err)
 		}
 	}
 
@@ -166,25 +236,13 @@ func (m *Manager) GetTheme(ctx context.Context, themeID string, evalData map[str
 	if m.config.AutoCompile {
 		resolvedTokens, err = m.resolver.ResolveAll(ctx, processedTheme, tenantID, darkMode)
 		if err != nil {
-			return nil, WrapError(ErrCodeResolution, "failed to resolve tokens", err)
+			return nil, nil, WrapError(ErrCodeResolution, "failed to resolve tokens", err)
 		}
 	} else {
 		resolvedTokens = processedTheme.Tokens
 	}
 
-	css, err := m.compiler.Compile(ctx, resolvedTokens, processedTheme)
-	if err != nil {
-		return nil, WrapError(ErrCodeCompilation, "failed to compile CSS", err)
-	}
-
-	return &CompiledTheme{
-		Theme:          processedTheme,
-		ResolvedTokens: resolvedTokens,
-		CSS:            css,
-		DarkMode:       darkMode,
-		TenantID:       tenantID,
-		CompiledAt:     time.Now(),
-	}, nil
+	return processedTheme, resolvedTokens, nil
 }
 
 // UpdateTheme updates an existing theme.
@@ -205,6 +263,10 @@ func (m *Manager) UpdateTheme(ctx context.Context, theme *Theme) error {
 		}
 	}
 
+	if err := m.validateConditionVariables(theme); err != nil {
+		return err
+	}
+
 	theme.SetCreatedAt(existing.CreatedAt())
 	theme.SetUpdatedAt(time.Now())
 
@@ -328,6 +390,36 @@ func (m *Manager) loadTheme(ctx context.Context, themeID string) (*Theme, error)
 	return theme, nil
 }
 
+// validateConditionVariables checks that condition expressions parse and only
+// read variables the evaluation context provides.
+func (m *Manager) validateConditionVariables(theme *Theme) error {
+	if !m.config.ValidateConditionVariables || len(theme.Conditions) == 0 {
+		return nil
+	}
+
+	known := m.config.ConditionVariables
+	if len(known) == 0 {
+		known = DefaultEvalVariables()
+	}
+
+	for _, condition := range theme.Conditions {
+		if condition == nil {
+			continue
+		}
+		unknown, err := UnknownVariables(condition.Expression, known)
+		if err != nil {
+			return WrapError(ErrCodeValidation,
+				fmt.Sprintf("invalid expression in condition %s", condition.ID), err)
+		}
+		if len(unknown) > 0 {
+			return NewErrorf(ErrCodeValidation, "condition %s references unknown variables: %s",
+				condition.ID, strings.Join(unknown, ", ")).WithDetail("unknownVariables", unknown)
+		}
+	}
+
+	return nil
+}
+
 // applyConditionalOverrides applies conditional theme overrides based on runtime context.
 func (m *Manager) applyConditionalOverrides(ctx context.Context, theme *Theme, evalData map[string]any) (*Theme, error) {
 	if len(theme.Conditions) == 0 || m.evaluator == nil {
@@ -568,6 +660,8 @@ type contextKey string
 const (
 	contextKeyTenant   contextKey = "theme:tenant"
 	contextKeyDarkMode contextKey = "theme:darkmode"
+	contextKeyLocale   contextKey = "theme:locale"
+	contextKeyDevice   contextKey = "theme:device"
 )
 
 // WithTenant returns a new context with the tenant ID.
@@ -580,6 +674,11 @@ func WithDarkMode(ctx context.Context, enabled bool) context.Context {
 	return context.WithValue(ctx, contextKeyDarkMode, enabled)
 }
 
+// WithLocale returns a new context with the active locale.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, contextKeyLocale, locale)
+}
+
 // getTenantID retrieves the tenant ID from context.
 func getTenantID(ctx context.Context) string {
 	if tenantID, ok := ctx.Value(contextKeyTenant).(string); ok {
@@ -595,3 +694,11 @@ func getDarkMode(ctx context.Context) bool {
 	}
 	return false
 }
+
+// getLocale retrieves the active locale from context.
+func getLocale(ctx context.Context) string {
+	if locale, ok := ctx.Value(contextKeyLocale).(string); ok {
+		return locale
+	}
+	return ""
+}
@@ -257,29 +257,37 @@ func CompileAndValidateTheme(ctx context.Context, compiler *Compiler, validator
 type ThemeBundle struct {
 	Theme      *Theme            `json:"theme"`
 	CSS        string            `json:"css"`
+	Chunks     *ChunkedCSS       `json:"-"`
+	Manifest   *ChunkManifest    `json:"manifest,omitempty"`
 	Validation *ValidationResult `json:"validation"`
 	Metadata   map[string]any    `json:"metadata"`
 }
 
 // GenerateThemeBundle creates a comprehensive theme bundle
 func GenerateThemeBundle(ctx context.Context, bundle *ThemeSystemBundle, themeID string) (*ThemeBundle, error) {
-	// Get compiled theme
-	compiled, err := bundle.Manager.GetTheme(ctx, themeID, nil)
+	// Compile once into critical, component and overlay chunks; the full
+	// stylesheet is the chunks joined in load order
+	chunks, err := bundle.Manager.GetThemeChunks(ctx, themeID, nil, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get theme: %w", err)
+		return nil, fmt.Errorf("failed to get theme chunks: %w", err)
 	}
+	css := chunks.CSS()
 
 	// Validate theme
-	validation := bundle.Validator.Validate(compiled.Theme)
+	validation := bundle.Validator.Validate(chunks.Theme)
 
 	return &ThemeBundle{
-		Theme:      compiled.Theme,
-		CSS:        compiled.CSS,
+		Theme:      chunks.Theme,
+		CSS:        css,
+		Chunks:     chunks,
+		Manifest:   chunks.Manifest,
 		Validation: validation,
 		Metadata: map[string]any{
 			"packageVersion": Version,
 			"generated":      time.Now(),
-			"bundleSize":     len(compiled.CSS),
+			"bundleSize":     len(css),
+			"criticalSize":   len(chunks.Manifest.CriticalCSS),
+			"chunkCount":     len(chunks.Chunks),
 			"valid":          validation.Valid,
 			"score":          validation.Score,
 		},
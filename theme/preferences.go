@@ -0,0 +1,300 @@
+package theme
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// defaultPreferenceScope is the key segment used for users outside any tenant.
+const defaultPreferenceScope = "_"
+
+// WithDevice returns a new context identifying the device or browser session
+// a preference change originates from.
+func WithDevice(ctx context.Context, deviceID string) context.Context {
+	return context.WithValue(ctx, contextKeyDevice, deviceID)
+}
+
+// getDeviceID retrieves the device ID from context.
+func getDeviceID(ctx context.Context) string {
+	if deviceID, ok := ctx.Value(contextKeyDevice).(string); ok {
+		return deviceID
+	}
+	return ""
+}
+
+// ResolvePreferenceConflict decides whether incoming preferences may replace
+// the stored ones. The later UpdatedAt wins; on equal timestamps the higher
+// device ID wins so every replica converges on the same value. A stale write
+// returns ErrStaleWrite.
+func ResolvePreferenceConflict(current, incoming *UserPreferences) error {
+	if current == nil || incoming == nil {
+		return nil
+	}
+	if incoming.UpdatedAt.Before(current.UpdatedAt) {
+		return ErrStaleWrite
+	}
+	if incoming.UpdatedAt.Equal(current.UpdatedAt) && incoming.DeviceID < current.DeviceID {
+		return ErrStaleWrite
+	}
+	return nil
+}
+
+// preferenceScope returns the tenant segment of a preference key.
+func preferenceScope(ctx context.Context) string {
+	if tenantID := getTenantID(ctx); tenantID != "" {
+		return tenantID
+	}
+	return defaultPreferenceScope
+}
+
+// validatePreferenceKey rejects IDs that cannot be used as key or path segments.
+func validatePreferenceKey(kind, id string) error {
+	if id == "" {
+		return NewErrorf(ErrCodeValidation, "%s ID is required", kind)
+	}
+	if id == "." || id == ".." || strings.ContainsAny(id, `/\:`) {
+		return NewErrorf(ErrCodeValidation, "invalid %s ID %q", kind, id)
+	}
+	return nil
+}
+
+// FileUserPreferenceStorage persists preferences as one JSON file per user
+// under a directory per tenant. Writes are atomic renames.
+type FileUserPreferenceStorage struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewFileUserPreferenceStorage creates file-backed preference storage rooted at dir.
+func NewFileUserPreferenceStorage(dir string) (*FileUserPreferenceStorage, error) {
+	if dir == "" {
+		return nil, NewError(ErrCodeValidation, "preference directory is required")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, WrapError(ErrCodeStorage, "failed to create preference directory", err)
+	}
+	return &FileUserPreferenceStorage{dir: dir}, nil
+}
+
+// SavePreferences writes preferences unless a newer write is already stored.
+func (s *FileUserPreferenceStorage) SavePreferences(ctx context.Context, userID string, prefs *UserPreferences) error {
+	path, err := s.path(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, err := s.read(path)
+	if err != nil && !IsNotFoundError(err) {
+		return err
+	}
+	if err := ResolvePreferenceConflict(current, prefs); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(prefs, "", "  ")
+	if err != nil {
+		return WrapError(ErrCodeStorage, "failed to encode preferences", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return WrapError(ErrCodeStorage, "failed to create tenant directory", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".prefs-*")
+	if err != nil {
+		return WrapError(ErrCodeStorage, "failed to create temporary file", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return WrapError(ErrCodeStorage, "failed to write preferences", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return WrapError(ErrCodeStorage, "failed to write preferences", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return WrapError(ErrCodeStorage, "failed to replace preferences", err)
+	}
+
+	return nil
+}
+
+// LoadPreferences reads preferences, returning defaults for unknown users.
+func (s *FileUserPreferenceStorage) LoadPreferences(ctx context.Context, userID string) (*UserPreferences, error) {
+	path, err := s.path(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prefs, err := s.read(path)
+	if IsNotFoundError(err) {
+		return DefaultUserPreferences(), nil
+	}
+	return prefs, err
+}
+
+// DeletePreferences removes a user's preferences file.
+func (s *FileUserPreferenceStorage) DeletePreferences(ctx context.Context, userID string) error {
+	path, err := s.path(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return WrapError(ErrCodeStorage, "failed to delete preferences", err)
+	}
+	return nil
+}
+
+func (s *FileUserPreferenceStorage) path(ctx context.Context, userID string) (string, error) {
+	scope := preferenceScope(ctx)
+	if err := validatePreferenceKey("tenant", scope); err != nil {
+		return "", err
+	}
+	if err := validatePreferenceKey("user", userID); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.dir, scope, userID+".json"), nil
+}
+
+func (s *FileUserPreferenceStorage) read(path string) (*UserPreferences, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, NewError(ErrCodeNotFound, "preferences not found")
+		}
+		return nil, WrapError(ErrCodeStorage, "failed to read preferences", err)
+	}
+
+	var prefs UserPreferences
+	if err := json.Unmarshal(data, &prefs); err != nil {
+		return nil, WrapError(ErrCodeStorage, "failed to decode preferences", err)
+	}
+	return &prefs, nil
+}
+
+// savePreferencesScript stores preferences in a hash only when the write is
+// not older than the stored one, mirroring ResolvePreferenceConflict.
+var savePreferencesScript = redis.NewScript(`
+local ts = redis.call('HGET', KEYS[1], 'ts')
+if ts then
+  local current = tonumber(ts)
+  local incoming = tonumber(ARGV[2])
+  if incoming < current then
+    return 0
+  end
+  if incoming == current and ARGV[3] < (redis.call('HGET', KEYS[1], 'device') or '') then
+    return 0
+  end
+end
+redis.call('HSET', KEYS[1], 'data', ARGV[1], 'ts', ARGV[2], 'device', ARGV[3])
+if tonumber(ARGV[4]) > 0 then
+  redis.call('PEXPIRE', KEYS[1], ARGV[4])
+end
+return 1
+`)
+
+// RedisUserPreferenceStorage persists preferences in Redis hashes keyed by
+// tenant and user. Conflict resolution runs atomically in a Lua script.
+type RedisUserPreferenceStorage struct {
+	client redis.Cmdable
+	prefix string
+	ttl    time.Duration
+}
+
+// NewRedisUserPreferenceStorage creates Redis-backed preference storage.
+// An empty prefix defaults to "theme:prefs:"; a zero ttl keeps preferences forever.
+func NewRedisUserPreferenceStorage(client redis.Cmdable, prefix string, ttl time.Duration) (*RedisUserPreferenceStorage, error) {
+	if client == nil {
+		return nil, NewError(ErrCodeValidation, "redis client is required")
+	}
+	if prefix == "" {
+		prefix = "theme:prefs:"
+	}
+	return &RedisUserPreferenceStorage{client: client, prefix: prefix, ttl: ttl}, nil
+}
+
+// SavePreferences writes preferences unless a newer write is already stored.
+func (s *RedisUserPreferenceStorage) SavePreferences(ctx context.Context, userID string, prefs *UserPreferences) error {
+	key, err := s.key(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(prefs)
+	if err != nil {
+		return WrapError(ErrCodeStorage, "failed to encode preferences", err)
+	}
+
+	applied, err := savePreferencesScript.Run(ctx, s.client, []string{key},
+		string(data), prefs.UpdatedAt.UnixMilli(), prefs.DeviceID, s.ttl.Milliseconds()).Int()
+	if err != nil {
+		return WrapError(ErrCodeStorage, "failed to save preferences", err)
+	}
+	if applied == 0 {
+		return ErrStaleWrite
+	}
+	return nil
+}
+
+// LoadPreferences reads preferences, returning defaults for unknown users.
+func (s *RedisUserPreferenceStorage) LoadPreferences(ctx context.Context, userID string) (*UserPreferences, error) {
+	key, err := s.key(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.client.HGet(ctx, key, "data").Bytes()
+	if err == redis.Nil {
+		return DefaultUserPreferences(), nil
+	}
+	if err != nil {
+		return nil, WrapError(ErrCodeStorage, "failed to load preferences", err)
+	}
+
+	var prefs UserPreferences
+	if err := json.Unmarshal(data, &prefs); err != nil {
+		return nil, WrapError(ErrCodeStorage, "failed to decode preferences", err)
+	}
+	return &prefs, nil
+}
+
+// DeletePreferences removes a user's preferences.
+func (s *RedisUserPreferenceStorage) DeletePreferences(ctx context.Context, userID string) error {
+	key, err := s.key(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.client.Del(ctx, key).Err(); err != nil {
+		return WrapError(ErrCodeStorage, "failed to delete preferences", err)
+	}
+	return nil
+}
+
+func (s *RedisUserPreferenceStorage) key(ctx context.Context, userID string) (string, error) {
+	scope := preferenceScope(ctx)
+	if err := validatePreferenceKey("tenant", scope); err != nil {
+		return "", err
+	}
+	if err := validatePreferenceKey("user", userID); err != nil {
+		return "", err
+	}
+	return s.prefix + scope + ":" + userID, nil
+}
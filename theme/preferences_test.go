@@ -0,0 +1,280 @@
+package theme
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/go-redis/redismock/v8"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolvePreferenceConflict(t *testing.T) {
+	now := time.Now()
+	current := &UserPreferences{UpdatedAt: now, DeviceID: "b"}
+
+	require.NoError(t, ResolvePreferenceConflict(nil, current))
+	require.NoError(t, ResolvePreferenceConflict(current, &UserPreferences{UpdatedAt: now.Add(time.Second)}))
+	require.NoError(t, ResolvePreferenceConflict(current, &UserPreferences{UpdatedAt: now, DeviceID: "c"}))
+	require.True(t, IsConflictError(ResolvePreferenceConflict(current, &UserPreferences{UpdatedAt: now, DeviceID: "a"})))
+	require.True(t, IsConflictError(ResolvePreferenceConflict(current, &UserPreferences{UpdatedAt: now.Add(-time.Second), DeviceID: "z"})))
+}
+
+func TestFileUserPreferenceStorage(t *testing.T) {
+	storage, err := NewFileUserPreferenceStorage(t.TempDir())
+	require.NoError(t, err)
+
+	acme := WithTenant(context.Background(), "acme")
+	globex := WithTenant(context.Background(), "globex")
+	now := time.Now()
+
+	prefs, err := storage.LoadPreferences(acme, "u1")
+	require.NoError(t, err)
+	require.Equal(t, "default", prefs.CurrentTheme)
+
+	require.NoError(t, storage.SavePreferences(acme, "u1", &UserPreferences{CurrentTheme: "ocean", UpdatedAt: now, DeviceID: "laptop"}))
+	require.NoError(t, storage.SavePreferences(globex, "u1", &UserPreferences{CurrentTheme: "forest", UpdatedAt: now, DeviceID: "laptop"}))
+
+	err = storage.SavePreferences(acme, "u1", &UserPreferences{CurrentTheme: "stale", UpdatedAt: now.Add(-time.Minute), DeviceID: "phone"})
+	require.True(t, IsConflictError(err))
+
+	prefs, err = storage.LoadPreferences(acme, "u1")
+	require.NoError(t, err)
+	require.Equal(t, "ocean", prefs.CurrentTheme)
+
+	prefs, err = storage.LoadPreferences(globex, "u1")
+	require.NoError(t, err)
+	require.Equal(t, "forest", prefs.CurrentTheme)
+
+	require.True(t, IsValidationError(storage.SavePreferences(acme, "../u2", &UserPreferences{})))
+
+	require.NoError(t, storage.DeletePreferences(acme, "u1"))
+	prefs, err = storage.LoadPreferences(acme, "u1")
+	require.NoError(t, err)
+	require.Equal(t, "default", prefs.CurrentTheme)
+}
+
+func TestRedisUserPreferenceStorage(t *testing.T) {
+	client, mock := redismock.NewClientMock()
+	storage, err := NewRedisUserPreferenceStorage(client, "", 0)
+	require.NoError(t, err)
+
+	ctx := WithTenant(context.Background(), "acme")
+	prefs := &UserPreferences{CurrentTheme: "ocean", UpdatedAt: time.UnixMilli(1700000000000), DeviceID: "laptop"}
+	data, err := json.Marshal(prefs)
+	require.NoError(t, err)
+
+	mock.ExpectEvalSha(savePreferencesScript.Hash(), []string{"theme:prefs:acme:u1"}, string(data), int64(1700000000000), "laptop", int64(0)).SetVal(int64(1))
+	require.NoError(t, storage.SavePreferences(ctx, "u1", prefs))
+
+	mock.ExpectEvalSha(savePreferencesScript.Hash(), []string{"theme:prefs:acme:u1"}, string(data), int64(1700000000000), "laptop", int64(0)).SetVal(int64(0))
+	require.True(t, IsConflictError(storage.SavePreferences(ctx, "u1", prefs)))
+
+	mock.ExpectHGet("theme:prefs:acme:u1", "data").SetVal(string(data))
+	loaded, err := storage.LoadPreferences(ctx, "u1")
+	require.NoError(t, err)
+	require.Equal(t, "ocean", loaded.CurrentTheme)
+
+	mock.ExpectHGet("theme:prefs:_:u2", "data").SetErr(redis.Nil)
+	loaded, err = storage.LoadPreferences(context.Background(), "u2")
+	require.NoError(t, err)
+	require.Equal(t, "default", loaded.CurrentTheme)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPreferenceSync_SSE(t *testing.T) {
+	bus := NewMemoryPreferenceEventBus()
+	handler := NewPreferenceSSEHandler(bus, func(r *http.Request) (string, string, string, error) {
+		return r.URL.Query().Get("tenant"), r.URL.Query().Get("user"), DeviceIDFromRequest(r), nil
+	}, time.Minute)
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "?tenant=acme&user=u1&device=phone")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(resp.Body)
+	line, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, ": connected\n", line)
+
+	// Events from the subscribing device itself are not echoed back.
+	require.NoError(t, bus.Publish(context.Background(), &PreferenceEvent{
+		Type:      PreferenceEventDarkModeToggled,
+		TenantID:  "acme",
+		UserID:    "u1",
+		DeviceID:  "phone",
+		DarkMode:  &DarkModeEvent{UserID: "u1", TenantID: "acme", DeviceID: "phone", DarkMode: true},
+		Timestamp: time.Now(),
+	}))
+	require.NoError(t, bus.Publish(context.Background(), &PreferenceEvent{
+		Type:      PreferenceEventThemeSwitched,
+		TenantID:  "acme",
+		UserID:    "u1",
+		DeviceID:  "laptop",
+		Theme:     &ThemeSwitchEvent{UserID: "u1", TenantID: "acme", DeviceID: "laptop", NewTheme: "ocean"},
+		Timestamp: time.Now(),
+	}))
+
+	var lines []string
+	for len(lines) < 3 {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	require.Equal(t, "event: theme_switched", lines[0])
+	require.True(t, strings.HasPrefix(lines[1], "id: "))
+
+	var event PreferenceEvent
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(lines[2], "data: ")), &event))
+	require.Equal(t, "laptop", event.DeviceID)
+	require.Equal(t, "ocean", event.Theme.NewTheme)
+}
+
+func TestThemeSwitcher_PublishesPreferenceEvents(t *testing.T) {
+	ctx := context.Background()
+	config := DefaultManagerConfig()
+	config.EnableValidation = false
+	manager, err := NewManager(config, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, manager.RegisterTheme(ctx, newTestTheme("ocean")))
+
+	bus := NewMemoryPreferenceEventBus()
+	sub, err := bus.Subscribe(ctx, "", "u1")
+	require.NoError(t, err)
+	defer sub.Close()
+
+	storage := NewSimpleUserPreferenceStorage()
+	switcher := NewThemeSwitcher(manager, nil, storage, nil)
+	switcher.AddObserver(NewPreferenceSyncObserver(bus))
+
+	_, err = switcher.SwitchTheme(WithDevice(ctx, "laptop"), "ocean", "u1", "")
+	require.NoError(t, err)
+
+	select {
+	case event := <-sub.C:
+		require.Equal(t, PreferenceEventThemeSwitched, event.Type)
+		require.Equal(t, "laptop", event.DeviceID)
+		require.Equal(t, "ocean", event.Theme.NewTheme)
+	case <-time.After(time.Second):
+		t.Fatal("theme switch was not published")
+	}
+
+	prefs, err := storage.LoadPreferences(ctx, "u1")
+	require.NoError(t, err)
+	require.Equal(t, "laptop", prefs.DeviceID)
+	require.False(t, prefs.UpdatedAt.IsZero())
+}
+
+// staleStorage rejects every write as stale, as a store does when another
+// device saved newer preferences in the meantime.
+type staleStorage struct {
+	*SimpleUserPreferenceStorage
+}
+
+func (s *staleStorage) SavePreferences(context.Context, string, *UserPreferences) error {
+	return ErrStaleWrite
+}
+
+func TestThemeSwitcher_ToggleDarkModeReportsStaleWrite(t *testing.T) {
+	ctx := context.Background()
+	config := DefaultManagerConfig()
+	config.EnableValidation = false
+	manager, err := NewManager(config, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, manager.RegisterTheme(ctx, newTestTheme("default")))
+
+	storage := &staleStorage{NewSimpleUserPreferenceStorage()}
+	switcher := NewThemeSwitcher(manager, nil, storage, nil)
+
+	_, err = switcher.ToggleDarkMode(ctx, "u1", "")
+	require.Error(t, err)
+	require.True(t, IsConflictError(err))
+
+	prefs, err := storage.LoadPreferences(ctx, "u1")
+	require.NoError(t, err)
+	require.False(t, prefs.DarkMode, "the rejected toggle is not applied")
+}
+
+func TestThemeSwitcher_SwitchThemeReportsStaleWrite(t *testing.T) {
+	ctx := context.Background()
+	config := DefaultManagerConfig()
+	config.EnableValidation = false
+	manager, err := NewManager(config, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, manager.RegisterTheme(ctx, newTestTheme("ocean")))
+
+	bus := NewMemoryPreferenceEventBus()
+	sub, err := bus.Subscribe(ctx, "", "u1")
+	require.NoError(t, err)
+	defer sub.Close()
+
+	storage := &staleStorage{NewSimpleUserPreferenceStorage()}
+	switcher := NewThemeSwitcher(manager, nil, storage, nil)
+	switcher.AddObserver(NewPreferenceSyncObserver(bus))
+
+	_, err = switcher.SwitchTheme(ctx, "ocean", "u1", "")
+	require.Error(t, err)
+	require.True(t, IsConflictError(err))
+
+	select {
+	case event := <-sub.C:
+		t.Fatalf("a rejected switch was published: %+v", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestThemeSwitcher_LoadTheme(t *testing.T) {
+	ctx := context.Background()
+	config := DefaultManagerConfig()
+	config.EnableValidation = false
+	manager, err := NewManager(config, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, manager.RegisterTheme(ctx, newTestTheme("ocean")))
+
+	storage := NewSimpleUserPreferenceStorage()
+	switcher := NewThemeSwitcher(manager, nil, storage, nil)
+
+	result, err := switcher.LoadTheme(ctx, "ocean", "")
+	require.NoError(t, err)
+	require.True(t, result.Success)
+	require.Equal(t, "ocean", result.ThemeID)
+	require.NotEmpty(t, result.CSS)
+
+	prefs, err := storage.LoadPreferences(ctx, "u1")
+	require.NoError(t, err)
+	require.Equal(t, "default", prefs.CurrentTheme, "loading a theme does not switch to it")
+
+	script, err := switcher.GenerateAlpineJSComponent(ctx, "")
+	require.NoError(t, err)
+	require.Contains(t, script, "await this.loadTheme(event.theme.newTheme)", "synced switches load the theme's CSS")
+	require.Contains(t, script, `"loadEndpoint":"/api/themes/load"`)
+}
+
+func TestMemoryPreferenceEventBus_ChannelsDoNotCollide(t *testing.T) {
+	ctx := context.Background()
+	bus := NewMemoryPreferenceEventBus()
+	sub, err := bus.Subscribe(ctx, "a", "b:c")
+	require.NoError(t, err)
+	defer sub.Close()
+
+	require.NotEqual(t, preferenceChannel("", "a:b", "c"), preferenceChannel("", "a", "b:c"))
+	require.NoError(t, bus.Publish(ctx, &PreferenceEvent{Type: PreferenceEventThemeSwitched, TenantID: "a:b", UserID: "c"}))
+	select {
+	case event := <-sub.C:
+		t.Fatalf("event delivered to another tenant's user: %+v", event)
+	default:
+	}
+}
@@ -0,0 +1,317 @@
+package theme
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// PreferenceEventType identifies the kind of preference change being synced.
+type PreferenceEventType string
+
+const (
+	PreferenceEventThemeSwitched   PreferenceEventType = "theme_switched"
+	PreferenceEventDarkModeToggled PreferenceEventType = "dark_mode_toggled"
+)
+
+// PreferenceEvent is the envelope published to other sessions of the same
+// user when their theme preferences change.
+type PreferenceEvent struct {
+	Type      PreferenceEventType `json:"type"`
+	TenantID  string              `json:"tenantId,omitempty"`
+	UserID    string              `json:"userId"`
+	DeviceID  string              `json:"deviceId,omitempty"`
+	Theme     *ThemeSwitchEvent   `json:"theme,omitempty"`
+	DarkMode  *DarkModeEvent      `json:"darkMode,omitempty"`
+	Timestamp time.Time           `json:"timestamp"`
+}
+
+// PreferenceEventBus fans preference events out to every subscribed session
+// of a tenant's user.
+type PreferenceEventBus interface {
+	Publish(ctx context.Context, event *PreferenceEvent) error
+	Subscribe(ctx context.Context, tenantID, userID string) (*PreferenceSubscription, error)
+}
+
+// PreferenceSubscription delivers events for one user until closed.
+type PreferenceSubscription struct {
+	C     <-chan *PreferenceEvent
+	close func()
+	once  sync.Once
+}
+
+// Close stops delivery and releases the subscription.
+func (s *PreferenceSubscription) Close() {
+	s.once.Do(s.close)
+}
+
+// preferenceChannel returns the channel name for a tenant's user. The IDs
+// are escaped, ":" included, so no tenant and user pair can name the
+// channel of another.
+func preferenceChannel(prefix, tenantID, userID string) string {
+	if tenantID == "" {
+		tenantID = defaultPreferenceScope
+	}
+	return prefix + url.QueryEscape(tenantID) + ":" + url.QueryEscape(userID)
+}
+
+// MemoryPreferenceEventBus delivers events within a single process.
+type MemoryPreferenceEventBus struct {
+	subscribers map[string]map[chan *PreferenceEvent]struct{}
+	buffer      int
+	mu          sync.RWMutex
+}
+
+// NewMemoryPreferenceEventBus creates an in-process event bus.
+func NewMemoryPreferenceEventBus() *MemoryPreferenceEventBus {
+	return &MemoryPreferenceEventBus{
+		subscribers: make(map[string]map[chan *PreferenceEvent]struct{}),
+		buffer:      16,
+	}
+}
+
+// Publish delivers an event to current subscribers. Slow subscribers whose
+// buffers are full miss the event rather than blocking the publisher.
+func (b *MemoryPreferenceEventBus) Publish(ctx context.Context, event *PreferenceEvent) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subscribers[preferenceChannel("", event.TenantID, event.UserID)] {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+	return nil
+}
+
+// Subscribe registers a subscriber for a tenant's user.
+func (b *MemoryPreferenceEventBus) Subscribe(ctx context.Context, tenantID, userID string) (*PreferenceSubscription, error) {
+	channel := preferenceChannel("", tenantID, userID)
+	ch := make(chan *PreferenceEvent, b.buffer)
+
+	b.mu.Lock()
+	if b.subscribers[channel] == nil {
+		b.subscribers[channel] = make(map[chan *PreferenceEvent]struct{})
+	}
+	b.subscribers[channel][ch] = struct{}{}
+	b.mu.Unlock()
+
+	return &PreferenceSubscription{
+		C: ch,
+		close: func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subscribers[channel], ch)
+			if len(b.subscribers[channel]) == 0 {
+				delete(b.subscribers, channel)
+			}
+			close(ch)
+		},
+	}, nil
+}
+
+// RedisPreferenceEventBus delivers events across instances over Redis pub/sub.
+type RedisPreferenceEventBus struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisPreferenceEventBus creates a Redis-backed event bus. An empty
+// prefix defaults to "theme:prefs:events:".
+func NewRedisPreferenceEventBus(client redis.UniversalClient, prefix string) (*RedisPreferenceEventBus, error) {
+	if client == nil {
+		return nil, NewError(ErrCodeValidation, "redis client is required")
+	}
+	if prefix == "" {
+		prefix = "theme:prefs:events:"
+	}
+	return &RedisPreferenceEventBus{client: client, prefix: prefix}, nil
+}
+
+// Publish sends an event to the user's channel.
+func (b *RedisPreferenceEventBus) Publish(ctx context.Context, event *PreferenceEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return WrapError(ErrCodeStorage, "failed to encode preference event", err)
+	}
+	if err := b.client.Publish(ctx, preferenceChannel(b.prefix, event.TenantID, event.UserID), data).Err(); err != nil {
+		return WrapError(ErrCodeStorage, "failed to publish preference event", err)
+	}
+	return nil
+}
+
+// Subscribe listens on the user's channel. Undecodable messages are skipped.
+func (b *RedisPreferenceEventBus) Subscribe(ctx context.Context, tenantID, userID string) (*PreferenceSubscription, error) {
+	pubsub := b.client.Subscribe(ctx, preferenceChannel(b.prefix, tenantID, userID))
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, WrapError(ErrCodeStorage, "failed to subscribe to preference events", err)
+	}
+
+	out := make(chan *PreferenceEvent, 16)
+	go func() {
+		defer close(out)
+		for msg := range pubsub.Channel() {
+			var event PreferenceEvent
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				continue
+			}
+			select {
+			case out <- &event:
+			default:
+			}
+		}
+	}()
+
+	return &PreferenceSubscription{
+		C:     out,
+		close: func() { pubsub.Close() },
+	}, nil
+}
+
+// PreferenceSyncObserver publishes theme switches and dark-mode toggles so
+// the user's other sessions can follow them.
+type PreferenceSyncObserver struct {
+	bus PreferenceEventBus
+	// ErrorHandler receives publish failures; they are dropped when nil.
+	ErrorHandler func(error)
+}
+
+// NewPreferenceSyncObserver creates an observer publishing to bus.
+func NewPreferenceSyncObserver(bus PreferenceEventBus) *PreferenceSyncObserver {
+	return &PreferenceSyncObserver{bus: bus}
+}
+
+// OnThemeSwitched publishes a theme switch.
+func (o *PreferenceSyncObserver) OnThemeSwitched(ctx context.Context, event *ThemeSwitchEvent) {
+	o.publish(ctx, &PreferenceEvent{
+		Type:      PreferenceEventThemeSwitched,
+		TenantID:  event.TenantID,
+		UserID:    event.UserID,
+		DeviceID:  event.DeviceID,
+		Theme:     event,
+		Timestamp: event.Timestamp,
+	})
+}
+
+// OnDarkModeToggled publishes a dark-mode toggle.
+func (o *PreferenceSyncObserver) OnDarkModeToggled(ctx context.Context, event *DarkModeEvent) {
+	o.publish(ctx, &PreferenceEvent{
+		Type:      PreferenceEventDarkModeToggled,
+		TenantID:  event.TenantID,
+		UserID:    event.UserID,
+		DeviceID:  event.DeviceID,
+		DarkMode:  event,
+		Timestamp: event.Timestamp,
+	})
+}
+
+func (o *PreferenceSyncObserver) publish(ctx context.Context, event *PreferenceEvent) {
+	if err := o.bus.Publish(context.WithoutCancel(ctx), event); err != nil && o.ErrorHandler != nil {
+		o.ErrorHandler(err)
+	}
+}
+
+// PreferenceIdentity resolves the tenant, user and device of an SSE request.
+type PreferenceIdentity func(r *http.Request) (tenantID, userID, deviceID string, err error)
+
+// DeviceIDFromRequest reads the device ID from the X-Device-ID header or the
+// "device" query parameter, which EventSource clients use since they cannot
+// set headers.
+func DeviceIDFromRequest(r *http.Request) string {
+	if deviceID := r.Header.Get("X-Device-ID"); deviceID != "" {
+		return deviceID
+	}
+	return r.URL.Query().Get("device")
+}
+
+// PreferenceSSEHandler streams a user's preference events as server-sent
+// events, skipping events that originated from the requesting device.
+type PreferenceSSEHandler struct {
+	bus       PreferenceEventBus
+	identify  PreferenceIdentity
+	heartbeat time.Duration
+}
+
+// NewPreferenceSSEHandler creates an SSE handler. Heartbeat comments keep
+// idle connections open through proxies; zero uses 25 seconds.
+func NewPreferenceSSEHandler(bus PreferenceEventBus, identify PreferenceIdentity, heartbeat time.Duration) *PreferenceSSEHandler {
+	if heartbeat <= 0 {
+		heartbeat = 25 * time.Second
+	}
+	return &PreferenceSSEHandler{bus: bus, identify: identify, heartbeat: heartbeat}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *PreferenceSSEHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	tenantID, userID, deviceID, err := h.identify(r)
+	if err != nil || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	sub, err := h.bus.Subscribe(r.Context(), tenantID, userID)
+	if err != nil {
+		http.Error(w, "subscription failed", http.StatusServiceUnavailable)
+		return
+	}
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	io.WriteString(w, ": connected\n\n")
+	flusher.Flush()
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case event, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			if deviceID != "" && event.DeviceID == deviceID {
+				continue
+			}
+			if err := WriteSSEEvent(w, event); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// WriteSSEEvent writes a preference event in server-sent event framing.
+func WriteSSEEvent(w io.Writer, event *PreferenceEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\nid: %d\ndata: %s\n\n", event.Type, event.Timestamp.UnixNano(), data)
+	return err
+}
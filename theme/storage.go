@@ -165,3 +165,67 @@ func (s *SimpleTenantStorage) ListTenants(ctx context.Context) ([]*TenantConfig,
 
 	return configs, nil
 }
+
+// BrandingStorage is an interface for branding submission persistence.
+type BrandingStorage interface {
+	GetSubmission(ctx context.Context, submissionID string) (*BrandingSubmission, error)
+	SaveSubmission(ctx context.Context, submission *BrandingSubmission) error
+	ListSubmissions(ctx context.Context, tenantID string) ([]*BrandingSubmission, error)
+}
+
+// SimpleBrandingStorage is an in-memory implementation of BrandingStorage.
+type SimpleBrandingStorage struct {
+	submissions map[string]*BrandingSubmission
+	mu          sync.RWMutex
+}
+
+// NewSimpleBrandingStorage creates a new in-memory branding storage.
+func NewSimpleBrandingStorage() *SimpleBrandingStorage {
+	return &SimpleBrandingStorage{
+		submissions: make(map[string]*BrandingSubmission),
+	}
+}
+
+// GetSubmission retrieves a branding submission from memory.
+func (s *SimpleBrandingStorage) GetSubmission(ctx context.Context, submissionID string) (*BrandingSubmission, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	submission, exists := s.submissions[submissionID]
+	if !exists {
+		return nil, NewErrorf(ErrCodeNotFound, "branding submission not found: %s", submissionID)
+	}
+
+	return submission, nil
+}
+
+// SaveSubmission stores a branding submission in memory.
+func (s *SimpleBrandingStorage) SaveSubmission(ctx context.Context, submission *BrandingSubmission) error {
+	if submission == nil {
+		return NewError(ErrCodeValidation, "branding submission cannot be nil")
+	}
+	if submission.ID == "" {
+		return NewError(ErrCodeValidation, "branding submission ID cannot be empty")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.submissions[submission.ID] = submission
+	return nil
+}
+
+// ListSubmissions returns all branding submissions of a tenant from memory.
+func (s *SimpleBrandingStorage) ListSubmissions(ctx context.Context, tenantID string) ([]*BrandingSubmission, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	submissions := make([]*BrandingSubmission, 0)
+	for _, submission := range s.submissions {
+		if submission.TenantID == tenantID {
+			submissions = append(submissions, submission)
+		}
+	}
+
+	return submissions, nil
+}
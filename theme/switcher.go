@@ -28,6 +28,8 @@ type SwitcherConfig struct {
 	MaxHistory         int           `json:"maxHistory"`
 	StorageKey         string        `json:"storageKey"`
 	CookieExpiry       time.Duration `json:"cookieExpiry"`
+	SyncEndpoint       string        `json:"syncEndpoint,omitempty"` // SSE endpoint streaming changes from other sessions
+	LoadEndpoint       string        `json:"loadEndpoint,omitempty"` // endpoint serving LoadTheme, for following those changes
 }
 
 // UserPreferences stores user theme preferences
@@ -39,6 +41,9 @@ type UserPreferences struct {
 	History      []string          `json:"history,omitempty"`
 	LastSwitched time.Time         `json:"lastSwitched"`
 	TenantID     string            `json:"tenantId,omitempty"`
+	Locale       string            `json:"locale,omitempty"`
+	DeviceID     string            `json:"deviceId,omitempty"`
+	UpdatedAt    time.Time         `json:"updatedAt"`
 }
 
 // UserPreferenceStorage interface for persisting user preferences
@@ -58,6 +63,7 @@ type ThemeSwitchObserver interface {
 type ThemeSwitchEvent struct {
 	UserID        string         `json:"userId"`
 	TenantID      string         `json:"tenantId,omitempty"`
+	DeviceID      string         `json:"deviceId,omitempty"`
 	PreviousTheme string         `json:"previousTheme"`
 	NewTheme      string         `json:"newTheme"`
 	Timestamp     time.Time      `json:"timestamp"`
@@ -68,6 +74,7 @@ type ThemeSwitchEvent struct {
 type DarkModeEvent struct {
 	UserID       string    `json:"userId"`
 	TenantID     string    `json:"tenantId,omitempty"`
+	DeviceID     string    `json:"deviceId,omitempty"`
 	DarkMode     bool      `json:"darkMode"`
 	PreviousMode bool      `json:"previousMode"`
 	Timestamp    time.Time `json:"timestamp"`
@@ -80,6 +87,8 @@ type ThemeSwitchResult struct {
 	CSS           string            `json:"css"`
 	Variables     map[string]string `json:"variables,omitempty"`
 	TransitionCSS string            `json:"transitionCss,omitempty"`
+	Locale        string            `json:"locale,omitempty"`
+	Direction     string            `json:"direction,omitempty"`
 	Error         string            `json:"error,omitempty"`
 	Metadata      map[string]any    `json:"metadata,omitempty"`
 }
@@ -89,6 +98,8 @@ type AlpineJSData struct {
 	CurrentTheme    string            `json:"currentTheme"`
 	AvailableThemes []*ThemeInfo      `json:"availableThemes"`
 	DarkMode        bool              `json:"darkMode"`
+	Locale          string            `json:"locale,omitempty"`
+	Direction       string            `json:"direction"`
 	IsLoading       bool              `json:"isLoading"`
 	Error           string            `json:"error,omitempty"`
 	Preferences     *UserPreferences  `json:"preferences"`
@@ -130,6 +141,8 @@ func DefaultSwitcherConfig() *SwitcherConfig {
 		MaxHistory:         10,
 		StorageKey:         "theme_preferences",
 		CookieExpiry:       30 * 24 * time.Hour, // 30 days
+		SyncEndpoint:       "/api/themes/events",
+		LoadEndpoint:       "/api/themes/load",
 	}
 }
 
@@ -149,6 +162,10 @@ func (ts *ThemeSwitcher) SwitchTheme(ctx context.Context, themeID, userID, tenan
 	ts.mu.Lock()
 	defer ts.mu.Unlock()
 
+	if tenantID != "" {
+		ctx = WithTenant(ctx, tenantID)
+	}
+
 	// Load user preferences
 	prefs, err := ts.loadPreferences(ctx, userID)
 	if err != nil {
@@ -156,17 +173,9 @@ func (ts *ThemeSwitcher) SwitchTheme(ctx context.Context, themeID, userID, tenan
 	}
 
 	previousTheme := prefs.CurrentTheme
+	locale := ts.activeLocale(ctx, prefs)
 
-	// Get theme using new Manager
-	var compiled *CompiledTheme
-	if tenantID != "" && ts.tenantManager != nil {
-		// Multi-tenant mode
-		compiled, err = ts.tenantManager.GetTenantTheme(ctx, tenantID, nil)
-	} else {
-		// Single-tenant mode
-		compiled, err = ts.manager.GetTheme(ctx, themeID, nil)
-	}
-
+	compiled, err := ts.compileTheme(ctx, themeID, tenantID)
 	if err != nil {
 		return &ThemeSwitchResult{
 			Success: false,
@@ -178,12 +187,13 @@ func (ts *ThemeSwitcher) SwitchTheme(ctx context.Context, themeID, userID, tenan
 	prefs.CurrentTheme = themeID
 	prefs.TenantID = tenantID
 	prefs.LastSwitched = time.Now()
+	ts.stampPreferences(ctx, prefs)
 	ts.addToHistory(prefs, themeID)
 
-	// Save preferences
+	// Save preferences; a rejected save is not announced to observers
 	if ts.config.EnablePersistence && ts.storage != nil {
 		if err := ts.storage.SavePreferences(ctx, userID, prefs); err != nil {
-			// TODO:  Log but don't fail
+			return nil, fmt.Errorf("failed to save preferences: %w", err)
 		}
 	}
 
@@ -191,6 +201,7 @@ func (ts *ThemeSwitcher) SwitchTheme(ctx context.Context, themeID, userID, tenan
 	event := &ThemeSwitchEvent{
 		UserID:        userID,
 		TenantID:      tenantID,
+		DeviceID:      prefs.DeviceID,
 		PreviousTheme: previousTheme,
 		NewTheme:      themeID,
 		Timestamp:     time.Now(),
@@ -211,6 +222,8 @@ func (ts *ThemeSwitcher) SwitchTheme(ctx context.Context, themeID, userID, tenan
 		CSS:           compiled.CSS,
 		Variables:     ts.extractVariables(compiled),
 		TransitionCSS: transitionCSS,
+		Locale:        locale,
+		Direction:     LocaleDirection(locale),
 		Metadata: map[string]any{
 			"previousTheme": previousTheme,
 			"timestamp":     event.Timestamp,
@@ -218,11 +231,50 @@ func (ts *ThemeSwitcher) SwitchTheme(ctx context.Context, themeID, userID, tenan
 	}, nil
 }
 
+// LoadTheme compiles a theme without switching to it or saving anything. A
+// session uses it to follow a switch made by another session of its user.
+func (ts *ThemeSwitcher) LoadTheme(ctx context.Context, themeID, tenantID string) (*ThemeSwitchResult, error) {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	if tenantID != "" {
+		ctx = WithTenant(ctx, tenantID)
+	}
+
+	compiled, err := ts.compileTheme(ctx, themeID, tenantID)
+	if err != nil {
+		return &ThemeSwitchResult{
+			Success: false,
+			Error:   err.Error(),
+		}, err
+	}
+
+	var transitionCSS string
+	if ts.config.EnableTransitions {
+		transitionCSS = ts.generateTransitionCSS()
+	}
+
+	locale := getLocale(ctx)
+	return &ThemeSwitchResult{
+		Success:       true,
+		ThemeID:       themeID,
+		CSS:           compiled.CSS,
+		Variables:     ts.extractVariables(compiled),
+		TransitionCSS: transitionCSS,
+		Locale:        locale,
+		Direction:     LocaleDirection(locale),
+	}, nil
+}
+
 // ToggleDarkMode toggles dark mode using conditional theming
 func (ts *ThemeSwitcher) ToggleDarkMode(ctx context.Context, userID, tenantID string) (*ThemeSwitchResult, error) {
 	ts.mu.Lock()
 	defer ts.mu.Unlock()
 
+	if tenantID != "" {
+		ctx = WithTenant(ctx, tenantID)
+	}
+
 	// Load preferences
 	prefs, err := ts.loadPreferences(ctx, userID)
 	if err != nil {
@@ -231,27 +283,37 @@ func (ts *ThemeSwitcher) ToggleDarkMode(ctx context.Context, userID, tenantID st
 
 	previousMode := prefs.DarkMode
 	newMode := !previousMode
+	locale := ts.activeLocale(ctx, prefs)
 
 	// Update preferences
 	prefs.DarkMode = newMode
 	prefs.LastSwitched = time.Now()
+	ts.stampPreferences(ctx, prefs)
 
 	// Save preferences
 	if ts.config.EnablePersistence && ts.storage != nil {
 		if err := ts.storage.SavePreferences(ctx, userID, prefs); err != nil {
-			// TODO: Log but don't fail
+			return nil, fmt.Errorf("failed to save preferences: %w", err)
 		}
 	}
 
 	// Get theme with dark mode via conditional evaluation
-	evalData := map[string]any{
-		"user": map[string]any{
-			"id": userID,
-			"preferences": map[string]any{
-				"darkMode": newMode,
-			},
-		},
+	evalContext := &EvalContext{
+		TenantID:    tenantID,
+		UserID:      userID,
+		Preferences: prefs,
+		Locale:      locale,
+		Direction:   LocaleDirection(locale),
+		Time:        time.Now(),
+		Device:      DeviceDesktop,
+		DarkMode:    newMode,
 	}
+	if tenantID != "" && ts.tenantManager != nil {
+		if tenant, err := ts.tenantManager.GetTenantConfig(ctx, tenantID); err == nil {
+			evalContext.Tenant = tenant
+		}
+	}
+	evalData := evalContext.ToMap()
 
 	var compiled *CompiledTheme
 	if tenantID != "" && ts.tenantManager != nil {
@@ -271,6 +333,7 @@ func (ts *ThemeSwitcher) ToggleDarkMode(ctx context.Context, userID, tenantID st
 	event := &DarkModeEvent{
 		UserID:       userID,
 		TenantID:     tenantID,
+		DeviceID:     prefs.DeviceID,
 		DarkMode:     newMode,
 		PreviousMode: previousMode,
 		Timestamp:    time.Now(),
@@ -284,6 +347,8 @@ func (ts *ThemeSwitcher) ToggleDarkMode(ctx context.Context, userID, tenantID st
 		ThemeID:   prefs.CurrentTheme,
 		CSS:       compiled.CSS,
 		Variables: ts.extractVariables(compiled),
+		Locale:    locale,
+		Direction: LocaleDirection(locale),
 		Metadata: map[string]any{
 			"darkModeChanged": true,
 			"previousMode":    previousMode,
@@ -292,6 +357,33 @@ func (ts *ThemeSwitcher) ToggleDarkMode(ctx context.Context, userID, tenantID st
 	}, nil
 }
 
+// SetLocale stores a user's locale in the tenant's preferences and returns
+// the text direction it implies.
+func (ts *ThemeSwitcher) SetLocale(ctx context.Context, userID, tenantID, locale string) (string, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if tenantID != "" {
+		ctx = WithTenant(ctx, tenantID)
+	}
+
+	prefs, err := ts.loadPreferences(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load preferences: %w", err)
+	}
+
+	prefs.Locale = locale
+	ts.stampPreferences(ctx, prefs)
+
+	if ts.config.EnablePersistence && ts.storage != nil {
+		if err := ts.storage.SavePreferences(ctx, userID, prefs); err != nil {
+			return "", fmt.Errorf("failed to save preferences: %w", err)
+		}
+	}
+
+	return LocaleDirection(locale), nil
+}
+
 // LoadUserPreferences loads preferences for a user
 func (ts *ThemeSwitcher) LoadUserPreferences(ctx context.Context, userID string) (*UserPreferences, error) {
 	return ts.loadPreferences(ctx, userID)
@@ -330,20 +422,28 @@ func (ts *ThemeSwitcher) GetAlpineJSData(ctx context.Context) (*AlpineJSData, er
 		}
 	}
 
+	locale := getLocale(ctx)
+
 	return &AlpineJSData{
 		CurrentTheme:    "default", // Would load from user prefs
 		AvailableThemes: themeInfos,
 		DarkMode:        false, // Would load from user prefs
+		Locale:          locale,
+		Direction:       LocaleDirection(locale),
 		IsLoading:       false,
 		Preferences:     DefaultUserPreferences(),
 		Config: map[string]any{
 			"enableTransitions":  ts.config.EnableTransitions,
 			"transitionDuration": ts.config.TransitionDuration.Milliseconds(),
 			"enableAutoDetect":   ts.config.EnableAutoDetect,
+			"rtlLanguages":       rtlLanguageList(),
+			"syncEndpoint":       ts.config.SyncEndpoint,
+			"loadEndpoint":       ts.config.LoadEndpoint,
 		},
 		Methods: map[string]string{
 			"switchTheme":    "switchTheme(themeId)",
 			"toggleDarkMode": "toggleDarkMode()",
+			"setLocale":      "setLocale(locale)",
 			"resetTheme":     "resetTheme()",
 			"exportTheme":    "exportTheme()",
 		},
@@ -368,9 +468,11 @@ function createThemeSwitcher() {
         ...window.themeData,
         
         init() {
+            this.deviceId = this.getDeviceId();
             this.detectSystemTheme();
             this.loadSavedPreferences();
             this.applyTheme();
+            this.connectSync();
             
             // Listen for system dark mode changes
             if (this.config.enableAutoDetect) {
@@ -391,6 +493,7 @@ function createThemeSwitcher() {
                     method: 'POST',
                     headers: {
                         'Content-Type': 'application/json',
+                        'X-Device-ID': this.deviceId,
                     },
                     body: JSON.stringify({
                         themeId: themeId,
@@ -407,6 +510,7 @@ function createThemeSwitcher() {
                 
                 if (result.success) {
                     this.currentTheme = result.themeId;
+                    this.setDirection(result.locale, result.direction);
                     this.applyThemeCSS(result.css, result.transitionCss);
                     this.savePreferences();
                     this.notifyThemeChanged(result);
@@ -430,6 +534,7 @@ function createThemeSwitcher() {
                     method: 'POST',
                     headers: {
                         'Content-Type': 'application/json',
+                        'X-Device-ID': this.deviceId,
                     },
                     body: JSON.stringify({
                         userId: this.getCurrentUserId(),
@@ -445,6 +550,7 @@ function createThemeSwitcher() {
                 
                 if (result.success) {
                     this.darkMode = !this.darkMode;
+                    this.setDirection(result.locale, result.direction);
                     this.applyThemeCSS(result.css, result.transitionCss);
                     this.savePreferences();
                     this.notifyThemeChanged(result);
@@ -459,6 +565,75 @@ function createThemeSwitcher() {
             }
         },
         
+        connectSync() {
+            if (!this.config.syncEndpoint || !window.EventSource) {
+                return;
+            }
+            const url = this.config.syncEndpoint + '?device=' + encodeURIComponent(this.deviceId);
+            const source = new EventSource(url);
+            source.addEventListener('theme_switched', async (e) => {
+                const event = JSON.parse(e.data);
+                if (await this.loadTheme(event.theme.newTheme)) {
+                    this.notifyThemeChanged(event);
+                }
+            });
+            source.addEventListener('dark_mode_toggled', (e) => {
+                const event = JSON.parse(e.data);
+                this.preferences.autoDarkMode = false;
+                this.setDarkMode(event.darkMode.darkMode);
+                this.savePreferences();
+                this.notifyThemeChanged(event);
+            });
+        },
+        
+        async loadTheme(themeId) {
+            try {
+                const response = await fetch(this.config.loadEndpoint, {
+                    method: 'POST',
+                    headers: {
+                        'Content-Type': 'application/json',
+                        'X-Device-ID': this.deviceId,
+                    },
+                    body: JSON.stringify({
+                        themeId: themeId,
+                        tenantId: this.getCurrentTenantId()
+                    })
+                });
+                
+                if (!response.ok) {
+                    throw new Error('Failed to load theme');
+                }
+                
+                const result = await response.json();
+                
+                if (!result.success) {
+                    throw new Error(result.error || 'Unknown error');
+                }
+                this.currentTheme = result.themeId;
+                this.setDirection(result.locale, result.direction);
+                this.applyThemeCSS(result.css, result.transitionCss);
+                this.savePreferences();
+                return result;
+            } catch (error) {
+                this.error = error.message;
+                console.error('Theme load failed:', error);
+                return null;
+            }
+        },
+        
+        getDeviceId() {
+            try {
+                let id = localStorage.getItem('%s_device');
+                if (!id) {
+                    id = window.crypto?.randomUUID ? crypto.randomUUID() : String(Date.now()) + Math.random().toString(16).slice(2);
+                    localStorage.setItem('%s_device', id);
+                }
+                return id;
+            } catch (error) {
+                return '';
+            }
+        },
+        
         resetTheme() {
             this.switchTheme('default');
             this.setDarkMode(false);
@@ -496,8 +671,26 @@ function createThemeSwitcher() {
             document.documentElement.classList.toggle('dark', enabled);
         },
         
+        setLocale(locale) {
+            this.setDirection(locale, this.config.rtlLanguages.includes(locale.split(/[-_]/)[0].toLowerCase()) ? 'rtl' : 'ltr');
+            this.savePreferences();
+        },
+        
+        setDirection(locale, direction) {
+            if (locale) {
+                this.locale = locale;
+            }
+            if (direction) {
+                this.direction = direction;
+            }
+        },
+        
         applyTheme() {
             document.documentElement.setAttribute('data-theme', this.currentTheme);
+            document.documentElement.setAttribute('dir', this.direction || 'ltr');
+            if (this.locale) {
+                document.documentElement.setAttribute('lang', this.locale);
+            }
             this.setDarkMode(this.darkMode);
         },
         
@@ -534,6 +727,9 @@ function createThemeSwitcher() {
                     this.preferences = { ...this.preferences, ...prefs };
                     this.currentTheme = prefs.currentTheme || this.currentTheme;
                     this.darkMode = prefs.darkMode || this.darkMode;
+                    if (prefs.locale) {
+                        this.setLocale(prefs.locale);
+                    }
                 }
             } catch (error) {
                 console.warn('Failed to load saved theme preferences:', error);
@@ -546,6 +742,7 @@ function createThemeSwitcher() {
                     currentTheme: this.currentTheme,
                     darkMode: this.darkMode,
                     autoDarkMode: this.preferences.autoDarkMode,
+                    locale: this.locale,
                     lastSwitched: new Date().toISOString()
                 };
                 localStorage.setItem('%s', JSON.stringify(prefs));
@@ -597,7 +794,7 @@ function createThemeSwitcher() {
 document.addEventListener('alpine:init', () => {
     Alpine.data('themeSwitcher', createThemeSwitcher);
 });
-`, dataJSON, ts.config.StorageKey, ts.config.StorageKey), nil
+`, dataJSON, ts.config.StorageKey, ts.config.StorageKey, ts.config.StorageKey, ts.config.StorageKey), nil
 }
 
 // AddObserver adds a theme switch observer
@@ -635,6 +832,29 @@ func (ts *ThemeSwitcher) loadPreferences(ctx context.Context, userID string) (*U
 	return prefs, nil
 }
 
+// stampPreferences records when and from which device preferences were last written.
+func (ts *ThemeSwitcher) stampPreferences(ctx context.Context, prefs *UserPreferences) {
+	prefs.UpdatedAt = time.Now()
+	prefs.DeviceID = getDeviceID(ctx)
+}
+
+// activeLocale returns the request locale, falling back to the user's saved locale.
+func (ts *ThemeSwitcher) activeLocale(ctx context.Context, prefs *UserPreferences) string {
+	if locale := getLocale(ctx); locale != "" {
+		return locale
+	}
+	return prefs.Locale
+}
+
+// compileTheme gets a theme using the new Manager: the tenant's theme in
+// multi-tenant mode, otherwise themeID
+func (ts *ThemeSwitcher) compileTheme(ctx context.Context, themeID, tenantID string) (*CompiledTheme, error) {
+	if tenantID != "" && ts.tenantManager != nil {
+		return ts.tenantManager.GetTenantTheme(ctx, tenantID, nil)
+	}
+	return ts.manager.GetTheme(ctx, themeID, nil)
+}
+
 func (ts *ThemeSwitcher) addToHistory(prefs *UserPreferences, themeID string) {
 	// Remove if already exists
 	for i, theme := range prefs.History {
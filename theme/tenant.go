@@ -2,15 +2,26 @@ package theme
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // TenantManager manages tenant-specific theme configurations and isolation.
+// Every method that updates a tenant configuration holds that tenant's write
+// lock across its read-modify-write cycle, so concurrent writers never
+// overwrite each other's changes.
 type TenantManager struct {
-	manager *Manager
-	storage TenantStorage
-	mu      sync.RWMutex
+	manager           *Manager
+	storage           TenantStorage
+	brandingStorage   BrandingStorage
+	brandingValidator *BrandingValidator
+	tenantLocks       sync.Map
+	mu                sync.RWMutex
 }
 
 // TenantConfig contains tenant-specific theme configuration.
@@ -40,12 +51,38 @@ type BrandingOverrides struct {
 // NewTenantManager creates a new tenant manager.
 func NewTenantManager(manager *Manager, storage TenantStorage) *TenantManager {
 	return &TenantManager{
-		manager: manager,
-		storage: storage,
+		manager:           manager,
+		storage:           storage,
+		brandingStorage:   NewSimpleBrandingStorage(),
+		brandingValidator: NewBrandingValidator(nil, nil),
 	}
 }
 
-// ConfigureTenant configures a tenant with specific theme settings.
+// SetBrandingStorage replaces the storage used for branding submissions.
+func (tm *TenantManager) SetBrandingStorage(storage BrandingStorage) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.brandingStorage = storage
+}
+
+// SetBrandingValidator replaces the validator applied to branding submissions.
+func (tm *TenantManager) SetBrandingValidator(validator *BrandingValidator) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.brandingValidator = validator
+}
+
+// lockTenant acquires the write lock of a tenant configuration and returns
+// the function releasing it.
+func (tm *TenantManager) lockTenant(tenantID string) func() {
+	lock, _ := tm.tenantLocks.LoadOrStore(tenantID, &sync.Mutex{})
+	mu := lock.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
+
+// ConfigureTenant configures a tenant with specific theme settings. Branding
+// in the configuration is validated and stored sanitised, as with SetBranding.
 func (tm *TenantManager) ConfigureTenant(ctx context.Context, config *TenantConfig) error {
 	if config == nil {
 		return NewError(ErrCodeValidation, "tenant config cannot be nil")
@@ -62,6 +99,17 @@ func (tm *TenantManager) ConfigureTenant(ctx context.Context, config *TenantConf
 		}
 	}
 
+	if config.Branding != nil {
+		branding, err := tm.validateBranding(ctx, config.TenantID, config.DefaultTheme, config.Branding)
+		if err != nil {
+			return err
+		}
+		config.Branding = branding
+	}
+
+	unlock := tm.lockTenant(config.TenantID)
+	defer unlock()
+
 	now := time.Now()
 	config.UpdatedAt = now
 
@@ -107,8 +155,17 @@ func (tm *TenantManager) GetTenantTheme(ctx context.Context, tenantID string, ev
 			tm.applyCustomTokens(compiled.ResolvedTokens, config.Branding.CustomTokens)
 		}
 
+		// Custom CSS is scoped to the theme class the same way the preview is,
+		// so published tenant CSS cannot restyle the rest of the page.
 		if config.Branding.CustomCSS != "" {
-			compiled.CSS += "\n" + config.Branding.CustomCSS
+			tm.mu.RLock()
+			validator := tm.brandingValidator
+			tm.mu.RUnlock()
+
+			scoped, _ := validator.SanitizeCSS(config.Branding.CustomCSS, "."+tm.manager.compiler.makeThemeClassName(themeID))
+			if scoped != "" {
+				compiled.CSS += "\n" + scoped
+			}
 		}
 	}
 
@@ -174,6 +231,9 @@ func (tm *TenantManager) ListTenantThemes(ctx context.Context, tenantID string)
 
 // SetTenantTheme sets the default theme for a tenant.
 func (tm *TenantManager) SetTenantTheme(ctx context.Context, tenantID, themeID string) error {
+	unlock := tm.lockTenant(tenantID)
+	defer unlock()
+
 	config, err := tm.GetTenantConfig(ctx, tenantID)
 	if err != nil {
 		return err
@@ -209,13 +269,26 @@ func (tm *TenantManager) SetTenantTheme(ctx context.Context, tenantID, themeID s
 	return nil
 }
 
-// SetBranding sets branding overrides for a tenant.
+// SetBranding sets branding overrides for a tenant without the preview step.
+// The overrides pass the same validation as submissions and are stored
+// sanitised; invalid branding fails with a validation error whose "issues"
+// detail lists the problems. A nil branding clears the overrides.
 func (tm *TenantManager) SetBranding(ctx context.Context, tenantID string, branding *BrandingOverrides) error {
+	unlock := tm.lockTenant(tenantID)
+	defer unlock()
+
 	config, err := tm.GetTenantConfig(ctx, tenantID)
 	if err != nil {
 		return err
 	}
 
+	if branding != nil {
+		branding, err = tm.validateBranding(ctx, tenantID, config.DefaultTheme, branding)
+		if err != nil {
+			return err
+		}
+	}
+
 	config.Branding = branding
 	config.UpdatedAt = time.Now()
 
@@ -228,8 +301,326 @@ func (tm *TenantManager) SetBranding(ctx context.Context, tenantID string, brand
 	return nil
 }
 
+// BrandingStatus is the lifecycle state of a branding submission.
+type BrandingStatus string
+
+const (
+	BrandingStatusRejected   BrandingStatus = "rejected"
+	BrandingStatusPreview    BrandingStatus = "preview"
+	BrandingStatusPublished  BrandingStatus = "published"
+	BrandingStatusSuperseded BrandingStatus = "superseded"
+	BrandingStatusDiscarded  BrandingStatus = "discarded"
+)
+
+// BrandingSubmission is a tenant's proposed branding change. Valid submissions
+// carry a preview theme compiled under its own scope class; publishing copies
+// the sanitised overrides into the tenant configuration.
+type BrandingSubmission struct {
+	ID           string
+	TenantID     string
+	ThemeID      string
+	Status       BrandingStatus
+	Submitted    *BrandingOverrides
+	Overrides    *BrandingOverrides
+	Issues       []ValidationIssue
+	Preview      *CompiledTheme
+	PreviewClass string
+	// BaseVersion is the tenant configuration UpdatedAt the submission was
+	// validated against; publishing fails if the configuration changed since.
+	BaseVersion time.Time
+	SubmittedBy string
+	SubmittedAt time.Time
+	PublishedBy string
+	PublishedAt time.Time
+}
+
+// SubmitBranding validates branding overrides for a tenant and, when valid,
+// compiles a scoped preview theme. Rejected submissions are stored with their
+// issues and returned without an error so callers can display them.
+func (tm *TenantManager) SubmitBranding(ctx context.Context, tenantID, submittedBy string, branding *BrandingOverrides) (*BrandingSubmission, error) {
+	config, err := tm.GetTenantConfig(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if config.DefaultTheme == "" {
+		return nil, NewError(ErrCodeValidation, "tenant has no default theme")
+	}
+
+	base, baseTokens, err := tm.brandingBase(ctx, tenantID, config.DefaultTheme)
+	if err != nil {
+		return nil, err
+	}
+
+	tm.mu.RLock()
+	validator := tm.brandingValidator
+	storage := tm.brandingStorage
+	tm.mu.RUnlock()
+
+	result := validator.Validate(ctx, baseTokens, branding)
+
+	submission := &BrandingSubmission{
+		ID:          uuid.NewString(),
+		TenantID:    tenantID,
+		ThemeID:     base.ID,
+		Status:      BrandingStatusRejected,
+		Submitted:   branding,
+		Overrides:   result.Sanitized,
+		Issues:      result.Issues,
+		BaseVersion: config.UpdatedAt,
+		SubmittedBy: submittedBy,
+		SubmittedAt: time.Now(),
+	}
+
+	if result.Valid {
+		if err := tm.compileBrandingPreview(ctx, validator, base, submission); err != nil {
+			return nil, err
+		}
+		submission.Status = BrandingStatusPreview
+	}
+
+	if err := storage.SaveSubmission(ctx, submission); err != nil {
+		return nil, WrapError(ErrCodeStorage, "failed to save branding submission", err)
+	}
+
+	return submission, nil
+}
+
+// brandingBase loads the theme branding is validated against and resolves its tokens.
+func (tm *TenantManager) brandingBase(ctx context.Context, tenantID, themeID string) (*Theme, *Tokens, error) {
+	base, err := tm.manager.loadTheme(ctx, themeID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	baseTokens, err := tm.manager.resolver.ResolveAll(ctx, base, tenantID, false)
+	if err != nil {
+		return nil, nil, WrapError(ErrCodeResolution, "failed to resolve base theme", err)
+	}
+
+	return base, baseTokens, nil
+}
+
+// validateBranding validates branding against the tenant's theme and returns
+// the sanitised overrides. Without a theme the contrast checks are skipped.
+func (tm *TenantManager) validateBranding(ctx context.Context, tenantID, themeID string, branding *BrandingOverrides) (*BrandingOverrides, error) {
+	var baseTokens *Tokens
+	if themeID != "" {
+		var err error
+		if _, baseTokens, err = tm.brandingBase(ctx, tenantID, themeID); err != nil {
+			return nil, err
+		}
+	}
+
+	tm.mu.RLock()
+	validator := tm.brandingValidator
+	tm.mu.RUnlock()
+
+	result := validator.Validate(ctx, baseTokens, branding)
+	if !result.Valid {
+		messages := make([]string, 0, len(result.Issues))
+		for _, issue := range result.Issues {
+			if issue.Severity == "error" {
+				messages = append(messages, issue.Message)
+			}
+		}
+		return nil, NewErrorf(ErrCodeValidation, "invalid branding for tenant %s: %s", tenantID, strings.Join(messages, "; ")).
+			WithDetail("issues", result.Issues)
+	}
+
+	return result.Sanitized, nil
+}
+
+// compileBrandingPreview compiles the base theme with the submission's overrides
+// under a preview-only theme ID, so the CSS is scoped to its own class and never
+// shares cache entries with the live tenant theme.
+func (tm *TenantManager) compileBrandingPreview(ctx context.Context, validator *BrandingValidator, base *Theme, submission *BrandingSubmission) error {
+	preview := tm.applyBrandingOverrides(base, submission.Overrides)
+	preview.ID = fmt.Sprintf("%s-preview-%s", base.ID, submission.ID[:8])
+	preview.Name = fmt.Sprintf("%s (preview)", base.Name)
+
+	if len(submission.Overrides.CustomTokens) > 0 {
+		if err := tm.manager.applyOverrides(preview, submission.Overrides.CustomTokens); err != nil {
+			return WrapError(ErrCodeValidation, "failed to apply custom tokens", err)
+		}
+	}
+
+	previewClass := tm.manager.compiler.makeThemeClassName(preview.ID)
+	if submission.Overrides.CustomCSS != "" {
+		scoped, _ := validator.SanitizeCSS(submission.Overrides.CustomCSS, "."+previewClass)
+		if preview.CustomCSS != "" {
+			preview.CustomCSS += "\n"
+		}
+		preview.CustomCSS += scoped
+	}
+
+	resolved, err := tm.manager.resolver.ResolveAll(ctx, preview, submission.TenantID, false)
+	if err != nil {
+		return WrapError(ErrCodeResolution, "failed to resolve preview tokens", err)
+	}
+
+	css, err := tm.manager.compiler.Compile(ctx, resolved, preview)
+	if err != nil {
+		return WrapError(ErrCodeCompilation, "failed to compile preview theme", err)
+	}
+
+	submission.PreviewClass = previewClass
+	submission.Preview = &CompiledTheme{
+		Theme:          preview,
+		ResolvedTokens: resolved,
+		CSS:            css,
+		TenantID:       submission.TenantID,
+		CompiledAt:     time.Now(),
+	}
+
+	return nil
+}
+
+// GetBrandingSubmission retrieves a branding submission.
+func (tm *TenantManager) GetBrandingSubmission(ctx context.Context, submissionID string) (*BrandingSubmission, error) {
+	tm.mu.RLock()
+	storage := tm.brandingStorage
+	tm.mu.RUnlock()
+
+	return storage.GetSubmission(ctx, submissionID)
+}
+
+// ListBrandingSubmissions returns a tenant's branding submissions, newest first.
+func (tm *TenantManager) ListBrandingSubmissions(ctx context.Context, tenantID string) ([]*BrandingSubmission, error) {
+	tm.mu.RLock()
+	storage := tm.brandingStorage
+	tm.mu.RUnlock()
+
+	submissions, err := storage.ListSubmissions(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(submissions, func(i, j int) bool {
+		return submissions[i].SubmittedAt.After(submissions[j].SubmittedAt)
+	})
+
+	return submissions, nil
+}
+
+// PreviewBranding returns the compiled preview theme of a pending submission.
+func (tm *TenantManager) PreviewBranding(ctx context.Context, submissionID string) (*CompiledTheme, error) {
+	submission, err := tm.GetBrandingSubmission(ctx, submissionID)
+	if err != nil {
+		return nil, err
+	}
+	if submission.Status != BrandingStatusPreview || submission.Preview == nil {
+		return nil, NewErrorf(ErrCodeValidation, "branding submission %s has no preview (status: %s)", submissionID, submission.Status)
+	}
+
+	return submission.Preview, nil
+}
+
+// PublishBranding atomically replaces the tenant's branding with a previewed
+// submission. The tenant configuration is copied, updated and saved in a single
+// write so readers see either the old or the new branding, never a mix. Publishing
+// fails if the configuration changed after the submission was validated.
+func (tm *TenantManager) PublishBranding(ctx context.Context, submissionID, publishedBy string) (*TenantConfig, error) {
+	tm.mu.RLock()
+	brandingStorage := tm.brandingStorage
+	tm.mu.RUnlock()
+
+	submission, err := brandingStorage.GetSubmission(ctx, submissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := tm.lockTenant(submission.TenantID)
+	defer unlock()
+
+	// Re-read under the tenant lock: a concurrent publish or discard may
+	// have changed the submission.
+	submission, err = brandingStorage.GetSubmission(ctx, submissionID)
+	if err != nil {
+		return nil, err
+	}
+	if submission.Status != BrandingStatusPreview {
+		return nil, NewErrorf(ErrCodeValidation, "branding submission %s cannot be published (status: %s)", submissionID, submission.Status)
+	}
+
+	current, err := tm.storage.GetTenant(ctx, submission.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	if !current.UpdatedAt.Equal(submission.BaseVersion) || current.DefaultTheme != submission.ThemeID {
+		return nil, NewErrorf(ErrCodeValidation, "tenant %s changed since branding submission %s was validated, resubmit it", submission.TenantID, submissionID)
+	}
+
+	now := time.Now()
+	published := *current
+	published.Branding = submission.Overrides
+	published.UpdatedAt = now
+
+	if err := tm.storage.SaveTenant(ctx, &published); err != nil {
+		return nil, WrapError(ErrCodeStorage, "failed to publish branding", err)
+	}
+
+	submission.Status = BrandingStatusPublished
+	submission.PublishedBy = publishedBy
+	submission.PublishedAt = now
+	if err := brandingStorage.SaveSubmission(ctx, submission); err != nil {
+		return nil, WrapError(ErrCodeStorage, "failed to update branding submission", err)
+	}
+
+	tm.supersedePendingBranding(ctx, brandingStorage, submission)
+	tm.manager.InvalidateCache(submission.ThemeID, submission.TenantID)
+
+	return &published, nil
+}
+
+// supersedePendingBranding marks other previews of the tenant as superseded.
+func (tm *TenantManager) supersedePendingBranding(ctx context.Context, storage BrandingStorage, published *BrandingSubmission) {
+	pending, err := storage.ListSubmissions(ctx, published.TenantID)
+	if err != nil {
+		return
+	}
+	for _, submission := range pending {
+		if submission.ID == published.ID || submission.Status != BrandingStatusPreview {
+			continue
+		}
+		submission.Status = BrandingStatusSuperseded
+		submission.Preview = nil
+		_ = storage.SaveSubmission(ctx, submission)
+	}
+}
+
+// DiscardBranding drops a pending branding submission and its preview.
+func (tm *TenantManager) DiscardBranding(ctx context.Context, submissionID string) error {
+	tm.mu.RLock()
+	brandingStorage := tm.brandingStorage
+	tm.mu.RUnlock()
+
+	submission, err := brandingStorage.GetSubmission(ctx, submissionID)
+	if err != nil {
+		return err
+	}
+
+	unlock := tm.lockTenant(submission.TenantID)
+	defer unlock()
+
+	submission, err = brandingStorage.GetSubmission(ctx, submissionID)
+	if err != nil {
+		return err
+	}
+	if submission.Status == BrandingStatusPublished {
+		return NewErrorf(ErrCodeValidation, "branding submission %s is already published", submissionID)
+	}
+
+	submission.Status = BrandingStatusDiscarded
+	submission.Preview = nil
+
+	return brandingStorage.SaveSubmission(ctx, submission)
+}
+
 // EnableFeature enables a feature flag for a tenant.
 func (tm *TenantManager) EnableFeature(ctx context.Context, tenantID, feature string) error {
+	unlock := tm.lockTenant(tenantID)
+	defer unlock()
+
 	config, err := tm.GetTenantConfig(ctx, tenantID)
 	if err != nil {
 		return err
@@ -246,6 +637,9 @@ func (tm *TenantManager) EnableFeature(ctx context.Context, tenantID, feature st
 
 // DisableFeature disables a feature flag for a tenant.
 func (tm *TenantManager) DisableFeature(ctx context.Context, tenantID, feature string) error {
+	unlock := tm.lockTenant(tenantID)
+	defer unlock()
+
 	config, err := tm.GetTenantConfig(ctx, tenantID)
 	if err != nil {
 		return err
@@ -277,6 +671,9 @@ func (tm *TenantManager) IsFeatureEnabled(ctx context.Context, tenantID, feature
 
 // DeleteTenant removes tenant configuration.
 func (tm *TenantManager) DeleteTenant(ctx context.Context, tenantID string) error {
+	unlock := tm.lockTenant(tenantID)
+	defer unlock()
+
 	if err := tm.storage.DeleteTenant(ctx, tenantID); err != nil {
 		return err
 	}
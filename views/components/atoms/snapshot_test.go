@@ -0,0 +1,50 @@
+package atoms
+
+import (
+	"testing"
+
+	"github.com/niiniyare/ruun/views/components"
+	"github.com/niiniyare/ruun/views/components/snapshot"
+)
+
+func TestButtonSnapshots(t *testing.T) {
+	set := snapshot.Set[ButtonProps]
+	snapshot.Run(t, snapshot.New(), snapshot.Matrix[ButtonProps]{
+		Base: ButtonProps{Text: "Save", Type: "button"},
+		Axes: []snapshot.Axis[ButtonProps]{
+			snapshot.Vary("variant",
+				set("default", func(p *ButtonProps) { p.Variant = components.ButtonDefault }),
+				set("secondary", func(p *ButtonProps) { p.Variant = components.ButtonSecondary }),
+				set("outline", func(p *ButtonProps) { p.Variant = components.ButtonOutline }),
+				set("destructive", func(p *ButtonProps) { p.Variant = components.ButtonDestructive }),
+				set("ghost", func(p *ButtonProps) { p.Variant = components.ButtonGhost }),
+				set("link", func(p *ButtonProps) { p.Variant = components.ButtonLink })),
+			snapshot.Vary("size",
+				set("sm", func(p *ButtonProps) { p.Size = components.SizeSm }),
+				set("default", func(p *ButtonProps) { p.Size = components.SizeDefault }),
+				set("lg", func(p *ButtonProps) { p.Size = components.SizeLg })),
+			snapshot.Vary("state",
+				set("enabled", nil),
+				set("disabled", func(p *ButtonProps) { p.Base.State.Disabled = true }),
+				set("loading", func(p *ButtonProps) { p.Base.State.Loading = true })),
+		},
+	}, Button)
+}
+
+func TestBadgeSnapshots(t *testing.T) {
+	set := snapshot.Set[BadgeProps]
+	snapshot.Run(t, snapshot.New(), snapshot.Matrix[BadgeProps]{
+		Base: BadgeProps{Text: "New"},
+		Axes: []snapshot.Axis[BadgeProps]{
+			snapshot.Vary("variant",
+				set("default", func(p *BadgeProps) { p.Variant = components.BadgeDefault }),
+				set("secondary", func(p *BadgeProps) { p.Variant = components.BadgeSecondary }),
+				set("success", func(p *BadgeProps) { p.Variant = components.BadgeSuccess }),
+				set("warning", func(p *BadgeProps) { p.Variant = components.BadgeWarning }),
+				set("destructive", func(p *BadgeProps) { p.Variant = components.BadgeDestructive })),
+			snapshot.Vary("link",
+				set("static", nil),
+				set("href", func(p *BadgeProps) { p.Href = "/inbox" })),
+		},
+	}, Badge)
+}
@@ -0,0 +1,108 @@
+package molecules
+
+import (
+	"testing"
+
+	"github.com/niiniyare/ruun/views/components"
+	"github.com/niiniyare/ruun/views/components/snapshot"
+)
+
+func TestFormFieldSnapshots(t *testing.T) {
+	set := snapshot.Set[FormFieldProps]
+	snapshot.Run(t, snapshot.New(), snapshot.Matrix[FormFieldProps]{
+		Base: FormFieldProps{ID: "field", Name: "field", Label: "Field", HelpText: "Help"},
+		Axes: []snapshot.Axis[FormFieldProps]{
+			snapshot.Vary("type",
+				set("text", func(p *FormFieldProps) { p.Type = FormFieldText }),
+				set("textarea", func(p *FormFieldProps) { p.Type = FormFieldTextarea }),
+				set("select", func(p *FormFieldProps) {
+					p.Type = FormFieldSelect
+					p.Options = []FormFieldOption{{Value: "a", Label: "A"}, {Value: "b", Label: "B", Selected: true}}
+				}),
+				set("checkbox", func(p *FormFieldProps) { p.Type = FormFieldCheckbox }),
+				set("radio", func(p *FormFieldProps) {
+					p.Type = FormFieldRadio
+					p.Options = []FormFieldOption{{Value: "a", Label: "A"}, {Value: "b", Label: "B", Disabled: true}}
+				})),
+			snapshot.Vary("state",
+				set("default", nil),
+				set("required", func(p *FormFieldProps) { p.Required = true }),
+				set("disabled", func(p *FormFieldProps) { p.Disabled = true }),
+				set("error", func(p *FormFieldProps) {
+					p.HasError = true
+					p.Errors = []string{"Field is invalid"}
+				})),
+		},
+	}, FormField)
+}
+
+func TestCardSnapshots(t *testing.T) {
+	set := snapshot.Set[CardProps]
+	snapshot.Run(t, snapshot.New(), snapshot.Matrix[CardProps]{
+		Base: CardProps{Title: "Revenue", Description: "Last 30 days"},
+		Axes: []snapshot.Axis[CardProps]{
+			snapshot.Vary("style",
+				set("plain", nil),
+				set("border", func(p *CardProps) { p.Border = true }),
+				set("compact", func(p *CardProps) { p.Compact = true }),
+				set("elevated", func(p *CardProps) { p.Elevated = true })),
+		},
+	}, Card)
+}
+
+func TestButtonGroupSnapshots(t *testing.T) {
+	set := snapshot.Set[ButtonGroupProps]
+	snapshot.Run(t, snapshot.New(), snapshot.Matrix[ButtonGroupProps]{
+		Base: ButtonGroupProps{
+			AriaLabel: "Alignment",
+			Buttons:   []ButtonConfig{{Text: "Left"}, {Text: "Center"}, {Text: "Right", Disabled: true}},
+		},
+		Axes: []snapshot.Axis[ButtonGroupProps]{
+			snapshot.Vary("orientation",
+				set("horizontal", func(p *ButtonGroupProps) { p.Orientation = components.OrientationHorizontal }),
+				set("vertical", func(p *ButtonGroupProps) { p.Orientation = components.OrientationVertical })),
+			snapshot.Vary("selection",
+				set("none", nil),
+				set("single", func(p *ButtonGroupProps) {
+					p.SelectionMode = "single"
+					p.Selected = []int{1}
+				})),
+		},
+	}, ButtonGroup)
+}
+
+func TestSearchBoxSnapshots(t *testing.T) {
+	set := snapshot.Set[SearchBoxProps]
+	snapshot.Run(t, snapshot.New(), snapshot.Matrix[SearchBoxProps]{
+		Base: SearchBoxProps{ID: "search", Name: "q", Placeholder: "Search"},
+		Axes: []snapshot.Axis[SearchBoxProps]{
+			snapshot.Vary("size",
+				set("sm", func(p *SearchBoxProps) { p.Size = SearchBoxSizeSM }),
+				set("md", func(p *SearchBoxProps) { p.Size = SearchBoxSizeMD }),
+				set("lg", func(p *SearchBoxProps) { p.Size = SearchBoxSizeLG })),
+			snapshot.Vary("state",
+				set("enabled", nil),
+				set("disabled", func(p *SearchBoxProps) { p.Disabled = true })),
+		},
+	}, SearchBox)
+}
+
+func TestMenuItemSnapshots(t *testing.T) {
+	set := snapshot.Set[MenuItemProps]
+	snapshot.Run(t, snapshot.New(), snapshot.Matrix[MenuItemProps]{
+		Base: MenuItemProps{ID: "item", Text: "Settings", Icon: "settings"},
+		Axes: []snapshot.Axis[MenuItemProps]{
+			snapshot.Vary("type",
+				set("button", func(p *MenuItemProps) { p.Type = MenuItemTypeButton }),
+				set("link", func(p *MenuItemProps) {
+					p.Type = MenuItemTypeLink
+					p.URL = "/settings"
+				}),
+				set("checkbox", func(p *MenuItemProps) { p.Type = MenuItemTypeCheckbox }),
+				set("divider", func(p *MenuItemProps) { p.Type = MenuItemTypeDivider })),
+			snapshot.Vary("badge",
+				set("none", nil),
+				set("count", func(p *MenuItemProps) { p.Badge = "3" })),
+		},
+	}, MenuItem)
+}
@@ -11,6 +11,7 @@ import templruntime "github.com/a-h/templ/runtime"
 import (
 	"fmt"
 	"github.com/niiniyare/ruun/pkg/utils"
+	"github.com/niiniyare/ruun/views/components"
 	"github.com/niiniyare/ruun/views/components/atoms"
 	"github.com/niiniyare/ruun/views/components/molecules"
 	"strconv"
@@ -239,6 +240,39 @@ type DataTableProps struct {
 	AriaLabels map[string]string `json:"ariaLabels"`
 }
 
+// selectable reports whether rows can be selected
+func (p DataTableProps) selectable() bool {
+	return p.Selection != nil && p.Selection.Enabled
+}
+
+// multiSelect reports whether more than one row can be selected
+func (p DataTableProps) multiSelect() bool {
+	return p.selectable() && p.Selection.MultiSelect
+}
+
+// rowClick reports whether clicking a row selects it
+func (p DataTableProps) rowClick() bool {
+	return p.selectable() && p.Selection.RowClick
+}
+
+// rowActions returns the per-row actions
+func (p DataTableProps) rowActions() []DataTableAction {
+	if p.Actions == nil {
+		return nil
+	}
+	return p.Actions.RowActions
+}
+
+// virtualized reports whether rows use virtual scrolling
+func (p DataTableProps) virtualized() bool {
+	return p.Performance != nil && p.Performance.Virtualized
+}
+
+// persistState reports whether table state is stored between visits
+func (p DataTableProps) persistState() bool {
+	return p.Storage != nil && p.Storage.Enabled
+}
+
 // SelectionConfig defines row selection features
 type SelectionConfig struct {
 	Enabled     bool `json:"enabled"`
@@ -440,7 +474,7 @@ func DataTable(props DataTableProps) templ.Component {
 		var templ_7745c5c3_Var3 string
 		templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs(templ.CSSClasses(templ_7745c5c3_Var2).String())
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 1, Col: 0}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 1, Col: 0}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var3))
 		if templ_7745c5c3_Err != nil {
@@ -458,7 +492,7 @@ func DataTable(props DataTableProps) templ.Component {
 			var templ_7745c5c3_Var4 string
 			templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(props.ID)
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 406, Col: 16}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 440, Col: 16}
 			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
 			if templ_7745c5c3_Err != nil {
@@ -477,7 +511,7 @@ func DataTable(props DataTableProps) templ.Component {
 			var templ_7745c5c3_Var5 string
 			templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs(props.AriaLabels["table"])
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 409, Col: 41}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 443, Col: 41}
 			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var5))
 			if templ_7745c5c3_Err != nil {
@@ -495,7 +529,7 @@ func DataTable(props DataTableProps) templ.Component {
 		var templ_7745c5c3_Var6 string
 		templ_7745c5c3_Var6, templ_7745c5c3_Err = templ.JoinStringErrs(buildDataTableAlpineData(props))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 411, Col: 42}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 445, Col: 42}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var6))
 		if templ_7745c5c3_Err != nil {
@@ -763,7 +797,7 @@ func dataTableHeader(props DataTableProps) templ.Component {
 				var templ_7745c5c3_Var8 string
 				templ_7745c5c3_Var8, templ_7745c5c3_Err = templ.JoinStringErrs(props.Title)
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 602, Col: 46}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 636, Col: 46}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var8))
 				if templ_7745c5c3_Err != nil {
@@ -782,7 +816,7 @@ func dataTableHeader(props DataTableProps) templ.Component {
 				var templ_7745c5c3_Var9 string
 				templ_7745c5c3_Var9, templ_7745c5c3_Err = templ.JoinStringErrs(props.Description)
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 605, Col: 57}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 639, Col: 57}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var9))
 				if templ_7745c5c3_Err != nil {
@@ -844,18 +878,18 @@ func dataTableToolbar(props DataTableProps) templ.Component {
 			templ_7745c5c3_Var10 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		if props.Filtering.Enabled || len(props.Filtering.Filters) > 0 || props.Export.Enabled {
+		if hasFiltering(props) || hasExport(props) {
 			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 26, "<div class=\"datatable-toolbar\"><div class=\"datatable-toolbar-left\">")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			if props.Filtering.Enabled {
+			if hasFiltering(props) && props.Filtering.Search != nil {
 				templ_7745c5c3_Err = dataTableSearch(props.Filtering).Render(ctx, templ_7745c5c3_Buffer)
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 			}
-			if len(props.Filtering.Filters) > 0 {
+			if hasFiltering(props) && len(props.Filtering.Filters) > 0 {
 				templ_7745c5c3_Err = dataTableFilters(props.Filtering.Filters).Render(ctx, templ_7745c5c3_Buffer)
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
@@ -865,7 +899,7 @@ func dataTableToolbar(props DataTableProps) templ.Component {
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			if props.Export.Enabled {
+			if hasExport(props) {
 				templ_7745c5c3_Err = dataTableExportButton(props.Export).Render(ctx, templ_7745c5c3_Buffer)
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
@@ -913,66 +947,53 @@ func dataTableSearch(filtering *FilteringConfig) templ.Component {
 		var templ_7745c5c3_Var12 string
 		templ_7745c5c3_Var12, templ_7745c5c3_Err = templ.JoinStringErrs(utils.IfElse(filtering.Search.Placeholder != "", filtering.Search.Placeholder, "Search..."))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 651, Col: 108}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 685, Col: 108}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var12))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 30, "\" value=\"")
-		if templ_7745c5c3_Err != nil {
-			return templ_7745c5c3_Err
-		}
-		var templ_7745c5c3_Var13 string
-		templ_7745c5c3_Var13, templ_7745c5c3_Err = templ.JoinStringErrs(filtering.Search.Value)
-		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 652, Col: 33}
-		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var13))
-		if templ_7745c5c3_Err != nil {
-			return templ_7745c5c3_Err
-		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 31, "\" x-model=\"searchQuery\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 30, "\" x-model=\"searchQuery\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		if filtering.Search.Delay > 0 {
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 32, " x-on:input=\"")
+		if filtering.Search.Debounce > 0 {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 31, " x-on:input=\"")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			var templ_7745c5c3_Var14 string
-			templ_7745c5c3_Var14, templ_7745c5c3_Err = templ.JoinStringErrs("debounce." + fmt.Sprintf("%dms", filtering.Search.Delay) + ":performSearch()")
+			var templ_7745c5c3_Var13 string
+			templ_7745c5c3_Var13, templ_7745c5c3_Err = templ.JoinStringErrs("debounce." + fmt.Sprintf("%dms", filtering.Search.Debounce) + ":performSearch()")
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 655, Col: 95}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 688, Col: 98}
 			}
-			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var14))
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var13))
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 33, "\"")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 32, "\"")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 		} else {
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 34, " x-on:input=\"performSearch()\"")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 33, " x-on:input=\"performSearch()\"")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 35, ">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 34, ">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		templ_7745c5c3_Err = atoms.Icon(atoms.IconProps{
 			Name: "search",
-			Size: atoms.IconSizeSM,
+			Size: components.SizeSm,
 		}).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		if filtering.Search.Advanced {
-			templ_7745c5c3_Var15 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		if filtering.Advanced {
+			templ_7745c5c3_Var14 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 				templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 				templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 				if !templ_7745c5c3_IsBuffer {
@@ -986,7 +1007,7 @@ func dataTableSearch(filtering *FilteringConfig) templ.Component {
 				ctx = templ.InitializeContext(ctx)
 				templ_7745c5c3_Err = atoms.Icon(atoms.IconProps{
 					Name: "filter",
-					Size: atoms.IconSizeSM,
+					Size: components.SizeSm,
 				}).Render(ctx, templ_7745c5c3_Buffer)
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
@@ -994,16 +1015,18 @@ func dataTableSearch(filtering *FilteringConfig) templ.Component {
 				return nil
 			})
 			templ_7745c5c3_Err = atoms.Button(atoms.ButtonProps{
-				Variant:   "ghost",
-				Size:      "sm",
-				OnClick:   "showAdvancedSearch = !showAdvancedSearch",
-				AriaLabel: "Toggle advanced search",
-			}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var15), templ_7745c5c3_Buffer)
+				Variant: "ghost",
+				Size:    "sm",
+				Base: components.BaseProps{
+					Events: components.EventHandlers{OnClick: "showAdvancedSearch = !showAdvancedSearch"},
+					A11y:   components.AccessibilityProps{AriaLabel: "Toggle advanced search"},
+				},
+			}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var14), templ_7745c5c3_Buffer)
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 36, "</div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 35, "</div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -1028,84 +1051,84 @@ func dataTableFilters(filters []DataTableFilter) templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var16 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var16 == nil {
-			templ_7745c5c3_Var16 = templ.NopComponent
+		templ_7745c5c3_Var15 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var15 == nil {
+			templ_7745c5c3_Var15 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 37, "<div class=\"datatable-filters\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 36, "<div class=\"datatable-filters\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		for _, filter := range filters {
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 38, "<div class=\"datatable-filter\"><select class=\"datatable-filter-select\" x-model=\"")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 37, "<div class=\"datatable-filter\"><select class=\"datatable-filter-select\" x-model=\"")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			var templ_7745c5c3_Var17 string
-			templ_7745c5c3_Var17, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("filters.%s", filter.Column))
+			var templ_7745c5c3_Var16 string
+			templ_7745c5c3_Var16, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("filters.%s", filter.Column))
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 686, Col: 94}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 721, Col: 94}
 			}
-			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var17))
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var16))
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 39, "\"><option value=\"\">")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 38, "\"><option value=\"\">")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			var templ_7745c5c3_Var18 string
-			templ_7745c5c3_Var18, templ_7745c5c3_Err = templ.JoinStringErrs(filter.Column)
+			var templ_7745c5c3_Var17 string
+			templ_7745c5c3_Var17, templ_7745c5c3_Err = templ.JoinStringErrs(filter.Column)
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 687, Col: 37}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 722, Col: 37}
 			}
-			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var18))
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var17))
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 40, "</option> ")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 39, "</option> ")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 			for _, option := range filter.Options {
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 41, "<option value=\"")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 40, "<option value=\"")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				var templ_7745c5c3_Var19 string
-				templ_7745c5c3_Var19, templ_7745c5c3_Err = templ.JoinStringErrs(option)
+				var templ_7745c5c3_Var18 string
+				templ_7745c5c3_Var18, templ_7745c5c3_Err = templ.JoinStringErrs(option)
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 689, Col: 28}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 724, Col: 28}
 				}
-				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var19))
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var18))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 42, "\">")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 41, "\">")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				var templ_7745c5c3_Var20 string
-				templ_7745c5c3_Var20, templ_7745c5c3_Err = templ.JoinStringErrs(option)
+				var templ_7745c5c3_Var19 string
+				templ_7745c5c3_Var19, templ_7745c5c3_Err = templ.JoinStringErrs(option)
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 689, Col: 39}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 724, Col: 39}
 				}
-				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var20))
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var19))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 43, "</option>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 42, "</option>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 44, "</select></div>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 43, "</select></div>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 45, "<button class=\"datatable-filter-clear\" x-on:click=\"clearFilters()\">Clear filters</button></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 44, "<button class=\"datatable-filter-clear\" x-on:click=\"clearFilters()\">Clear filters</button></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -1130,13 +1153,13 @@ func dataTableBulkActions(props DataTableProps) templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var21 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var21 == nil {
-			templ_7745c5c3_Var21 = templ.NopComponent
+		templ_7745c5c3_Var20 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var20 == nil {
+			templ_7745c5c3_Var20 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
 		if props.Actions != nil && len(props.Actions.BulkActions) > 0 {
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 46, "<div class=\"datatable-bulk-actions\" x-show=\"selectedRows.length > 0\" x-transition><div class=\"datatable-bulk-actions-content\"><span class=\"datatable-bulk-actions-count\" x-text=\"`${selectedRows.length} items selected`\"></span><div class=\"datatable-bulk-actions-buttons\">")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 45, "<div class=\"datatable-bulk-actions\" x-show=\"selectedRows.length > 0\" x-transition><div class=\"datatable-bulk-actions-content\"><span class=\"datatable-bulk-actions-count\" x-text=\"`${selectedRows.length} items selected`\"></span><div class=\"datatable-bulk-actions-buttons\">")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -1146,7 +1169,7 @@ func dataTableBulkActions(props DataTableProps) templ.Component {
 					return templ_7745c5c3_Err
 				}
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 47, "</div></div></div>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 46, "</div></div></div>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -1172,12 +1195,12 @@ func dataTableBulkAction(action DataTableBulkAction) templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var22 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var22 == nil {
-			templ_7745c5c3_Var22 = templ.NopComponent
+		templ_7745c5c3_Var21 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var21 == nil {
+			templ_7745c5c3_Var21 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Var23 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_Var22 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 			templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 			templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 			if !templ_7745c5c3_IsBuffer {
@@ -1192,7 +1215,7 @@ func dataTableBulkAction(action DataTableBulkAction) templ.Component {
 			if action.Icon != "" {
 				templ_7745c5c3_Err = atoms.Icon(atoms.IconProps{
 					Name: action.Icon,
-					Size: atoms.IconSizeXS,
+					Size: components.SizeXs,
 				}).Render(ctx, templ_7745c5c3_Buffer)
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
@@ -1202,10 +1225,10 @@ func dataTableBulkAction(action DataTableBulkAction) templ.Component {
 		})
 		templ_7745c5c3_Err = atoms.Button(atoms.ButtonProps{
 			Text:    action.Text,
-			Variant: action.Variant,
+			Variant: components.ButtonVariant(action.Variant),
 			Size:    "sm",
-			OnClick: utils.IfElse(action.Confirm, fmt.Sprintf("confirmBulkAction('%s')", action.ID), action.OnClick),
-		}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var23), templ_7745c5c3_Buffer)
+			Base:    components.BaseProps{Events: components.EventHandlers{OnClick: utils.IfElse(action.Confirm, fmt.Sprintf("confirmBulkAction('%s')", action.ID), action.OnClick)}},
+		}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var22), templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -1230,12 +1253,12 @@ func dataTableContent(props DataTableProps) templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var24 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var24 == nil {
-			templ_7745c5c3_Var24 = templ.NopComponent
+		templ_7745c5c3_Var23 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var23 == nil {
+			templ_7745c5c3_Var23 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		if props.Virtualized {
+		if props.virtualized() {
 			templ_7745c5c3_Err = dataTableVirtualized(props).Render(ctx, templ_7745c5c3_Buffer)
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
@@ -1267,30 +1290,30 @@ func dataTableStandard(props DataTableProps) templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var25 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var25 == nil {
-			templ_7745c5c3_Var25 = templ.NopComponent
+		templ_7745c5c3_Var24 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var24 == nil {
+			templ_7745c5c3_Var24 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		var templ_7745c5c3_Var26 = []any{getTableClasses(props)}
-		templ_7745c5c3_Err = templ.RenderCSSItems(ctx, templ_7745c5c3_Buffer, templ_7745c5c3_Var26...)
+		var templ_7745c5c3_Var25 = []any{getTableClasses(props)}
+		templ_7745c5c3_Err = templ.RenderCSSItems(ctx, templ_7745c5c3_Buffer, templ_7745c5c3_Var25...)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 48, "<table class=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 47, "<table class=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		var templ_7745c5c3_Var27 string
-		templ_7745c5c3_Var27, templ_7745c5c3_Err = templ.JoinStringErrs(templ.CSSClasses(templ_7745c5c3_Var26).String())
+		var templ_7745c5c3_Var26 string
+		templ_7745c5c3_Var26, templ_7745c5c3_Err = templ.JoinStringErrs(templ.CSSClasses(templ_7745c5c3_Var25).String())
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 1, Col: 0}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 1, Col: 0}
 		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var27))
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var26))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 49, "\"><thead class=\"datatable-thead\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 48, "\"><thead class=\"datatable-thead\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -1298,7 +1321,7 @@ func dataTableStandard(props DataTableProps) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 50, "</thead> <tbody class=\"datatable-tbody\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 49, "</thead> <tbody class=\"datatable-tbody\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -1308,43 +1331,43 @@ func dataTableStandard(props DataTableProps) templ.Component {
 				return templ_7745c5c3_Err
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 51, "</tbody> ")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 50, "</tbody> ")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		if props.Pagination.ShowTotal {
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 52, "<tfoot class=\"datatable-tfoot\"><tr><td colspan=\"")
+		if hasPagination(props) && props.Pagination.ShowTotal {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 51, "<tfoot class=\"datatable-tfoot\"><tr><td colspan=\"")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			var templ_7745c5c3_Var28 string
-			templ_7745c5c3_Var28, templ_7745c5c3_Err = templ.JoinStringErrs(strconv.Itoa(len(props.Columns)))
+			var templ_7745c5c3_Var27 string
+			templ_7745c5c3_Var27, templ_7745c5c3_Err = templ.JoinStringErrs(strconv.Itoa(len(props.Columns)))
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 762, Col: 51}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 797, Col: 51}
 			}
-			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var28))
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var27))
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 53, "\" class=\"datatable-footer\">Total: ")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 52, "\" class=\"datatable-footer\">Total: ")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			var templ_7745c5c3_Var29 string
-			templ_7745c5c3_Var29, templ_7745c5c3_Err = templ.JoinStringErrs(strconv.Itoa(props.Pagination.TotalItems))
+			var templ_7745c5c3_Var28 string
+			templ_7745c5c3_Var28, templ_7745c5c3_Err = templ.JoinStringErrs(strconv.Itoa(props.Pagination.TotalItems))
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 763, Col: 56}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 798, Col: 56}
 			}
-			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var29))
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var28))
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 54, " items</td></tr></tfoot>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 53, " items</td></tr></tfoot>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 55, "</table>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 54, "</table>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -1369,25 +1392,24 @@ func dataTableHeaderRow(props DataTableProps) templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var30 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var30 == nil {
-			templ_7745c5c3_Var30 = templ.NopComponent
+		templ_7745c5c3_Var29 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var29 == nil {
+			templ_7745c5c3_Var29 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 56, "<tr class=\"datatable-header-row\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 55, "<tr class=\"datatable-header-row\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		if props.Selectable {
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 57, "<th class=\"datatable-th datatable-th-checkbox\">")
+		if props.selectable() {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 56, "<th class=\"datatable-th datatable-th-checkbox\">")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			if props.MultiSelect {
+			if props.multiSelect() {
 				templ_7745c5c3_Err = atoms.Checkbox(atoms.CheckboxProps{
 					ID:        "select-all",
 					Name:      "select-all",
-					Size:      atoms.CheckboxSizeMD,
 					OnChange:  "toggleAllRows()",
 					AriaLabel: "Select all rows",
 				}).Render(ctx, templ_7745c5c3_Buffer)
@@ -1395,7 +1417,7 @@ func dataTableHeaderRow(props DataTableProps) templ.Component {
 					return templ_7745c5c3_Err
 				}
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 58, "</th>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 57, "</th>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -1406,13 +1428,13 @@ func dataTableHeaderRow(props DataTableProps) templ.Component {
 				return templ_7745c5c3_Err
 			}
 		}
-		if len(props.RowActions) > 0 {
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 59, "<th class=\"datatable-th datatable-th-actions\">Actions</th>")
+		if len(props.rowActions()) > 0 {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 58, "<th class=\"datatable-th datatable-th-actions\">Actions</th>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 60, "</tr>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 59, "</tr>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -1437,96 +1459,96 @@ func dataTableHeaderCell(column DataTableColumn, props DataTableProps) templ.Com
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var31 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var31 == nil {
-			templ_7745c5c3_Var31 = templ.NopComponent
+		templ_7745c5c3_Var30 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var30 == nil {
+			templ_7745c5c3_Var30 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 61, "<th class=\"datatable-th\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 60, "<th class=\"datatable-th\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		if column.Width != "" {
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 62, " style=\"")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 61, " style=\"")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			var templ_7745c5c3_Var32 string
-			templ_7745c5c3_Var32, templ_7745c5c3_Err = templruntime.SanitizeStyleAttributeValues(fmt.Sprintf("width: %s", column.Width))
+			var templ_7745c5c3_Var31 string
+			templ_7745c5c3_Var31, templ_7745c5c3_Err = templruntime.SanitizeStyleAttributeValues(fmt.Sprintf("width: %s", column.Width))
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 803, Col: 49}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 837, Col: 49}
 			}
-			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var32))
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var31))
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 63, "\"")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 62, "\"")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 		}
 		if column.Sortable {
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 64, " x-on:click=\"")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 63, " x-on:click=\"")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			var templ_7745c5c3_Var33 string
-			templ_7745c5c3_Var33, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("sortColumn('%s')", column.Key))
+			var templ_7745c5c3_Var32 string
+			templ_7745c5c3_Var32, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("sortColumn('%s')", column.Key))
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 806, Col: 59}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 840, Col: 59}
 			}
-			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var33))
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var32))
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 65, "\"")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 64, "\"")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 66, "><div class=\"datatable-th-content\"><span class=\"datatable-th-text\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 65, "><div class=\"datatable-th-content\"><span class=\"datatable-th-text\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		var templ_7745c5c3_Var34 string
-		templ_7745c5c3_Var34, templ_7745c5c3_Err = templ.JoinStringErrs(column.Title)
+		var templ_7745c5c3_Var33 string
+		templ_7745c5c3_Var33, templ_7745c5c3_Err = templ.JoinStringErrs(column.Title)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 810, Col: 49}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 844, Col: 49}
 		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var34))
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var33))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 67, "</span> ")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 66, "</span> ")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		if column.Sortable {
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 68, "<div class=\"datatable-th-sort\">")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 67, "<div class=\"datatable-th-sort\">")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 			templ_7745c5c3_Err = atoms.Icon(atoms.IconProps{
 				Name: "chevron-up",
-				Size: atoms.IconSizeXS,
+				Size: components.SizeXs,
 			}).Render(ctx, templ_7745c5c3_Buffer)
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 			templ_7745c5c3_Err = atoms.Icon(atoms.IconProps{
 				Name: "chevron-down",
-				Size: atoms.IconSizeXS,
+				Size: components.SizeXs,
 			}).Render(ctx, templ_7745c5c3_Buffer)
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 69, "</div>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 68, "</div>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 		}
 		if column.Filterable {
-			templ_7745c5c3_Var35 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+			templ_7745c5c3_Var34 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 				templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 				templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 				if !templ_7745c5c3_IsBuffer {
@@ -1540,7 +1562,7 @@ func dataTableHeaderCell(column DataTableColumn, props DataTableProps) templ.Com
 				ctx = templ.InitializeContext(ctx)
 				templ_7745c5c3_Err = atoms.Icon(atoms.IconProps{
 					Name: "filter",
-					Size: atoms.IconSizeXS,
+					Size: components.SizeXs,
 				}).Render(ctx, templ_7745c5c3_Buffer)
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
@@ -1550,36 +1572,36 @@ func dataTableHeaderCell(column DataTableColumn, props DataTableProps) templ.Com
 			templ_7745c5c3_Err = atoms.Button(atoms.ButtonProps{
 				Variant: "ghost",
 				Size:    "sm",
-				OnClick: fmt.Sprintf("toggleColumnFilter('%s')", column.Key),
-			}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var35), templ_7745c5c3_Buffer)
+				Base:    components.BaseProps{Events: components.EventHandlers{OnClick: fmt.Sprintf("toggleColumnFilter('%s')", column.Key)}},
+			}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var34), templ_7745c5c3_Buffer)
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 70, "</div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 69, "</div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		if column.Resizable {
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 71, "<div class=\"datatable-th-resizer\" x-on:mousedown=\"")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 70, "<div class=\"datatable-th-resizer\" x-on:mousedown=\"")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			var templ_7745c5c3_Var36 string
-			templ_7745c5c3_Var36, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("startResize('%s', $event)", column.Key))
+			var templ_7745c5c3_Var35 string
+			templ_7745c5c3_Var35, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("startResize('%s', $event)", column.Key))
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 840, Col: 106}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 874, Col: 106}
 			}
-			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var36))
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var35))
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 72, "\"></div>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 71, "\"></div>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 73, "</th>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 72, "</th>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -1604,53 +1626,53 @@ func dataTableRow(row DataTableRow, props DataTableProps) templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var37 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var37 == nil {
-			templ_7745c5c3_Var37 = templ.NopComponent
+		templ_7745c5c3_Var36 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var36 == nil {
+			templ_7745c5c3_Var36 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 74, "<tr class=\"datatable-tr\" data-row-id=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 73, "<tr class=\"datatable-tr\" data-row-id=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		var templ_7745c5c3_Var38 string
-		templ_7745c5c3_Var38, templ_7745c5c3_Err = templ.JoinStringErrs(row.ID)
+		var templ_7745c5c3_Var37 string
+		templ_7745c5c3_Var37, templ_7745c5c3_Err = templ.JoinStringErrs(row.ID)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 849, Col: 22}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 883, Col: 22}
 		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var38))
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var37))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 75, "\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 74, "\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		if props.OnRowClick != "" {
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 76, " x-on:click=\"")
+		if props.rowClick() {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 75, " x-on:click=\"")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			var templ_7745c5c3_Var39 string
-			templ_7745c5c3_Var39, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("handleRowClick('%s', $event)", row.ID))
+			var templ_7745c5c3_Var38 string
+			templ_7745c5c3_Var38, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("handleRowClick('%s', $event)", row.ID))
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 851, Col: 67}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 885, Col: 67}
 			}
-			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var39))
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var38))
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 77, "\"")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 76, "\"")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 78, ">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 77, ">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		if props.Selectable {
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 79, "<td class=\"datatable-td datatable-td-checkbox\">")
+		if props.selectable() {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 78, "<td class=\"datatable-td datatable-td-checkbox\">")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -1659,13 +1681,12 @@ func dataTableRow(row DataTableRow, props DataTableProps) templ.Component {
 				Name:     "row-select",
 				Value:    row.ID,
 				Checked:  row.Selected,
-				Size:     atoms.CheckboxSizeMD,
 				OnChange: fmt.Sprintf("toggleRow('%s')", row.ID),
 			}).Render(ctx, templ_7745c5c3_Buffer)
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 80, "</td>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 79, "</td>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -1676,8 +1697,8 @@ func dataTableRow(row DataTableRow, props DataTableProps) templ.Component {
 				return templ_7745c5c3_Err
 			}
 		}
-		if len(props.RowActions) > 0 || len(row.Actions) > 0 {
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 81, "<td class=\"datatable-td datatable-td-actions\">")
+		if len(props.rowActions()) > 0 || len(row.Actions) > 0 {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 80, "<td class=\"datatable-td datatable-td-actions\">")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -1685,12 +1706,12 @@ func dataTableRow(row DataTableRow, props DataTableProps) templ.Component {
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 82, "</td>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 81, "</td>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 83, "</tr>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 82, "</tr>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -1715,35 +1736,35 @@ func dataTableCell(row DataTableRow, column DataTableColumn, props DataTableProp
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var40 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var40 == nil {
-			templ_7745c5c3_Var40 = templ.NopComponent
+		templ_7745c5c3_Var39 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var39 == nil {
+			templ_7745c5c3_Var39 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 84, "<td class=\"datatable-td\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 83, "<td class=\"datatable-td\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		if column.Clickable {
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 85, " x-on:click=\"")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 84, " x-on:click=\"")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			var templ_7745c5c3_Var41 string
-			templ_7745c5c3_Var41, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("handleCellClick('%s', '%s', $event)", row.ID, column.Key))
+			var templ_7745c5c3_Var40 string
+			templ_7745c5c3_Var40, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("handleCellClick('%s', '%s', $event)", row.ID, column.Key))
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 884, Col: 86}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 917, Col: 86}
 			}
-			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var41))
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var40))
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 86, "\"")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 85, "\"")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 87, ">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 86, ">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -1751,7 +1772,7 @@ func dataTableCell(row DataTableRow, column DataTableColumn, props DataTableProp
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 88, "</td>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 87, "</td>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -1776,9 +1797,9 @@ func dataTableCellContent(value any, column DataTableColumn) templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var42 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var42 == nil {
-			templ_7745c5c3_Var42 = templ.NopComponent
+		templ_7745c5c3_Var41 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var41 == nil {
+			templ_7745c5c3_Var41 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
 		switch column.Type {
@@ -1787,8 +1808,8 @@ func dataTableCellContent(value any, column DataTableColumn) templ.Component {
 				if variant, exists := column.BadgeMap[strValue]; exists {
 					templ_7745c5c3_Err = atoms.Badge(atoms.BadgeProps{
 						Text:    strValue,
-						Variant: variant,
-						Size:    atoms.BadgeSizeSM,
+						Variant: components.BadgeVariant(variant),
+						Size:    components.SizeSm,
 					}).Render(ctx, templ_7745c5c3_Buffer)
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
@@ -1797,7 +1818,7 @@ func dataTableCellContent(value any, column DataTableColumn) templ.Component {
 					templ_7745c5c3_Err = atoms.Badge(atoms.BadgeProps{
 						Text:    strValue,
 						Variant: "primary",
-						Size:    atoms.BadgeSizeSM,
+						Size:    components.SizeSm,
 					}).Render(ctx, templ_7745c5c3_Buffer)
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
@@ -1806,218 +1827,218 @@ func dataTableCellContent(value any, column DataTableColumn) templ.Component {
 			}
 		case ColumnTypeDate:
 			if timeValue, ok := value.(time.Time); ok {
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 89, "<span class=\"datatable-cell-date\">")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 88, "<span class=\"datatable-cell-date\">")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				var templ_7745c5c3_Var43 string
-				templ_7745c5c3_Var43, templ_7745c5c3_Err = templ.JoinStringErrs(utils.IfElse(column.DateFormat != "", timeValue.Format(column.DateFormat), timeValue.Format("2006-01-02")))
+				var templ_7745c5c3_Var42 string
+				templ_7745c5c3_Var42, templ_7745c5c3_Err = templ.JoinStringErrs(utils.IfElse(column.DateFormat != "", timeValue.Format(column.DateFormat), timeValue.Format("2006-01-02")))
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 913, Col: 112}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 946, Col: 112}
 				}
-				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var43))
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var42))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 90, "</span>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 89, "</span>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 			} else if strValue, ok := value.(string); ok {
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 91, "<span class=\"datatable-cell-date\">")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 90, "<span class=\"datatable-cell-date\">")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				var templ_7745c5c3_Var44 string
-				templ_7745c5c3_Var44, templ_7745c5c3_Err = templ.JoinStringErrs(strValue)
+				var templ_7745c5c3_Var43 string
+				templ_7745c5c3_Var43, templ_7745c5c3_Err = templ.JoinStringErrs(strValue)
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 916, Col: 47}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 949, Col: 47}
 				}
-				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var44))
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var43))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 92, "</span>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 91, "</span>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 			}
 		case ColumnTypeNumber:
 			if floatValue, ok := value.(float64); ok {
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 93, "<span class=\"datatable-cell-number\">")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 92, "<span class=\"datatable-cell-number\">")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				var templ_7745c5c3_Var45 string
-				templ_7745c5c3_Var45, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("%."+strconv.Itoa(column.Precision)+"f", floatValue))
+				var templ_7745c5c3_Var44 string
+				templ_7745c5c3_Var44, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("%."+strconv.Itoa(column.Precision)+"f", floatValue))
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 921, Col: 70}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 954, Col: 70}
 				}
-				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var45))
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var44))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 94, "</span>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 93, "</span>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 			} else if intValue, ok := value.(int); ok {
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 95, "<span class=\"datatable-cell-number\">")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 94, "<span class=\"datatable-cell-number\">")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				var templ_7745c5c3_Var46 string
-				templ_7745c5c3_Var46, templ_7745c5c3_Err = templ.JoinStringErrs(strconv.Itoa(intValue))
+				var templ_7745c5c3_Var45 string
+				templ_7745c5c3_Var45, templ_7745c5c3_Err = templ.JoinStringErrs(strconv.Itoa(intValue))
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 924, Col: 63}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 957, Col: 63}
 				}
-				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var46))
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var45))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 96, "</span>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 95, "</span>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 			} else if strValue, ok := value.(string); ok {
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 97, "<span class=\"datatable-cell-number\">")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 96, "<span class=\"datatable-cell-number\">")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				var templ_7745c5c3_Var47 string
-				templ_7745c5c3_Var47, templ_7745c5c3_Err = templ.JoinStringErrs(strValue)
+				var templ_7745c5c3_Var46 string
+				templ_7745c5c3_Var46, templ_7745c5c3_Err = templ.JoinStringErrs(strValue)
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 926, Col: 49}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 959, Col: 49}
 				}
-				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var47))
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var46))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 98, "</span>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 97, "</span>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 			}
 		case ColumnTypeProgress:
 			if floatValue, ok := value.(float64); ok {
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 99, "<div class=\"datatable-cell-progress\"><div class=\"datatable-progress-bar\"><div class=\"datatable-progress-fill\" style=\"")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 98, "<div class=\"datatable-cell-progress\"><div class=\"datatable-progress-bar\"><div class=\"datatable-progress-fill\" style=\"")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				var templ_7745c5c3_Var48 string
-				templ_7745c5c3_Var48, templ_7745c5c3_Err = templruntime.SanitizeStyleAttributeValues(fmt.Sprintf("width: %.1f%%", floatValue))
+				var templ_7745c5c3_Var47 string
+				templ_7745c5c3_Var47, templ_7745c5c3_Err = templruntime.SanitizeStyleAttributeValues(fmt.Sprintf("width: %.1f%%", floatValue))
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 934, Col: 54}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 967, Col: 54}
 				}
-				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var48))
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var47))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 100, "\"></div></div><span class=\"datatable-progress-text\">")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 99, "\"></div></div><span class=\"datatable-progress-text\">")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				var templ_7745c5c3_Var49 string
-				templ_7745c5c3_Var49, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("%.1f%%", floatValue))
+				var templ_7745c5c3_Var48 string
+				templ_7745c5c3_Var48, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("%.1f%%", floatValue))
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 937, Col: 77}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 970, Col: 77}
 				}
-				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var49))
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var48))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 101, "</span></div>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 100, "</span></div>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 			}
 		case ColumnTypeLink:
 			if strValue, ok := value.(string); ok {
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 102, "<a href=\"")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 101, "<a href=\"")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				var templ_7745c5c3_Var50 templ.SafeURL
-				templ_7745c5c3_Var50, templ_7745c5c3_Err = templ.JoinURLErrs(strValue)
+				var templ_7745c5c3_Var49 templ.SafeURL
+				templ_7745c5c3_Var49, templ_7745c5c3_Err = templ.JoinURLErrs(strValue)
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 942, Col: 21}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 975, Col: 21}
 				}
-				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var50))
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var49))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 103, "\" class=\"datatable-cell-link\">")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 102, "\" class=\"datatable-cell-link\">")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				var templ_7745c5c3_Var51 string
-				templ_7745c5c3_Var51, templ_7745c5c3_Err = templ.JoinStringErrs(strValue)
+				var templ_7745c5c3_Var50 string
+				templ_7745c5c3_Var50, templ_7745c5c3_Err = templ.JoinStringErrs(strValue)
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 942, Col: 62}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 975, Col: 62}
 				}
-				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var51))
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var50))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 104, "</a>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 103, "</a>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 			}
 		case ColumnTypeImage:
 			if strValue, ok := value.(string); ok {
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 105, "<img src=\"")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 104, "<img src=\"")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				var templ_7745c5c3_Var52 string
-				templ_7745c5c3_Var52, templ_7745c5c3_Err = templ.JoinStringErrs(strValue)
+				var templ_7745c5c3_Var51 string
+				templ_7745c5c3_Var51, templ_7745c5c3_Err = templ.JoinStringErrs(strValue)
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 946, Col: 22}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 979, Col: 22}
 				}
-				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var52))
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var51))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 106, "\" alt=\"Cell image\" class=\"datatable-cell-image\">")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 105, "\" alt=\"Cell image\" class=\"datatable-cell-image\">")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 			}
 		default:
 			if strValue, ok := value.(string); ok {
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 107, "<span class=\"datatable-cell-text\">")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 106, "<span class=\"datatable-cell-text\">")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				var templ_7745c5c3_Var53 string
-				templ_7745c5c3_Var53, templ_7745c5c3_Err = templ.JoinStringErrs(strValue)
+				var templ_7745c5c3_Var52 string
+				templ_7745c5c3_Var52, templ_7745c5c3_Err = templ.JoinStringErrs(strValue)
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 950, Col: 47}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 983, Col: 47}
 				}
-				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var53))
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var52))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 108, "</span>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 107, "</span>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 			} else if value != nil {
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 109, "<span class=\"datatable-cell-text\">")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 108, "<span class=\"datatable-cell-text\">")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				var templ_7745c5c3_Var54 string
-				templ_7745c5c3_Var54, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("%v", value))
+				var templ_7745c5c3_Var53 string
+				templ_7745c5c3_Var53, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("%v", value))
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 952, Col: 63}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 985, Col: 63}
 				}
-				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var54))
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var53))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 110, "</span>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 109, "</span>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
@@ -2044,16 +2065,16 @@ func dataTableRowActions(row DataTableRow, props DataTableProps) templ.Component
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var55 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var55 == nil {
-			templ_7745c5c3_Var55 = templ.NopComponent
+		templ_7745c5c3_Var54 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var54 == nil {
+			templ_7745c5c3_Var54 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 111, "<div class=\"datatable-row-actions\" x-data=\"{ open: false }\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 110, "<div class=\"datatable-row-actions\" x-data=\"{ open: false }\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Var56 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_Var55 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 			templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 			templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 			if !templ_7745c5c3_IsBuffer {
@@ -2067,7 +2088,7 @@ func dataTableRowActions(row DataTableRow, props DataTableProps) templ.Component
 			ctx = templ.InitializeContext(ctx)
 			templ_7745c5c3_Err = atoms.Icon(atoms.IconProps{
 				Name: "more-vertical",
-				Size: atoms.IconSizeXS,
+				Size: components.SizeXs,
 			}).Render(ctx, templ_7745c5c3_Buffer)
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
@@ -2077,12 +2098,12 @@ func dataTableRowActions(row DataTableRow, props DataTableProps) templ.Component
 		templ_7745c5c3_Err = atoms.Button(atoms.ButtonProps{
 			Variant: "ghost",
 			Size:    "sm",
-			OnClick: "open = !open",
-		}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var56), templ_7745c5c3_Buffer)
+			Base:    components.BaseProps{Events: components.EventHandlers{OnClick: "open = !open"}},
+		}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var55), templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 112, "<div class=\"datatable-row-actions-dropdown\" x-show=\"open\" x-on:click.away=\"open = false\" x-transition>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 111, "<div class=\"datatable-row-actions-dropdown\" x-show=\"open\" x-on:click.away=\"open = false\" x-transition>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -2101,7 +2122,7 @@ func dataTableRowActions(row DataTableRow, props DataTableProps) templ.Component
 				return templ_7745c5c3_Err
 			}
 		}
-		for _, action := range props.RowActions {
+		for _, action := range props.rowActions() {
 			templ_7745c5c3_Err = molecules.MenuItem(molecules.MenuItemProps{
 				Type:    molecules.MenuItemTypeButton,
 				Text:    action.Text,
@@ -2112,7 +2133,7 @@ func dataTableRowActions(row DataTableRow, props DataTableProps) templ.Component
 				return templ_7745c5c3_Err
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 113, "</div></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 112, "</div></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -2137,34 +2158,34 @@ func dataTableVirtualized(props DataTableProps) templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var57 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var57 == nil {
-			templ_7745c5c3_Var57 = templ.NopComponent
+		templ_7745c5c3_Var56 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var56 == nil {
+			templ_7745c5c3_Var56 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 114, "<div class=\"datatable-virtual-container\" x-ref=\"virtualContainer\"><div class=\"datatable-virtual-header\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 113, "<div class=\"datatable-virtual-container\" x-ref=\"virtualContainer\"><div class=\"datatable-virtual-header\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		var templ_7745c5c3_Var58 = []any{getTableClasses(props)}
-		templ_7745c5c3_Err = templ.RenderCSSItems(ctx, templ_7745c5c3_Buffer, templ_7745c5c3_Var58...)
+		var templ_7745c5c3_Var57 = []any{getTableClasses(props)}
+		templ_7745c5c3_Err = templ.RenderCSSItems(ctx, templ_7745c5c3_Buffer, templ_7745c5c3_Var57...)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 115, "<table class=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 114, "<table class=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		var templ_7745c5c3_Var59 string
-		templ_7745c5c3_Var59, templ_7745c5c3_Err = templ.JoinStringErrs(templ.CSSClasses(templ_7745c5c3_Var58).String())
+		var templ_7745c5c3_Var58 string
+		templ_7745c5c3_Var58, templ_7745c5c3_Err = templ.JoinStringErrs(templ.CSSClasses(templ_7745c5c3_Var57).String())
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 1, Col: 0}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 1, Col: 0}
 		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var59))
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var58))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 116, "\"><thead>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 115, "\"><thead>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -2172,29 +2193,29 @@ func dataTableVirtualized(props DataTableProps) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 117, "</thead></table></div><div class=\"datatable-virtual-body\" x-ref=\"virtualBody\" x-on:scroll=\"handleVirtualScroll()\"><div class=\"datatable-virtual-spacer-top\" x-ref=\"spacerTop\"></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 116, "</thead></table></div><div class=\"datatable-virtual-body\" x-ref=\"virtualBody\" x-on:scroll=\"handleVirtualScroll()\"><div class=\"datatable-virtual-spacer-top\" x-ref=\"spacerTop\"></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		var templ_7745c5c3_Var60 = []any{getTableClasses(props)}
-		templ_7745c5c3_Err = templ.RenderCSSItems(ctx, templ_7745c5c3_Buffer, templ_7745c5c3_Var60...)
+		var templ_7745c5c3_Var59 = []any{getTableClasses(props)}
+		templ_7745c5c3_Err = templ.RenderCSSItems(ctx, templ_7745c5c3_Buffer, templ_7745c5c3_Var59...)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 118, "<table class=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 117, "<table class=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		var templ_7745c5c3_Var61 string
-		templ_7745c5c3_Var61, templ_7745c5c3_Err = templ.JoinStringErrs(templ.CSSClasses(templ_7745c5c3_Var60).String())
+		var templ_7745c5c3_Var60 string
+		templ_7745c5c3_Var60, templ_7745c5c3_Err = templ.JoinStringErrs(templ.CSSClasses(templ_7745c5c3_Var59).String())
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 1, Col: 0}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 1, Col: 0}
 		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var61))
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var60))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 119, "\"><tbody><template x-for=\"row in visibleRows\" :key=\"row.id\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 118, "\"><tbody><template x-for=\"row in visibleRows\" :key=\"row.id\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -2202,7 +2223,7 @@ func dataTableVirtualized(props DataTableProps) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 120, "</template></tbody></table><div class=\"datatable-virtual-spacer-bottom\" x-ref=\"spacerBottom\"></div></div></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 119, "</template></tbody></table><div class=\"datatable-virtual-spacer-bottom\" x-ref=\"spacerBottom\"></div></div></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -2227,86 +2248,86 @@ func dataTablePagination(pagination *PaginationConfig) templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var62 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var62 == nil {
-			templ_7745c5c3_Var62 = templ.NopComponent
+		templ_7745c5c3_Var61 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var61 == nil {
+			templ_7745c5c3_Var61 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
 		if pagination.Enabled {
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 121, "<div class=\"datatable-pagination\"><div class=\"datatable-pagination-info\">")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 120, "<div class=\"datatable-pagination\"><div class=\"datatable-pagination-info\">")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 			if pagination.ShowTotal {
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 122, "<span class=\"datatable-pagination-total\">")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 121, "<span class=\"datatable-pagination-total\">")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				var templ_7745c5c3_Var63 string
-				templ_7745c5c3_Var63, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("Showing %d-%d of %d entries",
+				var templ_7745c5c3_Var62 string
+				templ_7745c5c3_Var62, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("Showing %d-%d of %d entries",
 					(pagination.CurrentPage-1)*pagination.PageSize+1,
 					min(pagination.CurrentPage*pagination.PageSize, pagination.TotalItems),
 					pagination.TotalItems,
 				))
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 1044, Col: 7}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 1077, Col: 7}
 				}
-				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var63))
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var62))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 123, "</span> ")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 122, "</span> ")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 			}
 			if pagination.ShowPageSize {
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 124, "<select class=\"datatable-pagination-page-size\" x-model=\"pageSize\" x-on:change=\"changePageSize()\">")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 123, "<select class=\"datatable-pagination-page-size\" x-model=\"pageSize\" x-on:change=\"changePageSize()\">")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 				for _, size := range pagination.PageSizeOptions {
-					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 125, "<option value=\"")
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 124, "<option value=\"")
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
-					var templ_7745c5c3_Var64 string
-					templ_7745c5c3_Var64, templ_7745c5c3_Err = templ.JoinStringErrs(strconv.Itoa(size))
+					var templ_7745c5c3_Var63 string
+					templ_7745c5c3_Var63, templ_7745c5c3_Err = templ.JoinStringErrs(strconv.Itoa(size))
 					if templ_7745c5c3_Err != nil {
-						return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 1051, Col: 41}
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 1084, Col: 41}
 					}
-					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var64))
+					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var63))
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
-					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 126, "\">")
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 125, "\">")
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
-					var templ_7745c5c3_Var65 string
-					templ_7745c5c3_Var65, templ_7745c5c3_Err = templ.JoinStringErrs(strconv.Itoa(size))
+					var templ_7745c5c3_Var64 string
+					templ_7745c5c3_Var64, templ_7745c5c3_Err = templ.JoinStringErrs(strconv.Itoa(size))
 					if templ_7745c5c3_Err != nil {
-						return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/datatable.templ`, Line: 1051, Col: 64}
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `datatable.templ`, Line: 1084, Col: 64}
 					}
-					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var65))
+					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var64))
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
-					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 127, " per page</option>")
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 126, " per page</option>")
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 128, "</select>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 127, "</select>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 129, "</div><div class=\"datatable-pagination-controls\">")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 128, "</div><div class=\"datatable-pagination-controls\">")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Var66 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+			templ_7745c5c3_Var65 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 				templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 				templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 				if !templ_7745c5c3_IsBuffer {
@@ -2320,7 +2341,7 @@ func dataTablePagination(pagination *PaginationConfig) templ.Component {
 				ctx = templ.InitializeContext(ctx)
 				templ_7745c5c3_Err = atoms.Icon(atoms.IconProps{
 					Name: "chevron-left",
-					Size: atoms.IconSizeXS,
+					Size: components.SizeXs,
 				}).Render(ctx, templ_7745c5c3_Buffer)
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
@@ -2328,34 +2349,36 @@ func dataTablePagination(pagination *PaginationConfig) templ.Component {
 				return nil
 			})
 			templ_7745c5c3_Err = atoms.Button(atoms.ButtonProps{
-				Variant:  "outline",
-				Size:     "sm",
-				OnClick:  "previousPage()",
-				Disabled: pagination.CurrentPage <= 1,
-			}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var66), templ_7745c5c3_Buffer)
+				Variant: "outline",
+				Size:    "sm",
+				Base: components.BaseProps{
+					Events: components.EventHandlers{OnClick: "previousPage()"},
+					State:  components.ComponentState{Disabled: pagination.CurrentPage <= 1},
+				},
+			}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var65), templ_7745c5c3_Buffer)
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 130, "<div class=\"datatable-pagination-pages\">")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 129, "<div class=\"datatable-pagination-pages\">")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 			for i := 1; i <= pagination.TotalPages; i++ {
 				templ_7745c5c3_Err = atoms.Button(atoms.ButtonProps{
 					Text:    strconv.Itoa(i),
-					Variant: utils.IfElse(i == pagination.CurrentPage, "primary", "ghost"),
+					Variant: utils.IfElse(i == pagination.CurrentPage, components.ButtonPrimary, components.ButtonGhost),
 					Size:    "sm",
-					OnClick: fmt.Sprintf("goToPage(%d)", i),
+					Base:    components.BaseProps{Events: components.EventHandlers{OnClick: fmt.Sprintf("goToPage(%d)", i)}},
 				}).Render(ctx, templ_7745c5c3_Buffer)
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 131, "</div>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 130, "</div>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Var67 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+			templ_7745c5c3_Var66 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 				templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 				templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 				if !templ_7745c5c3_IsBuffer {
@@ -2369,7 +2392,7 @@ func dataTablePagination(pagination *PaginationConfig) templ.Component {
 				ctx = templ.InitializeContext(ctx)
 				templ_7745c5c3_Err = atoms.Icon(atoms.IconProps{
 					Name: "chevron-right",
-					Size: atoms.IconSizeXS,
+					Size: components.SizeXs,
 				}).Render(ctx, templ_7745c5c3_Buffer)
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
@@ -2377,15 +2400,17 @@ func dataTablePagination(pagination *PaginationConfig) templ.Component {
 				return nil
 			})
 			templ_7745c5c3_Err = atoms.Button(atoms.ButtonProps{
-				Variant:  "outline",
-				Size:     "sm",
-				OnClick:  "nextPage()",
-				Disabled: pagination.CurrentPage >= pagination.TotalPages,
-			}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var67), templ_7745c5c3_Buffer)
+				Variant: "outline",
+				Size:    "sm",
+				Base: components.BaseProps{
+					Events: components.EventHandlers{OnClick: "nextPage()"},
+					State:  components.ComponentState{Disabled: pagination.CurrentPage >= pagination.TotalPages},
+				},
+			}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var66), templ_7745c5c3_Buffer)
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 132, "</div></div>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 131, "</div></div>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -2411,12 +2436,12 @@ func dataTableAction(action DataTableAction) templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var68 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var68 == nil {
-			templ_7745c5c3_Var68 = templ.NopComponent
+		templ_7745c5c3_Var67 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var67 == nil {
+			templ_7745c5c3_Var67 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Var69 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_Var68 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 			templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 			templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 			if !templ_7745c5c3_IsBuffer {
@@ -2431,7 +2456,7 @@ func dataTableAction(action DataTableAction) templ.Component {
 			if action.Icon != "" {
 				templ_7745c5c3_Err = atoms.Icon(atoms.IconProps{
 					Name: action.Icon,
-					Size: atoms.IconSizeXS,
+					Size: components.SizeXs,
 				}).Render(ctx, templ_7745c5c3_Buffer)
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
@@ -2440,12 +2465,14 @@ func dataTableAction(action DataTableAction) templ.Component {
 			return nil
 		})
 		templ_7745c5c3_Err = atoms.Button(atoms.ButtonProps{
-			ID:      action.ID,
 			Text:    action.Text,
-			Variant: action.Variant,
-			Size:    action.Size,
-			OnClick: action.OnClick,
-		}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var69), templ_7745c5c3_Buffer)
+			Variant: components.ButtonVariant(action.Variant),
+			Size:    components.Size(action.Size),
+			Base: components.BaseProps{
+				ID:     action.ID,
+				Events: components.EventHandlers{OnClick: action.OnClick},
+			},
+		}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var68), templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -2470,16 +2497,16 @@ func dataTableExportButton(export *ExportConfig) templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var70 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var70 == nil {
-			templ_7745c5c3_Var70 = templ.NopComponent
+		templ_7745c5c3_Var69 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var69 == nil {
+			templ_7745c5c3_Var69 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 133, "<div class=\"datatable-export\" x-data=\"{ open: false }\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 132, "<div class=\"datatable-export\" x-data=\"{ open: false }\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Var71 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_Var70 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 			templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 			templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 			if !templ_7745c5c3_IsBuffer {
@@ -2493,12 +2520,12 @@ func dataTableExportButton(export *ExportConfig) templ.Component {
 			ctx = templ.InitializeContext(ctx)
 			templ_7745c5c3_Err = atoms.Icon(atoms.IconProps{
 				Name: "download",
-				Size: atoms.IconSizeXS,
+				Size: components.SizeXs,
 			}).Render(ctx, templ_7745c5c3_Buffer)
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 134, " Export")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 133, " Export")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -2507,12 +2534,12 @@ func dataTableExportButton(export *ExportConfig) templ.Component {
 		templ_7745c5c3_Err = atoms.Button(atoms.ButtonProps{
 			Variant: "outline",
 			Size:    "sm",
-			OnClick: "open = !open",
-		}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var71), templ_7745c5c3_Buffer)
+			Base:    components.BaseProps{Events: components.EventHandlers{OnClick: "open = !open"}},
+		}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var70), templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 135, "<div class=\"datatable-export-dropdown\" x-show=\"open\" x-on:click.away=\"open = false\" x-transition>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 134, "<div class=\"datatable-export-dropdown\" x-show=\"open\" x-on:click.away=\"open = false\" x-transition>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -2526,7 +2553,7 @@ func dataTableExportButton(export *ExportConfig) templ.Component {
 				return templ_7745c5c3_Err
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 136, "</div></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 135, "</div></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -2551,16 +2578,16 @@ func dataTableViewOptions(props DataTableProps) templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var72 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var72 == nil {
-			templ_7745c5c3_Var72 = templ.NopComponent
+		templ_7745c5c3_Var71 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var71 == nil {
+			templ_7745c5c3_Var71 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 137, "<div class=\"datatable-view-options\" x-data=\"{ open: false }\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 136, "<div class=\"datatable-view-options\" x-data=\"{ open: false }\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Var73 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_Var72 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 			templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 			templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 			if !templ_7745c5c3_IsBuffer {
@@ -2574,7 +2601,7 @@ func dataTableViewOptions(props DataTableProps) templ.Component {
 			ctx = templ.InitializeContext(ctx)
 			templ_7745c5c3_Err = atoms.Icon(atoms.IconProps{
 				Name: "settings",
-				Size: atoms.IconSizeXS,
+				Size: components.SizeXs,
 			}).Render(ctx, templ_7745c5c3_Buffer)
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
@@ -2582,15 +2609,17 @@ func dataTableViewOptions(props DataTableProps) templ.Component {
 			return nil
 		})
 		templ_7745c5c3_Err = atoms.Button(atoms.ButtonProps{
-			Variant:   "ghost",
-			Size:      "sm",
-			OnClick:   "open = !open",
-			AriaLabel: "View options",
-		}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var73), templ_7745c5c3_Buffer)
+			Variant: "ghost",
+			Size:    "sm",
+			Base: components.BaseProps{
+				Events: components.EventHandlers{OnClick: "open = !open"},
+				A11y:   components.AccessibilityProps{AriaLabel: "View options"},
+			},
+		}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var72), templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 138, "<div class=\"datatable-view-options-dropdown\" x-show=\"open\" x-on:click.away=\"open = false\" x-transition><div class=\"datatable-view-options-section\"><span class=\"datatable-view-options-label\">Density</span>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 137, "<div class=\"datatable-view-options-dropdown\" x-show=\"open\" x-on:click.away=\"open = false\" x-transition><div class=\"datatable-view-options-section\"><span class=\"datatable-view-options-label\">Density</span>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -2621,7 +2650,7 @@ func dataTableViewOptions(props DataTableProps) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 139, "</div><div class=\"datatable-view-options-section\"><span class=\"datatable-view-options-label\">Columns</span> ")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 138, "</div><div class=\"datatable-view-options-section\"><span class=\"datatable-view-options-label\">Columns</span> ")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -2636,7 +2665,7 @@ func dataTableViewOptions(props DataTableProps) templ.Component {
 				return templ_7745c5c3_Err
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 140, "</div></div></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 139, "</div></div></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -2661,17 +2690,17 @@ func dataTableExportModal(props DataTableProps) templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var74 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var74 == nil {
-			templ_7745c5c3_Var74 = templ.NopComponent
+		templ_7745c5c3_Var73 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var73 == nil {
+			templ_7745c5c3_Var73 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
 		if props.Export.Enabled {
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 141, "<div class=\"datatable-export-modal\" x-show=\"showExportModal\" x-transition><div class=\"datatable-export-modal-content\"><div class=\"datatable-export-modal-header\"><h3>Export Data</h3>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 140, "<div class=\"datatable-export-modal\" x-show=\"showExportModal\" x-transition><div class=\"datatable-export-modal-content\"><div class=\"datatable-export-modal-header\"><h3>Export Data</h3>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Var75 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+			templ_7745c5c3_Var74 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 				templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 				templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 				if !templ_7745c5c3_IsBuffer {
@@ -2685,7 +2714,7 @@ func dataTableExportModal(props DataTableProps) templ.Component {
 				ctx = templ.InitializeContext(ctx)
 				templ_7745c5c3_Err = atoms.Icon(atoms.IconProps{
 					Name: "x",
-					Size: atoms.IconSizeXS,
+					Size: components.SizeXs,
 				}).Render(ctx, templ_7745c5c3_Buffer)
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
@@ -2695,32 +2724,32 @@ func dataTableExportModal(props DataTableProps) templ.Component {
 			templ_7745c5c3_Err = atoms.Button(atoms.ButtonProps{
 				Variant: "ghost",
 				Size:    "sm",
-				OnClick: "showExportModal = false",
-			}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var75), templ_7745c5c3_Buffer)
+				Base:    components.BaseProps{Events: components.EventHandlers{OnClick: "showExportModal = false"}},
+			}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var74), templ_7745c5c3_Buffer)
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 142, "</div><div class=\"datatable-export-modal-body\"><!-- Export options would go here --></div><div class=\"datatable-export-modal-footer\">")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 141, "</div><div class=\"datatable-export-modal-body\"><!-- Export options would go here --></div><div class=\"datatable-export-modal-footer\">")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 			templ_7745c5c3_Err = atoms.Button(atoms.ButtonProps{
 				Text:    "Cancel",
 				Variant: "outline",
-				OnClick: "showExportModal = false",
+				Base:    components.BaseProps{Events: components.EventHandlers{OnClick: "showExportModal = false"}},
 			}).Render(ctx, templ_7745c5c3_Buffer)
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 			templ_7745c5c3_Err = atoms.Button(atoms.ButtonProps{
 				Text:    "Export",
-				Variant: stringPrimary,
-				OnClick: "confirmExport()",
+				Variant: components.ButtonPrimary,
+				Base:    components.BaseProps{Events: components.EventHandlers{OnClick: "confirmExport()"}},
 			}).Render(ctx, templ_7745c5c3_Buffer)
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 143, "</div></div></div>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 142, "</div></div></div>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -2819,8 +2848,8 @@ func buildDataTableAlpineDataV2(props DataTableProps) string {
 		"props.rows", // This would be replaced with actual row data
 		props.Pagination.CurrentPage,
 		props.Pagination.PageSize,
-		utils.IfElse(props.StatePersist, "this.loadState();", ""),
-		utils.IfElse(props.Virtualized, `
+		utils.IfElse(props.persistState(), "this.loadState();", ""),
+		utils.IfElse(props.virtualized(), `
 		handleVirtualScroll() {
 			// Virtual scrolling implementation
 		},
@@ -2845,12 +2874,12 @@ func dataTableAnalytics(analytics *DataTableAnalyticsConfig) templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var76 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var76 == nil {
-			templ_7745c5c3_Var76 = templ.NopComponent
+		templ_7745c5c3_Var75 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var75 == nil {
+			templ_7745c5c3_Var75 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 144, "<script>\n\t\t// DataTable analytics tracking\n\t\tif (typeof gtag !== 'undefined' || typeof analytics !== 'undefined') {\n\t\t\t// Track sorting actions\n\t\t\tif ({ fmt.Sprintf(\"%t\", analytics.TrackSort) }) {\n\t\t\t\tdocument.addEventListener('table-sorted', function(e) {\n\t\t\t\t\tconst eventName = '{ analytics.EventPrefix }datatable_sort';\n\t\t\t\t\tconst eventData = {\n\t\t\t\t\t\tcolumn: e.detail.column,\n\t\t\t\t\t\tdirection: e.detail.direction,\n\t\t\t\t\t\ttimestamp: new Date().toISOString()\n\t\t\t\t\t};\n\t\t\t\t\t\n\t\t\t\t\tif (typeof gtag !== 'undefined') {\n\t\t\t\t\t\tgtag('event', eventName, eventData);\n\t\t\t\t\t}\n\t\t\t\t\t\n\t\t\t\t\tif (typeof analytics !== 'undefined') {\n\t\t\t\t\t\tanalytics.track(eventName, eventData);\n\t\t\t\t\t}\n\t\t\t\t});\n\t\t\t}\n\t\t\t\n\t\t\t// Track filtering actions\n\t\t\tif ({ fmt.Sprintf(\"%t\", analytics.TrackFilter) }) {\n\t\t\t\tdocument.addEventListener('table-filtered', function(e) {\n\t\t\t\t\tconst eventName = '{ analytics.EventPrefix }datatable_filter';\n\t\t\t\t\tconst eventData = {\n\t\t\t\t\t\tfilter_type: e.detail.type,\n\t\t\t\t\t\tfilter_value: e.detail.value,\n\t\t\t\t\t\tresults_count: e.detail.results || 0,\n\t\t\t\t\t\ttimestamp: new Date().toISOString()\n\t\t\t\t\t};\n\t\t\t\t\t\n\t\t\t\t\tif (typeof gtag !== 'undefined') {\n\t\t\t\t\t\tgtag('event', eventName, eventData);\n\t\t\t\t\t}\n\t\t\t\t\t\n\t\t\t\t\tif (typeof analytics !== 'undefined') {\n\t\t\t\t\t\tanalytics.track(eventName, eventData);\n\t\t\t\t\t}\n\t\t\t\t});\n\t\t\t}\n\t\t\t\n\t\t\t// Track export actions\n\t\t\tif ({ fmt.Sprintf(\"%t\", analytics.TrackExport) }) {\n\t\t\t\tdocument.addEventListener('table-exported', function(e) {\n\t\t\t\t\tconst eventName = '{ analytics.EventPrefix }datatable_export';\n\t\t\t\t\tconst eventData = {\n\t\t\t\t\t\tformat: e.detail.format,\n\t\t\t\t\t\trow_count: e.detail.rowCount || 0,\n\t\t\t\t\t\ttimestamp: new Date().toISOString()\n\t\t\t\t\t};\n\t\t\t\t\t\n\t\t\t\t\tif (typeof gtag !== 'undefined') {\n\t\t\t\t\t\tgtag('event', eventName, eventData);\n\t\t\t\t\t}\n\t\t\t\t\t\n\t\t\t\t\tif (typeof analytics !== 'undefined') {\n\t\t\t\t\t\tanalytics.track(eventName, eventData);\n\t\t\t\t\t}\n\t\t\t\t});\n\t\t\t}\n\t\t\t\n\t\t\t// Track selection actions\n\t\t\tif ({ fmt.Sprintf(\"%t\", analytics.TrackSelection) }) {\n\t\t\t\tdocument.addEventListener('table-selection-changed', function(e) {\n\t\t\t\t\tconst eventName = '{ analytics.EventPrefix }datatable_selection';\n\t\t\t\t\tconst eventData = {\n\t\t\t\t\t\tselection_type: e.detail.type, // 'single', 'multiple', 'all'\n\t\t\t\t\t\tselected_count: e.detail.count,\n\t\t\t\t\t\ttimestamp: new Date().toISOString()\n\t\t\t\t\t};\n\t\t\t\t\t\n\t\t\t\t\tif (typeof gtag !== 'undefined') {\n\t\t\t\t\t\tgtag('event', eventName, eventData);\n\t\t\t\t\t}\n\t\t\t\t\t\n\t\t\t\t\tif (typeof analytics !== 'undefined') {\n\t\t\t\t\t\tanalytics.track(eventName, eventData);\n\t\t\t\t\t}\n\t\t\t\t});\n\t\t\t}\n\t\t}\n\t</script>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 143, "<script>\n\t\t// DataTable analytics tracking\n\t\tif (typeof gtag !== 'undefined' || typeof analytics !== 'undefined') {\n\t\t\t// Track sorting actions\n\t\t\tif ({ fmt.Sprintf(\"%t\", analytics.TrackSort) }) {\n\t\t\t\tdocument.addEventListener('table-sorted', function(e) {\n\t\t\t\t\tconst eventName = '{ analytics.EventPrefix }datatable_sort';\n\t\t\t\t\tconst eventData = {\n\t\t\t\t\t\tcolumn: e.detail.column,\n\t\t\t\t\t\tdirection: e.detail.direction,\n\t\t\t\t\t\ttimestamp: new Date().toISOString()\n\t\t\t\t\t};\n\t\t\t\t\t\n\t\t\t\t\tif (typeof gtag !== 'undefined') {\n\t\t\t\t\t\tgtag('event', eventName, eventData);\n\t\t\t\t\t}\n\t\t\t\t\t\n\t\t\t\t\tif (typeof analytics !== 'undefined') {\n\t\t\t\t\t\tanalytics.track(eventName, eventData);\n\t\t\t\t\t}\n\t\t\t\t});\n\t\t\t}\n\t\t\t\n\t\t\t// Track filtering actions\n\t\t\tif ({ fmt.Sprintf(\"%t\", analytics.TrackFilter) }) {\n\t\t\t\tdocument.addEventListener('table-filtered', function(e) {\n\t\t\t\t\tconst eventName = '{ analytics.EventPrefix }datatable_filter';\n\t\t\t\t\tconst eventData = {\n\t\t\t\t\t\tfilter_type: e.detail.type,\n\t\t\t\t\t\tfilter_value: e.detail.value,\n\t\t\t\t\t\tresults_count: e.detail.results || 0,\n\t\t\t\t\t\ttimestamp: new Date().toISOString()\n\t\t\t\t\t};\n\t\t\t\t\t\n\t\t\t\t\tif (typeof gtag !== 'undefined') {\n\t\t\t\t\t\tgtag('event', eventName, eventData);\n\t\t\t\t\t}\n\t\t\t\t\t\n\t\t\t\t\tif (typeof analytics !== 'undefined') {\n\t\t\t\t\t\tanalytics.track(eventName, eventData);\n\t\t\t\t\t}\n\t\t\t\t});\n\t\t\t}\n\t\t\t\n\t\t\t// Track export actions\n\t\t\tif ({ fmt.Sprintf(\"%t\", analytics.TrackExport) }) {\n\t\t\t\tdocument.addEventListener('table-exported', function(e) {\n\t\t\t\t\tconst eventName = '{ analytics.EventPrefix }datatable_export';\n\t\t\t\t\tconst eventData = {\n\t\t\t\t\t\tformat: e.detail.format,\n\t\t\t\t\t\trow_count: e.detail.rowCount || 0,\n\t\t\t\t\t\ttimestamp: new Date().toISOString()\n\t\t\t\t\t};\n\t\t\t\t\t\n\t\t\t\t\tif (typeof gtag !== 'undefined') {\n\t\t\t\t\t\tgtag('event', eventName, eventData);\n\t\t\t\t\t}\n\t\t\t\t\t\n\t\t\t\t\tif (typeof analytics !== 'undefined') {\n\t\t\t\t\t\tanalytics.track(eventName, eventData);\n\t\t\t\t\t}\n\t\t\t\t});\n\t\t\t}\n\t\t\t\n\t\t\t// Track selection actions\n\t\t\tif ({ fmt.Sprintf(\"%t\", analytics.TrackSelection) }) {\n\t\t\t\tdocument.addEventListener('table-selection-changed', function(e) {\n\t\t\t\t\tconst eventName = '{ analytics.EventPrefix }datatable_selection';\n\t\t\t\t\tconst eventData = {\n\t\t\t\t\t\tselection_type: e.detail.type, // 'single', 'multiple', 'all'\n\t\t\t\t\t\tselected_count: e.detail.count,\n\t\t\t\t\t\ttimestamp: new Date().toISOString()\n\t\t\t\t\t};\n\t\t\t\t\t\n\t\t\t\t\tif (typeof gtag !== 'undefined') {\n\t\t\t\t\t\tgtag('event', eventName, eventData);\n\t\t\t\t\t}\n\t\t\t\t\t\n\t\t\t\t\tif (typeof analytics !== 'undefined') {\n\t\t\t\t\t\tanalytics.track(eventName, eventData);\n\t\t\t\t\t}\n\t\t\t\t});\n\t\t\t}\n\t\t}\n\t</script>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -2875,12 +2904,12 @@ func dataTableJavaScript(props DataTableProps) templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var77 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var77 == nil {
-			templ_7745c5c3_Var77 = templ.NopComponent
+		templ_7745c5c3_Var76 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var76 == nil {
+			templ_7745c5c3_Var76 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 145, "<script type=\"module\">\n\t\t// Import the DataTable JavaScript library\n\t\timport { DataTable } from '/static/dist/datatable.js';\n\t\t\n\t\t// Wait for DOM to be ready\n\t\tdocument.addEventListener('DOMContentLoaded', function() {\n\t\t\t// Find the table element\n\t\t\tconst tableElement = document.querySelector('#{ props.ID } table');\n\t\t\tif (!tableElement) return;\n\t\t\t\n\t\t\t// Configure DataTable options from Go props\n\t\t\tconst config = {\n\t\t\t\t// Core configuration\n\t\t\t\tdata: {\n\t\t\t\t\tcolumns: [\n\t\t\t\t\t\t{ for i, column := range props.Columns }\n\t\t\t\t\t\t\t{\n\t\t\t\t\t\t\t\tkey: '{ column.Key }',\n\t\t\t\t\t\t\t\ttitle: '{ column.Title }',\n\t\t\t\t\t\t\t\ttype: '{ string(column.Type) }',\n\t\t\t\t\t\t\t\tsortable: { fmt.Sprintf(\"%t\", column.Sortable) },\n\t\t\t\t\t\t\t\tsearchable: { fmt.Sprintf(\"%t\", column.Searchable) },\n\t\t\t\t\t\t\t\twidth: '{ column.Width }',\n\t\t\t\t\t\t\t\talign: '{ column.Align }'\n\t\t\t\t\t\t\t}{ if i < len(props.Columns)-1 },{ end }\n\t\t\t\t\t\t{ end }\n\t\t\t\t\t],\n\t\t\t\t\trows: [\n\t\t\t\t\t\t{ for i, row := range props.Data }\n\t\t\t\t\t\t\t{\n\t\t\t\t\t\t\t\t{ for j, column := range props.Columns }\n\t\t\t\t\t\t\t\t\t'{ column.Key }': { if value, exists := row[column.Key]; exists }'{ fmt.Sprintf(\"%v\", value) }'{ else }''{ end }{ if j < len(props.Columns)-1 },{ end }\n\t\t\t\t\t\t\t\t{ end }\n\t\t\t\t\t\t\t}{ if i < len(props.Data)-1 },{ end }\n\t\t\t\t\t\t{ end }\n\t\t\t\t\t]\n\t\t\t\t},\n\t\t\t\t\n\t\t\t\t// Feature configuration\n\t\t\t\tfeatures: {\n\t\t\t\t\t{ if hasSorting(props) }\n\t\t\t\t\t\tsorting: {\n\t\t\t\t\t\t\tenabled: true,\n\t\t\t\t\t\t\t{ if props.Sorting.MultiColumn }multiColumn: true,{ end }\n\t\t\t\t\t\t\t{ if props.Sorting.DefaultSort.Column != \"\" }\n\t\t\t\t\t\t\t\tdefaultSort: {\n\t\t\t\t\t\t\t\t\tcolumn: '{ props.Sorting.DefaultSort.Column }',\n\t\t\t\t\t\t\t\t\tdirection: '{ props.Sorting.DefaultSort.Direction }'\n\t\t\t\t\t\t\t\t}\n\t\t\t\t\t\t\t{ end }\n\t\t\t\t\t\t},\n\t\t\t\t\t{ end }\n\t\t\t\t\t\n\t\t\t\t\t{ if hasFiltering(props) }\n\t\t\t\t\t\tfiltering: {\n\t\t\t\t\t\t\tenabled: true,\n\t\t\t\t\t\t\t{ if props.Filtering.GlobalSearch }globalSearch: true,{ end }\n\t\t\t\t\t\t\t{ if props.Filtering.ColumnFilters }columnFilters: true,{ end }\n\t\t\t\t\t\t},\n\t\t\t\t\t{ end }\n\t\t\t\t\t\n\t\t\t\t\t{ if hasPagination(props) }\n\t\t\t\t\t\tpagination: {\n\t\t\t\t\t\t\tenabled: true,\n\t\t\t\t\t\t\tpageSize: { props.Pagination.PageSize },\n\t\t\t\t\t\t\t{ if props.Pagination.ShowPageSize }showPageSize: true,{ end }\n\t\t\t\t\t\t\t{ if props.Pagination.ShowInfo }showInfo: true,{ end }\n\t\t\t\t\t\t},\n\t\t\t\t\t{ end }\n\t\t\t\t\t\n\t\t\t\t\t{ if hasSelection(props) }\n\t\t\t\t\t\tselection: {\n\t\t\t\t\t\t\tenabled: true,\n\t\t\t\t\t\t\t{ if props.Selection.Multiple }multiple: true,{ end }\n\t\t\t\t\t\t\t{ if props.Selection.ShowSelectAll }showSelectAll: true,{ end }\n\t\t\t\t\t\t},\n\t\t\t\t\t{ end }\n\t\t\t\t\t\n\t\t\t\t\t{ if hasExport(props) }\n\t\t\t\t\t\texport: {\n\t\t\t\t\t\t\tenabled: true,\n\t\t\t\t\t\t\tformats: [\n\t\t\t\t\t\t\t\t{ for i, format := range props.Export.Formats }\n\t\t\t\t\t\t\t\t\t'{ string(format) }'{ if i < len(props.Export.Formats)-1 },{ end }\n\t\t\t\t\t\t\t\t{ end }\n\t\t\t\t\t\t\t]\n\t\t\t\t\t\t},\n\t\t\t\t\t{ end }\n\t\t\t\t\t\n\t\t\t\t\t{ if hasPerformance(props) && props.Performance.VirtualScrolling }\n\t\t\t\t\t\tvirtualScrolling: {\n\t\t\t\t\t\t\tenabled: true,\n\t\t\t\t\t\t\trowHeight: { props.Performance.RowHeight },\n\t\t\t\t\t\t\toverscan: { props.Performance.Overscan }\n\t\t\t\t\t\t},\n\t\t\t\t\t{ end }\n\t\t\t\t},\n\t\t\t\t\n\t\t\t\t// Integration with Alpine.js\n\t\t\t\t{ if props.Alpine != nil }\n\t\t\t\t\talpine: {\n\t\t\t\t\t\tdata: '{ props.Alpine.DataName }',\n\t\t\t\t\t\t{ if props.Alpine.SelectionCallback != \"\" }\n\t\t\t\t\t\t\tonSelectionChange: '{ props.Alpine.SelectionCallback }',\n\t\t\t\t\t\t{ end }\n\t\t\t\t\t\t{ if props.Alpine.SortCallback != \"\" }\n\t\t\t\t\t\t\tonSortChange: '{ props.Alpine.SortCallback }',\n\t\t\t\t\t\t{ end }\n\t\t\t\t\t\t{ if props.Alpine.FilterCallback != \"\" }\n\t\t\t\t\t\t\tonFilterChange: '{ props.Alpine.FilterCallback }',\n\t\t\t\t\t\t{ end }\n\t\t\t\t\t},\n\t\t\t\t{ end }\n\t\t\t\t\n\t\t\t\t// HTMX integration\n\t\t\t\t{ if props.HTMX != nil }\n\t\t\t\t\thtmx: {\n\t\t\t\t\t\t{ if props.HTMX.SortEndpoint != \"\" }\n\t\t\t\t\t\t\tsortEndpoint: '{ props.HTMX.SortEndpoint }',\n\t\t\t\t\t\t{ end }\n\t\t\t\t\t\t{ if props.HTMX.FilterEndpoint != \"\" }\n\t\t\t\t\t\t\tfilterEndpoint: '{ props.HTMX.FilterEndpoint }',\n\t\t\t\t\t\t{ end }\n\t\t\t\t\t\t{ if props.HTMX.PaginationEndpoint != \"\" }\n\t\t\t\t\t\t\tpaginationEndpoint: '{ props.HTMX.PaginationEndpoint }',\n\t\t\t\t\t\t{ end }\n\t\t\t\t\t\t{ if props.HTMX.Target != \"\" }\n\t\t\t\t\t\t\ttarget: '{ props.HTMX.Target }',\n\t\t\t\t\t\t{ end }\n\t\t\t\t\t\t{ if props.HTMX.Swap != \"\" }\n\t\t\t\t\t\t\tswap: '{ props.HTMX.Swap }',\n\t\t\t\t\t\t{ end }\n\t\t\t\t\t},\n\t\t\t\t{ end }\n\t\t\t};\n\t\t\t\n\t\t\t// Initialize the DataTable\n\t\t\tconst dataTable = new DataTable(tableElement, config);\n\t\t\t\n\t\t\t// Store reference for external access\n\t\t\ttableElement.dataTable = dataTable;\n\t\t\t\n\t\t\t// Emit initialization event for analytics\n\t\t\t{ if hasDataTableAnalytics(props) }\n\t\t\t\tdocument.dispatchEvent(new CustomEvent('table-initialized', {\n\t\t\t\t\tdetail: {\n\t\t\t\t\t\tid: '{ props.ID }',\n\t\t\t\t\t\trowCount: { len(props.Data) },\n\t\t\t\t\t\tcolumnCount: { len(props.Columns) },\n\t\t\t\t\t\tfeatures: Object.keys(config.features || {})\n\t\t\t\t\t}\n\t\t\t\t}));\n\t\t\t{ end }\n\t\t\t\n\t\t\t// Handle window resize for responsive behavior\n\t\t\t{ if props.Layout != nil && props.Layout.Responsive }\n\t\t\t\tlet resizeTimeout;\n\t\t\t\twindow.addEventListener('resize', function() {\n\t\t\t\t\tclearTimeout(resizeTimeout);\n\t\t\t\t\tresizeTimeout = setTimeout(function() {\n\t\t\t\t\t\tdataTable.handleResize();\n\t\t\t\t\t}, 250);\n\t\t\t\t});\n\t\t\t{ end }\n\t\t\t\n\t\t\t// Initial load completion\n\t\t\tdataTable.on('ready', function() {\n\t\t\t\t// Remove loading state if present\n\t\t\t\tconst loadingEl = document.querySelector('#{ props.ID } .datatable-loading');\n\t\t\t\tif (loadingEl) {\n\t\t\t\t\tloadingEl.style.display = 'none';\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t// Show table content\n\t\t\t\tconst tableContainer = document.querySelector('#{ props.ID } .datatable-container');\n\t\t\t\tif (tableContainer) {\n\t\t\t\t\ttableContainer.style.opacity = '1';\n\t\t\t\t}\n\t\t\t});\n\t\t\t\n\t\t\t// Error handling\n\t\t\tdataTable.on('error', function(error) {\n\t\t\t\tconsole.error('DataTable error:', error);\n\t\t\t\t\n\t\t\t\t// Show error message\n\t\t\t\tconst errorEl = document.querySelector('#{ props.ID } .datatable-error');\n\t\t\t\tif (errorEl) {\n\t\t\t\t\terrorEl.textContent = error.message || 'An error occurred';\n\t\t\t\t\terrorEl.style.display = 'block';\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t// Emit error event for analytics\n\t\t\t\t{ if hasDataTableAnalytics(props) }\n\t\t\t\t\tdocument.dispatchEvent(new CustomEvent('table-error', {\n\t\t\t\t\t\tdetail: {\n\t\t\t\t\t\t\tid: '{ props.ID }',\n\t\t\t\t\t\t\terror: error.message,\n\t\t\t\t\t\t\ttimestamp: new Date().toISOString()\n\t\t\t\t\t\t}\n\t\t\t\t\t}));\n\t\t\t\t{ end }\n\t\t\t});\n\t\t});\n\t</script>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 144, "<script type=\"module\">\n\t\t// Import the DataTable JavaScript library\n\t\timport { DataTable } from '/static/dist/datatable.js';\n\t\t\n\t\t// Wait for DOM to be ready\n\t\tdocument.addEventListener('DOMContentLoaded', function() {\n\t\t\t// Find the table element\n\t\t\tconst tableElement = document.querySelector('#{ props.ID } table');\n\t\t\tif (!tableElement) return;\n\t\t\t\n\t\t\t// Configure DataTable options from Go props\n\t\t\tconst config = {\n\t\t\t\t// Core configuration\n\t\t\t\tdata: {\n\t\t\t\t\tcolumns: [\n\t\t\t\t\t\t{ for i, column := range props.Columns }\n\t\t\t\t\t\t\t{\n\t\t\t\t\t\t\t\tkey: '{ column.Key }',\n\t\t\t\t\t\t\t\ttitle: '{ column.Title }',\n\t\t\t\t\t\t\t\ttype: '{ string(column.Type) }',\n\t\t\t\t\t\t\t\tsortable: { fmt.Sprintf(\"%t\", column.Sortable) },\n\t\t\t\t\t\t\t\tsearchable: { fmt.Sprintf(\"%t\", column.Searchable) },\n\t\t\t\t\t\t\t\twidth: '{ column.Width }',\n\t\t\t\t\t\t\t\talign: '{ column.Align }'\n\t\t\t\t\t\t\t}{ if i < len(props.Columns)-1 },{ end }\n\t\t\t\t\t\t{ end }\n\t\t\t\t\t],\n\t\t\t\t\trows: [\n\t\t\t\t\t\t{ for i, row := range props.Data }\n\t\t\t\t\t\t\t{\n\t\t\t\t\t\t\t\t{ for j, column := range props.Columns }\n\t\t\t\t\t\t\t\t\t'{ column.Key }': { if value, exists := row[column.Key]; exists }'{ fmt.Sprintf(\"%v\", value) }'{ else }''{ end }{ if j < len(props.Columns)-1 },{ end }\n\t\t\t\t\t\t\t\t{ end }\n\t\t\t\t\t\t\t}{ if i < len(props.Data)-1 },{ end }\n\t\t\t\t\t\t{ end }\n\t\t\t\t\t]\n\t\t\t\t},\n\t\t\t\t\n\t\t\t\t// Feature configuration\n\t\t\t\tfeatures: {\n\t\t\t\t\t{ if hasSorting(props) }\n\t\t\t\t\t\tsorting: {\n\t\t\t\t\t\t\tenabled: true,\n\t\t\t\t\t\t\t{ if props.Sorting.MultiColumn }multiColumn: true,{ end }\n\t\t\t\t\t\t\t{ if props.Sorting.DefaultSort.Column != \"\" }\n\t\t\t\t\t\t\t\tdefaultSort: {\n\t\t\t\t\t\t\t\t\tcolumn: '{ props.Sorting.DefaultSort.Column }',\n\t\t\t\t\t\t\t\t\tdirection: '{ props.Sorting.DefaultSort.Direction }'\n\t\t\t\t\t\t\t\t}\n\t\t\t\t\t\t\t{ end }\n\t\t\t\t\t\t},\n\t\t\t\t\t{ end }\n\t\t\t\t\t\n\t\t\t\t\t{ if hasFiltering(props) }\n\t\t\t\t\t\tfiltering: {\n\t\t\t\t\t\t\tenabled: true,\n\t\t\t\t\t\t\t{ if props.Filtering.GlobalSearch }globalSearch: true,{ end }\n\t\t\t\t\t\t\t{ if props.Filtering.ColumnFilters }columnFilters: true,{ end }\n\t\t\t\t\t\t},\n\t\t\t\t\t{ end }\n\t\t\t\t\t\n\t\t\t\t\t{ if hasPagination(props) }\n\t\t\t\t\t\tpagination: {\n\t\t\t\t\t\t\tenabled: true,\n\t\t\t\t\t\t\tpageSize: { props.Pagination.PageSize },\n\t\t\t\t\t\t\t{ if props.Pagination.ShowPageSize }showPageSize: true,{ end }\n\t\t\t\t\t\t\t{ if props.Pagination.ShowInfo }showInfo: true,{ end }\n\t\t\t\t\t\t},\n\t\t\t\t\t{ end }\n\t\t\t\t\t\n\t\t\t\t\t{ if hasSelection(props) }\n\t\t\t\t\t\tselection: {\n\t\t\t\t\t\t\tenabled: true,\n\t\t\t\t\t\t\t{ if props.Selection.Multiple }multiple: true,{ end }\n\t\t\t\t\t\t\t{ if props.Selection.ShowSelectAll }showSelectAll: true,{ end }\n\t\t\t\t\t\t},\n\t\t\t\t\t{ end }\n\t\t\t\t\t\n\t\t\t\t\t{ if hasExport(props) }\n\t\t\t\t\t\texport: {\n\t\t\t\t\t\t\tenabled: true,\n\t\t\t\t\t\t\tformats: [\n\t\t\t\t\t\t\t\t{ for i, format := range props.Export.Formats }\n\t\t\t\t\t\t\t\t\t'{ string(format) }'{ if i < len(props.Export.Formats)-1 },{ end }\n\t\t\t\t\t\t\t\t{ end }\n\t\t\t\t\t\t\t]\n\t\t\t\t\t\t},\n\t\t\t\t\t{ end }\n\t\t\t\t\t\n\t\t\t\t\t{ if hasPerformance(props) && props.Performance.VirtualScrolling }\n\t\t\t\t\t\tvirtualScrolling: {\n\t\t\t\t\t\t\tenabled: true,\n\t\t\t\t\t\t\trowHeight: { props.Performance.RowHeight },\n\t\t\t\t\t\t\toverscan: { props.Performance.Overscan }\n\t\t\t\t\t\t},\n\t\t\t\t\t{ end }\n\t\t\t\t},\n\t\t\t\t\n\t\t\t\t// Integration with Alpine.js\n\t\t\t\t{ if props.Alpine != nil }\n\t\t\t\t\talpine: {\n\t\t\t\t\t\tdata: '{ props.Alpine.DataName }',\n\t\t\t\t\t\t{ if props.Alpine.SelectionCallback != \"\" }\n\t\t\t\t\t\t\tonSelectionChange: '{ props.Alpine.SelectionCallback }',\n\t\t\t\t\t\t{ end }\n\t\t\t\t\t\t{ if props.Alpine.SortCallback != \"\" }\n\t\t\t\t\t\t\tonSortChange: '{ props.Alpine.SortCallback }',\n\t\t\t\t\t\t{ end }\n\t\t\t\t\t\t{ if props.Alpine.FilterCallback != \"\" }\n\t\t\t\t\t\t\tonFilterChange: '{ props.Alpine.FilterCallback }',\n\t\t\t\t\t\t{ end }\n\t\t\t\t\t},\n\t\t\t\t{ end }\n\t\t\t\t\n\t\t\t\t// HTMX integration\n\t\t\t\t{ if props.HTMX != nil }\n\t\t\t\t\thtmx: {\n\t\t\t\t\t\t{ if props.HTMX.SortEndpoint != \"\" }\n\t\t\t\t\t\t\tsortEndpoint: '{ props.HTMX.SortEndpoint }',\n\t\t\t\t\t\t{ end }\n\t\t\t\t\t\t{ if props.HTMX.FilterEndpoint != \"\" }\n\t\t\t\t\t\t\tfilterEndpoint: '{ props.HTMX.FilterEndpoint }',\n\t\t\t\t\t\t{ end }\n\t\t\t\t\t\t{ if props.HTMX.PaginationEndpoint != \"\" }\n\t\t\t\t\t\t\tpaginationEndpoint: '{ props.HTMX.PaginationEndpoint }',\n\t\t\t\t\t\t{ end }\n\t\t\t\t\t\t{ if props.HTMX.Target != \"\" }\n\t\t\t\t\t\t\ttarget: '{ props.HTMX.Target }',\n\t\t\t\t\t\t{ end }\n\t\t\t\t\t\t{ if props.HTMX.Swap != \"\" }\n\t\t\t\t\t\t\tswap: '{ props.HTMX.Swap }',\n\t\t\t\t\t\t{ end }\n\t\t\t\t\t},\n\t\t\t\t{ end }\n\t\t\t};\n\t\t\t\n\t\t\t// Initialize the DataTable\n\t\t\tconst dataTable = new DataTable(tableElement, config);\n\t\t\t\n\t\t\t// Store reference for external access\n\t\t\ttableElement.dataTable = dataTable;\n\t\t\t\n\t\t\t// Emit initialization event for analytics\n\t\t\t{ if hasDataTableAnalytics(props) }\n\t\t\t\tdocument.dispatchEvent(new CustomEvent('table-initialized', {\n\t\t\t\t\tdetail: {\n\t\t\t\t\t\tid: '{ props.ID }',\n\t\t\t\t\t\trowCount: { len(props.Data) },\n\t\t\t\t\t\tcolumnCount: { len(props.Columns) },\n\t\t\t\t\t\tfeatures: Object.keys(config.features || {})\n\t\t\t\t\t}\n\t\t\t\t}));\n\t\t\t{ end }\n\t\t\t\n\t\t\t// Handle window resize for responsive behavior\n\t\t\t{ if props.Layout != nil && props.Layout.Responsive }\n\t\t\t\tlet resizeTimeout;\n\t\t\t\twindow.addEventListener('resize', function() {\n\t\t\t\t\tclearTimeout(resizeTimeout);\n\t\t\t\t\tresizeTimeout = setTimeout(function() {\n\t\t\t\t\t\tdataTable.handleResize();\n\t\t\t\t\t}, 250);\n\t\t\t\t});\n\t\t\t{ end }\n\t\t\t\n\t\t\t// Initial load completion\n\t\t\tdataTable.on('ready', function() {\n\t\t\t\t// Remove loading state if present\n\t\t\t\tconst loadingEl = document.querySelector('#{ props.ID } .datatable-loading');\n\t\t\t\tif (loadingEl) {\n\t\t\t\t\tloadingEl.style.display = 'none';\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t// Show table content\n\t\t\t\tconst tableContainer = document.querySelector('#{ props.ID } .datatable-container');\n\t\t\t\tif (tableContainer) {\n\t\t\t\t\ttableContainer.style.opacity = '1';\n\t\t\t\t}\n\t\t\t});\n\t\t\t\n\t\t\t// Error handling\n\t\t\tdataTable.on('error', function(error) {\n\t\t\t\tconsole.error('DataTable error:', error);\n\t\t\t\t\n\t\t\t\t// Show error message\n\t\t\t\tconst errorEl = document.querySelector('#{ props.ID } .datatable-error');\n\t\t\t\tif (errorEl) {\n\t\t\t\t\terrorEl.textContent = error.message || 'An error occurred';\n\t\t\t\t\terrorEl.style.display = 'block';\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t// Emit error event for analytics\n\t\t\t\t{ if hasDataTableAnalytics(props) }\n\t\t\t\t\tdocument.dispatchEvent(new CustomEvent('table-error', {\n\t\t\t\t\t\tdetail: {\n\t\t\t\t\t\t\tid: '{ props.ID }',\n\t\t\t\t\t\t\terror: error.message,\n\t\t\t\t\t\t\ttimestamp: new Date().toISOString()\n\t\t\t\t\t\t}\n\t\t\t\t\t}));\n\t\t\t\t{ end }\n\t\t\t});\n\t\t});\n\t</script>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -205,7 +205,7 @@ func groupActionsByPosition(actions []Action) map[string][]Action {
 func buildAlpineConfig(id string, props FormProps, flags formFlags, depGraph map[string][]Dependency) map[string]any {
 	config := map[string]any{
 		"formId":      id,
-		"initialData": props.Fields, // This would be processed to extract initial values
+		"initialData": initialFormData(props),
 
 		// Feature flags
 		"hasValidation":   flags.hasValidation,
@@ -274,6 +274,38 @@ func buildAlpineConfig(id string, props FormProps, flags formFlags, depGraph map
 	return config
 }
 
+// initialFormData maps field names to their values, seeding the store's
+// formData that conditional bindings read
+func initialFormData(props FormProps) map[string]any {
+	data := make(map[string]any)
+	add := func(fields []Field) {
+		for _, field := range fields {
+			if field.Name == "" {
+				continue
+			}
+			switch {
+			case field.Type == molecules.FormFieldCheckbox && len(field.Options) > 0:
+				checked := make([]string, 0, len(field.Options))
+				for _, option := range field.Options {
+					if option.Selected {
+						checked = append(checked, option.Value)
+					}
+				}
+				data[field.Name] = checked
+			case field.Type == molecules.FormFieldCheckbox:
+				data[field.Name] = field.Value == "true"
+			default:
+				data[field.Name] = field.Value
+			}
+		}
+	}
+	add(props.Fields)
+	for _, section := range props.Sections {
+		add(section.Fields)
+	}
+	return data
+}
+
 // ============================================================================
 // ATTRIBUTE BUILDERS
 // ============================================================================
@@ -287,8 +319,10 @@ func buildFormAttributes(state formState) templ.Attributes {
 		"novalidate": "true",
 	}
 
-	// Alpine.js store initialization
+	// Alpine.js store initialization; input events keep formData current
 	attrs["x-data"] = fmt.Sprintf("formStore_%s()", state.id)
+	attrs["x-on:input"] = "syncField($event)"
+	attrs["x-on:change"] = "syncField($event)"
 
 	// Restoration
 	if state.hasStorageRestore {
@@ -497,8 +531,8 @@ func mergeButtonProps(custom, defaults atoms.ButtonProps) atoms.ButtonProps {
 	merged := defaults
 
 	// Override with non-zero custom values
-	if custom.ID != "" {
-		merged.ID = custom.ID
+	if custom.Base.ID != "" {
+		merged.Base.ID = custom.Base.ID
 	}
 	if custom.Type != "" {
 		merged.Type = custom.Type
@@ -512,18 +546,18 @@ func mergeButtonProps(custom, defaults atoms.ButtonProps) atoms.ButtonProps {
 	if custom.Size != "" {
 		merged.Size = custom.Size
 	}
-	if custom.OnClick != "" {
-		merged.OnClick = custom.OnClick
+	if custom.Base.Events.OnClick != "" {
+		merged.Base.Events.OnClick = custom.Base.Events.OnClick
 	}
-	if custom.ClassName != "" {
-		if defaults.ClassName != "" {
-			merged.ClassName = defaults.ClassName + " " + custom.ClassName
+	if custom.Base.ClassName != "" {
+		if defaults.Base.ClassName != "" {
+			merged.Base.ClassName = defaults.Base.ClassName + " " + custom.Base.ClassName
 		} else {
-			merged.ClassName = custom.ClassName
+			merged.Base.ClassName = custom.Base.ClassName
 		}
 	}
-	if custom.Disabled {
-		merged.Disabled = custom.Disabled
+	if custom.Base.State.Disabled {
+		merged.Base.State.Disabled = custom.Base.State.Disabled
 	}
 
 	return merged
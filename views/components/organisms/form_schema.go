@@ -0,0 +1,232 @@
+package organisms
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/a-h/templ"
+	"github.com/niiniyare/ruun/pkg/condition"
+	"github.com/niiniyare/ruun/schema"
+	"github.com/niiniyare/ruun/views/components"
+	"github.com/niiniyare/ruun/views/components/atoms"
+	"github.com/niiniyare/ruun/views/components/molecules"
+)
+
+// ============================================================================
+// SCHEMA RENDERING - schema.Renderer backed by the Form organism
+// ============================================================================
+
+// SchemaRenderer renders schemas as Form organisms and fields as FormField
+// molecules. Field and action conditionals are compiled into Alpine
+// bindings ("x-show", ":required", ":disabled", ":readonly") that
+// re-evaluate against the form's formData in the browser.
+type SchemaRenderer struct {
+	// ConditionRuntime includes condition.JSRuntime before forms that use
+	// conditional bindings. Disable it when the layout already loads the
+	// runtime.
+	ConditionRuntime bool
+}
+
+var _ schema.Renderer = (*SchemaRenderer)(nil)
+
+// NewSchemaRenderer creates a schema renderer that includes the condition
+// runtime where it is needed
+func NewSchemaRenderer() *SchemaRenderer {
+	return &SchemaRenderer{ConditionRuntime: true}
+}
+
+// Render renders a schema as a form filled with data
+func (r *SchemaRenderer) Render(ctx context.Context, s *schema.Schema, data map[string]any) (string, error) {
+	props, err := FormPropsFromSchema(s, data)
+	if err != nil {
+		return "", err
+	}
+	markup, err := renderComponent(ctx, Form(props))
+	if err != nil {
+		return "", err
+	}
+	if r.ConditionRuntime && hasConditionBindings(props) {
+		markup = conditionRuntimeScript + markup
+	}
+	return markup, nil
+}
+
+// RenderField renders a single field. Conditional state bindings are
+// rendered on the input; visibility is left to the enclosing form.
+func (r *SchemaRenderer) RenderField(ctx context.Context, field *schema.Field, value any) (string, error) {
+	converted, err := fieldFromSchema(field, value)
+	if err != nil {
+		return "", err
+	}
+	return renderComponent(ctx, molecules.FormField(converted.FormFieldProps))
+}
+
+// Format formats a field value for display, using option labels where the
+// field has options
+func (r *SchemaRenderer) Format(ctx context.Context, field *schema.Field, value any) (string, error) {
+	formatted := formatFieldValue(value)
+	for _, option := range field.Options {
+		if formatFieldValue(option.Value) == formatted {
+			return option.Label, nil
+		}
+	}
+	return formatted, nil
+}
+
+// FormPropsFromSchema maps a schema to Form props. Hidden fields and
+// actions are left out; field values come from data, then the field value,
+// then its default. Conditionals that cannot be compiled are an error.
+func FormPropsFromSchema(s *schema.Schema, data map[string]any) (FormProps, error) {
+	props := FormProps{
+		ID:          s.ID,
+		Title:       s.Title,
+		Description: s.Description,
+	}
+	for i := range s.Fields {
+		field := &s.Fields[i]
+		if field.Hidden || field.Type == schema.FieldHidden {
+			continue
+		}
+		value, exists := data[field.Name]
+		if !exists {
+			value = field.Value
+		}
+		if value == nil {
+			value = field.Default
+		}
+		converted, err := fieldFromSchema(field, value)
+		if err != nil {
+			return FormProps{}, err
+		}
+		props.Fields = append(props.Fields, converted)
+	}
+	for _, action := range s.Actions {
+		if action.Hidden {
+			continue
+		}
+		bindings, err := action.Conditional.AlpineBindings(condition.JSOptions{})
+		if err != nil {
+			return FormProps{}, fmt.Errorf("action %s: %w", action.ID, err)
+		}
+		props.Actions = append(props.Actions, Action{
+			ID:          action.ID,
+			Type:        actionButtonType(action.Type),
+			Label:       action.Text,
+			Position:    action.Position,
+			Conditional: bindings["x-show"],
+			ButtonProps: atoms.ButtonProps{
+				Variant: components.ButtonVariant(action.Variant),
+				Base: components.BaseProps{State: components.ComponentState{
+					Disabled: action.Disabled,
+					Loading:  action.Loading,
+				}},
+			},
+		})
+	}
+	return props, nil
+}
+
+func fieldFromSchema(field *schema.Field, value any) (Field, error) {
+	bindings, err := field.Conditional.AlpineBindings(condition.JSOptions{})
+	if err != nil {
+		return Field{}, fmt.Errorf("field %s: %w", field.Name, err)
+	}
+
+	formatted := formatFieldValue(value)
+	props := molecules.FormFieldProps{
+		ID:          field.Name,
+		Name:        field.Name,
+		Type:        formFieldType(field.Type),
+		Label:       field.Label,
+		Value:       formatted,
+		Placeholder: field.Placeholder,
+		HelpText:    Coalesce(field.Help, field.Description),
+		Required:    field.Required,
+		Disabled:    field.Disabled,
+		Readonly:    field.Readonly,
+	}
+	for _, option := range field.Options {
+		optionValue := formatFieldValue(option.Value)
+		props.Options = append(props.Options, molecules.FormFieldOption{
+			Value:    optionValue,
+			Label:    option.Label,
+			Selected: formatted != "" && optionValue == formatted,
+			Disabled: option.Disabled,
+		})
+	}
+	for name, expression := range bindings {
+		if name == "x-show" {
+			continue
+		}
+		if props.Attrs == nil {
+			props.Attrs = templ.Attributes{}
+		}
+		props.Attrs[name] = expression
+	}
+	return Field{FormFieldProps: props, Conditional: bindings["x-show"]}, nil
+}
+
+// conditionRuntimeScript loads the browser runtime of compiled conditions
+var conditionRuntimeScript = "<script>" + condition.JSRuntime + "</script>"
+
+func hasConditionBindings(props FormProps) bool {
+	for _, field := range props.Fields {
+		if field.Conditional != "" || len(field.Attrs) > 0 {
+			return true
+		}
+	}
+	for _, action := range props.Actions {
+		if action.Conditional != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// formFieldType maps schema field types onto the inputs the FormField
+// molecule renders; anything else is a text input
+func formFieldType(fieldType schema.FieldType) molecules.FormFieldType {
+	switch fieldType {
+	case schema.FieldEmail:
+		return molecules.FormFieldEmail
+	case schema.FieldPassword:
+		return molecules.FormFieldPassword
+	case schema.FieldNumber:
+		return molecules.FormFieldNumber
+	case schema.FieldTextarea, schema.FieldRichText, schema.FieldCode, schema.FieldJSON:
+		return molecules.FormFieldTextarea
+	case schema.FieldSelect, schema.FieldMultiSelect:
+		return molecules.FormFieldSelect
+	case schema.FieldCheckbox, schema.FieldSwitch:
+		return molecules.FormFieldCheckbox
+	case schema.FieldRadio:
+		return molecules.FormFieldRadio
+	default:
+		return molecules.FormFieldText
+	}
+}
+
+func actionButtonType(actionType schema.ActionType) string {
+	switch actionType {
+	case schema.ActionSubmit, schema.ActionReset:
+		return string(actionType)
+	default:
+		return "button"
+	}
+}
+
+func formatFieldValue(value any) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+func renderComponent(ctx context.Context, component templ.Component) (string, error) {
+	var sb strings.Builder
+	if err := component.Render(ctx, &sb); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,99 @@
+package organisms
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/niiniyare/ruun/pkg/condition"
+	"github.com/niiniyare/ruun/schema"
+)
+
+func TestSchemaRenderer(t *testing.T) {
+	s := &schema.Schema{
+		ID:    "customer",
+		Title: "Customer",
+		Fields: []schema.Field{
+			{Name: "name", Type: schema.FieldText, Label: "Name", Required: true},
+			{Name: "type", Type: schema.FieldSelect, Label: "Type", Default: "person", Options: []schema.FieldOption{
+				{Value: "person", Label: "Person"},
+				{Value: "company", Label: "Company"},
+			}},
+			{Name: "token", Type: schema.FieldHidden},
+			{Name: "internal", Type: schema.FieldText, Label: "Internal", Hidden: true},
+		},
+		Actions: []schema.Action{{ID: "save", Type: schema.ActionSubmit, Text: "Save"}},
+	}
+
+	renderer := NewSchemaRenderer()
+	markup, err := renderer.Render(context.Background(), s, map[string]any{"name": "Ada"})
+	require.NoError(t, err)
+	assert.Contains(t, markup, `name="name"`)
+	assert.Contains(t, markup, `value="Ada"`)
+	assert.Contains(t, markup, `<option value="person" selected>`)
+	assert.Contains(t, markup, `type="submit"`)
+	assert.NotContains(t, markup, `name="token"`)
+	assert.NotContains(t, markup, `name="internal"`)
+
+	field, err := renderer.RenderField(context.Background(), &s.Fields[1], "company")
+	require.NoError(t, err)
+	assert.Contains(t, field, `<option value="company" selected>`)
+
+	formatted, err := renderer.Format(context.Background(), &s.Fields[1], "company")
+	require.NoError(t, err)
+	assert.Equal(t, "Company", formatted)
+}
+
+func TestSchemaRenderer_ConditionalBindings(t *testing.T) {
+	isCompany := func(id string) *condition.ConditionGroup {
+		return &condition.ConditionGroup{
+			ID:          id,
+			Conjunction: condition.ConjunctionAnd,
+			Children: []any{&condition.ConditionRule{
+				ID:    id + "_rule",
+				Left:  condition.Expression{Type: condition.ValueTypeField, Field: "type"},
+				Op:    condition.OpEqual,
+				Right: "company",
+			}},
+		}
+	}
+	s := &schema.Schema{
+		ID: "customer",
+		Fields: []schema.Field{
+			{Name: "type", Type: schema.FieldSelect, Label: "Type", Options: []schema.FieldOption{
+				{Value: "person", Label: "Person"},
+				{Value: "company", Label: "Company"},
+			}},
+			{Name: "company", Type: schema.FieldText, Label: "Company", Conditional: &schema.Conditional{Show: isCompany("show")}},
+			{Name: "vat", Type: schema.FieldTextarea, Label: "VAT", Conditional: &schema.Conditional{Required: isCompany("required")}},
+		},
+		Actions: []schema.Action{{ID: "verify", Text: "Verify", Conditional: &schema.Conditional{Show: isCompany("verify")}}},
+	}
+
+	renderer := NewSchemaRenderer()
+	markup, err := renderer.Render(context.Background(), s, map[string]any{"type": "person"})
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(markup, conditionRuntimeScript), "the condition runtime is included")
+	assert.Equal(t, 2, strings.Count(markup, `x-show="ruunCondition.test(`), "field and action visibility")
+	assert.Contains(t, markup, `:required="ruunCondition.`)
+	assert.Contains(t, markup, `x-on:change="syncField($event)"`)
+	assert.Contains(t, markup, `\"initialData\":{\"company\":\"\",\"type\":\"person\",\"vat\":\"\"}`,
+		"formData is seeded with field values")
+
+	field, err := renderer.RenderField(context.Background(), &s.Fields[2], nil)
+	require.NoError(t, err)
+	assert.Contains(t, field, `:required=`)
+
+	renderer.ConditionRuntime = false
+	markup, err = renderer.Render(context.Background(), s, nil)
+	require.NoError(t, err)
+	assert.NotContains(t, markup, conditionRuntimeScript)
+
+	plain, err := NewSchemaRenderer().Render(context.Background(), &schema.Schema{ID: "plain", Fields: s.Fields[:1]}, nil)
+	require.NoError(t, err)
+	assert.NotContains(t, plain, condition.JSRuntimeName)
+}
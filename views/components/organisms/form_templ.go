@@ -10,6 +10,7 @@ import templruntime "github.com/a-h/templ/runtime"
 
 import (
 	"fmt"
+	"github.com/niiniyare/ruun/views/components"
 	"github.com/niiniyare/ruun/views/components/atoms"
 	"github.com/niiniyare/ruun/views/components/molecules"
 )
@@ -56,7 +57,8 @@ type FormProps struct {
 	HTMX *HTMXConfig `json:"htmx,omitempty"`
 
 	// THEMING - Optional customization
-	Theme *FormThemeConfig `json:"theme,omitempty"`
+	Theme     *FormThemeConfig `json:"theme,omitempty"`
+	ClassName string           `json:"className,omitempty"`
 }
 
 // Field - Enhanced to support both simple and complex scenarios
@@ -475,7 +477,7 @@ func formHeader(props FormProps) templ.Component {
 			var templ_7745c5c3_Var4 string
 			templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(props.Title)
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/form.templ`, Line: 354, Col: 39}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/form.templ`, Line: 356, Col: 39}
 			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
 			if templ_7745c5c3_Err != nil {
@@ -494,7 +496,7 @@ func formHeader(props FormProps) templ.Component {
 			var templ_7745c5c3_Var5 string
 			templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs(props.Description)
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/form.templ`, Line: 357, Col: 50}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/form.templ`, Line: 359, Col: 50}
 			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var5))
 			if templ_7745c5c3_Err != nil {
@@ -542,7 +544,7 @@ func formProgress(config *FormProgressConfig) templ.Component {
 		var templ_7745c5c3_Var7 string
 		templ_7745c5c3_Var7, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("%d", config.CurrentStep))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/form.templ`, Line: 368, Col: 56}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/form.templ`, Line: 370, Col: 56}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var7))
 		if templ_7745c5c3_Err != nil {
@@ -555,7 +557,7 @@ func formProgress(config *FormProgressConfig) templ.Component {
 		var templ_7745c5c3_Var8 string
 		templ_7745c5c3_Var8, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("%d", config.TotalSteps))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/form.templ`, Line: 370, Col: 55}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/form.templ`, Line: 372, Col: 55}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var8))
 		if templ_7745c5c3_Err != nil {
@@ -568,7 +570,7 @@ func formProgress(config *FormProgressConfig) templ.Component {
 		var templ_7745c5c3_Var9 string
 		templ_7745c5c3_Var9, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("Step %d of %d", config.CurrentStep, config.TotalSteps))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/form.templ`, Line: 377, Col: 105}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/form.templ`, Line: 379, Col: 105}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var9))
 		if templ_7745c5c3_Err != nil {
@@ -609,9 +611,9 @@ func storageRestoreIndicator() templ.Component {
 			return templ_7745c5c3_Err
 		}
 		templ_7745c5c3_Err = atoms.Icon(atoms.IconProps{
-			Name:      "info",
-			Size:      atoms.IconSizeXS,
-			ClassName: "form-storage-icon",
+			Name: "info",
+			Size: components.SizeXs,
+			Base: components.BaseProps{ClassName: "form-storage-icon"},
 		}).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
@@ -621,10 +623,10 @@ func storageRestoreIndicator() templ.Component {
 			return templ_7745c5c3_Err
 		}
 		templ_7745c5c3_Err = atoms.Button(atoms.ButtonProps{
-			Variant: atoms.ButtonVariantGhost,
-			Size:    atoms.ButtonSizeSM,
+			Variant: components.ButtonGhost,
+			Size:    components.SizeSm,
 			Text:    "Clear",
-			OnClick: "clearStorage(); restoredFromStorage = false",
+			Base:    components.BaseProps{Events: components.EventHandlers{OnClick: "clearStorage(); restoredFromStorage = false"}},
 		}).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
@@ -691,9 +693,9 @@ func formSection(section Section, state formState) templ.Component {
 					ctx = templ.InitializeContext(ctx)
 					if section.Icon != "" {
 						templ_7745c5c3_Err = atoms.Icon(atoms.IconProps{
-							Name:      section.Icon,
-							Size:      atoms.IconSizeSM,
-							ClassName: "form-section-icon",
+							Name: section.Icon,
+							Size: components.SizeSm,
+							Base: components.BaseProps{ClassName: "form-section-icon"},
 						}).Render(ctx, templ_7745c5c3_Buffer)
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
@@ -706,7 +708,7 @@ func formSection(section Section, state formState) templ.Component {
 					var templ_7745c5c3_Var13 string
 					templ_7745c5c3_Var13, templ_7745c5c3_Err = templ.JoinStringErrs(section.Title)
 					if templ_7745c5c3_Err != nil {
-						return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/form.templ`, Line: 420, Col: 52}
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/form.templ`, Line: 424, Col: 52}
 					}
 					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var13))
 					if templ_7745c5c3_Err != nil {
@@ -717,9 +719,9 @@ func formSection(section Section, state formState) templ.Component {
 						return templ_7745c5c3_Err
 					}
 					templ_7745c5c3_Err = atoms.Icon(atoms.IconProps{
-						Name:      "chevron-down",
-						Size:      atoms.IconSizeXS,
-						ClassName: "form-section-chevron",
+						Name: "chevron-down",
+						Size: components.SizeXs,
+						Base: components.BaseProps{ClassName: "form-section-chevron"},
 					}).Render(ctx, templ_7745c5c3_Buffer)
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
@@ -727,12 +729,14 @@ func formSection(section Section, state formState) templ.Component {
 					return nil
 				})
 				templ_7745c5c3_Err = atoms.Button(atoms.ButtonProps{
-					Type:      "button",
-					Variant:   atoms.ButtonVariantGhost,
-					Size:      atoms.ButtonSizeSM,
-					ClassName: "form-section-toggle",
-					OnClick:   "collapsed = !collapsed",
-					AriaLabel: fmt.Sprintf("Toggle %s section", section.Title),
+					Type:    "button",
+					Variant: components.ButtonGhost,
+					Size:    components.SizeSm,
+					Base: components.BaseProps{
+						ClassName: "form-section-toggle",
+						Events:    components.EventHandlers{OnClick: "collapsed = !collapsed"},
+						A11y:      components.AccessibilityProps{AriaLabel: fmt.Sprintf("Toggle %s section", section.Title)},
+					},
 				}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var12), templ_7745c5c3_Buffer)
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
@@ -744,9 +748,9 @@ func formSection(section Section, state formState) templ.Component {
 				}
 				if section.Icon != "" {
 					templ_7745c5c3_Err = atoms.Icon(atoms.IconProps{
-						Name:      section.Icon,
-						Size:      atoms.IconSizeSM,
-						ClassName: "form-section-icon",
+						Name: section.Icon,
+						Size: components.SizeSm,
+						Base: components.BaseProps{ClassName: "form-section-icon"},
 					}).Render(ctx, templ_7745c5c3_Buffer)
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
@@ -759,7 +763,7 @@ func formSection(section Section, state formState) templ.Component {
 				var templ_7745c5c3_Var14 string
 				templ_7745c5c3_Var14, templ_7745c5c3_Err = templ.JoinStringErrs(section.Title)
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/form.templ`, Line: 436, Col: 52}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/form.templ`, Line: 440, Col: 52}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var14))
 				if templ_7745c5c3_Err != nil {
@@ -778,7 +782,7 @@ func formSection(section Section, state formState) templ.Component {
 				var templ_7745c5c3_Var15 string
 				templ_7745c5c3_Var15, templ_7745c5c3_Err = templ.JoinStringErrs(section.Description)
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/form.templ`, Line: 440, Col: 62}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/form.templ`, Line: 444, Col: 62}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var15))
 				if templ_7745c5c3_Err != nil {
@@ -923,7 +927,7 @@ func formField(field Field, state formState) templ.Component {
 		var templ_7745c5c3_Var20 string
 		templ_7745c5c3_Var20, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("%s-%s-feedback", state.id, field.Name))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/form.templ`, Line: 473, Col: 59}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/form.templ`, Line: 477, Col: 59}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var20))
 		if templ_7745c5c3_Err != nil {
@@ -936,7 +940,7 @@ func formField(field Field, state formState) templ.Component {
 		var templ_7745c5c3_Var21 string
 		templ_7745c5c3_Var21, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("errors.%s", field.Name))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/form.templ`, Line: 477, Col: 48}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/form.templ`, Line: 481, Col: 48}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var21))
 		if templ_7745c5c3_Err != nil {
@@ -947,9 +951,9 @@ func formField(field Field, state formState) templ.Component {
 			return templ_7745c5c3_Err
 		}
 		templ_7745c5c3_Err = atoms.Icon(atoms.IconProps{
-			Name:      "alert-circle",
-			Size:      atoms.IconSizeXS,
-			ClassName: "form-field-error-icon",
+			Name: "alert-circle",
+			Size: components.SizeXs,
+			Base: components.BaseProps{ClassName: "form-field-error-icon"},
 		}).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
@@ -961,7 +965,7 @@ func formField(field Field, state formState) templ.Component {
 		var templ_7745c5c3_Var22 string
 		templ_7745c5c3_Var22, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("errors.%s", field.Name))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/form.templ`, Line: 485, Col: 88}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/form.templ`, Line: 489, Col: 88}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var22))
 		if templ_7745c5c3_Err != nil {
@@ -974,7 +978,7 @@ func formField(field Field, state formState) templ.Component {
 		var templ_7745c5c3_Var23 string
 		templ_7745c5c3_Var23, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("validating.%s", field.Name))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/form.templ`, Line: 489, Col: 86}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/form.templ`, Line: 493, Col: 86}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var23))
 		if templ_7745c5c3_Err != nil {
@@ -985,9 +989,9 @@ func formField(field Field, state formState) templ.Component {
 			return templ_7745c5c3_Err
 		}
 		templ_7745c5c3_Err = atoms.Icon(atoms.IconProps{
-			Name:      "loader",
-			Size:      atoms.IconSizeXS,
-			ClassName: "animate-spin",
+			Name: "loader",
+			Size: components.SizeXs,
+			Base: components.BaseProps{ClassName: "animate-spin"},
 		}).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
@@ -1093,8 +1097,8 @@ func formActions(state formState) templ.Component {
 			templ_7745c5c3_Err = atoms.Button(atoms.ButtonProps{
 				Type:    "submit",
 				Text:    "Submit",
-				Variant: atoms.ButtonVariantPrimary,
-				Size:    atoms.ButtonSizeMD,
+				Variant: components.ButtonPrimary,
+				Size:    components.SizeDefault,
 			}).Render(ctx, templ_7745c5c3_Buffer)
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
@@ -1146,7 +1150,7 @@ func formAction(action Action, state formState) templ.Component {
 			var templ_7745c5c3_Var30 string
 			templ_7745c5c3_Var30, templ_7745c5c3_Err = templ.JoinStringErrs(action.Conditional)
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/form.templ`, Line: 532, Col: 30}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/components/organisms/form.templ`, Line: 536, Col: 30}
 			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var30))
 			if templ_7745c5c3_Err != nil {
@@ -1162,12 +1166,14 @@ func formAction(action Action, state formState) templ.Component {
 			return templ_7745c5c3_Err
 		}
 		templ_7745c5c3_Err = atoms.Button(mergeButtonProps(action.ButtonProps, atoms.ButtonProps{
-			ID:      action.ID,
 			Type:    Coalesce(action.Type, "button"),
 			Text:    action.Label,
-			Variant: Default(action.ButtonProps.Variant, atoms.ButtonVariantSecondary),
-			Size:    Default(action.ButtonProps.Size, atoms.ButtonSizeMD),
-			OnClick: action.OnClick,
+			Variant: Default(action.ButtonProps.Variant, components.ButtonSecondary),
+			Size:    Default(action.ButtonProps.Size, components.SizeDefault),
+			Base: components.BaseProps{
+				ID:     action.ID,
+				Events: components.EventHandlers{OnClick: action.OnClick},
+			},
 		})).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
@@ -1207,9 +1213,9 @@ func autoSaveIndicator() templ.Component {
 			return templ_7745c5c3_Err
 		}
 		templ_7745c5c3_Err = atoms.Icon(atoms.IconProps{
-			Name:      "loader",
-			Size:      atoms.IconSizeXS,
-			ClassName: "form-save-spinner animate-spin",
+			Name: "loader",
+			Size: components.SizeXs,
+			Base: components.BaseProps{ClassName: "form-save-spinner animate-spin"},
 		}).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
@@ -1219,9 +1225,9 @@ func autoSaveIndicator() templ.Component {
 			return templ_7745c5c3_Err
 		}
 		templ_7745c5c3_Err = atoms.Icon(atoms.IconProps{
-			Name:      "alert-circle",
-			Size:      atoms.IconSizeXS,
-			ClassName: "form-unsaved-icon",
+			Name: "alert-circle",
+			Size: components.SizeXs,
+			Base: components.BaseProps{ClassName: "form-unsaved-icon"},
 		}).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
@@ -1231,9 +1237,9 @@ func autoSaveIndicator() templ.Component {
 			return templ_7745c5c3_Err
 		}
 		templ_7745c5c3_Err = atoms.Icon(atoms.IconProps{
-			Name:      "check-circle",
-			Size:      atoms.IconSizeXS,
-			ClassName: "form-saved-icon",
+			Name: "check-circle",
+			Size: components.SizeXs,
+			Base: components.BaseProps{ClassName: "form-saved-icon"},
 		}).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
@@ -1286,7 +1292,7 @@ func debugPanel(config *DebugConfig) templ.Component {
 			ctx = templ.InitializeContext(ctx)
 			templ_7745c5c3_Err = atoms.Icon(atoms.IconProps{
 				Name: "x",
-				Size: atoms.IconSizeXS,
+				Size: components.SizeXs,
 			}).Render(ctx, templ_7745c5c3_Buffer)
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
@@ -1294,10 +1300,12 @@ func debugPanel(config *DebugConfig) templ.Component {
 			return nil
 		})
 		templ_7745c5c3_Err = atoms.Button(atoms.ButtonProps{
-			Variant:   atoms.ButtonVariantGhost,
-			Size:      atoms.ButtonSizeSM,
-			OnClick:   "showDebug = false",
-			AriaLabel: "Close debug panel",
+			Variant: components.ButtonGhost,
+			Size:    components.SizeSm,
+			Base: components.BaseProps{
+				Events: components.EventHandlers{OnClick: "showDebug = false"},
+				A11y:   components.AccessibilityProps{AriaLabel: "Close debug panel"},
+			},
 		}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var33), templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
@@ -1315,8 +1323,8 @@ func debugPanel(config *DebugConfig) templ.Component {
 // ============================================================================
 func formAlpineStore(configJSON string) templ.ComponentScript {
 	return templ.ComponentScript{
-		Name: `__templ_formAlpineStore_d068`,
-		Function: `function __templ_formAlpineStore_d068(configJSON){const cfg = JSON.parse(configJSON);
+		Name: `__templ_formAlpineStore_3525`,
+		Function: `function __templ_formAlpineStore_3525(configJSON){const cfg = JSON.parse(configJSON);
 	
 	// Create store function for this form
 	window[` + "`" + `formStore_${cfg.formId}` + "`" + `] = function() {
@@ -1399,6 +1407,21 @@ func formAlpineStore(configJSON string) templ.ComponentScript {
 				}
 			},
 			
+			syncField(event) {
+				const target = event.target;
+				if (!target || !target.name) return;
+				
+				let value = target.value;
+				if (target.type === 'checkbox') {
+					// Checkbox groups hold the checked values, single checkboxes a boolean
+					const boxes = this.$el.querySelectorAll(` + "`" + `input[type="checkbox"][name="${CSS.escape(target.name)}"]` + "`" + `);
+					value = boxes.length > 1
+						? Array.from(boxes).filter(box => box.checked).map(box => box.value)
+						: target.checked;
+				}
+				this.updateField(target.name, value);
+			},
+			
 			updateField(name, value) {
 				this.formData[name] = value;
 				this.touched[name] = true;
@@ -1766,8 +1789,8 @@ func formAlpineStore(configJSON string) templ.ComponentScript {
 		};
 	};
 }`,
-		Call:       templ.SafeScript(`__templ_formAlpineStore_d068`, configJSON),
-		CallInline: templ.SafeScriptInline(`__templ_formAlpineStore_d068`, configJSON),
+		Call:       templ.SafeScript(`__templ_formAlpineStore_3525`, configJSON),
+		CallInline: templ.SafeScriptInline(`__templ_formAlpineStore_3525`, configJSON),
 	}
 }
 
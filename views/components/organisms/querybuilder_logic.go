@@ -0,0 +1,646 @@
+package organisms
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/niiniyare/ruun/pkg/condition"
+)
+
+// Query builder edits requested through HTMX
+const (
+	QueryBuilderRefresh  = "refresh"
+	QueryBuilderAddRule  = "add_rule"
+	QueryBuilderAddGroup = "add_group"
+	QueryBuilderRemove   = "remove"
+)
+
+// ============================================================================
+// INTERNAL STATE MANAGEMENT
+// ============================================================================
+
+// queryBuilderState holds processed rendering state (internal only)
+type queryBuilderState struct {
+	props    QueryBuilderProps
+	id       string
+	name     string
+	fields   []condition.Field
+	root     qbNode
+	maxDepth int
+	// interactive is set when edits round-trip to the server
+	interactive bool
+}
+
+// qbNode is the view model of a group or rule
+type qbNode struct {
+	path  string
+	depth int
+	error string
+
+	group    *condition.ConditionGroup
+	children []qbNode
+
+	rule       *condition.ConditionRule
+	field      condition.Field
+	operators  []condition.OperatorType
+	valueTypes []condition.ValueType
+	valueType  condition.ValueType
+	values     []string
+	rightField string
+	// rawRight preserves operands the builder cannot edit, e.g. functions
+	rawRight string
+}
+
+func (n qbNode) isGroup() bool {
+	return n.group != nil
+}
+
+func (n qbNode) id() string {
+	if n.group != nil {
+		return n.group.ID
+	}
+	return n.rule.ID
+}
+
+// ============================================================================
+// STATE BUILDING - Convert props to internal state
+// ============================================================================
+
+func buildQueryBuilderState(props QueryBuilderProps) queryBuilderState {
+	root := props.Root
+	if root == nil {
+		root = newQueryGroup()
+	}
+	name := Coalesce(props.Name, "condition")
+	maxDepth := condition.DefaultMaxDepth
+	if props.Config != nil && props.Config.MaxLevel > 0 {
+		maxDepth = props.Config.MaxLevel
+	}
+
+	return queryBuilderState{
+		props:       props,
+		id:          sanitizeID(Coalesce(props.ID, name+"-builder")),
+		name:        name,
+		fields:      props.Config.FieldList(),
+		root:        buildQueryNode(props, root, name, 0),
+		maxDepth:    maxDepth,
+		interactive: props.Endpoint != "" && !props.ReadOnly,
+	}
+}
+
+func buildQueryNode(props QueryBuilderProps, node any, path string, depth int) qbNode {
+	switch v := normaliseQueryNode(node).(type) {
+	case *condition.ConditionGroup:
+		n := qbNode{path: path, depth: depth, group: v, error: props.Errors[v.ID]}
+		for i, child := range v.Children {
+			n.children = append(n.children, buildQueryNode(props, child, fmt.Sprintf("%s.%d", path, i), depth+1))
+		}
+		return n
+	case *condition.ConditionRule:
+		return buildRuleNode(props, v, path, depth)
+	}
+	// Unknown nodes are shown as invalid rules so they can be removed
+	return qbNode{
+		path:  path,
+		depth: depth,
+		rule:  &condition.ConditionRule{ID: uuid.New().String()},
+		error: fmt.Sprintf("unsupported condition node %T", node),
+	}
+}
+
+func buildRuleNode(props QueryBuilderProps, rule *condition.ConditionRule, path string, depth int) qbNode {
+	n := qbNode{path: path, depth: depth, rule: rule, error: props.Errors[rule.ID]}
+	if rule.If != "" {
+		return n
+	}
+
+	n.field, _ = props.Config.LookupField(rule.Left.Field)
+	if n.field.Name == "" {
+		n.field = condition.Field{Name: rule.Left.Field, Label: rule.Left.Field, Type: condition.FieldTypeText}
+	}
+	n.operators = props.Config.FieldOperators(n.field)
+	if len(n.operators) == 0 {
+		n.operators = allQueryOperators
+	}
+	n.valueTypes = props.Config.FieldValueTypes(n.field)
+	n.valueType = condition.ValueTypeValue
+
+	operands, err := rule.Operands()
+	if err != nil {
+		return n
+	}
+	for _, operand := range operands {
+		switch operand.Type {
+		case condition.ValueTypeField:
+			n.valueType = condition.ValueTypeField
+			n.rightField = operand.Field
+		case condition.ValueTypeFunc:
+			n.valueType = condition.ValueTypeFunc
+			n.rawRight = mustMarshalJSON(rule.Right)
+		default:
+			// A single operand may carry the whole list for IN
+			if items, ok := operand.Value.([]any); ok {
+				for _, item := range items {
+					n.values = append(n.values, queryValueString(item))
+				}
+			} else {
+				n.values = append(n.values, queryValueString(operand.Value))
+			}
+		}
+	}
+	return n
+}
+
+// normaliseQueryNode converts JSON-decoded maps and value nodes to pointers
+func normaliseQueryNode(node any) any {
+	switch v := node.(type) {
+	case *condition.ConditionGroup, *condition.ConditionRule:
+		return v
+	case condition.ConditionGroup:
+		return &v
+	case condition.ConditionRule:
+		return &v
+	case map[string]any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return node
+		}
+		if _, hasConjunction := v["conjunction"]; hasConjunction {
+			var group condition.ConditionGroup
+			if json.Unmarshal(data, &group) == nil {
+				return &group
+			}
+			return node
+		}
+		var rule condition.ConditionRule
+		if json.Unmarshal(data, &rule) == nil {
+			return &rule
+		}
+	}
+	return node
+}
+
+// ============================================================================
+// FORM ROUND-TRIP
+// ============================================================================
+
+// QueryBuilderFromForm rebuilds the condition tree from the builder's
+// submitted inputs, applies the edit named by the "<name>.action" and
+// "<name>.target" values, and validates the result. Render the returned
+// props to answer the HTMX request, or read Root once Valid reports true.
+func QueryBuilderFromForm(props QueryBuilderProps, form url.Values) QueryBuilderProps {
+	name := Coalesce(props.Name, "condition")
+	errs := make(map[string]string)
+
+	if form.Has(name + ".kind") {
+		props.Root = parseQueryGroup(props.Config, form, name, errs)
+	}
+	if props.Root == nil {
+		props.Root = newQueryGroup()
+	}
+
+	switch form.Get(name + ".action") {
+	case QueryBuilderAddRule:
+		if group := findQueryGroup(props.Root, form.Get(name+".target")); group != nil {
+			group.Children = append(group.Children, newQueryRule(props.Config))
+		}
+	case QueryBuilderAddGroup:
+		if group := findQueryGroup(props.Root, form.Get(name+".target")); group != nil {
+			child := newQueryGroup()
+			child.Children = append(child.Children, newQueryRule(props.Config))
+			group.Children = append(group.Children, child)
+		}
+	case QueryBuilderRemove:
+		removeQueryNode(props.Root, form.Get(name+".target"))
+	}
+
+	props.Errors, props.Error = validateQueryBuilder(props.Config, props.Root, errs)
+	return props
+}
+
+// Valid reports whether the last round-trip found no errors
+func (p QueryBuilderProps) Valid() bool {
+	return p.Error == "" && len(p.Errors) == 0
+}
+
+func parseQueryGroup(config *condition.Config, form url.Values, path string, errs map[string]string) *condition.ConditionGroup {
+	group := &condition.ConditionGroup{
+		ID:          Coalesce(form.Get(path+".id"), uuid.New().String()),
+		Conjunction: condition.Conjunction(Coalesce(form.Get(path+".conjunction"), string(condition.ConjunctionAnd))),
+		Not:         form.Get(path+".not") == "true",
+		If:          form.Get(path + ".if"),
+		Children:    []any{},
+	}
+	for i := 0; ; i++ {
+		child := fmt.Sprintf("%s.%d", path, i)
+		switch form.Get(child + ".kind") {
+		case "group":
+			group.Children = append(group.Children, parseQueryGroup(config, form, child, errs))
+		case "rule":
+			group.Children = append(group.Children, parseQueryRule(config, form, child, errs))
+		default:
+			return group
+		}
+	}
+}
+
+func parseQueryRule(config *condition.Config, form url.Values, path string, errs map[string]string) *condition.ConditionRule {
+	rule := &condition.ConditionRule{
+		ID: Coalesce(form.Get(path+".id"), uuid.New().String()),
+		If: form.Get(path + ".if"),
+	}
+	if rule.If != "" {
+		return rule
+	}
+
+	rule.Left = condition.Expression{Type: condition.ValueTypeField, Field: form.Get(path + ".field")}
+	field, _ := config.LookupField(rule.Left.Field)
+	rule.Op = condition.OperatorType(form.Get(path + ".op"))
+
+	// A field change resets the operator and value type when the new
+	// field does not allow them
+	if ops := config.FieldOperators(field); len(ops) > 0 && !slices.Contains(ops, rule.Op) {
+		rule.Op = config.FieldDefaultOperator(field)
+	}
+	valueType := condition.ValueType(Coalesce(form.Get(path+".valueType"), string(condition.ValueTypeValue)))
+	if allowed := config.FieldValueTypes(field); valueType != condition.ValueTypeFunc && !slices.Contains(allowed, valueType) {
+		valueType = allowed[0]
+	}
+
+	if isUnaryQueryOperator(rule.Op) {
+		return rule
+	}
+
+	switch valueType {
+	case condition.ValueTypeField:
+		rule.Right = condition.Expression{Type: condition.ValueTypeField, Field: form.Get(path + ".rightField")}
+	case condition.ValueTypeFunc:
+		var right any
+		if err := json.Unmarshal([]byte(form.Get(path+".right")), &right); err != nil {
+			errs[rule.ID] = "invalid function value"
+		}
+		rule.Right = right
+	default:
+		right, err := parseQueryValues(field, rule.Op, form[path+".value"])
+		if err != nil {
+			errs[rule.ID] = err.Error()
+		}
+		rule.Right = right
+	}
+	return rule
+}
+
+// parseQueryValues converts submitted value inputs into the right side
+// shapes the condition Builder produces
+func parseQueryValues(field condition.Field, op condition.OperatorType, raw []string) (any, error) {
+	switch op {
+	case condition.OpBetween, condition.OpNotBetween:
+		if len(raw) != 2 {
+			return nil, fmt.Errorf("%s needs two values", queryOperatorLabel(op))
+		}
+		right := make([]any, 2)
+		for i, s := range raw {
+			v, err := parseQueryValue(field, s)
+			if err != nil {
+				return nil, err
+			}
+			right[i] = condition.Expression{Type: condition.ValueTypeValue, Value: v}
+		}
+		return right, nil
+
+	case condition.OpIn, condition.OpNotIn:
+		// Free text inputs hold a comma separated list
+		if len(raw) == 1 && len(field.Values) == 0 {
+			raw = strings.Split(raw[0], ",")
+		}
+		right := make([]any, 0, len(raw))
+		for _, s := range raw {
+			if s = strings.TrimSpace(s); s == "" {
+				continue
+			}
+			v, err := parseQueryValue(field, s)
+			if err != nil {
+				return nil, err
+			}
+			right = append(right, condition.Expression{Type: condition.ValueTypeValue, Value: v})
+		}
+		if len(right) == 0 {
+			return nil, fmt.Errorf("%s needs at least one value", queryOperatorLabel(op))
+		}
+		return right, nil
+	}
+
+	var s string
+	if len(raw) > 0 {
+		s = raw[0]
+	}
+	v, err := parseQueryValue(field, s)
+	if err != nil {
+		return nil, err
+	}
+	return condition.Expression{Type: condition.ValueTypeValue, Value: v}, nil
+}
+
+// parseQueryValue types a submitted value by the field's type
+func parseQueryValue(field condition.Field, s string) (any, error) {
+	// Options keep their configured type
+	for _, option := range field.Values {
+		if queryValueString(option.Value) == s {
+			return option.Value, nil
+		}
+	}
+
+	switch field.Type {
+	case condition.FieldTypeNumber:
+		if s == "" {
+			return nil, fmt.Errorf("%s needs a number", field.Label)
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s needs a number", field.Label)
+		}
+		return f, nil
+	case condition.FieldTypeBoolean:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s needs true or false", field.Label)
+		}
+		return b, nil
+	case condition.FieldTypeDateTime:
+		// datetime-local inputs omit zero seconds
+		if len(s) == len("2006-01-02T15:04") {
+			s += ":00"
+		}
+	}
+	return s, nil
+}
+
+// ============================================================================
+// EDITING
+// ============================================================================
+
+func newQueryGroup() *condition.ConditionGroup {
+	return &condition.ConditionGroup{
+		ID:          uuid.New().String(),
+		Conjunction: condition.ConjunctionAnd,
+		Children:    []any{},
+	}
+}
+
+// newQueryRule starts a rule on the first configured field
+func newQueryRule(config *condition.Config) *condition.ConditionRule {
+	rule := &condition.ConditionRule{ID: uuid.New().String(), Op: condition.OpEqual}
+	fields := config.FieldList()
+	if len(fields) == 0 {
+		rule.Left = condition.Expression{Type: condition.ValueTypeField}
+		rule.Right = condition.Expression{Type: condition.ValueTypeValue, Value: ""}
+		return rule
+	}
+
+	field := fields[0]
+	rule.Left = condition.Expression{Type: condition.ValueTypeField, Field: field.Name}
+	rule.Op = config.FieldDefaultOperator(field)
+	value := field.DefaultValue
+	if value == nil && len(field.Values) > 0 {
+		value = field.Values[0].Value
+	}
+	if value == nil {
+		value = ""
+	}
+	switch {
+	case isUnaryQueryOperator(rule.Op):
+	case rule.Op == condition.OpBetween || rule.Op == condition.OpNotBetween:
+		rule.Right = []any{
+			condition.Expression{Type: condition.ValueTypeValue, Value: value},
+			condition.Expression{Type: condition.ValueTypeValue, Value: value},
+		}
+	default:
+		rule.Right = condition.Expression{Type: condition.ValueTypeValue, Value: value}
+	}
+	return rule
+}
+
+func findQueryGroup(group *condition.ConditionGroup, id string) *condition.ConditionGroup {
+	if group.ID == id {
+		return group
+	}
+	for _, child := range group.Children {
+		if g, ok := child.(*condition.ConditionGroup); ok {
+			if found := findQueryGroup(g, id); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
+
+// removeQueryNode removes the group or rule with the given ID below group
+func removeQueryNode(group *condition.ConditionGroup, id string) bool {
+	for i, child := range group.Children {
+		switch v := child.(type) {
+		case *condition.ConditionGroup:
+			if v.ID == id {
+				group.Children = slices.Delete(group.Children, i, i+1)
+				return true
+			}
+			if removeQueryNode(v, id) {
+				return true
+			}
+		case *condition.ConditionRule:
+			if v.ID == id {
+				group.Children = slices.Delete(group.Children, i, i+1)
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// validateQueryBuilder reports errors per node, keeping parse errors, and
+// runs Builder.Validate over the whole tree
+func validateQueryBuilder(config *condition.Config, root *condition.ConditionGroup, errs map[string]string) (map[string]string, string) {
+	var walk func(group *condition.ConditionGroup, depth int)
+	walk = func(group *condition.ConditionGroup, depth int) {
+		if err := group.Validate(); err != nil {
+			errs[group.ID] = err.Error()
+		}
+		if config != nil && config.MaxLevel > 0 && depth >= config.MaxLevel {
+			errs[group.ID] = fmt.Sprintf("groups may be nested at most %d levels", config.MaxLevel)
+		}
+		for _, child := range group.Children {
+			switch v := child.(type) {
+			case *condition.ConditionGroup:
+				walk(v, depth+1)
+			case *condition.ConditionRule:
+				if _, failed := errs[v.ID]; failed {
+					continue
+				}
+				if err := v.Validate(); err != nil {
+					errs[v.ID] = err.Error()
+				} else if config != nil {
+					if err := config.ValidateRule(v); err != nil {
+						errs[v.ID] = err.Error()
+					}
+				}
+			}
+		}
+	}
+	walk(root, 0)
+
+	if err := condition.NewBuilderFrom(root).Validate(); err != nil && len(errs) == 0 {
+		return nil, err.Error()
+	}
+	if len(errs) == 0 {
+		return nil, ""
+	}
+	return errs, ""
+}
+
+// ============================================================================
+// RENDERING HELPERS
+// ============================================================================
+
+var allQueryOperators = []condition.OperatorType{
+	condition.OpEqual, condition.OpNotEqual, condition.OpLess, condition.OpLessOrEqual,
+	condition.OpGreater, condition.OpGreaterOrEqual, condition.OpBetween, condition.OpNotBetween,
+	condition.OpIsEmpty, condition.OpIsNotEmpty, condition.OpContains, condition.OpNotContains,
+	condition.OpStartsWith, condition.OpEndsWith, condition.OpIn, condition.OpNotIn,
+	condition.OpMatchRegexp,
+}
+
+var queryOperatorLabels = map[condition.OperatorType]string{
+	condition.OpEqual:          "equals",
+	condition.OpNotEqual:       "does not equal",
+	condition.OpLess:           "is less than",
+	condition.OpLessOrEqual:    "is at most",
+	condition.OpGreater:        "is greater than",
+	condition.OpGreaterOrEqual: "is at least",
+	condition.OpBetween:        "is between",
+	condition.OpNotBetween:     "is not between",
+	condition.OpIsEmpty:        "is empty",
+	condition.OpIsNotEmpty:     "is not empty",
+	condition.OpContains:       "contains",
+	condition.OpNotContains:    "does not contain",
+	condition.OpStartsWith:     "starts with",
+	condition.OpEndsWith:       "ends with",
+	condition.OpIn:             "is any of",
+	condition.OpNotIn:          "is none of",
+	condition.OpMatchRegexp:    "matches pattern",
+}
+
+func queryOperatorLabel(op condition.OperatorType) string {
+	return Coalesce(queryOperatorLabels[op], string(op))
+}
+
+func isUnaryQueryOperator(op condition.OperatorType) bool {
+	return op == condition.OpIsEmpty || op == condition.OpIsNotEmpty
+}
+
+func isRangeQueryOperator(op condition.OperatorType) bool {
+	return op == condition.OpBetween || op == condition.OpNotBetween
+}
+
+func isListQueryOperator(op condition.OperatorType) bool {
+	return op == condition.OpIn || op == condition.OpNotIn
+}
+
+// queryInputType maps a field type to an HTML input type
+func queryInputType(fieldType condition.FieldType) string {
+	switch fieldType {
+	case condition.FieldTypeNumber:
+		return "number"
+	case condition.FieldTypeDate:
+		return "date"
+	case condition.FieldTypeDateTime:
+		return "datetime-local"
+	case condition.FieldTypeTime:
+		return "time"
+	default:
+		return "text"
+	}
+}
+
+func queryValueString(v any) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(value), 'f', -1, 32)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// nodeValue returns the i-th submitted value of a rule, or ""
+func (n qbNode) nodeValue(i int) string {
+	if i < len(n.values) {
+		return n.values[i]
+	}
+	return ""
+}
+
+func (n qbNode) hasValue(value string) bool {
+	return slices.Contains(n.values, value)
+}
+
+// comparableFields lists the fields a rule's value may reference
+func (s queryBuilderState) comparableFields(n qbNode) []condition.Field {
+	var fields []condition.Field
+	for _, field := range s.fields {
+		if field.Name != n.field.Name && field.Type == n.field.Type {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// queryHXVals encodes the edit a button requests
+func (s queryBuilderState) queryHXVals(action, target string) string {
+	return mustMarshalJSON(map[string]string{
+		s.name + ".action": action,
+		s.name + ".target": target,
+	})
+}
+
+func queryValueTypeLabel(valueType condition.ValueType) string {
+	switch valueType {
+	case condition.ValueTypeField:
+		return "field"
+	case condition.ValueTypeFunc:
+		return "function"
+	default:
+		return "value"
+	}
+}
+
+// queryFieldOptions returns the choices offered for a field's value
+func queryFieldOptions(field condition.Field) []condition.SelectOption {
+	if len(field.Values) > 0 {
+		return field.Values
+	}
+	return []condition.SelectOption{
+		{Label: "Yes", Value: true},
+		{Label: "No", Value: false},
+	}
+}
+
+// queryFieldDeclared reports whether a rule's field is one of the
+// configured fields, so rules on unknown fields keep their selection
+func queryFieldDeclared(fields []condition.Field, name string) bool {
+	return slices.ContainsFunc(fields, func(field condition.Field) bool {
+		return field.Name == name
+	})
+}
+
+func joinQueryValues(values []string) string {
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,299 @@
+package organisms
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html"
+
+	"github.com/niiniyare/ruun/pkg/condition"
+)
+
+func queryBuilderConfig() *condition.Config {
+	return &condition.Config{
+		ValueTypes: []condition.ValueType{condition.ValueTypeValue, condition.ValueTypeField, condition.ValueTypeFunc},
+		Fields: []any{
+			condition.Field{Name: "name", Label: "Name", Type: condition.FieldTypeText},
+			condition.Field{Name: "age", Label: "Age", Type: condition.FieldTypeNumber},
+			condition.Field{Name: "minAge", Label: "Minimum age", Type: condition.FieldTypeNumber},
+			condition.Field{Name: "active", Label: "Active", Type: condition.FieldTypeBoolean},
+			condition.Field{Name: "status", Label: "Status", Type: condition.FieldTypeSelect, Values: []condition.SelectOption{
+				{Label: "Active", Value: "active"},
+				{Label: "Closed", Value: "closed"},
+			}},
+			condition.Field{Name: "created", Label: "Created", Type: condition.FieldTypeDateTime},
+		},
+	}
+}
+
+func queryValue(v any) condition.Expression {
+	return condition.Expression{Type: condition.ValueTypeValue, Value: v}
+}
+
+func queryRule(id, field string, op condition.OperatorType, right any) *condition.ConditionRule {
+	return &condition.ConditionRule{
+		ID:    id,
+		Left:  condition.Expression{Type: condition.ValueTypeField, Field: field},
+		Op:    op,
+		Right: right,
+	}
+}
+
+// queryOperatorRule builds a rule exercising op with a right side of the
+// shape the builder produces for it
+func queryOperatorRule(op condition.OperatorType) *condition.ConditionRule {
+	id := "rule_" + string(op)
+	switch op {
+	case condition.OpIsEmpty, condition.OpIsNotEmpty:
+		return queryRule(id, "name", op, nil)
+	case condition.OpBetween, condition.OpNotBetween:
+		return queryRule(id, "age", op, []any{queryValue(18.0), queryValue(65.5)})
+	case condition.OpIn, condition.OpNotIn:
+		return queryRule(id, "age", op, []any{queryValue(1.0), queryValue(2.0), queryValue(3.0)})
+	case condition.OpContains, condition.OpNotContains, condition.OpStartsWith, condition.OpEndsWith:
+		return queryRule(id, "name", op, queryValue("ada"))
+	case condition.OpMatchRegexp:
+		return queryRule(id, "name", op, queryValue("^a.*e$"))
+	default:
+		return queryRule(id, "age", op, queryValue(42.0))
+	}
+}
+
+// queryBuilderTree nests groups three levels deep and covers every
+// operator, field and function operands, typed values and formulas
+func queryBuilderTree() *condition.ConditionGroup {
+	operators := &condition.ConditionGroup{ID: "operators", Conjunction: condition.ConjunctionOr, Not: true}
+	for _, op := range allQueryOperators {
+		operators.Children = append(operators.Children, queryOperatorRule(op))
+	}
+
+	typed := &condition.ConditionGroup{
+		ID:          "typed",
+		Conjunction: condition.ConjunctionAnd,
+		Children: []any{
+			queryRule("active", "active", condition.OpEqual, queryValue(true)),
+			queryRule("status", "status", condition.OpEqual, queryValue("closed")),
+			queryRule("statuses", "status", condition.OpIn, []any{queryValue("active"), queryValue("closed")}),
+			queryRule("created", "created", condition.OpGreater, queryValue("2025-01-02T03:04:05")),
+			&condition.ConditionGroup{
+				ID:          "deepest",
+				Conjunction: condition.ConjunctionOr,
+				Children: []any{
+					queryRule("field", "age", condition.OpGreaterOrEqual, condition.Expression{Type: condition.ValueTypeField, Field: "minAge"}),
+					queryRule("func", "created", condition.OpLess, condition.Expression{
+						Type: condition.ValueTypeFunc,
+						Func: &condition.FuncCall{Type: "NOW", Args: []any{}},
+					}),
+				},
+			},
+		},
+	}
+
+	return &condition.ConditionGroup{
+		ID:          "root",
+		Conjunction: condition.ConjunctionAnd,
+		Children: []any{
+			operators,
+			typed,
+			&condition.ConditionRule{ID: "formula", If: "age > 18 && active"},
+		},
+	}
+}
+
+// submitQueryBuilder renders props and collects the inputs a browser
+// would submit
+func submitQueryBuilder(t *testing.T, props QueryBuilderProps) url.Values {
+	t.Helper()
+	markup, err := renderComponent(context.Background(), QueryBuilder(props))
+	require.NoError(t, err)
+	doc, err := html.Parse(strings.NewReader(markup))
+	require.NoError(t, err)
+
+	form := url.Values{}
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			name, hasName := htmlAttr(n, "name")
+			_, disabled := htmlAttr(n, "disabled")
+			if hasName && !disabled {
+				switch n.Data {
+				case "input":
+					inputType, _ := htmlAttr(n, "type")
+					_, checked := htmlAttr(n, "checked")
+					value, _ := htmlAttr(n, "value")
+					if (inputType == "checkbox" || inputType == "radio") && !checked {
+						break
+					}
+					form.Add(name, value)
+				case "select":
+					collectSelected(n, name, form)
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+	return form
+}
+
+func collectSelected(sel *html.Node, name string, form url.Values) {
+	var first *html.Node
+	selected := false
+	for c := sel.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode || c.Data != "option" {
+			continue
+		}
+		if first == nil {
+			first = c
+		}
+		if _, ok := htmlAttr(c, "selected"); ok {
+			value, _ := htmlAttr(c, "value")
+			form.Add(name, value)
+			selected = true
+		}
+	}
+	if _, multiple := htmlAttr(sel, "multiple"); !selected && !multiple && first != nil {
+		value, _ := htmlAttr(first, "value")
+		form.Add(name, value)
+	}
+}
+
+func htmlAttr(n *html.Node, key string) (string, bool) {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return attr.Val, true
+		}
+	}
+	return "", false
+}
+
+func assertSameQueryTree(t *testing.T, want, got *condition.ConditionGroup) {
+	t.Helper()
+	wantJSON, err := json.Marshal(want)
+	require.NoError(t, err)
+	gotJSON, err := json.Marshal(got)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(wantJSON), string(gotJSON))
+}
+
+func TestQueryBuilderFromForm_RoundTrip(t *testing.T) {
+	props := QueryBuilderProps{Name: "visibility", Config: queryBuilderConfig(), Root: queryBuilderTree()}
+	form := submitQueryBuilder(t, props)
+	assert.Equal(t, []string{"true"}, form["visibility.0.not"])
+	assert.Equal(t, []string{"18", "65.5"}, form["visibility.0.6.value"], "between renders two inputs")
+	assert.Equal(t, []string{"1, 2, 3"}, form["visibility.0.14.value"], "free text lists are comma separated")
+
+	result := QueryBuilderFromForm(QueryBuilderProps{Name: "visibility", Config: queryBuilderConfig()}, form)
+	require.True(t, result.Valid(), "errors: %v %s", result.Errors, result.Error)
+	assertSameQueryTree(t, queryBuilderTree(), result.Root)
+
+	operators := result.Root.Children[0].(*condition.ConditionGroup)
+	require.Len(t, operators.Children, len(allQueryOperators))
+	for i, op := range allQueryOperators {
+		rule := operators.Children[i].(*condition.ConditionRule)
+		assert.Equal(t, op, rule.Op)
+	}
+
+	typed := result.Root.Children[1].(*condition.ConditionGroup)
+	assert.Equal(t, queryValue(true), typed.Children[0].(*condition.ConditionRule).Right, "booleans are typed")
+	between := operators.Children[6].(*condition.ConditionRule)
+	assert.Equal(t, []any{queryValue(18.0), queryValue(65.5)}, between.Right, "numbers are typed")
+
+	again := QueryBuilderFromForm(QueryBuilderProps{Name: "visibility", Config: queryBuilderConfig()}, submitQueryBuilder(t, result))
+	require.True(t, again.Valid())
+	assertSameQueryTree(t, result.Root, again.Root)
+}
+
+func TestQueryBuilderFromForm_Edits(t *testing.T) {
+	config := queryBuilderConfig()
+	edit := func(t *testing.T, action, target string) *condition.ConditionGroup {
+		t.Helper()
+		form := submitQueryBuilder(t, QueryBuilderProps{Config: config, Root: queryBuilderTree()})
+		form.Set("condition.action", action)
+		form.Set("condition.target", target)
+		result := QueryBuilderFromForm(QueryBuilderProps{Config: config}, form)
+		return result.Root
+	}
+
+	t.Run("add rule", func(t *testing.T) {
+		root := edit(t, QueryBuilderAddRule, "deepest")
+		deepest := findQueryGroup(root, "deepest")
+		require.Len(t, deepest.Children, 3)
+		added := deepest.Children[2].(*condition.ConditionRule)
+		assert.Equal(t, "name", added.Left.Field, "new rules start on the first field")
+		assert.Equal(t, condition.OpEqual, added.Op)
+	})
+
+	t.Run("add group", func(t *testing.T) {
+		root := edit(t, QueryBuilderAddGroup, "root")
+		require.Len(t, root.Children, 4)
+		added := root.Children[3].(*condition.ConditionGroup)
+		assert.Equal(t, condition.ConjunctionAnd, added.Conjunction)
+		assert.Len(t, added.Children, 1)
+	})
+
+	t.Run("remove rule", func(t *testing.T) {
+		root := edit(t, QueryBuilderRemove, "field")
+		deepest := findQueryGroup(root, "deepest")
+		require.Len(t, deepest.Children, 1)
+		assert.Equal(t, "func", deepest.Children[0].(*condition.ConditionRule).ID)
+	})
+
+	t.Run("remove group", func(t *testing.T) {
+		root := edit(t, QueryBuilderRemove, "deepest")
+		assert.Nil(t, findQueryGroup(root, "deepest"))
+		assert.Len(t, findQueryGroup(root, "typed").Children, 4)
+	})
+}
+
+func TestQueryBuilderFromForm_Errors(t *testing.T) {
+	rule := func(field, op string, values ...string) url.Values {
+		form := url.Values{
+			"condition.kind":        {"group"},
+			"condition.id":          {"root"},
+			"condition.0.kind":      {"rule"},
+			"condition.0.id":        {"rule"},
+			"condition.0.field":     {field},
+			"condition.0.op":        {op},
+			"condition.0.valueType": {"value"},
+		}
+		form["condition.0.value"] = values
+		return form
+	}
+	funcRule := rule("created", "less")
+	funcRule.Set("condition.0.valueType", "func")
+	funcRule.Set("condition.0.right", "{not json")
+
+	tests := []struct {
+		name string
+		form url.Values
+		want string
+	}{
+		{"between needs two values", rule("age", "between", "1"), "is between needs two values"},
+		{"not numeric", rule("age", "equal", "old"), "Age needs a number"},
+		{"empty number", rule("age", "less", ""), "Age needs a number"},
+		{"empty list", rule("age", "select_any_in", " , "), "is any of needs at least one value"},
+		{"not boolean", rule("active", "equal", "maybe"), "Active needs true or false"},
+		{"invalid function", funcRule, "invalid function value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := QueryBuilderFromForm(QueryBuilderProps{Config: queryBuilderConfig()}, tt.form)
+			assert.False(t, result.Valid())
+			assert.Equal(t, tt.want, result.Errors["rule"])
+		})
+	}
+
+	t.Run("unknown field", func(t *testing.T) {
+		result := QueryBuilderFromForm(QueryBuilderProps{Config: queryBuilderConfig()}, rule("missing", "equal", "x"))
+		assert.Contains(t, result.Errors["rule"], "missing")
+	})
+}
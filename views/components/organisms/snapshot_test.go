@@ -0,0 +1,86 @@
+package organisms
+
+import (
+	"testing"
+
+	"github.com/niiniyare/ruun/views/components/molecules"
+	"github.com/niiniyare/ruun/views/components/snapshot"
+)
+
+func TestTabsSnapshots(t *testing.T) {
+	set := snapshot.Set[TabsProps]
+	snapshot.Run(t, snapshot.New(), snapshot.Matrix[TabsProps]{
+		Base: TabsProps{
+			ID: "tabs",
+			Items: []TabItem{
+				{ID: "overview", Label: "Overview"},
+				{ID: "activity", Label: "Activity", Count: 4},
+				{ID: "archive", Label: "Archive", Disabled: true},
+			},
+		},
+		Axes: []snapshot.Axis[TabsProps]{
+			snapshot.Vary("orientation",
+				set("horizontal", func(p *TabsProps) { p.Orientation = "horizontal" }),
+				set("vertical", func(p *TabsProps) { p.Orientation = "vertical" })),
+			snapshot.Vary("width",
+				set("auto", nil),
+				set("full", func(p *TabsProps) { p.FullWidth = true })),
+		},
+	}, Tabs)
+}
+
+func TestDataTableSnapshots(t *testing.T) {
+	set := snapshot.Set[DataTableProps]
+	snapshot.Run(t, snapshot.New(), snapshot.Matrix[DataTableProps]{
+		Base: DataTableProps{
+			ID:    "users",
+			Title: "Users",
+			Columns: []DataTableColumn{
+				{Key: "name", Title: "Name", Type: ColumnTypeText, Visible: true},
+				{Key: "status", Title: "Status", Type: ColumnTypeBadge, Visible: true, BadgeMap: map[string]string{"active": "success"}},
+			},
+			Rows: []DataTableRow{
+				{ID: "1", Data: map[string]any{"name": "Ada", "status": "active"}},
+				{ID: "2", Data: map[string]any{"name": "Grace", "status": "invited"}},
+			},
+		},
+		Axes: []snapshot.Axis[DataTableProps]{
+			snapshot.Vary("variant",
+				set("default", func(p *DataTableProps) { p.Variant = DataTableDefault }),
+				set("striped", func(p *DataTableProps) { p.Variant = DataTableStriped })),
+			snapshot.Vary("features",
+				set("basic", nil),
+				set("selection", func(p *DataTableProps) {
+					p.Selection = &SelectionConfig{Enabled: true, MultiSelect: true, Checkbox: true}
+				}),
+				set("pagination", func(p *DataTableProps) {
+					p.Pagination = &PaginationConfig{Enabled: true, PageSize: 10, CurrentPage: 1, TotalItems: 2, ShowTotal: true}
+				})),
+		},
+	}, DataTable)
+}
+
+func TestFormSnapshots(t *testing.T) {
+	field := func(id, label string, fieldType molecules.FormFieldType) Field {
+		return Field{FormFieldProps: molecules.FormFieldProps{ID: id, Name: id, Label: label, Type: fieldType}}
+	}
+	set := snapshot.Set[FormProps]
+	snapshot.Run(t, snapshot.New(), snapshot.Matrix[FormProps]{
+		Base: FormProps{
+			ID:    "signup",
+			Title: "Sign up",
+			Fields: []Field{
+				field("email", "Email", molecules.FormFieldEmail),
+				field("password", "Password", molecules.FormFieldPassword),
+			},
+		},
+		Axes: []snapshot.Axis[FormProps]{
+			snapshot.Vary("layout",
+				set("vertical", func(p *FormProps) { p.Layout = FormLayoutVertical }),
+				set("horizontal", func(p *FormProps) { p.Layout = FormLayoutHorizontal })),
+			snapshot.Vary("mode",
+				set("editable", nil),
+				set("readonly", func(p *FormProps) { p.ReadOnly = true })),
+		},
+	}, Form)
+}
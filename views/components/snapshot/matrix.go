@@ -0,0 +1,86 @@
+package snapshot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+// Variation is one value along an axis, applied to a copy of the base props
+type Variation[P any] struct {
+	Name  string
+	Apply func(props *P)
+}
+
+// Set creates a variation
+func Set[P any](name string, apply func(props *P)) Variation[P] {
+	return Variation[P]{Name: name, Apply: apply}
+}
+
+// Axis is a named dimension of the props matrix, e.g. variant or size
+type Axis[P any] struct {
+	Name       string
+	Variations []Variation[P]
+}
+
+// Vary creates an axis
+func Vary[P any](name string, variations ...Variation[P]) Axis[P] {
+	return Axis[P]{Name: name, Variations: variations}
+}
+
+// Matrix is the cartesian product of its axes applied to Base. Props are
+// copied by value, so variations must not mutate maps or slices shared
+// with Base.
+type Matrix[P any] struct {
+	Base P
+	Axes []Axis[P]
+}
+
+// Case is one combination of the matrix
+type Case[P any] struct {
+	Name  string
+	Props P
+}
+
+// Cases expands the matrix. Case names join axis values as
+// "axis=value,axis=value"; a matrix without axes has a single "default"
+// case.
+func (m Matrix[P]) Cases() []Case[P] {
+	cases := []Case[P]{{Props: m.Base}}
+	for _, axis := range m.Axes {
+		if len(axis.Variations) == 0 {
+			continue
+		}
+		next := make([]Case[P], 0, len(cases)*len(axis.Variations))
+		for _, c := range cases {
+			for _, variation := range axis.Variations {
+				props := c.Props
+				if variation.Apply != nil {
+					variation.Apply(&props)
+				}
+				name := axis.Name + "=" + variation.Name
+				if c.Name != "" {
+					name = c.Name + "," + name
+				}
+				next = append(next, Case[P]{Name: name, Props: props})
+			}
+		}
+		cases = next
+	}
+	if len(cases) == 1 && cases[0].Name == "" {
+		cases[0].Name = "default"
+	}
+	return cases
+}
+
+// Run renders every case of the matrix as a subtest and matches it against
+// its golden file
+func Run[P any](t *testing.T, h *Harness, m Matrix[P], render func(props P) templ.Component) {
+	t.Helper()
+	for _, c := range m.Cases() {
+		t.Run(strings.ReplaceAll(c.Name, " ", "_"), func(t *testing.T) {
+			h.Match(t, render(c.Props))
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package snapshot
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Normalizer turns rendered HTML into a stable, indented form so golden
+// files only change when the markup does. Attributes and class names are
+// sorted, insignificant whitespace and comments are dropped, and
+// nondeterministic values are replaced by placeholders.
+type Normalizer struct {
+	replacements []replacement
+	dropAttrs    map[string]bool
+	indent       string
+}
+
+var (
+	uuidPattern      = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+	templHashPattern = regexp.MustCompile(`\b(__templ_[A-Za-z0-9_]+?)_[0-9a-f]{8}\b`)
+)
+
+type replacement struct {
+	pattern *regexp.Regexp
+	repl    string
+}
+
+// NewNormalizer creates a normalizer that masks UUIDs and the hashed names
+// templ generates for scripts and CSS classes
+func NewNormalizer() *Normalizer {
+	n := &Normalizer{
+		dropAttrs: make(map[string]bool),
+		indent:    "  ",
+	}
+	n.Replace(uuidPattern, "{uuid}")
+	n.Replace(templHashPattern, "${1}_{hash}")
+	return n
+}
+
+// Replace masks every match of pattern in text and attribute values
+func (n *Normalizer) Replace(pattern *regexp.Regexp, repl string) *Normalizer {
+	n.replacements = append(n.replacements, replacement{pattern: pattern, repl: repl})
+	return n
+}
+
+// DropAttr removes attributes from the output
+func (n *Normalizer) DropAttr(names ...string) *Normalizer {
+	for _, name := range names {
+		n.dropAttrs[strings.ToLower(name)] = true
+	}
+	return n
+}
+
+// Normalize parses an HTML fragment and prints it in normalised form
+func (n *Normalizer) Normalize(source string) (string, error) {
+	context := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(strings.NewReader(source), context)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	for _, node := range nodes {
+		n.print(&sb, node, 0)
+	}
+	return sb.String(), nil
+}
+
+func (n *Normalizer) print(sb *strings.Builder, node *html.Node, depth int) {
+	indent := strings.Repeat(n.indent, depth)
+	switch node.Type {
+	case html.TextNode:
+		if text := n.text(node); text != "" {
+			sb.WriteString(indent + text + "\n")
+		}
+	case html.DoctypeNode:
+		sb.WriteString(indent + "<!DOCTYPE " + node.Data + ">\n")
+	case html.ElementNode:
+		open := n.openTag(node)
+		if isVoid(node) {
+			sb.WriteString(indent + open + "\n")
+			return
+		}
+		closeTag := "</" + node.Data + ">"
+		if preserveWhitespace(node) {
+			sb.WriteString(indent + open + n.mask(html.EscapeString(rawText(node))) + closeTag + "\n")
+			return
+		}
+		children := n.significantChildren(node)
+		switch {
+		case len(children) == 0:
+			sb.WriteString(indent + open + closeTag + "\n")
+		case len(children) == 1 && children[0].Type == html.TextNode:
+			sb.WriteString(indent + open + n.text(children[0]) + closeTag + "\n")
+		default:
+			sb.WriteString(indent + open + "\n")
+			for _, child := range children {
+				n.print(sb, child, depth+1)
+			}
+			sb.WriteString(indent + closeTag + "\n")
+		}
+	}
+}
+
+// significantChildren drops comments and whitespace-only text
+func (n *Normalizer) significantChildren(node *html.Node) []*html.Node {
+	var children []*html.Node
+	for c := node.FirstChild; c != nil; c = c.NextSibling {
+		switch c.Type {
+		case html.ElementNode:
+			children = append(children, c)
+		case html.TextNode:
+			if n.text(c) != "" {
+				children = append(children, c)
+			}
+		}
+	}
+	return children
+}
+
+func (n *Normalizer) openTag(node *html.Node) string {
+	attrs := make([]html.Attribute, 0, len(node.Attr))
+	for _, a := range node.Attr {
+		if !n.dropAttrs[strings.ToLower(a.Key)] {
+			attrs = append(attrs, a)
+		}
+	}
+	slices.SortFunc(attrs, func(a, b html.Attribute) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+
+	var sb strings.Builder
+	sb.WriteString("<" + node.Data)
+	for _, a := range attrs {
+		value := a.Val
+		if a.Key == "class" {
+			value = normalizeClass(value)
+		} else if a.Key != "style" {
+			value = strings.TrimSpace(value)
+		} else {
+			value = normalizeStyle(value)
+		}
+		sb.WriteString(" " + a.Key)
+		if value != "" || a.Key == "class" || a.Key == "value" || a.Key == "alt" {
+			sb.WriteString(`="` + n.mask(html.EscapeString(value)) + `"`)
+		}
+	}
+	sb.WriteString(">")
+	return sb.String()
+}
+
+// text returns collapsed, escaped and masked text content
+func (n *Normalizer) text(node *html.Node) string {
+	text := strings.Join(strings.Fields(node.Data), " ")
+	if node.Parent != nil && (node.Parent.DataAtom == atom.Script || node.Parent.DataAtom == atom.Style) {
+		return n.mask(text)
+	}
+	return n.mask(html.EscapeString(text))
+}
+
+func (n *Normalizer) mask(s string) string {
+	for _, r := range n.replacements {
+		s = r.pattern.ReplaceAllString(s, r.repl)
+	}
+	return s
+}
+
+// normalizeClass sorts and deduplicates class names
+func normalizeClass(value string) string {
+	classes := strings.Fields(value)
+	slices.Sort(classes)
+	return strings.Join(slices.Compact(classes), " ")
+}
+
+// normalizeStyle trims declarations and their separators
+func normalizeStyle(value string) string {
+	var declarations []string
+	for _, declaration := range strings.Split(value, ";") {
+		property, val, ok := strings.Cut(declaration, ":")
+		if !ok {
+			continue
+		}
+		declarations = append(declarations, strings.TrimSpace(property)+": "+strings.Join(strings.Fields(val), " "))
+	}
+	return strings.Join(declarations, "; ")
+}
+
+func rawText(node *html.Node) string {
+	var sb strings.Builder
+	for c := node.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.TextNode {
+			sb.WriteString(c.Data)
+		}
+	}
+	return sb.String()
+}
+
+func preserveWhitespace(node *html.Node) bool {
+	return node.DataAtom == atom.Pre || node.DataAtom == atom.Textarea
+}
+
+func isVoid(node *html.Node) bool {
+	switch node.DataAtom {
+	case atom.Area, atom.Base, atom.Br, atom.Col, atom.Embed, atom.Hr, atom.Img, atom.Input,
+		atom.Link, atom.Meta, atom.Source, atom.Track, atom.Wbr:
+		return true
+	}
+	return false
+}
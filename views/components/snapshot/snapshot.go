@@ -0,0 +1,256 @@
+// Package snapshot renders templ components across a matrix of props and
+// rendering environments, normalises the HTML and compares it with golden
+// files stored next to the tests.
+//
+// Golden files are rewritten with:
+//
+//	go test ./... -update-snapshots
+//	UPDATE_SNAPSHOTS=1 go test ./...
+//
+// Missing golden files are recorded on first run, except when the CI
+// environment variable is set, in which case they fail the test.
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/a-h/templ"
+)
+
+// UpdateEnv is the environment variable that enables update mode
+const UpdateEnv = "UPDATE_SNAPSHOTS"
+
+var updateFlag = flag.Bool("update-snapshots", false, "rewrite golden snapshot files with the rendered output")
+
+// TB is the subset of testing.TB used by the harness
+type TB interface {
+	Helper()
+	Name() string
+	Errorf(format string, args ...any)
+	Fatalf(format string, args ...any)
+	Logf(format string, args ...any)
+}
+
+// Environment describes the document a component is rendered into
+type Environment struct {
+	Name string
+	Dark bool   // render inside class="dark"
+	RTL  bool   // render inside dir="rtl"
+	Lang string // lang attribute of the wrapper
+}
+
+// Predefined environments
+var (
+	Light = Environment{Name: "light"}
+	Dark  = Environment{Name: "dark", Dark: true}
+	RTL   = Environment{Name: "rtl", RTL: true, Lang: "ar"}
+
+	DefaultEnvironments = []Environment{Light, Dark, RTL}
+)
+
+// Harness renders components and matches them against golden files
+type Harness struct {
+	dir          string
+	environments []Environment
+	normalizer   *Normalizer
+	update       bool
+	ci           bool
+}
+
+// Option configures a Harness
+type Option func(*Harness)
+
+// WithDir sets the golden file directory (default testdata/snapshots)
+func WithDir(dir string) Option {
+	return func(h *Harness) {
+		h.dir = dir
+	}
+}
+
+// WithEnvironments sets the environments each snapshot is rendered in
+func WithEnvironments(environments ...Environment) Option {
+	return func(h *Harness) {
+		h.environments = environments
+	}
+}
+
+// WithNormalizer replaces the default HTML normalizer
+func WithNormalizer(normalizer *Normalizer) Option {
+	return func(h *Harness) {
+		h.normalizer = normalizer
+	}
+}
+
+// WithUpdate forces update mode on or off
+func WithUpdate(update bool) Option {
+	return func(h *Harness) {
+		h.update = update
+	}
+}
+
+// New creates a harness. Update mode follows the -update-snapshots flag
+// and the UPDATE_SNAPSHOTS environment variable unless set with WithUpdate.
+func New(opts ...Option) *Harness {
+	h := &Harness{
+		dir:          filepath.Join("testdata", "snapshots"),
+		environments: DefaultEnvironments,
+		normalizer:   NewNormalizer(),
+		update:       *updateFlag || os.Getenv(UpdateEnv) != "",
+		ci:           os.Getenv("CI") != "",
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// Render renders a component into an environment and returns the
+// normalised HTML, including the environment wrapper element
+func (h *Harness) Render(ctx context.Context, component templ.Component, env Environment) (string, error) {
+	var buf bytes.Buffer
+	buf.WriteString(wrapperOpenTag(env))
+	if err := component.Render(ContextWithEnvironment(ctx, env), &buf); err != nil {
+		return "", fmt.Errorf("render %s: %w", env.Name, err)
+	}
+	buf.WriteString("</div>")
+	normalized, err := h.normalizer.Normalize(buf.String())
+	if err != nil {
+		return "", fmt.Errorf("normalize %s: %w", env.Name, err)
+	}
+	return normalized, nil
+}
+
+// Snapshot renders a component in every environment of the harness and
+// returns the golden file content
+func (h *Harness) Snapshot(ctx context.Context, component templ.Component) (string, error) {
+	sections := make([]string, 0, len(h.environments))
+	for _, env := range h.environments {
+		html, err := h.Render(ctx, component, env)
+		if err != nil {
+			return "", err
+		}
+		sections = append(sections, html)
+	}
+	return strings.Join(sections, "\n"), nil
+}
+
+type environmentKey struct{}
+
+// ContextWithEnvironment returns a context carrying the environment
+func ContextWithEnvironment(ctx context.Context, env Environment) context.Context {
+	return context.WithValue(ctx, environmentKey{}, env)
+}
+
+// EnvironmentFromContext returns the environment a component is rendered in
+func EnvironmentFromContext(ctx context.Context) (Environment, bool) {
+	env, ok := ctx.Value(environmentKey{}).(Environment)
+	return env, ok
+}
+
+// wrapperOpenTag returns the element an environment renders into
+func wrapperOpenTag(env Environment) string {
+	attrs := []string{fmt.Sprintf(`data-snapshot-env="%s"`, env.Name)}
+	if env.Dark {
+		attrs = append(attrs, `class="dark"`)
+	}
+	if env.RTL {
+		attrs = append(attrs, `dir="rtl"`)
+	}
+	if env.Lang != "" {
+		attrs = append(attrs, fmt.Sprintf(`lang="%s"`, env.Lang))
+	}
+	return "<div " + strings.Join(attrs, " ") + ">"
+}
+
+// Path returns the golden file for a test name. Subtest names become
+// directories.
+func (h *Harness) Path(testName string) string {
+	segments := strings.Split(testName, "/")
+	for i, segment := range segments {
+		segments[i] = sanitize(segment)
+	}
+	return filepath.Join(h.dir, filepath.Join(segments...)+".golden.html")
+}
+
+// Match renders a component and compares it with the golden file of the
+// current test
+func (h *Harness) Match(t TB, component templ.Component) {
+	t.Helper()
+	actual, err := h.Snapshot(context.Background(), component)
+	if err != nil {
+		t.Fatalf("snapshot %s: %v", t.Name(), err)
+		return
+	}
+	h.MatchString(t, actual)
+}
+
+// MatchString compares content with the golden file of the current test
+func (h *Harness) MatchString(t TB, actual string) {
+	t.Helper()
+	path := h.Path(t.Name())
+
+	expected, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		if h.ci && !h.update {
+			t.Errorf("missing snapshot %s (run with -update-snapshots or %s=1 to record it)", path, UpdateEnv)
+			return
+		}
+		if err := write(path, actual); err != nil {
+			t.Fatalf("record snapshot: %v", err)
+			return
+		}
+		t.Logf("recorded snapshot %s", path)
+		return
+	case err != nil:
+		t.Fatalf("read snapshot: %v", err)
+		return
+	}
+
+	if string(expected) == actual {
+		return
+	}
+	if h.update {
+		if err := write(path, actual); err != nil {
+			t.Fatalf("update snapshot: %v", err)
+			return
+		}
+		t.Logf("updated snapshot %s", path)
+		return
+	}
+	t.Errorf("snapshot mismatch %s (run with -update-snapshots or %s=1 to accept)\n%s",
+		path, UpdateEnv, Diff(string(expected), actual))
+}
+
+func write(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(content), 0o644)
+}
+
+// sanitize makes a test name safe to use as a file name
+func sanitize(name string) string {
+	var sb strings.Builder
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9',
+			r == '-', r == '_', r == '.', r == '=', r == ',':
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune('_')
+		}
+	}
+	if sb.Len() == 0 {
+		return "_"
+	}
+	return sb.String()
+}
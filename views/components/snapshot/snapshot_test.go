@@ -0,0 +1,175 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder is a TB that records failures instead of failing the test
+type recorder struct {
+	name   string
+	errors []string
+	logs   []string
+}
+
+func (r *recorder) Helper()      {}
+func (r *recorder) Name() string { return r.name }
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+func (r *recorder) Fatalf(format string, args ...any) { r.Errorf(format, args...) }
+func (r *recorder) Logf(format string, args ...any) {
+	r.logs = append(r.logs, fmt.Sprintf(format, args...))
+}
+
+type badgeProps struct {
+	Text    string
+	Variant string
+	Size    string
+}
+
+func badge(props badgeProps) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		dir := "ltr"
+		if env, ok := EnvironmentFromContext(ctx); ok && env.RTL {
+			dir = "rtl"
+		}
+		_, err := fmt.Fprintf(w, `<span   class="badge badge-%s  badge-%s" data-dir="%s" id="b-7f1c2a9e-1111-4222-8333-944455556666">
+			%s
+		</span>`, props.Variant, props.Size, dir, props.Text)
+		return err
+	})
+}
+
+func TestNormalizer(t *testing.T) {
+	normalized, err := NewNormalizer().DropAttr("data-x").Normalize(`
+		<div   data-x="1" id="a" class="b a  b"><!-- comment -->
+			<p>  Hello
+			   <b>world</b> </p><input disabled value=""><pre>  keep
+  this</pre>
+			<button onclick="__templ_save_1a2b3c4d()">Save</button>
+		</div>`)
+	require.NoError(t, err)
+	require.Equal(t, `<div class="a b" id="a">
+  <p>
+    Hello
+    <b>world</b>
+  </p>
+  <input disabled value="">
+  <pre>  keep
+  this</pre>
+  <button onclick="__templ_save_{hash}()">Save</button>
+</div>
+`, normalized)
+}
+
+func TestMatrix_Cases(t *testing.T) {
+	matrix := Matrix[badgeProps]{
+		Base: badgeProps{Text: "New", Variant: "default", Size: "md"},
+		Axes: []Axis[badgeProps]{
+			Vary("variant",
+				Set("primary", func(p *badgeProps) { p.Variant = "primary" }),
+				Set("danger", func(p *badgeProps) { p.Variant = "danger" })),
+			Vary("size",
+				Set("sm", func(p *badgeProps) { p.Size = "sm" }),
+				Set("lg", func(p *badgeProps) { p.Size = "lg" })),
+		},
+	}
+	cases := matrix.Cases()
+	require.Len(t, cases, 4)
+	require.Equal(t, "variant=primary,size=sm", cases[0].Name)
+	require.Equal(t, badgeProps{Text: "New", Variant: "danger", Size: "lg"}, cases[3].Props)
+	require.Equal(t, "default", Matrix[badgeProps]{}.Cases()[0].Name)
+}
+
+func TestDiff(t *testing.T) {
+	expected := "a\nb\nc\nd\ne\nf\ng\nh\ni\n"
+	actual := "a\nb\nc\nd\nE\nf\ng\nh\ni\n"
+	require.Equal(t, `--- golden
++++ actual
+@@ -2,7 +2,7 @@
+ b
+ c
+ d
+-e
++E
+ f
+ g
+ h
+`, Diff(expected, actual))
+}
+
+func TestHarness_Match(t *testing.T) {
+	t.Setenv("CI", "")
+	dir := t.TempDir()
+	component := badge(badgeProps{Text: "New", Variant: "primary", Size: "sm"})
+
+	// First run records the golden file
+	r := &recorder{name: "TestBadge/variant=primary"}
+	New(WithDir(dir), WithUpdate(false)).Match(r, component)
+	require.Empty(t, r.errors)
+	path := filepath.Join(dir, "TestBadge", "variant=primary.golden.html")
+	golden, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, `<div data-snapshot-env="light">
+  <span class="badge badge-primary badge-sm" data-dir="ltr" id="b-{uuid}">New</span>
+</div>
+
+<div class="dark" data-snapshot-env="dark">
+  <span class="badge badge-primary badge-sm" data-dir="ltr" id="b-{uuid}">New</span>
+</div>
+
+<div data-snapshot-env="rtl" dir="rtl" lang="ar">
+  <span class="badge badge-primary badge-sm" data-dir="rtl" id="b-{uuid}">New</span>
+</div>
+`, string(golden))
+
+	// Matching output passes
+	r = &recorder{name: r.name}
+	New(WithDir(dir)).Match(r, component)
+	require.Empty(t, r.errors)
+
+	// Changed output fails with a diff
+	changed := badge(badgeProps{Text: "Old", Variant: "primary", Size: "sm"})
+	r = &recorder{name: r.name}
+	New(WithDir(dir), WithUpdate(false)).Match(r, changed)
+	require.Len(t, r.errors, 1)
+	require.Contains(t, r.errors[0], `-  <span class="badge badge-primary badge-sm" data-dir="ltr" id="b-{uuid}">New</span>`)
+	require.Contains(t, r.errors[0], `+  <span class="badge badge-primary badge-sm" data-dir="ltr" id="b-{uuid}">Old</span>`)
+
+	// Update mode rewrites the golden file
+	r = &recorder{name: r.name}
+	New(WithDir(dir), WithUpdate(true)).Match(r, changed)
+	require.Empty(t, r.errors)
+	golden, err = os.ReadFile(path)
+	require.NoError(t, err)
+	require.True(t, strings.Contains(string(golden), ">Old</span>"))
+}
+
+func TestHarness_MissingInCI(t *testing.T) {
+	t.Setenv("CI", "true")
+	r := &recorder{name: "TestMissing"}
+	New(WithDir(t.TempDir()), WithUpdate(false)).Match(r, badge(badgeProps{Text: "x"}))
+	require.Len(t, r.errors, 1)
+	require.Contains(t, r.errors[0], "missing snapshot")
+}
+
+func TestRun(t *testing.T) {
+	h := New(WithEnvironments(Light, RTL))
+	Run(t, h, Matrix[badgeProps]{
+		Base: badgeProps{Text: "Beta", Size: "md"},
+		Axes: []Axis[badgeProps]{
+			Vary("variant",
+				Set("default", func(p *badgeProps) { p.Variant = "default" }),
+				Set("success", func(p *badgeProps) { p.Variant = "success" })),
+		},
+	}, badge)
+}
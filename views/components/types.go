@@ -112,6 +112,7 @@ type AccessibilityProps struct {
 	AriaDescribedBy string // aria-describedby
 	AriaLabelledBy  string // aria-labelledby
 	AriaExpanded    string // aria-expanded
+	AriaHasPopup    string // aria-haspopup
 	AriaPressed     string // aria-pressed
 	AriaSelected    string // aria-selected
 	AriaChecked     string // aria-checked
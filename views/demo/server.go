@@ -4,12 +4,16 @@ import (
 	"context"
 	"log"
 	"os"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/compress"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
+
+	"github.com/niiniyare/ruun/pkg/config"
+	"github.com/niiniyare/ruun/pkg/ratelimit"
 )
 
 func main() {
@@ -65,13 +69,39 @@ func setupRoutes(app *fiber.App) {
 	// Health check endpoint
 	app.Get("/health", handleHealthCheck)
 
-	// API routes group (for future HTMX endpoints)
-	api := app.Group("/api")
+	// API routes group (for future HTMX endpoints), rate limited per client
+	api := app.Group("/api", newRateLimiter(config.UIRateLimitConfig{
+		Enabled:    true,
+		Requests:   120,
+		Window:     time.Minute,
+		BurstLimit: 30,
+	}))
 	api.Get("/ping", func(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{"message": "pong"})
 	})
 }
 
+// newRateLimiter limits requests with the UI rate limit settings, counted in
+// process since the demo runs as a single instance
+func newRateLimiter(cfg config.UIRateLimitConfig) fiber.Handler {
+	plan := &ratelimit.Plan{Name: "demo"}
+	if limit, ok := ratelimit.LimitFromConfig(cfg); ok {
+		plan.Limits = map[string]ratelimit.Limit{ratelimit.AnyAction: limit}
+	}
+	plans, err := ratelimit.NewStaticPlans(plan.Name, plan)
+	if err != nil {
+		log.Fatal(err)
+	}
+	limiter, err := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), plans)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return ratelimit.NewMiddleware(ratelimit.MiddlewareConfig{
+		Limiter:   limiter,
+		SkipPaths: cfg.SkipPaths,
+	})
+}
+
 // handleDemoPage renders the complete component gallery
 func handleDemoPage(c *fiber.Ctx) error {
 	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)